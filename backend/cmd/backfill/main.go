@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/awaymess/super-dashboard/backend/internal/config"
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/pkg/api/stocks"
+	"github.com/awaymess/super-dashboard/backend/pkg/database"
+)
+
+const dateLayout = "2006-01-02"
+
+// Default pause between requests per provider. The Alpha Vantage free tier
+// allows 5 calls per minute.
+var defaultPace = map[string]time.Duration{
+	"alphavantage": 12 * time.Second,
+	"yahoo":        time.Second,
+}
+
+func main() {
+	// Parse flags
+	symbolsFlag := flag.String("symbols", "", "Comma-separated symbols to backfill (required)")
+	fromFlag := flag.String("from", "", "First day to backfill, YYYY-MM-DD (default: five years before -to)")
+	toFlag := flag.String("to", "", "Last day to backfill, YYYY-MM-DD (default: today)")
+	providerFlag := flag.String("provider", "", "History provider: alphavantage or yahoo (default: alphavantage when ALPHA_VANTAGE_API_KEY is set, otherwise yahoo)")
+	paceFlag := flag.Duration("pace", 0, "Pause between provider requests (default: 12s for alphavantage, 1s for yahoo)")
+	retries := flag.Int("retries", 3, "Retries per symbol after the provider rate limits a request")
+	verbose := flag.Bool("v", false, "Enable verbose output")
+	flag.Parse()
+
+	// Initialize logger
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	if !*verbose {
+		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	}
+
+	symbols := parseSymbols(*symbolsFlag)
+	if len(symbols) == 0 {
+		log.Fatal().Msg("-symbols is required")
+	}
+	from, to, err := parseRange(*fromFlag, *toFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid date range")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal().Msg("DATABASE_URL environment variable is required")
+	}
+
+	provider, err := newHistoryProvider(*providerFlag, cfg.AlphaVantageAPIKey)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid provider")
+	}
+	pace := *paceFlag
+	if pace <= 0 {
+		pace = defaultPace[provider.Name()]
+	}
+
+	// Connect to database
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get underlying database connection")
+	}
+	defer func() {
+		if err := sqlDB.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close database connection")
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Info().
+		Str("provider", provider.Name()).
+		Int("symbols", len(symbols)).
+		Str("from", from.Format(dateLayout)).
+		Str("to", to.Format(dateLayout)).
+		Dur("pace", pace).
+		Msg("Starting historical price backfill")
+
+	b := &backfiller{
+		provider: provider,
+		repo:     repository.NewPriceHistoryRepository(db),
+		pace:     pace,
+		retries:  *retries,
+	}
+	failed := b.run(ctx, symbols, from, to)
+	if ctx.Err() != nil {
+		log.Fatal().Msg("Backfill interrupted")
+	}
+	if failed > 0 {
+		log.Fatal().Int("failed", failed).Msg("Backfill finished with failures")
+	}
+
+	fmt.Println("✓ Historical price backfill completed successfully")
+}
+
+// backfiller loads daily history for symbols into the stock_prices table.
+type backfiller struct {
+	provider stocks.HistoryProvider
+	repo     repository.PriceHistoryRepository
+	pace     time.Duration
+	retries  int
+}
+
+// run backfills each symbol in turn and returns the number that failed.
+func (b *backfiller) run(ctx context.Context, symbols []string, from, to time.Time) int {
+	failed := 0
+	for i, symbol := range symbols {
+		if i > 0 && !sleep(ctx, b.pace) {
+			return failed
+		}
+
+		fetched, inserted, err := b.backfillSymbol(ctx, symbol, from, to)
+		if err != nil {
+			if ctx.Err() != nil {
+				return failed
+			}
+			failed++
+			log.Error().Err(err).Str("symbol", symbol).
+				Str("progress", fmt.Sprintf("%d/%d", i+1, len(symbols))).
+				Msg("Failed to backfill symbol")
+			continue
+		}
+
+		log.Info().Str("symbol", symbol).
+			Str("progress", fmt.Sprintf("%d/%d", i+1, len(symbols))).
+			Int("fetched", fetched).
+			Int64("inserted", inserted).
+			Int64("already_stored", int64(fetched)-inserted).
+			Msg("Backfilled symbol")
+	}
+	return failed
+}
+
+// backfillSymbol fetches and stores one symbol's history, waiting out rate
+// limits up to the configured number of retries.
+func (b *backfiller) backfillSymbol(ctx context.Context, symbol string, from, to time.Time) (int, int64, error) {
+	var (
+		points []stocks.PricePoint
+		err    error
+	)
+	for attempt := 0; ; attempt++ {
+		points, err = b.provider.GetDailyHistory(ctx, symbol, from, to)
+		if err == nil || !errors.Is(err, stocks.ErrRateLimited) || attempt >= b.retries {
+			break
+		}
+		log.Warn().Str("symbol", symbol).Dur("wait", stocks.DefaultRateLimitCooldown).
+			Int("attempt", attempt+1).
+			Msg("Provider rate limited, waiting before retrying")
+		if !sleep(ctx, stocks.DefaultRateLimitCooldown) {
+			return 0, 0, ctx.Err()
+		}
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	stock, err := b.repo.EnsureStock(symbol)
+	if err != nil {
+		return 0, 0, fmt.Errorf("ensure stock: %w", err)
+	}
+
+	prices := make([]model.StockPrice, len(points))
+	for i, point := range points {
+		prices[i] = model.StockPrice{
+			Timestamp: point.Date,
+			Open:      point.Open,
+			High:      point.High,
+			Low:       point.Low,
+			Close:     point.Close,
+			Volume:    point.Volume,
+		}
+	}
+
+	inserted, err := b.repo.SavePrices(stock.ID, prices)
+	if err != nil {
+		return 0, 0, fmt.Errorf("save prices: %w", err)
+	}
+	return len(points), inserted, nil
+}
+
+// newHistoryProvider returns the named provider, choosing one from the
+// available credentials when name is empty.
+func newHistoryProvider(name, alphaVantageKey string) (stocks.HistoryProvider, error) {
+	if name == "" {
+		name = "yahoo"
+		if alphaVantageKey != "" {
+			name = "alphavantage"
+		}
+	}
+
+	switch name {
+	case "alphavantage":
+		if alphaVantageKey == "" {
+			return nil, errors.New("alphavantage requires ALPHA_VANTAGE_API_KEY")
+		}
+		return stocks.NewAlphaVantageClient(alphaVantageKey), nil
+	case "yahoo":
+		return stocks.NewYahooHistoryProvider(stocks.NewYahooFinanceClient()), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q, expected alphavantage or yahoo", name)
+	}
+}
+
+// parseSymbols splits and normalizes a comma-separated symbol list, dropping
+// blanks and duplicates.
+func parseSymbols(list string) []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, symbol := range strings.Split(list, ",") {
+		symbol = repository.NormalizeSymbol(symbol)
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// parseRange parses the -from and -to flags, applying their defaults.
+func parseRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if toStr != "" {
+		parsed, err := time.Parse(dateLayout, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("-to: %w", err)
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(-5, 0, 0)
+	if fromStr != "" {
+		parsed, err := time.Parse(dateLayout, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("-from: %w", err)
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("-from must not be after -to")
+	}
+	return from, to, nil
+}
+
+// sleep waits for d or until ctx is done, reporting whether it waited the
+// full duration.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -12,27 +12,39 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	goredis "github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 
 	"github.com/awaymess/super-dashboard/backend/internal/config"
 	"github.com/awaymess/super-dashboard/backend/internal/handler"
 	"github.com/awaymess/super-dashboard/backend/internal/middleware"
+	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/awaymess/super-dashboard/backend/internal/repository"
 	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/pkg/api"
+	"github.com/awaymess/super-dashboard/backend/pkg/api/notification"
+	"github.com/awaymess/super-dashboard/backend/pkg/api/oauth"
+	"github.com/awaymess/super-dashboard/backend/pkg/api/stocks"
 	"github.com/awaymess/super-dashboard/backend/pkg/database"
+	"github.com/awaymess/super-dashboard/backend/pkg/jobs"
 	"github.com/awaymess/super-dashboard/backend/pkg/logger"
 	"github.com/awaymess/super-dashboard/backend/pkg/nlp"
 	"github.com/awaymess/super-dashboard/backend/pkg/redis"
+	"github.com/awaymess/super-dashboard/backend/pkg/websocket"
 	"github.com/awaymess/super-dashboard/backend/workers"
 )
 
@@ -73,10 +85,42 @@ func main() {
 	healthHandler := handler.NewHealthHandler()
 	healthHandler.RegisterHealthRoutes(r)
 
+	// One Alpha Vantage client is shared so its response cache covers every caller
+	alphaVantage := newAlphaVantageClient(cfg)
+
+	// Report external data providers that are configured
+	if cfg.AlphaVantageAPIKey != "" {
+		healthHandler.AddProviderChecker(handler.NewAlphaVantageChecker())
+	}
+	if cfg.OddsAPIKey != "" {
+		healthHandler.AddProviderChecker(handler.NewOddsAPIChecker(cfg.OddsAPIKey))
+	}
+
 	// Initialize metrics handler
 	metricsHandler := handler.NewMetricsHandler()
 	metricsHandler.RegisterMetricsRoutes(r)
 	r.Use(metricsHandler.MetricsMiddleware())
+	metricsHandler.AddGauge("superdash_provider_circuit_state", "External provider circuit breaker state (0=closed, 1=open, 2=half-open)", "base_url", func() map[string]uint64 {
+		states := make(map[string]uint64)
+		for _, status := range api.BreakerStates() {
+			states[status.BaseURL] = uint64(status.State)
+		}
+		return states
+	})
+	metricsHandler.AddCounter("superdash_alphavantage_cache_hits_total", "Total number of Alpha Vantage responses served from cache", func() uint64 {
+		return stocks.AlphaVantageCacheMetrics().Hits
+	})
+	metricsHandler.AddCounter("superdash_alphavantage_cache_misses_total", "Total number of Alpha Vantage requests not found in cache", func() uint64 {
+		return stocks.AlphaVantageCacheMetrics().Misses
+	})
+	metricsHandler.AddCounterVec("superdash_paper_orders_total", "Total number of paper orders created, by side and status", service.PaperOrdersTotal)
+	metricsHandler.AddCounterVec("superdash_bets_placed_total", "Total number of bets placed", service.BetsPlacedTotal)
+	metricsHandler.AddCounterVec("superdash_value_bets_detected_total", "Total number of value bets detected", service.ValueBetsDetectedTotal)
+	metricsHandler.AddCounterVec("superdash_notifications_sent_total", "Total number of notification deliveries, by channel and status", service.NotificationsSentTotal)
+
+	// Real-time events for WebSocket clients, published by the workers
+	wsHub := websocket.NewHub()
+	go wsHub.Run()
 
 	// API v1 routes
 	v1 := r.Group("/api/v1")
@@ -97,6 +141,17 @@ func main() {
 		})
 	}
 
+	// Database for workers that reconcile persisted data; nil in mock mode
+	var workerDB *gorm.DB
+	// Paper portfolio revaluation for the stock sync worker; nil in mock mode
+	var markToMarketService service.MarkToMarketService
+	// Locks shared with other server replicas so that only one runs each
+	// worker tick; nil without Redis
+	var workerLocker jobs.Locker
+	// Provider callbacks; the price push is enabled once the workers it
+	// feeds are built
+	webhookHandler := handler.NewWebhookHandler()
+
 	// Initialize services based on configuration
 	if cfg.UseMockData {
 		// Use mock repositories
@@ -107,6 +162,7 @@ func main() {
 
 		// Initialize match repository
 		matchRepo, err := repository.NewMockMatchRepository(filepath.Join(mockDir, "matches.json"))
+		err = warnSkippedMockRecords(err)
 		if err != nil {
 			log.Warn().Err(err).Msg("Failed to load mock match data")
 		} else {
@@ -117,6 +173,7 @@ func main() {
 
 		// Initialize stock repository
 		stockRepo, err := repository.NewMockStockRepository(filepath.Join(mockDir, "stocks.json"))
+		err = warnSkippedMockRecords(err)
 		if err != nil {
 			log.Warn().Err(err).Msg("Failed to load mock stock data")
 		} else {
@@ -130,17 +187,22 @@ func main() {
 		betHandler.RegisterBetRoutes(v1)
 		log.Info().Msg("Betting endpoints registered")
 
-		// Initialize paper trading handler (mock mode - legacy endpoints)
-		paperTradingHandler := handler.NewPaperTradingHandler()
-		paperTradingHandler.RegisterPaperTradingRoutes(v1)
-		log.Info().Msg("Paper trading endpoints registered")
-
 		// Initialize NLP handler (mock mode)
-		nlpProvider := nlp.NewMockProvider()
+		lexicon := nlp.DefaultLexicon()
+		if cfg.NLPLexiconPath != "" {
+			lexicon, err = nlp.LoadLexicon(cfg.NLPLexiconPath)
+			if err != nil {
+				log.Fatal().Err(err).Str("path", cfg.NLPLexiconPath).Msg("Failed to load NLP lexicon")
+			}
+			log.Info().Str("path", cfg.NLPLexiconPath).Int("words", len(lexicon)).Msg("Loaded NLP sentiment lexicon")
+		}
+		nlpProvider := nlp.NewMockProviderWithLexicon(lexicon)
 		articleRepo := repository.NewInMemoryArticleRepository()
 		nlpService := service.NewNLPService(nlpProvider, articleRepo)
 		nlpHandler := handler.NewNLPHandler(nlpService)
-		nlpHandler.RegisterNLPRoutes(v1)
+		// Mock mode has no user store, so admin checks trust the JWT's role claim
+		nlpAuthService := service.NewExtendedAuthService(service.AuthServiceConfig{JWTSecret: cfg.JWTSecret})
+		nlpHandler.RegisterNLPRoutes(v1, middleware.AuthMiddleware(nlpAuthService), middleware.RequireRole("admin"))
 		log.Info().Msg("NLP endpoints registered")
 		// Initialize paper trading with in-memory repositories (new /paper endpoints)
 		portfolioRepo := repository.NewInMemoryPortfolioRepository()
@@ -153,12 +215,27 @@ func main() {
 			log.Warn().Err(err).Msg("Failed to seed default portfolio")
 		}
 
+		// Backtests run over the mock price history
+		var backtestHistory service.PriceHistorySource
+		if stockRepo != nil {
+			backtestHistory = stockRepo
+		}
+		backtestService := service.NewBacktestService(backtestHistory, repository.NewInMemoryBacktestResultRepository())
+
 		// Initialize paper trading service with mock price provider
 		paperService := service.NewPaperTradingService(portfolioRepo, positionRepo, orderRepo, tradeRepo, nil)
+		mockQuotes := service.NewMockQuoteSource(service.NewDefaultMockPriceProvider())
+		paperService.SetMarkToMarket(service.NewMarkToMarketService(portfolioRepo, positionRepo, mockQuotes))
 		paperHandler := handler.NewPaperHandler(paperService)
+		paperHandler.SetBacktests(backtestService)
 		paperHandler.RegisterPaperRoutes(v1)
 		log.Info().Msg("Paper trading API endpoints registered (/api/v1/paper)")
 
+		// Legacy paper trading endpoints; the portfolio is served from the paper service
+		paperTradingHandler := handler.NewPaperTradingHandler(paperService, backtestService)
+		paperTradingHandler.RegisterPaperTradingRoutes(v1)
+		log.Info().Msg("Paper trading endpoints registered")
+
 		log.Info().Msg("Running with mock data mode")
 	} else if cfg.DatabaseURL != "" {
 		// Use database repositories
@@ -166,21 +243,14 @@ func main() {
 		if err != nil {
 			log.Fatal().Err(err).Msg("Failed to connect to database")
 		}
+		workerDB = db
 
-		// Add database health checker with timeout
-		healthHandler.AddHealthChecker(func() (string, bool, string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-			defer cancel()
-			
-			sqlDB, err := db.DB()
-			if err != nil {
-				return "database", false, err.Error()
-			}
-			if err := sqlDB.PingContext(ctx); err != nil {
-				return "database", false, err.Error()
-			}
-			return "database", true, "connected"
-		})
+		// Add database health checker with timeout, reporting pool stats
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to get database connection pool")
+		}
+		healthHandler.AddDatabaseChecker("database", sqlDB, 3*time.Second)
 
 		// Run migrations
 		if err := database.AutoMigrate(db); err != nil {
@@ -197,9 +267,15 @@ func main() {
 		positionRepo := repository.NewPositionRepository(db)
 		orderRepo := repository.NewOrderRepository(db)
 		tradeRepo := repository.NewTradeRepository(db)
+		screenerPresetRepo := repository.NewScreenerPresetRepository(db)
+		favoriteRepo := repository.NewFavoriteRepository(db)
+		fairValueRepo := repository.NewFairValueRepository(db)
+		stockMergeRepo := repository.NewStockMergeRepository(db)
+		bulkRepo := repository.NewBulkRepository(db)
 
 		// Initialize Redis for token storage and rate limiting
 		var tokenStore service.TokenStore
+		var idempotencyStore handler.IdempotencyStore
 		var redisClient *goredis.Client
 		if cfg.RedisURL != "" {
 			redisWrapper, err := redis.Connect(cfg.RedisURL)
@@ -207,10 +283,12 @@ func main() {
 				log.Warn().Err(err).Msg("Failed to connect to Redis, continuing without token persistence and distributed rate limiting")
 			} else {
 				tokenStore = redisWrapper
+				idempotencyStore = redisWrapper
 				// Parse Redis URL to get underlying client for rate limiting
 				opts, _ := goredis.ParseURL(cfg.RedisURL)
 				if opts != nil {
 					redisClient = goredis.NewClient(opts)
+					workerLocker = jobs.NewRedisLocker(redisClient)
 				}
 				// Add Redis health checker with timeout
 				healthHandler.AddHealthChecker(func() (string, bool, string) {
@@ -221,59 +299,297 @@ func main() {
 					}
 					return "redis", true, "connected"
 				})
+				if alphaVantage != nil {
+					alphaVantage.SetCache(redisWrapper)
+				}
 				log.Info().Msg("Connected to Redis for token storage and rate limiting")
 			}
 		}
 
 		// Initialize extended auth service with full functionality
 		authService := service.NewExtendedAuthService(service.AuthServiceConfig{
-			UserRepo:     userRepo,
-			SessionRepo:  sessionRepo,
-			OAuthRepo:    oauthRepo,
-			TwoFARepo:    twoFARepo,
-			AuditLogRepo: auditLogRepo,
-			TokenStore:   tokenStore,
-			JWTSecret:    cfg.JWTSecret,
-			IssuerName:   "SuperDashboard",
+			UserRepo:                 userRepo,
+			SessionRepo:              sessionRepo,
+			OAuthRepo:                oauthRepo,
+			TwoFARepo:                twoFARepo,
+			AuditLogRepo:             auditLogRepo,
+			UserDataRepo:             repository.NewUserDataRepository(db),
+			TokenStore:               tokenStore,
+			JWTSecret:                cfg.JWTSecret,
+			IssuerName:               "SuperDashboard",
+			DeletionMode:             service.AccountDeletionMode(cfg.AccountDeletionMode),
+			BcryptCost:               cfg.BcryptCost,
+			RequireEmailVerification: cfg.RequireEmailVerification,
+			OAuthProviders:           newOAuthProviders(cfg),
 		})
-		paperService := service.NewPaperTradingService(portfolioRepo, positionRepo, orderRepo, tradeRepo, nil)
+		// Live quotes fail over from Yahoo Finance to Alpha Vantage; paper orders fill at them
+		liveQuotes := quoteSource{quotes: stocks.NewDefaultQuoteService(alphaVantage)}
+		paperService := service.NewPaperTradingService(portfolioRepo, positionRepo, orderRepo, tradeRepo, service.NewQuotePriceProvider(liveQuotes))
+		priceHistoryRepo := repository.NewPriceHistoryRepository(db)
+		paperService.SetVolatilitySource(service.NewPriceHistoryVolatility(priceHistoryRepo, service.DefaultVolatilityLookback))
+		paperService.SetPriceHistory(priceHistoryRepo)
+		markToMarketService = service.NewMarkToMarketService(portfolioRepo, positionRepo, liveQuotes)
+		paperService.SetMarkToMarket(markToMarketService)
+		favoriteService := service.NewFavoriteService(favoriteRepo)
+		bulkService := service.NewBulkService(bulkRepo)
+		watchlistService := service.NewWatchlistService(repository.NewWatchlistRepository(db), liveQuotes)
+		notificationRepo := repository.NewNotificationRepository(db)
+		notificationDeliveryService := service.NewNotificationDeliveryService(notificationRepo)
+		notificationInboxService := service.NewNotificationInboxService(notificationRepo)
+		dashboardSummaryService := service.NewDashboardSummaryService(repository.NewDashboardSummaryRepository(db), service.DashboardSummaryCacheTTL)
+		stockMergeService := service.NewStockMergeService(stockMergeRepo)
+		// Dry runs never notify, so the engines are built without a notification service
+		engineDryRunService := service.NewEngineDryRunService(
+			workers.NewValueBetCalculatorWorker(time.Hour, log.Logger, db, nil),
+			workers.NewAlertCheckerWorker(cfg.AlertChecker.Interval, log.Logger, repository.NewAlertRepository(db), nil, db),
+		)
+		// Valuation and the screener need Alpha Vantage fundamentals; without a key they are unavailable
+		var fairValueCalculator service.FairValueCalculator
+		var screenerOverviews service.OverviewSource
+		if alphaVantage != nil {
+			overviews := overviewSource{client: alphaVantage}
+			fairValueCalculator = service.NewValuationService(
+				overviews,
+				quoteSource{quotes: stocks.NewDefaultQuoteService(alphaVantage)},
+			)
+			screenerOverviews = overviews
+		}
+		screenerService := service.NewScreenerService(fairValueRepo, screenerOverviews)
+		screenerPresetService := service.NewScreenerPresetService(screenerPresetRepo, service.NewScreenRunner(screenerService), repository.NewNotificationRepository(db))
+		// Stored history can always be read; syncing it needs Alpha Vantage
+		var historyBars service.DailyBarSource
+		if alphaVantage != nil {
+			historyBars = dailyBarSource{client: alphaVantage}
+		}
+		stockHistoryService := service.NewStockHistoryService(repository.NewStockPriceRepository(db), historyBars)
+		// Technical analysis and forecasts fall back to Yahoo Finance bars without an Alpha Vantage key
+		var technicalBars service.DailyBarSource = yahooDailyBarSource{history: stocks.NewYahooHistoryProvider(stocks.NewYahooFinanceClient())}
+		if historyBars != nil {
+			technicalBars = historyBars
+		}
+		kellyService := service.NewKellyService(repository.NewSettingsRepository(db))
+		valueBetService := service.NewValueBetService(repository.NewValueBetRepository(db), service.DefaultEloProbabilityModel, repository.NewSettingsRepository(db))
+		bettingService := service.NewBettingService(repository.NewBetRepository(db), repository.NewSettingsRepository(db))
+		bankrollAnalyticsService := service.NewBankrollAnalyticsService(repository.NewBankrollRepository(db), repository.NewBetRepository(db))
+		fairValueService := service.NewFairValueService(fairValueRepo, fairValueCalculator, repository.NewAlertRepository(db), repository.NewNotificationRepository(db), repository.NewSettingsRepository(db))
+		exportService := newDataExportService(db, userRepo, auditLogRepo, portfolioRepo, positionRepo, orderRepo, tradeRepo, favoriteRepo, screenerPresetRepo)
 
 		// Create auth middleware
 		authMiddleware := middleware.AuthMiddleware(authService)
 
 		// Initialize handlers
 		authHandler := handler.NewExtendedAuthHandler(authService)
+		authHandler.SetOAuthTokenLogin(cfg.OAuthTokenLoginEnabled)
+		authHandler.SetEmailProvider(newEmailProvider(cfg))
 		paperHandler := handler.NewPaperHandler(paperService)
-
-		// Apply rate limiting to auth routes
+		paperHandler.SetAuditRecorder(handler.NewAuditRecorder(auditLogRepo))
+		if idempotencyStore != nil {
+			paperHandler.SetIdempotencyStore(idempotencyStore)
+		}
+		screenerPresetHandler := handler.NewScreenerPresetHandler(screenerPresetService)
+		screenerHandler := handler.NewScreenerHandler(screenerService)
+		favoriteHandler := handler.NewFavoriteHandler(favoriteService)
+		alertHandler := handler.NewAlertHandler(service.NewAlertService(repository.NewAlertRepository(db)))
+		alertHandler.SetAuditRecorder(handler.NewAuditRecorder(auditLogRepo))
+		bulkHandler := handler.NewBulkHandler(bulkService)
+		watchlistHandler := handler.NewWatchlistHandler(watchlistService)
+		stockHistoryHandler := handler.NewStockHistoryHandler(stockHistoryService)
+		technicalAnalysisHandler := handler.NewTechnicalAnalysisHandler(service.NewTechnicalAnalysisService(technicalBars))
+		stockForecastHandler := handler.NewStockForecastHandler(service.NewStockForecastService(technicalBars))
+		stockQuotesHandler := handler.NewStockQuotesHandler(service.NewStockQuotesService(batchQuoteSource{
+			quotes: stocks.NewDefaultBatchQuoteService(alphaVantage),
+		}))
+		notificationHandler := handler.NewNotificationHandler(notificationDeliveryService, notificationInboxService)
+		dashboardHandler := handler.NewDashboardHandler(dashboardSummaryService)
+		valuationHandler := handler.NewValuationHandler(fairValueService)
+		kellyHandler := handler.NewKellyHandler(kellyService)
+		valueBetHandler := handler.NewValueBetHandler(valueBetService)
+		bettingHandler := handler.NewBettingHandler(bettingService)
+		bankrollAnalyticsHandler := handler.NewBankrollAnalyticsHandler(bankrollAnalyticsService)
+		stockAdminHandler := handler.NewStockAdminHandler(stockMergeService)
+		engineAdminHandler := handler.NewEngineAdminHandler(engineDryRunService)
+		exportHandler := handler.NewExportHandler(exportService)
+		exportHandler.SetAuditRecorder(handler.NewAuditRecorder(auditLogRepo))
+		auditLogHandler := handler.NewAuditLogHandler(auditLogRepo)
+
+		// Register auth routes, rate limiting login and password reset
 		authRateLimiter := middleware.AuthRateLimitMiddleware(redisClient)
-		apiRateLimiter := middleware.APIRateLimitMiddleware(redisClient)
-
-		// Register auth routes with rate limiting
-		authGroup := v1.Group("/auth")
-		authGroup.Use(authRateLimiter)
-		authHandler.RegisterExtendedAuthRoutes(v1, authMiddleware)
-		
-		// Register paper routes with API rate limiting
-		paperGroup := v1.Group("/paper")
-		paperGroup.Use(apiRateLimiter)
+		authHandler.RegisterExtendedAuthRoutes(v1, authMiddleware, authRateLimiter)
+
+		// Register paper routes
 		paperHandler.RegisterPaperRoutes(v1)
 
+		// Register batch live quotes
+		stockQuotesHandler.RegisterStockQuotesRoutes(v1)
+
+		// Register stored price history (syncing requires auth)
+		stockHistoryHandler.RegisterStockHistoryRoutes(v1, authMiddleware)
+
+		// Register locally computed technical indicators
+		technicalAnalysisHandler.RegisterTechnicalAnalysisRoutes(v1)
+
+		// Register Monte Carlo price forecasts
+		stockForecastHandler.RegisterStockForecastRoutes(v1)
+
+		// Register real-time events; connections authenticate with a JWT
+		wsHandler := websocket.NewWebSocketHandler(wsHub)
+		wsHandler.SetAuthenticator(func(token string) (string, error) {
+			claims, err := authService.ValidateToken(token)
+			if err != nil {
+				return "", err
+			}
+			userID, _ := (*claims)["user_id"].(string)
+			if userID == "" {
+				return "", service.ErrInvalidToken
+			}
+			return userID, nil
+		})
+		wsHandler.RegisterRoutes(v1)
+
+		// Register the Server-Sent Events fallback for price updates (requires auth)
+		handler.NewPriceStreamHandler(wsHub).RegisterPriceStreamRoutes(v1, authMiddleware)
+
+		// Register the fundamental stock screener
+		screenerHandler.RegisterScreenerRoutes(v1)
+
+		// Register saved screener presets (requires auth)
+		screenerPresetHandler.RegisterScreenerPresetRoutes(v1, authMiddleware)
+
+		// Register favorites (requires auth)
+		favoriteHandler.RegisterFavoriteRoutes(v1, authMiddleware)
+
+		// Register alerts (requires auth)
+		alertHandler.RegisterAlertRoutes(v1, authMiddleware)
+
+		// Register watchlists (requires auth)
+		watchlistHandler.RegisterWatchlistRoutes(v1, authMiddleware)
+
+		// Register bulk watchlist and alert operations (requires auth)
+		bulkHandler.RegisterBulkRoutes(v1, authMiddleware)
+
+		// Register notification inbox and delivery status (requires auth)
+		notificationHandler.RegisterNotificationRoutes(v1, authMiddleware)
+
+		// Register dashboard summary (requires auth)
+		dashboardHandler.RegisterDashboardRoutes(v1, authMiddleware)
+
+		// Register personal data export (requires auth)
+		exportHandler.RegisterExportRoutes(v1, authMiddleware)
+
+		// Register Kelly stake sizing (requires auth)
+		kellyHandler.RegisterKellyRoutes(v1, authMiddleware)
+
+		// Register bet placement and settlement (requires auth)
+		bettingHandler.RegisterBettingRoutes(v1, authMiddleware)
+
+		// Register bankroll history and betting performance (requires auth)
+		bankrollAnalyticsHandler.RegisterBankrollAnalyticsRoutes(v1, authMiddleware)
+
+		// Register value bet detection (per-user threshold when authenticated)
+		valueBetHandler.RegisterValueBetRoutes(v1, middleware.OptionalAuthMiddleware(authService))
+
+		// Register fair value history (per-user recommendations when authenticated)
+		valuationHandler.RegisterValuationRoutes(v1, middleware.OptionalAuthMiddleware(authService))
+
+		// Register stock maintenance (requires admin)
+		stockAdminHandler.RegisterStockAdminRoutes(v1, authMiddleware, middleware.AdminMiddleware())
+
+		// Register engine dry runs (requires admin)
+		engineAdminHandler.RegisterEngineAdminRoutes(v1, authMiddleware, middleware.AdminMiddleware())
+
+		// Register audit log browsing (requires admin)
+		auditLogHandler.RegisterAuditLogRoutes(v1, authMiddleware, middleware.RequireRole("admin"))
+
+		// Register provider callbacks (requires a WEBHOOK_SECRET signature)
+		webhookHandler.RegisterWebhookRoutes(v1, middleware.WebhookSignatureMiddleware(middleware.WebhookSignatureConfig{Secret: cfg.WebhookSecret}))
+
 		log.Info().Msg("Database-backed services initialized with extended auth")
 	} else {
 		log.Warn().Msg("No database URL configured and not in mock mode")
 	}
 
 	// Start background workers
-	// Create a cancellable context for workers
+	// Create a cancellable context for workers; shutdown waits on workerWG
+	// for them to finish in-flight work
 	workerCtx, workerCancel := context.WithCancel(context.Background())
 	defer workerCancel()
+	var workerWG sync.WaitGroup
 
-	// Start workers as goroutines
-	go workers.StartOddsSync(workerCtx, log.Logger)
-	go workers.StartStockSync(workerCtx, log.Logger)
-	go workers.StartAlertChecker(workerCtx, log.Logger)
-	log.Info().Msg("Background workers started")
+	// Start enabled workers as goroutines
+	if cfg.OddsSync.Enabled {
+		workerWG.Add(1)
+		go workers.StartOddsSync(workerCtx, &workerWG, cfg.OddsSync.Interval, log.Logger, workerLocker)
+		log.Info().Dur("interval", cfg.OddsSync.Interval).Msg("Odds sync worker started")
+	} else {
+		log.Info().Msg("Odds sync worker disabled")
+	}
+	workerQuotes := quoteSource{quotes: stocks.NewDefaultQuoteService(alphaVantage)}
+	hubPublisher := workers.NewHubPublisher(wsHub, log.Logger)
+	// The alert checker is built first so stock syncs can wake it
+	var alertChecker *workers.AlertCheckerWorker
+	if cfg.AlertChecker.Enabled && workerDB != nil {
+		alertChecker = workers.NewAlertCheckerWorker(
+			cfg.AlertChecker.Interval,
+			log.Logger,
+			repository.NewAlertRepository(workerDB),
+			newAlertNotifier(cfg, workerDB),
+			workerDB,
+		)
+		alertChecker.SetQuoteSource(workerQuotes)
+		alertChecker.SetEventPublisher(hubPublisher)
+		alertChecker.SetLocker(workerLocker)
+	}
+	// Synced and pushed prices go to live subscribers and wake the alert checker
+	pricePublisher := workers.PriceUpdatePublishers{hubPublisher}
+	if alertChecker != nil {
+		pricePublisher = append(pricePublisher, alertChecker)
+	}
+	if workerDB != nil {
+		webhookHandler.SetPriceReceiver(priceTickPublisher{publisher: pricePublisher})
+	}
+	if cfg.StockSync.Enabled && workerDB != nil {
+		stockSync := workers.NewStockSyncWorker(
+			cfg.StockSync.Interval,
+			log.Logger,
+			repository.NewTrackedSymbolRepository(workerDB),
+			workerQuotes,
+			repository.NewStockPriceRepository(workerDB),
+		)
+		stockSync.SetMarkToMarket(markToMarketService)
+		stockSync.SetPublisher(pricePublisher)
+		stockSync.SetLocker(workerLocker)
+		workerWG.Add(1)
+		go func() {
+			defer workerWG.Done()
+			stockSync.Run(workerCtx)
+		}()
+		log.Info().Dur("interval", cfg.StockSync.Interval).Msg("Stock sync worker started")
+	} else {
+		log.Info().Msg("Stock sync worker disabled")
+	}
+	if alertChecker != nil {
+		workerWG.Add(1)
+		go func() {
+			defer workerWG.Done()
+			alertChecker.Run(workerCtx)
+		}()
+		metricsHandler.AddCounter("superdash_alert_checker_alerts_evaluated_total", "Total number of alerts evaluated by the alert checker", func() uint64 {
+			return workers.AlertCheckerMetrics().AlertsEvaluated
+		})
+		metricsHandler.AddCounter("superdash_alert_checker_quotes_fetched_total", "Total number of quotes fetched by the alert checker", func() uint64 {
+			return workers.AlertCheckerMetrics().QuotesFetched
+		})
+		log.Info().Dur("interval", cfg.AlertChecker.Interval).Msg("Alert checker worker started")
+	} else {
+		log.Info().Msg("Alert checker worker disabled")
+	}
+	if cfg.BankrollReconciliationEnabled && workerDB != nil {
+		workerWG.Add(1)
+		go workers.StartBankrollReconciliation(workerCtx, &workerWG, log.Logger, workerDB, workerLocker)
+		log.Info().Msg("Bankroll reconciliation worker started")
+	} else {
+		log.Info().Msg("Bankroll reconciliation worker disabled")
+	}
 
 	// Start server with graceful shutdown
 	addr := ":" + cfg.Port
@@ -304,7 +620,27 @@ func main() {
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal().Err(err).Msg("Server forced to shutdown")
+		log.Error().Err(err).Msg("Server forced to shutdown")
+	}
+
+	// Let the workers finish in-flight writes before closing the database
+	workersDone := make(chan struct{})
+	go func() {
+		workerWG.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+		log.Info().Msg("Background workers stopped")
+	case <-ctx.Done():
+		log.Warn().Msg("Timed out waiting for background workers to stop")
+	}
+	if workerDB != nil {
+		if sqlDB, err := workerDB.DB(); err == nil {
+			if err := sqlDB.Close(); err != nil {
+				log.Error().Err(err).Msg("Failed to close database")
+			}
+		}
 	}
 
 	log.Info().Msg("Server exited gracefully")
@@ -325,3 +661,312 @@ func findMockDir() string {
 	}
 	return "mock"
 }
+
+// warnSkippedMockRecords logs each record a mock repository skipped and
+// returns nil for such partial loads, so the valid records are still served.
+// Other errors are returned unchanged.
+func warnSkippedMockRecords(err error) error {
+	var dataErr *repository.MockDataError
+	if !errors.As(err, &dataErr) {
+		return err
+	}
+	for _, record := range dataErr.Records {
+		log.Warn().
+			Err(record.Err).
+			Str("file", dataErr.File).
+			Str("section", record.Section).
+			Int("index", record.Index).
+			Str("id", record.ID).
+			Str("field", record.Field).
+			Msg("Skipped invalid mock record")
+	}
+	return nil
+}
+
+// quoteSource adapts the stock QuoteService to service.QuoteSource.
+type quoteSource struct {
+	quotes *stocks.QuoteService
+}
+
+// GetQuote fetches a live quote, failing over between providers.
+func (s quoteSource) GetQuote(ctx context.Context, symbol string) (*service.MarketQuote, error) {
+	quote, err := s.quotes.GetQuote(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &service.MarketQuote{
+		Price:         quote.Price,
+		Change:        quote.Change,
+		ChangePercent: quote.ChangePercent,
+	}, nil
+}
+
+// GetSymbolQuote fetches a live quote with the day's range and volume.
+func (s quoteSource) GetSymbolQuote(ctx context.Context, symbol string) (*service.SymbolQuote, error) {
+	quote, err := s.quotes.GetQuote(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &service.SymbolQuote{
+		Symbol:        quote.Symbol,
+		Price:         quote.Price,
+		Change:        quote.Change,
+		ChangePercent: quote.ChangePercent,
+		Open:          quote.Open,
+		High:          quote.High,
+		Low:           quote.Low,
+		PreviousClose: quote.PreviousClose,
+		Volume:        quote.Volume,
+		Timestamp:     quote.Timestamp,
+	}, nil
+}
+
+// batchQuoteSource adapts the stock BatchQuoteService to service.BatchQuoteSource.
+type batchQuoteSource struct {
+	quotes *stocks.BatchQuoteService
+}
+
+// GetQuotes fetches live quotes in one batch, falling back per symbol.
+func (s batchQuoteSource) GetQuotes(ctx context.Context, symbols []string) ([]service.SymbolQuote, map[string]error) {
+	quotes, failed := s.quotes.GetQuotes(ctx, symbols)
+	result := make([]service.SymbolQuote, len(quotes))
+	for i, quote := range quotes {
+		result[i] = service.SymbolQuote{
+			Symbol:        quote.Symbol,
+			Price:         quote.Price,
+			Change:        quote.Change,
+			ChangePercent: quote.ChangePercent,
+			Open:          quote.Open,
+			High:          quote.High,
+			Low:           quote.Low,
+			PreviousClose: quote.PreviousClose,
+			Volume:        quote.Volume,
+			Timestamp:     quote.Timestamp,
+		}
+	}
+	return result, failed
+}
+
+// dailyBarSource adapts the Alpha Vantage daily time series to service.DailyBarSource.
+type dailyBarSource struct {
+	client *stocks.AlphaVantageClient
+}
+
+// GetDailyBars fetches the last 100 daily bars, or the full history.
+func (s dailyBarSource) GetDailyBars(ctx context.Context, symbol string, full bool) ([]model.StockPrice, error) {
+	series, err := s.client.GetDailyTimeSeries(ctx, symbol, full)
+	if err != nil {
+		return nil, err
+	}
+	bars := make([]model.StockPrice, 0, len(series.TimeSeries))
+	for _, point := range series.TimeSeries {
+		// Bars with an unparseable date would all share the zero timestamp.
+		if point.Date.IsZero() {
+			continue
+		}
+		bars = append(bars, model.StockPrice{
+			Timestamp: point.Date,
+			Open:      point.Open,
+			High:      point.High,
+			Low:       point.Low,
+			Close:     point.Close,
+			Volume:    point.Volume,
+		})
+	}
+	return bars, nil
+}
+
+// yahooDailyBarSource adapts Yahoo Finance daily history to service.DailyBarSource.
+type yahooDailyBarSource struct {
+	history stocks.HistoryProvider
+}
+
+// GetDailyBars fetches the last 150 days of bars, about as many trading days
+// as Alpha Vantage's compact series, or the full history.
+func (s yahooDailyBarSource) GetDailyBars(ctx context.Context, symbol string, full bool) ([]model.StockPrice, error) {
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -150)
+	if full {
+		from = time.Unix(0, 0).UTC()
+	}
+	points, err := s.history.GetDailyHistory(ctx, symbol, from, to)
+	if err != nil {
+		return nil, err
+	}
+	bars := make([]model.StockPrice, len(points))
+	for i, point := range points {
+		bars[i] = model.StockPrice{
+			Timestamp: point.Date,
+			Open:      point.Open,
+			High:      point.High,
+			Low:       point.Low,
+			Close:     point.Close,
+			Volume:    point.Volume,
+		}
+	}
+	return bars, nil
+}
+
+// overviewSource adapts Alpha Vantage company overviews to service.FundamentalsSource
+// and service.OverviewSource.
+type overviewSource struct {
+	client *stocks.AlphaVantageClient
+}
+
+// GetFundamentals fetches a company overview. Quarterly year-over-year
+// earnings growth stands in for the expected annual growth rate.
+func (s overviewSource) GetFundamentals(ctx context.Context, symbol string) (*service.CompanyFundamentals, error) {
+	overview, err := s.client.GetCompanyOverview(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &service.CompanyFundamentals{
+		Sector:     overview.Sector,
+		EPS:        overview.EPS,
+		BookValue:  overview.BookValue,
+		GrowthRate: overview.QuarterlyEarningsGrowthYOY,
+	}, nil
+}
+
+// GetOverview fetches the company overview fields the screener filters on.
+func (s overviewSource) GetOverview(ctx context.Context, symbol string) (*service.StockOverview, error) {
+	overview, err := s.client.GetCompanyOverview(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &service.StockOverview{
+		Symbol:         symbol,
+		Name:           overview.Name,
+		Sector:         overview.Sector,
+		MarketCap:      overview.MarketCapitalization,
+		PERatio:        overview.PERatio,
+		DividendYield:  overview.DividendYield,
+		ReturnOnEquity: overview.ReturnOnEquityTTM,
+	}, nil
+}
+
+// priceTickPublisher publishes prices pushed to the price webhook like the
+// prices a stock sync stores.
+type priceTickPublisher struct {
+	publisher workers.PriceUpdatePublisher
+}
+
+// ReceivePriceTicks implements handler.PriceTickReceiver.
+func (p priceTickPublisher) ReceivePriceTicks(ticks []handler.PriceTick) {
+	updates := make([]workers.PriceUpdate, len(ticks))
+	for i, tick := range ticks {
+		updates[i] = workers.PriceUpdate{
+			Symbol: repository.NormalizeSymbol(tick.Symbol),
+			Price:  tick.Price,
+			Volume: tick.Volume,
+			At:     tick.Timestamp,
+		}
+	}
+	p.publisher.PublishPriceUpdates(updates)
+}
+
+// newDataExportService builds the per-user data export with a section for
+// each kind of data stored about a user.
+func newDataExportService(
+	db *gorm.DB,
+	userRepo repository.UserRepository,
+	auditLogRepo repository.AuditLogRepository,
+	portfolioRepo repository.PortfolioRepository,
+	positionRepo repository.PositionRepository,
+	orderRepo repository.OrderRepository,
+	tradeRepo repository.TradeRepository,
+	favoriteRepo repository.FavoriteRepository,
+	screenerPresetRepo repository.ScreenerPresetRepository,
+) service.DataExportService {
+	settingsRepo := repository.NewSettingsRepository(db)
+	betRepo := repository.NewBetRepository(db)
+	alertRepo := repository.NewAlertRepository(db)
+	watchlistRepo := repository.NewWatchlistRepository(db)
+	journalRepo := repository.NewTradeJournalRepository(db)
+
+	exportService := service.NewDataExportService(userRepo, repository.NewNotificationRepository(db))
+	exportService.AddSource("settings", func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		return settingsRepo.GetUserSettings(ctx, userID)
+	})
+	exportService.AddSource("portfolios", service.PortfolioExportSource(portfolioRepo, positionRepo, orderRepo, tradeRepo))
+	exportService.AddSource("bets", func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		return betRepo.GetUserBets(ctx, userID, repository.BetFilters{})
+	})
+	exportService.AddSource("alerts", func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		return alertRepo.GetUserAlerts(ctx, userID)
+	})
+	exportService.AddSource("watchlists", func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		return watchlistRepo.GetByUserID(userID)
+	})
+	exportService.AddSource("journal", func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		return journalRepo.GetUserEntries(ctx, userID, 0, 0)
+	})
+	exportService.AddSource("favorites", func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		return favoriteRepo.GetByUserID(userID, "")
+	})
+	exportService.AddSource("screener_presets", func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		return screenerPresetRepo.GetByUserID(userID)
+	})
+	exportService.AddSource("audit_logs", service.AuditLogExportSource(auditLogRepo))
+
+	return exportService
+}
+
+// newOAuthProviders builds the OAuth providers that have credentials
+// configured. Providers redirect back to the callback routes under
+// OAuthRedirectBaseURL.
+func newOAuthProviders(cfg *config.Config) map[model.OAuthProvider]service.OAuthProvider {
+	callbackURL := func(provider model.OAuthProvider) string {
+		return strings.TrimRight(cfg.OAuthRedirectBaseURL, "/") + "/api/v1/auth/oauth/" + string(provider) + "/callback"
+	}
+
+	providers := make(map[model.OAuthProvider]service.OAuthProvider)
+	if cfg.GoogleClientID != "" && cfg.GoogleClientSecret != "" {
+		providers[model.OAuthProviderGoogle] = oauth.NewGoogleProvider(cfg.GoogleClientID, cfg.GoogleClientSecret, callbackURL(model.OAuthProviderGoogle))
+	}
+	if cfg.GitHubClientID != "" && cfg.GitHubClientSecret != "" {
+		providers[model.OAuthProviderGitHub] = oauth.NewGitHubProvider(cfg.GitHubClientID, cfg.GitHubClientSecret, callbackURL(model.OAuthProviderGitHub))
+	}
+	return providers
+}
+
+// newAlphaVantageClient builds the Alpha Vantage client with the configured
+// cache lifetimes, or returns nil when no API key is set.
+func newAlphaVantageClient(cfg *config.Config) *stocks.AlphaVantageClient {
+	if cfg.AlphaVantageAPIKey == "" {
+		return nil
+	}
+	client := stocks.NewAlphaVantageClient(cfg.AlphaVantageAPIKey)
+	client.SetCacheTTLs(cfg.AlphaVantageQuoteCacheTTL, cfg.AlphaVantageOverviewCacheTTL)
+	return client
+}
+
+// newEmailProvider builds the SendGrid client, or returns nil when no API
+// key is set.
+func newEmailProvider(cfg *config.Config) notification.EmailProvider {
+	if cfg.SendGridAPIKey == "" {
+		return nil
+	}
+	return notification.NewSendGridClient(cfg.SendGridAPIKey, cfg.EmailFrom, cfg.EmailFromName)
+}
+
+// newAlertNotifier builds the notification service the alert checker sends
+// triggered alerts through, with every channel that has credentials.
+func newAlertNotifier(cfg *config.Config, db *gorm.DB) *service.NotificationService {
+	email := newEmailProvider(cfg)
+	var telegram *notification.TelegramClient
+	if cfg.TelegramBotToken != "" {
+		telegram = notification.NewTelegramClient(cfg.TelegramBotToken)
+	}
+	var line *notification.LINEClient
+	if cfg.LINEChannelToken != "" {
+		line = notification.NewLINEClient(cfg.LINEChannelToken)
+	}
+
+	notifier := service.NewNotificationService(repository.NewNotificationRepository(db), log.Logger)
+	notifier.SetChannels(
+		notification.NewNotificationManager(email, telegram, line, nil),
+		repository.NewSettingsRepository(db),
+	)
+	return notifier
+}
@@ -1,13 +1,24 @@
 package main
 
 import (
+	"context"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 
 	"github.com/awaymess/super-dashboard/backend/internal/config"
+	"github.com/awaymess/super-dashboard/backend/internal/handler"
+	"github.com/awaymess/super-dashboard/backend/internal/middleware"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/pkg/api/stocks"
+	"github.com/awaymess/super-dashboard/backend/pkg/database"
 	"github.com/awaymess/super-dashboard/backend/pkg/jobs"
 	"github.com/awaymess/super-dashboard/backend/pkg/logger"
 )
@@ -29,6 +40,19 @@ func main() {
 	// Create scheduler
 	scheduler := jobs.NewScheduler()
 
+	// Share job locks through Redis so that only one worker replica runs
+	// each tick of a job
+	if cfg.RedisURL != "" {
+		opts, err := goredis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid REDIS_URL")
+		}
+		redisClient := goredis.NewClient(opts)
+		defer redisClient.Close()
+		scheduler.SetLocker(jobs.NewRedisLocker(redisClient))
+		log.Info().Msg("Using Redis job locks")
+	}
+
 	// Add default jobs
 	for _, job := range jobs.CreateDefaultJobs() {
 		if err := scheduler.AddJob(job); err != nil {
@@ -47,10 +71,79 @@ func main() {
 		log.Info().Str("job", job.Name).Str("cron", job.CronExpr).Msg("Job registered")
 	}
 
+	// Add database-backed jobs
+	if !cfg.UseMockData && cfg.DatabaseURL != "" {
+		db, err := database.Connect(cfg.DatabaseURL)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to connect to database")
+		}
+
+		// Presets are screened on Alpha Vantage fundamentals; without a key
+		// the diff job finds the screener unavailable
+		var screenerOverviews service.OverviewSource
+		if cfg.AlphaVantageAPIKey != "" {
+			alphaVantage := stocks.NewAlphaVantageClient(cfg.AlphaVantageAPIKey)
+			alphaVantage.SetCacheTTLs(cfg.AlphaVantageQuoteCacheTTL, cfg.AlphaVantageOverviewCacheTTL)
+			screenerOverviews = overviewSource{client: alphaVantage}
+		}
+		presetService := service.NewScreenerPresetService(
+			repository.NewScreenerPresetRepository(db),
+			service.NewScreenRunner(service.NewScreenerService(repository.NewFairValueRepository(db), screenerOverviews)),
+			repository.NewNotificationRepository(db),
+		)
+		paperService := service.NewPaperTradingService(
+			repository.NewPortfolioRepository(db),
+			repository.NewPositionRepository(db),
+			repository.NewOrderRepository(db),
+			repository.NewTradeRepository(db),
+			nil,
+		)
+		fairValueService := service.NewFairValueService(
+			repository.NewFairValueRepository(db),
+			nil,
+			repository.NewAlertRepository(db),
+			repository.NewNotificationRepository(db),
+			repository.NewSettingsRepository(db),
+		)
+
+		for _, job := range []*jobs.Job{
+			jobs.ScreenerPresetDiffJob(presetService),
+			jobs.OrderExpiryJob(paperService),
+			jobs.PendingOrderFillJob(paperService),
+			jobs.FairValueRecalcJob(fairValueService),
+		} {
+			if err := scheduler.AddJob(job); err != nil {
+				log.Error().Err(err).Str("job", job.Name).Msg("Failed to add job")
+				continue
+			}
+			log.Info().Str("job", job.Name).Str("cron", job.CronExpr).Msg("Job registered")
+		}
+	}
+
 	// Start scheduler
 	scheduler.Start()
 	log.Info().Int("job_count", scheduler.JobCount()).Msg("Worker started with scheduled jobs")
 
+	// Serve job statuses to admins when an admin port is configured
+	var adminSrv *http.Server
+	if cfg.WorkerAdminPort != "" {
+		r := gin.New()
+		r.Use(gin.Recovery())
+		authService := service.NewExtendedAuthService(service.AuthServiceConfig{JWTSecret: cfg.JWTSecret})
+		handler.NewJobAdminHandler(scheduler).RegisterJobAdminRoutes(r.Group("/api/v1"), middleware.AuthMiddleware(authService), middleware.AdminMiddleware())
+
+		adminSrv = &http.Server{
+			Addr:    ":" + cfg.WorkerAdminPort,
+			Handler: r,
+		}
+		go func() {
+			log.Info().Str("addr", adminSrv.Addr).Msg("Starting worker admin server")
+			if err := adminSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Error().Err(err).Msg("Worker admin server failed")
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -58,8 +151,38 @@ func main() {
 
 	log.Info().Msg("Shutting down worker...")
 
+	if adminSrv != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := adminSrv.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("Worker admin server forced to shutdown")
+		}
+		cancel()
+	}
+
 	// Stop scheduler
 	scheduler.Stop()
 
 	log.Info().Msg("Worker shutdown complete")
 }
+
+// overviewSource adapts the Alpha Vantage client to the screener.
+type overviewSource struct {
+	client *stocks.AlphaVantageClient
+}
+
+// GetOverview fetches the company overview fields the screener filters on.
+func (s overviewSource) GetOverview(ctx context.Context, symbol string) (*service.StockOverview, error) {
+	overview, err := s.client.GetCompanyOverview(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &service.StockOverview{
+		Symbol:         symbol,
+		Name:           overview.Name,
+		Sector:         overview.Sector,
+		MarketCap:      overview.MarketCapitalization,
+		PERatio:        overview.PERatio,
+		DividendYield:  overview.DividendYield,
+		ReturnOnEquity: overview.ReturnOnEquityTTM,
+	}, nil
+}
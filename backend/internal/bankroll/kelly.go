@@ -0,0 +1,21 @@
+// Package bankroll sizes bets against a user's bankroll and measures how
+// the bankroll has performed.
+package bankroll
+
+// KellyStake returns the fractional Kelly stake for a bet at decimal odds
+// whose true probability of winning is trueProb: the full Kelly fraction
+// (b*p - q) / b of the bankroll, with b = odds - 1, scaled by kellyFactor
+// (0.5 is half Kelly). Bets without a positive edge, and invalid inputs,
+// get a zero stake.
+func KellyStake(bankroll, odds, trueProb, kellyFactor float64) float64 {
+	if bankroll <= 0 || odds <= 1 || trueProb <= 0 || trueProb >= 1 || kellyFactor <= 0 {
+		return 0
+	}
+
+	b := odds - 1
+	fraction := (b*trueProb - (1 - trueProb)) / b
+	if fraction <= 0 {
+		return 0
+	}
+	return bankroll * fraction * kellyFactor
+}
@@ -0,0 +1,38 @@
+package bankroll
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKellyStake(t *testing.T) {
+	tests := []struct {
+		name        string
+		bankroll    float64
+		odds        float64
+		trueProb    float64
+		kellyFactor float64
+		want        float64
+	}{
+		// b = 1, f = (0.6 - 0.4) / 1 = 0.2
+		{"positive edge full kelly", 1000, 2.0, 0.6, 1, 200},
+		{"positive edge half kelly", 1000, 2.0, 0.6, 0.5, 100},
+		// b = 2, f = (1 - 0.5) / 2 = 0.25
+		{"longer odds", 1000, 3.0, 0.5, 0.5, 125},
+		{"zero edge", 1000, 2.0, 0.5, 1, 0},
+		{"negative edge", 1000, 2.0, 0.4, 1, 0},
+		{"odds of one", 1000, 1.0, 0.9, 1, 0},
+		{"certain outcome", 1000, 2.0, 1, 1, 0},
+		{"no bankroll", 0, 2.0, 0.6, 1, 0},
+		{"no kelly factor", 1000, 2.0, 0.6, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := KellyStake(tt.bankroll, tt.odds, tt.trueProb, tt.kellyFactor)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("KellyStake() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
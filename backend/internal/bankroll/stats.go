@@ -0,0 +1,41 @@
+package bankroll
+
+// Streaks returns the longest runs of consecutive winning and losing bets in
+// profits, which must be in settlement order. Bets with zero profit, such as
+// voids, neither extend nor break a run.
+func Streaks(profits []float64) (longestWin, longestLoss int) {
+	var wins, losses int
+	for _, profit := range profits {
+		switch {
+		case profit > 0:
+			wins++
+			losses = 0
+		case profit < 0:
+			losses++
+			wins = 0
+		default:
+			continue
+		}
+		longestWin = max(longestWin, wins)
+		longestLoss = max(longestLoss, losses)
+	}
+	return longestWin, longestLoss
+}
+
+// CurrentDrawdown returns how far the last of balances is below the highest
+// balance before it, in percent of that peak. It is 0 at a new high or when
+// there are no balances.
+func CurrentDrawdown(balances []float64) float64 {
+	if len(balances) == 0 {
+		return 0
+	}
+	var peak float64
+	for _, balance := range balances {
+		peak = max(peak, balance)
+	}
+	last := balances[len(balances)-1]
+	if peak <= 0 || last >= peak {
+		return 0
+	}
+	return (peak - last) / peak * 100
+}
@@ -0,0 +1,49 @@
+package bankroll
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStreaks(t *testing.T) {
+	tests := []struct {
+		name              string
+		profits           []float64
+		wantWin, wantLoss int
+	}{
+		{"no bets", nil, 0, 0},
+		{"mixed", []float64{10, 15, -10, 20, 5, 8, -10, -10}, 3, 2},
+		{"voids do not break a run", []float64{-10, 0, -10, 0, -10, 12}, 1, 3},
+		{"all wins", []float64{1, 2, 3}, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			win, loss := Streaks(tt.profits)
+			if win != tt.wantWin || loss != tt.wantLoss {
+				t.Errorf("Streaks() = %d, %d, want %d, %d", win, loss, tt.wantWin, tt.wantLoss)
+			}
+		})
+	}
+}
+
+func TestCurrentDrawdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		balances []float64
+		want     float64
+	}{
+		{"no balances", nil, 0},
+		{"new high", []float64{1000, 900, 1100}, 0},
+		{"below peak", []float64{1000, 1250, 1100, 1000}, 20},
+		{"recovering", []float64{1000, 500, 800}, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CurrentDrawdown(tt.balances); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CurrentDrawdown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
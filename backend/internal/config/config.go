@@ -2,11 +2,14 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Config holds application configuration.
@@ -28,16 +31,117 @@ type Config struct {
 	// OAuth configuration (optional)
 	GoogleClientID     string `mapstructure:"GOOGLE_CLIENT_ID"`
 	GoogleClientSecret string `mapstructure:"GOOGLE_CLIENT_SECRET"`
+	GitHubClientID     string `mapstructure:"GITHUB_CLIENT_ID"`
+	GitHubClientSecret string `mapstructure:"GITHUB_CLIENT_SECRET"`
+	// Public base URL of this API, used to build the OAuth callback URLs
+	OAuthRedirectBaseURL string `mapstructure:"OAUTH_REDIRECT_BASE_URL"`
 
 	// API Keys (optional)
 	OddsAPIKey         string `mapstructure:"ODDS_API_KEY"`
 	AlphaVantageAPIKey string `mapstructure:"ALPHA_VANTAGE_API_KEY"`
+	// How long Alpha Vantage responses are cached, read from
+	// ALPHA_VANTAGE_QUOTE_CACHE_TTL and ALPHA_VANTAGE_OVERVIEW_CACHE_TTL; 0 disables
+	AlphaVantageQuoteCacheTTL    time.Duration `mapstructure:"-"`
+	AlphaVantageOverviewCacheTTL time.Duration `mapstructure:"-"`
+
+	// Notification channels for triggered alerts (optional)
+	SendGridAPIKey   string `mapstructure:"SENDGRID_API_KEY"`
+	EmailFrom        string `mapstructure:"EMAIL_FROM"`
+	EmailFromName    string `mapstructure:"EMAIL_FROM_NAME"`
+	TelegramBotToken string `mapstructure:"TELEGRAM_BOT_TOKEN"`
+	LINEChannelToken string `mapstructure:"LINE_CHANNEL_TOKEN"`
 
 	// OpenAI / NLP configuration (optional)
 	OpenAIAPIKey string `mapstructure:"OPENAI_API_KEY"`
+	// JSON file of word to weight for the mock sentiment provider (optional)
+	NLPLexiconPath string `mapstructure:"NLP_LEXICON_PATH"`
 
 	// Vector Database configuration (optional)
 	VectorDBDSN string `mapstructure:"VECTOR_DB_DSN"`
+
+	// Shared secret for verifying inbound webhook signatures (optional)
+	WebhookSecret string `mapstructure:"WEBHOOK_SECRET"`
+
+	// How DELETE /auth/me removes accounts: "delete" or "anonymize"
+	AccountDeletionMode string `mapstructure:"ACCOUNT_DELETION_MODE"`
+
+	// bcrypt cost factor for password hashes (4-31, default 10)
+	BcryptCost int `mapstructure:"BCRYPT_COST"`
+
+	// Background worker schedules, read from <NAME>_ENABLED and <NAME>_INTERVAL
+	OddsSync     WorkerConfig `mapstructure:"-"`
+	StockSync    WorkerConfig `mapstructure:"-"`
+	AlertChecker WorkerConfig `mapstructure:"-"`
+
+	// Port the worker process serves GET /api/v1/admin/jobs on (optional)
+	WorkerAdminPort string `mapstructure:"WORKER_ADMIN_PORT"`
+
+	// Nightly bankroll reconciliation, read from BANKROLL_RECONCILIATION_ENABLED
+	BankrollReconciliationEnabled bool `mapstructure:"-"`
+
+	// Reject logins until the email is verified, read from REQUIRE_EMAIL_VERIFICATION
+	RequireEmailVerification bool `mapstructure:"-"`
+
+	// Accept client-supplied OAuth profiles on POST /auth/oauth/{provider},
+	// for mock mode only, read from OAUTH_TOKEN_LOGIN_ENABLED
+	OAuthTokenLoginEnabled bool `mapstructure:"-"`
+}
+
+// WorkerConfig controls whether a background worker runs and how often it ticks.
+type WorkerConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+// Default background worker intervals.
+const (
+	DefaultOddsSyncInterval     = 5 * time.Minute
+	DefaultStockSyncInterval    = 1 * time.Minute
+	DefaultAlertCheckerInterval = 30 * time.Second
+)
+
+// Default Alpha Vantage response cache lifetimes.
+const (
+	DefaultAlphaVantageQuoteCacheTTL    = time.Minute
+	DefaultAlphaVantageOverviewCacheTTL = 24 * time.Hour
+)
+
+// loadCacheTTL reads a cache lifetime from key. Zero disables the cache; an
+// invalid or negative value falls back to defaultTTL.
+func loadCacheTTL(key string, defaultTTL time.Duration) time.Duration {
+	value := viper.GetString(key)
+	if value == "" {
+		return defaultTTL
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		log.Warn().Str("key", key).Str("value", value).Dur("default", defaultTTL).
+			Msg("Invalid cache TTL, using default")
+		return defaultTTL
+	}
+	return ttl
+}
+
+// loadWorkerConfig reads a worker's enabled flag and interval for the given
+// environment variable prefix. Workers are enabled by default; an invalid or
+// non-positive interval falls back to defaultInterval.
+func loadWorkerConfig(prefix string, defaultInterval time.Duration) WorkerConfig {
+	cfg := WorkerConfig{
+		Enabled:  parseBoolEnv(viper.GetString(prefix+"_ENABLED"), true),
+		Interval: defaultInterval,
+	}
+
+	if value := viper.GetString(prefix + "_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Warn().Str("key", prefix+"_INTERVAL").Str("value", value).Dur("default", defaultInterval).
+				Msg("Invalid worker interval, using default")
+		} else {
+			cfg.Interval = interval
+		}
+	}
+
+	return cfg
 }
 
 // parseBoolEnv parses a boolean from a string value,
@@ -69,6 +173,9 @@ func Load() (*Config, error) {
 	viper.SetDefault("ENV", "development")
 	viper.SetDefault("PORT", "8080")
 	viper.SetDefault("USE_MOCK_DATA", true)
+	viper.SetDefault("ACCOUNT_DELETION_MODE", "delete")
+	viper.SetDefault("BCRYPT_COST", bcrypt.DefaultCost)
+	viper.SetDefault("OAUTH_REDIRECT_BASE_URL", "http://localhost:8080")
 
 	// Read .env file if present
 	if err := viper.ReadInConfig(); err != nil {
@@ -88,7 +195,18 @@ func Load() (*Config, error) {
 	envKeys := []string{
 		"ENV", "PORT", "DATABASE_URL", "REDIS_URL", "JWT_SECRET",
 		"USE_MOCK_DATA", "GOOGLE_CLIENT_ID", "GOOGLE_CLIENT_SECRET",
+		"GITHUB_CLIENT_ID", "GITHUB_CLIENT_SECRET", "OAUTH_REDIRECT_BASE_URL",
 		"ODDS_API_KEY", "ALPHA_VANTAGE_API_KEY", "OPENAI_API_KEY", "VECTOR_DB_DSN",
+		"ALPHA_VANTAGE_QUOTE_CACHE_TTL", "ALPHA_VANTAGE_OVERVIEW_CACHE_TTL",
+		"NLP_LEXICON_PATH",
+		"SENDGRID_API_KEY", "EMAIL_FROM", "EMAIL_FROM_NAME", "TELEGRAM_BOT_TOKEN", "LINE_CHANNEL_TOKEN",
+		"WEBHOOK_SECRET",
+		"ACCOUNT_DELETION_MODE",
+		"BCRYPT_COST",
+		"ODDS_SYNC_ENABLED", "ODDS_SYNC_INTERVAL",
+		"STOCK_SYNC_ENABLED", "STOCK_SYNC_INTERVAL",
+		"ALERT_CHECKER_ENABLED", "ALERT_CHECKER_INTERVAL",
+		"WORKER_ADMIN_PORT",
 	}
 	for _, key := range envKeys {
 		if err := viper.BindEnv(key); err != nil {
@@ -107,5 +225,24 @@ func Load() (*Config, error) {
 		cfg.UseMockData = parseBoolEnv(envVal, true)
 	}
 
+	cfg.OddsSync = loadWorkerConfig("ODDS_SYNC", DefaultOddsSyncInterval)
+	cfg.StockSync = loadWorkerConfig("STOCK_SYNC", DefaultStockSyncInterval)
+	cfg.AlertChecker = loadWorkerConfig("ALERT_CHECKER", DefaultAlertCheckerInterval)
+	cfg.AlphaVantageQuoteCacheTTL = loadCacheTTL("ALPHA_VANTAGE_QUOTE_CACHE_TTL", DefaultAlphaVantageQuoteCacheTTL)
+	cfg.AlphaVantageOverviewCacheTTL = loadCacheTTL("ALPHA_VANTAGE_OVERVIEW_CACHE_TTL", DefaultAlphaVantageOverviewCacheTTL)
+	cfg.BankrollReconciliationEnabled = parseBoolEnv(viper.GetString("BANKROLL_RECONCILIATION_ENABLED"), true)
+	cfg.RequireEmailVerification = parseBoolEnv(viper.GetString("REQUIRE_EMAIL_VERIFICATION"), false)
+	cfg.OAuthTokenLoginEnabled = parseBoolEnv(viper.GetString("OAUTH_TOKEN_LOGIN_ENABLED"), false)
+
+	switch cfg.AccountDeletionMode {
+	case "delete", "anonymize":
+	default:
+		return nil, fmt.Errorf("invalid ACCOUNT_DELETION_MODE %q: must be \"delete\" or \"anonymize\"", cfg.AccountDeletionMode)
+	}
+
+	if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("invalid BCRYPT_COST %d: must be between %d and %d", cfg.BcryptCost, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
 	return cfg, nil
 }
@@ -1,179 +1,278 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-
-	"super-dashboard/backend/internal/model"
-	"super-dashboard/backend/internal/repository"
 )
 
-// AlertHandler handles alert-related HTTP requests.
+// AlertHandler handles alert HTTP requests.
 type AlertHandler struct {
-	alertRepo        *repository.AlertRepository
-	notificationRepo *repository.NotificationRepository
+	service service.AlertService
+	audit   *AuditRecorder
 }
 
-// NewAlertHandler creates a new AlertHandler.
-func NewAlertHandler(alertRepo *repository.AlertRepository, notificationRepo *repository.NotificationRepository) *AlertHandler {
-	return &AlertHandler{
-		alertRepo:        alertRepo,
-		notificationRepo: notificationRepo,
-	}
+// NewAlertHandler creates a new AlertHandler instance.
+func NewAlertHandler(svc service.AlertService) *AlertHandler {
+	return &AlertHandler{service: svc}
 }
 
-// CreateAlert handles POST /api/alerts
-func (h *AlertHandler) CreateAlert(c *gin.Context) {
-	userID, _ := c.Get("user_id")
+// SetAuditRecorder records alert creation in the audit log.
+func (h *AlertHandler) SetAuditRecorder(audit *AuditRecorder) {
+	h.audit = audit
+}
 
-	var req struct {
-		AlertType     string  `json:"alert_type" binding:"required"`
-		Symbol        string  `json:"symbol" binding:"required"`
-		Condition     string  `json:"condition" binding:"required"`
-		TargetValue   float64 `json:"target_value" binding:"required"`
-		Message       string  `json:"message"`
-		Enabled       bool    `json:"enabled"`
+// CreateAlertRequest represents a request to create an alert. Event alerts
+// (match_start, news, dividend, earnings) take no condition.
+type CreateAlertRequest struct {
+	Type           string  `json:"type" binding:"required"`
+	Symbol         string  `json:"symbol" binding:"required"`
+	Condition      string  `json:"condition"`
+	TargetValue    float64 `json:"target_value"`
+	Message        string  `json:"message"`
+	NotifyEmail    bool    `json:"notify_email"`
+	NotifyTelegram bool    `json:"notify_telegram"`
+	NotifyLINE     bool    `json:"notify_line"`
+	NotifyDiscord  bool    `json:"notify_discord"`
+}
+
+// UpdateAlertRequest represents a request to change an alert. Omitted fields
+// are left as they are.
+type UpdateAlertRequest struct {
+	Condition      *string  `json:"condition"`
+	TargetValue    *float64 `json:"target_value"`
+	Message        *string  `json:"message"`
+	Active         *bool    `json:"active"`
+	NotifyEmail    *bool    `json:"notify_email"`
+	NotifyTelegram *bool    `json:"notify_telegram"`
+	NotifyLINE     *bool    `json:"notify_line"`
+	NotifyDiscord  *bool    `json:"notify_discord"`
+}
+
+// CreateAlert handles POST /api/v1/alerts.
+// @Summary Create an alert
+// @Description Create an alert. The condition must be one the alert type accepts and percent_up/percent_down need a positive target value.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAlertRequest true "Alert to create"
+// @Success 201 {object} model.Alert
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /api/v1/alerts [post]
+func (h *AlertHandler) CreateAlert(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
 	}
 
+	var req CreateAlertRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	alert := &model.Alert{
-		UserID:      userID.(uuid.UUID),
-		AlertType:   req.AlertType,
-		Symbol:      req.Symbol,
-		Condition:   req.Condition,
-		TargetValue: req.TargetValue,
-		Message:     req.Message,
-		Enabled:     req.Enabled,
+		Type:           model.AlertType(req.Type),
+		Symbol:         req.Symbol,
+		Condition:      model.AlertCondition(req.Condition),
+		TargetValue:    req.TargetValue,
+		Message:        req.Message,
+		NotifyEmail:    req.NotifyEmail,
+		NotifyTelegram: req.NotifyTelegram,
+		NotifyLINE:     req.NotifyLINE,
+		NotifyDiscord:  req.NotifyDiscord,
 	}
-
-	if err := h.alertRepo.CreateAlert(c.Request.Context(), alert); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.service.CreateAlert(c.Request.Context(), userID, alert); err != nil {
+		h.respondAlertError(c, err, "failed to create alert")
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"alert": alert})
-}
-
-// GetAlerts handles GET /api/alerts
-func (h *AlertHandler) GetAlerts(c *gin.Context) {
-	userID, _ := c.Get("user_id")
-
-	alerts, err := h.alertRepo.GetUserAlerts(c.Request.Context(), userID.(uuid.UUID))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
+	h.audit.Record(c, model.AuditActionAlertCreate, map[string]interface{}{
+		"alert_id":     alert.ID,
+		"alert_type":   alert.Type,
+		"symbol":       alert.Symbol,
+		"condition":    alert.Condition,
+		"target_value": alert.TargetValue,
+	})
 
-	c.JSON(http.StatusOK, gin.H{"alerts": alerts})
+	c.JSON(http.StatusCreated, alert)
 }
 
-// GetAlertByID handles GET /api/alerts/:id
-func (h *AlertHandler) GetAlertByID(c *gin.Context) {
-	alertID, err := uuid.Parse(c.Param("id"))
+// ListAlerts handles GET /api/v1/alerts.
+// @Summary List alerts
+// @Description List the current user's alerts, active or not, oldest first
+// @Tags alerts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.Alert
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/alerts [get]
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	userID, err := currentUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert ID"})
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
-	alert, err := h.alertRepo.GetAlertByID(c.Request.Context(), alertID)
+	alerts, err := h.service.ListAlerts(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "alert not found"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to list alerts")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"alert": alert})
+	c.JSON(http.StatusOK, alerts)
 }
 
-// UpdateAlert handles PUT /api/alerts/:id
-func (h *AlertHandler) UpdateAlert(c *gin.Context) {
-	alertID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert ID"})
+// GetAlert handles GET /api/v1/alerts/:id.
+// @Summary Get an alert
+// @Tags alerts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Alert ID"
+// @Success 200 {object} model.Alert
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/alerts/{id} [get]
+func (h *AlertHandler) GetAlert(c *gin.Context) {
+	userID, alertID, ok := alertRequestIDs(c)
+	if !ok {
 		return
 	}
 
-	alert, err := h.alertRepo.GetAlertByID(c.Request.Context(), alertID)
+	alert, err := h.service.GetAlert(c.Request.Context(), userID, alertID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "alert not found"})
+		h.respondAlertError(c, err, "failed to get alert")
 		return
 	}
 
-	var req struct {
-		TargetValue *float64 `json:"target_value"`
-		Message     *string  `json:"message"`
-		Enabled     *bool    `json:"enabled"`
+	c.JSON(http.StatusOK, alert)
+}
+
+// UpdateAlert handles PUT /api/v1/alerts/:id.
+// @Summary Update an alert
+// @Description Change an alert's condition, target, message, notification channels or active flag. The result is validated like a new alert.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Alert ID"
+// @Param request body UpdateAlertRequest true "Fields to change"
+// @Success 200 {object} model.Alert
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /api/v1/alerts/{id} [put]
+func (h *AlertHandler) UpdateAlert(c *gin.Context) {
+	userID, alertID, ok := alertRequestIDs(c)
+	if !ok {
+		return
 	}
 
+	var req UpdateAlertRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	if req.TargetValue != nil {
-		alert.TargetValue = *req.TargetValue
-	}
-	if req.Message != nil {
-		alert.Message = *req.Message
+	update := service.AlertUpdate{
+		TargetValue:    req.TargetValue,
+		Message:        req.Message,
+		Active:         req.Active,
+		NotifyEmail:    req.NotifyEmail,
+		NotifyTelegram: req.NotifyTelegram,
+		NotifyLINE:     req.NotifyLINE,
+		NotifyDiscord:  req.NotifyDiscord,
 	}
-	if req.Enabled != nil {
-		alert.Enabled = *req.Enabled
+	if req.Condition != nil {
+		condition := model.AlertCondition(*req.Condition)
+		update.Condition = &condition
 	}
 
-	if err := h.alertRepo.UpdateAlert(c.Request.Context(), alert); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	alert, err := h.service.UpdateAlert(c.Request.Context(), userID, alertID, update)
+	if err != nil {
+		h.respondAlertError(c, err, "failed to update alert")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"alert": alert})
+	c.JSON(http.StatusOK, alert)
 }
 
-// DeleteAlert handles DELETE /api/alerts/:id
+// DeleteAlert handles DELETE /api/v1/alerts/:id.
+// @Summary Delete an alert
+// @Tags alerts
+// @Security BearerAuth
+// @Param id path string true "Alert ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/alerts/{id} [delete]
 func (h *AlertHandler) DeleteAlert(c *gin.Context) {
-	alertID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert ID"})
+	userID, alertID, ok := alertRequestIDs(c)
+	if !ok {
 		return
 	}
 
-	if err := h.alertRepo.DeleteAlert(c.Request.Context(), alertID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.service.DeleteAlert(c.Request.Context(), userID, alertID); err != nil {
+		h.respondAlertError(c, err, "failed to delete alert")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Alert deleted successfully"})
+	c.Status(http.StatusNoContent)
 }
 
-// GetNotifications handles GET /api/notifications
-func (h *AlertHandler) GetNotifications(c *gin.Context) {
-	userID, _ := c.Get("user_id")
-
-	notifications, err := h.notificationRepo.GetUserNotifications(c.Request.Context(), userID.(uuid.UUID), 50)
+// alertRequestIDs reads the current user and the alert ID path parameter,
+// responding with an error when either is missing or invalid.
+func alertRequestIDs(c *gin.Context) (userID, alertID uuid.UUID, ok bool) {
+	userID, err := currentUserID(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
 	}
 
-	c.JSON(http.StatusOK, gin.H{"notifications": notifications})
-}
-
-// MarkNotificationRead handles PUT /api/notifications/:id/read
-func (h *AlertHandler) MarkNotificationRead(c *gin.Context) {
-	notificationID, err := uuid.Parse(c.Param("id"))
+	alertID, err = uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification ID"})
-		return
+		respondErrorMessage(c, http.StatusBadRequest, "invalid alert id")
+		return uuid.Nil, uuid.Nil, false
 	}
+	return userID, alertID, true
+}
 
-	if err := h.notificationRepo.MarkAsRead(c.Request.Context(), notificationID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+// respondAlertError maps alert service errors to HTTP responses. Alerts that
+// fail validation are well-formed requests, so they get 422.
+func (h *AlertHandler) respondAlertError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrAlertNotFound):
+		respondError(c, http.StatusNotFound, err)
+	case errors.Is(err, model.ErrInvalidAlertType),
+		errors.Is(err, model.ErrInvalidAlertCondition),
+		errors.Is(err, model.ErrUnsupportedAlertPairing),
+		errors.Is(err, model.ErrInvalidAlertTarget):
+		respondError(c, http.StatusUnprocessableEntity, err)
+	default:
+		respondErrorMessage(c, http.StatusInternalServerError, message)
 	}
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+// RegisterAlertRoutes registers alert routes.
+func (h *AlertHandler) RegisterAlertRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	alerts := rg.Group("/alerts")
+	alerts.Use(authMiddleware)
+	{
+		alerts.POST("", h.CreateAlert)
+		alerts.GET("", h.ListAlerts)
+		alerts.GET("/:id", h.GetAlert)
+		alerts.PUT("/:id", h.UpdateAlert)
+		alerts.DELETE("/:id", h.DeleteAlert)
+	}
 }
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// mockAlertStore stores alerts in memory.
+type mockAlertStore struct {
+	alerts map[uuid.UUID]*model.Alert
+}
+
+func (m *mockAlertStore) CreateAlert(ctx context.Context, alert *model.Alert) error {
+	stored := *alert
+	m.alerts[alert.ID] = &stored
+	return nil
+}
+
+func (m *mockAlertStore) GetUserAlerts(ctx context.Context, userID uuid.UUID) ([]model.Alert, error) {
+	var alerts []model.Alert
+	for _, alert := range m.alerts {
+		if alert.UserID == userID {
+			alerts = append(alerts, *alert)
+		}
+	}
+	return alerts, nil
+}
+
+func (m *mockAlertStore) GetAlertByID(ctx context.Context, alertID uuid.UUID) (*model.Alert, error) {
+	alert, ok := m.alerts[alertID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	found := *alert
+	return &found, nil
+}
+
+func (m *mockAlertStore) UpdateAlert(ctx context.Context, alert *model.Alert) error {
+	stored := *alert
+	m.alerts[alert.ID] = &stored
+	return nil
+}
+
+func (m *mockAlertStore) DeleteAlert(ctx context.Context, alertID uuid.UUID) error {
+	delete(m.alerts, alertID)
+	return nil
+}
+
+// setupAlertRouter serves alerts for userID backed by store.
+func setupAlertRouter(store *mockAlertStore, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		c.Next()
+	}
+	NewAlertHandler(service.NewAlertService(store)).RegisterAlertRoutes(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+// addAlert stores an active stock price alert for userID.
+func (m *mockAlertStore) addAlert(userID uuid.UUID) *model.Alert {
+	alert := &model.Alert{ID: uuid.New(), UserID: userID, Type: model.AlertTypeStockPrice, Symbol: "AAPL", Condition: model.AlertConditionAbove, TargetValue: 200, Active: true}
+	m.alerts[alert.ID] = alert
+	return alert
+}
+
+func TestAlertHandler_CreateAlert(t *testing.T) {
+	store := &mockAlertStore{alerts: make(map[uuid.UUID]*model.Alert)}
+	userID := uuid.New()
+	router := setupAlertRouter(store, userID)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"price above", `{"type":"stock_price","symbol":"AAPL","condition":"above","target_value":200}`, http.StatusCreated, ""},
+		{"percent up", `{"type":"stock_price","symbol":"AAPL","condition":"percent_up","target_value":5}`, http.StatusCreated, ""},
+		{"match start without condition", `{"type":"match_start","symbol":"match-1"}`, http.StatusCreated, ""},
+		{"missing symbol", `{"type":"stock_price","condition":"above","target_value":200}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"unknown type", `{"type":"price","symbol":"AAPL","condition":"above"}`, http.StatusUnprocessableEntity, CodeInvalidAlertType},
+		{"unknown condition", `{"type":"stock_price","symbol":"AAPL","condition":"sideways"}`, http.StatusUnprocessableEntity, CodeInvalidAlertCondition},
+		{"match start with numeric condition", `{"type":"match_start","symbol":"match-1","condition":"above","target_value":1}`, http.StatusUnprocessableEntity, CodeUnsupportedAlertCondition},
+		{"percent down without target", `{"type":"stock_price","symbol":"AAPL","condition":"percent_down"}`, http.StatusUnprocessableEntity, CodeInvalidAlertTarget},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/alerts", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("Failed to parse response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, resp.Code)
+				}
+				return
+			}
+
+			var alert model.Alert
+			if err := json.Unmarshal(w.Body.Bytes(), &alert); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if alert.UserID != userID || !alert.Active || store.alerts[alert.ID] == nil {
+				t.Errorf("Expected an active alert stored for the user, got %+v", alert)
+			}
+		})
+	}
+}
+
+func TestAlertHandler_ListAlerts(t *testing.T) {
+	store := &mockAlertStore{alerts: make(map[uuid.UUID]*model.Alert)}
+	userID := uuid.New()
+	store.addAlert(userID)
+	store.addAlert(userID)
+	store.addAlert(uuid.New())
+	router := setupAlertRouter(store, userID)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/alerts", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var alerts []model.Alert
+	if err := json.Unmarshal(w.Body.Bytes(), &alerts); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Errorf("Expected the user's 2 alerts, got %d", len(alerts))
+	}
+}
+
+func TestAlertHandler_GetAlert(t *testing.T) {
+	store := &mockAlertStore{alerts: make(map[uuid.UUID]*model.Alert)}
+	userID := uuid.New()
+	own := store.addAlert(userID)
+	other := store.addAlert(uuid.New())
+	router := setupAlertRouter(store, userID)
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"own alert", own.ID.String(), http.StatusOK},
+		{"other user's alert", other.ID.String(), http.StatusNotFound},
+		{"unknown alert", uuid.New().String(), http.StatusNotFound},
+		{"invalid alert ID", "not-a-uuid", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/alerts/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAlertHandler_UpdateAlert(t *testing.T) {
+	store := &mockAlertStore{alerts: make(map[uuid.UUID]*model.Alert)}
+	userID := uuid.New()
+	alert := store.addAlert(userID)
+	other := store.addAlert(uuid.New())
+	router := setupAlertRouter(store, userID)
+
+	tests := []struct {
+		name       string
+		id         uuid.UUID
+		body       string
+		wantStatus int
+	}{
+		{"change target and pause", alert.ID, `{"target_value":250,"active":false}`, http.StatusOK},
+		{"percent condition needs a positive target", alert.ID, `{"condition":"percent_up","target_value":0}`, http.StatusUnprocessableEntity},
+		{"condition not allowed for type", alert.ID, `{"condition":"sideways"}`, http.StatusUnprocessableEntity},
+		{"other user's alert", other.ID, `{"active":false}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPut, "/api/v1/alerts/"+tt.id.String(), bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Rejected updates leave the stored alert as it was
+	stored := store.alerts[alert.ID]
+	if stored.TargetValue != 250 || stored.Active || stored.Condition != model.AlertConditionAbove {
+		t.Errorf("Unexpected stored alert %+v", stored)
+	}
+	if !store.alerts[other.ID].Active {
+		t.Error("Expected other user's alert to be unchanged")
+	}
+}
+
+func TestAlertHandler_DeleteAlert(t *testing.T) {
+	store := &mockAlertStore{alerts: make(map[uuid.UUID]*model.Alert)}
+	userID := uuid.New()
+	alert := store.addAlert(userID)
+	other := store.addAlert(uuid.New())
+	router := setupAlertRouter(store, userID)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/v1/alerts/"+other.ID.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound || store.alerts[other.ID] == nil {
+		t.Errorf("Expected other user's alert to be kept with 404, got %d", w.Code)
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, "/api/v1/alerts/"+alert.ID.String(), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if _, ok := store.alerts[alert.ID]; ok {
+		t.Error("Expected alert to be deleted")
+	}
+}
@@ -28,7 +28,7 @@ func (h *AnalyticsHandler) GetDashboardStats(c *gin.Context) {
 
 	stats, err := h.analyticsService.GetDashboardStats(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -42,7 +42,7 @@ func (h *AnalyticsHandler) GetPerformanceReport(c *gin.Context) {
 
 	report, err := h.analyticsService.GetPerformanceReport(c.Request.Context(), userID.(uuid.UUID), period)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -55,7 +55,7 @@ func (h *AnalyticsHandler) GetBettingAnalytics(c *gin.Context) {
 
 	analytics, err := h.analyticsService.GetBettingAnalytics(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -68,7 +68,7 @@ func (h *AnalyticsHandler) GetPortfolioAnalytics(c *gin.Context) {
 
 	analytics, err := h.analyticsService.GetPortfolioAnalytics(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -81,7 +81,7 @@ func (h *AnalyticsHandler) GetGoalProgress(c *gin.Context) {
 
 	progress, err := h.analyticsService.GetGoalProgress(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -92,7 +92,7 @@ func (h *AnalyticsHandler) GetGoalProgress(c *gin.Context) {
 func (h *AnalyticsHandler) GetTimeSeriesData(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	dataType := c.Param("type")
-	
+
 	daysStr := c.DefaultQuery("days", "30")
 	days, err := strconv.Atoi(daysStr)
 	if err != nil || days < 1 || days > 365 {
@@ -101,7 +101,7 @@ func (h *AnalyticsHandler) GetTimeSeriesData(c *gin.Context) {
 
 	data, err := h.analyticsService.GetTimeSeriesData(c.Request.Context(), userID.(uuid.UUID), dataType, days)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -115,7 +115,7 @@ func (h *AnalyticsHandler) ExportData(c *gin.Context) {
 
 	data, err := h.analyticsService.ExportData(c.Request.Context(), userID.(uuid.UUID), period)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// AuditRecorder writes data-modifying business actions to the audit log so
+// handlers record the acting user, client IP and details the same way.
+// A nil AuditRecorder records nothing.
+type AuditRecorder struct {
+	repo repository.AuditLogRepository
+}
+
+// NewAuditRecorder creates a new AuditRecorder instance.
+func NewAuditRecorder(repo repository.AuditLogRepository) *AuditRecorder {
+	return &AuditRecorder{repo: repo}
+}
+
+// Record stores a successful action by the current user. Failures are logged
+// and never fail the request that triggered them.
+func (a *AuditRecorder) Record(c *gin.Context, action model.AuditAction, details map[string]interface{}) {
+	if a == nil || a.repo == nil {
+		return
+	}
+
+	var detailsJSON string
+	if len(details) > 0 {
+		data, err := json.Marshal(details)
+		if err != nil {
+			log.Warn().Err(err).Str("action", string(action)).Msg("Failed to marshal audit details")
+		} else {
+			detailsJSON = string(data)
+		}
+	}
+
+	entry := &model.AuditLog{
+		ID:        uuid.New(),
+		UserID:    auditUserID(c),
+		Action:    action,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Details:   detailsJSON,
+		Success:   true,
+	}
+
+	if err := a.repo.Create(entry); err != nil {
+		log.Warn().Err(err).Str("action", string(action)).Msg("Failed to write audit log")
+	}
+}
+
+// auditUserID returns the authenticated user, if any. Older handlers store the
+// user ID as a uuid.UUID rather than the string set by the auth middleware.
+func auditUserID(c *gin.Context) *uuid.UUID {
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(uuid.UUID); ok {
+			return &id
+		}
+	}
+	if id, err := currentUserID(c); err == nil {
+		return &id
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Audit log page size bounds.
+const (
+	defaultAuditLogLimit = 50
+	maxAuditLogLimit     = 200
+)
+
+// AuditLogHandler handles administrative audit log requests.
+type AuditLogHandler struct {
+	repo repository.AuditLogRepository
+}
+
+// NewAuditLogHandler creates a new AuditLogHandler instance.
+func NewAuditLogHandler(repo repository.AuditLogRepository) *AuditLogHandler {
+	return &AuditLogHandler{repo: repo}
+}
+
+// auditLogQuery holds the parsed query parameters of ListAuditLogs.
+type auditLogQuery struct {
+	action model.AuditAction
+	filter repository.AuditLogFilter
+	limit  int
+	offset int
+}
+
+// ListAuditLogs handles GET /api/v1/admin/audit-logs.
+// @Summary List audit logs
+// @Description List a page of audit log entries across all users, newest first. The total match count is returned in X-Total-Count.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param action query string false "Audit action, e.g. login or failed_login"
+// @Param user_id query string false "User ID"
+// @Param success query bool false "Only successful (true) or failed (false) entries"
+// @Param limit query int false "Page size (1-200, default 50)"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {array} model.AuditLog
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/admin/audit-logs [get]
+func (h *AuditLogHandler) ListAuditLogs(c *gin.Context) {
+	query, err := parseAuditLogQuery(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var logs []model.AuditLog
+	if query.action != "" {
+		logs, err = h.repo.GetByAction(query.action, query.filter, query.limit, query.offset)
+	} else {
+		logs, err = h.repo.GetRecent(query.filter, query.limit, query.offset)
+	}
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to list audit logs")
+		return
+	}
+
+	total, err := h.repo.Count(query.action, query.filter)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to count audit logs")
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.JSON(http.StatusOK, logs)
+}
+
+// parseAuditLogQuery reads and validates the ListAuditLogs query parameters.
+func parseAuditLogQuery(c *gin.Context) (auditLogQuery, error) {
+	query := auditLogQuery{
+		action: model.AuditAction(c.Query("action")),
+		limit:  defaultAuditLogLimit,
+	}
+
+	if v := c.Query("user_id"); v != "" {
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			return query, errors.New("invalid user_id")
+		}
+		query.filter.UserID = &userID
+	}
+	if v := c.Query("success"); v != "" {
+		success, err := strconv.ParseBool(v)
+		if err != nil {
+			return query, errors.New("invalid success: use true or false")
+		}
+		query.filter.Success = &success
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxAuditLogLimit {
+			return query, errors.New("invalid limit: must be between 1 and 200")
+		}
+		query.limit = limit
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return query, errors.New("invalid offset")
+		}
+		query.offset = offset
+	}
+
+	return query, nil
+}
+
+// RegisterAuditLogRoutes registers audit log routes behind auth and admin checks.
+func (h *AuditLogHandler) RegisterAuditLogRoutes(rg *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	admin := rg.Group("/admin")
+	admin.Use(authMiddleware, adminMiddleware)
+	{
+		admin.GET("/audit-logs", h.ListAuditLogs)
+	}
+}
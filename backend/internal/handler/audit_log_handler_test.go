@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestAuditLogHandler_ListAuditLogs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockAuditLogRepository{}
+	for i := 0; i < defaultAuditLogLimit+5; i++ {
+		repo.logs = append(repo.logs, model.AuditLog{ID: uuid.New(), Action: model.AuditActionLogin, Success: true})
+	}
+
+	passThrough := func(c *gin.Context) { c.Next() }
+	router := gin.New()
+	NewAuditLogHandler(repo).RegisterAuditLogRoutes(router.Group("/api/v1"), passThrough, passThrough)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/audit-logs", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var logs []model.AuditLog
+	if err := json.Unmarshal(w.Body.Bytes(), &logs); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(logs) != defaultAuditLogLimit {
+		t.Errorf("Expected %d logs, got %d", defaultAuditLogLimit, len(logs))
+	}
+}
+
+func TestAuditLogHandler_ListAuditLogsFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	alice, bob := uuid.New(), uuid.New()
+	repo := &mockAuditLogRepository{logs: []model.AuditLog{
+		{ID: uuid.New(), UserID: &alice, Action: model.AuditActionLogin, Success: true},
+		{ID: uuid.New(), UserID: &alice, Action: model.AuditActionFailedLogin, Success: false},
+		{ID: uuid.New(), UserID: &bob, Action: model.AuditActionLogin, Success: true},
+		{ID: uuid.New(), UserID: &bob, Action: model.AuditActionFailedLogin, Success: false},
+		{ID: uuid.New(), UserID: &bob, Action: model.AuditActionFailedLogin, Success: false},
+	}}
+
+	passThrough := func(c *gin.Context) { c.Next() }
+	router := gin.New()
+	NewAuditLogHandler(repo).RegisterAuditLogRoutes(router.Group("/api/v1"), passThrough, passThrough)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCount  int
+		wantTotal  string
+		wantRecent bool
+	}{
+		{name: "all", query: "", wantStatus: http.StatusOK, wantCount: 5, wantTotal: "5", wantRecent: true},
+		{name: "by action", query: "?action=failed_login", wantStatus: http.StatusOK, wantCount: 3, wantTotal: "3"},
+		{name: "by action and user", query: "?action=failed_login&user_id=" + bob.String(), wantStatus: http.StatusOK, wantCount: 2, wantTotal: "2"},
+		{name: "successful only", query: "?success=true", wantStatus: http.StatusOK, wantCount: 2, wantTotal: "2", wantRecent: true},
+		{name: "failed only for user", query: "?success=false&user_id=" + alice.String(), wantStatus: http.StatusOK, wantCount: 1, wantTotal: "1", wantRecent: true},
+		{name: "paginated", query: "?limit=2&offset=4", wantStatus: http.StatusOK, wantCount: 1, wantTotal: "5", wantRecent: true},
+		{name: "offset past end", query: "?offset=10", wantStatus: http.StatusOK, wantCount: 0, wantTotal: "5", wantRecent: true},
+		{name: "limit too small", query: "?limit=0", wantStatus: http.StatusBadRequest},
+		{name: "limit too large", query: "?limit=201", wantStatus: http.StatusBadRequest},
+		{name: "negative offset", query: "?offset=-1", wantStatus: http.StatusBadRequest},
+		{name: "invalid user_id", query: "?user_id=nope", wantStatus: http.StatusBadRequest},
+		{name: "invalid success", query: "?success=maybe", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.lastAction, repo.calledRecent = "", false
+
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/audit-logs"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var logs []model.AuditLog
+			if err := json.Unmarshal(w.Body.Bytes(), &logs); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(logs) != tt.wantCount {
+				t.Errorf("Expected %d logs, got %d", tt.wantCount, len(logs))
+			}
+			if got := w.Header().Get("X-Total-Count"); got != tt.wantTotal {
+				t.Errorf("Expected X-Total-Count %s, got %s", tt.wantTotal, got)
+			}
+			if repo.calledRecent != tt.wantRecent {
+				t.Errorf("Expected GetRecent called=%v, got %v", tt.wantRecent, repo.calledRecent)
+			}
+		})
+	}
+}
+
+func TestAuditLogHandler_RequiresAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	passThrough := func(c *gin.Context) { c.Next() }
+	deny := func(c *gin.Context) {
+		respondErrorMessage(c, http.StatusForbidden, "insufficient permissions")
+		c.Abort()
+	}
+	router := gin.New()
+	NewAuditLogHandler(&mockAuditLogRepository{}).RegisterAuditLogRoutes(router.Group("/api/v1"), passThrough, deny)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/audit-logs", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// mockAuditLogRepository is a mock implementation of AuditLogRepository.
+type mockAuditLogRepository struct {
+	logs         []model.AuditLog
+	lastAction   model.AuditAction
+	calledRecent bool
+}
+
+func (m *mockAuditLogRepository) Create(log *model.AuditLog) error {
+	m.logs = append(m.logs, *log)
+	return nil
+}
+
+func (m *mockAuditLogRepository) GetByUserID(userID uuid.UUID, limit, offset int) ([]model.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *mockAuditLogRepository) GetByAction(action model.AuditAction, filter repository.AuditLogFilter, limit, offset int) ([]model.AuditLog, error) {
+	m.lastAction = action
+	logs := m.filtered(action, filter)
+	if offset >= len(logs) {
+		return []model.AuditLog{}, nil
+	}
+	end := offset + limit
+	if end > len(logs) {
+		end = len(logs)
+	}
+	return logs[offset:end], nil
+}
+
+func (m *mockAuditLogRepository) GetRecent(filter repository.AuditLogFilter, limit, offset int) ([]model.AuditLog, error) {
+	m.calledRecent = true
+	return m.GetByAction("", filter, limit, offset)
+}
+
+func (m *mockAuditLogRepository) Count(action model.AuditAction, filter repository.AuditLogFilter) (int64, error) {
+	return int64(len(m.filtered(action, filter))), nil
+}
+
+func (m *mockAuditLogRepository) filtered(action model.AuditAction, filter repository.AuditLogFilter) []model.AuditLog {
+	var logs []model.AuditLog
+	for _, log := range m.logs {
+		if action != "" && log.Action != action {
+			continue
+		}
+		if filter.UserID != nil && (log.UserID == nil || *log.UserID != *filter.UserID) {
+			continue
+		}
+		if filter.Success != nil && log.Success != *filter.Success {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs
+}
+
+func (m *mockAuditLogRepository) DeleteOlderThan(before time.Time) error {
+	return nil
+}
+
+func TestAuditRecorder_NilIsNoop(t *testing.T) {
+	var recorder *AuditRecorder
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+
+	recorder.Record(c, model.AuditActionOrderPlace, nil)
+	NewAuditRecorder(nil).Record(c, model.AuditActionOrderPlace, nil)
+}
+
+func TestPaperHandler_AuditLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := newMockPaperTradingService()
+	auditRepo := &mockAuditLogRepository{}
+	handler := NewPaperHandler(mockService)
+	handler.SetAuditRecorder(NewAuditRecorder(auditRepo))
+
+	userID := uuid.New()
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		c.Next()
+	})
+	handler.RegisterPaperRoutes(router.Group("/api/v1"))
+
+	portfolio, _ := mockService.CreatePortfolio(userID, "Test Portfolio", 100000)
+
+	body, _ := json.Marshal(PaperOrderRequest{
+		PortfolioID: portfolio.ID.String(),
+		Symbol:      "AAPL",
+		Side:        "buy",
+		OrderType:   "market",
+		Quantity:    10,
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/paper/orders", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "audit-test")
+	req.RemoteAddr = "203.0.113.7:5000"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	// A failed delete is not an action and must not be logged.
+	req, _ = http.NewRequest(http.MethodDelete, "/api/v1/paper/portfolios/"+uuid.New().String(), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, "/api/v1/paper/portfolios/"+portfolio.ID.String(), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+
+	if len(auditRepo.logs) != 2 {
+		t.Fatalf("Expected 2 audit logs, got %d", len(auditRepo.logs))
+	}
+
+	order := auditRepo.logs[0]
+	if order.Action != model.AuditActionOrderPlace {
+		t.Errorf("Expected action %s, got %s", model.AuditActionOrderPlace, order.Action)
+	}
+	if order.UserID == nil || *order.UserID != userID {
+		t.Errorf("Expected user %s, got %v", userID, order.UserID)
+	}
+	if order.IPAddress != "203.0.113.7" {
+		t.Errorf("Expected IP 203.0.113.7, got %s", order.IPAddress)
+	}
+	if order.UserAgent != "audit-test" {
+		t.Errorf("Expected user agent audit-test, got %s", order.UserAgent)
+	}
+	var details map[string]interface{}
+	if err := json.Unmarshal([]byte(order.Details), &details); err != nil {
+		t.Fatalf("Failed to parse details: %v", err)
+	}
+	if details["symbol"] != "AAPL" || details["portfolio_id"] != portfolio.ID.String() {
+		t.Errorf("Unexpected order details: %s", order.Details)
+	}
+
+	if auditRepo.logs[1].Action != model.AuditActionPortfolioDelete {
+		t.Errorf("Expected action %s, got %s", model.AuditActionPortfolioDelete, auditRepo.logs[1].Action)
+	}
+}
@@ -1,18 +1,35 @@
 package handler
 
 import (
+	"context"
+	"crypto/subtle"
+	"io"
 	"net/http"
+	"path"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 
 	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/pkg/api/notification"
 )
 
+// RefreshTokenHeader carries the caller's refresh token on session
+// management requests so the current session can be identified.
+const RefreshTokenHeader = "X-Refresh-Token"
+
+// oauthStateCookie holds the state of the OAuth login a browser started, so
+// that a callback only completes a login started in the same browser.
+const oauthStateCookie = "oauth_state"
+
 // ExtendedAuthHandler handles all authentication-related HTTP requests.
 type ExtendedAuthHandler struct {
-	authService service.ExtendedAuthService
+	authService     service.ExtendedAuthService
+	oauthTokenLogin bool
+	email           notification.EmailProvider
 }
 
 // NewExtendedAuthHandler creates a new ExtendedAuthHandler instance.
@@ -20,6 +37,53 @@ func NewExtendedAuthHandler(authService service.ExtendedAuthService) *ExtendedAu
 	return &ExtendedAuthHandler{authService: authService}
 }
 
+// requestContext returns the request's context carrying the client IP and
+// User-Agent for the service's audit events.
+func requestContext(c *gin.Context) context.Context {
+	return service.WithRequestMeta(c.Request.Context(), service.RequestMeta{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+	})
+}
+
+// SetOAuthTokenLogin enables POST /auth/oauth/google and /auth/oauth/github,
+// which trust the client-supplied OAuth profile. Only enable it in mock
+// mode; real logins go through the authorization code callbacks.
+func (h *ExtendedAuthHandler) SetOAuthTokenLogin(enabled bool) {
+	h.oauthTokenLogin = enabled
+}
+
+// SetEmailProvider sets the provider password reset and email verification
+// tokens are sent through. Without one the tokens are issued but not sent.
+func (h *ExtendedAuthHandler) SetEmailProvider(email notification.EmailProvider) {
+	h.email = email
+}
+
+// authEmailTimeout bounds sending one password reset or verification email.
+const authEmailTimeout = 10 * time.Second
+
+// sendVerificationEmail emails an email verification token.
+func (h *ExtendedAuthHandler) sendVerificationEmail(to, token string) {
+	h.sendEmail(to, "Verify your email address",
+		"Use this token to verify your email address. It expires in 24 hours and can be used once.\n\n"+token)
+}
+
+// sendEmail sends an email in the background, so that the response time
+// does not reveal whether a message was sent. Failures are logged.
+func (h *ExtendedAuthHandler) sendEmail(to, subject, body string) {
+	if h.email == nil {
+		log.Warn().Str("subject", subject).Msg("No email provider configured, email not sent")
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), authEmailTimeout)
+		defer cancel()
+		if err := h.email.SendEmail(ctx, []string{to}, subject, body); err != nil {
+			log.Error().Err(err).Str("subject", subject).Msg("Failed to send email")
+		}
+	}()
+}
+
 // LogoutRequest represents a logout request.
 type LogoutRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
@@ -27,11 +91,37 @@ type LogoutRequest struct {
 
 // UserResponse represents the current user response.
 type UserResponse struct {
-	ID           string `json:"id"`
-	Email        string `json:"email"`
-	Name         string `json:"name"`
-	Role         string `json:"role"`
-	TwoFAEnabled bool   `json:"two_fa_enabled"`
+	ID                  string `json:"id"`
+	Email               string `json:"email"`
+	Name                string `json:"name"`
+	AvatarURL           string `json:"avatar_url"`
+	Role                string `json:"role"`
+	TwoFAEnabled        bool   `json:"two_fa_enabled"`
+	EmailVerified       bool   `json:"email_verified"`
+	ProfileSyncProvider string `json:"profile_sync_provider,omitempty"`
+}
+
+// SessionResponse represents one of the current user's sessions.
+type SessionResponse struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	IPAddress string    `json:"ip_address"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Current is set on the session holding the refresh token sent in the
+	// X-Refresh-Token header.
+	Current bool `json:"current"`
+}
+
+// RevokeSessionsResponse reports how many sessions were revoked.
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+// ProfileSyncRequest chooses the OAuth provider to sync the profile from.
+type ProfileSyncRequest struct {
+	// Provider is "google" or "github"; empty turns syncing off.
+	Provider string `json:"provider"`
 }
 
 // OAuthRequest represents an OAuth callback request.
@@ -71,9 +161,58 @@ type LoginWith2FARequest struct {
 	Code     string `json:"code" binding:"required"`
 }
 
+// LoginWithRecoveryRequest represents a login request with a 2FA backup code.
+type LoginWithRecoveryRequest struct {
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required"`
+	BackupCode string `json:"backup_code" binding:"required"`
+}
+
+// DeleteAccountRequest represents the re-authentication for deleting an account.
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+	// Code is a TOTP or backup code, required when 2FA is enabled.
+	Code string `json:"code"`
+}
+
+// ChangePasswordRequest represents a password change request.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// ForgotPasswordRequest represents a password reset request.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents setting a new password with a reset token.
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// VerifyEmailRequest represents an email verification confirmation.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// LoginWithRecoveryResponse represents a backup code login response.
+type LoginWithRecoveryResponse struct {
+	AccessToken           string `json:"access_token"`
+	RefreshToken          string `json:"refresh_token"`
+	RemainingBackupCodes  int    `json:"remaining_backup_codes"`
+	RegenerateRecommended bool   `json:"regenerate_recommended"`
+}
+
+// BackupCodesResponse represents a freshly generated set of backup codes.
+type BackupCodesResponse struct {
+	BackupCodes []string `json:"backup_codes"`
+}
+
 // Register handles user registration.
 // @Summary Register a new user
-// @Description Create a new user account
+// @Description Create a new user account and email it an email verification token
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -85,24 +224,27 @@ type LoginWith2FARequest struct {
 func (h *ExtendedAuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	user, err := h.authService.Register(req.Email, req.Password, req.Name)
+	user, err := h.authService.RegisterContext(requestContext(c), req.Email, req.Password, req.Name)
 	if err != nil {
 		if err == service.ErrUserAlreadyExists {
-			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusConflict, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to register user"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to register user")
 		return
 	}
 
-	// Log audit event with request context
-	ipAddress := c.ClientIP()
-	userAgent := c.GetHeader("User-Agent")
-	_ = h.authService.LogAuditEvent(&user.ID, model.AuditActionRegister, ipAddress, userAgent, "", true)
+	// The account is created even if the verification email cannot be
+	// sent; the user can request another one.
+	if token, err := h.authService.SendVerificationEmail(user.ID); err != nil {
+		log.Warn().Err(err).Str("user_id", user.ID.String()).Msg("Failed to issue verification token")
+	} else {
+		h.sendVerificationEmail(user.Email, token)
+	}
 
 	c.JSON(http.StatusCreated, RegisterResponse{
 		ID:    user.ID.String(),
@@ -121,29 +263,40 @@ func (h *ExtendedAuthHandler) Register(c *gin.Context) {
 // @Success 200 {object} LoginResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Email not verified"
+// @Failure 423 {object} ErrorResponse "Account locked after too many failed logins"
 // @Failure 428 {object} ErrorResponse "2FA required"
 // @Router /api/v1/auth/login [post]
 func (h *ExtendedAuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	accessToken, refreshToken, err := h.authService.Login(req.Email, req.Password)
+	accessToken, refreshToken, err := h.authService.LoginContext(requestContext(c), req.Email, req.Password)
 	if err != nil {
 		if err == service.Err2FARequired {
 			c.JSON(http.StatusPreconditionRequired, gin.H{
 				"error":        "2FA verification required",
+				"code":         Code2FARequired,
 				"requires_2fa": true,
 			})
 			return
 		}
 		if err == service.ErrInvalidCredentials {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusUnauthorized, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to login"})
+		if err == service.ErrEmailNotVerified {
+			respondError(c, http.StatusForbidden, err)
+			return
+		}
+		if err == service.ErrAccountLocked {
+			respondError(c, http.StatusLocked, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to login")
 		return
 	}
 
@@ -163,25 +316,35 @@ func (h *ExtendedAuthHandler) Login(c *gin.Context) {
 // @Success 200 {object} LoginResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Email not verified"
+// @Failure 423 {object} ErrorResponse "Account locked after too many failed logins"
 // @Router /api/v1/auth/login/2fa [post]
 func (h *ExtendedAuthHandler) LoginWith2FA(c *gin.Context) {
 	var req LoginWith2FARequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	accessToken, refreshToken, err := h.authService.ValidateLoginWith2FA(req.Email, req.Password, req.Code)
+	accessToken, refreshToken, err := h.authService.ValidateLoginWith2FAContext(requestContext(c), req.Email, req.Password, req.Code)
 	if err != nil {
 		if err == service.ErrInvalidCredentials || err == service.Err2FAInvalidCode {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusUnauthorized, err)
 			return
 		}
 		if err == service.Err2FANotEnabled {
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to login"})
+		if err == service.ErrEmailNotVerified {
+			respondError(c, http.StatusForbidden, err)
+			return
+		}
+		if err == service.ErrAccountLocked {
+			respondError(c, http.StatusLocked, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to login")
 		return
 	}
 
@@ -191,6 +354,56 @@ func (h *ExtendedAuthHandler) LoginWith2FA(c *gin.Context) {
 	})
 }
 
+// LoginWithRecovery handles login with a 2FA backup code.
+// @Summary Login with backup code
+// @Description Authenticate a 2FA user with a backup code instead of a TOTP code. The code is consumed; regenerate_recommended is set when few codes remain.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body LoginWithRecoveryRequest true "Login credentials with backup code"
+// @Success 200 {object} LoginWithRecoveryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Email not verified"
+// @Failure 423 {object} ErrorResponse "Account locked after too many failed logins"
+// @Router /api/v1/auth/login/recovery [post]
+func (h *ExtendedAuthHandler) LoginWithRecovery(c *gin.Context) {
+	var req LoginWithRecoveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	accessToken, refreshToken, remaining, err := h.authService.LoginWithBackupCodeContext(requestContext(c), req.Email, req.Password, req.BackupCode)
+	if err != nil {
+		if err == service.ErrInvalidCredentials || err == service.ErrInvalidBackupCode {
+			respondError(c, http.StatusUnauthorized, err)
+			return
+		}
+		if err == service.Err2FANotEnabled {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		if err == service.ErrEmailNotVerified {
+			respondError(c, http.StatusForbidden, err)
+			return
+		}
+		if err == service.ErrAccountLocked {
+			respondError(c, http.StatusLocked, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to login")
+		return
+	}
+
+	c.JSON(http.StatusOK, LoginWithRecoveryResponse{
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		RemainingBackupCodes:  remaining,
+		RegenerateRecommended: remaining <= service.LowBackupCodesThreshold,
+	})
+}
+
 // Refresh handles token refresh.
 // @Summary Refresh access token
 // @Description Generate a new access token using a refresh token
@@ -205,13 +418,13 @@ func (h *ExtendedAuthHandler) LoginWith2FA(c *gin.Context) {
 func (h *ExtendedAuthHandler) Refresh(c *gin.Context) {
 	var req RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	accessToken, err := h.authService.RefreshToken(req.RefreshToken)
+	accessToken, err := h.authService.RefreshTokenContext(requestContext(c), req.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid refresh token"})
+		respondErrorMessage(c, http.StatusUnauthorized, "invalid refresh token")
 		return
 	}
 
@@ -234,12 +447,12 @@ func (h *ExtendedAuthHandler) Refresh(c *gin.Context) {
 func (h *ExtendedAuthHandler) Logout(c *gin.Context) {
 	var req LogoutRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := h.authService.Logout(req.RefreshToken); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "failed to logout"})
+	if err := h.authService.LogoutContext(requestContext(c), req.RefreshToken); err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "failed to logout")
 		return
 	}
 
@@ -258,40 +471,81 @@ func (h *ExtendedAuthHandler) Logout(c *gin.Context) {
 func (h *ExtendedAuthHandler) GetCurrentUser(c *gin.Context) {
 	userIDVal, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
 	userIDStr, ok := userIDVal.(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid user id"})
+		respondErrorMessage(c, http.StatusUnauthorized, "invalid user id")
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid user id"})
+		respondErrorMessage(c, http.StatusUnauthorized, "invalid user id")
 		return
 	}
 
 	user, err := h.authService.GetUserByID(userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "user not found"})
+		respondErrorMessage(c, http.StatusNotFound, "user not found")
 		return
 	}
 
-	c.JSON(http.StatusOK, UserResponse{
-		ID:           user.ID.String(),
-		Email:        user.Email,
-		Name:         user.Name,
-		Role:         user.Role,
-		TwoFAEnabled: user.TwoFAEnabled,
-	})
+	c.JSON(http.StatusOK, userToResponse(user))
+}
+
+// SetProfileSync chooses the linked provider the profile is synced from.
+// @Summary Sync profile from an OAuth provider
+// @Description Copy the name and avatar from a linked OAuth provider now and on every login with it. An empty provider turns syncing off.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ProfileSyncRequest true "Provider to sync from"
+// @Success 200 {object} UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/auth/me/profile-sync [put]
+func (h *ExtendedAuthHandler) SetProfileSync(c *gin.Context) {
+	userID, err := h.getUserIDFromContext(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req ProfileSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	provider := model.OAuthProvider(req.Provider)
+	switch provider {
+	case "", model.OAuthProviderGoogle, model.OAuthProviderGitHub:
+	default:
+		respondErrorMessage(c, http.StatusBadRequest, "invalid provider")
+		return
+	}
+
+	user, err := h.authService.SetProfileSyncProvider(userID, provider)
+	if err != nil {
+		if err == service.ErrOAuthAccountNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to update profile sync")
+		return
+	}
+
+	c.JSON(http.StatusOK, userToResponse(user))
 }
 
-// GoogleOAuth handles Google OAuth callback.
-// @Summary Google OAuth login
-// @Description Authenticate or register user via Google OAuth
+// GoogleOAuth handles Google OAuth login with a client-supplied profile.
+// @Summary Google OAuth login (mock mode)
+// @Description Authenticate or register user with a Google profile supplied by the client. Only available in mock mode; use the /oauth/google/login flow otherwise.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -303,9 +557,9 @@ func (h *ExtendedAuthHandler) GoogleOAuth(c *gin.Context) {
 	h.handleOAuth(c, model.OAuthProviderGoogle)
 }
 
-// GitHubOAuth handles GitHub OAuth callback.
-// @Summary GitHub OAuth login
-// @Description Authenticate or register user via GitHub OAuth
+// GitHubOAuth handles GitHub OAuth login with a client-supplied profile.
+// @Summary GitHub OAuth login (mock mode)
+// @Description Authenticate or register user with a GitHub profile supplied by the client. Only available in mock mode; use the /oauth/github/login flow otherwise.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -320,13 +574,13 @@ func (h *ExtendedAuthHandler) GitHubOAuth(c *gin.Context) {
 func (h *ExtendedAuthHandler) handleOAuth(c *gin.Context, expectedProvider model.OAuthProvider) {
 	var req OAuthRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	// Validate provider matches endpoint
 	if model.OAuthProvider(req.Provider) != expectedProvider {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid provider for this endpoint"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid provider for this endpoint")
 		return
 	}
 
@@ -342,23 +596,147 @@ func (h *ExtendedAuthHandler) handleOAuth(c *gin.Context, expectedProvider model
 
 	user, accessToken, refreshToken, err := h.authService.HandleOAuthLogin(info)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to authenticate with " + string(expectedProvider)})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to authenticate with "+string(expectedProvider))
 		return
 	}
 
 	c.JSON(http.StatusOK, OAuthResponse{
-		User: UserResponse{
-			ID:           user.ID.String(),
-			Email:        user.Email,
-			Name:         user.Name,
-			Role:         user.Role,
-			TwoFAEnabled: user.TwoFAEnabled,
-		},
+		User:         userToResponse(user),
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	})
 }
 
+// GoogleOAuthLogin starts a Google OAuth login.
+// @Summary Start Google OAuth login
+// @Description Redirect to Google's consent page. Google redirects back to /auth/oauth/google/callback, which must be opened in the same browser: the login's state is kept in an HttpOnly cookie.
+// @Tags auth
+// @Success 302
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/auth/oauth/google/login [get]
+func (h *ExtendedAuthHandler) GoogleOAuthLogin(c *gin.Context) {
+	h.redirectToOAuth(c, model.OAuthProviderGoogle)
+}
+
+// GoogleOAuthCallback completes a Google OAuth login.
+// @Summary Google OAuth callback
+// @Description Exchange the authorization code from Google for tokens and sign the user in
+// @Tags auth
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the login redirect"
+// @Success 200 {object} OAuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "No verified email at the provider"
+// @Failure 502 {object} ErrorResponse
+// @Router /api/v1/auth/oauth/google/callback [get]
+func (h *ExtendedAuthHandler) GoogleOAuthCallback(c *gin.Context) {
+	h.handleOAuthCallback(c, model.OAuthProviderGoogle)
+}
+
+// GitHubOAuthLogin starts a GitHub OAuth login.
+// @Summary Start GitHub OAuth login
+// @Description Redirect to GitHub's consent page. GitHub redirects back to /auth/oauth/github/callback, which must be opened in the same browser: the login's state is kept in an HttpOnly cookie.
+// @Tags auth
+// @Success 302
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/auth/oauth/github/login [get]
+func (h *ExtendedAuthHandler) GitHubOAuthLogin(c *gin.Context) {
+	h.redirectToOAuth(c, model.OAuthProviderGitHub)
+}
+
+// GitHubOAuthCallback completes a GitHub OAuth login.
+// @Summary GitHub OAuth callback
+// @Description Exchange the authorization code from GitHub for tokens and sign the user in
+// @Tags auth
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the login redirect"
+// @Success 200 {object} OAuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "No verified email at the provider"
+// @Failure 502 {object} ErrorResponse
+// @Router /api/v1/auth/oauth/github/callback [get]
+func (h *ExtendedAuthHandler) GitHubOAuthCallback(c *gin.Context) {
+	h.handleOAuthCallback(c, model.OAuthProviderGitHub)
+}
+
+func (h *ExtendedAuthHandler) redirectToOAuth(c *gin.Context, provider model.OAuthProvider) {
+	authURL, state, err := h.authService.OAuthLoginURL(provider)
+	if err != nil {
+		if err == service.ErrOAuthProviderNotConfigured || err == service.ErrTokenStoreUnavailable {
+			respondError(c, http.StatusServiceUnavailable, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to start "+string(provider)+" login")
+		return
+	}
+
+	setOAuthStateCookie(c, state, int(service.OAuthStateDuration/time.Second))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+func (h *ExtendedAuthHandler) handleOAuthCallback(c *gin.Context, provider model.OAuthProvider) {
+	// The provider reports a denied consent as an error parameter
+	if reason := c.Query("error"); reason != "" {
+		respondErrorMessage(c, http.StatusBadRequest, string(provider)+" login failed: "+reason)
+		return
+	}
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		respondErrorMessage(c, http.StatusBadRequest, "code and state are required")
+		return
+	}
+
+	// Without this check an attacker could have a victim's browser complete
+	// the attacker's own login
+	cookieState, _ := c.Cookie(oauthStateCookie)
+	setOAuthStateCookie(c, "", -1)
+	if subtle.ConstantTimeCompare([]byte(cookieState), []byte(state)) != 1 {
+		respondError(c, http.StatusBadRequest, service.ErrInvalidOAuthState)
+		return
+	}
+
+	user, accessToken, refreshToken, err := h.authService.CompleteOAuthLogin(requestContext(c), provider, state, code)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidOAuthState:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrOAuthEmailNotVerified:
+			respondError(c, http.StatusForbidden, err)
+		case service.ErrOAuthExchangeFailed:
+			respondError(c, http.StatusBadGateway, err)
+		case service.ErrOAuthProviderNotConfigured, service.ErrTokenStoreUnavailable:
+			respondError(c, http.StatusServiceUnavailable, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to authenticate with "+string(provider))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, OAuthResponse{
+		User:         userToResponse(user),
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	})
+}
+
+// setOAuthStateCookie sets the OAuth state cookie for the provider's login
+// and callback routes, which share a parent path. A negative maxAge deletes
+// it. It is sent on the provider's top-level redirect back, but not on
+// cross-site subrequests.
+func setOAuthStateCookie(c *gin.Context, state string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     path.Dir(c.Request.URL.Path),
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
 // Setup2FA sets up 2FA for the current user.
 // @Summary Setup 2FA
 // @Description Generate TOTP secret and backup codes for 2FA
@@ -372,17 +750,17 @@ func (h *ExtendedAuthHandler) handleOAuth(c *gin.Context, expectedProvider model
 func (h *ExtendedAuthHandler) Setup2FA(c *gin.Context) {
 	userID, err := h.getUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
 	setup, err := h.authService.Setup2FA(userID)
 	if err != nil {
 		if err == service.Err2FAAlreadyEnabled {
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to setup 2FA"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to setup 2FA")
 		return
 	}
 
@@ -408,26 +786,26 @@ func (h *ExtendedAuthHandler) Setup2FA(c *gin.Context) {
 func (h *ExtendedAuthHandler) Verify2FA(c *gin.Context) {
 	userID, err := h.getUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
 	var req TwoFAVerifyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.authService.Verify2FA(userID, req.Code); err != nil {
 		if err == service.Err2FAInvalidCode {
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
 		if err == service.Err2FANotEnabled {
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "2FA setup not found"})
+			respondErrorMessage(c, http.StatusBadRequest, "2FA setup not found")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to verify 2FA"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to verify 2FA")
 		return
 	}
 
@@ -449,58 +827,489 @@ func (h *ExtendedAuthHandler) Verify2FA(c *gin.Context) {
 func (h *ExtendedAuthHandler) Disable2FA(c *gin.Context) {
 	userID, err := h.getUserIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
 	var req TwoFAVerifyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.authService.Disable2FA(userID, req.Code); err != nil {
 		if err == service.Err2FAInvalidCode {
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
 		if err == service.Err2FANotEnabled {
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to disable 2FA"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to disable 2FA")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "2FA disabled successfully"})
 }
 
-// Helper to extract user ID from context
-func (h *ExtendedAuthHandler) getUserIDFromContext(c *gin.Context) (uuid.UUID, error) {
-	userIDVal, exists := c.Get("user_id")
-	if !exists {
-		return uuid.Nil, service.ErrInvalidToken
+// RegenerateBackupCodes replaces the current user's 2FA backup codes.
+// @Summary Regenerate backup codes
+// @Description Invalidate all existing backup codes and issue a new set
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TwoFAVerifyRequest true "TOTP code for verification"
+// @Success 200 {object} BackupCodesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/auth/2fa/backup-codes/regenerate [post]
+func (h *ExtendedAuthHandler) RegenerateBackupCodes(c *gin.Context) {
+	userID, err := h.getUserIDFromContext(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
 	}
 
-	userIDStr, ok := userIDVal.(string)
-	if !ok {
-		return uuid.Nil, service.ErrInvalidToken
+	var req TwoFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	backupCodes, err := h.authService.RegenerateBackupCodesContext(requestContext(c), userID, req.Code)
+	if err != nil {
+		if err == service.Err2FAInvalidCode || err == service.Err2FANotEnabled {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to regenerate backup codes")
+		return
+	}
+
+	c.JSON(http.StatusOK, BackupCodesResponse{BackupCodes: backupCodes})
+}
+
+// ChangePassword changes the current user's password.
+// @Summary Change password
+// @Description Change the current user's password after verifying the current one. All sessions are revoked, so other devices must sign in again. Accounts that sign in through OAuth only have no password to change.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/auth/password [post]
+func (h *ExtendedAuthHandler) ChangePassword(c *gin.Context) {
+	userID, err := h.getUserIDFromContext(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.authService.ChangePassword(userID, req.CurrentPassword, req.NewPassword); err != nil {
+		switch err {
+		case service.ErrInvalidCredentials:
+			respondError(c, http.StatusUnauthorized, err)
+		case service.ErrPasswordNotSet, service.ErrSamePassword:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to change password")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListSessions lists the current user's active sessions.
+// @Summary List sessions
+// @Description List the current user's active sessions. Send the refresh token in the X-Refresh-Token header to have the session holding it marked as current.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param X-Refresh-Token header string false "Refresh token of the current session"
+// @Success 200 {array} SessionResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/auth/sessions [get]
+func (h *ExtendedAuthHandler) ListSessions(c *gin.Context) {
+	userID, err := h.getUserIDFromContext(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessions, err := h.authService.GetUserSessions(userID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to list sessions")
+		return
+	}
+
+	refreshToken := c.GetHeader(RefreshTokenHeader)
+	response := make([]SessionResponse, len(sessions))
+	for i, session := range sessions {
+		response[i] = SessionResponse{
+			ID:        session.ID.String(),
+			UserAgent: session.UserAgent,
+			IPAddress: session.IPAddress,
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+			Current:   refreshToken != "" && session.RefreshToken == refreshToken,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeSession revokes one of the current user's sessions.
+// @Summary Revoke a session
+// @Description Revoke one of the current user's sessions so its refresh token stops working.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/auth/sessions/{id} [delete]
+func (h *ExtendedAuthHandler) RevokeSession(c *gin.Context) {
+	userID, err := h.getUserIDFromContext(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid session id")
+		return
+	}
+
+	if err := h.authService.RevokeUserSession(userID, sessionID); err != nil {
+		switch err {
+		case service.ErrSessionNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrSessionForbidden:
+			respondError(c, http.StatusForbidden, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to revoke session")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeOtherSessions revokes all of the current user's sessions but the current one.
+// @Summary Revoke other sessions
+// @Description Sign out every other device by revoking all of the current user's sessions except the one holding the refresh token sent in the X-Refresh-Token header.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param X-Refresh-Token header string true "Refresh token of the current session"
+// @Success 200 {object} RevokeSessionsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/auth/sessions [delete]
+func (h *ExtendedAuthHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, err := h.getUserIDFromContext(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	refreshToken := c.GetHeader(RefreshTokenHeader)
+	if refreshToken == "" {
+		respondErrorMessage(c, http.StatusBadRequest, RefreshTokenHeader+" header is required")
+		return
+	}
+
+	revoked, err := h.authService.RevokeOtherSessions(userID, refreshToken)
+	if err != nil {
+		if err == service.ErrInvalidToken {
+			respondError(c, http.StatusUnauthorized, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to revoke sessions")
+		return
+	}
+
+	c.JSON(http.StatusOK, RevokeSessionsResponse{Revoked: revoked})
+}
+
+// ForgotPassword starts a password reset for an email address.
+// @Summary Request a password reset
+// @Description Email a password reset token, valid for 1 hour, to the account using the email. The response is the same whether or not such an account exists.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ForgotPasswordRequest true "Account email"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/auth/password/forgot [post]
+func (h *ExtendedAuthHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	token, err := h.authService.RequestPasswordReset(req.Email)
+	if err != nil {
+		if err == service.ErrTokenStoreUnavailable {
+			respondError(c, http.StatusServiceUnavailable, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to request password reset")
+		return
+	}
+
+	if token != "" {
+		h.sendEmail(req.Email, "Reset your password",
+			"Use this token to reset your password. It expires in 1 hour and can be used once.\n\n"+token+
+				"\n\nIf you did not ask to reset your password, you can ignore this email.")
 	}
 
-	return uuid.Parse(userIDStr)
+	c.JSON(http.StatusAccepted, gin.H{"message": "if an account uses this email, a password reset token has been sent"})
 }
 
-// RegisterExtendedAuthRoutes registers all authentication routes.
-func (h *ExtendedAuthHandler) RegisterExtendedAuthRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+// ResetPassword sets a new password with a token from a password reset email.
+// @Summary Reset password
+// @Description Set a new password with a password reset token. Tokens are single use and expire after 1 hour. All sessions are revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Reset token and new password"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/auth/password/reset [post]
+func (h *ExtendedAuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.authService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		switch err {
+		case service.ErrInvalidResetToken:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrTokenStoreUnavailable:
+			respondError(c, http.StatusServiceUnavailable, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to reset password")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestEmailVerification sends a verification link to the current user's email.
+// @Summary Request email verification
+// @Description Email an email verification token, valid for 24 hours, to the current user. Requesting again does not invalidate earlier tokens.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} map[string]string
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/auth/verify-email/request [post]
+func (h *ExtendedAuthHandler) RequestEmailVerification(c *gin.Context) {
+	userID, err := h.getUserIDFromContext(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	user, err := h.authService.GetUserByID(userID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	token, err := h.authService.SendVerificationEmail(userID)
+	if err != nil {
+		switch err {
+		case service.ErrEmailAlreadyVerified:
+			respondError(c, http.StatusConflict, err)
+		case service.ErrTokenStoreUnavailable:
+			respondError(c, http.StatusServiceUnavailable, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to send verification email")
+		}
+		return
+	}
+	h.sendVerificationEmail(user.Email, token)
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "verification email sent"})
+}
+
+// ConfirmEmailVerification verifies an email with a token from a verification email.
+// @Summary Confirm email verification
+// @Description Mark the token's user as verified. Tokens are single use and expire after 24 hours.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "Verification token"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/auth/verify-email/confirm [post]
+func (h *ExtendedAuthHandler) ConfirmEmailVerification(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.authService.VerifyEmail(req.Token); err != nil {
+		switch err {
+		case service.ErrInvalidVerificationToken:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrEmailAlreadyVerified:
+			respondError(c, http.StatusConflict, err)
+		case service.ErrTokenStoreUnavailable:
+			respondError(c, http.StatusServiceUnavailable, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to verify email")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteAccount permanently deletes the current user's account.
+// @Summary Delete account
+// @Description Delete the current user's account after re-authentication. All sessions are revoked, linked OAuth accounts are unlinked and the user's data is deleted or anonymized depending on server configuration. Accounts without a password must have signed in within the last 5 minutes.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteAccountRequest false "Password and, when 2FA is enabled, a TOTP or backup code"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 428 {object} ErrorResponse
+// @Router /api/v1/auth/me [delete]
+func (h *ExtendedAuthHandler) DeleteAccount(c *gin.Context) {
+	userID, err := h.getUserIDFromContext(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	deletion := service.AccountDeletion{
+		Password:  req.Password,
+		Code:      req.Code,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if authTime, ok := c.Get("auth_time"); ok {
+		if v, ok := authTime.(float64); ok {
+			deletion.AuthenticatedAt = time.Unix(int64(v), 0)
+		}
+	}
+
+	if err := h.authService.DeleteAccount(userID, deletion); err != nil {
+		switch err {
+		case service.ErrInvalidCredentials, service.Err2FAInvalidCode:
+			respondError(c, http.StatusUnauthorized, err)
+		case service.ErrReauthRequired, service.Err2FARequired:
+			respondError(c, http.StatusPreconditionRequired, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to delete account")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// userToResponse converts a model.User to UserResponse.
+func userToResponse(user *model.User) UserResponse {
+	return UserResponse{
+		ID:                  user.ID.String(),
+		Email:               user.Email,
+		Name:                user.Name,
+		AvatarURL:           user.AvatarURL,
+		Role:                user.Role,
+		TwoFAEnabled:        user.TwoFAEnabled,
+		EmailVerified:       user.EmailVerified,
+		ProfileSyncProvider: string(user.ProfileSyncProvider),
+	}
+}
+
+// Helper to extract user ID from context
+func (h *ExtendedAuthHandler) getUserIDFromContext(c *gin.Context) (uuid.UUID, error) {
+	return currentUserID(c)
+}
+
+// RegisterExtendedAuthRoutes registers all authentication routes. The login
+// and password reset routes are wrapped in rateLimiter when it is not nil, and
+// the client-supplied OAuth login routes are only registered when enabled
+// with SetOAuthTokenLogin.
+func (h *ExtendedAuthHandler) RegisterExtendedAuthRoutes(rg *gin.RouterGroup, authMiddleware, rateLimiter gin.HandlerFunc) {
 	auth := rg.Group("/auth")
 	{
 		// Public routes
 		auth.POST("/register", h.Register)
-		auth.POST("/login", h.Login)
-		auth.POST("/login/2fa", h.LoginWith2FA)
 		auth.POST("/refresh", h.Refresh)
-		auth.POST("/oauth/google", h.GoogleOAuth)
-		auth.POST("/oauth/github", h.GitHubOAuth)
+		auth.GET("/oauth/google/login", h.GoogleOAuthLogin)
+		auth.GET("/oauth/google/callback", h.GoogleOAuthCallback)
+		auth.GET("/oauth/github/login", h.GitHubOAuthLogin)
+		auth.GET("/oauth/github/callback", h.GitHubOAuthCallback)
+		if h.oauthTokenLogin {
+			auth.POST("/oauth/google", h.GoogleOAuth)
+			auth.POST("/oauth/github", h.GitHubOAuth)
+		}
+		auth.POST("/verify-email/confirm", h.ConfirmEmailVerification)
+
+		// Login, rate limited against credential spraying across accounts,
+		// which per-account lockout does not stop
+		login := auth.Group("/login")
+		if rateLimiter != nil {
+			login.Use(rateLimiter)
+		}
+		{
+			login.POST("", h.Login)
+			login.POST("/2fa", h.LoginWith2FA)
+			login.POST("/recovery", h.LoginWithRecovery)
+		}
+
+		// Password reset, rate limited against token guessing and email flooding
+		reset := auth.Group("/password")
+		if rateLimiter != nil {
+			reset.Use(rateLimiter)
+		}
+		{
+			reset.POST("/forgot", h.ForgotPassword)
+			reset.POST("/reset", h.ResetPassword)
+		}
 
 		// Protected routes
 		protected := auth.Group("")
@@ -508,9 +1317,17 @@ func (h *ExtendedAuthHandler) RegisterExtendedAuthRoutes(rg *gin.RouterGroup, au
 		{
 			protected.POST("/logout", h.Logout)
 			protected.GET("/me", h.GetCurrentUser)
+			protected.DELETE("/me", h.DeleteAccount)
+			protected.POST("/password", h.ChangePassword)
+			protected.GET("/sessions", h.ListSessions)
+			protected.DELETE("/sessions", h.RevokeOtherSessions)
+			protected.DELETE("/sessions/:id", h.RevokeSession)
+			protected.POST("/verify-email/request", h.RequestEmailVerification)
+			protected.PUT("/me/profile-sync", h.SetProfileSync)
 			protected.POST("/2fa/setup", h.Setup2FA)
 			protected.POST("/2fa/verify", h.Verify2FA)
 			protected.POST("/2fa/disable", h.Disable2FA)
+			protected.POST("/2fa/backup-codes/regenerate", h.RegenerateBackupCodes)
 		}
 	}
 }
@@ -2,9 +2,12 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,18 +22,63 @@ import (
 
 // mockExtendedAuthService is a mock implementation of ExtendedAuthService for testing.
 type mockExtendedAuthService struct {
-	users        map[string]*model.User
-	twoFASetups  map[uuid.UUID]*service.TwoFactorSetup
-	twoFAEnabled map[uuid.UUID]bool
-	jwtSecret    string
+	users              map[string]*model.User
+	twoFASetups        map[uuid.UUID]*service.TwoFactorSetup
+	twoFAEnabled       map[uuid.UUID]bool
+	verificationTokens map[string]uuid.UUID
+	resetTokens        map[string]uuid.UUID
+	sessions           map[uuid.UUID]*model.Session
+	lastRequestMeta    service.RequestMeta
+	lockedEmails       map[string]bool
+	oauthStates        map[string]model.OAuthProvider
+	jwtSecret          string
+}
+
+// sentEmail is an email captured by mockEmailProvider.
+type sentEmail struct {
+	to      []string
+	subject string
+	body    string
+}
+
+// mockEmailProvider captures sent emails on a channel, since the handler
+// sends them in the background.
+type mockEmailProvider struct {
+	sent chan sentEmail
+}
+
+func newMockEmailProvider() *mockEmailProvider {
+	return &mockEmailProvider{sent: make(chan sentEmail, 10)}
+}
+
+func (m *mockEmailProvider) SendEmail(ctx context.Context, to []string, subject, body string) error {
+	m.sent <- sentEmail{to: to, subject: subject, body: body}
+	return nil
+}
+
+// next waits for the next sent email.
+func (m *mockEmailProvider) next(t *testing.T) sentEmail {
+	t.Helper()
+	select {
+	case email := <-m.sent:
+		return email
+	case <-time.After(time.Second):
+		t.Fatal("Expected an email to be sent")
+		return sentEmail{}
+	}
 }
 
 func newMockExtendedAuthService() *mockExtendedAuthService {
 	return &mockExtendedAuthService{
-		users:        make(map[string]*model.User),
-		twoFASetups:  make(map[uuid.UUID]*service.TwoFactorSetup),
-		twoFAEnabled: make(map[uuid.UUID]bool),
-		jwtSecret:    "test-secret",
+		users:              make(map[string]*model.User),
+		twoFASetups:        make(map[uuid.UUID]*service.TwoFactorSetup),
+		twoFAEnabled:       make(map[uuid.UUID]bool),
+		verificationTokens: make(map[string]uuid.UUID),
+		resetTokens:        make(map[string]uuid.UUID),
+		sessions:           make(map[uuid.UUID]*model.Session),
+		lockedEmails:       make(map[string]bool),
+		oauthStates:        make(map[string]model.OAuthProvider),
+		jwtSecret:          "test-secret",
 	}
 }
 
@@ -51,7 +99,41 @@ func (m *mockExtendedAuthService) Register(email, password, name string) (*model
 	return user, nil
 }
 
+func (m *mockExtendedAuthService) RegisterContext(ctx context.Context, email, password, name string) (*model.User, error) {
+	m.lastRequestMeta = service.RequestMetaFromContext(ctx)
+	return m.Register(email, password, name)
+}
+
+func (m *mockExtendedAuthService) LoginContext(ctx context.Context, email, password string) (string, string, error) {
+	m.lastRequestMeta = service.RequestMetaFromContext(ctx)
+	return m.Login(email, password)
+}
+
+func (m *mockExtendedAuthService) ValidateLoginWith2FAContext(ctx context.Context, email, password, code string) (string, string, error) {
+	m.lastRequestMeta = service.RequestMetaFromContext(ctx)
+	return m.ValidateLoginWith2FA(email, password, code)
+}
+
+func (m *mockExtendedAuthService) LoginWithBackupCodeContext(ctx context.Context, email, password, backupCode string) (string, string, int, error) {
+	m.lastRequestMeta = service.RequestMetaFromContext(ctx)
+	return m.LoginWithBackupCode(email, password, backupCode)
+}
+
+func (m *mockExtendedAuthService) RefreshTokenContext(ctx context.Context, refreshToken string) (string, error) {
+	m.lastRequestMeta = service.RequestMetaFromContext(ctx)
+	return m.RefreshToken(refreshToken)
+}
+
+func (m *mockExtendedAuthService) LogoutContext(ctx context.Context, refreshToken string) error {
+	m.lastRequestMeta = service.RequestMetaFromContext(ctx)
+	return m.Logout(refreshToken)
+}
+
 func (m *mockExtendedAuthService) Login(email, password string) (string, string, error) {
+	if m.lockedEmails[email] {
+		return "", "", service.ErrAccountLocked
+	}
+
 	user, exists := m.users[email]
 	if !exists {
 		return "", "", service.ErrInvalidCredentials
@@ -72,6 +154,10 @@ func (m *mockExtendedAuthService) Login(email, password string) (string, string,
 }
 
 func (m *mockExtendedAuthService) ValidateLoginWith2FA(email, password, code string) (string, string, error) {
+	if m.lockedEmails[email] {
+		return "", "", service.ErrAccountLocked
+	}
+
 	user, exists := m.users[email]
 	if !exists {
 		return "", "", service.ErrInvalidCredentials
@@ -96,6 +182,33 @@ func (m *mockExtendedAuthService) ValidateLoginWith2FA(email, password, code str
 	return accessToken, refreshToken, nil
 }
 
+func (m *mockExtendedAuthService) LoginWithBackupCode(email, password, backupCode string) (string, string, int, error) {
+	user, exists := m.users[email]
+	if !exists {
+		return "", "", 0, service.ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return "", "", 0, service.ErrInvalidCredentials
+	}
+
+	setup, exists := m.twoFASetups[user.ID]
+	if !user.TwoFAEnabled || !exists {
+		return "", "", 0, service.Err2FANotEnabled
+	}
+
+	for i, code := range setup.BackupCodes {
+		if code == backupCode {
+			setup.BackupCodes = append(setup.BackupCodes[:i], setup.BackupCodes[i+1:]...)
+			accessToken, _ := m.generateToken(user.ID, user.Email, user.Role, 15*time.Minute)
+			refreshToken, _ := m.generateToken(user.ID, user.Email, user.Role, 7*24*time.Hour)
+			return accessToken, refreshToken, len(setup.BackupCodes), nil
+		}
+	}
+
+	return "", "", 0, service.ErrInvalidBackupCode
+}
+
 func (m *mockExtendedAuthService) RefreshToken(refreshToken string) (string, error) {
 	claims, err := m.ValidateToken(refreshToken)
 	if err != nil {
@@ -161,22 +274,66 @@ func (m *mockExtendedAuthService) CreateSession(userID uuid.UUID, userAgent, ipA
 		IPAddress:    ipAddress,
 		ExpiresAt:    time.Now().Add(7 * 24 * time.Hour),
 	}
+	m.sessions[session.ID] = session
 
 	return session, accessToken, refreshToken, nil
 }
 
 func (m *mockExtendedAuthService) GetSession(sessionID uuid.UUID) (*model.Session, error) {
-	return nil, service.ErrSessionNotFound
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return nil, service.ErrSessionNotFound
+	}
+	return session, nil
 }
 
 func (m *mockExtendedAuthService) GetUserSessions(userID uuid.UUID) ([]model.Session, error) {
-	return nil, nil
+	var sessions []model.Session
+	for _, session := range m.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, *session)
+		}
+	}
+	return sessions, nil
 }
 
 func (m *mockExtendedAuthService) RevokeSession(sessionID uuid.UUID) error {
+	delete(m.sessions, sessionID)
 	return nil
 }
 
+func (m *mockExtendedAuthService) RevokeUserSession(userID, sessionID uuid.UUID) error {
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return service.ErrSessionNotFound
+	}
+	if session.UserID != userID {
+		return service.ErrSessionForbidden
+	}
+	return m.RevokeSession(sessionID)
+}
+
+func (m *mockExtendedAuthService) RevokeOtherSessions(userID uuid.UUID, currentRefreshToken string) (int, error) {
+	var current *model.Session
+	for _, session := range m.sessions {
+		if session.UserID == userID && session.RefreshToken == currentRefreshToken {
+			current = session
+		}
+	}
+	if current == nil {
+		return 0, service.ErrInvalidToken
+	}
+
+	revoked := 0
+	for id, session := range m.sessions {
+		if session.UserID == userID && id != current.ID {
+			delete(m.sessions, id)
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
 func (m *mockExtendedAuthService) RevokeAllUserSessions(userID uuid.UUID) error {
 	return nil
 }
@@ -186,14 +343,41 @@ func (m *mockExtendedAuthService) Logout(refreshToken string) error {
 	return err
 }
 
+func (m *mockExtendedAuthService) OAuthLoginURL(provider model.OAuthProvider) (string, string, error) {
+	if provider != model.OAuthProviderGoogle {
+		return "", "", service.ErrOAuthProviderNotConfigured
+	}
+	state := uuid.New().String()
+	m.oauthStates[state] = provider
+	return "https://accounts.example.com/auth?state=" + state, state, nil
+}
+
+func (m *mockExtendedAuthService) CompleteOAuthLogin(ctx context.Context, provider model.OAuthProvider, state, code string) (*model.User, string, string, error) {
+	storedProvider, ok := m.oauthStates[state]
+	delete(m.oauthStates, state)
+	if !ok || storedProvider != provider {
+		return nil, "", "", service.ErrInvalidOAuthState
+	}
+	if code != "good-code" {
+		return nil, "", "", service.ErrOAuthExchangeFailed
+	}
+	return m.HandleOAuthLogin(&service.OAuthUserInfo{
+		Provider:       provider,
+		ProviderUserID: "google-callback",
+		Email:          "callback@example.com",
+		Name:           "Callback User",
+	})
+}
+
 func (m *mockExtendedAuthService) HandleOAuthLogin(info *service.OAuthUserInfo) (*model.User, string, string, error) {
 	user, exists := m.users[info.Email]
 	if !exists {
 		user = &model.User{
-			ID:    uuid.New(),
-			Email: info.Email,
-			Name:  info.Name,
-			Role:  "user",
+			ID:        uuid.New(),
+			Email:     info.Email,
+			Name:      info.Name,
+			AvatarURL: info.AvatarURL,
+			Role:      "user",
 		}
 		m.users[info.Email] = user
 	}
@@ -216,6 +400,21 @@ func (m *mockExtendedAuthService) GetLinkedOAuthAccounts(userID uuid.UUID) ([]mo
 	return nil, nil
 }
 
+func (m *mockExtendedAuthService) SetProfileSyncProvider(userID uuid.UUID, provider model.OAuthProvider) (*model.User, error) {
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// For testing, only Google is linked
+	if provider != "" && provider != model.OAuthProviderGoogle {
+		return nil, service.ErrOAuthAccountNotFound
+	}
+
+	user.ProfileSyncProvider = provider
+	return user, nil
+}
+
 func (m *mockExtendedAuthService) Setup2FA(userID uuid.UUID) (*service.TwoFactorSetup, error) {
 	if m.twoFAEnabled[userID] {
 		return nil, service.Err2FAAlreadyEnabled
@@ -272,6 +471,129 @@ func (m *mockExtendedAuthService) Disable2FA(userID uuid.UUID, code string) erro
 	return nil
 }
 
+func (m *mockExtendedAuthService) RegenerateBackupCodesContext(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	m.lastRequestMeta = service.RequestMetaFromContext(ctx)
+	return m.RegenerateBackupCodes(userID, code)
+}
+
+func (m *mockExtendedAuthService) RegenerateBackupCodes(userID uuid.UUID, code string) ([]string, error) {
+	if !m.twoFAEnabled[userID] {
+		return nil, service.Err2FANotEnabled
+	}
+
+	if code != "123456" {
+		return nil, service.Err2FAInvalidCode
+	}
+
+	m.twoFASetups[userID].BackupCodes = []string{"NEWCODE1", "NEWCODE2", "NEWCODE3", "NEWCODE4"}
+	return m.twoFASetups[userID].BackupCodes, nil
+}
+
+func (m *mockExtendedAuthService) ChangePassword(userID uuid.UUID, oldPassword, newPassword string) error {
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.PasswordHash == "" {
+		return service.ErrPasswordNotSet
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(oldPassword)); err != nil {
+		return service.ErrInvalidCredentials
+	}
+	if oldPassword == newPassword {
+		return service.ErrSamePassword
+	}
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	user.PasswordHash = string(hashedPassword)
+	return nil
+}
+
+func (m *mockExtendedAuthService) RequestPasswordReset(email string) (string, error) {
+	user, exists := m.users[email]
+	if !exists {
+		return "", nil
+	}
+
+	token := uuid.New().String()
+	m.resetTokens[token] = user.ID
+	return token, nil
+}
+
+func (m *mockExtendedAuthService) ResetPassword(token, newPassword string) error {
+	userID, ok := m.resetTokens[token]
+	if !ok {
+		return service.ErrInvalidResetToken
+	}
+	delete(m.resetTokens, token)
+
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return service.ErrInvalidResetToken
+	}
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	user.PasswordHash = string(hashedPassword)
+	return nil
+}
+
+func (m *mockExtendedAuthService) SendVerificationEmail(userID uuid.UUID) (string, error) {
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return "", err
+	}
+	if user.EmailVerified {
+		return "", service.ErrEmailAlreadyVerified
+	}
+
+	token := uuid.New().String()
+	m.verificationTokens[token] = userID
+	return token, nil
+}
+
+func (m *mockExtendedAuthService) VerifyEmail(token string) error {
+	userID, ok := m.verificationTokens[token]
+	if !ok {
+		return service.ErrInvalidVerificationToken
+	}
+	delete(m.verificationTokens, token)
+
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return service.ErrInvalidVerificationToken
+	}
+	if user.EmailVerified {
+		return service.ErrEmailAlreadyVerified
+	}
+	user.EmailVerified = true
+	return nil
+}
+
+func (m *mockExtendedAuthService) DeleteAccount(userID uuid.UUID, req service.AccountDeletion) error {
+	user, err := m.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if req.Password == "" {
+		return service.ErrReauthRequired
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return service.ErrInvalidCredentials
+	}
+	if m.twoFAEnabled[userID] {
+		if req.Code == "" {
+			return service.Err2FARequired
+		}
+		if req.Code != "123456" {
+			return service.Err2FAInvalidCode
+		}
+	}
+
+	delete(m.users, user.Email)
+	return nil
+}
+
 func (m *mockExtendedAuthService) LogAuditEvent(userID *uuid.UUID, action model.AuditAction, ipAddress, userAgent, details string, success bool) error {
 	return nil
 }
@@ -301,7 +623,7 @@ func TestExtendedAuthHandler_Register(t *testing.T) {
 
 	router := gin.New()
 	v1 := router.Group("/api/v1")
-	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) { c.Next() })
+	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) { c.Next() }, nil)
 
 	tests := []struct {
 		name       string
@@ -353,6 +675,36 @@ func TestExtendedAuthHandler_Register(t *testing.T) {
 	}
 }
 
+func TestExtendedAuthHandler_RegisterSendsVerificationEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+	emailProvider := newMockEmailProvider()
+	handler.SetEmailProvider(emailProvider)
+	router := gin.New()
+	handler.RegisterExtendedAuthRoutes(router.Group("/api/v1"), func(c *gin.Context) { c.Next() }, nil)
+
+	body, _ := json.Marshal(RegisterRequest{Email: "new@example.com", Password: "password123", Name: "New User"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/auth/register", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	if len(mockService.verificationTokens) != 1 {
+		t.Fatalf("Expected one verification token, got %d", len(mockService.verificationTokens))
+	}
+	email := emailProvider.next(t)
+	for token := range mockService.verificationTokens {
+		if len(email.to) != 1 || email.to[0] != "new@example.com" || !strings.Contains(email.body, token) {
+			t.Errorf("Expected the token to be emailed to new@example.com, got %v: %q", email.to, email.body)
+		}
+	}
+}
+
 func TestExtendedAuthHandler_Login(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -361,15 +713,18 @@ func TestExtendedAuthHandler_Login(t *testing.T) {
 
 	router := gin.New()
 	v1 := router.Group("/api/v1")
-	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) { c.Next() })
+	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) { c.Next() }, nil)
 
 	// Register a user first
 	_, _ = mockService.Register("login@example.com", "password123", "Login User")
+	_, _ = mockService.Register("locked@example.com", "password123", "Locked User")
+	mockService.lockedEmails["locked@example.com"] = true
 
 	tests := []struct {
 		name       string
 		body       LoginRequest
 		wantStatus int
+		wantCode   string
 	}{
 		{
 			name: "valid login",
@@ -386,6 +741,7 @@ func TestExtendedAuthHandler_Login(t *testing.T) {
 				Password: "wrongpassword",
 			},
 			wantStatus: http.StatusUnauthorized,
+			wantCode:   CodeInvalidCredentials,
 		},
 		{
 			name: "non-existent user",
@@ -394,6 +750,24 @@ func TestExtendedAuthHandler_Login(t *testing.T) {
 				Password: "password123",
 			},
 			wantStatus: http.StatusUnauthorized,
+			wantCode:   CodeInvalidCredentials,
+		},
+		{
+			name: "locked account",
+			body: LoginRequest{
+				Email:    "locked@example.com",
+				Password: "password123",
+			},
+			wantStatus: http.StatusLocked,
+			wantCode:   CodeAccountLocked,
+		},
+		{
+			name: "missing password",
+			body: LoginRequest{
+				Email: "login@example.com",
+			},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeInvalidRequest,
 		},
 	}
 
@@ -409,10 +783,49 @@ func TestExtendedAuthHandler_Login(t *testing.T) {
 			if w.Code != tt.wantStatus {
 				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
 			}
+
+			if tt.wantCode != "" {
+				var response ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+				if response.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, response.Code)
+				}
+			}
 		})
 	}
 }
 
+func TestExtendedAuthHandler_LoginPassesRequestMeta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+
+	router := gin.New()
+	v1 := router.Group("/api/v1")
+	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) { c.Next() }, nil)
+
+	_, _ = mockService.Register("meta@example.com", "password123", "Meta User")
+
+	body, _ := json.Marshal(LoginRequest{Email: "meta@example.com", Password: "password123"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "test-agent/1.0")
+	req.RemoteAddr = "203.0.113.7:54321"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	want := service.RequestMeta{IPAddress: "203.0.113.7", UserAgent: "test-agent/1.0"}
+	if mockService.lastRequestMeta != want {
+		t.Errorf("Expected request meta %+v, got %+v", want, mockService.lastRequestMeta)
+	}
+}
+
 func TestExtendedAuthHandler_LoginWith2FA(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -421,7 +834,7 @@ func TestExtendedAuthHandler_LoginWith2FA(t *testing.T) {
 
 	router := gin.New()
 	v1 := router.Group("/api/v1")
-	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) { c.Next() })
+	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) { c.Next() }, nil)
 
 	// Register a user and enable 2FA
 	user, _ := mockService.Register("2fa@example.com", "password123", "2FA User")
@@ -478,6 +891,109 @@ func TestExtendedAuthHandler_LoginWith2FA(t *testing.T) {
 	}
 }
 
+func TestExtendedAuthHandler_LoginWithRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+
+	router := gin.New()
+	v1 := router.Group("/api/v1")
+	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) { c.Next() }, nil)
+
+	// Register a user and enable 2FA
+	user, _ := mockService.Register("recovery@example.com", "password123", "Recovery User")
+	_, _ = mockService.Setup2FA(user.ID)
+	_ = mockService.Verify2FA(user.ID, "123456")
+	_, _ = mockService.Register("no2fa@example.com", "password123", "No 2FA User")
+
+	tests := []struct {
+		name           string
+		body           LoginWithRecoveryRequest
+		wantStatus     int
+		wantRemaining  int
+		wantRegenerate bool
+	}{
+		{
+			name: "valid backup code",
+			body: LoginWithRecoveryRequest{
+				Email:      "recovery@example.com",
+				Password:   "password123",
+				BackupCode: "CODE1",
+			},
+			wantStatus:     http.StatusOK,
+			wantRemaining:  2,
+			wantRegenerate: true,
+		},
+		{
+			name: "reused backup code",
+			body: LoginWithRecoveryRequest{
+				Email:      "recovery@example.com",
+				Password:   "password123",
+				BackupCode: "CODE1",
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong password",
+			body: LoginWithRecoveryRequest{
+				Email:      "recovery@example.com",
+				Password:   "wrongpassword",
+				BackupCode: "CODE2",
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "2FA not enabled",
+			body: LoginWithRecoveryRequest{
+				Email:      "no2fa@example.com",
+				Password:   "password123",
+				BackupCode: "CODE2",
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "missing backup code",
+			body: LoginWithRecoveryRequest{
+				Email:    "recovery@example.com",
+				Password: "password123",
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodyBytes, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/auth/login/recovery", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				var response LoginWithRecoveryResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Failed to parse response: %v", err)
+				}
+				if response.AccessToken == "" {
+					t.Error("Expected access token to be set")
+				}
+				if response.RemainingBackupCodes != tt.wantRemaining {
+					t.Errorf("Expected %d remaining backup codes, got %d", tt.wantRemaining, response.RemainingBackupCodes)
+				}
+				if response.RegenerateRecommended != tt.wantRegenerate {
+					t.Errorf("Expected regenerate_recommended %v, got %v", tt.wantRegenerate, response.RegenerateRecommended)
+				}
+			}
+		})
+	}
+}
+
 func TestExtendedAuthHandler_Logout(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -489,7 +1005,7 @@ func TestExtendedAuthHandler_Logout(t *testing.T) {
 	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) {
 		c.Set("user_id", uuid.New().String())
 		c.Next()
-	})
+	}, nil)
 
 	// Get a valid token
 	user, _ := mockService.Register("logout@example.com", "password123", "Logout User")
@@ -548,7 +1064,7 @@ func TestExtendedAuthHandler_GetCurrentUser(t *testing.T) {
 		c.Set("email", user.Email)
 		c.Set("role", user.Role)
 		c.Next()
-	})
+	}, nil)
 
 	req, _ := http.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
 	w := httptest.NewRecorder()
@@ -573,10 +1089,11 @@ func TestExtendedAuthHandler_GoogleOAuth(t *testing.T) {
 
 	mockService := newMockExtendedAuthService()
 	handler := NewExtendedAuthHandler(mockService)
+	handler.SetOAuthTokenLogin(true)
 
 	router := gin.New()
 	v1 := router.Group("/api/v1")
-	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) { c.Next() })
+	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) { c.Next() }, nil)
 
 	tests := []struct {
 		name       string
@@ -623,7 +1140,117 @@ func TestExtendedAuthHandler_GoogleOAuth(t *testing.T) {
 	}
 }
 
-func TestExtendedAuthHandler_Setup2FA(t *testing.T) {
+func TestExtendedAuthHandler_OAuthTokenLoginDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewExtendedAuthHandler(newMockExtendedAuthService())
+	router := gin.New()
+	handler.RegisterExtendedAuthRoutes(router.Group("/api/v1"), func(c *gin.Context) { c.Next() }, nil)
+
+	bodyBytes, _ := json.Marshal(OAuthRequest{
+		Provider:       "google",
+		ProviderUserID: "google-123",
+		Email:          "google@example.com",
+		AccessToken:    "access-token",
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/auth/oauth/google", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestExtendedAuthHandler_OAuthCodeFlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+	router := gin.New()
+	handler.RegisterExtendedAuthRoutes(router.Group("/api/v1"), func(c *gin.Context) { c.Next() }, nil)
+
+	// The login route redirects to the provider with a state
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/auth/oauth/google/login", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusFound {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusFound, w.Code, w.Body.String())
+	}
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("Invalid redirect: %v", err)
+	}
+	state := location.Query().Get("state")
+	var stateCookie *http.Cookie
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "oauth_state" {
+			stateCookie = cookie
+		}
+	}
+	if stateCookie == nil || stateCookie.Value != state || !stateCookie.HttpOnly || stateCookie.Path != "/api/v1/auth/oauth/google" {
+		t.Fatalf("Expected an HttpOnly state cookie for the Google routes, got %+v", stateCookie)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/auth/oauth/github/login", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d for an unconfigured provider, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		cookieState string
+		wantStatus  int
+		wantCode    string
+	}{
+		{name: "missing code", query: "?state=" + state, cookieState: state, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidRequest},
+		{name: "consent denied", query: "?error=access_denied&state=" + state, cookieState: state, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidRequest},
+		{name: "forged state", query: "?code=good-code&state=forged", cookieState: state, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidOAuthState},
+		// Login CSRF: the attacker's valid state opened in another browser
+		{name: "no state cookie", query: "?code=good-code&state=" + state, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidOAuthState},
+		{name: "other browser's state", query: "?code=good-code&state=" + state, cookieState: "victim-state", wantStatus: http.StatusBadRequest, wantCode: CodeInvalidOAuthState},
+		{name: "valid callback", query: "?code=good-code&state=" + state, cookieState: state, wantStatus: http.StatusOK},
+		{name: "reused state", query: "?code=good-code&state=" + state, cookieState: state, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidOAuthState},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/auth/oauth/google/callback"+tt.query, nil)
+			if tt.cookieState != "" {
+				req.AddCookie(&http.Cookie{Name: "oauth_state", Value: tt.cookieState})
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var response ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+				if response.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, response.Code)
+				}
+				return
+			}
+			var response OAuthResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if response.User.Email != "callback@example.com" || response.AccessToken == "" {
+				t.Errorf("Unexpected response %+v", response)
+			}
+		})
+	}
+}
+
+func TestExtendedAuthHandler_Setup2FA(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockService := newMockExtendedAuthService()
@@ -639,7 +1266,7 @@ func TestExtendedAuthHandler_Setup2FA(t *testing.T) {
 		c.Set("email", user.Email)
 		c.Set("role", user.Role)
 		c.Next()
-	})
+	}, nil)
 
 	req, _ := http.NewRequest(http.MethodPost, "/api/v1/auth/2fa/setup", nil)
 	w := httptest.NewRecorder()
@@ -682,7 +1309,7 @@ func TestExtendedAuthHandler_Verify2FA(t *testing.T) {
 		c.Set("email", user.Email)
 		c.Set("role", user.Role)
 		c.Next()
-	})
+	}, nil)
 
 	tests := []struct {
 		name       string
@@ -713,3 +1340,583 @@ func TestExtendedAuthHandler_Verify2FA(t *testing.T) {
 		})
 	}
 }
+
+func TestExtendedAuthHandler_RegenerateBackupCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+
+	user, _ := mockService.Register("regen@example.com", "password123", "Regenerate User")
+	_, _ = mockService.Setup2FA(user.ID)
+	_ = mockService.Verify2FA(user.ID, "123456")
+
+	router := gin.New()
+	v1 := router.Group("/api/v1")
+	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) {
+		c.Set("user_id", user.ID.String())
+		c.Set("email", user.Email)
+		c.Set("role", user.Role)
+		c.Next()
+	}, nil)
+
+	tests := []struct {
+		name       string
+		code       string
+		wantStatus int
+	}{
+		{name: "valid code", code: "123456", wantStatus: http.StatusOK},
+		{name: "invalid code", code: "000000", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodyBytes, _ := json.Marshal(TwoFAVerifyRequest{Code: tt.code})
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/auth/2fa/backup-codes/regenerate", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var response BackupCodesResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if len(response.BackupCodes) == 0 {
+				t.Error("Expected new backup codes in the response")
+			}
+		})
+	}
+}
+
+func TestExtendedAuthHandler_DeleteAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+
+	// Register a user with 2FA enabled
+	user, _ := mockService.Register("delete@example.com", "password123", "Delete User")
+	_, _ = mockService.Setup2FA(user.ID)
+	_ = mockService.Verify2FA(user.ID, "123456")
+
+	router := gin.New()
+	v1 := router.Group("/api/v1")
+	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) {
+		c.Set("user_id", user.ID.String())
+		c.Set("email", user.Email)
+		c.Set("role", user.Role)
+		c.Next()
+	}, nil)
+
+	tests := []struct {
+		name       string
+		body       *DeleteAccountRequest
+		wantStatus int
+	}{
+		{
+			name:       "missing re-authentication",
+			body:       nil,
+			wantStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:       "wrong password",
+			body:       &DeleteAccountRequest{Password: "wrongpassword", Code: "123456"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing 2FA code",
+			body:       &DeleteAccountRequest{Password: "password123"},
+			wantStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:       "invalid 2FA code",
+			body:       &DeleteAccountRequest{Password: "password123", Code: "000000"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "password and 2FA code",
+			body:       &DeleteAccountRequest{Password: "password123", Code: "123456"},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			if tt.body != nil {
+				_ = json.NewEncoder(&body).Encode(tt.body)
+			}
+			req, _ := http.NewRequest(http.MethodDelete, "/api/v1/auth/me", &body)
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if _, err := mockService.GetUserByID(user.ID); err == nil {
+		t.Error("Expected user to be deleted")
+	}
+}
+
+func TestExtendedAuthHandler_ChangePassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+
+	user, _ := mockService.Register("change@example.com", "password123", "Change User")
+	oauthUser := &model.User{ID: uuid.New(), Email: "oauth@example.com", Role: "user"}
+	mockService.users[oauthUser.Email] = oauthUser
+
+	tests := []struct {
+		name       string
+		userID     uuid.UUID
+		body       ChangePasswordRequest
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "new password too short",
+			userID:     user.ID,
+			body:       ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "abc"},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeInvalidRequest,
+		},
+		{
+			name:       "wrong current password",
+			userID:     user.ID,
+			body:       ChangePasswordRequest{CurrentPassword: "wrongpassword", NewPassword: "newpassword"},
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   CodeInvalidCredentials,
+		},
+		{
+			name:       "same password",
+			userID:     user.ID,
+			body:       ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "password123"},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeSamePassword,
+		},
+		{
+			name:       "OAuth-only account",
+			userID:     oauthUser.ID,
+			body:       ChangePasswordRequest{NewPassword: "newpassword"},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodePasswordNotSet,
+		},
+		{
+			name:       "success",
+			userID:     user.ID,
+			body:       ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "newpassword"},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			handler.RegisterExtendedAuthRoutes(router.Group("/api/v1"), func(c *gin.Context) {
+				c.Set("user_id", tt.userID.String())
+				c.Next()
+			}, nil)
+
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/auth/password", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var response ErrorResponse
+				_ = json.Unmarshal(w.Body.Bytes(), &response)
+				if response.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, response.Code)
+				}
+			}
+		})
+	}
+
+	if _, _, err := mockService.Login("change@example.com", "newpassword"); err != nil {
+		t.Errorf("Expected new password to work, got %v", err)
+	}
+}
+
+func TestExtendedAuthHandler_Sessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+
+	user, _ := mockService.Register("sessions@example.com", "password123", "Sessions User")
+	other, _ := mockService.Register("other@example.com", "password123", "Other User")
+	addSession := func(userID uuid.UUID, refreshToken string) *model.Session {
+		session := &model.Session{ID: uuid.New(), UserID: userID, RefreshToken: refreshToken, ExpiresAt: time.Now().Add(time.Hour)}
+		mockService.sessions[session.ID] = session
+		return session
+	}
+	current := addSession(user.ID, "current-token")
+	laptop := addSession(user.ID, "laptop-token")
+	phone := addSession(user.ID, "phone-token")
+	otherSession := addSession(other.ID, "other-token")
+
+	router := gin.New()
+	handler.RegisterExtendedAuthRoutes(router.Group("/api/v1"), func(c *gin.Context) {
+		c.Set("user_id", user.ID.String())
+		c.Next()
+	}, nil)
+
+	do := func(method, path, refreshToken string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		if refreshToken != "" {
+			req.Header.Set(RefreshTokenHeader, refreshToken)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodGet, "/api/v1/auth/sessions", "current-token")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var sessions []SessionResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &sessions)
+	if len(sessions) != 3 {
+		t.Fatalf("Expected 3 sessions, got %d", len(sessions))
+	}
+	for _, session := range sessions {
+		if want := session.ID == current.ID.String(); session.Current != want {
+			t.Errorf("Session %s: expected current=%v, got %v", session.ID, want, session.Current)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "invalid id", path: "/api/v1/auth/sessions/not-a-uuid", wantStatus: http.StatusBadRequest, wantCode: CodeInvalidRequest},
+		{name: "unknown session", path: "/api/v1/auth/sessions/" + uuid.New().String(), wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+		{name: "another user's session", path: "/api/v1/auth/sessions/" + otherSession.ID.String(), wantStatus: http.StatusForbidden, wantCode: CodeForbidden},
+		{name: "own session", path: "/api/v1/auth/sessions/" + laptop.ID.String(), wantStatus: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(http.MethodDelete, tt.path, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var response ErrorResponse
+				_ = json.Unmarshal(w.Body.Bytes(), &response)
+				if response.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, response.Code)
+				}
+			}
+		})
+	}
+	if _, ok := mockService.sessions[otherSession.ID]; !ok {
+		t.Error("Expected other user's session to stay active")
+	}
+
+	if w := do(http.MethodDelete, "/api/v1/auth/sessions", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d without refresh token, got %d", http.StatusBadRequest, w.Code)
+	}
+	w = do(http.MethodDelete, "/api/v1/auth/sessions", "current-token")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var revoked RevokeSessionsResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &revoked)
+	if revoked.Revoked != 1 {
+		t.Errorf("Expected 1 revoked session, got %d", revoked.Revoked)
+	}
+	if _, ok := mockService.sessions[phone.ID]; ok {
+		t.Error("Expected phone session to be revoked")
+	}
+	if _, ok := mockService.sessions[current.ID]; !ok {
+		t.Error("Expected current session to stay active")
+	}
+}
+
+func TestExtendedAuthHandler_PasswordReset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+	emailProvider := newMockEmailProvider()
+	handler.SetEmailProvider(emailProvider)
+	_, _ = mockService.Register("reset@example.com", "password123", "Reset User")
+
+	router := gin.New()
+	handler.RegisterExtendedAuthRoutes(router.Group("/api/v1"), func(c *gin.Context) { c.Next() }, nil)
+
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Known and unknown emails get the same response
+	known := post("/api/v1/auth/password/forgot", ForgotPasswordRequest{Email: "reset@example.com"})
+	unknown := post("/api/v1/auth/password/forgot", ForgotPasswordRequest{Email: "nobody@example.com"})
+	if known.Code != http.StatusAccepted || unknown.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d for both emails, got %d and %d", http.StatusAccepted, known.Code, unknown.Code)
+	}
+	if known.Body.String() != unknown.Body.String() {
+		t.Errorf("Expected identical bodies, got %s and %s", known.Body.String(), unknown.Body.String())
+	}
+
+	if len(mockService.resetTokens) != 1 {
+		t.Fatalf("Expected one reset token, got %d", len(mockService.resetTokens))
+	}
+	var token string
+	for k := range mockService.resetTokens {
+		token = k
+	}
+	email := emailProvider.next(t)
+	if len(email.to) != 1 || email.to[0] != "reset@example.com" {
+		t.Errorf("Expected the email to go to reset@example.com, got %v", email.to)
+	}
+	if !strings.Contains(email.body, token) {
+		t.Errorf("Expected the email to contain the reset token, got %q", email.body)
+	}
+
+	tests := []struct {
+		name       string
+		body       ResetPasswordRequest
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "password too short", body: ResetPasswordRequest{Token: token, NewPassword: "abc"}, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidRequest},
+		{name: "unknown token", body: ResetPasswordRequest{Token: "unknown", NewPassword: "newpassword"}, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidToken},
+		{name: "success", body: ResetPasswordRequest{Token: token, NewPassword: "newpassword"}, wantStatus: http.StatusNoContent},
+		{name: "reused token", body: ResetPasswordRequest{Token: token, NewPassword: "otherpassword"}, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post("/api/v1/auth/password/reset", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var response ErrorResponse
+				_ = json.Unmarshal(w.Body.Bytes(), &response)
+				if response.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, response.Code)
+				}
+			}
+		})
+	}
+
+	if _, _, err := mockService.Login("reset@example.com", "newpassword"); err != nil {
+		t.Errorf("Expected new password to work, got %v", err)
+	}
+}
+
+func TestExtendedAuthHandler_CredentialRoutesRateLimited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewExtendedAuthHandler(newMockExtendedAuthService())
+	router := gin.New()
+	limited := func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+	}
+	handler.RegisterExtendedAuthRoutes(router.Group("/api/v1"), func(c *gin.Context) { c.Next() }, limited)
+
+	for _, path := range []string{
+		"/api/v1/auth/login",
+		"/api/v1/auth/login/2fa",
+		"/api/v1/auth/login/recovery",
+		"/api/v1/auth/password/forgot",
+		"/api/v1/auth/password/reset",
+	} {
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusTooManyRequests {
+			t.Errorf("%s: expected status %d, got %d", path, http.StatusTooManyRequests, w.Code)
+		}
+	}
+}
+
+func TestExtendedAuthHandler_EmailVerification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+	emailProvider := newMockEmailProvider()
+	handler.SetEmailProvider(emailProvider)
+
+	user, _ := mockService.Register("verify@example.com", "password123", "Verify User")
+
+	router := gin.New()
+	handler.RegisterExtendedAuthRoutes(router.Group("/api/v1"), func(c *gin.Context) {
+		c.Set("user_id", user.ID.String())
+		c.Next()
+	}, nil)
+
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("/api/v1/auth/verify-email/request", nil); w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	if len(mockService.verificationTokens) != 1 {
+		t.Fatalf("Expected one verification token, got %d", len(mockService.verificationTokens))
+	}
+	var token string
+	for k := range mockService.verificationTokens {
+		token = k
+	}
+	email := emailProvider.next(t)
+	if len(email.to) != 1 || email.to[0] != "verify@example.com" || !strings.Contains(email.body, token) {
+		t.Errorf("Expected the token to be emailed to verify@example.com, got %v: %q", email.to, email.body)
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "missing token", token: "", wantStatus: http.StatusBadRequest, wantCode: CodeInvalidRequest},
+		{name: "unknown token", token: "unknown", wantStatus: http.StatusBadRequest, wantCode: CodeInvalidToken},
+		{name: "success", token: token, wantStatus: http.StatusNoContent},
+		{name: "reused token", token: token, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post("/api/v1/auth/verify-email/confirm", VerifyEmailRequest{Token: tt.token})
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var response ErrorResponse
+				_ = json.Unmarshal(w.Body.Bytes(), &response)
+				if response.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, response.Code)
+				}
+			}
+		})
+	}
+
+	if !user.EmailVerified {
+		t.Error("Expected email to be verified")
+	}
+
+	w := post("/api/v1/auth/verify-email/request", nil)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d for verified email, got %d", http.StatusConflict, w.Code)
+	}
+	var response ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	if response.Code != CodeEmailAlreadyVerified {
+		t.Errorf("Expected code %q, got %q", CodeEmailAlreadyVerified, response.Code)
+	}
+}
+
+func TestExtendedAuthHandler_SetProfileSync(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := newMockExtendedAuthService()
+	handler := NewExtendedAuthHandler(mockService)
+
+	user, _, _, _ := mockService.HandleOAuthLogin(&service.OAuthUserInfo{
+		Provider:  model.OAuthProviderGoogle,
+		Email:     "sync@example.com",
+		Name:      "Sync User",
+		AvatarURL: "https://example.com/avatar.png",
+	})
+
+	router := gin.New()
+	v1 := router.Group("/api/v1")
+	handler.RegisterExtendedAuthRoutes(v1, func(c *gin.Context) {
+		c.Set("user_id", user.ID.String())
+		c.Set("email", user.Email)
+		c.Set("role", user.Role)
+		c.Next()
+	}, nil)
+
+	tests := []struct {
+		name       string
+		body       ProfileSyncRequest
+		wantStatus int
+	}{
+		{
+			name:       "linked provider",
+			body:       ProfileSyncRequest{Provider: "google"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "provider not linked",
+			body:       ProfileSyncRequest{Provider: "github"},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "unknown provider",
+			body:       ProfileSyncRequest{Provider: "myspace"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "turn off syncing",
+			body:       ProfileSyncRequest{},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodyBytes, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest(http.MethodPut, "/api/v1/auth/me/profile-sync", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// The avatar from the OAuth login is returned by GET /me
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response UserResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.AvatarURL != "https://example.com/avatar.png" {
+		t.Errorf("Expected avatar URL from OAuth login, got '%s'", response.AvatarURL)
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// BankrollAnalyticsHandler handles bankroll history HTTP requests.
+type BankrollAnalyticsHandler struct {
+	service service.BankrollAnalyticsService
+}
+
+// NewBankrollAnalyticsHandler creates a new BankrollAnalyticsHandler instance.
+func NewBankrollAnalyticsHandler(svc service.BankrollAnalyticsService) *BankrollAnalyticsHandler {
+	return &BankrollAnalyticsHandler{service: svc}
+}
+
+// GetHistory handles GET /api/v1/bankroll/history.
+// @Summary Get bankroll history
+// @Description Get the user's bankroll balance over a period with the ROI, yield, longest winning and losing streaks of the bets settled in it and the current drawdown from the period's peak. Periods without history return zeros.
+// @Tags bankroll
+// @Produce json
+// @Security BearerAuth
+// @Param period query string false "week, month (default), year or all"
+// @Success 200 {object} service.BankrollAnalytics
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/bankroll/history [get]
+func (h *BankrollAnalyticsHandler) GetHistory(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	analytics, err := h.service.History(c.Request.Context(), userID, c.Query("period"))
+	if err != nil {
+		switch err {
+		case service.ErrInvalidBankrollPeriod:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to get bankroll history")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// RegisterBankrollAnalyticsRoutes registers the bankroll history route.
+func (h *BankrollAnalyticsHandler) RegisterBankrollAnalyticsRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	bankroll := rg.Group("/bankroll")
+	bankroll.Use(authMiddleware)
+	{
+		bankroll.GET("/history", h.GetHistory)
+	}
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// memoryBankroll serves a fixed bankroll history.
+type memoryBankroll []model.BankrollHistory
+
+func (m memoryBankroll) Append(entry *model.BankrollHistory) error { return nil }
+
+func (m memoryBankroll) GetByUserID(userID uuid.UUID, from, to time.Time) ([]model.BankrollHistory, error) {
+	var entries []model.BankrollHistory
+	for _, entry := range m {
+		if entry.UserID == userID && !entry.CreatedAt.Before(from) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func setupBankrollAnalyticsRouter(history memoryBankroll, bets *mockBetStore, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		c.Next()
+	}
+	svc := service.NewBankrollAnalyticsService(history, bets)
+	NewBankrollAnalyticsHandler(svc).RegisterBankrollAnalyticsRoutes(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestBankrollAnalyticsHandler_GetHistory(t *testing.T) {
+	userID := uuid.New()
+	placed := time.Now().Add(-2 * time.Hour)
+	settled := placed.Add(time.Hour)
+	history := memoryBankroll{
+		{UserID: userID, Balance: 900, Change: -100, Reason: service.BankrollReasonBetPlaced, CreatedAt: placed},
+		{UserID: userID, Balance: 1150, Change: 250, Reason: service.BankrollReasonBetWon, CreatedAt: settled},
+	}
+	bets := &mockBetStore{bets: map[uuid.UUID]*model.Bet{}}
+	bet := &model.Bet{ID: uuid.New(), UserID: userID, Stake: 100, Odds: 2.5, Status: service.BetStatusSettled, Result: service.BetResultWon, Profit: 150, SettledAt: &settled}
+	bets.bets[bet.ID] = bet
+	router := setupBankrollAnalyticsRouter(history, bets, userID)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/bankroll/history?period=week", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var analytics service.BankrollAnalytics
+	if err := json.Unmarshal(w.Body.Bytes(), &analytics); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(analytics.History) != 2 || analytics.Profit != 150 || analytics.Yield != 150 || analytics.ROI != 15 {
+		t.Errorf("Expected two points, 150 profit, 150%% yield and 15%% ROI, got %+v", analytics)
+	}
+}
+
+func TestBankrollAnalyticsHandler_GetHistory_Empty(t *testing.T) {
+	router := setupBankrollAnalyticsRouter(nil, &mockBetStore{bets: map[uuid.UUID]*model.Bet{}}, uuid.New())
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/bankroll/history", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for an empty history, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if points, ok := body["history"].([]interface{}); !ok || len(points) != 0 {
+		t.Errorf("Expected an empty history list, got %v", body["history"])
+	}
+	if body["roi"] != 0.0 || body["current_drawdown"] != 0.0 || body["period"] != service.BankrollPeriodMonth {
+		t.Errorf("Expected zero statistics for the default month, got %v", body)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/bankroll/history?period=decade", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown period, got %d", w.Code)
+	}
+}
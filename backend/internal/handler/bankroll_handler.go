@@ -28,7 +28,7 @@ func (h *BankrollHandler) GetBalance(c *gin.Context) {
 
 	balance, err := h.bankrollService.GetCurrentBalance(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -44,12 +44,12 @@ func (h *BankrollHandler) Deposit(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.bankrollService.Deposit(c.Request.Context(), userID.(uuid.UUID), req.Amount); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -65,12 +65,12 @@ func (h *BankrollHandler) Withdraw(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.bankrollService.Withdraw(c.Request.Context(), userID.(uuid.UUID), req.Amount); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -89,7 +89,7 @@ func (h *BankrollHandler) GetHistory(c *gin.Context) {
 
 	history, err := h.bankrollService.GetHistory(c.Request.Context(), userID.(uuid.UUID), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -103,7 +103,7 @@ func (h *BankrollHandler) GetGrowthMetrics(c *gin.Context) {
 
 	metrics, err := h.bankrollService.GetGrowthMetrics(c.Request.Context(), userID.(uuid.UUID), period)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -122,7 +122,7 @@ func (h *BankrollHandler) GetChart(c *gin.Context) {
 
 	chartData, err := h.bankrollService.GetBankrollChart(c.Request.Context(), userID.(uuid.UUID), days)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -135,7 +135,7 @@ func (h *BankrollHandler) GetSummary(c *gin.Context) {
 
 	summary, err := h.bankrollService.GetBankrollSummary(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -147,7 +147,7 @@ func (h *BankrollHandler) ResetBankroll(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 
 	if err := h.bankrollService.ResetBankroll(c.Request.Context(), userID.(uuid.UUID)); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -21,20 +21,20 @@ type ValueBetResponse struct {
 
 // BettingStatsResponse represents betting statistics.
 type BettingStatsResponse struct {
-	TotalBets      int     `json:"total_bets"`
-	WonBets        int     `json:"won_bets"`
-	LostBets       int     `json:"lost_bets"`
-	VoidBets       int     `json:"void_bets"`
-	TotalStaked    float64 `json:"total_staked"`
-	TotalReturns   float64 `json:"total_returns"`
-	Profit         float64 `json:"profit"`
-	ROI            float64 `json:"roi"`
-	AvgOdds        float64 `json:"avg_odds"`
-	AvgStake       float64 `json:"avg_stake"`
-	WinRate        float64 `json:"win_rate"`
-	CurrentStreak  int     `json:"current_streak"`
-	BestStreak     int     `json:"best_streak"`
-	WorstStreak    int     `json:"worst_streak"`
+	TotalBets     int     `json:"total_bets"`
+	WonBets       int     `json:"won_bets"`
+	LostBets      int     `json:"lost_bets"`
+	VoidBets      int     `json:"void_bets"`
+	TotalStaked   float64 `json:"total_staked"`
+	TotalReturns  float64 `json:"total_returns"`
+	Profit        float64 `json:"profit"`
+	ROI           float64 `json:"roi"`
+	AvgOdds       float64 `json:"avg_odds"`
+	AvgStake      float64 `json:"avg_stake"`
+	WinRate       float64 `json:"win_rate"`
+	CurrentStreak int     `json:"current_streak"`
+	BestStreak    int     `json:"best_streak"`
+	WorstStreak   int     `json:"worst_streak"`
 }
 
 // BetRequest represents a bet placement request.
@@ -142,7 +142,7 @@ func (h *BetHandler) GetBettingStats(c *gin.Context) {
 func (h *BetHandler) PlaceBet(c *gin.Context) {
 	var req BetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BettingHandler handles placing, settling and listing a user's bets.
+type BettingHandler struct {
+	service service.BettingService
+}
+
+// NewBettingHandler creates a new BettingHandler instance.
+func NewBettingHandler(svc service.BettingService) *BettingHandler {
+	return &BettingHandler{service: svc}
+}
+
+// PlaceBetRequest represents a request to place a bet.
+type PlaceBetRequest struct {
+	MatchID   string  `json:"match_id" binding:"required"`
+	Market    string  `json:"market" binding:"required"`
+	Selection string  `json:"selection" binding:"required"`
+	Bookmaker string  `json:"bookmaker"`
+	Odds      float64 `json:"odds" binding:"required"`
+	Stake     float64 `json:"stake" binding:"required"`
+}
+
+// SettleBetRequest represents a request to settle a bet.
+type SettleBetRequest struct {
+	Result string `json:"result" binding:"required"`
+}
+
+// PlaceBet handles POST /api/v1/bets.
+// @Summary Place a bet
+// @Description Place a bet and debit the stake from the bankroll. The stake may not exceed the bankroll or the max stake per bet, and no more than max daily bets may be placed per day.
+// @Tags betting
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PlaceBetRequest true "Bet to place"
+// @Success 201 {object} model.Bet
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /api/v1/bets [post]
+func (h *BettingHandler) PlaceBet(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req PlaceBetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	matchID, err := uuid.Parse(req.MatchID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid match id")
+		return
+	}
+
+	bet, err := h.service.PlaceBet(c.Request.Context(), userID, service.PlaceBetRequest{
+		MatchID:   matchID,
+		Market:    req.Market,
+		Selection: req.Selection,
+		Bookmaker: req.Bookmaker,
+		Odds:      req.Odds,
+		Stake:     req.Stake,
+	})
+	if err != nil {
+		switch err {
+		case service.ErrInvalidStake, service.ErrInvalidBetOdds:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrMatchNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrStakeAboveLimit, service.ErrDailyBetLimit, service.ErrInsufficientFunds:
+			respondError(c, http.StatusUnprocessableEntity, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to place bet")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, bet)
+}
+
+// SettleBet handles POST /api/v1/bets/:id/settle.
+// @Summary Settle a bet
+// @Description Settle a pending bet as won, lost or void. Won bets credit stake times odds to the bankroll and void bets refund the stake.
+// @Tags betting
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Bet ID"
+// @Param request body SettleBetRequest true "Result"
+// @Success 200 {object} model.Bet
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/bets/{id}/settle [post]
+func (h *BettingHandler) SettleBet(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	betID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid bet id")
+		return
+	}
+
+	var req SettleBetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	bet, err := h.service.SettleBet(c.Request.Context(), userID, betID, req.Result)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidBetResult:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrBetNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrBetAlreadySettled:
+			respondError(c, http.StatusConflict, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to settle bet")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, bet)
+}
+
+// ListBets handles GET /api/v1/bets.
+// @Summary List bets
+// @Description List the current user's bets, newest first
+// @Tags betting
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status (pending, settled)"
+// @Success 200 {array} model.Bet
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/bets [get]
+func (h *BettingHandler) ListBets(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	bets, err := h.service.ListBets(c.Request.Context(), userID, c.Query("status"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to list bets")
+		return
+	}
+
+	c.JSON(http.StatusOK, bets)
+}
+
+// RegisterBettingRoutes registers bet placement and settlement routes.
+func (h *BettingHandler) RegisterBettingRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	bets := rg.Group("/bets")
+	bets.Use(authMiddleware)
+	{
+		bets.POST("", h.PlaceBet)
+		bets.GET("", h.ListBets)
+		bets.POST("/:id/settle", h.SettleBet)
+	}
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// mockBetStore stores bets for one match and one user's settings in memory.
+type mockBetStore struct {
+	matchID  uuid.UUID
+	settings model.Settings
+	bets     map[uuid.UUID]*model.Bet
+}
+
+func (m *mockBetStore) GetUserSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error) {
+	settings := m.settings
+	return &settings, nil
+}
+
+func (m *mockBetStore) GetMatch(ctx context.Context, matchID uuid.UUID) (*model.Match, error) {
+	if matchID != m.matchID {
+		return nil, errors.New("record not found")
+	}
+	return &model.Match{ID: matchID}, nil
+}
+
+func (m *mockBetStore) GetBetByID(ctx context.Context, id uuid.UUID) (*model.Bet, error) {
+	bet, ok := m.bets[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	found := *bet
+	return &found, nil
+}
+
+func (m *mockBetStore) GetUserBets(ctx context.Context, userID uuid.UUID, filters repository.BetFilters) ([]model.Bet, error) {
+	var bets []model.Bet
+	for _, bet := range m.bets {
+		if bet.UserID == userID && (filters.Status == "" || bet.Status == filters.Status) {
+			bets = append(bets, *bet)
+		}
+	}
+	return bets, nil
+}
+
+func (m *mockBetStore) CountUserBetsSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	for _, bet := range m.bets {
+		if bet.UserID == userID && !bet.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockBetStore) PlaceBet(ctx context.Context, bet *model.Bet, debit *model.BankrollHistory) error {
+	stored := *bet
+	m.bets[bet.ID] = &stored
+	m.settings.CurrentBankroll += debit.Change
+	return nil
+}
+
+func (m *mockBetStore) SettleBet(ctx context.Context, bet *model.Bet, credit *model.BankrollHistory) error {
+	stored := *bet
+	m.bets[bet.ID] = &stored
+	if credit != nil {
+		m.settings.CurrentBankroll += credit.Change
+	}
+	return nil
+}
+
+// setupBettingRouter serves bets for userID backed by store.
+func setupBettingRouter(store *mockBetStore, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		c.Next()
+	}
+	NewBettingHandler(service.NewBettingService(store, store)).RegisterBettingRoutes(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func postJSON(router *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBettingHandler_PlaceAndSettle(t *testing.T) {
+	store := &mockBetStore{matchID: uuid.New(), settings: model.Settings{CurrentBankroll: 1000}, bets: make(map[uuid.UUID]*model.Bet)}
+	router := setupBettingRouter(store, uuid.New())
+
+	w := postJSON(router, "/api/v1/bets", map[string]interface{}{
+		"match_id": store.matchID, "market": "1X2", "selection": "1", "odds": 2.0, "stake": 50,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var bet model.Bet
+	if err := json.Unmarshal(w.Body.Bytes(), &bet); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if bet.PotentialReturn != 100 || bet.Status != service.BetStatusPending {
+		t.Errorf("Expected a pending bet returning 100, got %+v", bet)
+	}
+
+	w = postJSON(router, "/api/v1/bets/"+bet.ID.String()+"/settle", map[string]string{"result": "won"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if store.settings.CurrentBankroll != 1050 {
+		t.Errorf("Expected a 1050 bankroll after the win, got %v", store.settings.CurrentBankroll)
+	}
+
+	w = postJSON(router, "/api/v1/bets/"+bet.ID.String()+"/settle", map[string]string{"result": "lost"})
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 settling twice, got %d", w.Code)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/bets?status=settled", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var bets []model.Bet
+	if err := json.Unmarshal(w.Body.Bytes(), &bets); err != nil || len(bets) != 1 || bets[0].Result != service.BetResultWon {
+		t.Errorf("Expected the won bet to be listed, got %s", w.Body.String())
+	}
+}
+
+func TestBettingHandler_PlaceBet_DailyLimit(t *testing.T) {
+	store := &mockBetStore{matchID: uuid.New(), settings: model.Settings{CurrentBankroll: 1000, MaxDailyBets: 1}, bets: make(map[uuid.UUID]*model.Bet)}
+	router := setupBettingRouter(store, uuid.New())
+	body := map[string]interface{}{"match_id": store.matchID, "market": "1X2", "selection": "X", "odds": 3.4, "stake": 10}
+
+	if w := postJSON(router, "/api/v1/bets", body); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	w := postJSON(router, "/api/v1/bets", body)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422 over the daily limit, got %d", w.Code)
+	}
+	var errResp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil || errResp.Code != CodeDailyBetLimit {
+		t.Errorf("Expected code %q, got %s", CodeDailyBetLimit, w.Body.String())
+	}
+	if len(store.bets) != 1 || store.settings.CurrentBankroll != 990 {
+		t.Errorf("Expected the rejected bet to be neither stored nor debited, got %d bets and a %v bankroll", len(store.bets), store.settings.CurrentBankroll)
+	}
+}
+
+func TestBettingHandler_BadRequests(t *testing.T) {
+	store := &mockBetStore{matchID: uuid.New(), settings: model.Settings{CurrentBankroll: 1000}, bets: make(map[uuid.UUID]*model.Bet)}
+	router := setupBettingRouter(store, uuid.New())
+
+	for _, tt := range []struct {
+		name string
+		path string
+		body interface{}
+		want int
+	}{
+		{"missing stake", "/api/v1/bets", map[string]interface{}{"match_id": store.matchID, "market": "1X2", "selection": "1", "odds": 2}, http.StatusBadRequest},
+		{"invalid match id", "/api/v1/bets", map[string]interface{}{"match_id": "abc", "market": "1X2", "selection": "1", "odds": 2, "stake": 10}, http.StatusBadRequest},
+		{"odds of 1", "/api/v1/bets", map[string]interface{}{"match_id": store.matchID, "market": "1X2", "selection": "1", "odds": 1, "stake": 10}, http.StatusBadRequest},
+		{"unknown match", "/api/v1/bets", map[string]interface{}{"match_id": uuid.New(), "market": "1X2", "selection": "1", "odds": 2, "stake": 10}, http.StatusNotFound},
+		{"invalid bet id", "/api/v1/bets/abc/settle", map[string]string{"result": "won"}, http.StatusBadRequest},
+		{"unknown bet", "/api/v1/bets/" + uuid.New().String() + "/settle", map[string]string{"result": "won"}, http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := postJSON(router, tt.path, tt.body); w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
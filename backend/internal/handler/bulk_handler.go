@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BulkHandler handles bulk watchlist and alert HTTP requests.
+type BulkHandler struct {
+	service service.BulkService
+}
+
+// NewBulkHandler creates a new BulkHandler instance.
+func NewBulkHandler(svc service.BulkService) *BulkHandler {
+	return &BulkHandler{service: svc}
+}
+
+// BulkWatchlistItemsRequest represents a request to add many symbols to a watchlist.
+type BulkWatchlistItemsRequest struct {
+	Symbols []string `json:"symbols" binding:"required"`
+}
+
+// BulkAlertRequest represents one alert in a bulk alert request.
+type BulkAlertRequest struct {
+	Type           string  `json:"type"`
+	Symbol         string  `json:"symbol"`
+	Condition      string  `json:"condition"`
+	TargetValue    float64 `json:"target_value"`
+	Message        string  `json:"message"`
+	NotifyEmail    bool    `json:"notify_email"`
+	NotifyTelegram bool    `json:"notify_telegram"`
+	NotifyLINE     bool    `json:"notify_line"`
+	NotifyDiscord  bool    `json:"notify_discord"`
+}
+
+// BulkAlertsRequest represents a request to create many alerts.
+type BulkAlertsRequest struct {
+	Alerts []BulkAlertRequest `json:"alerts" binding:"required"`
+}
+
+// AddWatchlistItems handles POST /api/v1/watchlists/:id/items/bulk.
+// @Summary Add many symbols to a watchlist
+// @Description Add up to 100 symbols in one transaction. Unknown symbols fail and symbols already in the watchlist are skipped; each symbol's outcome is reported.
+// @Tags watchlists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Watchlist ID"
+// @Param request body BulkWatchlistItemsRequest true "Symbols to add"
+// @Success 200 {object} service.BulkResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/watchlists/{id}/items/bulk [post]
+func (h *BulkHandler) AddWatchlistItems(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	watchlistID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid watchlist id")
+		return
+	}
+
+	var req BulkWatchlistItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.service.AddWatchlistItems(userID, watchlistID, req.Symbols)
+	if err != nil {
+		h.respondBulkError(c, err, "failed to add watchlist items")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateAlerts handles POST /api/v1/alerts/bulk.
+// @Summary Create many alerts
+// @Description Create up to 100 stock alerts in one transaction. Invalid alerts fail and alerts identical to an active alert are skipped; each alert's outcome is reported.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkAlertsRequest true "Alerts to create"
+// @Success 200 {object} service.BulkResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/alerts/bulk [post]
+func (h *BulkHandler) CreateAlerts(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req BulkAlertsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	alerts := make([]model.Alert, len(req.Alerts))
+	for i, alert := range req.Alerts {
+		alerts[i] = model.Alert{
+			Type:           model.AlertType(alert.Type),
+			Symbol:         alert.Symbol,
+			Condition:      model.AlertCondition(alert.Condition),
+			TargetValue:    alert.TargetValue,
+			Message:        alert.Message,
+			NotifyEmail:    alert.NotifyEmail,
+			NotifyTelegram: alert.NotifyTelegram,
+			NotifyLINE:     alert.NotifyLINE,
+			NotifyDiscord:  alert.NotifyDiscord,
+		}
+	}
+
+	result, err := h.service.CreateAlerts(userID, alerts)
+	if err != nil {
+		h.respondBulkError(c, err, "failed to create alerts")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RegisterBulkRoutes registers bulk watchlist and alert routes.
+func (h *BulkHandler) RegisterBulkRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	rg.POST("/watchlists/:id/items/bulk", authMiddleware, h.AddWatchlistItems)
+	rg.POST("/alerts/bulk", authMiddleware, h.CreateAlerts)
+}
+
+// respondBulkError maps bulk service errors to HTTP responses.
+func (h *BulkHandler) respondBulkError(c *gin.Context, err error, message string) {
+	switch err {
+	case service.ErrEmptyBulkRequest, service.ErrTooManyBulkItems:
+		respondError(c, http.StatusBadRequest, err)
+	case service.ErrWatchlistNotFound:
+		respondError(c, http.StatusNotFound, err)
+	default:
+		respondErrorMessage(c, http.StatusInternalServerError, message)
+	}
+}
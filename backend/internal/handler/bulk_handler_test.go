@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// mockBulkService is a mock implementation of BulkService.
+type mockBulkService struct {
+	watchlistID uuid.UUID
+	alerts      []model.Alert
+}
+
+func (m *mockBulkService) AddWatchlistItems(userID, watchlistID uuid.UUID, symbols []string) (*service.BulkResult, error) {
+	if len(symbols) > service.MaxBulkItems {
+		return nil, service.ErrTooManyBulkItems
+	}
+	if watchlistID != m.watchlistID {
+		return nil, service.ErrWatchlistNotFound
+	}
+	result := &service.BulkResult{}
+	for i, symbol := range symbols {
+		result.Items = append(result.Items, service.BulkItemResult{Index: i, Symbol: symbol, Status: service.BulkItemCreated})
+		result.Created++
+	}
+	return result, nil
+}
+
+func (m *mockBulkService) CreateAlerts(userID uuid.UUID, alerts []model.Alert) (*service.BulkResult, error) {
+	m.alerts = alerts
+	return &service.BulkResult{Created: len(alerts)}, nil
+}
+
+func setupBulkRouter(svc service.BulkService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		c.Next()
+	}
+	NewBulkHandler(svc).RegisterBulkRoutes(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestBulkHandler_AddWatchlistItems(t *testing.T) {
+	svc := &mockBulkService{watchlistID: uuid.New()}
+	router := setupBulkRouter(svc)
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"adds symbols", "/api/v1/watchlists/" + svc.watchlistID.String() + "/items/bulk", `{"symbols":["AAPL","MSFT"]}`, http.StatusOK, ""},
+		{"missing symbols", "/api/v1/watchlists/" + svc.watchlistID.String() + "/items/bulk", `{}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"invalid watchlist ID", "/api/v1/watchlists/not-a-uuid/items/bulk", `{"symbols":["AAPL"]}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"unknown watchlist", "/api/v1/watchlists/" + uuid.New().String() + "/items/bulk", `{"symbols":["AAPL"]}`, http.StatusNotFound, CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("Failed to parse response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, resp.Code)
+				}
+			}
+		})
+	}
+
+	symbols := make([]string, service.MaxBulkItems+1)
+	body, _ := json.Marshal(BulkWatchlistItemsRequest{Symbols: symbols})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/watchlists/"+svc.watchlistID.String()+"/items/bulk", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusBadRequest || resp.Code != CodeTooManyItems {
+		t.Errorf("Expected status %d with code %q, got %d %q", http.StatusBadRequest, CodeTooManyItems, w.Code, resp.Code)
+	}
+}
+
+func TestBulkHandler_CreateAlerts(t *testing.T) {
+	svc := &mockBulkService{}
+	router := setupBulkRouter(svc)
+
+	body := `{"alerts":[
+		{"type":"stock_price","symbol":"AAPL","condition":"above","target_value":200,"notify_email":true},
+		{"type":"stock_volume","symbol":"MSFT","condition":"percent_up","target_value":50}
+	]}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/alerts/bulk", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if len(svc.alerts) != 2 {
+		t.Fatalf("Expected 2 alerts, got %d", len(svc.alerts))
+	}
+	first := svc.alerts[0]
+	if first.Type != model.AlertTypeStockPrice || first.Symbol != "AAPL" || first.Condition != model.AlertConditionAbove || first.TargetValue != 200 || !first.NotifyEmail {
+		t.Errorf("Unexpected alert %+v", first)
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// DashboardHandler handles dashboard HTTP requests.
+type DashboardHandler struct {
+	service service.DashboardSummaryService
+}
+
+// NewDashboardHandler creates a new DashboardHandler instance.
+func NewDashboardHandler(svc service.DashboardSummaryService) *DashboardHandler {
+	return &DashboardHandler{service: svc}
+}
+
+// GetSummary handles GET /api/v1/me/summary.
+// @Summary Get dashboard summary
+// @Description Get the authenticated user's key figures in one call: paper portfolio value and day change, open bets and bankroll, unread notifications, active alerts and top watchlist movers. Summaries are cached briefly per user.
+// @Tags dashboard
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} service.DashboardSummary
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/me/summary [get]
+func (h *DashboardHandler) GetSummary(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	summary, err := h.service.GetSummary(c.Request.Context(), userID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get dashboard summary")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// RegisterDashboardRoutes registers dashboard routes.
+func (h *DashboardHandler) RegisterDashboardRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	rg.GET("/me/summary", authMiddleware, h.GetSummary)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// mockDashboardSummaryService is a mock implementation of DashboardSummaryService.
+type mockDashboardSummaryService struct {
+	err error
+}
+
+func (m *mockDashboardSummaryService) GetSummary(ctx context.Context, userID uuid.UUID) (*service.DashboardSummary, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &service.DashboardSummary{
+		Portfolio:           service.PortfolioSummary{Portfolios: 1, TotalValue: 100000},
+		UnreadNotifications: 3,
+		TopMovers:           []service.WatchlistMover{{Symbol: "AAPL", ChangePercent: 2.5}},
+	}, nil
+}
+
+func TestDashboardHandler_GetSummary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		c.Next()
+	}
+
+	tests := []struct {
+		name       string
+		svcErr     error
+		wantStatus int
+	}{
+		{"summary", nil, http.StatusOK},
+		{"service error", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			NewDashboardHandler(&mockDashboardSummaryService{err: tt.svcErr}).RegisterDashboardRoutes(router.Group("/api/v1"), authMiddleware)
+
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/me/summary", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp service.DashboardSummary
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if resp.Portfolio.TotalValue != 100000 || resp.UnreadNotifications != 3 || len(resp.TopMovers) != 1 {
+				t.Errorf("Unexpected response %+v", resp)
+			}
+		})
+	}
+}
+
+func TestDashboardHandler_GetSummary_Unauthorized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	noUser := func(c *gin.Context) { c.Next() }
+	NewDashboardHandler(&mockDashboardSummaryService{}).RegisterDashboardRoutes(router.Group("/api/v1"), noUser)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/me/summary", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d. Body: %s", http.StatusUnauthorized, w.Code, w.Body.String())
+	}
+}
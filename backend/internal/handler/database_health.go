@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// PoolStats are a database connection pool's statistics, as reported by
+// sql.DB.Stats. A growing WaitCount with InUse at MaxOpenConnections means
+// the pool is exhausted.
+type PoolStats struct {
+	MaxOpenConnections int     `json:"max_open_connections"`
+	OpenConnections    int     `json:"open_connections"`
+	InUse              int     `json:"in_use"`
+	Idle               int     `json:"idle"`
+	WaitCount          int64   `json:"wait_count"`
+	WaitDurationMS     float64 `json:"wait_duration_ms"`
+}
+
+// newPoolStats converts sql.DBStats.
+func newPoolStats(stats sql.DBStats) *PoolStats {
+	return &PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMS:     float64(stats.WaitDuration.Microseconds()) / 1000,
+	}
+}
+
+// AddDatabaseChecker adds a readiness check, named name, that pings db
+// within timeout. The check reports the connection pool's statistics
+// whether or not the ping succeeds.
+func (h *HealthHandler) AddDatabaseChecker(name string, db *sql.DB, timeout time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checkers = append(h.checkers, func() (string, bool, DependencyStatus) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		status := DependencyStatus{Status: "up", Message: "connected"}
+		healthy := true
+		if err := db.PingContext(ctx); err != nil {
+			status = DependencyStatus{Status: "down", Message: err.Error()}
+			healthy = false
+		}
+		status.Pool = newPoolStats(db.Stats())
+		return name, healthy, status
+	})
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pingDriver is a database/sql driver whose connections only answer pings,
+// failing while down is set.
+type pingDriver struct {
+	down atomic.Bool
+}
+
+type pingConn struct {
+	driver *pingDriver
+}
+
+func (d *pingDriver) Open(string) (driver.Conn, error) {
+	return &pingConn{driver: d}, nil
+}
+
+func (c *pingConn) Ping(context.Context) error {
+	if c.driver.down.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (c *pingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *pingConn) Close() error { return nil }
+
+func (c *pingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+var testPingDriver = &pingDriver{}
+
+func init() {
+	sql.Register("handler-health-test", testPingDriver)
+}
+
+func TestHealthHandler_AddDatabaseChecker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("handler-health-test", "")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(5)
+
+	// Hold a connection so the pool has one in use
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	healthHandler := NewHealthHandler()
+	healthHandler.AddDatabaseChecker("database", db, time.Second)
+	router := gin.New()
+	healthHandler.RegisterHealthRoutes(router)
+
+	readiness := func() (int, DependencyStatus) {
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Details map[string]DependencyStatus `json:"details"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return w.Code, response.Details["database"]
+	}
+
+	code, status := readiness()
+	if code != http.StatusOK || status.Status != "up" {
+		t.Fatalf("Expected the database up, got %d %+v", code, status)
+	}
+	if status.Pool == nil {
+		t.Fatal("Expected pool stats")
+	}
+	if status.Pool.MaxOpenConnections != 5 || status.Pool.OpenConnections != 2 || status.Pool.InUse != 1 || status.Pool.Idle != 1 {
+		t.Errorf("Expected 2 of 5 connections open with 1 in use, got %+v", status.Pool)
+	}
+
+	// The stats are reported when the ping fails too
+	testPingDriver.down.Store(true)
+	defer testPingDriver.down.Store(false)
+	code, status = readiness()
+	if code != http.StatusServiceUnavailable || status.Status != "down" || status.Message != "connection refused" {
+		t.Errorf("Expected the database down, got %d %+v", code, status)
+	}
+	if status.Pool == nil || status.Pool.MaxOpenConnections != 5 {
+		t.Errorf("Expected pool stats while down, got %+v", status.Pool)
+	}
+}
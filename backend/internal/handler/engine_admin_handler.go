@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// EngineAdminHandler handles administrative requests against the value-bet
+// and alert engines.
+type EngineAdminHandler struct {
+	dryRunService service.EngineDryRunService
+}
+
+// NewEngineAdminHandler creates a new EngineAdminHandler instance.
+func NewEngineAdminHandler(dryRunService service.EngineDryRunService) *EngineAdminHandler {
+	return &EngineAdminHandler{dryRunService: dryRunService}
+}
+
+// EngineDryRunRequest represents a request to replay an engine over a date range.
+type EngineDryRunRequest struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
+
+// DryRunValueBets handles POST /api/v1/admin/engines/value-bets/dry-run.
+// @Summary Dry-run the value-bet engine
+// @Description Replay the value-bet engine over matches starting in a date range and return the value bets it would find, without saving them or notifying users
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body EngineDryRunRequest true "Date range (RFC3339, or YYYY-MM-DD with to including that day)"
+// @Success 200 {object} service.ValueBetDryRun
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/engines/value-bets/dry-run [post]
+func (h *EngineAdminHandler) DryRunValueBets(c *gin.Context) {
+	from, to, err := bindDryRunRange(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.dryRunService.DryRunValueBets(c.Request.Context(), from, to)
+	if err != nil {
+		respondDryRunError(c, err, "failed to dry-run value bets")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DryRunAlerts handles POST /api/v1/admin/engines/alerts/dry-run.
+// @Summary Dry-run the alert engine
+// @Description Replay active alerts against market data in a date range and return the triggers they would fire, without notifying users or updating the alerts
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body EngineDryRunRequest true "Date range (RFC3339, or YYYY-MM-DD with to including that day)"
+// @Success 200 {object} service.AlertDryRun
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/engines/alerts/dry-run [post]
+func (h *EngineAdminHandler) DryRunAlerts(c *gin.Context) {
+	from, to, err := bindDryRunRange(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.dryRunService.DryRunAlerts(c.Request.Context(), from, to)
+	if err != nil {
+		respondDryRunError(c, err, "failed to dry-run alerts")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// bindDryRunRange parses the request's date range. A date-only to includes
+// that whole day.
+func bindDryRunRange(c *gin.Context) (time.Time, time.Time, error) {
+	var req EngineDryRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	from, _, err := parseHistoryTime(req.From)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid from: use RFC3339 or YYYY-MM-DD")
+	}
+	to, dateOnly, err := parseHistoryTime(req.To)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid to: use RFC3339 or YYYY-MM-DD")
+	}
+	if dateOnly {
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
+
+// respondDryRunError maps dry-run service errors to responses.
+func respondDryRunError(c *gin.Context, err error, message string) {
+	switch err {
+	case service.ErrInvalidDateRange, service.ErrDryRunWindowTooLong:
+		respondError(c, http.StatusBadRequest, err)
+	case service.ErrEngineUnavailable:
+		respondError(c, http.StatusServiceUnavailable, err)
+	default:
+		respondErrorMessage(c, http.StatusInternalServerError, message)
+	}
+}
+
+// RegisterEngineAdminRoutes registers engine dry-run routes behind auth and admin checks.
+func (h *EngineAdminHandler) RegisterEngineAdminRoutes(rg *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	engines := rg.Group("/admin/engines")
+	engines.Use(authMiddleware, adminMiddleware)
+	{
+		engines.POST("/value-bets/dry-run", h.DryRunValueBets)
+		engines.POST("/alerts/dry-run", h.DryRunAlerts)
+	}
+}
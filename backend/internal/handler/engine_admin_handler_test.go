@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// mockEngineDryRunService is a mock implementation of EngineDryRunService.
+type mockEngineDryRunService struct {
+	from, to time.Time
+	err      error
+}
+
+func (m *mockEngineDryRunService) DryRunValueBets(ctx context.Context, from, to time.Time) (*service.ValueBetDryRun, error) {
+	m.from, m.to = from, to
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &service.ValueBetDryRun{From: from, To: to}, nil
+}
+
+func (m *mockEngineDryRunService) DryRunAlerts(ctx context.Context, from, to time.Time) (*service.AlertDryRun, error) {
+	m.from, m.to = from, to
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &service.AlertDryRun{
+		From:     from,
+		To:       to,
+		Count:    1,
+		Triggers: []service.AlertTrigger{{AlertID: uuid.New(), Symbol: "AAPL", Value: 201.5, TriggeredAt: from}},
+	}, nil
+}
+
+func TestEngineAdminHandler_DryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	passThrough := func(c *gin.Context) { c.Next() }
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		svcErr     error
+		wantStatus int
+		wantCode   string
+	}{
+		{"value bets", "/value-bets/dry-run", `{"from":"2026-01-01","to":"2026-01-31"}`, nil, http.StatusOK, ""},
+		{"alerts", "/alerts/dry-run", `{"from":"2026-01-01T00:00:00Z","to":"2026-01-02T00:00:00Z"}`, nil, http.StatusOK, ""},
+		{"missing to", "/alerts/dry-run", `{"from":"2026-01-01"}`, nil, http.StatusBadRequest, CodeInvalidRequest},
+		{"invalid from", "/alerts/dry-run", `{"from":"January","to":"2026-01-31"}`, nil, http.StatusBadRequest, CodeInvalidRequest},
+		{"range too long", "/value-bets/dry-run", `{"from":"2025-01-01","to":"2026-01-31"}`, service.ErrDryRunWindowTooLong, http.StatusBadRequest, CodeInvalidRequest},
+		{"engine unavailable", "/alerts/dry-run", `{"from":"2026-01-01","to":"2026-01-31"}`, service.ErrEngineUnavailable, http.StatusServiceUnavailable, CodeServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			svc := &mockEngineDryRunService{err: tt.svcErr}
+			NewEngineAdminHandler(svc).RegisterEngineAdminRoutes(router.Group("/api/v1"), passThrough, passThrough)
+
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/engines"+tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("Failed to parse response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, resp.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestEngineAdminHandler_DryRunIncludesLastDay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	passThrough := func(c *gin.Context) { c.Next() }
+	router := gin.New()
+	svc := &mockEngineDryRunService{}
+	NewEngineAdminHandler(svc).RegisterEngineAdminRoutes(router.Group("/api/v1"), passThrough, passThrough)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/engines/alerts/dry-run", bytes.NewBufferString(`{"from":"2026-01-01","to":"2026-01-31"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if want := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC); !svc.to.Equal(want) {
+		t.Errorf("Expected to %v, got %v", want, svc.to)
+	}
+
+	var resp service.AlertDryRun
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Count != 1 || len(resp.Triggers) != 1 || resp.Triggers[0].Symbol != "AAPL" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+}
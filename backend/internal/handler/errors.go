@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes returned in ErrorResponse.Code. Codes are stable, so clients
+// can switch on them instead of matching error messages.
+const (
+	// Generic codes, chosen from the HTTP status when no specific code applies.
+	CodeInvalidRequest     = "invalid_request"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodeRateLimited        = "rate_limited"
+	CodeInternal           = "internal_error"
+	CodeUpstreamError      = "upstream_error"
+	CodeServiceUnavailable = "service_unavailable"
+
+	// Auth codes.
+	CodeInvalidCredentials   = "invalid_credentials"
+	CodeInvalidToken         = "invalid_token"
+	CodeAlreadyExists        = "already_exists"
+	Code2FARequired          = "2fa_required"
+	Code2FAAlreadyEnabled    = "2fa_already_enabled"
+	Code2FANotEnabled        = "2fa_not_enabled"
+	CodeInvalid2FACode       = "invalid_2fa_code"
+	CodeInvalidBackupCode    = "invalid_backup_code"
+	CodeReauthRequired       = "reauth_required"
+	CodePasswordNotSet       = "password_not_set"
+	CodeSamePassword         = "same_password"
+	CodeEmailNotVerified     = "email_not_verified"
+	CodeEmailAlreadyVerified = "email_already_verified"
+	CodeAccountLocked        = "account_locked"
+	CodeInvalidOAuthState    = "invalid_oauth_state"
+
+	// Paper trading codes.
+	CodeInsufficientFunds    = "insufficient_funds"
+	CodeInsufficientPosition = "insufficient_position"
+	CodeInvalidOrderStatus   = "invalid_order_status"
+	CodeOrderNotPending      = "order_not_pending"
+
+	// Valuation codes.
+	CodeInsufficientFundamentals = "insufficient_fundamentals"
+
+	// Technical analysis codes.
+	CodeUnknownIndicator         = "unknown_indicator"
+	CodeInsufficientPriceHistory = "insufficient_price_history"
+
+	// Bulk operation codes.
+	CodeTooManyItems = "too_many_items"
+
+	// Betting codes.
+	CodeStakeAboveLimit   = "stake_above_limit"
+	CodeDailyBetLimit     = "daily_bet_limit"
+	CodeBetAlreadySettled = "bet_already_settled"
+
+	// Alert codes.
+	CodeInvalidAlertType          = "invalid_alert_type"
+	CodeInvalidAlertCondition     = "invalid_alert_condition"
+	CodeUnsupportedAlertCondition = "unsupported_alert_condition"
+	CodeInvalidAlertTarget        = "invalid_alert_target"
+
+	// Idempotency codes.
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+)
+
+// serviceErrorCodes maps service error sentinels to their codes. Errors are
+// matched with errors.Is, so wrapped sentinels get the same code.
+var serviceErrorCodes = []struct {
+	err  error
+	code string
+}{
+	{service.ErrInvalidCredentials, CodeInvalidCredentials},
+	{service.ErrInvalidToken, CodeInvalidToken},
+	{service.ErrRefreshTokenNotFound, CodeInvalidToken},
+	{service.ErrUserAlreadyExists, CodeAlreadyExists},
+	{service.ErrOAuthAccountAlreadyLinked, CodeAlreadyExists},
+	{service.ErrFavoriteExists, CodeAlreadyExists},
+	{service.Err2FARequired, Code2FARequired},
+	{service.Err2FAAlreadyEnabled, Code2FAAlreadyEnabled},
+	{service.Err2FANotEnabled, Code2FANotEnabled},
+	{service.Err2FAInvalidCode, CodeInvalid2FACode},
+	{service.ErrInvalidBackupCode, CodeInvalidBackupCode},
+	{service.ErrReauthRequired, CodeReauthRequired},
+	{service.ErrPasswordNotSet, CodePasswordNotSet},
+	{service.ErrSamePassword, CodeSamePassword},
+	{service.ErrEmailNotVerified, CodeEmailNotVerified},
+	{service.ErrEmailAlreadyVerified, CodeEmailAlreadyVerified},
+	{service.ErrInvalidVerificationToken, CodeInvalidToken},
+	{service.ErrInvalidResetToken, CodeInvalidToken},
+	{service.ErrAccountLocked, CodeAccountLocked},
+	{service.ErrInvalidOAuthState, CodeInvalidOAuthState},
+	{service.ErrOAuthEmailNotVerified, CodeEmailNotVerified},
+	{service.ErrOAuthExchangeFailed, CodeUpstreamError},
+	{service.ErrOAuthProviderNotConfigured, CodeServiceUnavailable},
+	{service.ErrInsufficientFunds, CodeInsufficientFunds},
+	{service.ErrInsufficientPosition, CodeInsufficientPosition},
+	{service.ErrInvalidOrderStatus, CodeInvalidOrderStatus},
+	{service.ErrOrderNotPending, CodeOrderNotPending},
+	{service.ErrSessionNotFound, CodeNotFound},
+	{service.ErrSessionForbidden, CodeForbidden},
+	{service.ErrOAuthAccountNotFound, CodeNotFound},
+	{service.ErrPortfolioNotFound, CodeNotFound},
+	{service.ErrPositionNotFound, CodeNotFound},
+	{service.ErrOrderNotFound, CodeNotFound},
+	{service.ErrPresetNotFound, CodeNotFound},
+	{service.ErrFavoriteNotFound, CodeNotFound},
+	{service.ErrStockNotFound, CodeNotFound},
+	{service.ErrExportJobNotFound, CodeNotFound},
+	{service.ErrWatchlistNotFound, CodeNotFound},
+	{service.ErrWatchlistForbidden, CodeForbidden},
+	{service.ErrWatchlistItemNotFound, CodeNotFound},
+	{service.ErrWatchlistItemExists, CodeConflict},
+	{service.ErrNotificationNotFound, CodeNotFound},
+	{service.ErrBacktestNotFound, CodeNotFound},
+	{service.ErrMatchNotFound, CodeNotFound},
+	{service.ErrBetNotFound, CodeNotFound},
+	{service.ErrStakeAboveLimit, CodeStakeAboveLimit},
+	{service.ErrDailyBetLimit, CodeDailyBetLimit},
+	{service.ErrBetAlreadySettled, CodeBetAlreadySettled},
+	{service.ErrTooManyBulkItems, CodeTooManyItems},
+	{service.ErrTooManyQuoteSymbols, CodeTooManyItems},
+	{model.ErrInvalidAlertType, CodeInvalidAlertType},
+	{model.ErrInvalidAlertCondition, CodeInvalidAlertCondition},
+	{model.ErrUnsupportedAlertPairing, CodeUnsupportedAlertCondition},
+	{model.ErrInvalidAlertTarget, CodeInvalidAlertTarget},
+	{service.ErrAlertNotFound, CodeNotFound},
+	{service.ErrExportInProgress, CodeConflict},
+	{service.ErrValuationUnavailable, CodeServiceUnavailable},
+	{service.ErrInsufficientFundamentals, CodeInsufficientFundamentals},
+	{service.ErrUnknownIndicator, CodeUnknownIndicator},
+	{service.ErrInsufficientPriceHistory, CodeInsufficientPriceHistory},
+	{service.ErrScreenerUnavailable, CodeServiceUnavailable},
+	{service.ErrQuotesUnavailable, CodeServiceUnavailable},
+	{service.ErrEngineUnavailable, CodeServiceUnavailable},
+	{service.ErrBacktestsUnavailable, CodeServiceUnavailable},
+	{service.ErrTokenStoreUnavailable, CodeServiceUnavailable},
+	{service.ErrHistorySyncUnavailable, CodeServiceUnavailable},
+	{ErrIdempotencyKeyReused, CodeIdempotencyKeyReused},
+}
+
+// statusErrorCodes are the codes used for errors without a specific code.
+var statusErrorCodes = map[int]string{
+	http.StatusBadRequest:           CodeInvalidRequest,
+	http.StatusUnauthorized:         CodeUnauthorized,
+	http.StatusForbidden:            CodeForbidden,
+	http.StatusNotFound:             CodeNotFound,
+	http.StatusConflict:             CodeConflict,
+	http.StatusUnprocessableEntity:  CodeInvalidRequest,
+	http.StatusPreconditionRequired: CodeReauthRequired,
+	http.StatusTooManyRequests:      CodeRateLimited,
+	http.StatusInternalServerError:  CodeInternal,
+	http.StatusBadGateway:           CodeUpstreamError,
+	http.StatusServiceUnavailable:   CodeServiceUnavailable,
+}
+
+// errorCode returns the code for err, or the generic code for status when err
+// is not a known service error.
+func errorCode(err error, status int) string {
+	if err != nil {
+		for _, known := range serviceErrorCodes {
+			if errors.Is(err, known.err) {
+				return known.code
+			}
+		}
+	}
+	return statusCode(status)
+}
+
+// statusCode returns the generic code for an HTTP status.
+func statusCode(status int) string {
+	if code, ok := statusErrorCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeInvalidRequest
+}
+
+// respondError writes an ErrorResponse with err's message and code.
+func respondError(c *gin.Context, status int, err error) {
+	c.JSON(status, ErrorResponse{Error: err.Error(), Code: errorCode(err, status)})
+}
+
+// respondErrorMessage writes an ErrorResponse with a fixed message and the
+// generic code for status.
+func respondErrorMessage(c *gin.Context, status int, message string) {
+	c.JSON(status, ErrorResponse{Error: message, Code: statusCode(status)})
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		want   string
+	}{
+		{"service sentinel", service.ErrInvalidCredentials, http.StatusUnauthorized, CodeInvalidCredentials},
+		{"2FA required", service.Err2FARequired, http.StatusPreconditionRequired, Code2FARequired},
+		{"wrapped sentinel", fmt.Errorf("place order: %w", service.ErrInsufficientFunds), http.StatusBadRequest, CodeInsufficientFunds},
+		{"unknown error uses status", errors.New("boom"), http.StatusInternalServerError, CodeInternal},
+		{"validation error", errors.New("Key: 'LoginRequest.Email' Error:Field validation for 'Email' failed on the 'required' tag"), http.StatusBadRequest, CodeInvalidRequest},
+		{"unmapped 5xx status", errors.New("timeout"), http.StatusGatewayTimeout, CodeInternal},
+		{"nil error", nil, http.StatusNotFound, CodeNotFound},
+		{"alert validation error", (&model.Alert{Type: model.AlertTypeMatchStart, Condition: model.AlertConditionPercentUp}).Validate(), http.StatusBadRequest, CodeUnsupportedAlertCondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(tt.err, tt.status); got != tt.want {
+				t.Errorf("Expected code %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
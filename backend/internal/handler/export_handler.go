@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ExportHandler handles per-user data export HTTP requests.
+type ExportHandler struct {
+	service service.DataExportService
+	audit   *AuditRecorder
+}
+
+// NewExportHandler creates a new ExportHandler instance.
+func NewExportHandler(svc service.DataExportService) *ExportHandler {
+	return &ExportHandler{service: svc}
+}
+
+// SetAuditRecorder enables audit logging of data exports.
+func (h *ExportHandler) SetAuditRecorder(audit *AuditRecorder) {
+	h.audit = audit
+}
+
+// ExportJobResponse represents a background export in API responses.
+type ExportJobResponse struct {
+	ID          string  `json:"id"`
+	Status      string  `json:"status"`
+	Error       string  `json:"error,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	CompletedAt *string `json:"completed_at,omitempty"`
+	DownloadURL string  `json:"download_url"`
+}
+
+// ExportData handles GET /api/v1/auth/export.
+// @Summary Export my data
+// @Description Download everything stored about the current user as JSON. With async=true the export is generated in the background and the user is notified when it can be downloaded.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param async query bool false "Generate the export in the background"
+// @Success 200 {object} service.DataExport
+// @Success 202 {object} ExportJobResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/export [get]
+func (h *ExportHandler) ExportData(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if c.Query("async") == "true" {
+		job, err := h.service.StartExport(userID)
+		if err != nil {
+			if err == service.ErrExportInProgress {
+				respondError(c, http.StatusConflict, err)
+				return
+			}
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to start export")
+			return
+		}
+
+		h.audit.Record(c, model.AuditActionDataExport, map[string]interface{}{"job_id": job.ID.String()})
+		c.JSON(http.StatusAccepted, exportJobToResponse(job))
+		return
+	}
+
+	export, err := h.service.Export(c.Request.Context(), userID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to export data")
+		return
+	}
+
+	h.audit.Record(c, model.AuditActionDataExport, nil)
+	sendExport(c, export)
+}
+
+// GetExportJob handles GET /api/v1/auth/export/jobs/:id.
+// @Summary Get a background data export
+// @Description Download a finished background export, or get its status while it is still running
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Export job ID"
+// @Success 200 {object} service.DataExport
+// @Success 202 {object} ExportJobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/auth/export/jobs/{id} [get]
+func (h *ExportHandler) GetExportJob(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid job ID")
+		return
+	}
+
+	job, err := h.service.GetJob(userID, jobID)
+	if err != nil {
+		if err == service.ErrExportJobNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get export job")
+		return
+	}
+
+	switch job.Status {
+	case service.ExportJobReady:
+		sendExport(c, job.Export)
+	case service.ExportJobPending:
+		c.JSON(http.StatusAccepted, exportJobToResponse(job))
+	default:
+		c.JSON(http.StatusOK, exportJobToResponse(job))
+	}
+}
+
+// RegisterExportRoutes registers data export routes on the auth group.
+func (h *ExportHandler) RegisterExportRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	export := rg.Group("/auth/export")
+	export.Use(authMiddleware)
+	{
+		export.GET("", h.ExportData)
+		export.GET("/jobs/:id", h.GetExportJob)
+	}
+}
+
+// sendExport writes an export as a JSON file download.
+func sendExport(c *gin.Context, export *service.DataExport) {
+	filename := fmt.Sprintf("super-dashboard-export-%s.json", export.GeneratedAt.Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.JSON(http.StatusOK, export)
+}
+
+// exportJobToResponse converts a service.ExportJob to ExportJobResponse.
+func exportJobToResponse(job *service.ExportJob) ExportJobResponse {
+	resp := ExportJobResponse{
+		ID:          job.ID.String(),
+		Status:      string(job.Status),
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt.Format(time.RFC3339),
+		DownloadURL: "/api/v1/auth/export/jobs/" + job.ID.String(),
+	}
+	if job.CompletedAt != nil {
+		completedAt := job.CompletedAt.Format(time.RFC3339)
+		resp.CompletedAt = &completedAt
+	}
+	return resp
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// mockDataExportService is a mock implementation of DataExportService.
+type mockDataExportService struct {
+	jobs map[uuid.UUID]*service.ExportJob
+}
+
+func newMockDataExportService() *mockDataExportService {
+	return &mockDataExportService{jobs: make(map[uuid.UUID]*service.ExportJob)}
+}
+
+func (m *mockDataExportService) AddSource(name string, source service.ExportSource) {}
+
+func (m *mockDataExportService) Export(ctx context.Context, userID uuid.UUID) (*service.DataExport, error) {
+	return &service.DataExport{
+		UserID:      userID,
+		GeneratedAt: time.Date(2024, 12, 2, 0, 0, 0, 0, time.UTC),
+		Sections:    map[string]interface{}{"profile": map[string]string{"email": "test@example.com"}},
+	}, nil
+}
+
+func (m *mockDataExportService) StartExport(userID uuid.UUID) (*service.ExportJob, error) {
+	for _, job := range m.jobs {
+		if job.UserID == userID && job.Status == service.ExportJobPending {
+			return nil, service.ErrExportInProgress
+		}
+	}
+	job := &service.ExportJob{ID: uuid.New(), UserID: userID, Status: service.ExportJobPending, CreatedAt: time.Now()}
+	m.jobs[job.ID] = job
+	return job, nil
+}
+
+func (m *mockDataExportService) GetJob(userID, jobID uuid.UUID) (*service.ExportJob, error) {
+	job, ok := m.jobs[jobID]
+	if !ok || job.UserID != userID {
+		return nil, service.ErrExportJobNotFound
+	}
+	return job, nil
+}
+
+func setupExportRouter(svc service.DataExportService, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		c.Next()
+	}
+	NewExportHandler(svc).RegisterExportRoutes(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestExportHandler_ExportData(t *testing.T) {
+	userID := uuid.New()
+	router := setupExportRouter(newMockDataExportService(), userID)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/auth/export", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="super-dashboard-export-20241202.json"` {
+		t.Errorf("Unexpected Content-Disposition %q", got)
+	}
+
+	var export service.DataExport
+	if err := json.Unmarshal(w.Body.Bytes(), &export); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if export.UserID != userID {
+		t.Errorf("Expected user %s, got %s", userID, export.UserID)
+	}
+}
+
+func TestExportHandler_AsyncExport(t *testing.T) {
+	userID := uuid.New()
+	svc := newMockDataExportService()
+	router := setupExportRouter(svc, userID)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/auth/export?async=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+
+	var job ExportJobResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if job.Status != string(service.ExportJobPending) || job.DownloadURL != "/api/v1/auth/export/jobs/"+job.ID {
+		t.Errorf("Unexpected job response %+v", job)
+	}
+
+	// A second export is rejected while the first is running
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		prepare    func()
+		wantStatus int
+	}{
+		{"pending job", "/api/v1/auth/export/jobs/" + job.ID, nil, http.StatusAccepted},
+		{"ready job", "/api/v1/auth/export/jobs/" + job.ID, func() {
+			stored := svc.jobs[uuid.MustParse(job.ID)]
+			stored.Status = service.ExportJobReady
+			stored.Export, _ = svc.Export(context.Background(), userID)
+		}, http.StatusOK},
+		{"unknown job", "/api/v1/auth/export/jobs/" + uuid.New().String(), nil, http.StatusNotFound},
+		{"invalid job ID", "/api/v1/auth/export/jobs/not-a-uuid", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// FavoriteHandler handles favorites HTTP requests.
+type FavoriteHandler struct {
+	service service.FavoriteService
+}
+
+// NewFavoriteHandler creates a new FavoriteHandler instance.
+func NewFavoriteHandler(svc service.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{service: svc}
+}
+
+// AddFavoriteRequest represents a request to pin an entity.
+type AddFavoriteRequest struct {
+	EntityType string `json:"entity_type" binding:"required"`
+	EntityID   string `json:"entity_id" binding:"required"`
+}
+
+// FavoriteResponse represents a favorite in API responses.
+type FavoriteResponse struct {
+	ID         string `json:"id"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// AddFavorite handles POST /api/v1/favorites.
+// @Summary Add a favorite
+// @Description Pin a stock, match, or portfolio for quick access
+// @Tags favorites
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AddFavoriteRequest true "Entity to pin"
+// @Success 201 {object} FavoriteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/favorites [post]
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req AddFavoriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	favorite, err := h.service.AddFavorite(userID, model.FavoriteEntityType(req.EntityType), req.EntityID)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidEntityType, service.ErrInvalidEntityID:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrFavoriteExists:
+			respondError(c, http.StatusConflict, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to add favorite")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, favoriteToResponse(favorite))
+}
+
+// ListFavorites handles GET /api/v1/favorites.
+// @Summary List favorites
+// @Description List the current user's favorites, optionally filtered by entity type
+// @Tags favorites
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Entity type (stock, match, portfolio)"
+// @Success 200 {array} FavoriteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/favorites [get]
+func (h *FavoriteHandler) ListFavorites(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	favorites, err := h.service.ListFavorites(userID, model.FavoriteEntityType(c.Query("type")))
+	if err != nil {
+		if err == service.ErrInvalidEntityType {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to list favorites")
+		return
+	}
+
+	response := make([]FavoriteResponse, len(favorites))
+	for i := range favorites {
+		response[i] = favoriteToResponse(&favorites[i])
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RemoveFavorite handles DELETE /api/v1/favorites/:type/:entity_id.
+// @Summary Remove a favorite
+// @Description Unpin a stock, match, or portfolio
+// @Tags favorites
+// @Security BearerAuth
+// @Param type path string true "Entity type (stock, match, portfolio)"
+// @Param entity_id path string true "Stock symbol or entity ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/favorites/{type}/{entity_id} [delete]
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	err = h.service.RemoveFavorite(userID, model.FavoriteEntityType(c.Param("type")), c.Param("entity_id"))
+	if err != nil {
+		switch err {
+		case service.ErrInvalidEntityType, service.ErrInvalidEntityID:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrFavoriteNotFound:
+			respondError(c, http.StatusNotFound, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to remove favorite")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegisterFavoriteRoutes registers favorites routes.
+func (h *FavoriteHandler) RegisterFavoriteRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	favorites := rg.Group("/favorites")
+	favorites.Use(authMiddleware)
+	{
+		favorites.POST("", h.AddFavorite)
+		favorites.GET("", h.ListFavorites)
+		favorites.DELETE("/:type/:entity_id", h.RemoveFavorite)
+	}
+}
+
+// favoriteToResponse converts a model.Favorite to FavoriteResponse.
+func favoriteToResponse(favorite *model.Favorite) FavoriteResponse {
+	return FavoriteResponse{
+		ID:         favorite.ID.String(),
+		EntityType: string(favorite.EntityType),
+		EntityID:   favorite.EntityID,
+		CreatedAt:  favorite.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -36,13 +36,13 @@ func (h *GoalHandler) CreateGoal(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	targetDate, err := time.Parse("2006-01-02", req.TargetDate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target date format"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid target date format")
 		return
 	}
 
@@ -59,7 +59,7 @@ func (h *GoalHandler) CreateGoal(c *gin.Context) {
 	}
 
 	if err := h.goalRepo.CreateGoal(c.Request.Context(), goal); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -72,7 +72,7 @@ func (h *GoalHandler) GetGoals(c *gin.Context) {
 
 	goals, err := h.goalRepo.GetUserGoals(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -85,7 +85,7 @@ func (h *GoalHandler) GetActiveGoals(c *gin.Context) {
 
 	goals, err := h.goalRepo.GetActiveGoals(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -96,13 +96,13 @@ func (h *GoalHandler) GetActiveGoals(c *gin.Context) {
 func (h *GoalHandler) GetGoalByID(c *gin.Context) {
 	goalID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal ID"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid goal ID")
 		return
 	}
 
 	goal, err := h.goalRepo.GetGoalByID(c.Request.Context(), goalID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+		respondErrorMessage(c, http.StatusNotFound, "goal not found")
 		return
 	}
 
@@ -113,13 +113,13 @@ func (h *GoalHandler) GetGoalByID(c *gin.Context) {
 func (h *GoalHandler) UpdateGoal(c *gin.Context) {
 	goalID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal ID"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid goal ID")
 		return
 	}
 
 	goal, err := h.goalRepo.GetGoalByID(c.Request.Context(), goalID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+		respondErrorMessage(c, http.StatusNotFound, "goal not found")
 		return
 	}
 
@@ -130,7 +130,7 @@ func (h *GoalHandler) UpdateGoal(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -142,7 +142,7 @@ func (h *GoalHandler) UpdateGoal(c *gin.Context) {
 	}
 	if req.CurrentValue != nil {
 		if err := h.goalRepo.UpdateGoalProgress(c.Request.Context(), goalID, *req.CurrentValue); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err)
 			return
 		}
 		// Reload goal
@@ -152,7 +152,7 @@ func (h *GoalHandler) UpdateGoal(c *gin.Context) {
 	}
 
 	if err := h.goalRepo.UpdateGoal(c.Request.Context(), goal); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -163,12 +163,12 @@ func (h *GoalHandler) UpdateGoal(c *gin.Context) {
 func (h *GoalHandler) MarkGoalAchieved(c *gin.Context) {
 	goalID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal ID"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid goal ID")
 		return
 	}
 
 	if err := h.goalRepo.MarkGoalAsAchieved(c.Request.Context(), goalID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -179,12 +179,12 @@ func (h *GoalHandler) MarkGoalAchieved(c *gin.Context) {
 func (h *GoalHandler) DeleteGoal(c *gin.Context) {
 	goalID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal ID"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid goal ID")
 		return
 	}
 
 	if err := h.goalRepo.DeleteGoal(c.Request.Context(), goalID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -197,7 +197,7 @@ func (h *GoalHandler) GetGoalStatistics(c *gin.Context) {
 
 	stats, err := h.goalRepo.GetGoalStatistics(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -3,8 +3,9 @@ package handler
 import (
 	"net/http"
 
-	"github.com/gin-gonic/gin"
 	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // AuthHandler handles authentication-related HTTP requests.
@@ -53,9 +54,11 @@ type RefreshResponse struct {
 	AccessToken string `json:"access_token"`
 }
 
-// ErrorResponse represents an error response.
+// ErrorResponse represents an error response. Error is a human-readable
+// message; Code is a stable machine-readable code (see errors.go).
 type ErrorResponse struct {
 	Error string `json:"error"`
+	Code  string `json:"code"`
 }
 
 // Register handles user registration.
@@ -72,17 +75,17 @@ type ErrorResponse struct {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	user, err := h.authService.Register(req.Email, req.Password, req.Name)
 	if err != nil {
 		if err == service.ErrUserAlreadyExists {
-			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusConflict, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to register user"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to register user")
 		return
 	}
 
@@ -107,17 +110,17 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	accessToken, refreshToken, err := h.authService.Login(req.Email, req.Password)
 	if err != nil {
 		if err == service.ErrInvalidCredentials {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusUnauthorized, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to login"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to login")
 		return
 	}
 
@@ -141,13 +144,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 func (h *AuthHandler) Refresh(c *gin.Context) {
 	var req RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	accessToken, err := h.authService.RefreshToken(req.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid refresh token"})
+		respondErrorMessage(c, http.StatusUnauthorized, "invalid refresh token")
 		return
 	}
 
@@ -165,3 +168,18 @@ func (h *AuthHandler) RegisterAuthRoutes(rg *gin.RouterGroup) {
 		auth.POST("/refresh", h.Refresh)
 	}
 }
+
+// currentUserID extracts the authenticated user's ID set by the auth middleware.
+func currentUserID(c *gin.Context) (uuid.UUID, error) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		return uuid.Nil, service.ErrInvalidToken
+	}
+
+	userIDStr, ok := userIDVal.(string)
+	if !ok {
+		return uuid.Nil, service.ErrInvalidToken
+	}
+
+	return uuid.Parse(userIDStr)
+}
@@ -3,20 +3,38 @@ package handler
 import (
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // HealthHandler handles health check endpoints.
 type HealthHandler struct {
-	mu       sync.RWMutex
-	ready    bool
-	checkers []HealthChecker
+	mu        sync.RWMutex
+	ready     bool
+	checkers  []dependencyCheck
+	providers []dependencyCheck
 }
 
 // HealthChecker is a function that checks a dependency's health.
 type HealthChecker func() (name string, healthy bool, message string)
 
+// dependencyCheck checks a dependency's health and reports its status.
+// runCheck fills in the latency.
+type dependencyCheck func() (name string, healthy bool, status DependencyStatus)
+
+// statusCheck adapts a HealthChecker.
+func statusCheck(checker HealthChecker) dependencyCheck {
+	return func() (string, bool, DependencyStatus) {
+		name, healthy, message := checker()
+		status := DependencyStatus{Status: "up", Message: message}
+		if !healthy {
+			status.Status = "down"
+		}
+		return name, healthy, status
+	}
+}
+
 // HealthResponse represents a health check response.
 type HealthResponse struct {
 	Status  string                 `json:"status"`
@@ -27,13 +45,25 @@ type HealthResponse struct {
 type DependencyStatus struct {
 	Status  string `json:"status"`
 	Message string `json:"message,omitempty"`
+	// LatencyMS is how long the check took, in milliseconds.
+	LatencyMS float64 `json:"latency_ms"`
+	// Pool holds connection pool statistics, for database checks.
+	Pool *PoolStats `json:"pool,omitempty"`
+}
+
+// runCheck runs a check and times it.
+func runCheck(check dependencyCheck) (string, bool, DependencyStatus) {
+	start := time.Now()
+	name, healthy, status := check()
+	status.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	return name, healthy, status
 }
 
 // NewHealthHandler creates a new HealthHandler instance.
 func NewHealthHandler() *HealthHandler {
 	return &HealthHandler{
 		ready:    true,
-		checkers: make([]HealthChecker, 0),
+		checkers: make([]dependencyCheck, 0),
 	}
 }
 
@@ -41,7 +71,16 @@ func NewHealthHandler() *HealthHandler {
 func (h *HealthHandler) AddHealthChecker(checker HealthChecker) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.checkers = append(h.checkers, checker)
+	h.checkers = append(h.checkers, statusCheck(checker))
+}
+
+// AddProviderChecker adds a health checker for an external data provider.
+// Provider status is reported by /health but does not affect readiness,
+// since the service keeps running when an upstream is unavailable.
+func (h *HealthHandler) AddProviderChecker(checker HealthChecker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.providers = append(h.providers, statusCheck(checker))
 }
 
 // SetReady sets the readiness state.
@@ -53,24 +92,47 @@ func (h *HealthHandler) SetReady(ready bool) {
 
 // Health returns basic health status.
 // @Summary Basic health check
-// @Description Returns basic health status of the service
+// @Description Returns basic health status of the service and of any configured external data providers
 // @Tags health
 // @Produce json
 // @Success 200 {object} HealthResponse
 // @Router /health [get]
 func (h *HealthHandler) Health(c *gin.Context) {
+	h.mu.RLock()
+	providers := h.providers
+	h.mu.RUnlock()
+
+	if len(providers) == 0 {
+		c.JSON(http.StatusOK, HealthResponse{
+			Status: "ok",
+		})
+		return
+	}
+
+	status := "ok"
+	statuses := make(map[string]DependencyStatus, len(providers))
+	for _, checker := range providers {
+		name, healthy, providerStatus := runCheck(checker)
+		if !healthy {
+			status = "degraded"
+		}
+		statuses[name] = providerStatus
+	}
+
 	c.JSON(http.StatusOK, HealthResponse{
-		Status: "ok",
+		Status:  status,
+		Details: map[string]interface{}{"providers": statuses},
 	})
 }
 
 // Ready checks if the service is ready to accept traffic.
 // @Summary Readiness check
-// @Description Checks if the service and all dependencies are ready
+// @Description Checks if the service and all dependencies are ready, reporting each dependency's status and check latency (used by Kubernetes readiness probe)
 // @Tags health
 // @Produce json
 // @Success 200 {object} HealthResponse
 // @Failure 503 {object} HealthResponse
+// @Router /readyz [get]
 // @Router /health/ready [get]
 func (h *HealthHandler) Ready(c *gin.Context) {
 	h.mu.RLock()
@@ -89,16 +151,11 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 	allHealthy := true
 
 	for _, checker := range checkers {
-		name, healthy, message := checker()
-		status := "up"
+		name, healthy, status := runCheck(checker)
 		if !healthy {
-			status = "down"
 			allHealthy = false
 		}
-		details[name] = DependencyStatus{
-			Status:  status,
-			Message: message,
-		}
+		details[name] = status
 	}
 
 	if !allHealthy {
@@ -121,6 +178,7 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 // @Tags health
 // @Produce json
 // @Success 200 {object} HealthResponse
+// @Router /healthz [get]
 // @Router /health/live [get]
 func (h *HealthHandler) Live(c *gin.Context) {
 	c.JSON(http.StatusOK, HealthResponse{
@@ -128,9 +186,12 @@ func (h *HealthHandler) Live(c *gin.Context) {
 	})
 }
 
-// RegisterHealthRoutes registers health check routes.
+// RegisterHealthRoutes registers health check routes. /healthz and /readyz
+// are the Kubernetes-style names for the liveness and readiness checks.
 func (h *HealthHandler) RegisterHealthRoutes(r *gin.Engine) {
 	r.GET("/health", h.Health)
 	r.GET("/health/ready", h.Ready)
 	r.GET("/health/live", h.Live)
+	r.GET("/healthz", h.Live)
+	r.GET("/readyz", h.Ready)
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -150,6 +151,57 @@ func TestHealthHandler_Ready(t *testing.T) {
 	}
 }
 
+func TestHealthHandler_Probes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	healthHandler := NewHealthHandler()
+	healthHandler.AddHealthChecker(func() (string, bool, string) {
+		return "database", true, "connected"
+	})
+	healthHandler.AddHealthChecker(func() (string, bool, string) {
+		time.Sleep(5 * time.Millisecond)
+		return "redis", false, "connection refused"
+	})
+	router := gin.New()
+	healthHandler.RegisterHealthRoutes(router)
+
+	// A failing dependency does not make the process unhealthy
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected /healthz status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/readyz", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected /readyz status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	var response struct {
+		Status  string                      `json:"status"`
+		Details map[string]DependencyStatus `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.Status != "not_ready" {
+		t.Errorf("Expected status 'not_ready', got '%s'", response.Status)
+	}
+	if db := response.Details["database"]; db.Status != "up" || db.Message != "connected" {
+		t.Errorf("Expected database up, got %+v", db)
+	}
+	redis := response.Details["redis"]
+	if redis.Status != "down" || redis.Message != "connection refused" {
+		t.Errorf("Expected redis down, got %+v", redis)
+	}
+	if redis.LatencyMS < 5 {
+		t.Errorf("Expected redis latency of at least 5ms, got %v", redis.LatencyMS)
+	}
+}
+
 func TestHealthHandler_AddHealthChecker(t *testing.T) {
 	healthHandler := NewHealthHandler()
 
@@ -182,3 +234,99 @@ func TestHealthHandler_SetReady(t *testing.T) {
 		t.Error("Expected ready state to be true after SetReady(true)")
 	}
 }
+
+func TestHealthHandler_Health_Providers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		healthy    bool
+		wantStatus string
+	}{
+		{name: "provider up", healthy: true, wantStatus: "ok"},
+		{name: "provider down", healthy: false, wantStatus: "degraded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthHandler := NewHealthHandler()
+			healthHandler.AddProviderChecker(func() (string, bool, string) {
+				return "alphavantage", tt.healthy, ""
+			})
+			router := gin.New()
+			healthHandler.RegisterHealthRoutes(router)
+
+			req, _ := http.NewRequest(http.MethodGet, "/health", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+			}
+
+			var response HealthResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if response.Status != tt.wantStatus {
+				t.Errorf("Expected status '%s', got '%s'", tt.wantStatus, response.Status)
+			}
+			if _, ok := response.Details["providers"]; !ok {
+				t.Error("Expected providers in details")
+			}
+
+			// Providers do not affect readiness.
+			req, _ = http.NewRequest(http.MethodGet, "/health/ready", nil)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected ready status code %d, got %d", http.StatusOK, w.Code)
+			}
+		})
+	}
+}
+
+func TestCachedHealthChecker(t *testing.T) {
+	calls := 0
+	checker := CachedHealthChecker(func() (string, bool, string) {
+		calls++
+		return "provider", true, ""
+	}, time.Minute)
+
+	checker()
+	checker()
+
+	if calls != 1 {
+		t.Errorf("Expected 1 probe within the cache TTL, got %d", calls)
+	}
+}
+
+func TestNewHTTPProviderChecker(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		wantHealthy bool
+	}{
+		{name: "ok", status: http.StatusOK, wantHealthy: true},
+		{name: "rate limited", status: http.StatusTooManyRequests, wantHealthy: true},
+		{name: "bad key", status: http.StatusUnauthorized, wantHealthy: false},
+		{name: "server error", status: http.StatusBadGateway, wantHealthy: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			name, healthy, _ := NewHTTPProviderChecker("provider", server.URL, server.Client())()
+			if name != "provider" {
+				t.Errorf("Expected name 'provider', got '%s'", name)
+			}
+			if healthy != tt.wantHealthy {
+				t.Errorf("Expected healthy %v, got %v", tt.wantHealthy, healthy)
+			}
+		})
+	}
+}
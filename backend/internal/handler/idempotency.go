@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyHeader names the header clients set to make a create
+// request safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyKeyTTL is how long an Idempotency-Key is remembered.
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// maxIdempotencyKeyLength bounds the Idempotency-Key header.
+const maxIdempotencyKeyLength = 255
+
+// Idempotency errors.
+var (
+	ErrIdempotencyKeyTooLong    = fmt.Errorf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength)
+	ErrIdempotencyKeyReused     = fmt.Errorf("%s was already used with a different request", IdempotencyKeyHeader)
+	ErrIdempotencyKeyInProgress = fmt.Errorf("a request with this %s is still in progress", IdempotencyKeyHeader)
+)
+
+// IdempotencyStore remembers which resource each Idempotency-Key created.
+// *redis.Client implements it.
+type IdempotencyStore interface {
+	// ReserveIdempotencyKey claims key for a request with requestHash and
+	// reports false if the key is already claimed.
+	ReserveIdempotencyKey(ctx context.Context, key, requestHash string, ttl time.Duration) (bool, error)
+	// GetIdempotencyKey returns the request hash and resource ID stored
+	// under key. Both are empty for an unused key, and the resource ID is
+	// empty while the first request is in progress.
+	GetIdempotencyKey(ctx context.Context, key string) (requestHash, resourceID string, err error)
+	// CompleteIdempotencyKey records the resource the request created.
+	CompleteIdempotencyKey(ctx context.Context, key, requestHash, resourceID string, ttl time.Duration) error
+	// DeleteIdempotencyKey frees key after the request failed, so that a
+	// retry can succeed.
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+}
+
+// orderIdempotencyKey scopes a client's Idempotency-Key to the portfolio
+// the order is placed in.
+func orderIdempotencyKey(portfolioID uuid.UUID, key string) string {
+	return "paper_order:" + portfolioID.String() + ":" + key
+}
+
+// requestHash fingerprints a bound request body, so that formatting
+// differences in the JSON do not count as a different request.
+func requestHash(req interface{}) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/awaymess/super-dashboard/backend/pkg/jobs"
+	"github.com/gin-gonic/gin"
+)
+
+// JobScheduler reports scheduled jobs, runs them on demand and pauses
+// them. *jobs.Scheduler implements it.
+type JobScheduler interface {
+	JobStatuses() []jobs.JobStatus
+	RunJobNow(name string) error
+	PauseJob(name string) error
+	ResumeJob(name string) error
+}
+
+// JobAdminHandler serves the status of the scheduled background jobs.
+type JobAdminHandler struct {
+	scheduler JobScheduler
+}
+
+// NewJobAdminHandler creates a new JobAdminHandler instance.
+func NewJobAdminHandler(scheduler JobScheduler) *JobAdminHandler {
+	return &JobAdminHandler{scheduler: scheduler}
+}
+
+// JobStatusesResponse lists the scheduled jobs.
+type JobStatusesResponse struct {
+	Jobs []jobs.JobStatus `json:"jobs"`
+}
+
+// ListJobs handles GET /api/v1/admin/jobs.
+// @Summary List scheduled jobs
+// @Description List each scheduled job with its cron expression, next run and up to its 20 most recent runs (start time, duration, success and error), newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} JobStatusesResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/admin/jobs [get]
+func (h *JobAdminHandler) ListJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, JobStatusesResponse{Jobs: h.scheduler.JobStatuses()})
+}
+
+// RunJob handles POST /api/v1/admin/jobs/:name/run.
+// @Summary Run a scheduled job now
+// @Description Start the named job in the background without changing its schedule. The run shows up in the job list once it finishes.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Success 202 {object} map[string]string
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/admin/jobs/{name}/run [post]
+func (h *JobAdminHandler) RunJob(c *gin.Context) {
+	if err := h.scheduler.RunJobNow(c.Param("name")); err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "job started"})
+}
+
+// PauseJob handles POST /api/v1/admin/jobs/:name/pause.
+// @Summary Pause a scheduled job
+// @Description Skip the named job's scheduled runs until it is resumed. A run in progress finishes, and the job can still be run on demand.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/admin/jobs/{name}/pause [post]
+func (h *JobAdminHandler) PauseJob(c *gin.Context) {
+	if err := h.scheduler.PauseJob(c.Param("name")); err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "job paused"})
+}
+
+// ResumeJob handles POST /api/v1/admin/jobs/:name/resume.
+// @Summary Resume a paused job
+// @Description Restart the named job's scheduled runs from its next cron time.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Job name"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/admin/jobs/{name}/resume [post]
+func (h *JobAdminHandler) ResumeJob(c *gin.Context) {
+	if err := h.scheduler.ResumeJob(c.Param("name")); err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "job resumed"})
+}
+
+// respondJobError maps scheduler errors to HTTP statuses.
+func respondJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, jobs.ErrJobNotFound):
+		respondError(c, http.StatusNotFound, err)
+	case errors.Is(err, jobs.ErrJobAlreadyRunning):
+		respondError(c, http.StatusConflict, err)
+	default:
+		respondError(c, http.StatusInternalServerError, err)
+	}
+}
+
+// RegisterJobAdminRoutes registers the job status and control routes behind auth and admin checks.
+func (h *JobAdminHandler) RegisterJobAdminRoutes(rg *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	adminJobs := rg.Group("/admin/jobs")
+	adminJobs.Use(authMiddleware, adminMiddleware)
+	{
+		adminJobs.GET("", h.ListJobs)
+		adminJobs.POST("/:name/run", h.RunJob)
+		adminJobs.POST("/:name/pause", h.PauseJob)
+		adminJobs.POST("/:name/resume", h.ResumeJob)
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/middleware"
+	"github.com/awaymess/super-dashboard/backend/pkg/jobs"
+	"github.com/gin-gonic/gin"
+)
+
+// mockJobScheduler is a mock implementation of JobScheduler.
+type mockJobScheduler struct {
+	statuses []jobs.JobStatus
+	ran      string
+	runErr   error
+	paused   map[string]bool
+}
+
+func (m *mockJobScheduler) JobStatuses() []jobs.JobStatus {
+	return m.statuses
+}
+
+func (m *mockJobScheduler) RunJobNow(name string) error {
+	m.ran = name
+	return m.runErr
+}
+
+func (m *mockJobScheduler) PauseJob(name string) error {
+	return m.setPaused(name, true)
+}
+
+func (m *mockJobScheduler) ResumeJob(name string) error {
+	return m.setPaused(name, false)
+}
+
+func (m *mockJobScheduler) setPaused(name string, paused bool) error {
+	if _, ok := m.paused[name]; !ok {
+		return jobs.ErrJobNotFound
+	}
+	m.paused[name] = paused
+	return nil
+}
+
+func TestJobAdminHandler_ListJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	next := started.Add(time.Minute)
+	run := jobs.JobRun{StartedAt: started, DurationMS: 12, Success: false, Error: "boom"}
+	source := &mockJobScheduler{statuses: []jobs.JobStatus{
+		{Name: "sync", CronExpr: "* * * * *", NextRun: &next, LastRun: &run, Runs: []jobs.JobRun{run}},
+	}}
+
+	tests := []struct {
+		name       string
+		role       string
+		wantStatus int
+	}{
+		{"admin", "admin", http.StatusOK},
+		{"user", "user", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			setRole := func(c *gin.Context) {
+				c.Set("role", tt.role)
+				c.Next()
+			}
+			NewJobAdminHandler(source).RegisterJobAdminRoutes(router.Group("/api/v1"), setRole, middleware.AdminMiddleware())
+
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/jobs", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp JobStatusesResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if len(resp.Jobs) != 1 {
+				t.Fatalf("Expected 1 job, got %d", len(resp.Jobs))
+			}
+			job := resp.Jobs[0]
+			if job.Name != "sync" || job.NextRun == nil || !job.NextRun.Equal(next) {
+				t.Errorf("Expected sync next running at %v, got %+v", next, job)
+			}
+			if job.LastRun == nil || job.LastRun.Success || job.LastRun.Error != "boom" || job.LastRun.DurationMS != 12 {
+				t.Errorf("Expected the failed last run, got %+v", job.LastRun)
+			}
+		})
+	}
+}
+
+func TestJobAdminHandler_RunJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	passThrough := func(c *gin.Context) { c.Next() }
+
+	tests := []struct {
+		name       string
+		runErr     error
+		wantStatus int
+		wantCode   string
+	}{
+		{"started", nil, http.StatusAccepted, ""},
+		{"unknown job", jobs.ErrJobNotFound, http.StatusNotFound, CodeNotFound},
+		{"already running", jobs.ErrJobAlreadyRunning, http.StatusConflict, CodeConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			scheduler := &mockJobScheduler{runErr: tt.runErr}
+			NewJobAdminHandler(scheduler).RegisterJobAdminRoutes(router.Group("/api/v1"), passThrough, passThrough)
+
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/jobs/OddsSync/run", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if scheduler.ran != "OddsSync" {
+				t.Errorf("Expected OddsSync to be run, got %q", scheduler.ran)
+			}
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("Failed to parse response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, resp.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestJobAdminHandler_PauseResume(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	passThrough := func(c *gin.Context) { c.Next() }
+	scheduler := &mockJobScheduler{paused: map[string]bool{"OddsSync": false}}
+	router := gin.New()
+	NewJobAdminHandler(scheduler).RegisterJobAdminRoutes(router.Group("/api/v1"), passThrough, passThrough)
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantPaused bool
+	}{
+		{"/api/v1/admin/jobs/OddsSync/pause", http.StatusOK, true},
+		{"/api/v1/admin/jobs/OddsSync/pause", http.StatusOK, true},
+		{"/api/v1/admin/jobs/OddsSync/resume", http.StatusOK, false},
+		{"/api/v1/admin/jobs/Missing/pause", http.StatusNotFound, false},
+		{"/api/v1/admin/jobs/Missing/resume", http.StatusNotFound, false},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodPost, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.wantStatus {
+			t.Fatalf("POST %s: expected status %d, got %d. Body: %s", tt.path, tt.wantStatus, w.Code, w.Body.String())
+		}
+		if scheduler.paused["OddsSync"] != tt.wantPaused {
+			t.Errorf("POST %s: expected paused %v", tt.path, tt.wantPaused)
+		}
+	}
+}
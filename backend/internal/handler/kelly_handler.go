@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// KellyHandler handles Kelly stake HTTP requests.
+type KellyHandler struct {
+	service service.KellyService
+}
+
+// NewKellyHandler creates a new KellyHandler instance.
+func NewKellyHandler(svc service.KellyService) *KellyHandler {
+	return &KellyHandler{service: svc}
+}
+
+// KellyStakeRequest represents a request to size a bet.
+type KellyStakeRequest struct {
+	Odds            float64 `json:"odds" binding:"required"`
+	TrueProbability float64 `json:"true_probability" binding:"required"`
+}
+
+// KellyStake handles POST /api/v1/bankroll/kelly.
+// @Summary Calculate a Kelly stake
+// @Description Size a bet at decimal odds with fractional Kelly, using the estimated true probability of winning and the user's current bankroll and Kelly factor. Bets without a positive edge get a zero stake, and stakes are capped at the user's max stake per bet when one is set.
+// @Tags bankroll
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body KellyStakeRequest true "Bet odds and probability"
+// @Success 200 {object} service.KellyRecommendation
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/bankroll/kelly [post]
+func (h *KellyHandler) KellyStake(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req KellyStakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	rec, err := h.service.Stake(c.Request.Context(), userID, req.Odds, req.TrueProbability)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidKellyOdds, service.ErrInvalidKellyProbability:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to calculate stake")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, rec)
+}
+
+// RegisterKellyRoutes registers the Kelly stake route.
+func (h *KellyHandler) RegisterKellyRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	bankroll := rg.Group("/bankroll")
+	bankroll.Use(authMiddleware)
+	{
+		bankroll.POST("/kelly", h.KellyStake)
+	}
+}
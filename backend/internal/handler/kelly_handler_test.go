@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// fixedSettings returns the same settings for every user.
+type fixedSettings model.Settings
+
+func (s fixedSettings) GetUserSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error) {
+	settings := model.Settings(s)
+	settings.UserID = userID
+	return &settings, nil
+}
+
+func setupKellyRouter(settings model.Settings) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		c.Next()
+	}
+	NewKellyHandler(service.NewKellyService(fixedSettings(settings))).RegisterKellyRoutes(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestKellyHandler_KellyStake(t *testing.T) {
+	router := setupKellyRouter(model.Settings{CurrentBankroll: 1000, KellyFactor: 1, MaxStakePerBet: 150})
+
+	body := bytes.NewBufferString(`{"odds": 2.0, "true_probability": 0.6}`)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/bankroll/kelly", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var resp service.KellyRecommendation
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Stake != 150 || !resp.Capped || resp.Bankroll != 1000 {
+		t.Errorf("Expected a stake capped at 150, got %+v", resp)
+	}
+}
+
+func TestKellyHandler_KellyStake_Invalid(t *testing.T) {
+	router := setupKellyRouter(model.Settings{CurrentBankroll: 1000})
+
+	for _, body := range []string{
+		`{"odds": 0.9, "true_probability": 0.6}`,
+		`{"odds": 2.0, "true_probability": 1.5}`,
+		`{"odds": 2.0}`,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/bankroll/kelly", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d. Body: %s", body, w.Code, w.Body.String())
+		}
+	}
+}
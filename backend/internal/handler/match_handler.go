@@ -2,19 +2,28 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
-	"github.com/gin-gonic/gin"
 	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
 )
 
 // MatchHandler handles match-related HTTP requests.
 type MatchHandler struct {
-	matchRepo repository.MatchRepository
+	matchRepo     repository.MatchRepository
+	probabilities service.MatchProbabilityService
+	poisson       service.MatchPoissonService
 }
 
-// NewMatchHandler creates a new MatchHandler instance.
+// NewMatchHandler creates a new MatchHandler instance. Match probabilities
+// come from the default Elo model.
 func NewMatchHandler(matchRepo repository.MatchRepository) *MatchHandler {
-	return &MatchHandler{matchRepo: matchRepo}
+	return &MatchHandler{
+		matchRepo:     matchRepo,
+		probabilities: service.NewMatchProbabilityService(matchRepo, service.DefaultEloProbabilityModel),
+		poisson:       service.NewMatchPoissonService(matchRepo),
+	}
 }
 
 // ListMatches returns all matches.
@@ -27,7 +36,7 @@ func NewMatchHandler(matchRepo repository.MatchRepository) *MatchHandler {
 func (h *MatchHandler) ListMatches(c *gin.Context) {
 	matches, err := h.matchRepo.GetAll()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to fetch matches"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch matches")
 		return
 	}
 	c.JSON(http.StatusOK, matches)
@@ -47,10 +56,10 @@ func (h *MatchHandler) GetMatch(c *gin.Context) {
 	match, err := h.matchRepo.GetByID(id)
 	if err != nil {
 		if err == repository.ErrNotFound {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: "match not found"})
+			respondErrorMessage(c, http.StatusNotFound, "match not found")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to fetch match"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch match")
 		return
 	}
 	c.JSON(http.StatusOK, match)
@@ -69,12 +78,85 @@ func (h *MatchHandler) GetMatchOdds(c *gin.Context) {
 	id := c.Param("id")
 	odds, err := h.matchRepo.GetOddsByMatchID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to fetch odds"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch odds")
 		return
 	}
 	c.JSON(http.StatusOK, odds)
 }
 
+// GetMatchProbabilities returns the result probabilities of a match.
+// @Summary Get match probabilities
+// @Description Convert the teams' Elo ratings into home win, draw and away win probabilities. Draws are likeliest between evenly rated sides.
+// @Tags betting
+// @Produce json
+// @Param id path string true "Match ID"
+// @Success 200 {object} service.MatchProbabilities
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /api/v1/matches/{id}/probabilities [get]
+func (h *MatchHandler) GetMatchProbabilities(c *gin.Context) {
+	probs, err := h.probabilities.Probabilities(c.Param("id"))
+	if err != nil {
+		switch err {
+		case service.ErrMatchNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrUnratedTeams:
+			respondError(c, http.StatusUnprocessableEntity, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to calculate probabilities")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, probs)
+}
+
+// GetMatchPoisson returns Poisson model predictions for a match.
+// @Summary Get Poisson match predictions
+// @Description Model each side's goals as a Poisson variable with the given mean and return the likeliest scorelines with 1X2, both teams to score and over/under probabilities.
+// @Tags betting
+// @Produce json
+// @Param id path string true "Match ID"
+// @Param home_lambda query number true "Expected home goals"
+// @Param away_lambda query number true "Expected away goals"
+// @Param max_goals query int false "Goals per side in the score grid (1-10, default 10)"
+// @Success 200 {object} service.PoissonPrediction
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/matches/{id}/poisson [get]
+func (h *MatchHandler) GetMatchPoisson(c *gin.Context) {
+	homeLambda, err := strconv.ParseFloat(c.Query("home_lambda"), 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, service.ErrInvalidLambda)
+		return
+	}
+	awayLambda, err := strconv.ParseFloat(c.Query("away_lambda"), 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, service.ErrInvalidLambda)
+		return
+	}
+	var maxGoals int
+	if v := c.Query("max_goals"); v != "" {
+		if maxGoals, err = strconv.Atoi(v); err != nil || maxGoals == 0 {
+			respondError(c, http.StatusBadRequest, service.ErrInvalidMaxGoals)
+			return
+		}
+	}
+
+	prediction, err := h.poisson.Predict(c.Param("id"), homeLambda, awayLambda, maxGoals)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidLambda, service.ErrInvalidMaxGoals:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrMatchNotFound:
+			respondError(c, http.StatusNotFound, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to calculate predictions")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, prediction)
+}
+
 // RegisterMatchRoutes registers match-related routes.
 func (h *MatchHandler) RegisterMatchRoutes(rg *gin.RouterGroup) {
 	betting := rg.Group("/betting")
@@ -83,4 +165,6 @@ func (h *MatchHandler) RegisterMatchRoutes(rg *gin.RouterGroup) {
 		betting.GET("/matches/:id", h.GetMatch)
 		betting.GET("/matches/:id/odds", h.GetMatchOdds)
 	}
+	rg.GET("/matches/:id/probabilities", h.GetMatchProbabilities)
+	rg.GET("/matches/:id/poisson", h.GetMatchPoisson)
 }
@@ -2,15 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
-	"github.com/gin-gonic/gin"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
 )
 
 // findMockDataPathForHandler finds the mock data directory for handler tests
@@ -345,6 +347,85 @@ func TestMatchHandler_GetMatchOdds_Details(t *testing.T) {
 	}
 }
 
+func TestMatchHandler_GetMatchProbabilities(t *testing.T) {
+	router := setupMatchHandlerRouter(t)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/matches/1/probabilities", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var probs service.MatchProbabilities
+	if err := json.Unmarshal(w.Body.Bytes(), &probs); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if probs.HomeTeam != "Manchester United" || probs.HomeElo != 1850 {
+		t.Errorf("Expected Manchester United at home rated 1850, got %+v", probs)
+	}
+	if total := probs.HomeWin + probs.Draw + probs.AwayWin; math.Abs(total-1) > 1e-9 {
+		t.Errorf("Expected probabilities to sum to 1, got %v", total)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/matches/999/probabilities", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown match, got %d", w.Code)
+	}
+}
+
+func TestMatchHandler_GetMatchPoisson(t *testing.T) {
+	router := setupMatchHandlerRouter(t)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/matches/1/poisson?home_lambda=1.3&away_lambda=1.3&max_goals=6", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var prediction service.PoissonPrediction
+	if err := json.Unmarshal(w.Body.Bytes(), &prediction); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if prediction.MaxGoals != 6 || len(prediction.Scorelines) != service.TopScorelines {
+		t.Errorf("Expected a 6-goal grid and %d scorelines, got %+v", service.TopScorelines, prediction)
+	}
+	if top := prediction.Scorelines[0]; top.HomeGoals != 1 || top.AwayGoals != 1 {
+		t.Errorf("Expected 1-1 to be the likeliest score for even sides, got %d-%d", top.HomeGoals, top.AwayGoals)
+	}
+	if math.Abs(prediction.Markets.HomeWin-prediction.Markets.AwayWin) > 1e-9 {
+		t.Errorf("Expected even sides to have equal win probabilities, got %+v", prediction.Markets)
+	}
+
+	for _, tt := range []struct {
+		query string
+		want  int
+	}{
+		{"home_lambda=1.3", http.StatusBadRequest},
+		{"home_lambda=0&away_lambda=1.3", http.StatusBadRequest},
+		{"home_lambda=abc&away_lambda=1.3", http.StatusBadRequest},
+		{"home_lambda=1.3&away_lambda=1.3&max_goals=11", http.StatusBadRequest},
+		{"home_lambda=1.3&away_lambda=1.3&max_goals=0", http.StatusBadRequest},
+	} {
+		req, _ = http.NewRequest(http.MethodGet, "/api/v1/matches/1/poisson?"+tt.query, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("Expected status %d for %q, got %d", tt.want, tt.query, w.Code)
+		}
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/matches/999/poisson?home_lambda=1.3&away_lambda=1.3", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown match, got %d", w.Code)
+	}
+}
+
 func TestMatchHandler_RoutesRegistered(t *testing.T) {
 	router := setupMatchHandlerRouter(t)
 
@@ -353,6 +434,8 @@ func TestMatchHandler_RoutesRegistered(t *testing.T) {
 		"/api/v1/betting/matches",
 		"/api/v1/betting/matches/1",
 		"/api/v1/betting/matches/1/odds",
+		"/api/v1/matches/1/probabilities",
+		"/api/v1/matches/1/poisson?home_lambda=1.5&away_lambda=1.1",
 	}
 
 	for _, route := range routes {
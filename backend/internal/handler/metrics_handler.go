@@ -3,28 +3,92 @@ package handler
 import (
 	"net/http"
 	"runtime"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/awaymess/super-dashboard/backend/pkg/metrics"
 	"github.com/gin-gonic/gin"
 )
 
+// requestDurationBuckets are the upper bounds, in seconds, of the request
+// duration histogram: Prometheus' default buckets.
+var requestDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// unmatchedRoute labels requests that matched no route, so that probes for
+// arbitrary paths cannot add series.
+const unmatchedRoute = "unmatched"
+
 // MetricsHandler handles metrics endpoints.
 type MetricsHandler struct {
-	startTime     time.Time
-	requestCount  atomic.Uint64
-	errorCount    atomic.Uint64
+	startTime    time.Time
+	requestCount atomic.Uint64
+	errorCount   atomic.Uint64
+	inFlight     atomic.Int64
+	counters     []counterMetric
+	gauges       []gaugeMetric
+	counterVecs  []counterVecMetric
+
+	// durations holds one request duration histogram per route.
+	durationsMu sync.Mutex
+	durations   map[routeKey]*histogram
+}
+
+// routeKey labels a request duration series.
+type routeKey struct {
+	method string
+	route  string
+	status string
+}
+
+// histogram counts observations into requestDurationBuckets. counts[i] is
+// the number of observations no greater than requestDurationBuckets[i].
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// counterMetric is a counter maintained outside the handler, such as by a worker.
+type counterMetric struct {
+	name  string
+	help  string
+	value func() uint64
+}
+
+// gaugeMetric is a labelled gauge maintained outside the handler, reporting
+// one value per label value.
+type gaugeMetric struct {
+	name   string
+	help   string
+	label  string
+	values func() map[string]uint64
+}
+
+// counterVecMetric is a labelled counter incremented outside the handler,
+// such as by a service.
+type counterVecMetric struct {
+	name string
+	help string
+	vec  *metrics.CounterVec
 }
 
 // MetricsResponse represents the metrics response.
 type MetricsResponse struct {
-	Uptime        string        `json:"uptime"`
-	UptimeSeconds float64       `json:"uptime_seconds"`
-	Requests      uint64        `json:"requests_total"`
-	Errors        uint64        `json:"errors_total"`
-	Memory        MemoryMetrics `json:"memory"`
-	Goroutines    int           `json:"goroutines"`
+	Uptime        string                       `json:"uptime"`
+	UptimeSeconds float64                      `json:"uptime_seconds"`
+	Requests      uint64                       `json:"requests_total"`
+	Errors        uint64                       `json:"errors_total"`
+	Memory        MemoryMetrics                `json:"memory"`
+	Goroutines    int                          `json:"goroutines"`
+	Counters      map[string]uint64            `json:"counters,omitempty"`
+	Gauges        map[string]map[string]uint64 `json:"gauges,omitempty"`
+	// LabelledCounters maps each labelled counter to its series, keyed by
+	// their labels, e.g. side=buy,status=filled.
+	LabelledCounters map[string]map[string]uint64 `json:"labelled_counters,omitempty"`
 }
 
 // MemoryMetrics contains memory-related metrics.
@@ -39,6 +103,7 @@ type MemoryMetrics struct {
 func NewMetricsHandler() *MetricsHandler {
 	return &MetricsHandler{
 		startTime: time.Now(),
+		durations: make(map[routeKey]*histogram),
 	}
 }
 
@@ -52,6 +117,48 @@ func (h *MetricsHandler) IncrementErrors() {
 	h.errorCount.Add(1)
 }
 
+// ObserveRequest records a request's duration. route should be a route
+// template such as /api/v1/stocks/:symbol, never a raw path.
+func (h *MetricsHandler) ObserveRequest(method, route string, status int, duration time.Duration) {
+	key := routeKey{method: method, route: route, status: strconv.Itoa(status)}
+	seconds := duration.Seconds()
+
+	h.durationsMu.Lock()
+	defer h.durationsMu.Unlock()
+	hist, ok := h.durations[key]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(requestDurationBuckets))}
+		h.durations[key] = hist
+	}
+	for i, bound := range requestDurationBuckets {
+		if seconds <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += seconds
+}
+
+// AddCounter exposes an external counter. name is used as-is in the
+// Prometheus output and should carry the superdash_ prefix.
+func (h *MetricsHandler) AddCounter(name, help string, value func() uint64) {
+	h.counters = append(h.counters, counterMetric{name: name, help: help, value: value})
+}
+
+// AddGauge exposes an external gauge with one series per key of the map
+// returned by values, labelled with label. name follows the same rules as
+// for AddCounter.
+func (h *MetricsHandler) AddGauge(name, help, label string, values func() map[string]uint64) {
+	h.gauges = append(h.gauges, gaugeMetric{name: name, help: help, label: label, values: values})
+}
+
+// AddCounterVec exposes a labelled counter. name follows the same rules as
+// for AddCounter. A counter without labels is reported like one added with
+// AddCounter.
+func (h *MetricsHandler) AddCounterVec(name, help string, vec *metrics.CounterVec) {
+	h.counterVecs = append(h.counterVecs, counterVecMetric{name: name, help: help, vec: vec})
+}
+
 // Metrics returns application metrics.
 // @Summary Get application metrics
 // @Description Returns application metrics including uptime, memory usage, and request counts
@@ -65,6 +172,46 @@ func (h *MetricsHandler) Metrics(c *gin.Context) {
 
 	uptime := time.Since(h.startTime)
 
+	var counters map[string]uint64
+	if len(h.counters) > 0 {
+		counters = make(map[string]uint64, len(h.counters))
+		for _, counter := range h.counters {
+			counters[counter.name] = counter.value()
+		}
+	}
+
+	var labelled map[string]map[string]uint64
+	for _, counter := range h.counterVecs {
+		labels := counter.vec.Labels()
+		if len(labels) == 0 {
+			if counters == nil {
+				counters = make(map[string]uint64)
+			}
+			counters[counter.name] = counter.vec.Value()
+			continue
+		}
+		if labelled == nil {
+			labelled = make(map[string]map[string]uint64)
+		}
+		values := make(map[string]uint64)
+		for _, series := range counter.vec.Series() {
+			pairs := make([]string, len(labels))
+			for i, label := range labels {
+				pairs[i] = label + "=" + series.LabelValues[i]
+			}
+			values[strings.Join(pairs, ",")] = series.Value
+		}
+		labelled[counter.name] = values
+	}
+
+	var gauges map[string]map[string]uint64
+	if len(h.gauges) > 0 {
+		gauges = make(map[string]map[string]uint64, len(h.gauges))
+		for _, gauge := range h.gauges {
+			gauges[gauge.name] = gauge.values()
+		}
+	}
+
 	c.JSON(http.StatusOK, MetricsResponse{
 		Uptime:        uptime.String(),
 		UptimeSeconds: uptime.Seconds(),
@@ -76,7 +223,10 @@ func (h *MetricsHandler) Metrics(c *gin.Context) {
 			Sys:        memStats.Sys,
 			NumGC:      memStats.NumGC,
 		},
-		Goroutines: runtime.NumGoroutine(),
+		Goroutines:       runtime.NumGoroutine(),
+		Counters:         counters,
+		Gauges:           gauges,
+		LabelledCounters: labelled,
 	})
 }
 
@@ -114,22 +264,126 @@ func (h *MetricsHandler) PrometheusMetrics(c *gin.Context) {
 	metrics += "# HELP superdash_goroutines Current number of goroutines\n"
 	metrics += "# TYPE superdash_goroutines gauge\n"
 	metrics += "superdash_goroutines " + formatInt(runtime.NumGoroutine()) + "\n"
+	metrics += "\n"
+	metrics += "# HELP http_requests_in_flight Number of HTTP requests being served\n"
+	metrics += "# TYPE http_requests_in_flight gauge\n"
+	metrics += "http_requests_in_flight " + strconv.FormatInt(h.inFlight.Load(), 10) + "\n"
+	metrics += "\n"
+	metrics += h.requestDurationMetrics()
+
+	for _, counter := range h.counters {
+		metrics += "\n"
+		metrics += "# HELP " + counter.name + " " + counter.help + "\n"
+		metrics += "# TYPE " + counter.name + " counter\n"
+		metrics += counter.name + " " + formatUint64(counter.value()) + "\n"
+	}
+
+	for _, gauge := range h.gauges {
+		values := gauge.values()
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		metrics += "\n"
+		metrics += "# HELP " + gauge.name + " " + gauge.help + "\n"
+		metrics += "# TYPE " + gauge.name + " gauge\n"
+		for _, key := range keys {
+			metrics += gauge.name + "{" + gauge.label + "=" + strconv.Quote(key) + "} " + formatUint64(values[key]) + "\n"
+		}
+	}
+
+	for _, counter := range h.counterVecs {
+		metrics += "\n"
+		metrics += "# HELP " + counter.name + " " + counter.help + "\n"
+		metrics += "# TYPE " + counter.name + " counter\n"
+		labels := counter.vec.Labels()
+		if len(labels) == 0 {
+			metrics += counter.name + " " + formatUint64(counter.vec.Value()) + "\n"
+			continue
+		}
+		for _, s := range counter.vec.Series() {
+			pairs := make([]string, len(labels))
+			for i, label := range labels {
+				pairs[i] = label + "=" + strconv.Quote(s.LabelValues[i])
+			}
+			metrics += counter.name + "{" + strings.Join(pairs, ",") + "} " + formatUint64(s.Value) + "\n"
+		}
+	}
 
 	c.Header("Content-Type", "text/plain; charset=utf-8")
 	c.String(http.StatusOK, metrics)
 }
 
+// requestDurationMetrics renders the request duration histograms, sorted
+// by route, method and status.
+func (h *MetricsHandler) requestDurationMetrics() string {
+	h.durationsMu.Lock()
+	defer h.durationsMu.Unlock()
+
+	keys := make([]routeKey, 0, len(h.durations))
+	for key := range h.durations {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	metrics := "# HELP http_request_duration_seconds HTTP request duration by method, route and status\n"
+	metrics += "# TYPE http_request_duration_seconds histogram\n"
+	for _, key := range keys {
+		hist := h.durations[key]
+		labels := "method=" + strconv.Quote(key.method) + ",route=" + strconv.Quote(key.route) + ",status=" + strconv.Quote(key.status)
+		for i, bound := range requestDurationBuckets {
+			metrics += "http_request_duration_seconds_bucket{" + labels + ",le=" + strconv.Quote(strconv.FormatFloat(bound, 'g', -1, 64)) + "} " + formatUint64(hist.counts[i]) + "\n"
+		}
+		metrics += "http_request_duration_seconds_bucket{" + labels + ",le=\"+Inf\"} " + formatUint64(hist.count) + "\n"
+		metrics += "http_request_duration_seconds_sum{" + labels + "} " + strconv.FormatFloat(hist.sum, 'g', -1, 64) + "\n"
+		metrics += "http_request_duration_seconds_count{" + labels + "} " + formatUint64(hist.count) + "\n"
+	}
+	return metrics
+}
+
+// serveMetrics serves the Prometheus text format to scrapers, which ask for
+// text/plain or OpenMetrics, and the JSON metrics to everyone else.
+func (h *MetricsHandler) serveMetrics(c *gin.Context) {
+	accept := c.GetHeader("Accept")
+	if strings.Contains(accept, "text/plain") || strings.Contains(accept, "application/openmetrics-text") {
+		h.PrometheusMetrics(c)
+		return
+	}
+	h.Metrics(c)
+}
+
 // RegisterMetricsRoutes registers metrics routes.
 func (h *MetricsHandler) RegisterMetricsRoutes(r *gin.Engine) {
-	r.GET("/metrics", h.Metrics)
+	r.GET("/metrics", h.serveMetrics)
 	r.GET("/metrics/prometheus", h.PrometheusMetrics)
 }
 
-// MetricsMiddleware returns a middleware that tracks requests.
+// MetricsMiddleware returns a middleware that tracks requests, their
+// duration per route template and the number in flight.
 func (h *MetricsHandler) MetricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		h.IncrementRequests()
+		h.inFlight.Add(1)
+		start := time.Now()
+
 		c.Next()
+
+		h.inFlight.Add(-1)
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		h.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
 		if c.Writer.Status() >= 400 {
 			h.IncrementErrors()
 		}
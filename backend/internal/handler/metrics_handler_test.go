@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/pkg/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestMetricsHandler_AddCounter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var evaluated uint64 = 42
+	h := NewMetricsHandler()
+	h.AddCounter("superdash_test_events_total", "Total number of test events", func() uint64 {
+		return evaluated
+	})
+
+	router := gin.New()
+	h.RegisterMetricsRoutes(router)
+
+	req, _ := http.NewRequest(http.MethodGet, "/metrics/prometheus", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	for _, want := range []string{
+		"# HELP superdash_test_events_total Total number of test events\n",
+		"# TYPE superdash_test_events_total counter\n",
+		"superdash_test_events_total 42\n",
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, w.Body.String())
+		}
+	}
+
+	evaluated = 43
+	req, _ = http.NewRequest(http.MethodGet, "/metrics", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response MetricsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Counters["superdash_test_events_total"] != 43 {
+		t.Errorf("Expected counter value 43, got %d", response.Counters["superdash_test_events_total"])
+	}
+}
+
+func TestMetricsHandler_AddGauge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := NewMetricsHandler()
+	h.AddGauge("superdash_test_state", "Test state per provider", "provider", func() map[string]uint64 {
+		return map[string]uint64{"yahoo": 1, "alphavantage": 0}
+	})
+
+	router := gin.New()
+	h.RegisterMetricsRoutes(router)
+
+	req, _ := http.NewRequest(http.MethodGet, "/metrics/prometheus", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	want := "# HELP superdash_test_state Test state per provider\n" +
+		"# TYPE superdash_test_state gauge\n" +
+		"superdash_test_state{provider=\"alphavantage\"} 0\n" +
+		"superdash_test_state{provider=\"yahoo\"} 1\n"
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("Expected output to contain %q, got:\n%s", want, w.Body.String())
+	}
+}
+
+func TestMetricsHandler_RequestDurations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := NewMetricsHandler()
+	router := gin.New()
+	h.RegisterMetricsRoutes(router)
+	router.Use(h.MetricsMiddleware())
+	router.GET("/api/v1/stocks/:symbol", func(c *gin.Context) {
+		if c.Param("symbol") == "MISSING" {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/api/v1/stocks/AAPL", "/api/v1/stocks/MSFT", "/api/v1/stocks/MISSING", "/no/such/path"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Scrape the way Prometheus does
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "text/plain;version=0.0.4;q=0.5,*/*;q=0.1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"# TYPE http_request_duration_seconds histogram\n",
+		"http_request_duration_seconds_bucket{method=\"GET\",route=\"/api/v1/stocks/:symbol\",status=\"200\",le=\"+Inf\"} 2\n",
+		"http_request_duration_seconds_count{method=\"GET\",route=\"/api/v1/stocks/:symbol\",status=\"200\"} 2\n",
+		"http_request_duration_seconds_bucket{method=\"GET\",route=\"/api/v1/stocks/:symbol\",status=\"404\",le=\"10\"} 1\n",
+		"http_request_duration_seconds_count{method=\"GET\",route=\"unmatched\",status=\"404\"} 1\n",
+		"# TYPE http_requests_in_flight gauge\n",
+		"http_requests_in_flight 0\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, body)
+		}
+	}
+	for _, raw := range []string{"AAPL", "/no/such/path"} {
+		if strings.Contains(body, raw) {
+			t.Errorf("Expected raw path %q to stay out of the labels, got:\n%s", raw, body)
+		}
+	}
+
+	// Other clients still get JSON
+	req, _ = http.NewRequest(http.MethodGet, "/metrics", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response MetricsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse JSON metrics: %v", err)
+	}
+	if response.Requests != 4 || response.Errors != 2 {
+		t.Errorf("Expected 4 requests and 2 errors, got %d and %d", response.Requests, response.Errors)
+	}
+}
+
+func TestMetricsHandler_InFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := NewMetricsHandler()
+	router := gin.New()
+	h.RegisterMetricsRoutes(router)
+	router.Use(h.MetricsMiddleware())
+
+	var scraped string
+	router.GET("/slow", func(c *gin.Context) {
+		req, _ := http.NewRequest(http.MethodGet, "/metrics/prometheus", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		scraped = w.Body.String()
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/slow", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(scraped, "http_requests_in_flight 1\n") {
+		t.Errorf("Expected one request in flight during the request, got:\n%s", scraped)
+	}
+}
+
+func TestMetricsHandler_AddCounterVec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	deliveries := metrics.NewCounterVec("channel", "status")
+	deliveries.Inc("email", "failed")
+	deliveries.Add(2, "discord", "delivered")
+	placed := metrics.NewCounterVec()
+	placed.Add(3)
+
+	h := NewMetricsHandler()
+	h.AddCounterVec("superdash_test_deliveries_total", "Total number of test deliveries", deliveries)
+	h.AddCounterVec("superdash_test_placed_total", "Total number of test placements", placed)
+	router := gin.New()
+	h.RegisterMetricsRoutes(router)
+
+	req, _ := http.NewRequest(http.MethodGet, "/metrics/prometheus", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	want := "# HELP superdash_test_deliveries_total Total number of test deliveries\n" +
+		"# TYPE superdash_test_deliveries_total counter\n" +
+		"superdash_test_deliveries_total{channel=\"discord\",status=\"delivered\"} 2\n" +
+		"superdash_test_deliveries_total{channel=\"email\",status=\"failed\"} 1\n"
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("Expected output to contain %q, got:\n%s", want, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "\nsuperdash_test_placed_total 3\n") {
+		t.Errorf("Expected an unlabelled counter, got:\n%s", w.Body.String())
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/metrics", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response MetricsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if got := response.LabelledCounters["superdash_test_deliveries_total"]["channel=discord,status=delivered"]; got != 2 {
+		t.Errorf("Expected 2 discord deliveries, got %d", got)
+	}
+	if got := response.Counters["superdash_test_placed_total"]; got != 3 {
+		t.Errorf("Expected 3 placements, got %d", got)
+	}
+}
+
+func TestMetricsHandler_PaperOrdersCounter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	portfolioRepo := repository.NewInMemoryPortfolioRepository()
+	paperService := service.NewPaperTradingService(portfolioRepo, repository.NewInMemoryPositionRepository(), repository.NewInMemoryOrderRepository(), repository.NewInMemoryTradeRepository(), nil)
+	portfolio, err := paperService.CreatePortfolio(uuid.New(), "Metrics", 10000)
+	if err != nil {
+		t.Fatalf("Failed to create portfolio: %v", err)
+	}
+
+	h := NewMetricsHandler()
+	h.AddCounterVec("superdash_paper_orders_total", "Total number of paper orders created", service.PaperOrdersTotal)
+	router := gin.New()
+	h.RegisterMetricsRoutes(router)
+	NewPaperHandler(paperService).RegisterPaperRoutes(router.Group("/api/v1"))
+
+	// The counter is process-wide, so compare against its starting value
+	before := service.PaperOrdersTotal.Value("buy", "filled")
+
+	body, _ := json.Marshal(PaperOrderRequest{PortfolioID: portfolio.ID.String(), Symbol: "AAPL", Side: "buy", OrderType: "market", Quantity: 1})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/paper/orders", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "text/plain")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	want := fmt.Sprintf("superdash_paper_orders_total{side=\"buy\",status=\"filled\"} %d\n", before+1)
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("Expected output to contain %q, got:\n%s", want, w.Body.String())
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/gin-gonic/gin"
+	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // NLPHandler handles NLP-related HTTP requests.
@@ -31,10 +33,10 @@ type IngestRequest struct {
 
 // IngestResponse represents the response after ingesting an article.
 type IngestResponse struct {
-	ID        string          `json:"id"`
-	Sentiment SentimentOutput `json:"sentiment"`
-	EventType string          `json:"event_type"`
-	EmbeddingCreated bool     `json:"embedding_created"`
+	ID               string          `json:"id"`
+	Sentiment        SentimentOutput `json:"sentiment"`
+	EventType        string          `json:"event_type"`
+	EmbeddingCreated bool            `json:"embedding_created"`
 }
 
 // SentimentOutput contains sentiment analysis results.
@@ -55,9 +57,24 @@ type SearchResultResponse struct {
 
 // SearchResponse represents a semantic search response.
 type SearchResponse struct {
-	Results             []SearchResultResponse `json:"results"`
-	QueryEmbeddingTimeMs int64                 `json:"query_embedding_time_ms"`
-	SearchTimeMs        int64                  `json:"search_time_ms"`
+	Results              []SearchResultResponse `json:"results"`
+	QueryEmbeddingTimeMs int64                  `json:"query_embedding_time_ms"`
+	SearchTimeMs         int64                  `json:"search_time_ms"`
+}
+
+// ArticleResponse represents a stored article with its NLP results.
+type ArticleResponse struct {
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	Content     string          `json:"content"`
+	Source      string          `json:"source"`
+	URL         string          `json:"url"`
+	Symbols     []string        `json:"symbols"`
+	Summary     string          `json:"summary"`
+	Sentiment   SentimentOutput `json:"sentiment"`
+	EventType   string          `json:"event_type"`
+	PublishedAt string          `json:"published_at,omitempty"`
+	CreatedAt   string          `json:"created_at"`
 }
 
 // Ingest handles the POST /api/v1/nlp/ingest endpoint.
@@ -74,7 +91,7 @@ type SearchResponse struct {
 func (h *NLPHandler) Ingest(c *gin.Context) {
 	var req IngestRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -104,7 +121,7 @@ func (h *NLPHandler) Ingest(c *gin.Context) {
 
 	result, err := h.nlpService.IngestArticle(c.Request.Context(), ingestReq)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to ingest article: " + err.Error()})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to ingest article: "+err.Error())
 		return
 	}
 
@@ -133,7 +150,7 @@ func (h *NLPHandler) Ingest(c *gin.Context) {
 func (h *NLPHandler) Search(c *gin.Context) {
 	query := c.Query("q")
 	if query == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "query parameter 'q' is required"})
+		respondErrorMessage(c, http.StatusBadRequest, "query parameter 'q' is required")
 		return
 	}
 
@@ -146,7 +163,7 @@ func (h *NLPHandler) Search(c *gin.Context) {
 
 	result, err := h.nlpService.SemanticSearch(c.Request.Context(), query, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "search failed: " + err.Error()})
+		respondErrorMessage(c, http.StatusInternalServerError, "search failed: "+err.Error())
 		return
 	}
 
@@ -168,19 +185,111 @@ func (h *NLPHandler) Search(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, SearchResponse{
-		Results:             results,
+		Results:              results,
 		QueryEmbeddingTimeMs: result.QueryEmbeddingTimeMs,
-		SearchTimeMs:        result.SearchTimeMs,
+		SearchTimeMs:         result.SearchTimeMs,
 	})
 }
 
-// RegisterNLPRoutes registers NLP-related routes.
-func (h *NLPHandler) RegisterNLPRoutes(rg *gin.RouterGroup) {
+// GetArticle handles the GET /api/v1/nlp/articles/:id endpoint.
+// @Summary Get an ingested article
+// @Description Get a stored article with its sentiment, event type, summary and symbols
+// @Tags nlp
+// @Produce json
+// @Param id path string true "Article ID"
+// @Success 200 {object} ArticleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/nlp/articles/{id} [get]
+func (h *NLPHandler) GetArticle(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid article id")
+		return
+	}
+
+	article, err := h.nlpService.GetArticle(c.Request.Context(), id)
+	if err != nil {
+		if err == service.ErrArticleNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch article")
+		return
+	}
+
+	c.JSON(http.StatusOK, articleToResponse(article))
+}
+
+// DeleteArticle handles the DELETE /api/v1/nlp/articles/:id endpoint.
+// @Summary Delete an ingested article
+// @Description Delete a stored article and its embedding. Requires the admin role.
+// @Tags nlp
+// @Security BearerAuth
+// @Param id path string true "Article ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/nlp/articles/{id} [delete]
+func (h *NLPHandler) DeleteArticle(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid article id")
+		return
+	}
+
+	if err := h.nlpService.DeleteArticle(c.Request.Context(), id); err != nil {
+		if err == service.ErrArticleNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to delete article")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegisterNLPRoutes registers NLP-related routes. Deleting articles requires
+// authMiddleware and adminMiddleware.
+func (h *NLPHandler) RegisterNLPRoutes(rg *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
 	nlp := rg.Group("/nlp")
 	{
 		nlp.POST("/ingest", h.Ingest)
 		nlp.GET("/search", h.Search)
+		nlp.GET("/articles/:id", h.GetArticle)
+		nlp.DELETE("/articles/:id", authMiddleware, adminMiddleware, h.DeleteArticle)
+	}
+}
+
+// articleToResponse converts a model.Article to ArticleResponse.
+func articleToResponse(article *model.Article) ArticleResponse {
+	symbols := []string(article.Symbols)
+	if symbols == nil {
+		symbols = []string{}
+	}
+
+	response := ArticleResponse{
+		ID:      article.ID.String(),
+		Title:   article.Title,
+		Content: article.Content,
+		Source:  article.Source,
+		URL:     article.URL,
+		Symbols: symbols,
+		Summary: article.Summary,
+		Sentiment: SentimentOutput{
+			Score: article.SentimentScore,
+			Label: article.SentimentLabel,
+		},
+		EventType: article.EventType,
+		CreatedAt: article.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if article.PublishedAt != nil {
+		response.PublishedAt = article.PublishedAt.Format("2006-01-02T15:04:05Z")
 	}
+	return response
 }
 
 // parseTime attempts to parse a time string with the given layout.
@@ -7,13 +7,23 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/gin-gonic/gin"
+	"github.com/awaymess/super-dashboard/backend/internal/middleware"
 	"github.com/awaymess/super-dashboard/backend/internal/repository"
 	"github.com/awaymess/super-dashboard/backend/internal/service"
 	"github.com/awaymess/super-dashboard/backend/pkg/nlp"
+	"github.com/gin-gonic/gin"
 )
 
 func setupNLPHandler() (*NLPHandler, *gin.Engine) {
+	return setupNLPHandlerWithAuth(func(c *gin.Context) {
+		c.Set("role", "admin")
+		c.Next()
+	})
+}
+
+// setupNLPHandlerWithAuth registers the NLP routes behind authMiddleware
+// and the admin role check.
+func setupNLPHandlerWithAuth(authMiddleware gin.HandlerFunc) (*NLPHandler, *gin.Engine) {
 	gin.SetMode(gin.TestMode)
 
 	provider := nlp.NewMockProvider()
@@ -23,7 +33,7 @@ func setupNLPHandler() (*NLPHandler, *gin.Engine) {
 
 	router := gin.New()
 	v1 := router.Group("/api/v1")
-	handler.RegisterNLPRoutes(v1)
+	handler.RegisterNLPRoutes(v1, authMiddleware, middleware.RequireRole("admin"))
 
 	return handler, router
 }
@@ -378,3 +388,108 @@ func TestNLPHandler_SearchSemanticSimilarity(t *testing.T) {
 		}
 	}
 }
+
+func TestNLPHandler_GetAndDeleteArticle(t *testing.T) {
+	_, router := setupNLPHandler()
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{
+		"title":   "Apple announces quarterly earnings",
+		"content": "Apple beat expectations with record revenue.",
+		"symbols": []string{"AAPL"},
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/nlp/ingest", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var ingested IngestResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &ingested); err != nil {
+		t.Fatalf("Failed to unmarshal ingest response: %v", err)
+	}
+
+	t.Run("get ingested article", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/nlp/articles/"+ingested.ID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var article ArticleResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &article); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if article.ID != ingested.ID {
+			t.Errorf("Expected ID %s, got %s", ingested.ID, article.ID)
+		}
+		if article.EventType != "earnings" {
+			t.Errorf("Expected event type earnings, got %s", article.EventType)
+		}
+		if article.Sentiment.Label != ingested.Sentiment.Label {
+			t.Errorf("Expected sentiment %s, got %s", ingested.Sentiment.Label, article.Sentiment.Label)
+		}
+		if article.Summary != "Apple beat expectations with record revenue." {
+			t.Errorf("Unexpected summary %q", article.Summary)
+		}
+		if len(article.Symbols) != 1 || article.Symbols[0] != "AAPL" {
+			t.Errorf("Expected symbols [AAPL], got %v", article.Symbols)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		id         string
+		wantStatus int
+	}{
+		{"invalid id", http.MethodGet, "not-a-uuid", http.StatusBadRequest},
+		{"get unknown article", http.MethodGet, "00000000-0000-0000-0000-000000000001", http.StatusNotFound},
+		{"delete invalid id", http.MethodDelete, "not-a-uuid", http.StatusBadRequest},
+		{"delete article", http.MethodDelete, ingested.ID, http.StatusNoContent},
+		{"get deleted article", http.MethodGet, ingested.ID, http.StatusNotFound},
+		{"delete deleted article", http.MethodDelete, ingested.ID, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "/api/v1/nlp/articles/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNLPHandler_DeleteArticleRequiresAdmin(t *testing.T) {
+	authService := service.NewExtendedAuthService(service.AuthServiceConfig{JWTSecret: "test-secret"})
+	setUserRole := func(c *gin.Context) {
+		c.Set("role", "user")
+		c.Next()
+	}
+
+	tests := []struct {
+		name           string
+		authMiddleware gin.HandlerFunc
+		wantStatus     int
+	}{
+		{"no token", middleware.AuthMiddleware(authService), http.StatusUnauthorized},
+		{"non-admin", setUserRole, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, router := setupNLPHandlerWithAuth(tt.authMiddleware)
+
+			req, _ := http.NewRequest(http.MethodDelete, "/api/v1/nlp/articles/00000000-0000-0000-0000-000000000001", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
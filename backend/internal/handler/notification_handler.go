@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Notification page size bounds.
+const (
+	defaultNotificationLimit = 20
+	maxNotificationLimit     = 100
+)
+
+// NotificationHandler handles notification HTTP requests.
+type NotificationHandler struct {
+	service service.NotificationDeliveryService
+	inbox   service.NotificationInboxService
+}
+
+// NewNotificationHandler creates a new NotificationHandler instance.
+func NewNotificationHandler(svc service.NotificationDeliveryService, inbox service.NotificationInboxService) *NotificationHandler {
+	return &NotificationHandler{service: svc, inbox: inbox}
+}
+
+// UnreadCountResponse is the number of unread notifications a user has.
+type UnreadCountResponse struct {
+	Count int64 `json:"count"`
+}
+
+// ListNotifications handles GET /api/v1/notifications.
+// @Summary List notifications
+// @Description List a page of the current user's notifications, newest first. The total count is returned in X-Total-Count.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of notifications to skip"
+// @Success 200 {array} model.Notification
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	limit, offset, err := parseNotificationPage(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	notifications, total, err := h.inbox.ListNotifications(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to list notifications")
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.JSON(http.StatusOK, notifications)
+}
+
+// MarkNotificationRead handles POST /api/v1/notifications/:id/read.
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	if err := h.inbox.MarkRead(c.Request.Context(), userID, notificationID); err != nil {
+		if err == service.ErrNotificationNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to mark notification as read")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MarkAllNotificationsRead handles POST /api/v1/notifications/read-all.
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.inbox.MarkAllRead(c.Request.Context(), userID); err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to mark notifications as read")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetUnreadCount handles GET /api/v1/notifications/unread-count.
+// @Summary Count unread notifications
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} UnreadCountResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	count, err := h.inbox.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to count unread notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, UnreadCountResponse{Count: count})
+}
+
+// parseNotificationPage reads and validates the limit and offset query parameters.
+func parseNotificationPage(c *gin.Context) (limit, offset int, err error) {
+	limit = defaultNotificationLimit
+	if v := c.Query("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxNotificationLimit {
+			return 0, 0, errors.New("invalid limit: must be between 1 and 100")
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("invalid offset")
+		}
+	}
+	return limit, offset, nil
+}
+
+// NotificationDeliveriesResponse lists a notification's per-channel deliveries.
+type NotificationDeliveriesResponse struct {
+	NotificationID uuid.UUID                    `json:"notification_id"`
+	Deliveries     []model.NotificationDelivery `json:"deliveries"`
+}
+
+// GetDeliveries handles GET /api/v1/notifications/:id/deliveries.
+// @Summary List notification deliveries
+// @Description List the channels a notification was sent through with each channel's status, error, attempt count and delivery time
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} NotificationDeliveriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/notifications/{id}/deliveries [get]
+func (h *NotificationHandler) GetDeliveries(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	deliveries, err := h.service.GetDeliveries(c.Request.Context(), userID, notificationID)
+	if err != nil {
+		if err == service.ErrNotificationNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get notification deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, NotificationDeliveriesResponse{
+		NotificationID: notificationID,
+		Deliveries:     deliveries,
+	})
+}
+
+// RegisterNotificationRoutes registers notification routes.
+func (h *NotificationHandler) RegisterNotificationRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	notifications := rg.Group("/notifications")
+	notifications.Use(authMiddleware)
+	{
+		notifications.GET("", h.ListNotifications)
+		notifications.GET("/unread-count", h.GetUnreadCount)
+		notifications.POST("/read-all", h.MarkAllNotificationsRead)
+		notifications.POST("/:id/read", h.MarkNotificationRead)
+		notifications.GET("/:id/deliveries", h.GetDeliveries)
+	}
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// mockNotificationDeliveryService is a mock implementation of NotificationDeliveryService.
+type mockNotificationDeliveryService struct {
+	notificationID uuid.UUID
+}
+
+func (m *mockNotificationDeliveryService) GetDeliveries(ctx context.Context, userID, notificationID uuid.UUID) ([]model.NotificationDelivery, error) {
+	if notificationID != m.notificationID {
+		return nil, service.ErrNotificationNotFound
+	}
+	return []model.NotificationDelivery{
+		{NotificationID: notificationID, Channel: model.NotificationChannelInApp, Status: model.DeliveryStatusDelivered, Attempts: 1},
+		{NotificationID: notificationID, Channel: model.NotificationChannelTelegram, Status: model.DeliveryStatusFailed, Attempts: 3, Error: "chat not found"},
+	}, nil
+}
+
+// mockNotificationInboxService is a mock implementation of NotificationInboxService
+// holding a fixed number of notifications, of which unread are unread.
+type mockNotificationInboxService struct {
+	total       int
+	unread      int64
+	lastLimit   int
+	lastOffset  int
+	markedRead  []uuid.UUID
+	readAllUser uuid.UUID
+}
+
+func (m *mockNotificationInboxService) ListNotifications(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Notification, int64, error) {
+	m.lastLimit, m.lastOffset = limit, offset
+	notifications := []model.Notification{}
+	for i := offset; i < m.total && i < offset+limit; i++ {
+		notifications = append(notifications, model.Notification{ID: uuid.New(), UserID: userID})
+	}
+	return notifications, int64(m.total), nil
+}
+
+func (m *mockNotificationInboxService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	m.markedRead = append(m.markedRead, notificationID)
+	return nil
+}
+
+func (m *mockNotificationInboxService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	m.readAllUser = userID
+	m.unread = 0
+	return nil
+}
+
+func (m *mockNotificationInboxService) UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return m.unread, nil
+}
+
+func TestNotificationHandler_GetDeliveries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &mockNotificationDeliveryService{notificationID: uuid.New()}
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", uuid.New().String())
+		c.Next()
+	}
+	NewNotificationHandler(svc, &mockNotificationInboxService{}).RegisterNotificationRoutes(router.Group("/api/v1"), authMiddleware)
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+		wantCode   string
+	}{
+		{"existing notification", svc.notificationID.String(), http.StatusOK, ""},
+		{"unknown notification", uuid.New().String(), http.StatusNotFound, CodeNotFound},
+		{"invalid notification ID", "not-a-uuid", http.StatusBadRequest, CodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/notifications/"+tt.id+"/deliveries", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var resp ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("Failed to parse response: %v", err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("Expected code %q, got %q", tt.wantCode, resp.Code)
+				}
+			}
+		})
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/notifications/"+svc.notificationID.String()+"/deliveries", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp NotificationDeliveriesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.NotificationID != svc.notificationID || len(resp.Deliveries) != 2 {
+		t.Fatalf("Unexpected response %+v", resp)
+	}
+	if failed := resp.Deliveries[1]; failed.Status != model.DeliveryStatusFailed || failed.Attempts != 3 || failed.Error != "chat not found" {
+		t.Errorf("Unexpected failed delivery %+v", failed)
+	}
+}
+
+func setupNotificationInboxRouter(inbox *mockNotificationInboxService, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+		c.Next()
+	}
+	NewNotificationHandler(&mockNotificationDeliveryService{}, inbox).RegisterNotificationRoutes(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func TestNotificationHandler_ListNotifications(t *testing.T) {
+	inbox := &mockNotificationInboxService{total: 25}
+	router := setupNotificationInboxRouter(inbox, uuid.New())
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantLimit  int
+		wantOffset int
+		wantCount  int
+	}{
+		{"default page", "", http.StatusOK, 20, 0, 20},
+		{"last page", "?limit=10&offset=20", http.StatusOK, 10, 20, 5},
+		{"limit too large", "?limit=101", http.StatusBadRequest, 0, 0, 0},
+		{"negative offset", "?offset=-1", http.StatusBadRequest, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/notifications"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if inbox.lastLimit != tt.wantLimit || inbox.lastOffset != tt.wantOffset {
+				t.Errorf("Expected limit %d offset %d, got %d and %d", tt.wantLimit, tt.wantOffset, inbox.lastLimit, inbox.lastOffset)
+			}
+			if total := w.Header().Get("X-Total-Count"); total != "25" {
+				t.Errorf("Expected X-Total-Count 25, got %q", total)
+			}
+			var notifications []model.Notification
+			if err := json.Unmarshal(w.Body.Bytes(), &notifications); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if len(notifications) != tt.wantCount {
+				t.Errorf("Expected %d notifications, got %d", tt.wantCount, len(notifications))
+			}
+		})
+	}
+}
+
+func TestNotificationHandler_MarkAllRead(t *testing.T) {
+	userID := uuid.New()
+	inbox := &mockNotificationInboxService{unread: 3}
+	router := setupNotificationInboxRouter(inbox, userID)
+
+	unreadCount := func() int64 {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/notifications/unread-count", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var resp UnreadCountResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return resp.Count
+	}
+
+	if count := unreadCount(); count != 3 {
+		t.Fatalf("Expected 3 unread, got %d", count)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/notifications/read-all", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if inbox.readAllUser != userID {
+		t.Errorf("Expected notifications of %s to be marked read, got %s", userID, inbox.readAllUser)
+	}
+	if count := unreadCount(); count != 0 {
+		t.Errorf("Expected 0 unread after marking all read, got %d", count)
+	}
+
+	notificationID := uuid.New()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/notifications/"+notificationID.String()+"/read", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent || len(inbox.markedRead) != 1 || inbox.markedRead[0] != notificationID {
+		t.Errorf("Expected notification to be marked read, got status %d and %v", w.Code, inbox.markedRead)
+	}
+}
@@ -1,14 +1,22 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
-	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
 	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 )
 
+// historyDateLayout is the date-only format accepted by history from/to parameters.
+const historyDateLayout = "2006-01-02"
+
 // PaperOrderRequest represents a request to create a paper trading order.
 type PaperOrderRequest struct {
 	PortfolioID string  `json:"portfolio_id" binding:"required,uuid"`
@@ -17,6 +25,7 @@ type PaperOrderRequest struct {
 	OrderType   string  `json:"order_type" binding:"required,oneof=market limit"`
 	Quantity    int64   `json:"quantity" binding:"required,gt=0"`
 	Price       float64 `json:"price,omitempty"`
+	TimeInForce string  `json:"time_in_force,omitempty" binding:"omitempty,oneof=DAY GTC IOC FOK"`
 }
 
 // OrderResponse represents an order response.
@@ -29,6 +38,7 @@ type OrderResponse struct {
 	Quantity    int64   `json:"quantity"`
 	Price       float64 `json:"price"`
 	Status      string  `json:"status"`
+	TimeInForce string  `json:"time_in_force"`
 	FilledAt    string  `json:"filled_at,omitempty"`
 	CreatedAt   string  `json:"created_at"`
 	UpdatedAt   string  `json:"updated_at"`
@@ -44,7 +54,15 @@ type TradeResponse struct {
 	Quantity    int64   `json:"quantity"`
 	Price       float64 `json:"price"`
 	Total       float64 `json:"total"`
-	ExecutedAt  string  `json:"executed_at"`
+	// ReferencePrice is the market price before slippage; Slippage is the
+	// fill's total cost against it.
+	ReferencePrice float64 `json:"reference_price"`
+	Slippage       float64 `json:"slippage"`
+	// Fees is the commission charged on the fill.
+	Fees float64 `json:"fees"`
+	// RealizedPL is the P&L a sell locked in against the average cost.
+	RealizedPL float64 `json:"realized_pl"`
+	ExecutedAt string  `json:"executed_at"`
 }
 
 // CreatePortfolioRequest represents a request to create a portfolio.
@@ -59,9 +77,34 @@ type UpdatePortfolioRequest struct {
 	Name string `json:"name" binding:"required"`
 }
 
+// UpdateSlippageRequest represents a request to change a portfolio's slippage model.
+type UpdateSlippageRequest struct {
+	SlippageModel     string  `json:"slippage_model" binding:"required,oneof=none fixed_bps volatility"`
+	SlippageBps       float64 `json:"slippage_bps"`
+	SlippageVolFactor float64 `json:"slippage_vol_factor"`
+}
+
+// UpdateFeesRequest represents a request to change a portfolio's fee model.
+type UpdateFeesRequest struct {
+	FeeModel  string  `json:"fee_model" binding:"required,oneof=none flat per_share bps"`
+	FeeAmount float64 `json:"fee_amount"`
+}
+
+// PortfolioValueResponse is a portfolio valued at its refreshed prices.
+type PortfolioValueResponse struct {
+	PortfolioID uuid.UUID        `json:"portfolio_id"`
+	CashBalance float64          `json:"cash_balance"`
+	MarketValue float64          `json:"market_value"`
+	TotalValue  float64          `json:"total_value"`
+	Positions   []model.Position `json:"positions"`
+}
+
 // PaperHandler handles paper trading HTTP requests with service layer.
 type PaperHandler struct {
-	service service.PaperTradingService
+	service     service.PaperTradingService
+	backtests   service.BacktestService
+	audit       *AuditRecorder
+	idempotency IdempotencyStore
 }
 
 // NewPaperHandler creates a new PaperHandler instance.
@@ -69,55 +112,158 @@ func NewPaperHandler(svc service.PaperTradingService) *PaperHandler {
 	return &PaperHandler{service: svc}
 }
 
+// SetAuditRecorder enables audit logging of placed orders and deleted portfolios.
+func (h *PaperHandler) SetAuditRecorder(audit *AuditRecorder) {
+	h.audit = audit
+}
+
+// SetIdempotencyStore enables the Idempotency-Key header on order creation.
+func (h *PaperHandler) SetIdempotencyStore(store IdempotencyStore) {
+	h.idempotency = store
+}
+
+// SetBacktests enables serving stored backtest results.
+func (h *PaperHandler) SetBacktests(backtests service.BacktestService) {
+	h.backtests = backtests
+}
+
 // CreateOrder creates a new paper trading order.
 // @Summary Create paper order
-// @Description Create a new paper trading order with simulated fill
+// @Description Create a new paper trading order with simulated fill. Limit orders that are not marketable rest as pending orders (IOC and FOK are cancelled instead); pending buys reserve their cost at the limit price. Retrying with the same Idempotency-Key and body within 24 hours returns the original order with 200 instead of placing another.
 // @Tags paper
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Client-chosen key, unique per portfolio, that makes the request safe to retry"
 // @Param request body PaperOrderRequest true "Order request"
+// @Success 200 {object} OrderResponse "Order already placed with this Idempotency-Key"
 // @Success 201 {object} OrderResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 422 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
 // @Router /api/v1/paper/orders [post]
 func (h *PaperHandler) CreateOrder(c *gin.Context) {
 	var req PaperOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	portfolioID, err := uuid.Parse(req.PortfolioID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid portfolio_id"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio_id")
 		return
 	}
 
+	// Without an idempotency store the header is ignored.
+	var idempotencyKey, hash string
+	if header := c.GetHeader(IdempotencyKeyHeader); header != "" && h.idempotency != nil {
+		if len(header) > maxIdempotencyKeyLength {
+			respondError(c, http.StatusBadRequest, ErrIdempotencyKeyTooLong)
+			return
+		}
+		idempotencyKey, hash = orderIdempotencyKey(portfolioID, header), requestHash(req)
+		reserved, err := h.idempotency.ReserveIdempotencyKey(c.Request.Context(), idempotencyKey, hash, IdempotencyKeyTTL)
+		if err != nil {
+			respondErrorMessage(c, http.StatusServiceUnavailable, "idempotency store unavailable")
+			return
+		}
+		if !reserved {
+			h.replayOrder(c, idempotencyKey, hash)
+			return
+		}
+	}
+
 	side := model.OrderSide(req.Side)
 	orderType := model.OrderType(req.OrderType)
 
-	order, trade, err := h.service.CreateOrder(portfolioID, req.Symbol, side, orderType, req.Quantity, req.Price)
+	order, trade, err := h.service.CreateOrder(portfolioID, req.Symbol, side, orderType, req.Quantity, req.Price, model.TimeInForce(req.TimeInForce))
 	if err != nil {
+		if idempotencyKey != "" {
+			if err := h.idempotency.DeleteIdempotencyKey(c.Request.Context(), idempotencyKey); err != nil {
+				log.Warn().Err(err).Msg("Failed to release idempotency key")
+			}
+		}
 		switch err {
 		case service.ErrPortfolioNotFound:
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
-		case service.ErrInsufficientFunds, service.ErrInsufficientPosition, service.ErrInvalidQuantity, service.ErrInvalidPrice:
-			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrInsufficientFunds, service.ErrInsufficientPosition, service.ErrInvalidQuantity, service.ErrInvalidPrice, service.ErrInvalidTimeInForce:
+			respondError(c, http.StatusUnprocessableEntity, err)
+		case service.ErrQuotesUnavailable:
+			respondError(c, http.StatusServiceUnavailable, err)
 		default:
-			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to create order"})
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to create order")
 		}
 		return
 	}
 
-	response := struct {
-		Order OrderResponse `json:"order"`
-		Trade TradeResponse `json:"trade"`
-	}{
-		Order: orderToResponse(order),
-		Trade: tradeToResponse(trade),
+	if idempotencyKey != "" {
+		if err := h.idempotency.CompleteIdempotencyKey(c.Request.Context(), idempotencyKey, hash, order.ID.String(), IdempotencyKeyTTL); err != nil {
+			log.Warn().Err(err).Str("order_id", order.ID.String()).Msg("Failed to record idempotency key")
+		}
 	}
 
-	c.JSON(http.StatusCreated, response)
+	h.audit.Record(c, model.AuditActionOrderPlace, map[string]interface{}{
+		"order_id":     order.ID,
+		"portfolio_id": order.PortfolioID,
+		"symbol":       order.Symbol,
+		"side":         order.Side,
+		"order_type":   order.OrderType,
+		"quantity":     order.Quantity,
+		"status":       order.Status,
+	})
+
+	c.JSON(http.StatusCreated, createdOrderResponse(order, trade))
+}
+
+// replayOrder answers a repeated Idempotency-Key with the order the first
+// request placed, provided the request body is the same.
+func (h *PaperHandler) replayOrder(c *gin.Context, idempotencyKey, hash string) {
+	storedHash, orderID, err := h.idempotency.GetIdempotencyKey(c.Request.Context(), idempotencyKey)
+	if err != nil {
+		respondErrorMessage(c, http.StatusServiceUnavailable, "idempotency store unavailable")
+		return
+	}
+	if storedHash != "" && storedHash != hash {
+		respondError(c, http.StatusUnprocessableEntity, ErrIdempotencyKeyReused)
+		return
+	}
+	id, err := uuid.Parse(orderID)
+	if err != nil {
+		respondError(c, http.StatusConflict, ErrIdempotencyKeyInProgress)
+		return
+	}
+
+	order, err := h.service.GetOrder(id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	trades, err := h.service.GetTrades(order.PortfolioID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get trades")
+		return
+	}
+	var trade *model.Trade
+	for i := range trades {
+		if trades[i].OrderID == order.ID {
+			trade = &trades[i]
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, createdOrderResponse(order, trade))
+}
+
+// createdOrderResponse is the body returned for a placed order. Trade is
+// omitted when the order did not fill: a resting limit order or a cancelled
+// IOC/FOK order.
+func createdOrderResponse(order *model.Order, trade *model.Trade) gin.H {
+	response := gin.H{"order": orderToResponse(order)}
+	if trade != nil {
+		response["trade"] = tradeToResponse(trade)
+	}
+	return response
 }
 
 // GetOrder retrieves an order by ID.
@@ -132,44 +278,100 @@ func (h *PaperHandler) CreateOrder(c *gin.Context) {
 func (h *PaperHandler) GetOrder(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid order id"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid order id")
 		return
 	}
 
 	order, err := h.service.GetOrder(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orderToResponse(order))
+}
+
+// CancelOrder cancels a pending order.
+// @Summary Cancel order
+// @Description Cancel a pending paper trading order. Cash reserved for a limit buy is returned to the portfolio.
+// @Tags paper
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/paper/orders/{id} [delete]
+func (h *PaperHandler) CancelOrder(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid order id")
+		return
+	}
+
+	order, err := h.service.CancelOrder(id)
+	if err != nil {
+		switch err {
+		case service.ErrOrderNotFound, service.ErrPortfolioNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrOrderNotPending:
+			respondError(c, http.StatusConflict, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to cancel order")
+		}
 		return
 	}
 
+	h.audit.Record(c, model.AuditActionOrderCancel, map[string]interface{}{
+		"order_id":     order.ID,
+		"portfolio_id": order.PortfolioID,
+		"symbol":       order.Symbol,
+	})
+
 	c.JSON(http.StatusOK, orderToResponse(order))
 }
 
 // ListOrders lists orders for a portfolio.
 // @Summary List orders
-// @Description List all orders for a portfolio
+// @Description List a page of orders for a portfolio, newest first. The total match count is returned in X-Total-Count.
 // @Tags paper
 // @Produce json
 // @Param portfolio_id query string true "Portfolio ID"
+// @Param status query string false "Order status (pending, filled, cancelled, rejected)"
+// @Param from query string false "Earliest creation time, inclusive (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Latest creation time, exclusive (RFC3339, or YYYY-MM-DD to include that day)"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param offset query int false "Number of orders to skip"
 // @Success 200 {array} OrderResponse
 // @Failure 400 {object} ErrorResponse
 // @Router /api/v1/paper/orders [get]
 func (h *PaperHandler) ListOrders(c *gin.Context) {
 	portfolioIDStr := c.Query("portfolio_id")
 	if portfolioIDStr == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "portfolio_id is required"})
+		respondErrorMessage(c, http.StatusBadRequest, "portfolio_id is required")
 		return
 	}
 
 	portfolioID, err := uuid.Parse(portfolioIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid portfolio_id"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio_id")
+		return
+	}
+
+	filter, err := parseHistoryFilter(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	orders, err := h.service.GetOrders(portfolioID)
+	orders, total, err := h.service.ListOrders(portfolioID, model.OrderStatus(c.Query("status")), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to get orders"})
+		switch err {
+		case service.ErrInvalidOrderStatus, service.ErrInvalidDateRange, service.ErrInvalidPagination:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to get orders")
+		}
 		return
 	}
 
@@ -178,6 +380,7 @@ func (h *PaperHandler) ListOrders(c *gin.Context) {
 		response[i] = orderToResponse(&order)
 	}
 
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
 	c.JSON(http.StatusOK, response)
 }
 
@@ -194,19 +397,19 @@ func (h *PaperHandler) ListOrders(c *gin.Context) {
 func (h *PaperHandler) CreatePortfolio(c *gin.Context) {
 	var req CreatePortfolioRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	userID, err := uuid.Parse(req.UserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid user_id"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid user_id")
 		return
 	}
 
 	portfolio, err := h.service.CreatePortfolio(userID, req.Name, req.InitialBalance)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to create portfolio"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to create portfolio")
 		return
 	}
 
@@ -225,13 +428,13 @@ func (h *PaperHandler) CreatePortfolio(c *gin.Context) {
 func (h *PaperHandler) GetPortfolio(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid portfolio id"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio id")
 		return
 	}
 
 	portfolio, err := h.service.GetPortfolio(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -247,14 +450,14 @@ func (h *PaperHandler) GetPortfolio(c *gin.Context) {
 // @Router /api/v1/paper/portfolios [get]
 func (h *PaperHandler) ListPortfolios(c *gin.Context) {
 	userIDStr := c.Query("user_id")
-	
+
 	var portfolios []model.Portfolio
 	var err error
-	
+
 	if userIDStr != "" {
 		userID, parseErr := uuid.Parse(userIDStr)
 		if parseErr != nil {
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid user_id"})
+			respondErrorMessage(c, http.StatusBadRequest, "invalid user_id")
 			return
 		}
 		portfolios, err = h.service.GetUserPortfolios(userID)
@@ -263,7 +466,7 @@ func (h *PaperHandler) ListPortfolios(c *gin.Context) {
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to get portfolios"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get portfolios")
 		return
 	}
 
@@ -285,23 +488,112 @@ func (h *PaperHandler) ListPortfolios(c *gin.Context) {
 func (h *PaperHandler) UpdatePortfolio(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid portfolio id"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio id")
 		return
 	}
 
 	var req UpdatePortfolioRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	portfolio, err := h.service.UpdatePortfolio(id, req.Name)
 	if err != nil {
 		if err == service.ErrPortfolioNotFound {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to update portfolio"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to update portfolio")
+		return
+	}
+
+	c.JSON(http.StatusOK, portfolio)
+}
+
+// UpdateSlippage changes a portfolio's slippage model.
+// @Summary Update portfolio slippage
+// @Description Set the slippage applied to the portfolio's future market orders. fixed_bps charges slippage_bps; volatility charges slippage_vol_factor times the symbol's daily volatility, with slippage_bps as the minimum. Buys fill above and sells below the market price.
+// @Tags paper
+// @Accept json
+// @Produce json
+// @Param id path string true "Portfolio ID"
+// @Param request body UpdateSlippageRequest true "Slippage settings"
+// @Success 200 {object} model.Portfolio
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/paper/portfolios/{id}/slippage [put]
+func (h *PaperHandler) UpdateSlippage(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio id")
+		return
+	}
+
+	var req UpdateSlippageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	portfolio, err := h.service.UpdateSlippage(id, service.SlippageSettings{
+		Model:     model.SlippageModel(req.SlippageModel),
+		Bps:       req.SlippageBps,
+		VolFactor: req.SlippageVolFactor,
+	})
+	if err != nil {
+		switch err {
+		case service.ErrPortfolioNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrInvalidSlippageModel, service.ErrInvalidSlippage:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to update slippage")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, portfolio)
+}
+
+// UpdateFees changes a portfolio's fee model.
+// @Summary Update portfolio fees
+// @Description Set the commission charged on the portfolio's future fills. flat charges fee_amount per trade, per_share charges it per share and bps charges fee_amount basis points of the fill value. Fees are deducted from cash on top of the fill value and recorded on each trade.
+// @Tags paper
+// @Accept json
+// @Produce json
+// @Param id path string true "Portfolio ID"
+// @Param request body UpdateFeesRequest true "Fee settings"
+// @Success 200 {object} model.Portfolio
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/paper/portfolios/{id}/fees [put]
+func (h *PaperHandler) UpdateFees(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio id")
+		return
+	}
+
+	var req UpdateFeesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	portfolio, err := h.service.UpdateFees(id, service.FeeSettings{
+		Model:  model.FeeModel(req.FeeModel),
+		Amount: req.FeeAmount,
+	})
+	if err != nil {
+		switch err {
+		case service.ErrPortfolioNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrInvalidFeeModel, service.ErrInvalidFee:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to update fees")
+		}
 		return
 	}
 
@@ -319,22 +611,76 @@ func (h *PaperHandler) UpdatePortfolio(c *gin.Context) {
 func (h *PaperHandler) DeletePortfolio(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid portfolio id"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio id")
 		return
 	}
 
 	if err := h.service.DeletePortfolio(id); err != nil {
 		if err == service.ErrPortfolioNotFound {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			respondError(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to delete portfolio"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to delete portfolio")
 		return
 	}
 
+	h.audit.Record(c, model.AuditActionPortfolioDelete, map[string]interface{}{
+		"portfolio_id": id,
+	})
+
 	c.Status(http.StatusNoContent)
 }
 
+// RefreshPortfolio refreshes a portfolio's position prices and values it.
+// @Summary Refresh portfolio prices
+// @Description Update each position's current price (and day change, when live quotes are configured) and return the portfolio's cash, market value and refreshed positions
+// @Tags paper
+// @Produce json
+// @Param id path string true "Portfolio ID"
+// @Success 200 {object} PortfolioValueResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/paper/portfolios/{id}/refresh [post]
+func (h *PaperHandler) RefreshPortfolio(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio id")
+		return
+	}
+
+	if err := h.service.RefreshPrices(id); err != nil {
+		switch err {
+		case service.ErrPortfolioNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrQuotesUnavailable:
+			respondError(c, http.StatusServiceUnavailable, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to refresh portfolio")
+		}
+		return
+	}
+
+	cash, marketValue, err := h.service.GetPortfolioValue(id)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to value portfolio")
+		return
+	}
+	positions, err := h.service.GetPositions(id)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get positions")
+		return
+	}
+
+	c.JSON(http.StatusOK, PortfolioValueResponse{
+		PortfolioID: id,
+		CashBalance: cash,
+		MarketValue: marketValue,
+		TotalValue:  cash + marketValue,
+		Positions:   positions,
+	})
+}
+
 // GetPositions lists positions for a portfolio.
 // @Summary List positions
 // @Description List all positions for a portfolio
@@ -347,19 +693,19 @@ func (h *PaperHandler) DeletePortfolio(c *gin.Context) {
 func (h *PaperHandler) GetPositions(c *gin.Context) {
 	portfolioIDStr := c.Query("portfolio_id")
 	if portfolioIDStr == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "portfolio_id is required"})
+		respondErrorMessage(c, http.StatusBadRequest, "portfolio_id is required")
 		return
 	}
 
 	portfolioID, err := uuid.Parse(portfolioIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid portfolio_id"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio_id")
 		return
 	}
 
 	positions, err := h.service.GetPositions(portfolioID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to get positions"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get positions")
 		return
 	}
 
@@ -378,13 +724,13 @@ func (h *PaperHandler) GetPositions(c *gin.Context) {
 func (h *PaperHandler) GetPosition(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid position id"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid position id")
 		return
 	}
 
 	position, err := h.service.GetPosition(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -393,29 +739,44 @@ func (h *PaperHandler) GetPosition(c *gin.Context) {
 
 // GetTrades lists trades for a portfolio.
 // @Summary List trades
-// @Description List all trades for a portfolio
+// @Description List a page of trades for a portfolio, newest first. The total match count is returned in X-Total-Count.
 // @Tags paper
 // @Produce json
 // @Param portfolio_id query string true "Portfolio ID"
+// @Param from query string false "Earliest execution time, inclusive (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Latest execution time, exclusive (RFC3339, or YYYY-MM-DD to include that day)"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param offset query int false "Number of trades to skip"
 // @Success 200 {array} TradeResponse
 // @Failure 400 {object} ErrorResponse
 // @Router /api/v1/paper/trades [get]
 func (h *PaperHandler) GetTrades(c *gin.Context) {
 	portfolioIDStr := c.Query("portfolio_id")
 	if portfolioIDStr == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "portfolio_id is required"})
+		respondErrorMessage(c, http.StatusBadRequest, "portfolio_id is required")
 		return
 	}
 
 	portfolioID, err := uuid.Parse(portfolioIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid portfolio_id"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio_id")
 		return
 	}
 
-	trades, err := h.service.GetTrades(portfolioID)
+	filter, err := parseHistoryFilter(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to get trades"})
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	trades, total, err := h.service.ListTrades(portfolioID, filter)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidDateRange, service.ErrInvalidPagination:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to get trades")
+		}
 		return
 	}
 
@@ -424,9 +785,249 @@ func (h *PaperHandler) GetTrades(c *gin.Context) {
 		response[i] = tradeToResponse(&trade)
 	}
 
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
 	c.JSON(http.StatusOK, response)
 }
 
+// GetPositionPnL returns the P&L attribution for a position.
+// @Summary Get position P&L
+// @Description Break down a position's P&L over its holding period into price change, dividends, and fees
+// @Tags paper
+// @Produce json
+// @Param id path string true "Position ID"
+// @Success 200 {object} service.PositionPnL
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/paper/positions/{id}/pnl [get]
+func (h *PaperHandler) GetPositionPnL(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid position id")
+		return
+	}
+
+	pnl, err := h.service.GetPositionPnL(id)
+	if err != nil {
+		if err == service.ErrPositionNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to compute position P&L")
+		return
+	}
+
+	c.JSON(http.StatusOK, pnl)
+}
+
+// PortfolioPnLResponse represents a portfolio's realized P&L.
+type PortfolioPnLResponse struct {
+	PortfolioID string  `json:"portfolio_id"`
+	RealizedPL  float64 `json:"realized_pl"`
+}
+
+// GetPortfolioPnL returns the P&L realized by a portfolio's sells.
+// @Summary Get portfolio realized P&L
+// @Description Sum the realized P&L recorded on every sell in the portfolio, measured against the position's average cost at the time
+// @Tags paper
+// @Produce json
+// @Param id path string true "Portfolio ID"
+// @Success 200 {object} PortfolioPnLResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/paper/portfolios/{id}/pnl [get]
+func (h *PaperHandler) GetPortfolioPnL(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio id")
+		return
+	}
+
+	realized, err := h.service.GetRealizedPL(id)
+	if err != nil {
+		if err == service.ErrPortfolioNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to compute portfolio P&L")
+		return
+	}
+
+	c.JSON(http.StatusOK, PortfolioPnLResponse{PortfolioID: id.String(), RealizedPL: realized})
+}
+
+// BacktestEquityResponse is a stored backtest's equity curve.
+type BacktestEquityResponse struct {
+	BacktestID  string              `json:"backtest_id"`
+	EquityCurve []model.EquityPoint `json:"equity_curve"`
+}
+
+// GetBacktestEquity returns just the equity curve of a stored backtest.
+// @Summary Get backtest equity curve
+// @Description Get the account value at the close of every bar of a backtest, starting with the initial capital
+// @Tags paper
+// @Produce json
+// @Param id path string true "Backtest ID"
+// @Success 200 {object} BacktestEquityResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/paper/backtest/{id}/equity [get]
+func (h *PaperHandler) GetBacktestEquity(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid backtest id")
+		return
+	}
+	if h.backtests == nil {
+		respondError(c, http.StatusServiceUnavailable, service.ErrBacktestsUnavailable)
+		return
+	}
+
+	result, err := h.backtests.GetResult(id)
+	if err != nil {
+		if err == service.ErrBacktestNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get backtest")
+		return
+	}
+
+	c.JSON(http.StatusOK, BacktestEquityResponse{BacktestID: id.String(), EquityCurve: result.EquityCurve})
+}
+
+// OpenOrderResponse represents a working order with its distance to trigger.
+type OpenOrderResponse struct {
+	OrderResponse
+	CurrentPrice    float64  `json:"current_price"`
+	Distance        *float64 `json:"distance,omitempty"`
+	DistancePercent *float64 `json:"distance_percent,omitempty"`
+}
+
+// GetOpenOrders lists the working orders for a portfolio.
+// @Summary List open orders
+// @Description List pending orders for a portfolio sorted by proximity to their trigger price. Orders whose symbol cannot be quoted have no distance and sort last.
+// @Tags paper
+// @Produce json
+// @Param id path string true "Portfolio ID"
+// @Success 200 {array} OpenOrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/paper/portfolios/{id}/open-orders [get]
+func (h *PaperHandler) GetOpenOrders(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio id")
+		return
+	}
+
+	openOrders, err := h.service.GetOpenOrders(id)
+	if err != nil {
+		if err == service.ErrPortfolioNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get open orders")
+		return
+	}
+
+	response := make([]OpenOrderResponse, len(openOrders))
+	for i, open := range openOrders {
+		response[i] = OpenOrderResponse{
+			OrderResponse:   orderToResponse(&open.Order),
+			CurrentPrice:    open.CurrentPrice,
+			Distance:        open.Distance,
+			DistancePercent: open.DistancePercent,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCalendarPnL returns a portfolio's realized P&L by trading day.
+// @Summary Get calendar P&L
+// @Description Realized P&L per trading day for a calendar year, with best and worst days, win/loss day counts, and the current streak
+// @Tags paper
+// @Produce json
+// @Param id path string true "Portfolio ID"
+// @Param year query int false "Calendar year (defaults to the current year)"
+// @Success 200 {object} service.CalendarPnL
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/paper/portfolios/{id}/analytics/calendar [get]
+func (h *PaperHandler) GetCalendarPnL(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio id")
+		return
+	}
+
+	year := 0
+	if raw := c.Query("year"); raw != "" {
+		if year, err = strconv.Atoi(raw); err != nil {
+			respondError(c, http.StatusBadRequest, service.ErrInvalidYear)
+			return
+		}
+	}
+
+	calendar, err := h.service.GetCalendarPnL(id, year)
+	if err != nil {
+		switch err {
+		case service.ErrPortfolioNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrInvalidYear:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to compute calendar P&L")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+// GetPortfolioRisk returns a portfolio's historical VaR and CVaR.
+// @Summary Get portfolio risk
+// @Description Historical Value at Risk and Conditional VaR of the daily returns of the portfolio's equity curve, in percent and applied to its current equity. With fewer than two returns every figure is zero
+// @Tags paper
+// @Produce json
+// @Param id path string true "Portfolio ID"
+// @Param confidence query number false "Confidence level in percent, at least 50 and below 100 (defaults to 95)"
+// @Success 200 {object} service.RiskMetrics
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/paper/portfolios/{id}/risk [get]
+func (h *PaperHandler) GetPortfolioRisk(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio id")
+		return
+	}
+
+	confidence := 0.0
+	if raw := c.Query("confidence"); raw != "" {
+		if confidence, err = strconv.ParseFloat(raw, 64); err != nil {
+			respondError(c, http.StatusBadRequest, service.ErrInvalidConfidence)
+			return
+		}
+	}
+
+	risk, err := h.service.ComputeRisk(id, confidence)
+	if err != nil {
+		switch err {
+		case service.ErrPortfolioNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrInvalidConfidence:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to compute portfolio risk")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, risk)
+}
+
 // RegisterPaperRoutes registers paper trading routes.
 func (h *PaperHandler) RegisterPaperRoutes(rg *gin.RouterGroup) {
 	paper := rg.Group("/paper")
@@ -437,18 +1038,30 @@ func (h *PaperHandler) RegisterPaperRoutes(rg *gin.RouterGroup) {
 		paper.GET("/portfolios/:id", h.GetPortfolio)
 		paper.PUT("/portfolios/:id", h.UpdatePortfolio)
 		paper.DELETE("/portfolios/:id", h.DeletePortfolio)
+		paper.PUT("/portfolios/:id/slippage", h.UpdateSlippage)
+		paper.PUT("/portfolios/:id/fees", h.UpdateFees)
+		paper.GET("/portfolios/:id/open-orders", h.GetOpenOrders)
+		paper.POST("/portfolios/:id/refresh", h.RefreshPortfolio)
+		paper.GET("/portfolios/:id/pnl", h.GetPortfolioPnL)
+		paper.GET("/portfolios/:id/risk", h.GetPortfolioRisk)
+		paper.GET("/portfolios/:id/analytics/calendar", h.GetCalendarPnL)
 
 		// Positions
 		paper.GET("/positions", h.GetPositions)
 		paper.GET("/positions/:id", h.GetPosition)
+		paper.GET("/positions/:id/pnl", h.GetPositionPnL)
 
 		// Orders
 		paper.POST("/orders", h.CreateOrder)
 		paper.GET("/orders", h.ListOrders)
 		paper.GET("/orders/:id", h.GetOrder)
+		paper.DELETE("/orders/:id", h.CancelOrder)
 
 		// Trades
 		paper.GET("/trades", h.GetTrades)
+
+		// Backtests
+		paper.GET("/backtest/:id/equity", h.GetBacktestEquity)
 	}
 }
 
@@ -464,6 +1077,7 @@ func orderToResponse(order *model.Order) OrderResponse {
 		Quantity:    order.Quantity,
 		Price:       order.Price,
 		Status:      string(order.Status),
+		TimeInForce: string(order.TimeInForce),
 		CreatedAt:   order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:   order.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
@@ -475,14 +1089,67 @@ func orderToResponse(order *model.Order) OrderResponse {
 
 func tradeToResponse(trade *model.Trade) TradeResponse {
 	return TradeResponse{
-		ID:          trade.ID.String(),
-		PortfolioID: trade.PortfolioID.String(),
-		OrderID:     trade.OrderID.String(),
-		Symbol:      trade.Symbol,
-		Side:        string(trade.Side),
-		Quantity:    trade.Quantity,
-		Price:       trade.Price,
-		Total:       trade.Total,
-		ExecutedAt:  trade.ExecutedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:             trade.ID.String(),
+		PortfolioID:    trade.PortfolioID.String(),
+		OrderID:        trade.OrderID.String(),
+		Symbol:         trade.Symbol,
+		Side:           string(trade.Side),
+		Quantity:       trade.Quantity,
+		Price:          trade.Price,
+		Total:          trade.Total,
+		ReferencePrice: trade.ReferencePrice,
+		Slippage:       trade.Slippage,
+		Fees:           trade.Fees,
+		RealizedPL:     trade.RealizedPL,
+		ExecutedAt:     trade.ExecutedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// parseHistoryFilter reads the from, to, limit and offset query parameters.
+// A date-only to value includes the whole of that day.
+func parseHistoryFilter(c *gin.Context) (repository.HistoryFilter, error) {
+	var filter repository.HistoryFilter
+
+	if v := c.Query("from"); v != "" {
+		from, _, err := parseHistoryTime(v)
+		if err != nil {
+			return filter, errors.New("invalid from: use RFC3339 or YYYY-MM-DD")
+		}
+		filter.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, dateOnly, err := parseHistoryTime(v)
+		if err != nil {
+			return filter, errors.New("invalid to: use RFC3339 or YYYY-MM-DD")
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("invalid limit")
+		}
+		filter.Limit = limit
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("invalid offset")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
+
+// parseHistoryTime parses an RFC3339 timestamp or a YYYY-MM-DD date in UTC.
+func parseHistoryTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
 	}
+	t, err := time.Parse(historyDateLayout, value)
+	return t, true, err
 }
@@ -2,16 +2,18 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
-	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
 	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // mockPaperTradingService is a mock implementation of PaperTradingService.
@@ -40,6 +42,7 @@ func (m *mockPaperTradingService) CreatePortfolio(userID uuid.UUID, name string,
 		UserID:      userID,
 		Name:        name,
 		CashBalance: initialBalance,
+		InitialCash: initialBalance,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -106,7 +109,46 @@ func (m *mockPaperTradingService) GetPosition(id uuid.UUID) (*model.Position, er
 	return nil, service.ErrPositionNotFound
 }
 
-func (m *mockPaperTradingService) CreateOrder(portfolioID uuid.UUID, symbol string, side model.OrderSide, orderType model.OrderType, quantity int64, price float64) (*model.Order, *model.Trade, error) {
+func (m *mockPaperTradingService) GetPositionPnL(id uuid.UUID) (*service.PositionPnL, error) {
+	p, ok := m.positions[id]
+	if !ok {
+		return nil, service.ErrPositionNotFound
+	}
+	unrealized := float64(p.Quantity) * (150.00 - p.AvgCost)
+	return &service.PositionPnL{
+		PositionID:    p.ID,
+		Symbol:        p.Symbol,
+		Quantity:      p.Quantity,
+		AvgCost:       p.AvgCost,
+		CurrentPrice:  150.00,
+		UnrealizedPL:  unrealized,
+		PriceChangePL: unrealized,
+		TotalPL:       unrealized,
+	}, nil
+}
+
+func (m *mockPaperTradingService) RefreshPrices(portfolioID uuid.UUID) error {
+	if _, ok := m.portfolios[portfolioID]; !ok {
+		return service.ErrPortfolioNotFound
+	}
+	return nil
+}
+
+func (m *mockPaperTradingService) GetPortfolioValue(portfolioID uuid.UUID) (float64, float64, error) {
+	p, ok := m.portfolios[portfolioID]
+	if !ok {
+		return 0, 0, service.ErrPortfolioNotFound
+	}
+	var marketValue float64
+	for _, position := range m.positions {
+		if position.PortfolioID == portfolioID {
+			marketValue += float64(position.Quantity) * position.CurrentPrice
+		}
+	}
+	return p.CashBalance, marketValue, nil
+}
+
+func (m *mockPaperTradingService) CreateOrder(portfolioID uuid.UUID, symbol string, side model.OrderSide, orderType model.OrderType, quantity int64, price float64, timeInForce model.TimeInForce) (*model.Order, *model.Trade, error) {
 	portfolio, ok := m.portfolios[portfolioID]
 	if !ok {
 		return nil, nil, service.ErrPortfolioNotFound
@@ -124,6 +166,27 @@ func (m *mockPaperTradingService) CreateOrder(portfolioID uuid.UUID, symbol stri
 		return nil, nil, service.ErrInvalidPrice
 	}
 
+	if timeInForce == "" {
+		timeInForce = model.TimeInForceDay
+	}
+
+	// Non-marketable IOC buy limits are cancelled without a trade.
+	if orderType == model.OrderTypeLimit && timeInForce == model.TimeInForceIOC && side == model.OrderSideBuy && price < 150.00 {
+		order := &model.Order{
+			ID:          uuid.New(),
+			PortfolioID: portfolioID,
+			Symbol:      symbol,
+			Side:        side,
+			OrderType:   orderType,
+			Quantity:    quantity,
+			Price:       price,
+			Status:      model.OrderStatusCancelled,
+			TimeInForce: timeInForce,
+		}
+		m.orders[order.ID] = order
+		return order, nil, nil
+	}
+
 	total := float64(quantity) * executionPrice
 
 	if side == model.OrderSideBuy {
@@ -157,6 +220,7 @@ func (m *mockPaperTradingService) CreateOrder(portfolioID uuid.UUID, symbol stri
 		Quantity:    quantity,
 		Price:       executionPrice,
 		Status:      model.OrderStatusFilled,
+		TimeInForce: timeInForce,
 		FilledAt:    &now,
 		CreatedAt:   now,
 		UpdatedAt:   now,
@@ -196,6 +260,111 @@ func (m *mockPaperTradingService) GetOrders(portfolioID uuid.UUID) ([]model.Orde
 	return result, nil
 }
 
+func (m *mockPaperTradingService) GetOrdersByStatus(portfolioID uuid.UUID, status model.OrderStatus) ([]model.Order, error) {
+	if !status.IsValid() {
+		return nil, service.ErrInvalidOrderStatus
+	}
+	var result []model.Order
+	for _, o := range m.orders {
+		if o.PortfolioID == portfolioID && o.Status == status {
+			result = append(result, *o)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockPaperTradingService) ListOrders(portfolioID uuid.UUID, status model.OrderStatus, filter repository.HistoryFilter) ([]model.Order, int64, error) {
+	if status != "" && !status.IsValid() {
+		return nil, 0, service.ErrInvalidOrderStatus
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, service.ErrInvalidDateRange
+	}
+	var result []model.Order
+	for _, o := range m.orders {
+		if o.PortfolioID == portfolioID && (status == "" || o.Status == status) {
+			result = append(result, *o)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (m *mockPaperTradingService) GetOpenOrders(portfolioID uuid.UUID) ([]service.OpenOrder, error) {
+	if _, ok := m.portfolios[portfolioID]; !ok {
+		return nil, service.ErrPortfolioNotFound
+	}
+	orders, _ := m.GetOrdersByStatus(portfolioID, model.OrderStatusPending)
+	result := make([]service.OpenOrder, len(orders))
+	for i, o := range orders {
+		distance := o.Price - 150.00
+		result[i] = service.OpenOrder{Order: o, CurrentPrice: 150.00, Distance: &distance}
+	}
+	return result, nil
+}
+
+func (m *mockPaperTradingService) ExpireOrders(now time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *mockPaperTradingService) GetRealizedPL(portfolioID uuid.UUID) (float64, error) {
+	if _, ok := m.portfolios[portfolioID]; !ok {
+		return 0, service.ErrPortfolioNotFound
+	}
+	var realized float64
+	for _, trade := range m.trades {
+		if trade.PortfolioID == portfolioID {
+			realized += trade.RealizedPL
+		}
+	}
+	return realized, nil
+}
+
+func (m *mockPaperTradingService) ComputePerformance(portfolioID uuid.UUID) (service.PerformanceMetrics, error) {
+	var metrics service.PerformanceMetrics
+	portfolio, ok := m.portfolios[portfolioID]
+	if !ok {
+		return metrics, service.ErrPortfolioNotFound
+	}
+	wins := 0
+	for _, trade := range m.trades {
+		if trade.PortfolioID != portfolioID || trade.Side != model.OrderSideSell {
+			continue
+		}
+		metrics.ClosedTrades++
+		metrics.RealizedPL += trade.RealizedPL
+		if trade.RealizedPL > 0 {
+			wins++
+		}
+	}
+	for _, position := range m.positions {
+		if position.PortfolioID == portfolioID {
+			metrics.UnrealizedPL += (position.CurrentPrice - position.AvgCost) * float64(position.Quantity)
+		}
+	}
+	metrics.TotalReturn = metrics.RealizedPL + metrics.UnrealizedPL
+	metrics.TotalReturnPercent = metrics.TotalReturn / portfolio.InitialCash * 100
+	if metrics.ClosedTrades > 0 {
+		metrics.WinRate = float64(wins) / float64(metrics.ClosedTrades) * 100
+	}
+	return metrics, nil
+}
+
+func (m *mockPaperTradingService) ProcessPendingOrders(portfolioID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func (m *mockPaperTradingService) CancelOrder(id uuid.UUID) (*model.Order, error) {
+	order, ok := m.orders[id]
+	if !ok {
+		return nil, service.ErrOrderNotFound
+	}
+	if order.Status != model.OrderStatusPending {
+		return nil, service.ErrOrderNotPending
+	}
+	order.Status = model.OrderStatusCancelled
+	return order, nil
+}
+
 func (m *mockPaperTradingService) GetTrades(portfolioID uuid.UUID) ([]model.Trade, error) {
 	var result []model.Trade
 	for _, t := range m.trades {
@@ -206,6 +375,89 @@ func (m *mockPaperTradingService) GetTrades(portfolioID uuid.UUID) ([]model.Trad
 	return result, nil
 }
 
+func (m *mockPaperTradingService) ComputeRisk(portfolioID uuid.UUID, confidence float64) (service.RiskMetrics, error) {
+	if confidence == 0 {
+		confidence = service.DefaultRiskConfidence
+	}
+	if confidence < 50 || confidence >= 100 {
+		return service.RiskMetrics{}, service.ErrInvalidConfidence
+	}
+	portfolio, ok := m.portfolios[portfolioID]
+	if !ok {
+		return service.RiskMetrics{}, service.ErrPortfolioNotFound
+	}
+	return service.RiskMetrics{
+		Confidence:            confidence,
+		Observations:          20,
+		ValueAtRiskPercent:    2,
+		ConditionalVaRPercent: 3,
+		Equity:                portfolio.CashBalance,
+		ValueAtRisk:           portfolio.CashBalance * 0.02,
+		ConditionalVaR:        portfolio.CashBalance * 0.03,
+	}, nil
+}
+
+func (m *mockPaperTradingService) GetCalendarPnL(portfolioID uuid.UUID, year int) (*service.CalendarPnL, error) {
+	if year != 0 && (year < 1970 || year > 9999) {
+		return nil, service.ErrInvalidYear
+	}
+	if _, ok := m.portfolios[portfolioID]; !ok {
+		return nil, service.ErrPortfolioNotFound
+	}
+	day := service.DailyPnL{Date: "2026-03-02", RealizedPL: 125, TradeCount: 2}
+	return &service.CalendarPnL{
+		PortfolioID:     portfolioID,
+		Year:            year,
+		Days:            []service.DailyPnL{day},
+		TotalRealizedPL: day.RealizedPL,
+		BestDay:         &day,
+		WorstDay:        &day,
+		WinningDays:     1,
+		CurrentStreak:   1,
+	}, nil
+}
+
+func (m *mockPaperTradingService) ListTrades(portfolioID uuid.UUID, filter repository.HistoryFilter) ([]model.Trade, int64, error) {
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return nil, 0, service.ErrInvalidPagination
+	}
+	trades, _ := m.GetTrades(portfolioID)
+	return trades, int64(len(trades)), nil
+}
+
+func (m *mockPaperTradingService) UpdateSlippage(id uuid.UUID, settings service.SlippageSettings) (*model.Portfolio, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	p, ok := m.portfolios[id]
+	if !ok {
+		return nil, service.ErrPortfolioNotFound
+	}
+	p.SlippageModel = settings.Model
+	p.SlippageBps = settings.Bps
+	p.SlippageVolFactor = settings.VolFactor
+	return p, nil
+}
+
+func (m *mockPaperTradingService) UpdateFees(id uuid.UUID, settings service.FeeSettings) (*model.Portfolio, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	p, ok := m.portfolios[id]
+	if !ok {
+		return nil, service.ErrPortfolioNotFound
+	}
+	p.FeeModel = settings.Model
+	p.FeeAmount = settings.Amount
+	return p, nil
+}
+
+func (m *mockPaperTradingService) SetVolatilitySource(source service.VolatilitySource) {}
+
+func (m *mockPaperTradingService) SetPriceHistory(history service.PriceHistorySource) {}
+
+func (m *mockPaperTradingService) SetMarkToMarket(markToMarket service.MarkToMarketService) {}
+
 func setupPaperHandler() (*gin.Engine, *mockPaperTradingService) {
 	gin.SetMode(gin.TestMode)
 	mockService := newMockPaperTradingService()
@@ -373,6 +625,82 @@ func TestPaperHandler_UpdatePortfolio(t *testing.T) {
 	})
 }
 
+func TestPaperHandler_UpdateSlippage(t *testing.T) {
+	router, mockService := setupPaperHandler()
+	portfolio, _ := mockService.CreatePortfolio(uuid.New(), "Slippage", 100000)
+
+	tests := []struct {
+		name           string
+		portfolioID    string
+		body           string
+		expectedStatus int
+	}{
+		{"fixed bps", portfolio.ID.String(), `{"slippage_model":"fixed_bps","slippage_bps":5}`, http.StatusOK},
+		{"volatility", portfolio.ID.String(), `{"slippage_model":"volatility","slippage_bps":2,"slippage_vol_factor":0.1}`, http.StatusOK},
+		{"unknown model", portfolio.ID.String(), `{"slippage_model":"random"}`, http.StatusBadRequest},
+		{"bps out of range", portfolio.ID.String(), `{"slippage_model":"fixed_bps","slippage_bps":5000}`, http.StatusBadRequest},
+		{"negative vol factor", portfolio.ID.String(), `{"slippage_model":"volatility","slippage_vol_factor":-1}`, http.StatusBadRequest},
+		{"invalid id", "not-a-uuid", `{"slippage_model":"none"}`, http.StatusBadRequest},
+		{"unknown portfolio", uuid.New().String(), `{"slippage_model":"none"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPut, "/api/v1/paper/portfolios/"+tt.portfolioID+"/slippage", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if portfolio.SlippageModel != model.SlippageModelVolatility || portfolio.SlippageVolFactor != 0.1 {
+		t.Errorf("Expected volatility model with factor 0.1, got %s with %v", portfolio.SlippageModel, portfolio.SlippageVolFactor)
+	}
+}
+
+func TestPaperHandler_UpdateFees(t *testing.T) {
+	router, mockService := setupPaperHandler()
+	portfolio, _ := mockService.CreatePortfolio(uuid.New(), "Fees", 100000)
+
+	tests := []struct {
+		name           string
+		portfolioID    string
+		body           string
+		expectedStatus int
+	}{
+		{"flat", portfolio.ID.String(), `{"fee_model":"flat","fee_amount":1}`, http.StatusOK},
+		{"bps", portfolio.ID.String(), `{"fee_model":"bps","fee_amount":5}`, http.StatusOK},
+		{"unknown model", portfolio.ID.String(), `{"fee_model":"random"}`, http.StatusBadRequest},
+		{"negative amount", portfolio.ID.String(), `{"fee_model":"per_share","fee_amount":-0.01}`, http.StatusBadRequest},
+		{"bps out of range", portfolio.ID.String(), `{"fee_model":"bps","fee_amount":5000}`, http.StatusBadRequest},
+		{"invalid id", "not-a-uuid", `{"fee_model":"none"}`, http.StatusBadRequest},
+		{"unknown portfolio", uuid.New().String(), `{"fee_model":"none"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPut, "/api/v1/paper/portfolios/"+tt.portfolioID+"/fees", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if portfolio.FeeModel != model.FeeModelBps || portfolio.FeeAmount != 5 {
+		t.Errorf("Expected bps model with amount 5, got %s with %v", portfolio.FeeModel, portfolio.FeeAmount)
+	}
+}
+
 func TestPaperHandler_DeletePortfolio(t *testing.T) {
 	router, mockService := setupPaperHandler()
 
@@ -469,6 +797,31 @@ func TestPaperHandler_CreateOrder(t *testing.T) {
 			},
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name: "non-marketable IOC limit order is cancelled",
+			body: PaperOrderRequest{
+				PortfolioID: portfolio.ID.String(),
+				Symbol:      "AAPL",
+				Side:        "buy",
+				OrderType:   "limit",
+				Quantity:    5,
+				Price:       100.00,
+				TimeInForce: "IOC",
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "invalid time in force",
+			body: PaperOrderRequest{
+				PortfolioID: portfolio.ID.String(),
+				Symbol:      "AAPL",
+				Side:        "buy",
+				OrderType:   "market",
+				Quantity:    10,
+				TimeInForce: "WEEK",
+			},
+			wantStatus: http.StatusBadRequest,
+		},
 		{
 			name: "non-existent portfolio",
 			body: PaperOrderRequest{
@@ -524,13 +877,204 @@ func TestPaperHandler_CreateOrder_InsufficientFunds(t *testing.T) {
 	}
 }
 
+// mockIdempotencyStore is an in-memory IdempotencyStore.
+type mockIdempotencyStore struct {
+	records map[string][2]string
+}
+
+func (m *mockIdempotencyStore) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, ttl time.Duration) (bool, error) {
+	if _, ok := m.records[key]; ok {
+		return false, nil
+	}
+	m.records[key] = [2]string{requestHash, ""}
+	return true, nil
+}
+
+func (m *mockIdempotencyStore) GetIdempotencyKey(ctx context.Context, key string) (string, string, error) {
+	record := m.records[key]
+	return record[0], record[1], nil
+}
+
+func (m *mockIdempotencyStore) CompleteIdempotencyKey(ctx context.Context, key, requestHash, resourceID string, ttl time.Duration) error {
+	m.records[key] = [2]string{requestHash, resourceID}
+	return nil
+}
+
+func (m *mockIdempotencyStore) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	delete(m.records, key)
+	return nil
+}
+
+func TestPaperHandler_CreateOrder_IdempotencyKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := newMockPaperTradingService()
+	store := &mockIdempotencyStore{records: make(map[string][2]string)}
+	handler := NewPaperHandler(mockService)
+	handler.SetIdempotencyStore(store)
+	router := gin.New()
+	handler.RegisterPaperRoutes(router.Group("/api/v1"))
+
+	portfolio, _ := mockService.CreatePortfolio(uuid.New(), "Test Portfolio", 100000)
+	other, _ := mockService.CreatePortfolio(uuid.New(), "Other Portfolio", 100000)
+	placeOrder := func(key, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/paper/orders", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(IdempotencyKeyHeader, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	orderID := func(w *httptest.ResponseRecorder) string {
+		var resp struct {
+			Order OrderResponse  `json:"order"`
+			Trade *TradeResponse `json:"trade"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if resp.Trade == nil || resp.Trade.OrderID != resp.Order.ID {
+			t.Errorf("Expected the order's fill in the response, got %+v", resp.Trade)
+		}
+		return resp.Order.ID
+	}
+
+	body := `{"portfolio_id":"` + portfolio.ID.String() + `","symbol":"AAPL","side":"buy","order_type":"market","quantity":10}`
+	first := placeOrder("retry-1", body)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, first.Code, first.Body.String())
+	}
+	firstID := orderID(first)
+
+	t.Run("repeat with the same body", func(t *testing.T) {
+		// Formatting differences do not make a different request
+		w := placeOrder("retry-1", `{"symbol":"AAPL", "portfolio_id":"`+portfolio.ID.String()+`", "side":"buy", "order_type":"market", "quantity":10}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if id := orderID(w); id != firstID {
+			t.Errorf("Expected the original order %s, got %s", firstID, id)
+		}
+		if len(mockService.orders) != 1 {
+			t.Errorf("Expected one order placed, got %d", len(mockService.orders))
+		}
+	})
+
+	t.Run("repeat with a different body", func(t *testing.T) {
+		w := placeOrder("retry-1", `{"portfolio_id":"`+portfolio.ID.String()+`","symbol":"AAPL","side":"buy","order_type":"market","quantity":20}`)
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		}
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if resp.Code != CodeIdempotencyKeyReused {
+			t.Errorf("Expected code %q, got %q", CodeIdempotencyKeyReused, resp.Code)
+		}
+		if len(mockService.orders) != 1 {
+			t.Errorf("Expected no new order, got %d orders", len(mockService.orders))
+		}
+	})
+
+	t.Run("same key in another portfolio", func(t *testing.T) {
+		w := placeOrder("retry-1", `{"portfolio_id":"`+other.ID.String()+`","symbol":"AAPL","side":"buy","order_type":"market","quantity":10}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		store.records[orderIdempotencyKey(portfolio.ID, "retry-2")] = [2]string{requestHash(PaperOrderRequest{PortfolioID: portfolio.ID.String(), Symbol: "AAPL", Side: "buy", OrderType: "market", Quantity: 10}), ""}
+		w := placeOrder("retry-2", body)
+		if w.Code != http.StatusConflict {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusConflict, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("failed order frees the key", func(t *testing.T) {
+		invalid := `{"portfolio_id":"` + portfolio.ID.String() + `","symbol":"AAPL","side":"buy","order_type":"limit","quantity":10}`
+		if w := placeOrder("retry-3", invalid); w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		}
+		if w := placeOrder("retry-3", body); w.Code != http.StatusCreated {
+			t.Errorf("Expected a retry after a failure to place the order, got %d. Body: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestPaperHandler_CancelOrder(t *testing.T) {
+	router, mockService := setupPaperHandler()
+
+	pending := &model.Order{ID: uuid.New(), Symbol: "AAPL", Side: model.OrderSideBuy, OrderType: model.OrderTypeLimit, Quantity: 1, Price: 140, Status: model.OrderStatusPending}
+	filled := &model.Order{ID: uuid.New(), Symbol: "AAPL", Side: model.OrderSideBuy, OrderType: model.OrderTypeMarket, Quantity: 1, Price: 150, Status: model.OrderStatusFilled}
+	mockService.orders[pending.ID] = pending
+	mockService.orders[filled.ID] = filled
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"pending order", pending.ID.String(), http.StatusOK},
+		{"already filled", filled.ID.String(), http.StatusConflict},
+		{"unknown order", uuid.New().String(), http.StatusNotFound},
+		{"invalid order ID", "not-a-uuid", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodDelete, "/api/v1/paper/orders/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if pending.Status != model.OrderStatusCancelled {
+		t.Errorf("Expected pending order to be cancelled, got %s", pending.Status)
+	}
+}
+
+func TestPaperHandler_GetPortfolioPnL(t *testing.T) {
+	router, mockService := setupPaperHandler()
+	portfolio, _ := mockService.CreatePortfolio(uuid.New(), "Test Portfolio", 100000)
+	for _, realized := range []float64{200, -50} {
+		trade := &model.Trade{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Side: model.OrderSideSell, RealizedPL: realized}
+		mockService.trades[trade.ID] = trade
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/portfolios/"+portfolio.ID.String()+"/pnl", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp PortfolioPnLResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.RealizedPL != 150 || resp.PortfolioID != portfolio.ID.String() {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/paper/portfolios/"+uuid.New().String()+"/pnl", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
 func TestPaperHandler_GetPositions(t *testing.T) {
 	router, mockService := setupPaperHandler()
 
 	// Create a test portfolio and position
 	userID := uuid.New()
 	portfolio, _ := mockService.CreatePortfolio(userID, "Test Portfolio", 100000)
-	
+
 	position := &model.Position{
 		ID:          uuid.New(),
 		PortfolioID: portfolio.ID,
@@ -569,7 +1113,7 @@ func TestPaperHandler_GetTrades(t *testing.T) {
 	portfolio, _ := mockService.CreatePortfolio(userID, "Test Portfolio", 100000)
 
 	// Create an order (which creates a trade)
-	_, _, _ = mockService.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeMarket, 10, 0)
+	_, _, _ = mockService.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeMarket, 10, 0, model.TimeInForceDay)
 
 	t.Run("get trades with valid portfolio_id", func(t *testing.T) {
 		req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/trades?portfolio_id="+portfolio.ID.String(), nil)
@@ -581,6 +1125,31 @@ func TestPaperHandler_GetTrades(t *testing.T) {
 		}
 	})
 
+	t.Run("get trades with pagination", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/trades?portfolio_id="+portfolio.ID.String()+"&from=2024-01-01&to=2024-12-31&limit=10&offset=0", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if w.Header().Get("X-Total-Count") != "1" {
+			t.Errorf("Expected X-Total-Count 1, got %q", w.Header().Get("X-Total-Count"))
+		}
+	})
+
+	t.Run("get trades with invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"&from=yesterday", "&limit=ten", "&offset=-1"} {
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/trades?portfolio_id="+portfolio.ID.String()+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+			}
+		}
+	})
+
 	t.Run("get trades without portfolio_id", func(t *testing.T) {
 		req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/trades", nil)
 		w := httptest.NewRecorder()
@@ -591,3 +1160,274 @@ func TestPaperHandler_GetTrades(t *testing.T) {
 		}
 	})
 }
+
+func TestPaperHandler_ListOrders_StatusFilter(t *testing.T) {
+	router, mockService := setupPaperHandler()
+
+	userID := uuid.New()
+	portfolio, _ := mockService.CreatePortfolio(userID, "Test Portfolio", 100000)
+	_, _, _ = mockService.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeMarket, 10, 0, model.TimeInForceDay)
+
+	pending := &model.Order{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "MSFT", Side: model.OrderSideBuy, OrderType: model.OrderTypeLimit, Quantity: 5, Price: 140, Status: model.OrderStatusPending}
+	mockService.orders[pending.ID] = pending
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{name: "pending only", query: "&status=pending", expectedStatus: http.StatusOK, expectedCount: 1},
+		{name: "all orders", query: "", expectedStatus: http.StatusOK, expectedCount: 2},
+		{name: "invalid status", query: "&status=open", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/orders?portfolio_id="+portfolio.ID.String()+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response []OrderResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if len(response) != tt.expectedCount {
+				t.Errorf("Expected %d orders, got %d", tt.expectedCount, len(response))
+			}
+		})
+	}
+}
+
+func TestPaperHandler_GetOpenOrders(t *testing.T) {
+	router, mockService := setupPaperHandler()
+
+	userID := uuid.New()
+	portfolio, _ := mockService.CreatePortfolio(userID, "Test Portfolio", 100000)
+	pending := &model.Order{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Side: model.OrderSideBuy, OrderType: model.OrderTypeLimit, Quantity: 5, Price: 140, Status: model.OrderStatusPending}
+	mockService.orders[pending.ID] = pending
+
+	t.Run("existing portfolio", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/portfolios/"+portfolio.ID.String()+"/open-orders", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+
+		var response []OpenOrderResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if len(response) != 1 {
+			t.Fatalf("Expected 1 open order, got %d", len(response))
+		}
+		if response[0].Distance == nil || *response[0].Distance != -10 {
+			t.Errorf("Expected distance -10, got %v", response[0].Distance)
+		}
+	})
+
+	t.Run("unknown portfolio", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/portfolios/"+uuid.New().String()+"/open-orders", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
+
+func TestPaperHandler_RefreshPortfolio(t *testing.T) {
+	router, mockService := setupPaperHandler()
+	portfolio := &model.Portfolio{ID: uuid.New(), UserID: uuid.New(), Name: "Test", CashBalance: 10000}
+	mockService.portfolios[portfolio.ID] = portfolio
+	position := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Quantity: 10, CurrentPrice: 150}
+	mockService.positions[position.ID] = position
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"existing portfolio", portfolio.ID.String(), http.StatusOK},
+		{"non-existent portfolio", uuid.New().String(), http.StatusNotFound},
+		{"invalid portfolio ID", "invalid-uuid", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/paper/portfolios/"+tt.id+"/refresh", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/paper/portfolios/"+portfolio.ID.String()+"/refresh", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var response PortfolioValueResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.CashBalance != 10000 || response.MarketValue != 1500 || response.TotalValue != 11500 || len(response.Positions) != 1 {
+		t.Errorf("Unexpected valuation %+v", response)
+	}
+}
+
+func TestPaperHandler_GetCalendarPnL(t *testing.T) {
+	router, mockService := setupPaperHandler()
+	portfolio := &model.Portfolio{ID: uuid.New(), UserID: uuid.New(), Name: "Test"}
+	mockService.portfolios[portfolio.ID] = portfolio
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"current year", "/api/v1/paper/portfolios/" + portfolio.ID.String() + "/analytics/calendar", http.StatusOK},
+		{"explicit year", "/api/v1/paper/portfolios/" + portfolio.ID.String() + "/analytics/calendar?year=2026", http.StatusOK},
+		{"non-numeric year", "/api/v1/paper/portfolios/" + portfolio.ID.String() + "/analytics/calendar?year=last", http.StatusBadRequest},
+		{"out of range year", "/api/v1/paper/portfolios/" + portfolio.ID.String() + "/analytics/calendar?year=99999", http.StatusBadRequest},
+		{"non-existent portfolio", "/api/v1/paper/portfolios/" + uuid.New().String() + "/analytics/calendar", http.StatusNotFound},
+		{"invalid portfolio ID", "/api/v1/paper/portfolios/invalid-uuid/analytics/calendar", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/portfolios/"+portfolio.ID.String()+"/analytics/calendar?year=2026", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var calendar service.CalendarPnL
+	if err := json.Unmarshal(w.Body.Bytes(), &calendar); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if calendar.Year != 2026 || len(calendar.Days) != 1 || calendar.BestDay == nil || calendar.CurrentStreak != 1 {
+		t.Errorf("Unexpected calendar %+v", calendar)
+	}
+}
+
+func TestPaperHandler_GetPortfolioRisk(t *testing.T) {
+	router, mockService := setupPaperHandler()
+	portfolio := &model.Portfolio{ID: uuid.New(), UserID: uuid.New(), Name: "Test", CashBalance: 10000}
+	mockService.portfolios[portfolio.ID] = portfolio
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"default confidence", "/api/v1/paper/portfolios/" + portfolio.ID.String() + "/risk", http.StatusOK},
+		{"explicit confidence", "/api/v1/paper/portfolios/" + portfolio.ID.String() + "/risk?confidence=99", http.StatusOK},
+		{"non-numeric confidence", "/api/v1/paper/portfolios/" + portfolio.ID.String() + "/risk?confidence=high", http.StatusBadRequest},
+		{"out of range confidence", "/api/v1/paper/portfolios/" + portfolio.ID.String() + "/risk?confidence=100", http.StatusBadRequest},
+		{"non-existent portfolio", "/api/v1/paper/portfolios/" + uuid.New().String() + "/risk", http.StatusNotFound},
+		{"invalid portfolio ID", "/api/v1/paper/portfolios/invalid-uuid/risk", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/portfolios/"+portfolio.ID.String()+"/risk", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var risk service.RiskMetrics
+	if err := json.Unmarshal(w.Body.Bytes(), &risk); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if risk.Confidence != service.DefaultRiskConfidence || risk.ValueAtRisk != 200 || risk.ConditionalVaR != 300 {
+		t.Errorf("Unexpected risk %+v", risk)
+	}
+}
+
+func TestPaperHandler_GetBacktestEquity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	paperHandler := NewPaperHandler(newMockPaperTradingService())
+	router := gin.New()
+	paperHandler.RegisterPaperRoutes(router.Group("/api/v1"))
+
+	// Without a backtest service the endpoint is unavailable
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/backtest/"+uuid.New().String()+"/equity", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := []model.StockPrice{
+		{Timestamp: start.AddDate(0, 0, 2), Close: 12},
+		{Timestamp: start.AddDate(0, 0, 1), Close: 11},
+		{Timestamp: start, Close: 10},
+	}
+	backtests := service.NewBacktestService(&backtestHistory{bars: bars}, repository.NewInMemoryBacktestResultRepository())
+	result, err := backtests.Run(service.BacktestConfig{
+		Symbol:         "AAPL",
+		StartDate:      start,
+		EndDate:        start.AddDate(0, 0, 2),
+		InitialCapital: 1000,
+		StrategyType:   model.BacktestStrategyRSI,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	paperHandler.SetBacktests(backtests)
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{"existing backtest", result.ID.String(), http.StatusOK},
+		{"non-existent backtest", uuid.New().String(), http.StatusNotFound},
+		{"invalid backtest ID", "invalid-uuid", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper/backtest/"+tt.id+"/equity", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var resp BacktestEquityResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if resp.BacktestID != tt.id || len(resp.EquityCurve) != 3 || resp.EquityCurve[0].Value != 1000 {
+				t.Errorf("Unexpected equity curve %+v", resp)
+			}
+			if !resp.EquityCurve[0].Timestamp.Equal(start) {
+				t.Errorf("First point at %v, want %v", resp.EquityCurve[0].Timestamp, start)
+			}
+		})
+	}
+}
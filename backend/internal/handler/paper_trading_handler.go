@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"sort"
 	"time"
 
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -79,29 +83,31 @@ type TransactionResponse struct {
 
 // BacktestRequest represents a backtest configuration.
 type BacktestRequest struct {
-	Symbol         string            `json:"symbol" binding:"required"`
-	StartDate      string            `json:"start_date" binding:"required"`
-	EndDate        string            `json:"end_date" binding:"required"`
-	InitialCapital float64           `json:"initial_capital" binding:"required,gt=0"`
-	Strategy       BacktestStrategy  `json:"strategy" binding:"required"`
+	Symbol         string           `json:"symbol" binding:"required"`
+	StartDate      string           `json:"start_date" binding:"required"`
+	EndDate        string           `json:"end_date" binding:"required"`
+	InitialCapital float64          `json:"initial_capital" binding:"required,gt=0"`
+	Strategy       BacktestStrategy `json:"strategy" binding:"required"`
 }
 
 // BacktestStrategy represents a backtest strategy configuration.
 type BacktestStrategy struct {
 	Name   string             `json:"name" binding:"required"`
-	Type   string             `json:"type" binding:"required,oneof=sma_crossover rsi macd custom"`
+	Type   string             `json:"type" binding:"required,oneof=sma_crossover rsi macd"`
 	Params map[string]float64 `json:"params"`
 }
 
 // BacktestResultResponse represents backtest results.
 type BacktestResultResponse struct {
-	ID          string             `json:"id"`
-	Config      BacktestRequest    `json:"config"`
-	Metrics     PerformanceMetrics `json:"metrics"`
-	TotalTrades int                `json:"total_trades"`
-	WinningTrades int              `json:"winning_trades"`
-	LosingTrades int               `json:"losing_trades"`
-	CompletedAt string             `json:"completed_at"`
+	ID            string              `json:"id"`
+	Config        BacktestRequest     `json:"config"`
+	Metrics       PerformanceMetrics  `json:"metrics"`
+	FinalValue    float64             `json:"final_value"`
+	TotalTrades   int                 `json:"total_trades"`
+	WinningTrades int                 `json:"winning_trades"`
+	LosingTrades  int                 `json:"losing_trades"`
+	EquityCurve   []model.EquityPoint `json:"equity_curve"`
+	CompletedAt   string              `json:"completed_at"`
 }
 
 // LeaderboardEntryResponse represents a leaderboard entry.
@@ -131,93 +137,133 @@ type JournalEntryRequest struct {
 }
 
 // PaperTradingHandler handles paper trading HTTP requests.
-type PaperTradingHandler struct{}
+type PaperTradingHandler struct {
+	service   service.PaperTradingService
+	backtests service.BacktestService
+}
 
 // NewPaperTradingHandler creates a new PaperTradingHandler instance.
-func NewPaperTradingHandler() *PaperTradingHandler {
-	return &PaperTradingHandler{}
+func NewPaperTradingHandler(svc service.PaperTradingService, backtests service.BacktestService) *PaperTradingHandler {
+	return &PaperTradingHandler{service: svc, backtests: backtests}
 }
 
-// GetPortfolio returns the user's paper trading portfolio.
+// GetPortfolio returns a paper trading portfolio with its performance.
 // @Summary Get portfolio
-// @Description Get user's paper trading portfolio
+// @Description Get a paper trading portfolio with positions valued at their last refreshed prices and performance computed from its trade history. Without portfolio_id the oldest portfolio is returned.
 // @Tags paper-trading
 // @Produce json
+// @Param portfolio_id query string false "Portfolio ID"
 // @Success 200 {object} PortfolioResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Router /api/v1/paper-trading/portfolio [get]
 func (h *PaperTradingHandler) GetPortfolio(c *gin.Context) {
-	portfolio := PortfolioResponse{
-		ID:             uuid.New().String(),
-		Name:           "My Portfolio",
-		InitialBalance: 100000,
-		CurrentBalance: 125000,
-		TotalValue:     125000,
-		CashBalance:    25000,
-		Positions: []PositionResponse{
-			{
-				ID:                  uuid.New().String(),
-				Symbol:              "AAPL",
-				Name:                "Apple Inc.",
-				Quantity:            50,
-				AvgCost:             175.00,
-				CurrentPrice:        189.95,
-				MarketValue:         9497.50,
-				UnrealizedPL:        747.50,
-				UnrealizedPLPercent: 8.54,
-				DayChange:           47.50,
-				DayChangePercent:    0.50,
-				Weight:              7.6,
-				OpenedAt:            "2024-11-01T10:00:00Z",
-			},
-			{
-				ID:                  uuid.New().String(),
-				Symbol:              "MSFT",
-				Name:                "Microsoft Corporation",
-				Quantity:            30,
-				AvgCost:             360.00,
-				CurrentPrice:        374.58,
-				MarketValue:         11237.40,
-				UnrealizedPL:        437.40,
-				UnrealizedPLPercent: 4.05,
-				DayChange:           153.60,
-				DayChangePercent:    1.39,
-				Weight:              9.0,
-				OpenedAt:            "2024-11-05T14:00:00Z",
-			},
-			{
-				ID:                  uuid.New().String(),
-				Symbol:              "NVDA",
-				Name:                "NVIDIA Corporation",
-				Quantity:            25,
-				AvgCost:             420.00,
-				CurrentPrice:        476.09,
-				MarketValue:         11902.25,
-				UnrealizedPL:        1402.25,
-				UnrealizedPLPercent: 13.35,
-				DayChange:           311.25,
-				DayChangePercent:    2.68,
-				Weight:              9.5,
-				OpenedAt:            "2024-10-15T09:00:00Z",
-			},
-		},
+	portfolio, err := h.resolvePortfolio(c.Query("portfolio_id"))
+	if err != nil {
+		switch err {
+		case errInvalidPortfolioID:
+			respondErrorMessage(c, http.StatusBadRequest, "invalid portfolio_id")
+		case service.ErrPortfolioNotFound:
+			respondError(c, http.StatusNotFound, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to get portfolio")
+		}
+		return
+	}
+
+	positions, err := h.service.GetPositions(portfolio.ID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get positions")
+		return
+	}
+	metrics, err := h.service.ComputePerformance(portfolio.ID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to compute performance")
+		return
+	}
+
+	var marketValue, dayReturn float64
+	for _, position := range positions {
+		marketValue += float64(position.Quantity) * position.CurrentPrice
+		dayReturn += float64(position.Quantity) * position.DayChange
+	}
+	totalValue := portfolio.CashBalance + marketValue
+
+	response := PortfolioResponse{
+		ID:             portfolio.ID.String(),
+		Name:           portfolio.Name,
+		InitialBalance: portfolio.InitialCash,
+		CurrentBalance: totalValue,
+		TotalValue:     totalValue,
+		CashBalance:    portfolio.CashBalance,
+		Positions:      make([]PositionResponse, 0, len(positions)),
 		Performance: PerformanceMetrics{
-			TotalReturn:        25000,
-			TotalReturnPercent: 25.0,
-			DayReturn:          512.35,
-			DayReturnPercent:   0.41,
-			WeekReturn:         2150.00,
-			WeekReturnPercent:  1.75,
-			MonthReturn:        8500.00,
-			MonthReturnPercent: 7.29,
-			SharpeRatio:        1.85,
-			MaxDrawdown:        -5.2,
-			WinRate:            68.5,
+			TotalReturn:        metrics.TotalReturn,
+			TotalReturnPercent: metrics.TotalReturnPercent,
+			DayReturn:          dayReturn,
+			SharpeRatio:        metrics.SharpeRatio,
+			MaxDrawdown:        metrics.MaxDrawdown,
+			WinRate:            metrics.WinRate,
 		},
-		CreatedAt: "2024-10-01T00:00:00Z",
-		UpdatedAt: time.Now().Format(time.RFC3339),
+		CreatedAt: portfolio.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: portfolio.UpdatedAt.Format(time.RFC3339),
+	}
+	if previousValue := totalValue - dayReturn; previousValue > 0 {
+		response.Performance.DayReturnPercent = dayReturn / previousValue * 100
 	}
 
-	c.JSON(http.StatusOK, portfolio)
+	for _, position := range positions {
+		value := float64(position.Quantity) * position.CurrentPrice
+		unrealized := (position.CurrentPrice - position.AvgCost) * float64(position.Quantity)
+		p := PositionResponse{
+			ID:               position.ID.String(),
+			Symbol:           position.Symbol,
+			Quantity:         position.Quantity,
+			AvgCost:          position.AvgCost,
+			CurrentPrice:     position.CurrentPrice,
+			MarketValue:      value,
+			UnrealizedPL:     unrealized,
+			DayChange:        float64(position.Quantity) * position.DayChange,
+			DayChangePercent: position.DayChangePercent,
+			OpenedAt:         position.CreatedAt.Format(time.RFC3339),
+		}
+		if cost := position.AvgCost * float64(position.Quantity); cost > 0 {
+			p.UnrealizedPLPercent = unrealized / cost * 100
+		}
+		if totalValue > 0 {
+			p.Weight = value / totalValue * 100
+		}
+		response.Positions = append(response.Positions, p)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// errInvalidPortfolioID reports a portfolio_id that is not a UUID.
+var errInvalidPortfolioID = errors.New("invalid portfolio_id")
+
+// resolvePortfolio returns the portfolio with the given ID, or the oldest
+// portfolio when id is empty.
+func (h *PaperTradingHandler) resolvePortfolio(id string) (*model.Portfolio, error) {
+	if id != "" {
+		portfolioID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, errInvalidPortfolioID
+		}
+		return h.service.GetPortfolio(portfolioID)
+	}
+
+	portfolios, err := h.service.ListPortfolios()
+	if err != nil {
+		return nil, err
+	}
+	if len(portfolios) == 0 {
+		return nil, service.ErrPortfolioNotFound
+	}
+	sort.Slice(portfolios, func(i, j int) bool {
+		return portfolios[i].CreatedAt.Before(portfolios[j].CreatedAt)
+	})
+	return &portfolios[0], nil
 }
 
 // GetPositions returns all positions.
@@ -277,7 +323,7 @@ func (h *PaperTradingHandler) GetPositions(c *gin.Context) {
 func (h *PaperTradingHandler) ExecuteTrade(c *gin.Context) {
 	var req TradeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -337,45 +383,117 @@ func (h *PaperTradingHandler) GetTransactions(c *gin.Context) {
 
 // RunBacktest runs a backtest simulation.
 // @Summary Run backtest
-// @Description Run a backtest with specified strategy
+// @Description Simulate a strategy over the symbol's stored daily closes between start_date and end_date (YYYY-MM-DD, inclusive) and store the result. Signals fill at the close: buys spend all cash on whole shares and sells close the position.
 // @Tags paper-trading
 // @Accept json
 // @Produce json
 // @Param request body BacktestRequest true "Backtest configuration"
 // @Success 200 {object} BacktestResultResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Router /api/v1/paper-trading/backtest [post]
 func (h *PaperTradingHandler) RunBacktest(c *gin.Context) {
 	var req BacktestRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	startDate, err := time.Parse(historyDateLayout, req.StartDate)
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "start_date must be YYYY-MM-DD")
+		return
+	}
+	endDate, err := time.Parse(historyDateLayout, req.EndDate)
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "end_date must be YYYY-MM-DD")
 		return
 	}
 
-	// Mock backtest result
-	result := BacktestResultResponse{
-		ID:     uuid.New().String(),
-		Config: req,
+	result, err := h.backtests.Run(service.BacktestConfig{
+		Symbol:         req.Symbol,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		InitialCapital: req.InitialCapital,
+		StrategyName:   req.Strategy.Name,
+		StrategyType:   model.BacktestStrategyType(req.Strategy.Type),
+		Params:         req.Strategy.Params,
+	})
+	if err != nil {
+		switch err {
+		case service.ErrInvalidBacktestStrategy, service.ErrInvalidBacktestParams,
+			service.ErrInvalidBacktestRange, service.ErrInvalidBacktestCapital:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrInsufficientBacktestData:
+			respondError(c, http.StatusUnprocessableEntity, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to run backtest")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, toBacktestResultResponse(result))
+}
+
+// GetBacktest returns a stored backtest result.
+// @Summary Get backtest
+// @Description Get a previously run backtest result
+// @Tags paper-trading
+// @Produce json
+// @Param id path string true "Backtest ID"
+// @Success 200 {object} BacktestResultResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/paper-trading/backtest/{id} [get]
+func (h *PaperTradingHandler) GetBacktest(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid backtest id")
+		return
+	}
+
+	result, err := h.backtests.GetResult(id)
+	if err != nil {
+		if err == service.ErrBacktestNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to get backtest")
+		return
+	}
+
+	c.JSON(http.StatusOK, toBacktestResultResponse(result))
+}
+
+// toBacktestResultResponse converts a stored backtest result for the API.
+func toBacktestResultResponse(result *model.BacktestResult) BacktestResultResponse {
+	return BacktestResultResponse{
+		ID: result.ID.String(),
+		Config: BacktestRequest{
+			Symbol:         result.Symbol,
+			StartDate:      result.StartDate.Format(historyDateLayout),
+			EndDate:        result.EndDate.Format(historyDateLayout),
+			InitialCapital: result.InitialCapital,
+			Strategy: BacktestStrategy{
+				Name:   result.StrategyName,
+				Type:   string(result.StrategyType),
+				Params: result.Params,
+			},
+		},
 		Metrics: PerformanceMetrics{
-			TotalReturn:        12500,
-			TotalReturnPercent: 12.5,
-			DayReturn:          0,
-			DayReturnPercent:   0,
-			WeekReturn:         0,
-			WeekReturnPercent:  0,
-			MonthReturn:        0,
-			MonthReturnPercent: 0,
-			SharpeRatio:        1.42,
-			MaxDrawdown:        -8.5,
-			WinRate:            62.5,
+			TotalReturn:        result.TotalReturn,
+			TotalReturnPercent: result.TotalReturnPercent,
+			SharpeRatio:        result.SharpeRatio,
+			MaxDrawdown:        result.MaxDrawdown,
+			WinRate:            result.WinRate,
 		},
-		TotalTrades:   48,
-		WinningTrades: 30,
-		LosingTrades:  18,
-		CompletedAt:   time.Now().Format(time.RFC3339),
+		FinalValue:    result.FinalValue,
+		TotalTrades:   result.TotalTrades,
+		WinningTrades: result.WinningTrades,
+		LosingTrades:  result.LosingTrades,
+		EquityCurve:   result.EquityCurve,
+		CompletedAt:   result.CompletedAt.Format(time.RFC3339),
 	}
-
-	c.JSON(http.StatusOK, result)
 }
 
 // GetLeaderboard returns the paper trading leaderboard.
@@ -466,7 +584,7 @@ func (h *PaperTradingHandler) GetJournalEntries(c *gin.Context) {
 func (h *PaperTradingHandler) CreateJournalEntry(c *gin.Context) {
 	var req JournalEntryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -527,6 +645,7 @@ func (h *PaperTradingHandler) RegisterPaperTradingRoutes(rg *gin.RouterGroup) {
 		pt.GET("/transactions", h.GetTransactions)
 		pt.POST("/trade", h.ExecuteTrade)
 		pt.POST("/backtest", h.RunBacktest)
+		pt.GET("/backtest/:id", h.GetBacktest)
 		pt.GET("/leaderboard", h.GetLeaderboard)
 		pt.GET("/journal", h.GetJournalEntries)
 		pt.POST("/journal", h.CreateJournalEntry)
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func setupPaperTradingHandler() (*gin.Engine, *mockPaperTradingService) {
+	gin.SetMode(gin.TestMode)
+	mockService := newMockPaperTradingService()
+	router := gin.New()
+	NewPaperTradingHandler(mockService, nil).RegisterPaperTradingRoutes(router.Group("/api/v1"))
+	return router, mockService
+}
+
+func TestPaperTradingHandler_GetPortfolio(t *testing.T) {
+	router, mockService := setupPaperTradingHandler()
+	portfolio, _ := mockService.CreatePortfolio(uuid.New(), "Main", 10000)
+	portfolio.CashBalance = 9000
+	newer, _ := mockService.CreatePortfolio(uuid.New(), "Newer", 5000)
+	newer.CreatedAt = portfolio.CreatedAt.Add(time.Hour)
+
+	mockService.positions[uuid.New()] = &model.Position{
+		ID:           uuid.New(),
+		PortfolioID:  portfolio.ID,
+		Symbol:       "AAPL",
+		Quantity:     10,
+		AvgCost:      100,
+		CurrentPrice: 110,
+		DayChange:    2,
+	}
+	mockService.trades[uuid.New()] = &model.Trade{PortfolioID: portfolio.ID, Side: model.OrderSideSell, RealizedPL: 40}
+	mockService.trades[uuid.New()] = &model.Trade{PortfolioID: portfolio.ID, Side: model.OrderSideSell, RealizedPL: -10}
+
+	for _, path := range []string{
+		"/api/v1/paper-trading/portfolio",
+		"/api/v1/paper-trading/portfolio?portfolio_id=" + portfolio.ID.String(),
+	} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d. Body: %s", path, w.Code, w.Body.String())
+		}
+		var resp PortfolioResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if resp.ID != portfolio.ID.String() || resp.TotalValue != 10100 || resp.InitialBalance != 10000 {
+			t.Errorf("%s: unexpected portfolio %+v", path, resp)
+		}
+		if len(resp.Positions) != 1 || resp.Positions[0].UnrealizedPL != 100 || resp.Positions[0].UnrealizedPLPercent != 10 {
+			t.Errorf("%s: unexpected positions %+v", path, resp.Positions)
+		}
+		// 30 realized plus 100 unrealized against 10000 initial cash.
+		perf := resp.Performance
+		if perf.TotalReturn != 130 || math.Abs(perf.TotalReturnPercent-1.3) > 1e-9 || perf.WinRate != 50 || perf.DayReturn != 20 {
+			t.Errorf("%s: unexpected performance %+v", path, perf)
+		}
+	}
+}
+
+func TestPaperTradingHandler_GetPortfolio_Errors(t *testing.T) {
+	router, _ := setupPaperTradingHandler()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"no portfolios", "", http.StatusNotFound},
+		{"invalid portfolio_id", "?portfolio_id=not-a-uuid", http.StatusBadRequest},
+		{"unknown portfolio", "?portfolio_id=" + uuid.New().String(), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper-trading/portfolio"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// backtestHistory serves a fixed daily history, newest first.
+type backtestHistory struct {
+	bars []model.StockPrice
+}
+
+func (h *backtestHistory) GetPriceHistory(symbol string, limit int) ([]model.StockPrice, error) {
+	if symbol != "AAPL" {
+		return nil, repository.ErrNotFound
+	}
+	return h.bars, nil
+}
+
+func setupBacktestHandler() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	closes := []float64{10, 10, 10, 12, 14, 13, 11, 9, 9, 9, 11, 13, 15, 14, 12}
+	bars := make([]model.StockPrice, len(closes))
+	for i, c := range closes {
+		bars[len(closes)-1-i] = model.StockPrice{Timestamp: start.AddDate(0, 0, i), Close: c}
+	}
+
+	backtests := service.NewBacktestService(&backtestHistory{bars: bars}, repository.NewInMemoryBacktestResultRepository())
+	router := gin.New()
+	NewPaperTradingHandler(newMockPaperTradingService(), backtests).RegisterPaperTradingRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestPaperTradingHandler_RunBacktest(t *testing.T) {
+	router := setupBacktestHandler()
+
+	body := `{"symbol":"AAPL","start_date":"2024-01-01","end_date":"2024-01-15","initial_capital":1000,` +
+		`"strategy":{"name":"fast cross","type":"sma_crossover","params":{"fast_period":2,"slow_period":3}}}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/paper-trading/backtest", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var resp BacktestResultResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.TotalTrades != 2 || resp.WinningTrades != 1 || resp.Metrics.WinRate != 50 || len(resp.EquityCurve) != 15 {
+		t.Errorf("Unexpected backtest result %+v", resp)
+	}
+	if resp.Config.StartDate != "2024-01-01" || resp.Config.Strategy.Params["slow_period"] != 3 {
+		t.Errorf("Unexpected backtest config %+v", resp.Config)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/paper-trading/backtest/"+resp.ID, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for stored backtest, got %d. Body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPaperTradingHandler_RunBacktest_Errors(t *testing.T) {
+	router := setupBacktestHandler()
+
+	request := func(symbol, start, end, strategy string) string {
+		return `{"symbol":"` + symbol + `","start_date":"` + start + `","end_date":"` + end + `","initial_capital":1000,` +
+			`"strategy":{"name":"test","type":"` + strategy + `"}}`
+	}
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"bad date", request("AAPL", "01/01/2024", "2024-01-15", "rsi"), http.StatusBadRequest},
+		{"end before start", request("AAPL", "2024-01-15", "2024-01-01", "rsi"), http.StatusBadRequest},
+		{"unsupported strategy", request("AAPL", "2024-01-01", "2024-01-15", "custom"), http.StatusBadRequest},
+		{"unknown symbol", request("MSFT", "2024-01-01", "2024-01-15", "rsi"), http.StatusUnprocessableEntity},
+		{"range without data", request("AAPL", "2023-01-01", "2023-01-15", "rsi"), http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/paper-trading/backtest", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/paper-trading/backtest/"+uuid.New().String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown backtest, got %d", w.Code)
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/pkg/websocket"
+	"github.com/gin-gonic/gin"
+)
+
+// priceStreamHeartbeat is how often a price stream sends a comment, so
+// proxies do not time out a stream between syncs.
+const priceStreamHeartbeat = 15 * time.Second
+
+// PriceEventSource delivers the events sent to channels until stop is
+// called. *websocket.Hub implements it.
+type PriceEventSource interface {
+	Listen(channels []string) (events <-chan []byte, stop func())
+}
+
+// PriceStreamHandler streams price updates as Server-Sent Events, for
+// clients that cannot use WebSockets.
+type PriceStreamHandler struct {
+	events    PriceEventSource
+	heartbeat time.Duration
+}
+
+// NewPriceStreamHandler creates a new PriceStreamHandler instance.
+func NewPriceStreamHandler(events PriceEventSource) *PriceStreamHandler {
+	return &PriceStreamHandler{events: events, heartbeat: priceStreamHeartbeat}
+}
+
+// StreamPrices handles GET /api/v1/stream/prices.
+// @Summary Stream price updates
+// @Description Open a Server-Sent Events stream of stock:price_update events for up to 50 comma-separated symbols, sent as each stock sync stores new prices. A comment is sent every 15 seconds to keep the connection open.
+// @Tags stocks
+// @Produce text/event-stream
+// @Param symbols query string true "Comma-separated symbols, e.g. AAPL,MSFT"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /api/v1/stream/prices [get]
+func (h *PriceStreamHandler) StreamPrices(c *gin.Context) {
+	symbols, err := service.NormalizeQuoteSymbols(strings.Split(c.Query("symbols"), ","))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	channels := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		channels[i] = websocket.StockChannel(symbol)
+	}
+
+	events, stop := h.events.Listen(channels)
+	defer stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Stop nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case data, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects
+				return
+			}
+			var event websocket.Event
+			if err := json.Unmarshal(data, &event); err != nil {
+				continue
+			}
+			fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, data)
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// RegisterPriceStreamRoutes registers the price stream route behind auth.
+func (h *PriceStreamHandler) RegisterPriceStreamRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	rg.GET("/stream/prices", authMiddleware, h.StreamPrices)
+}
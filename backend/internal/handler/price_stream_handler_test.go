@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/pkg/websocket"
+	"github.com/gin-gonic/gin"
+)
+
+// fakePriceEvents hands out one events channel and records the channels
+// listened to and whether the listener stopped.
+type fakePriceEvents struct {
+	events   chan []byte
+	channels chan []string
+	stopped  chan struct{}
+}
+
+func newFakePriceEvents() *fakePriceEvents {
+	return &fakePriceEvents{
+		events:   make(chan []byte, 10),
+		channels: make(chan []string, 1),
+		stopped:  make(chan struct{}),
+	}
+}
+
+func (f *fakePriceEvents) Listen(channels []string) (<-chan []byte, func()) {
+	f.channels <- channels
+	return f.events, func() { close(f.stopped) }
+}
+
+func priceStreamServer(t *testing.T, events PriceEventSource, heartbeat time.Duration) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	h := NewPriceStreamHandler(events)
+	h.heartbeat = heartbeat
+	auth := func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: "authorization header required", Code: CodeUnauthorized})
+			return
+		}
+		c.Next()
+	}
+	h.RegisterPriceStreamRoutes(router.Group("/api/v1"), auth)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func openPriceStream(t *testing.T, server *httptest.Server, query string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/stream/prices"+query, nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// readFrame reads lines up to the blank line that ends an SSE frame.
+func readFrame(t *testing.T, r *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read frame: %v (read %q)", err, lines)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestPriceStreamHandler_StreamPrices(t *testing.T) {
+	events := newFakePriceEvents()
+	server := priceStreamServer(t, events, time.Hour)
+
+	resp := openPriceStream(t, server, "?symbols=aapl,MSFT,aapl")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected an event stream, got %q", ct)
+	}
+	if channels := <-events.channels; strings.Join(channels, ",") != "stock:AAPL,stock:MSFT" {
+		t.Errorf("Expected to listen to stock:AAPL and stock:MSFT, got %v", channels)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	for _, symbol := range []string{"AAPL", "MSFT"} {
+		data, _ := json.Marshal(websocket.Event{Type: websocket.EventStockPriceUpdate, Payload: map[string]interface{}{"symbol": symbol}})
+		events.events <- data
+
+		frame := readFrame(t, reader)
+		if len(frame) != 2 || frame[0] != "event: stock:price_update" || frame[1] != "data: "+string(data) {
+			t.Errorf("Unexpected frame %q for %s", frame, symbol)
+		}
+	}
+
+	// Closing the stream stops listening
+	resp.Body.Close()
+	select {
+	case <-events.stopped:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the handler to stop listening when the client disconnects")
+	}
+}
+
+func TestPriceStreamHandler_Heartbeat(t *testing.T) {
+	server := priceStreamServer(t, newFakePriceEvents(), 10*time.Millisecond)
+
+	reader := bufio.NewReader(openPriceStream(t, server, "?symbols=AAPL").Body)
+	if frame := readFrame(t, reader); len(frame) != 1 || frame[0] != ": heartbeat" {
+		t.Errorf("Expected a heartbeat comment, got %q", frame)
+	}
+}
+
+func TestPriceStreamHandler_EndsWhenDropped(t *testing.T) {
+	events := newFakePriceEvents()
+	server := priceStreamServer(t, events, time.Hour)
+
+	resp := openPriceStream(t, server, "?symbols=AAPL")
+	close(events.events)
+
+	// The body ends rather than hanging
+	done := make(chan struct{})
+	go func() {
+		_, _ = bufio.NewReader(resp.Body).ReadString(0)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the stream to end when the hub drops the listener")
+	}
+}
+
+func TestPriceStreamHandler_Errors(t *testing.T) {
+	server := priceStreamServer(t, newFakePriceEvents(), time.Hour)
+	symbols := make([]string, service.MaxQuoteSymbols+1)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("S%d", i)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		query  string
+		auth   bool
+		status int
+		code   string
+	}{
+		{"no symbols", "", true, http.StatusBadRequest, CodeInvalidRequest},
+		{"too many symbols", "?symbols=" + strings.Join(symbols, ","), true, http.StatusBadRequest, CodeTooManyItems},
+		{"no token", "?symbols=AAPL", false, http.StatusUnauthorized, CodeUnauthorized},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/stream/prices"+tt.query, nil)
+			if tt.auth {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			var body ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if body.Code != tt.code {
+				t.Errorf("Expected code %q, got %q", tt.code, body.Code)
+			}
+		})
+	}
+}
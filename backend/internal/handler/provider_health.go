@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Provider probe settings.
+const (
+	ProviderHealthCacheTTL = time.Minute
+	providerProbeTimeout   = 5 * time.Second
+)
+
+// Upstream endpoints probed by the provider health checkers. Neither request
+// counts against the provider's API quota.
+const (
+	alphaVantageProbeURL = "https://www.alphavantage.co/query"
+	oddsAPIProbeURL      = "https://api.the-odds-api.com/v4/sports"
+)
+
+// CachedHealthChecker wraps checker so that it runs at most once per ttl.
+// Concurrent callers wait for the in-flight probe and share its result.
+func CachedHealthChecker(checker HealthChecker, ttl time.Duration) HealthChecker {
+	var (
+		mu      sync.Mutex
+		checked time.Time
+		name    string
+		healthy bool
+		message string
+	)
+
+	return func() (string, bool, string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if checked.IsZero() || time.Since(checked) >= ttl {
+			name, healthy, message = checker()
+			checked = time.Now()
+		}
+		return name, healthy, message
+	}
+}
+
+// NewHTTPProviderChecker returns a HealthChecker that probes a provider with a
+// GET request. Authentication failures and 5xx responses report the provider
+// as down; a 429 reports it as up but rate limited.
+func NewHTTPProviderChecker(name, probeURL string, client *http.Client) HealthChecker {
+	if client == nil {
+		client = &http.Client{Timeout: providerProbeTimeout}
+	}
+
+	return func() (string, bool, string) {
+		ctx, cancel := context.WithTimeout(context.Background(), providerProbeTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, probeURL, nil)
+		if err != nil {
+			return name, false, err.Error()
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			// Report the cause without the URL, which may carry an API key.
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return name, false, err.Error()
+		}
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			return name, false, "authentication failed"
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return name, true, "rate limited"
+		case resp.StatusCode >= http.StatusInternalServerError:
+			return name, false, fmt.Sprintf("unexpected status %d", resp.StatusCode)
+		default:
+			return name, true, "reachable"
+		}
+	}
+}
+
+// NewAlphaVantageChecker returns a cached reachability check for Alpha Vantage.
+// The probe omits the API key so it does not use the free tier's daily quota.
+func NewAlphaVantageChecker() HealthChecker {
+	return CachedHealthChecker(NewHTTPProviderChecker("alphavantage", alphaVantageProbeURL, nil), ProviderHealthCacheTTL)
+}
+
+// NewOddsAPIChecker returns a cached check for the odds API. Listing sports is
+// free of quota cost and validates the API key.
+func NewOddsAPIChecker(apiKey string) HealthChecker {
+	probeURL := oddsAPIProbeURL + "?apiKey=" + url.QueryEscape(apiKey)
+	return CachedHealthChecker(NewHTTPProviderChecker("odds_api", probeURL, nil), ProviderHealthCacheTTL)
+}
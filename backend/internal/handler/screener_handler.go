@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ScreenerHandler handles stock screener HTTP requests.
+type ScreenerHandler struct {
+	service service.ScreenerService
+}
+
+// NewScreenerHandler creates a new ScreenerHandler instance.
+func NewScreenerHandler(svc service.ScreenerService) *ScreenerHandler {
+	return &ScreenerHandler{service: svc}
+}
+
+// ScreenerResponse represents the stocks matching a screen.
+type ScreenerResponse struct {
+	Results []service.ScreenerResult `json:"results"`
+	Count   int                      `json:"count"`
+}
+
+// Screen handles POST /api/v1/screener.
+// @Summary Screen stocks by fundamentals
+// @Description Filter tracked stocks by P/E range, dividend yield, market cap, sector and return on equity, using company overviews cached for up to a day. A P/E bound excludes stocks without earnings. Dividend yield and return on equity are fractions.
+// @Tags screener
+// @Accept json
+// @Produce json
+// @Param request body service.ScreenerCriteria true "Screen criteria"
+// @Param sort query string false "Sort order (pe, market_cap, dividend_yield); overrides the body"
+// @Success 200 {object} ScreenerResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/screener [post]
+func (h *ScreenerHandler) Screen(c *gin.Context) {
+	var criteria service.ScreenerCriteria
+	if err := c.ShouldBindJSON(&criteria); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	if sort := c.Query("sort"); sort != "" {
+		criteria.Sort = sort
+	}
+
+	results, err := h.service.Screen(criteria)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidScreenerRange, service.ErrInvalidScreenerSort:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrScreenerUnavailable:
+			respondError(c, http.StatusServiceUnavailable, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to screen stocks")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, ScreenerResponse{Results: results, Count: len(results)})
+}
+
+// RegisterScreenerRoutes registers the stock screener route.
+func (h *ScreenerHandler) RegisterScreenerRoutes(rg *gin.RouterGroup) {
+	rg.POST("/screener", h.Screen)
+}
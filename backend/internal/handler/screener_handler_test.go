@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// memoryOverviews serves company overviews for every stock it holds.
+type memoryOverviews map[string]service.StockOverview
+
+func (m memoryOverviews) GetTrackedStocks(ctx context.Context) ([]model.Stock, error) {
+	stocks := make([]model.Stock, 0, len(m))
+	for symbol := range m {
+		stocks = append(stocks, model.Stock{Symbol: symbol})
+	}
+	return stocks, nil
+}
+
+func (m memoryOverviews) GetOverview(ctx context.Context, symbol string) (*service.StockOverview, error) {
+	overview := m[symbol]
+	return &overview, nil
+}
+
+func setupScreenerHandler(overviews memoryOverviews) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var source service.OverviewSource
+	if overviews != nil {
+		source = overviews
+	}
+	NewScreenerHandler(service.NewScreenerService(overviews, source)).RegisterScreenerRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestScreenerHandler_Screen(t *testing.T) {
+	router := setupScreenerHandler(memoryOverviews{
+		"JNJ":  {Sector: "HEALTHCARE", MarketCap: 4e11, PERatio: 15, DividendYield: 0.03},
+		"KO":   {Sector: "CONSUMER DEFENSIVE", MarketCap: 2.6e11, PERatio: 24, DividendYield: 0.031},
+		"MSFT": {Sector: "TECHNOLOGY", MarketCap: 3.1e12, PERatio: 35, DividendYield: 0.007},
+	})
+
+	body := bytes.NewBufferString(`{"max_pe": 30, "min_dividend_yield": 0.01, "sort": "pe"}`)
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/screener?sort=market_cap", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var resp ScreenerResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Count != 2 || len(resp.Results) != 2 || resp.Results[0].Symbol != "JNJ" || resp.Results[1].Symbol != "KO" {
+		t.Errorf("Expected JNJ then KO by market cap, got %+v", resp)
+	}
+}
+
+func TestScreenerHandler_Screen_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		overviews  memoryOverviews
+		url        string
+		body       string
+		wantStatus int
+	}{
+		{"min pe above max pe", memoryOverviews{}, "/api/v1/screener", `{"min_pe": 20, "max_pe": 10}`, http.StatusBadRequest},
+		{"unknown sort", memoryOverviews{}, "/api/v1/screener?sort=price", `{}`, http.StatusBadRequest},
+		{"invalid body", memoryOverviews{}, "/api/v1/screener", `{"min_pe": "low"}`, http.StatusBadRequest},
+		{"no overview source", nil, "/api/v1/screener", `{}`, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupScreenerHandler(tt.overviews)
+			req, _ := http.NewRequest(http.MethodPost, tt.url, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ScreenerPresetHandler handles saved screener filter HTTP requests.
+type ScreenerPresetHandler struct {
+	service service.ScreenerPresetService
+}
+
+// NewScreenerPresetHandler creates a new ScreenerPresetHandler instance.
+func NewScreenerPresetHandler(svc service.ScreenerPresetService) *ScreenerPresetHandler {
+	return &ScreenerPresetHandler{service: svc}
+}
+
+// CreateScreenerPresetRequest represents a request to save a screener filter.
+type CreateScreenerPresetRequest struct {
+	Name        string          `json:"name" binding:"required"`
+	Filter      json.RawMessage `json:"filter" binding:"required"`
+	NotifyOnNew *bool           `json:"notify_on_new"`
+}
+
+// ScreenerPresetResponse represents a saved screener filter in API responses.
+type ScreenerPresetResponse struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Filter      json.RawMessage `json:"filter"`
+	NotifyOnNew bool            `json:"notify_on_new"`
+	LastResults []string        `json:"last_results"`
+	LastRunAt   string          `json:"last_run_at,omitempty"`
+	CreatedAt   string          `json:"created_at"`
+}
+
+// CreatePreset handles POST /api/v1/screener/presets.
+// @Summary Save a screener preset
+// @Description Save a screener filter so it can be re-run and monitored daily
+// @Tags screener
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateScreenerPresetRequest true "Preset details"
+// @Success 201 {object} ScreenerPresetResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/screener/presets [post]
+func (h *ScreenerPresetHandler) CreatePreset(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req CreateScreenerPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	notifyOnNew := true
+	if req.NotifyOnNew != nil {
+		notifyOnNew = *req.NotifyOnNew
+	}
+
+	preset, err := h.service.CreatePreset(userID, req.Name, req.Filter, notifyOnNew)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidPresetName, service.ErrInvalidPresetFilter:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to save preset")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, presetToResponse(preset))
+}
+
+// ListPresets handles GET /api/v1/screener/presets.
+// @Summary List screener presets
+// @Description List the current user's saved screener filters
+// @Tags screener
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} ScreenerPresetResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/screener/presets [get]
+func (h *ScreenerPresetHandler) ListPresets(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	presets, err := h.service.ListPresets(userID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to list presets")
+		return
+	}
+
+	response := make([]ScreenerPresetResponse, len(presets))
+	for i := range presets {
+		response[i] = presetToResponse(&presets[i])
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeletePreset handles DELETE /api/v1/screener/presets/:id.
+// @Summary Delete a screener preset
+// @Description Delete one of the current user's saved screener filters
+// @Tags screener
+// @Security BearerAuth
+// @Param id path string true "Preset ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/screener/presets/{id} [delete]
+func (h *ScreenerPresetHandler) DeletePreset(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid preset id")
+		return
+	}
+
+	if err := h.service.DeletePreset(userID, id); err != nil {
+		if err == service.ErrPresetNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to delete preset")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RunPreset handles POST /api/v1/screener/presets/:id/run.
+// @Summary Run a screener preset
+// @Description Run a saved screener filter and report changes since the last daily snapshot
+// @Tags screener
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Preset ID"
+// @Success 200 {object} service.PresetRunResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Router /api/v1/screener/presets/{id}/run [post]
+func (h *ScreenerPresetHandler) RunPreset(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid preset id")
+		return
+	}
+
+	result, err := h.service.RunPreset(c.Request.Context(), userID, id)
+	if err != nil {
+		switch err {
+		case service.ErrPresetNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrScreenerUnavailable:
+			respondError(c, http.StatusNotImplemented, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to run preset")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RegisterScreenerPresetRoutes registers screener preset routes.
+func (h *ScreenerPresetHandler) RegisterScreenerPresetRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	presets := rg.Group("/screener/presets")
+	presets.Use(authMiddleware)
+	{
+		presets.POST("", h.CreatePreset)
+		presets.GET("", h.ListPresets)
+		presets.DELETE("/:id", h.DeletePreset)
+		presets.POST("/:id/run", h.RunPreset)
+	}
+}
+
+// presetToResponse converts a model.ScreenerPreset to ScreenerPresetResponse.
+func presetToResponse(preset *model.ScreenerPreset) ScreenerPresetResponse {
+	resp := ScreenerPresetResponse{
+		ID:          preset.ID.String(),
+		Name:        preset.Name,
+		Filter:      json.RawMessage(preset.Filter),
+		NotifyOnNew: preset.NotifyOnNew,
+		LastResults: []string(preset.LastResults),
+		CreatedAt:   preset.CreatedAt.Format(time.RFC3339),
+	}
+	if resp.LastResults == nil {
+		resp.LastResults = []string{}
+	}
+	if preset.LastRunAt != nil {
+		resp.LastRunAt = preset.LastRunAt.Format(time.RFC3339)
+	}
+	return resp
+}
@@ -6,18 +6,22 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"super-dashboard/backend/internal/model"
 	"super-dashboard/backend/internal/repository"
+	"super-dashboard/backend/internal/service"
 )
 
 // SettingsHandler handles settings-related HTTP requests.
 type SettingsHandler struct {
 	settingsRepo *repository.SettingsRepository
+	audit        *AuditRecorder
 }
 
 // NewSettingsHandler creates a new SettingsHandler.
-func NewSettingsHandler(settingsRepo *repository.SettingsRepository) *SettingsHandler {
+func NewSettingsHandler(settingsRepo *repository.SettingsRepository, audit *AuditRecorder) *SettingsHandler {
 	return &SettingsHandler{
 		settingsRepo: settingsRepo,
+		audit:        audit,
 	}
 }
 
@@ -27,7 +31,7 @@ func (h *SettingsHandler) GetSettings(c *gin.Context) {
 
 	settings, err := h.settingsRepo.GetUserSettings(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -40,7 +44,7 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 
 	settings, err := h.settingsRepo.GetUserSettings(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -59,10 +63,14 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 		NotifyValueBets  *bool    `json:"notify_value_bets"`
 		NotifyAlerts     *bool    `json:"notify_alerts"`
 		NotifyNews       *bool    `json:"notify_news"`
+		DiscordWebhook   *string  `json:"discord_webhook"`
+
+		BuyMarginOfSafety  *float64 `json:"buy_margin_of_safety"`
+		SellMarginOfSafety *float64 `json:"sell_margin_of_safety"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -109,12 +117,45 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 	if req.NotifyNews != nil {
 		settings.NotifyNews = *req.NotifyNews
 	}
+	if req.BuyMarginOfSafety != nil || req.SellMarginOfSafety != nil {
+		if req.BuyMarginOfSafety != nil {
+			settings.BuyMarginOfSafety = *req.BuyMarginOfSafety
+		}
+		if req.SellMarginOfSafety != nil {
+			settings.SellMarginOfSafety = *req.SellMarginOfSafety
+		}
+		thresholds := service.RecommendationThresholds{
+			BuyMargin:  settings.BuyMarginOfSafety,
+			SellMargin: settings.SellMarginOfSafety,
+		}
+		if err := thresholds.Validate(); err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+	webhookAdded := false
+	if req.DiscordWebhook != nil {
+		webhookAdded = *req.DiscordWebhook != "" && *req.DiscordWebhook != settings.DiscordWebhook
+		settings.DiscordWebhook = *req.DiscordWebhook
+	}
 
 	if err := h.settingsRepo.UpdateSettings(c.Request.Context(), settings); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
+	// The webhook URL is a credential; only record that it was set.
+	changes := req
+	changes.DiscordWebhook = nil
+	h.audit.Record(c, model.AuditActionSettingsChange, map[string]interface{}{
+		"changes": changes,
+	})
+	if webhookAdded {
+		h.audit.Record(c, model.AuditActionWebhookAdd, map[string]interface{}{
+			"channel": "discord",
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{"settings": settings})
 }
 
@@ -127,15 +168,19 @@ func (h *SettingsHandler) UpdateTheme(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.settingsRepo.UpdateTheme(c.Request.Context(), userID.(uuid.UUID), req.Theme); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
+	h.audit.Record(c, model.AuditActionSettingsChange, map[string]interface{}{
+		"theme": req.Theme,
+	})
+
 	c.JSON(http.StatusOK, gin.H{"message": "Theme updated"})
 }
 
@@ -148,15 +193,19 @@ func (h *SettingsHandler) UpdateLanguage(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.settingsRepo.UpdateLanguage(c.Request.Context(), userID.(uuid.UUID), req.Language); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
+	h.audit.Record(c, model.AuditActionSettingsChange, map[string]interface{}{
+		"language": req.Language,
+	})
+
 	c.JSON(http.StatusOK, gin.H{"message": "Language updated"})
 }
 
@@ -166,7 +215,7 @@ func (h *SettingsHandler) GetNotificationPreferences(c *gin.Context) {
 
 	prefs, err := h.settingsRepo.GetNotificationPreferences(c.Request.Context(), userID.(uuid.UUID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -180,14 +229,18 @@ func (h *SettingsHandler) UpdateNotificationPreferences(c *gin.Context) {
 	var prefs map[string]bool
 
 	if err := c.ShouldBindJSON(&prefs); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.settingsRepo.UpdateNotificationSettings(c.Request.Context(), userID.(uuid.UUID), prefs); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
+	h.audit.Record(c, model.AuditActionSettingsChange, map[string]interface{}{
+		"notifications": prefs,
+	})
+
 	c.JSON(http.StatusOK, gin.H{"message": "Notification preferences updated"})
 }
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StockAdminHandler handles administrative stock maintenance requests.
+type StockAdminHandler struct {
+	mergeService service.StockMergeService
+}
+
+// NewStockAdminHandler creates a new StockAdminHandler instance.
+func NewStockAdminHandler(mergeService service.StockMergeService) *StockAdminHandler {
+	return &StockAdminHandler{mergeService: mergeService}
+}
+
+// MergeStocksRequest represents a request to merge duplicate stocks.
+type MergeStocksRequest struct {
+	CanonicalID  string   `json:"canonical_id" binding:"required,uuid"`
+	DuplicateIDs []string `json:"duplicate_ids" binding:"required,min=1,dive,uuid"`
+}
+
+// ListDuplicates handles GET /api/v1/admin/stocks/duplicates.
+// @Summary List duplicate stocks
+// @Description List stock rows whose symbols normalize to the same ticker, with a suggested canonical row
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} service.DuplicateStockGroup
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /api/v1/admin/stocks/duplicates [get]
+func (h *StockAdminHandler) ListDuplicates(c *gin.Context) {
+	groups, err := h.mergeService.FindDuplicates()
+	if err != nil {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to find duplicate stocks")
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// MergeStocks handles POST /api/v1/admin/stocks/merge.
+// @Summary Merge duplicate stocks
+// @Description Repoint watchlist items, prices, news and fair values from duplicate stocks to the canonical stock and delete the duplicates
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MergeStocksRequest true "Merge details"
+// @Success 200 {object} repository.StockMergeCounts
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /api/v1/admin/stocks/merge [post]
+func (h *StockAdminHandler) MergeStocks(c *gin.Context) {
+	var req MergeStocksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	canonicalID, _ := uuid.Parse(req.CanonicalID)
+	duplicateIDs := make([]uuid.UUID, len(req.DuplicateIDs))
+	for i, id := range req.DuplicateIDs {
+		duplicateIDs[i], _ = uuid.Parse(id)
+	}
+
+	counts, err := h.mergeService.MergeStocks(canonicalID, duplicateIDs)
+	if err != nil {
+		switch err {
+		case service.ErrStockNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrNoDuplicates, service.ErrCanonicalDuplicate, service.ErrSymbolMismatch:
+			respondError(c, http.StatusUnprocessableEntity, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to merge stocks")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// RegisterStockAdminRoutes registers stock maintenance routes behind auth and admin checks.
+func (h *StockAdminHandler) RegisterStockAdminRoutes(rg *gin.RouterGroup, authMiddleware, adminMiddleware gin.HandlerFunc) {
+	stocks := rg.Group("/admin/stocks")
+	stocks.Use(authMiddleware, adminMiddleware)
+	{
+		stocks.GET("/duplicates", h.ListDuplicates)
+		stocks.POST("/merge", h.MergeStocks)
+	}
+}
@@ -31,7 +31,7 @@ func (h *StockAnalysisHandler) CalculateDCF(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -44,7 +44,7 @@ func (h *StockAnalysisHandler) CalculateDCF(c *gin.Context) {
 		req.Years,
 	)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -60,7 +60,7 @@ func (h *StockAnalysisHandler) CalculateGraham(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -71,7 +71,7 @@ func (h *StockAnalysisHandler) CalculateGraham(c *gin.Context) {
 		req.BookValue,
 	)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -87,7 +87,7 @@ func (h *StockAnalysisHandler) CalculatePE(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -98,7 +98,7 @@ func (h *StockAnalysisHandler) CalculatePE(c *gin.Context) {
 		req.IndustryPE,
 	)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -111,7 +111,7 @@ func (h *StockAnalysisHandler) GetFairValue(c *gin.Context) {
 
 	fairValue, err := h.stockAnalysisService.GetLatestFairValue(c.Request.Context(), symbol)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "fair value not found"})
+		respondErrorMessage(c, http.StatusNotFound, "fair value not found")
 		return
 	}
 
@@ -128,7 +128,7 @@ func (h *StockAnalysisHandler) GetUndervaluedStocks(c *gin.Context) {
 
 	stocks, err := h.stockAnalysisService.GetUndervaluedStocks(c.Request.Context(), minUpside)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -141,7 +141,7 @@ func (h *StockAnalysisHandler) GetStockWithSentiment(c *gin.Context) {
 
 	data, err := h.stockAnalysisService.GetStockWithSentiment(c.Request.Context(), symbol)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -153,13 +153,13 @@ func (h *StockAnalysisHandler) ScreenStocks(c *gin.Context) {
 	var criteria map[string]interface{}
 
 	if err := c.ShouldBindJSON(&criteria); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	stocks, err := h.stockAnalysisService.GetStockScreener(c.Request.Context(), criteria)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
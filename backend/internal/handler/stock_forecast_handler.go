@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// StockForecastHandler serves Monte Carlo price forecasts.
+type StockForecastHandler struct {
+	service service.StockForecastService
+}
+
+// NewStockForecastHandler creates a new StockForecastHandler instance.
+func NewStockForecastHandler(svc service.StockForecastService) *StockForecastHandler {
+	return &StockForecastHandler{service: svc}
+}
+
+// Forecast handles POST /api/v1/stocks/:symbol/forecast.
+// @Summary Forecast a stock's price
+// @Description Estimate drift and volatility from the stock's recent daily closes and simulate its price with geometric Brownian motion. Returns the mean, median and 5th/95th percentile terminal prices and the probability of ending above the current price. The body is optional.
+// @Tags stocks
+// @Accept json
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param request body service.ForecastRequest false "Horizon (1 to 252 trading days, default 30), simulations (1 to 100000, default 10000) and an optional seed"
+// @Success 200 {object} service.StockForecast
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /api/v1/stocks/{symbol}/forecast [post]
+func (h *StockForecastHandler) Forecast(c *gin.Context) {
+	var req service.ForecastRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	forecast, err := h.service.Forecast(c.Request.Context(), c.Param("symbol"), req)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidForecastHorizon, service.ErrInvalidForecastSimulations:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrStockNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrInsufficientPriceHistory:
+			respondError(c, http.StatusUnprocessableEntity, err)
+		default:
+			respondErrorMessage(c, http.StatusBadGateway, "failed to fetch price history")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
+
+// RegisterStockForecastRoutes registers the price forecast route.
+func (h *StockForecastHandler) RegisterStockForecastRoutes(rg *gin.RouterGroup) {
+	rg.POST("/stocks/:symbol/forecast", h.Forecast)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func postForecast(source service.DailyBarSource, symbol, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewStockForecastHandler(service.NewStockForecastService(source)).RegisterStockForecastRoutes(router.Group("/api/v1"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/stocks/"+symbol+"/forecast", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestStockForecastHandler_Forecast(t *testing.T) {
+	w := postForecast(stubDailyBarSource{days: 60}, "aapl", `{"horizon_days": 20, "num_simulations": 1000, "seed": 1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	for _, field := range []string{"symbol", "as_of", "current_price", "horizon_days", "num_simulations", "daily_drift", "daily_volatility", "mean", "median", "std_dev", "percentile_5", "percentile_95", "probability_above"} {
+		if _, ok := resp[field]; !ok {
+			t.Errorf("Response is missing %s: %s", field, w.Body.String())
+		}
+	}
+	if resp["symbol"] != "AAPL" || resp["current_price"] != 159.0 || resp["horizon_days"] != 20.0 {
+		t.Errorf("Unexpected forecast %s", w.Body.String())
+	}
+	if p, _ := resp["probability_above"].(float64); p < 0 || p > 1 {
+		t.Errorf("Expected probability_above within [0, 1], got %v", resp["probability_above"])
+	}
+
+	// Without a body the defaults apply.
+	w = postForecast(stubDailyBarSource{days: 60}, "AAPL", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 without a body, got %d. Body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStockForecastHandler_Forecast_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		source service.DailyBarSource
+		body   string
+		status int
+		code   string
+	}{
+		{"malformed body", stubDailyBarSource{days: 60}, `{"horizon_days": "soon"}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"horizon out of range", stubDailyBarSource{days: 60}, `{"horizon_days": 1000}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"too many simulations", stubDailyBarSource{days: 60}, `{"num_simulations": 1000000}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"unknown symbol", stubDailyBarSource{}, `{}`, http.StatusNotFound, CodeNotFound},
+		{"too little history", stubDailyBarSource{days: 10}, `{}`, http.StatusUnprocessableEntity, CodeInsufficientPriceHistory},
+		{"provider failure", stubDailyBarSource{err: errors.New("upstream error")}, `{}`, http.StatusBadGateway, CodeUpstreamError},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postForecast(tt.source, "AAPL", tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.status, w.Code, w.Body.String())
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if resp.Code != tt.code {
+				t.Errorf("Expected code %q, got %q", tt.code, resp.Code)
+			}
+		})
+	}
+}
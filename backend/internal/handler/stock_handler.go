@@ -4,10 +4,12 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
 )
 
 // StockQuoteResponse represents a stock quote response.
@@ -29,6 +31,15 @@ type StockPriceHistoryResponse struct {
 	Prices []model.StockPrice `json:"prices"`
 }
 
+// StockCandlesResponse represents resampled OHLCV candles for a stock.
+type StockCandlesResponse struct {
+	Symbol         string           `json:"symbol"`
+	Interval       string           `json:"interval"`
+	Range          string           `json:"range"`
+	SourceInterval string           `json:"source_interval"`
+	Candles        []service.Candle `json:"candles"`
+}
+
 // StockHandler handles stock-related HTTP requests.
 type StockHandler struct {
 	stockRepo repository.StockRepository
@@ -54,16 +65,16 @@ func (h *StockHandler) GetQuote(c *gin.Context) {
 	stock, err := h.stockRepo.GetBySymbol(symbol)
 	if err != nil {
 		if err == repository.ErrNotFound {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: "stock not found"})
+			respondErrorMessage(c, http.StatusNotFound, "stock not found")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to fetch stock"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch stock")
 		return
 	}
 
 	price, err := h.stockRepo.GetLatestPrice(symbol)
 	if err != nil && err != repository.ErrNotFound {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to fetch price"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch price")
 		return
 	}
 
@@ -110,10 +121,10 @@ func (h *StockHandler) GetHistory(c *gin.Context) {
 	stock, err := h.stockRepo.GetBySymbol(symbol)
 	if err != nil {
 		if err == repository.ErrNotFound {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: "stock not found"})
+			respondErrorMessage(c, http.StatusNotFound, "stock not found")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to fetch stock"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch stock")
 		return
 	}
 
@@ -127,7 +138,7 @@ func (h *StockHandler) GetHistory(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to fetch price history"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch price history")
 		return
 	}
 
@@ -137,6 +148,94 @@ func (h *StockHandler) GetHistory(c *gin.Context) {
 	})
 }
 
+// GetCandles returns OHLCV candles for a stock, resampled from the finest
+// stored price data.
+// @Summary Get stock candles
+// @Description Get OHLCV candles resampled to the requested interval. The range ends at the most recent stored bar.
+// @Tags stocks
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param interval query string false "Candle interval, e.g. 1h, 4h, 1d, 1w (default 1d)"
+// @Param range query string false "Time range, e.g. 5d, 1mo, 1y (default 1mo)"
+// @Success 200 {object} StockCandlesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/stocks/{symbol}/candles [get]
+func (h *StockHandler) GetCandles(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	intervalStr := c.DefaultQuery("interval", "1d")
+	rangeStr := c.DefaultQuery("range", "1mo")
+
+	interval, err := service.ParseCandleInterval(intervalStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	stock, err := h.stockRepo.GetBySymbol(symbol)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			respondErrorMessage(c, http.StatusNotFound, "stock not found")
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch stock")
+		return
+	}
+
+	response := StockCandlesResponse{
+		Symbol:   stock.Symbol,
+		Interval: intervalStr,
+		Range:    rangeStr,
+		Candles:  []service.Candle{},
+	}
+
+	// Fetch everything; history is returned newest first.
+	prices, err := h.stockRepo.GetPriceHistory(symbol, 0)
+	if err != nil && err != repository.ErrNotFound {
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch price history")
+		return
+	}
+	if len(prices) == 0 {
+		if _, err := service.CandleRangeStart(rangeStr, time.Now()); err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	latest := prices[0].Timestamp
+	for _, price := range prices {
+		if price.Timestamp.After(latest) {
+			latest = price.Timestamp
+		}
+	}
+	start, err := service.CandleRangeStart(rangeStr, latest)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	source := service.InferCandleInterval(prices)
+	response.SourceInterval = formatCandleInterval(source)
+
+	inRange := make([]model.StockPrice, 0, len(prices))
+	for _, price := range prices {
+		if price.Timestamp.After(start) {
+			inRange = append(inRange, price)
+		}
+	}
+
+	candles, err := service.ResampleCandles(inRange, source, interval)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error() + " (" + response.SourceInterval + ")", Code: errorCode(err, http.StatusBadRequest)})
+		return
+	}
+	response.Candles = candles
+
+	c.JSON(http.StatusOK, response)
+}
+
 // ListStocks returns all available stocks.
 // @Summary List all stocks
 // @Description Get a list of all available stocks
@@ -147,7 +246,7 @@ func (h *StockHandler) GetHistory(c *gin.Context) {
 func (h *StockHandler) ListStocks(c *gin.Context) {
 	stocks, err := h.stockRepo.GetAll()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to fetch stocks"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch stocks")
 		return
 	}
 	c.JSON(http.StatusOK, stocks)
@@ -160,5 +259,21 @@ func (h *StockHandler) RegisterStockRoutes(rg *gin.RouterGroup) {
 		stocks.GET("", h.ListStocks)
 		stocks.GET("/quotes/:symbol", h.GetQuote)
 		stocks.GET("/:symbol/history", h.GetHistory)
+		stocks.GET("/:symbol/candles", h.GetCandles)
+	}
+}
+
+// formatCandleInterval formats a duration in the units accepted by the
+// interval parameter, e.g. 4h or 1d.
+func formatCandleInterval(d time.Duration) string {
+	switch {
+	case d%(7*24*time.Hour) == 0:
+		return strconv.Itoa(int(d/(7*24*time.Hour))) + "w"
+	case d%(24*time.Hour) == 0:
+		return strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	case d%time.Hour == 0:
+		return strconv.Itoa(int(d/time.Hour)) + "h"
+	default:
+		return strconv.Itoa(int(d/time.Minute)) + "m"
 	}
 }
@@ -7,10 +7,10 @@ import (
 	"testing"
 	"time"
 
-	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // mockStockRepository is a mock implementation of StockRepository for testing.
@@ -279,3 +279,74 @@ func TestStockHandler_ListStocks(t *testing.T) {
 		t.Errorf("Expected 1 stock, got %d", len(stocks))
 	}
 }
+
+func TestStockHandler_GetCandles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newMockStockRepository()
+	stockID := repo.stocks["AAPL"].ID
+	start := time.Date(2024, 12, 2, 0, 0, 0, 0, time.UTC)
+	hourly := make([]model.StockPrice, 0, 48)
+	for i := 47; i >= 0; i-- {
+		hourly = append(hourly, model.StockPrice{
+			ID:        uuid.New(),
+			StockID:   stockID,
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      100,
+			High:      101,
+			Low:       99,
+			Close:     100.5,
+			Volume:    1000,
+		})
+	}
+	repo.priceHistory["AAPL"] = hourly
+
+	handler := NewStockHandler(repo)
+	router := gin.New()
+	handler.RegisterStockRoutes(router.Group("/api/v1"))
+
+	tests := []struct {
+		name        string
+		path        string
+		wantStatus  int
+		wantCandles int
+	}{
+		{"4h candles over a day", "/api/v1/stocks/AAPL/candles?interval=4h&range=1d", http.StatusOK, 6},
+		{"daily candles by default", "/api/v1/stocks/AAPL/candles", http.StatusOK, 2},
+		{"source interval passthrough", "/api/v1/stocks/AAPL/candles?interval=1h&range=1d", http.StatusOK, 24},
+		{"interval not a multiple", "/api/v1/stocks/AAPL/candles?interval=90m", http.StatusBadRequest, 0},
+		{"interval finer than source", "/api/v1/stocks/AAPL/candles?interval=30m", http.StatusBadRequest, 0},
+		{"invalid interval", "/api/v1/stocks/AAPL/candles?interval=abc", http.StatusBadRequest, 0},
+		{"invalid range", "/api/v1/stocks/AAPL/candles?range=1h", http.StatusBadRequest, 0},
+		{"unknown stock", "/api/v1/stocks/INVALID/candles", http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response StockCandlesResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.SourceInterval != "1h" {
+				t.Errorf("Expected source interval 1h, got %s", response.SourceInterval)
+			}
+			if len(response.Candles) != tt.wantCandles {
+				t.Fatalf("Expected %d candles, got %d", tt.wantCandles, len(response.Candles))
+			}
+			if response.Candles[0].Timestamp.After(response.Candles[len(response.Candles)-1].Timestamp) {
+				t.Error("Expected candles oldest first")
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// StockHistoryHandler serves stored daily price history.
+type StockHistoryHandler struct {
+	service service.StockHistoryService
+}
+
+// NewStockHistoryHandler creates a new StockHistoryHandler instance.
+func NewStockHistoryHandler(svc service.StockHistoryService) *StockHistoryHandler {
+	return &StockHistoryHandler{service: svc}
+}
+
+// GetHistory handles GET /api/v1/stocks/:symbol/history.
+// @Summary Get stored stock price history
+// @Description Get a stock's stored daily bars between from and to, oldest first. The range defaults to the month before now; a date-only to includes that day.
+// @Tags stocks
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param from query string false "Start, RFC3339 or YYYY-MM-DD"
+// @Param to query string false "End, RFC3339 or YYYY-MM-DD"
+// @Success 200 {object} StockPriceHistoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/stocks/{symbol}/history [get]
+func (h *StockHistoryHandler) GetHistory(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	to := time.Now().UTC()
+	if v := c.Query("to"); v != "" {
+		parsed, dateOnly, err := parseHistoryTime(v)
+		if err != nil {
+			respondErrorMessage(c, http.StatusBadRequest, "invalid to: use RFC3339 or YYYY-MM-DD")
+			return
+		}
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, -1, 0)
+	if v := c.Query("from"); v != "" {
+		parsed, _, err := parseHistoryTime(v)
+		if err != nil {
+			respondErrorMessage(c, http.StatusBadRequest, "invalid from: use RFC3339 or YYYY-MM-DD")
+			return
+		}
+		from = parsed
+	}
+
+	prices, err := h.service.GetHistory(c.Request.Context(), symbol, from, to)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidHistoryRange:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrStockNotFound:
+			respondError(c, http.StatusNotFound, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch price history")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, StockPriceHistoryResponse{Symbol: symbol, Prices: prices})
+}
+
+// SyncHistory handles POST /api/v1/stocks/:symbol/history/sync.
+// @Summary Sync stock price history
+// @Description Fetch a stock's daily bars from Alpha Vantage and store those not stored yet. A stock without stored bars gets its full history.
+// @Tags stocks
+// @Produce json
+// @Security BearerAuth
+// @Param symbol path string true "Stock symbol"
+// @Success 200 {object} service.HistorySyncResult
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/stocks/{symbol}/history/sync [post]
+func (h *StockHistoryHandler) SyncHistory(c *gin.Context) {
+	result, err := h.service.SyncDailyHistory(c.Request.Context(), c.Param("symbol"))
+	if err != nil {
+		switch err {
+		case service.ErrStockNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrHistorySyncUnavailable:
+			respondError(c, http.StatusServiceUnavailable, err)
+		default:
+			respondErrorMessage(c, http.StatusBadGateway, "failed to sync price history")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RegisterStockHistoryRoutes registers the stock price history routes.
+func (h *StockHistoryHandler) RegisterStockHistoryRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	rg.GET("/stocks/:symbol/history", h.GetHistory)
+	rg.POST("/stocks/:symbol/history/sync", authMiddleware, h.SyncHistory)
+}
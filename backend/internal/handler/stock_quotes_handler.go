@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// StockQuotesHandler serves live quotes for many stocks at once.
+type StockQuotesHandler struct {
+	service service.StockQuotesService
+}
+
+// NewStockQuotesHandler creates a new StockQuotesHandler instance.
+func NewStockQuotesHandler(svc service.StockQuotesService) *StockQuotesHandler {
+	return &StockQuotesHandler{service: svc}
+}
+
+// GetQuotes handles GET /api/v1/stocks/quotes.
+// @Summary Get live quotes for many stocks
+// @Description Get live quotes for up to 50 comma-separated symbols. Symbols that cannot be quoted are listed in warnings instead of failing the request.
+// @Tags stocks
+// @Produce json
+// @Param symbols query string true "Comma-separated symbols, e.g. AAPL,MSFT,NVDA"
+// @Success 200 {object} service.BatchQuotes
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/stocks/quotes [get]
+func (h *StockQuotesHandler) GetQuotes(c *gin.Context) {
+	symbols := strings.Split(c.Query("symbols"), ",")
+
+	quotes, err := h.service.GetQuotes(c.Request.Context(), symbols)
+	if err != nil {
+		switch err {
+		case service.ErrNoQuoteSymbols, service.ErrTooManyQuoteSymbols:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch quotes")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, quotes)
+}
+
+// RegisterStockQuotesRoutes registers the batch quote route.
+func (h *StockQuotesHandler) RegisterStockQuotesRoutes(rg *gin.RouterGroup) {
+	rg.GET("/stocks/quotes", h.GetQuotes)
+}
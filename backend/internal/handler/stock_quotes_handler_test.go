@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// stubBatchQuoteSource quotes the symbols it has prices for and fails the rest.
+type stubBatchQuoteSource struct {
+	prices  map[string]float64
+	symbols []string
+}
+
+func (s *stubBatchQuoteSource) GetQuotes(ctx context.Context, symbols []string) ([]service.SymbolQuote, map[string]error) {
+	s.symbols = symbols
+	var quotes []service.SymbolQuote
+	failed := make(map[string]error)
+	for _, symbol := range symbols {
+		if price, ok := s.prices[symbol]; ok {
+			quotes = append(quotes, service.SymbolQuote{Symbol: symbol, Price: price})
+		} else {
+			failed[symbol] = errors.New("get quote: upstream error")
+		}
+	}
+	return quotes, failed
+}
+
+func getQuotes(source service.BatchQuoteSource, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewStockQuotesHandler(service.NewStockQuotesService(source)).RegisterStockQuotesRoutes(router.Group("/api/v1"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/stocks/quotes"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestStockQuotesHandler_GetQuotes_PartialFailure(t *testing.T) {
+	source := &stubBatchQuoteSource{prices: map[string]float64{"AAPL": 190, "NVDA": 120}}
+	w := getQuotes(source, "?symbols=aapl,XXXX,NVDA,AAPL.US")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if strings.Join(source.symbols, ",") != "AAPL,XXXX,NVDA" {
+		t.Errorf("Expected normalized, de-duplicated symbols, got %v", source.symbols)
+	}
+
+	var resp service.BatchQuotes
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.Quotes) != 2 || resp.Quotes[0].Symbol != "AAPL" || resp.Quotes[1].Symbol != "NVDA" {
+		t.Errorf("Expected AAPL and NVDA quotes, got %+v", resp.Quotes)
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0].Symbol != "XXXX" {
+		t.Errorf("Expected a warning naming XXXX, got %+v", resp.Warnings)
+	}
+	if strings.Contains(w.Body.String(), "upstream error") {
+		t.Errorf("Expected provider errors to stay out of the response, got %s", w.Body.String())
+	}
+}
+
+func TestStockQuotesHandler_GetQuotes_BadRequests(t *testing.T) {
+	symbols := make([]string, service.MaxQuoteSymbols+1)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("S%d", i)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{"no symbols", "", CodeInvalidRequest},
+		{"blank symbols", "?symbols=,%20,", CodeInvalidRequest},
+		{"too many symbols", "?symbols=" + strings.Join(symbols, ","), CodeTooManyItems},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &stubBatchQuoteSource{}
+			w := getQuotes(source, tt.query)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status 400, got %d. Body: %s", w.Code, w.Body.String())
+			}
+			var errResp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil || errResp.Code != tt.code {
+				t.Errorf("Expected code %q, got %s", tt.code, w.Body.String())
+			}
+			if source.symbols != nil {
+				t.Errorf("Expected no quotes to be fetched, got a request for %v", source.symbols)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// TechnicalAnalysisHandler serves technical indicators computed from daily bars.
+type TechnicalAnalysisHandler struct {
+	service service.TechnicalAnalysisService
+}
+
+// NewTechnicalAnalysisHandler creates a new TechnicalAnalysisHandler instance.
+func NewTechnicalAnalysisHandler(svc service.TechnicalAnalysisService) *TechnicalAnalysisHandler {
+	return &TechnicalAnalysisHandler{service: svc}
+}
+
+// GetTechnicalAnalysis handles GET /api/v1/technical/:symbol.
+// @Summary Get technical indicators for a stock
+// @Description Compute indicators from the stock's recent daily bars and return each one's latest values and last 30 points. Supported indicators are sma, ema, rsi, macd, bbands, atr, stoch, willr, cci and obv; MACD always uses 12/26/9.
+// @Tags stocks
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param indicators query string false "Comma-separated indicators (default rsi,macd,bbands)"
+// @Param period query int false "Lookback period, 2 to 50 (default 14)"
+// @Success 200 {object} service.TechnicalAnalysis
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /api/v1/technical/{symbol} [get]
+func (h *TechnicalAnalysisHandler) GetTechnicalAnalysis(c *gin.Context) {
+	period := 0
+	if v := c.Query("period"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, service.ErrInvalidIndicatorPeriod)
+			return
+		}
+		period = parsed
+	}
+
+	var indicators []string
+	if v := c.Query("indicators"); v != "" {
+		indicators = strings.Split(v, ",")
+	}
+
+	analysis, err := h.service.Analyze(c.Request.Context(), c.Param("symbol"), indicators, period)
+	if err != nil {
+		switch err {
+		case service.ErrUnknownIndicator, service.ErrInvalidIndicatorPeriod:
+			respondError(c, http.StatusBadRequest, err)
+		case service.ErrStockNotFound:
+			respondError(c, http.StatusNotFound, err)
+		case service.ErrInsufficientPriceHistory:
+			respondError(c, http.StatusUnprocessableEntity, err)
+		default:
+			respondErrorMessage(c, http.StatusBadGateway, "failed to fetch price history")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// RegisterTechnicalAnalysisRoutes registers the technical analysis route.
+func (h *TechnicalAnalysisHandler) RegisterTechnicalAnalysisRoutes(rg *gin.RouterGroup) {
+	rg.GET("/technical/:symbol", h.GetTechnicalAnalysis)
+}
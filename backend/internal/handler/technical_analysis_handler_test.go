@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// stubDailyBarSource returns bars whose close rises by 1 a day, or err.
+type stubDailyBarSource struct {
+	days int
+	err  error
+}
+
+func (s stubDailyBarSource) GetDailyBars(ctx context.Context, symbol string, full bool) ([]model.StockPrice, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	bars := make([]model.StockPrice, s.days)
+	for i := range bars {
+		price := 100 + float64(i)
+		bars[i] = model.StockPrice{Timestamp: start.AddDate(0, 0, i), Open: price, High: price + 1, Low: price - 1, Close: price}
+	}
+	return bars, nil
+}
+
+func getTechnicalAnalysis(source service.DailyBarSource, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewTechnicalAnalysisHandler(service.NewTechnicalAnalysisService(source)).RegisterTechnicalAnalysisRoutes(router.Group("/api/v1"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/technical/"+path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTechnicalAnalysisHandler_GetTechnicalAnalysis(t *testing.T) {
+	w := getTechnicalAnalysis(stubDailyBarSource{days: 60}, "aapl?indicators=rsi,macd&period=14")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var resp service.TechnicalAnalysis
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Symbol != "AAPL" || resp.Period != 14 {
+		t.Errorf("Expected AAPL with period 14, got %s with %d", resp.Symbol, resp.Period)
+	}
+	if _, ok := resp.Indicators["rsi"].Latest.Values["value"]; !ok {
+		t.Errorf("Expected a latest RSI value, got %+v", resp.Indicators["rsi"])
+	}
+	if _, ok := resp.Indicators["macd"].Latest.Values["signal"]; !ok {
+		t.Errorf("Expected a latest MACD signal, got %+v", resp.Indicators["macd"])
+	}
+}
+
+func TestTechnicalAnalysisHandler_GetTechnicalAnalysis_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		source service.DailyBarSource
+		path   string
+		status int
+		code   string
+	}{
+		{"unknown indicator", stubDailyBarSource{days: 60}, "AAPL?indicators=rsi,foo", http.StatusBadRequest, CodeUnknownIndicator},
+		{"non-numeric period", stubDailyBarSource{days: 60}, "AAPL?period=abc", http.StatusBadRequest, CodeInvalidRequest},
+		{"period out of range", stubDailyBarSource{days: 60}, "AAPL?period=500", http.StatusBadRequest, CodeInvalidRequest},
+		{"unknown symbol", stubDailyBarSource{}, "XXXX", http.StatusNotFound, CodeNotFound},
+		{"too little history", stubDailyBarSource{days: 10}, "AAPL?indicators=macd", http.StatusUnprocessableEntity, CodeInsufficientPriceHistory},
+		{"provider failure", stubDailyBarSource{err: errors.New("upstream error")}, "AAPL", http.StatusBadGateway, CodeUpstreamError},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getTechnicalAnalysis(tt.source, tt.path)
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.status, w.Code, w.Body.String())
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if resp.Code != tt.code {
+				t.Errorf("Expected code %q, got %q", tt.code, resp.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ValuationHandler handles fair value HTTP requests.
+type ValuationHandler struct {
+	fairValueService service.FairValueService
+}
+
+// NewValuationHandler creates a new ValuationHandler instance.
+func NewValuationHandler(fairValueService service.FairValueService) *ValuationHandler {
+	return &ValuationHandler{fairValueService: fairValueService}
+}
+
+// FairValuePoint represents one fair value calculation in a history response.
+type FairValuePoint struct {
+	FairValue      float64 `json:"fair_value"`
+	CurrentPrice   float64 `json:"current_price"`
+	MarginOfSafety float64 `json:"margin_of_safety"`
+	UpsidePercent  float64 `json:"upside_percent"`
+	Recommendation string  `json:"recommendation"`
+	CalculatedAt   string  `json:"calculated_at"`
+}
+
+// FairValueHistoryResponse represents how a stock's fair value evolved over time.
+type FairValueHistoryResponse struct {
+	Symbol     string                           `json:"symbol"`
+	Thresholds service.RecommendationThresholds `json:"thresholds"`
+	History    []FairValuePoint                 `json:"history"`
+}
+
+// FairValueResponse represents a stock's current fair value and the models
+// behind it. Models that could not be computed are 0.
+type FairValueResponse struct {
+	Symbol         string                           `json:"symbol"`
+	FairValue      float64                          `json:"fair_value"`
+	DCFValue       float64                          `json:"dcf_value"`
+	PEValue        float64                          `json:"pe_value"`
+	GrahamValue    float64                          `json:"graham_value"`
+	CurrentPrice   float64                          `json:"current_price"`
+	MarginOfSafety float64                          `json:"margin_of_safety"`
+	UpsidePercent  float64                          `json:"upside_percent"`
+	Recommendation string                           `json:"recommendation"`
+	Thresholds     service.RecommendationThresholds `json:"thresholds"`
+	CalculatedAt   string                           `json:"calculated_at"`
+}
+
+// GetFairValue calculates a stock's current fair value.
+// @Summary Get fair value
+// @Description Value a stock from its fundamentals with a two-stage DCF, the sector median P/E and the Graham number, weighted 40/30/30. Models whose inputs are missing or not positive are left out of the average. The recommendation follows the authenticated user's margin-of-safety thresholds, or the defaults for anonymous requests.
+// @Tags valuation
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Success 200 {object} FairValueResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/valuation/{symbol} [get]
+func (h *ValuationHandler) GetFairValue(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	fv, err := h.fairValueService.Calculate(symbol)
+	if err != nil {
+		switch err {
+		case service.ErrValuationUnavailable:
+			respondError(c, http.StatusServiceUnavailable, err)
+		case service.ErrInsufficientFundamentals, service.ErrNoCurrentPrice:
+			respondError(c, http.StatusUnprocessableEntity, err)
+		default:
+			respondErrorMessage(c, http.StatusBadGateway, "failed to fetch fundamentals or quote")
+		}
+		return
+	}
+
+	thresholds := service.DefaultRecommendationThresholds
+	if userID, err := currentUserID(c); err == nil {
+		thresholds, err = h.fairValueService.Thresholds(c.Request.Context(), userID)
+		if err != nil {
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch recommendation thresholds")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, FairValueResponse{
+		Symbol:         symbol,
+		FairValue:      fv.WeightedAvg,
+		DCFValue:       fv.DCFValue,
+		PEValue:        fv.PEValue,
+		GrahamValue:    fv.GrahamValue,
+		CurrentPrice:   fv.CurrentPrice,
+		MarginOfSafety: fv.MarginOfSafety,
+		UpsidePercent:  fv.UpsidePercent,
+		Recommendation: thresholds.Recommend(fv.MarginOfSafety),
+		Thresholds:     thresholds,
+		CalculatedAt:   fv.CalculatedAt.Format(time.RFC3339),
+	})
+}
+
+// GetHistory returns the fair value history for a stock.
+// @Summary Get fair value history
+// @Description Get a stock's fair value calculations over time, newest first. Recommendations follow the authenticated user's buy/sell margin-of-safety thresholds, or the defaults for anonymous requests.
+// @Tags valuation
+// @Produce json
+// @Param symbol path string true "Stock symbol"
+// @Param days query int false "Number of days of history to return (default 365)"
+// @Success 200 {object} FairValueHistoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api/v1/valuation/{symbol}/history [get]
+func (h *ValuationHandler) GetHistory(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	days := 0
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil {
+			respondErrorMessage(c, http.StatusBadRequest, "invalid days")
+			return
+		}
+		days = parsed
+	}
+
+	history, err := h.fairValueService.GetHistory(c.Request.Context(), symbol, days)
+	if err != nil {
+		if err == service.ErrInvalidHistoryDays {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch fair value history")
+		return
+	}
+
+	thresholds := service.DefaultRecommendationThresholds
+	if userID, err := currentUserID(c); err == nil {
+		thresholds, err = h.fairValueService.Thresholds(c.Request.Context(), userID)
+		if err != nil {
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch recommendation thresholds")
+			return
+		}
+	}
+
+	response := FairValueHistoryResponse{
+		Symbol:     symbol,
+		Thresholds: thresholds,
+		History:    make([]FairValuePoint, len(history)),
+	}
+	for i := range history {
+		response.History[i] = fairValueToPoint(&history[i])
+		response.History[i].Recommendation = thresholds.Recommend(history[i].MarginOfSafety)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RegisterValuationRoutes registers valuation routes. optionalAuth
+// identifies the user, when there is one, for personal recommendations.
+func (h *ValuationHandler) RegisterValuationRoutes(rg *gin.RouterGroup, optionalAuth gin.HandlerFunc) {
+	valuation := rg.Group("/valuation")
+	valuation.Use(optionalAuth)
+	{
+		valuation.GET("/:symbol", h.GetFairValue)
+		valuation.GET("/:symbol/history", h.GetHistory)
+	}
+}
+
+// fairValueToPoint converts a model.FairValue to FairValuePoint.
+func fairValueToPoint(fv *model.FairValue) FairValuePoint {
+	return FairValuePoint{
+		FairValue:      fv.WeightedAvg,
+		CurrentPrice:   fv.CurrentPrice,
+		MarginOfSafety: fv.MarginOfSafety,
+		UpsidePercent:  fv.UpsidePercent,
+		Recommendation: fv.Recommendation,
+		CalculatedAt:   fv.CalculatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// stubFairValueCalculator returns a fixed fair value, or err.
+type stubFairValueCalculator struct {
+	fv  model.FairValue
+	err error
+}
+
+func (s *stubFairValueCalculator) Calculate(symbol string) (*model.FairValue, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	fv := s.fv
+	fv.Symbol = symbol
+	return &fv, nil
+}
+
+func setupValuationHandler(calculator service.FairValueCalculator) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	svc := service.NewFairValueService(nil, calculator, nil, nil, nil)
+	NewValuationHandler(svc).RegisterValuationRoutes(router.Group("/api/v1"), func(c *gin.Context) { c.Next() })
+	return router
+}
+
+func TestValuationHandler_GetFairValue(t *testing.T) {
+	router := setupValuationHandler(&stubFairValueCalculator{fv: model.FairValue{
+		DCFValue:       130,
+		PEValue:        120,
+		WeightedAvg:    125,
+		CurrentPrice:   100,
+		MarginOfSafety: 20,
+		UpsidePercent:  25,
+	}})
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/valuation/aapl", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var resp FairValueResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if resp.Symbol != "AAPL" || resp.FairValue != 125 || resp.GrahamValue != 0 || resp.Recommendation != service.RecommendationBuy {
+		t.Errorf("Unexpected fair value %+v", resp)
+	}
+}
+
+func TestValuationHandler_GetFairValue_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		calculator service.FairValueCalculator
+		wantStatus int
+		wantCode   string
+	}{
+		{"no calculator", nil, http.StatusServiceUnavailable, CodeServiceUnavailable},
+		{"missing fundamentals", &stubFairValueCalculator{err: service.ErrInsufficientFundamentals}, http.StatusUnprocessableEntity, CodeInsufficientFundamentals},
+		{"upstream failure", &stubFairValueCalculator{err: errors.New("rate limited")}, http.StatusBadGateway, CodeUpstreamError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupValuationHandler(tt.calculator)
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/valuation/AAPL", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("Expected code %q, got %q", tt.wantCode, resp.Code)
+			}
+		})
+	}
+}
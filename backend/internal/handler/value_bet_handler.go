@@ -2,81 +2,75 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
 	"github.com/gin-gonic/gin"
-
-	"super-dashboard/backend/internal/service"
+	"github.com/google/uuid"
 )
 
-// ValueBetHandler handles value bet-related HTTP requests.
+// ValueBetHandler handles value bet HTTP requests.
 type ValueBetHandler struct {
-	valueBetService *service.ValueBetService
+	service service.ValueBetService
 }
 
-// NewValueBetHandler creates a new ValueBetHandler.
-func NewValueBetHandler(valueBetService *service.ValueBetService) *ValueBetHandler {
-	return &ValueBetHandler{
-		valueBetService: valueBetService,
-	}
+// NewValueBetHandler creates a new ValueBetHandler instance.
+func NewValueBetHandler(svc service.ValueBetService) *ValueBetHandler {
+	return &ValueBetHandler{service: svc}
 }
 
-// GetValueBets handles GET /api/value-bets
-func (h *ValueBetHandler) GetValueBets(c *gin.Context) {
-	minValueStr := c.DefaultQuery("min_value", "5.0")
-	minValue, err := strconv.ParseFloat(minValueStr, 64)
-	if err != nil {
-		minValue = 5.0
-	}
-
-	valueBets, err := h.valueBetService.DetectValueBets(c.Request.Context(), minValue)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"value_bets": valueBets})
+// ValueBetsResponse represents the value bets detected for a match.
+type ValueBetsResponse struct {
+	MatchID   string           `json:"match_id"`
+	Threshold float64          `json:"threshold"`
+	ValueBets []model.ValueBet `json:"value_bets"`
 }
 
-// GetTopValueBets handles GET /api/value-bets/top
-func (h *ValueBetHandler) GetTopValueBets(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 50 {
-		limit = 10
-	}
-
-	valueBets, err := h.valueBetService.GetTopValueBets(c.Request.Context(), limit)
+// GetValueBets handles GET /api/v1/value-bets.
+// @Summary Detect value bets for a match
+// @Description Compare each bookmaker's latest odds for a match with the model's true probabilities and return bets whose value, (true probability * odds - 1) * 100, reaches the authenticated user's value bet threshold, or the default for anonymous requests. Implied probabilities are reported with the bookmaker's margin removed. The detected bets replace those stored for the match.
+// @Tags betting
+// @Produce json
+// @Param match_id query string true "Match ID"
+// @Success 200 {object} ValueBetsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/value-bets [get]
+func (h *ValueBetHandler) GetValueBets(c *gin.Context) {
+	matchID, err := uuid.Parse(c.Query("match_id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid match_id")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"value_bets": valueBets})
-}
-
-// GetValueBetsByLeague handles GET /api/value-bets/league/:league
-func (h *ValueBetHandler) GetValueBetsByLeague(c *gin.Context) {
-	league := c.Param("league")
+	threshold := service.DefaultValueBetThreshold
+	if userID, err := currentUserID(c); err == nil {
+		threshold, err = h.service.Threshold(c.Request.Context(), userID)
+		if err != nil {
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to fetch value bet threshold")
+			return
+		}
+	}
 
-	valueBets, err := h.valueBetService.GetValueBetsByLeague(c.Request.Context(), league)
+	bets, err := h.service.DetectAbove(matchID, threshold)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err == service.ErrMatchNotFound {
+			respondError(c, http.StatusNotFound, err)
+			return
+		}
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to detect value bets")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"value_bets": valueBets})
+	c.JSON(http.StatusOK, ValueBetsResponse{
+		MatchID:   matchID.String(),
+		Threshold: threshold,
+		ValueBets: bets,
+	})
 }
 
-// GetValueBetStatistics handles GET /api/value-bets/statistics
-func (h *ValueBetHandler) GetValueBetStatistics(c *gin.Context) {
-	period := c.DefaultQuery("period", "week")
-
-	stats, err := h.valueBetService.GetValueBetStatistics(c.Request.Context(), period)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, stats)
+// RegisterValueBetRoutes registers value bet routes. optionalAuth identifies
+// the user, when there is one, for their value bet threshold.
+func (h *ValueBetHandler) RegisterValueBetRoutes(rg *gin.RouterGroup, optionalAuth gin.HandlerFunc) {
+	rg.GET("/value-bets", optionalAuth, h.GetValueBets)
 }
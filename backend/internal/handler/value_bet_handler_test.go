@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// oneMatchStore holds a single match and its odds.
+type oneMatchStore struct {
+	match model.Match
+	odds  []model.Odds
+}
+
+func (s *oneMatchStore) GetMatch(ctx context.Context, matchID uuid.UUID) (*model.Match, error) {
+	if matchID != s.match.ID {
+		return nil, errors.New("record not found")
+	}
+	return &s.match, nil
+}
+
+func (s *oneMatchStore) GetMatchOdds(ctx context.Context, matchID uuid.UUID) ([]model.Odds, error) {
+	return s.odds, nil
+}
+
+func (s *oneMatchStore) ReplaceMatchValueBets(ctx context.Context, matchID uuid.UUID, bets []model.ValueBet) error {
+	return nil
+}
+
+// setupValueBetRouter serves value bets for store. A non-nil settings
+// authenticates every request as a user with those settings.
+func setupValueBetRouter(store *oneMatchStore, settings *model.Settings) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	var settingsStore service.ValueBetSettingsStore
+	optionalAuth := func(c *gin.Context) { c.Next() }
+	if settings != nil {
+		settingsStore = fixedSettings(*settings)
+		optionalAuth = func(c *gin.Context) {
+			c.Set("user_id", uuid.New().String())
+			c.Next()
+		}
+	}
+	svc := service.NewValueBetService(store, service.DefaultEloProbabilityModel, settingsStore)
+	NewValueBetHandler(svc).RegisterValueBetRoutes(router.Group("/api/v1"), optionalAuth)
+	return router
+}
+
+// evenMatch is a match between evenly rated sides, once home advantage is
+// counted: 40% home, 20% draw, 40% away.
+func evenMatch() *oneMatchStore {
+	id := uuid.New()
+	return &oneMatchStore{
+		match: model.Match{ID: id, HomeTeam: model.Team{Elo: 1600}, AwayTeam: model.Team{Elo: 1700}},
+		odds: []model.Odds{
+			{MatchID: id, Bookmaker: "Bet365", Market: "1X2", Outcome: "1", Price: 2.8}, // 12% value
+			{MatchID: id, Bookmaker: "Bet365", Market: "1X2", Outcome: "X", Price: 5.3}, // 6% value
+			{MatchID: id, Bookmaker: "Bet365", Market: "1X2", Outcome: "2", Price: 2.0},
+		},
+	}
+}
+
+func TestValueBetHandler_GetValueBets(t *testing.T) {
+	tests := []struct {
+		name          string
+		settings      *model.Settings
+		wantThreshold float64
+		wantBets      int
+	}{
+		{"anonymous uses default threshold", nil, service.DefaultValueBetThreshold, 2},
+		{"user threshold", &model.Settings{ValueBetThreshold: 10}, 10, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := evenMatch()
+			router := setupValueBetRouter(store, tt.settings)
+
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/value-bets?match_id="+store.match.ID.String(), nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+			}
+			var resp ValueBetsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if resp.Threshold != tt.wantThreshold || len(resp.ValueBets) != tt.wantBets {
+				t.Errorf("Expected %d bets at threshold %v, got %+v", tt.wantBets, tt.wantThreshold, resp)
+			}
+			if len(resp.ValueBets) > 0 && resp.ValueBets[0].Selection != "1" {
+				t.Errorf("Expected the home win first, got %+v", resp.ValueBets[0])
+			}
+		})
+	}
+}
+
+func TestValueBetHandler_GetValueBets_Errors(t *testing.T) {
+	router := setupValueBetRouter(evenMatch(), nil)
+
+	for url, want := range map[string]int{
+		"/api/v1/value-bets":                                 http.StatusBadRequest,
+		"/api/v1/value-bets?match_id=abc":                    http.StatusBadRequest,
+		"/api/v1/value-bets?match_id=" + uuid.New().String(): http.StatusNotFound,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d. Body: %s", url, want, w.Code, w.Body.String())
+		}
+	}
+}
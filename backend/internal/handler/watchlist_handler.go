@@ -3,192 +3,265 @@ package handler
 import (
 	"net/http"
 
+	"github.com/awaymess/super-dashboard/backend/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-
-	"super-dashboard/backend/internal/service"
 )
 
-// WatchlistHandler handles watchlist-related HTTP requests.
+// WatchlistHandler handles a user's watchlists and their stocks.
 type WatchlistHandler struct {
-	watchlistService *service.WatchlistService
+	service service.WatchlistService
 }
 
-// NewWatchlistHandler creates a new WatchlistHandler.
-func NewWatchlistHandler(watchlistService *service.WatchlistService) *WatchlistHandler {
-	return &WatchlistHandler{
-		watchlistService: watchlistService,
-	}
+// NewWatchlistHandler creates a new WatchlistHandler instance.
+func NewWatchlistHandler(svc service.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{service: svc}
 }
 
-// CreateWatchlist handles POST /api/watchlists
-func (h *WatchlistHandler) CreateWatchlist(c *gin.Context) {
-	userID, _ := c.Get("user_id")
+// CreateWatchlistRequest represents a request to create a watchlist.
+type CreateWatchlistRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
 
-	var req struct {
-		Name        string `json:"name" binding:"required"`
-		Description string `json:"description"`
-	}
+// AddWatchlistItemRequest represents a request to add a stock to a watchlist.
+type AddWatchlistItemRequest struct {
+	StockID string `json:"stock_id" binding:"required"`
+	Notes   string `json:"notes"`
+}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// CreateWatchlist handles POST /api/v1/watchlists.
+// @Summary Create a watchlist
+// @Description Create a watchlist for the current user
+// @Tags watchlists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateWatchlistRequest true "Watchlist to create"
+// @Success 201 {object} model.Watchlist
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/watchlists [post]
+func (h *WatchlistHandler) CreateWatchlist(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
-	watchlist, err := h.watchlistService.CreateWatchlist(c.Request.Context(), userID.(uuid.UUID), req.Name, req.Description)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req CreateWatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"watchlist": watchlist})
-}
-
-// GetWatchlists handles GET /api/watchlists
-func (h *WatchlistHandler) GetWatchlists(c *gin.Context) {
-	userID, _ := c.Get("user_id")
-
-	watchlists, err := h.watchlistService.GetUserWatchlists(c.Request.Context(), userID.(uuid.UUID))
+	watchlist, err := h.service.Create(c.Request.Context(), userID, req.Name, req.Description)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch err {
+		case service.ErrWatchlistNameRequired:
+			respondError(c, http.StatusBadRequest, err)
+		default:
+			respondErrorMessage(c, http.StatusInternalServerError, "failed to create watchlist")
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"watchlists": watchlists})
+	c.JSON(http.StatusCreated, watchlist)
 }
 
-// GetWatchlist handles GET /api/watchlists/:id
-func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
-	watchlistID, err := uuid.Parse(c.Param("id"))
+// ListWatchlists handles GET /api/v1/watchlists.
+// @Summary List watchlists
+// @Description List the current user's watchlists with their items, newest first
+// @Tags watchlists
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.Watchlist
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/watchlists [get]
+func (h *WatchlistHandler) ListWatchlists(c *gin.Context) {
+	userID, err := currentUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid watchlist ID"})
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
-	watchlist, err := h.watchlistService.GetWatchlist(c.Request.Context(), watchlistID)
+	watchlists, err := h.service.List(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "watchlist not found"})
+		respondErrorMessage(c, http.StatusInternalServerError, "failed to list watchlists")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"watchlist": watchlist})
+	c.JSON(http.StatusOK, watchlists)
 }
 
-// UpdateWatchlist handles PUT /api/watchlists/:id
-func (h *WatchlistHandler) UpdateWatchlist(c *gin.Context) {
-	watchlistID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid watchlist ID"})
-		return
-	}
-
-	var req struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// GetWatchlist handles GET /api/v1/watchlists/:id.
+// @Summary Get a watchlist
+// @Description Get one of the current user's watchlists with a current quote for each stock. Items without a live quote omit it.
+// @Tags watchlists
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Watchlist ID"
+// @Success 200 {object} service.WatchlistDetail
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/watchlists/{id} [get]
+func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
+	userID, watchlistID, ok := h.watchlistParams(c)
+	if !ok {
 		return
 	}
 
-	if err := h.watchlistService.UpdateWatchlist(c.Request.Context(), watchlistID, req.Name, req.Description); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	watchlist, err := h.service.Get(c.Request.Context(), userID, watchlistID)
+	if err != nil {
+		h.respondWatchlistError(c, err, "failed to get watchlist")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Watchlist updated successfully"})
+	c.JSON(http.StatusOK, watchlist)
 }
 
-// DeleteWatchlist handles DELETE /api/watchlists/:id
+// DeleteWatchlist handles DELETE /api/v1/watchlists/:id.
+// @Summary Delete a watchlist
+// @Description Delete one of the current user's watchlists and its items
+// @Tags watchlists
+// @Security BearerAuth
+// @Param id path string true "Watchlist ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/watchlists/{id} [delete]
 func (h *WatchlistHandler) DeleteWatchlist(c *gin.Context) {
-	watchlistID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid watchlist ID"})
+	userID, watchlistID, ok := h.watchlistParams(c)
+	if !ok {
 		return
 	}
 
-	if err := h.watchlistService.DeleteWatchlist(c.Request.Context(), watchlistID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.service.Delete(c.Request.Context(), userID, watchlistID); err != nil {
+		h.respondWatchlistError(c, err, "failed to delete watchlist")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Watchlist deleted successfully"})
+	c.Status(http.StatusNoContent)
 }
 
-// AddStock handles POST /api/watchlists/:id/stocks
-func (h *WatchlistHandler) AddStock(c *gin.Context) {
-	watchlistID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid watchlist ID"})
+// AddItem handles POST /api/v1/watchlists/:id/items.
+// @Summary Add a stock to a watchlist
+// @Description Add a stock to one of the current user's watchlists. A stock can be in a watchlist only once.
+// @Tags watchlists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Watchlist ID"
+// @Param request body AddWatchlistItemRequest true "Stock to add"
+// @Success 201 {object} model.WatchlistItem
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/watchlists/{id}/items [post]
+func (h *WatchlistHandler) AddItem(c *gin.Context) {
+	userID, watchlistID, ok := h.watchlistParams(c)
+	if !ok {
 		return
 	}
 
-	var req struct {
-		Symbol string `json:"symbol" binding:"required"`
-	}
-
+	var req AddWatchlistItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
-
-	if err := h.watchlistService.AddStock(c.Request.Context(), watchlistID, req.Symbol); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	stockID, err := uuid.Parse(req.StockID)
+	if err != nil {
+		respondErrorMessage(c, http.StatusBadRequest, "invalid stock id")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Stock added to watchlist"})
-}
-
-// RemoveStock handles DELETE /api/watchlists/:id/stocks/:symbol
-func (h *WatchlistHandler) RemoveStock(c *gin.Context) {
-	watchlistID, err := uuid.Parse(c.Param("id"))
+	item, err := h.service.AddItem(c.Request.Context(), userID, watchlistID, stockID, req.Notes)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid watchlist ID"})
+		h.respondWatchlistError(c, err, "failed to add stock")
 		return
 	}
 
-	symbol := c.Param("symbol")
+	c.JSON(http.StatusCreated, item)
+}
 
-	if err := h.watchlistService.RemoveStock(c.Request.Context(), watchlistID, symbol); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// RemoveItem handles DELETE /api/v1/watchlists/:id/items/:stockId.
+// @Summary Remove a stock from a watchlist
+// @Description Remove a stock from one of the current user's watchlists
+// @Tags watchlists
+// @Security BearerAuth
+// @Param id path string true "Watchlist ID"
+// @Param stockId path string true "Stock ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/watchlists/{id}/items/{stockId} [delete]
+func (h *WatchlistHandler) RemoveItem(c *gin.Context) {
+	userID, watchlistID, ok := h.watchlistParams(c)
+	if !ok {
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Stock removed from watchlist"})
-}
-
-// GetWatchlistStocks handles GET /api/watchlists/:id/stocks
-func (h *WatchlistHandler) GetWatchlistStocks(c *gin.Context) {
-	watchlistID, err := uuid.Parse(c.Param("id"))
+	stockID, err := uuid.Parse(c.Param("stockId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid watchlist ID"})
+		respondErrorMessage(c, http.StatusBadRequest, "invalid stock id")
 		return
 	}
 
-	stocks, err := h.watchlistService.GetWatchlistStocks(c.Request.Context(), watchlistID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.RemoveItem(c.Request.Context(), userID, watchlistID, stockID); err != nil {
+		h.respondWatchlistError(c, err, "failed to remove stock")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"stocks": stocks})
+	c.Status(http.StatusNoContent)
 }
 
-// GetWatchlistSummary handles GET /api/watchlists/:id/summary
-func (h *WatchlistHandler) GetWatchlistSummary(c *gin.Context) {
+// watchlistParams returns the current user and the watchlist ID from the
+// path, responding with an error if either is missing or invalid.
+func (h *WatchlistHandler) watchlistParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondErrorMessage(c, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
 	watchlistID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid watchlist ID"})
-		return
+		respondErrorMessage(c, http.StatusBadRequest, "invalid watchlist id")
+		return uuid.Nil, uuid.Nil, false
 	}
+	return userID, watchlistID, true
+}
 
-	summary, err := h.watchlistService.GetWatchlistSummary(c.Request.Context(), watchlistID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// respondWatchlistError maps watchlist service errors to HTTP responses.
+func (h *WatchlistHandler) respondWatchlistError(c *gin.Context, err error, message string) {
+	switch err {
+	case service.ErrWatchlistNotFound, service.ErrWatchlistItemNotFound, service.ErrStockNotFound:
+		respondError(c, http.StatusNotFound, err)
+	case service.ErrWatchlistForbidden:
+		respondError(c, http.StatusForbidden, err)
+	case service.ErrWatchlistItemExists:
+		respondError(c, http.StatusConflict, err)
+	default:
+		respondErrorMessage(c, http.StatusInternalServerError, message)
 	}
+}
 
-	c.JSON(http.StatusOK, summary)
+// RegisterWatchlistRoutes registers watchlist routes.
+func (h *WatchlistHandler) RegisterWatchlistRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	watchlists := rg.Group("/watchlists")
+	watchlists.Use(authMiddleware)
+	{
+		watchlists.POST("", h.CreateWatchlist)
+		watchlists.GET("", h.ListWatchlists)
+		watchlists.GET("/:id", h.GetWatchlist)
+		watchlists.DELETE("/:id", h.DeleteWatchlist)
+		watchlists.POST("/:id/items", h.AddItem)
+		watchlists.DELETE("/:id/items/:stockId", h.RemoveItem)
+	}
 }
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// mockWatchlistRepository stores watchlists and a fixed set of stocks in memory.
+type mockWatchlistRepository struct {
+	watchlists map[uuid.UUID]*model.Watchlist
+	stocks     map[uuid.UUID]model.Stock
+}
+
+func newMockWatchlistRepository(stocks ...model.Stock) *mockWatchlistRepository {
+	repo := &mockWatchlistRepository{watchlists: make(map[uuid.UUID]*model.Watchlist), stocks: make(map[uuid.UUID]model.Stock)}
+	for _, stock := range stocks {
+		repo.stocks[stock.ID] = stock
+	}
+	return repo
+}
+
+func (m *mockWatchlistRepository) Create(watchlist *model.Watchlist) error {
+	stored := *watchlist
+	m.watchlists[watchlist.ID] = &stored
+	return nil
+}
+
+func (m *mockWatchlistRepository) GetByID(id uuid.UUID) (*model.Watchlist, error) {
+	watchlist, ok := m.watchlists[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	found := *watchlist
+	found.Items = append([]model.WatchlistItem(nil), watchlist.Items...)
+	return &found, nil
+}
+
+func (m *mockWatchlistRepository) GetByUserID(userID uuid.UUID) ([]model.Watchlist, error) {
+	var watchlists []model.Watchlist
+	for _, watchlist := range m.watchlists {
+		if watchlist.UserID == userID {
+			watchlists = append(watchlists, *watchlist)
+		}
+	}
+	return watchlists, nil
+}
+
+func (m *mockWatchlistRepository) Delete(id uuid.UUID) error {
+	delete(m.watchlists, id)
+	return nil
+}
+
+func (m *mockWatchlistRepository) GetStock(id uuid.UUID) (*model.Stock, error) {
+	stock, ok := m.stocks[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &stock, nil
+}
+
+func (m *mockWatchlistRepository) AddItem(item *model.WatchlistItem) error {
+	watchlist := m.watchlists[item.WatchlistID]
+	stored := *item
+	stored.Stock = m.stocks[item.StockID]
+	watchlist.Items = append(watchlist.Items, stored)
+	return nil
+}
+
+func (m *mockWatchlistRepository) RemoveItem(watchlistID, stockID uuid.UUID) error {
+	watchlist := m.watchlists[watchlistID]
+	items := watchlist.Items[:0]
+	for _, item := range watchlist.Items {
+		if item.StockID != stockID {
+			items = append(items, item)
+		}
+	}
+	watchlist.Items = items
+	return nil
+}
+
+// mockWatchlistQuotes quotes the symbols it has prices for.
+type mockWatchlistQuotes map[string]float64
+
+func (m mockWatchlistQuotes) GetQuote(ctx context.Context, symbol string) (*service.MarketQuote, error) {
+	price, ok := m[symbol]
+	if !ok {
+		return nil, errors.New("no quote")
+	}
+	return &service.MarketQuote{Price: price, Change: 1, ChangePercent: 0.5}, nil
+}
+
+// setupWatchlistRouter serves watchlists for the user in the X-User-ID header.
+func setupWatchlistRouter(repo *mockWatchlistRepository, quotes service.QuoteSource) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authMiddleware := func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-User-ID"))
+		c.Next()
+	}
+	NewWatchlistHandler(service.NewWatchlistService(repo, quotes)).RegisterWatchlistRoutes(router.Group("/api/v1"), authMiddleware)
+	return router
+}
+
+func watchlistRequest(router *gin.Engine, method, path string, userID uuid.UUID, body interface{}) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		req, _ = http.NewRequest(method, path, bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req, _ = http.NewRequest(method, path, nil)
+	}
+	req.Header.Set("X-User-ID", userID.String())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// createWatchlist creates a watchlist for userID and returns its ID.
+func createWatchlist(t *testing.T, router *gin.Engine, userID uuid.UUID) string {
+	t.Helper()
+	w := watchlistRequest(router, http.MethodPost, "/api/v1/watchlists", userID, map[string]string{"name": "Tech"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var watchlist model.Watchlist
+	if err := json.Unmarshal(w.Body.Bytes(), &watchlist); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return watchlist.ID.String()
+}
+
+func TestWatchlistHandler_GetWatchlist_Quotes(t *testing.T) {
+	aapl := model.Stock{ID: uuid.New(), Symbol: "AAPL"}
+	msft := model.Stock{ID: uuid.New(), Symbol: "MSFT"}
+	repo := newMockWatchlistRepository(aapl, msft)
+	router := setupWatchlistRouter(repo, mockWatchlistQuotes{"AAPL": 190})
+	userID := uuid.New()
+	id := createWatchlist(t, router, userID)
+
+	for _, stock := range []model.Stock{aapl, msft} {
+		w := watchlistRequest(router, http.MethodPost, "/api/v1/watchlists/"+id+"/items", userID, map[string]string{"stock_id": stock.ID.String()})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201 adding %s, got %d. Body: %s", stock.Symbol, w.Code, w.Body.String())
+		}
+	}
+
+	w := watchlistRequest(router, http.MethodGet, "/api/v1/watchlists/"+id, userID, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var detail service.WatchlistDetail
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(detail.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(detail.Items))
+	}
+	if detail.Items[0].Quote == nil || detail.Items[0].Quote.Price != 190 {
+		t.Errorf("Expected AAPL quoted at 190, got %+v", detail.Items[0].Quote)
+	}
+	if detail.Items[1].Quote != nil {
+		t.Errorf("Expected no quote for MSFT, got %+v", detail.Items[1].Quote)
+	}
+}
+
+func TestWatchlistHandler_AddItem_Duplicate(t *testing.T) {
+	stock := model.Stock{ID: uuid.New(), Symbol: "AAPL"}
+	repo := newMockWatchlistRepository(stock)
+	router := setupWatchlistRouter(repo, nil)
+	userID := uuid.New()
+	id := createWatchlist(t, router, userID)
+	body := map[string]string{"stock_id": stock.ID.String()}
+
+	if w := watchlistRequest(router, http.MethodPost, "/api/v1/watchlists/"+id+"/items", userID, body); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	w := watchlistRequest(router, http.MethodPost, "/api/v1/watchlists/"+id+"/items", userID, body)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status 409 adding the stock twice, got %d", w.Code)
+	}
+	var errResp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil || errResp.Code != CodeConflict {
+		t.Errorf("Expected code %q, got %s", CodeConflict, w.Body.String())
+	}
+	if items := repo.watchlists[uuid.MustParse(id)].Items; len(items) != 1 {
+		t.Errorf("Expected 1 item after the duplicate, got %d", len(items))
+	}
+}
+
+func TestWatchlistHandler_Ownership(t *testing.T) {
+	stock := model.Stock{ID: uuid.New(), Symbol: "AAPL"}
+	repo := newMockWatchlistRepository(stock)
+	router := setupWatchlistRouter(repo, nil)
+	owner, other := uuid.New(), uuid.New()
+	id := createWatchlist(t, router, owner)
+	if w := watchlistRequest(router, http.MethodPost, "/api/v1/watchlists/"+id+"/items", owner, map[string]string{"stock_id": stock.ID.String()}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	for _, tt := range []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+	}{
+		{"get", http.MethodGet, "/api/v1/watchlists/" + id, nil},
+		{"delete", http.MethodDelete, "/api/v1/watchlists/" + id, nil},
+		{"add item", http.MethodPost, "/api/v1/watchlists/" + id + "/items", map[string]string{"stock_id": stock.ID.String()}},
+		{"remove item", http.MethodDelete, "/api/v1/watchlists/" + id + "/items/" + stock.ID.String(), nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := watchlistRequest(router, tt.method, tt.path, other, tt.body)
+			if w.Code != http.StatusForbidden {
+				t.Errorf("Expected status 403, got %d. Body: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+
+	watchlist := repo.watchlists[uuid.MustParse(id)]
+	if watchlist == nil || len(watchlist.Items) != 1 {
+		t.Fatalf("Expected the owner's watchlist to be unchanged, got %+v", watchlist)
+	}
+
+	w := watchlistRequest(router, http.MethodGet, "/api/v1/watchlists", other, nil)
+	if w.Code != http.StatusOK || w.Body.String() != "[]" {
+		t.Errorf("Expected an empty list for another user, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := watchlistRequest(router, http.MethodDelete, "/api/v1/watchlists/"+id+"/items/"+stock.ID.String(), owner, nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 removing the owner's stock, got %d", w.Code)
+	}
+	if w := watchlistRequest(router, http.MethodDelete, "/api/v1/watchlists/"+id, owner, nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 deleting the owner's watchlist, got %d", w.Code)
+	}
+	if w := watchlistRequest(router, http.MethodGet, "/api/v1/watchlists/"+id, owner, nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after deleting, got %d", w.Code)
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PriceTick is one price a market data provider pushes.
+type PriceTick struct {
+	Symbol string  `json:"symbol" binding:"required"`
+	Price  float64 `json:"price" binding:"required,gt=0"`
+	Volume int64   `json:"volume"`
+	// Timestamp is when the price was quoted; the time of receipt when unset.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PriceWebhookRequest is the body of a provider's price push.
+type PriceWebhookRequest struct {
+	Prices []PriceTick `json:"prices" binding:"required,min=1,dive"`
+}
+
+// PriceWebhookResponse reports how many pushed prices were accepted.
+type PriceWebhookResponse struct {
+	Accepted int `json:"accepted"`
+}
+
+// PriceTickReceiver takes the prices providers push.
+type PriceTickReceiver interface {
+	ReceivePriceTicks(ticks []PriceTick)
+}
+
+// WebhookHandler handles inbound callbacks from data providers. Requests
+// are authenticated by the signature middleware the routes are mounted
+// behind, not by user tokens.
+type WebhookHandler struct {
+	prices PriceTickReceiver
+}
+
+// NewWebhookHandler creates a new WebhookHandler instance.
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{}
+}
+
+// SetPriceReceiver enables the price push webhook. Without a receiver it
+// answers 503.
+func (h *WebhookHandler) SetPriceReceiver(receiver PriceTickReceiver) {
+	h.prices = receiver
+}
+
+// ReceivePrices handles POST /api/v1/webhooks/prices.
+// @Summary Receive pushed prices
+// @Description Accept prices pushed by a market data provider and pass them to live price subscribers and the alert checker. The request must be signed: X-Webhook-Signature is the hex HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" under WEBHOOK_SECRET, and the Unix timestamp must be within 5 minutes of the server's clock.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Signature header string true "HMAC-SHA256 signature, optionally prefixed with sha256="
+// @Param X-Webhook-Timestamp header string true "Unix seconds"
+// @Param request body PriceWebhookRequest true "Pushed prices"
+// @Success 202 {object} PriceWebhookResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/webhooks/prices [post]
+func (h *WebhookHandler) ReceivePrices(c *gin.Context) {
+	if h.prices == nil {
+		respondErrorMessage(c, http.StatusServiceUnavailable, "price webhooks are not enabled")
+		return
+	}
+
+	var req PriceWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+	for i := range req.Prices {
+		if req.Prices[i].Timestamp.IsZero() {
+			req.Prices[i].Timestamp = now
+		}
+	}
+	h.prices.ReceivePriceTicks(req.Prices)
+
+	c.JSON(http.StatusAccepted, PriceWebhookResponse{Accepted: len(req.Prices)})
+}
+
+// RegisterWebhookRoutes registers the inbound provider webhooks behind
+// signatureMiddleware.
+func (h *WebhookHandler) RegisterWebhookRoutes(rg *gin.RouterGroup, signatureMiddleware gin.HandlerFunc) {
+	webhooks := rg.Group("/webhooks")
+	webhooks.Use(signatureMiddleware)
+	{
+		webhooks.POST("/prices", h.ReceivePrices)
+	}
+}
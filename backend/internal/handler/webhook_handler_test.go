@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// recordingTickReceiver keeps the prices it receives.
+type recordingTickReceiver struct {
+	ticks []PriceTick
+}
+
+func (r *recordingTickReceiver) ReceivePriceTicks(ticks []PriceTick) {
+	r.ticks = append(r.ticks, ticks...)
+}
+
+func TestWebhookHandler_ReceivePrices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const secret = "webhook-secret"
+
+	tests := []struct {
+		name       string
+		body       string
+		secret     string
+		receiver   bool
+		wantStatus int
+		wantTicks  int
+	}{
+		{"accepted", `{"prices":[{"symbol":"AAPL","price":187.5,"volume":100},{"symbol":"MSFT","price":410}]}`, secret, true, http.StatusAccepted, 2},
+		{"bad signature", `{"prices":[{"symbol":"AAPL","price":187.5}]}`, "other-secret", true, http.StatusUnauthorized, 0},
+		{"no prices", `{"prices":[]}`, secret, true, http.StatusBadRequest, 0},
+		{"non-positive price", `{"prices":[{"symbol":"AAPL","price":0}]}`, secret, true, http.StatusBadRequest, 0},
+		{"no receiver", `{"prices":[{"symbol":"AAPL","price":187.5}]}`, secret, false, http.StatusServiceUnavailable, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewWebhookHandler()
+			receiver := &recordingTickReceiver{}
+			if tt.receiver {
+				handler.SetPriceReceiver(receiver)
+			}
+			router := gin.New()
+			handler.RegisterWebhookRoutes(router.Group("/api/v1"), middleware.WebhookSignatureMiddleware(middleware.WebhookSignatureConfig{Secret: secret}))
+
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/webhooks/prices", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(middleware.WebhookTimestampHeader, timestamp)
+			req.Header.Set(middleware.WebhookSignatureHeader, "sha256="+middleware.ComputeHMACSignature(tt.secret, timestamp, []byte(tt.body)))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if len(receiver.ticks) != tt.wantTicks {
+				t.Fatalf("Expected %d received prices, got %d", tt.wantTicks, len(receiver.ticks))
+			}
+			for _, tick := range receiver.ticks {
+				if tick.Timestamp.IsZero() {
+					t.Errorf("Expected %s to be stamped with the time of receipt", tick.Symbol)
+				}
+			}
+		})
+	}
+}
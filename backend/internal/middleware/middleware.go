@@ -4,8 +4,8 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/gin-gonic/gin"
 	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/gin-gonic/gin"
 )
 
 // AuthMiddleware validates JWT tokens.
@@ -13,7 +13,7 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required", "code": "unauthorized"})
 			c.Abort()
 			return
 		}
@@ -21,7 +21,7 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		// Extract token from "Bearer <token>" format
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format", "code": "unauthorized"})
 			c.Abort()
 			return
 		}
@@ -29,7 +29,7 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		tokenString := parts[1]
 		claims, err := authService.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token", "code": "invalid_token"})
 			c.Abort()
 			return
 		}
@@ -38,6 +38,7 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		c.Set("user_id", (*claims)["user_id"])
 		c.Set("email", (*claims)["email"])
 		c.Set("role", (*claims)["role"])
+		c.Set("auth_time", (*claims)["auth_time"])
 
 		c.Next()
 	}
@@ -75,34 +76,27 @@ func OptionalAuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 	}
 }
 
-// RoleMiddleware requires a specific role to access the endpoint.
-func RoleMiddleware(requiredRoles ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		roleVal, exists := c.Get("role")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-			c.Abort()
-			return
-		}
+// DefaultRole is the role assumed for tokens without a role claim.
+const DefaultRole = "user"
 
-		role, ok := roleVal.(string)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid role"})
-			c.Abort()
-			return
-		}
+// RequireRole allows the request only when the role claim set by
+// AuthMiddleware is one of roles, and responds 403 otherwise. A missing or
+// empty role claim is treated as DefaultRole.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		allowed[r] = true
+	}
 
-		// Check if user has any of the required roles
-		hasRole := false
-		for _, r := range requiredRoles {
-			if role == r {
-				hasRole = true
-				break
-			}
+	return func(c *gin.Context) {
+		roleVal, _ := c.Get("role")
+		role, _ := roleVal.(string)
+		if role == "" {
+			role = DefaultRole
 		}
 
-		if !hasRole {
-			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		if !allowed[role] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions", "code": "forbidden"})
 			c.Abort()
 			return
 		}
@@ -111,9 +105,16 @@ func RoleMiddleware(requiredRoles ...string) gin.HandlerFunc {
 	}
 }
 
+// RoleMiddleware requires one of requiredRoles to access the endpoint.
+//
+// Deprecated: Use RequireRole.
+func RoleMiddleware(requiredRoles ...string) gin.HandlerFunc {
+	return RequireRole(requiredRoles...)
+}
+
 // AdminMiddleware requires admin role to access the endpoint.
 func AdminMiddleware() gin.HandlerFunc {
-	return RoleMiddleware("admin")
+	return RequireRole("admin")
 }
 
 // LoggingMiddleware logs requests.
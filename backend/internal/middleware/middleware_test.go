@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -142,10 +143,10 @@ func TestOptionalAuthMiddleware(t *testing.T) {
 	validToken := mockService.generateToken(userID, "test@example.com", "user")
 
 	tests := []struct {
-		name          string
-		authHeader    string
-		wantStatus    int
-		expectUserID  bool
+		name         string
+		authHeader   string
+		wantStatus   int
+		expectUserID bool
 	}{
 		{
 			name:         "valid token",
@@ -191,6 +192,58 @@ func TestOptionalAuthMiddleware(t *testing.T) {
 	}
 }
 
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		roles      []string
+		setRole    bool
+		role       interface{}
+		wantStatus int
+	}{
+		{name: "admin allowed", roles: []string{"admin"}, setRole: true, role: "admin", wantStatus: http.StatusOK},
+		{name: "one of several roles allowed", roles: []string{"admin", "moderator"}, setRole: true, role: "moderator", wantStatus: http.StatusOK},
+		{name: "user allowed on user route", roles: []string{"user", "admin"}, setRole: true, role: "user", wantStatus: http.StatusOK},
+		{name: "user denied", roles: []string{"admin"}, setRole: true, role: "user", wantStatus: http.StatusForbidden},
+		{name: "unknown role denied", roles: []string{"admin"}, setRole: true, role: "superuser", wantStatus: http.StatusForbidden},
+		{name: "role match is case sensitive", roles: []string{"admin"}, setRole: true, role: "Admin", wantStatus: http.StatusForbidden},
+		{name: "no roles allowed denies everyone", roles: nil, setRole: true, role: "admin", wantStatus: http.StatusForbidden},
+		{name: "missing claim treated as user and allowed", roles: []string{"user"}, setRole: false, wantStatus: http.StatusOK},
+		{name: "missing claim treated as user and denied", roles: []string{"admin"}, setRole: false, wantStatus: http.StatusForbidden},
+		{name: "nil claim treated as user", roles: []string{"user"}, setRole: true, role: nil, wantStatus: http.StatusOK},
+		{name: "empty claim treated as user", roles: []string{"admin"}, setRole: true, role: "", wantStatus: http.StatusForbidden},
+		{name: "non-string claim treated as user", roles: []string{"admin"}, setRole: true, role: 42, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.setRole {
+					c.Set("role", tt.role)
+				}
+				c.Next()
+			})
+			router.Use(RequireRole(tt.roles...))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusForbidden && !strings.Contains(w.Body.String(), `"code":"forbidden"`) {
+				t.Errorf("Expected forbidden code, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
 func TestRoleMiddleware(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
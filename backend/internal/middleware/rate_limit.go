@@ -153,6 +153,7 @@ func RateLimitMiddleware(limiter RateLimiter, keyPrefix string) gin.HandlerFunc
 			c.Header("Retry-After", strconv.FormatInt(int64(ttl.Seconds()), 10))
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
 				"error":       "too many requests",
+				"code":        "rate_limited",
 				"retry_after": int64(ttl.Seconds()),
 			})
 			return
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestWebhookSignatureMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	secret := "webhook-secret"
+	now := time.Unix(1700000000, 0)
+	body := `{"event":"odds.updated"}`
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	staleTimestamp := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name       string
+		signature  string
+		timestamp  string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "valid signature",
+			signature:  "sha256=" + ComputeHMACSignature(secret, timestamp, []byte(body)),
+			timestamp:  timestamp,
+			body:       body,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "valid signature without prefix",
+			signature:  ComputeHMACSignature(secret, timestamp, []byte(body)),
+			timestamp:  timestamp,
+			body:       body,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "tampered body",
+			signature:  "sha256=" + ComputeHMACSignature(secret, timestamp, []byte(body)),
+			timestamp:  timestamp,
+			body:       `{"event":"odds.deleted"}`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong secret",
+			signature:  "sha256=" + ComputeHMACSignature("other", timestamp, []byte(body)),
+			timestamp:  timestamp,
+			body:       body,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "replayed timestamp",
+			signature:  "sha256=" + ComputeHMACSignature(secret, staleTimestamp, []byte(body)),
+			timestamp:  staleTimestamp,
+			body:       body,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing signature",
+			timestamp:  timestamp,
+			body:       body,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "non-numeric timestamp",
+			signature:  "sha256=" + ComputeHMACSignature(secret, "yesterday", []byte(body)),
+			timestamp:  "yesterday",
+			body:       body,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(WebhookSignatureMiddleware(WebhookSignatureConfig{
+				Secret: secret,
+				Now:    func() time.Time { return now },
+			}))
+			router.POST("/webhook", func(c *gin.Context) {
+				received, _ := io.ReadAll(c.Request.Body)
+				c.String(http.StatusOK, string(received))
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/webhook", strings.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set(WebhookSignatureHeader, tt.signature)
+			}
+			if tt.timestamp != "" {
+				req.Header.Set(WebhookTimestampHeader, tt.timestamp)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != tt.body {
+				t.Errorf("Expected handler to receive body %q, got %q", tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWebhookSignatureMiddleware_Rejections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Unix(1700000000, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	large := `{"padding":"` + strings.Repeat("x", maxWebhookBodySize) + `"}`
+
+	tests := []struct {
+		name       string
+		secret     string
+		body       string
+		wantStatus int
+	}{
+		// Without a secret, a signature made with the empty key must not pass
+		{name: "no secret", secret: "", body: `{}`, wantStatus: http.StatusServiceUnavailable},
+		{name: "body too large", secret: "webhook-secret", body: large, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			router := gin.New()
+			router.Use(WebhookSignatureMiddleware(WebhookSignatureConfig{
+				Secret: tt.secret,
+				Now:    func() time.Time { return now },
+			}))
+			router.POST("/webhook", func(c *gin.Context) {
+				reached = true
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/webhook", strings.NewReader(tt.body))
+			req.Header.Set(WebhookSignatureHeader, ComputeHMACSignature(tt.secret, timestamp, []byte(tt.body)))
+			req.Header.Set(WebhookTimestampHeader, timestamp)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if reached {
+				t.Error("Expected the handler not to run")
+			}
+		})
+	}
+}
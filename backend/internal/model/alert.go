@@ -0,0 +1,105 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Alert validation errors.
+var (
+	ErrInvalidAlertType        = errors.New("invalid alert type")
+	ErrInvalidAlertCondition   = errors.New("invalid alert condition")
+	ErrUnsupportedAlertPairing = errors.New("condition is not supported for this alert type")
+	ErrInvalidAlertTarget      = errors.New("percent conditions require a positive target value")
+)
+
+// alertTypeConditions lists the conditions each alert type accepts. Event
+// alerts (match start, news, dividends and earnings) fire when the event
+// happens and take no condition.
+var alertTypeConditions = map[AlertType][]AlertCondition{
+	AlertTypeStockPrice: {
+		AlertConditionAbove, AlertConditionBelow, AlertConditionEquals,
+		AlertConditionPercentUp, AlertConditionPercentDown, AlertConditionCrosses,
+	},
+	AlertTypeStockVolume: {
+		AlertConditionAbove, AlertConditionBelow, AlertConditionPercentUp, AlertConditionPercentDown,
+	},
+	AlertTypeOddsChange: {
+		AlertConditionAbove, AlertConditionBelow, AlertConditionPercentUp,
+		AlertConditionPercentDown, AlertConditionCrosses,
+	},
+	AlertTypeTechnical: {
+		AlertConditionAbove, AlertConditionBelow, AlertConditionEquals, AlertConditionCrosses,
+	},
+	AlertTypeValueBet:   {AlertConditionAbove, AlertConditionBelow},
+	AlertTypeFairValue:  {AlertConditionAbove, AlertConditionBelow},
+	AlertTypeMatchStart: nil,
+	AlertTypeNews:       nil,
+	AlertTypeDividend:   nil,
+	AlertTypeEarnings:   nil,
+}
+
+// IsValid reports whether the alert type is one of the known types.
+func (t AlertType) IsValid() bool {
+	_, ok := alertTypeConditions[t]
+	return ok
+}
+
+// IsValid reports whether the condition is one of the known conditions.
+func (c AlertCondition) IsValid() bool {
+	switch c {
+	case AlertConditionAbove, AlertConditionBelow, AlertConditionEquals,
+		AlertConditionPercentUp, AlertConditionPercentDown, AlertConditionCrosses:
+		return true
+	}
+	return false
+}
+
+// Conditions returns the conditions the alert type accepts.
+func (t AlertType) Conditions() []AlertCondition {
+	return alertTypeConditions[t]
+}
+
+// Validate checks that the alert's type and condition are known, that the
+// condition makes sense for the type and that percent conditions have a
+// positive target.
+func (a *Alert) Validate() error {
+	if !a.Type.IsValid() {
+		return fmt.Errorf("%w %q", ErrInvalidAlertType, a.Type)
+	}
+
+	allowed := a.Type.Conditions()
+	if len(allowed) == 0 {
+		if a.Condition != "" {
+			return fmt.Errorf("%w: %s alerts take no condition", ErrUnsupportedAlertPairing, a.Type)
+		}
+		return nil
+	}
+
+	if !a.Condition.IsValid() {
+		return fmt.Errorf("%w %q", ErrInvalidAlertCondition, a.Condition)
+	}
+	for _, condition := range allowed {
+		if condition == a.Condition {
+			return a.validateTarget()
+		}
+	}
+
+	names := make([]string, len(allowed))
+	for i, condition := range allowed {
+		names[i] = string(condition)
+	}
+	return fmt.Errorf("%w: %s alerts take one of %s", ErrUnsupportedAlertPairing, a.Type, strings.Join(names, ", "))
+}
+
+// validateTarget checks the target value against the alert's condition.
+func (a *Alert) validateTarget() error {
+	switch a.Condition {
+	case AlertConditionPercentUp, AlertConditionPercentDown:
+		if a.TargetValue <= 0 {
+			return ErrInvalidAlertTarget
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAlert_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		alertType AlertType
+		condition AlertCondition
+		target    float64
+		wantErr   error
+	}{
+		{"stock price above", AlertTypeStockPrice, AlertConditionAbove, 0, nil},
+		{"fair value below", AlertTypeFairValue, AlertConditionBelow, 0, nil},
+		{"match start without condition", AlertTypeMatchStart, "", 0, nil},
+		{"unknown type", "price", AlertConditionAbove, 0, ErrInvalidAlertType},
+		{"empty type", "", AlertConditionAbove, 0, ErrInvalidAlertType},
+		{"unknown condition", AlertTypeStockPrice, "sideways", 0, ErrInvalidAlertCondition},
+		{"missing condition", AlertTypeStockPrice, "", 0, ErrInvalidAlertCondition},
+		{"condition case matters", AlertTypeStockPrice, "ABOVE", 0, ErrInvalidAlertCondition},
+		{"match start with percent up", AlertTypeMatchStart, AlertConditionPercentUp, 0, ErrUnsupportedAlertPairing},
+		{"fair value crosses", AlertTypeFairValue, AlertConditionCrosses, 0, ErrUnsupportedAlertPairing},
+		{"percent up with target", AlertTypeStockPrice, AlertConditionPercentUp, 5, nil},
+		{"percent up without target", AlertTypeStockPrice, AlertConditionPercentUp, 0, ErrInvalidAlertTarget},
+		{"percent down with negative target", AlertTypeOddsChange, AlertConditionPercentDown, -5, ErrInvalidAlertTarget},
+		{"below with zero target", AlertTypeStockPrice, AlertConditionBelow, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &Alert{Type: tt.alertType, Condition: tt.condition, TargetValue: tt.target}
+			if err := alert.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAlert_ValidatePairings(t *testing.T) {
+	conditions := []AlertCondition{
+		AlertConditionAbove, AlertConditionBelow, AlertConditionEquals,
+		AlertConditionPercentUp, AlertConditionPercentDown, AlertConditionCrosses,
+	}
+	allowed := map[AlertType]string{
+		AlertTypeStockPrice:  "above below equals percent_up percent_down crosses",
+		AlertTypeStockVolume: "above below percent_up percent_down",
+		AlertTypeOddsChange:  "above below percent_up percent_down crosses",
+		AlertTypeTechnical:   "above below equals crosses",
+		AlertTypeValueBet:    "above below",
+		AlertTypeFairValue:   "above below",
+		AlertTypeMatchStart:  "",
+		AlertTypeNews:        "",
+		AlertTypeDividend:    "",
+		AlertTypeEarnings:    "",
+	}
+
+	for alertType, accepted := range allowed {
+		for _, condition := range conditions {
+			name := string(alertType) + "/" + string(condition)
+			t.Run(name, func(t *testing.T) {
+				err := (&Alert{Type: alertType, Condition: condition, TargetValue: 1}).Validate()
+				if strings.Contains(" "+accepted+" ", " "+string(condition)+" ") {
+					if err != nil {
+						t.Errorf("Expected %s to be valid, got %v", name, err)
+					}
+				} else if !errors.Is(err, ErrUnsupportedAlertPairing) {
+					t.Errorf("Expected ErrUnsupportedAlertPairing for %s, got %v", name, err)
+				}
+			})
+		}
+	}
+}
@@ -3,8 +3,8 @@ package model
 import (
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/pkg/pq"
+	"github.com/google/uuid"
 )
 
 // Article represents a news article stored in the system.
@@ -19,6 +19,7 @@ type Article struct {
 	SentimentScore float64        `json:"sentiment_score"`
 	SentimentLabel string         `json:"sentiment_label"`
 	EventType      string         `json:"event_type"`
+	Summary        string         `json:"summary"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 }
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BacktestStrategyType selects the signal rule a backtest trades on.
+type BacktestStrategyType string
+
+const (
+	BacktestStrategySMACrossover BacktestStrategyType = "sma_crossover"
+	BacktestStrategyRSI          BacktestStrategyType = "rsi"
+	BacktestStrategyMACD         BacktestStrategyType = "macd"
+)
+
+// IsValid reports whether the strategy type is one of the known strategies.
+func (t BacktestStrategyType) IsValid() bool {
+	switch t {
+	case BacktestStrategySMACrossover, BacktestStrategyRSI, BacktestStrategyMACD:
+		return true
+	}
+	return false
+}
+
+// EquityPoint is the value of a backtest account at the close of one bar.
+type EquityPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// BacktestResult is a completed backtest run and its performance.
+type BacktestResult struct {
+	ID             uuid.UUID            `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Symbol         string               `json:"symbol" gorm:"not null;index"`
+	StrategyName   string               `json:"strategy_name"`
+	StrategyType   BacktestStrategyType `json:"strategy_type" gorm:"type:varchar(20);not null"`
+	Params         map[string]float64   `json:"params" gorm:"type:jsonb;serializer:json"`
+	StartDate      time.Time            `json:"start_date"`
+	EndDate        time.Time            `json:"end_date"`
+	InitialCapital float64              `json:"initial_capital"`
+	FinalValue     float64              `json:"final_value"`
+	// TotalReturn and TotalReturnPercent compare FinalValue with
+	// InitialCapital. MaxDrawdown is in percent.
+	TotalReturn        float64 `json:"total_return"`
+	TotalReturnPercent float64 `json:"total_return_percent"`
+	SharpeRatio        float64 `json:"sharpe_ratio"`
+	MaxDrawdown        float64 `json:"max_drawdown"`
+	WinRate            float64 `json:"win_rate"`
+	// Trades are round trips; a position still open at EndDate is closed
+	// at the last bar's close.
+	TotalTrades   int `json:"total_trades"`
+	WinningTrades int `json:"winning_trades"`
+	LosingTrades  int `json:"losing_trades"`
+	// EquityCurve has one point per bar, oldest first. Fills happen at the
+	// close, so the first point is always the initial capital.
+	EquityCurve []EquityPoint `json:"equity_curve" gorm:"type:jsonb;serializer:json"`
+	CompletedAt time.Time     `json:"completed_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// TableName returns the table name for the BacktestResult model.
+func (BacktestResult) TableName() string {
+	return "backtest_results"
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FavoriteEntityType represents the kind of entity a user can pin as a favorite.
+type FavoriteEntityType string
+
+const (
+	FavoriteEntityStock     FavoriteEntityType = "stock"
+	FavoriteEntityMatch     FavoriteEntityType = "match"
+	FavoriteEntityPortfolio FavoriteEntityType = "portfolio"
+)
+
+// IsValid reports whether the entity type is one of the known favorite types.
+func (t FavoriteEntityType) IsValid() bool {
+	switch t {
+	case FavoriteEntityStock, FavoriteEntityMatch, FavoriteEntityPortfolio:
+		return true
+	}
+	return false
+}
+
+// Favorite represents an entity pinned by a user for quick access.
+// EntityID is a stock symbol for stocks and a UUID string for matches and portfolios.
+type Favorite struct {
+	ID         uuid.UUID          `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID     uuid.UUID          `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_favorites_user_entity"`
+	User       User               `json:"-" gorm:"foreignKey:UserID"`
+	EntityType FavoriteEntityType `json:"entity_type" gorm:"type:varchar(20);not null;uniqueIndex:idx_favorites_user_entity"`
+	EntityID   string             `json:"entity_id" gorm:"not null;uniqueIndex:idx_favorites_user_entity"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// TableName returns the table name for the Favorite model.
+func (Favorite) TableName() string {
+	return "favorites"
+}
@@ -12,10 +12,17 @@ type User struct {
 	Email        string    `json:"email" gorm:"uniqueIndex;not null"`
 	PasswordHash string    `json:"-" gorm:"not null"`
 	Name         string    `json:"name"`
+	AvatarURL    string    `json:"avatar_url"`
 	Role         string    `json:"role" gorm:"default:'user'"`
 	TwoFAEnabled bool      `json:"two_fa_enabled" gorm:"default:false"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	// EmailVerified is set once the user confirms a verification token sent
+	// to Email. OAuth sign-ups are verified by their provider.
+	EmailVerified bool `json:"email_verified" gorm:"default:false"`
+	// ProfileSyncProvider is the linked OAuth provider whose name and avatar
+	// are copied to the profile on every login. Empty disables syncing.
+	ProfileSyncProvider OAuthProvider `json:"profile_sync_provider,omitempty" gorm:"type:varchar(20)"`
+	CreatedAt           time.Time     `json:"created_at"`
+	UpdatedAt           time.Time     `json:"updated_at"`
 }
 
 // Session represents a user session.
@@ -63,7 +70,7 @@ type TwoFactorAuth struct {
 	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;uniqueIndex;not null"`
 	User        User       `json:"-" gorm:"foreignKey:UserID"`
 	Secret      string     `json:"-" gorm:"not null"`
-	BackupCodes string     `json:"-"` // JSON array of backup codes
+	BackupCodes string     `json:"-"` // JSON array of SHA-256 hashes of backup codes
 	Verified    bool       `json:"verified" gorm:"default:false"`
 	EnabledAt   *time.Time `json:"enabled_at,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
@@ -87,6 +94,19 @@ const (
 	AuditActionSessionRevoke    AuditAction = "session_revoke"
 	AuditActionFailedLogin      AuditAction = "failed_login"
 	AuditActionFailed2FAAttempt AuditAction = "failed_2fa_attempt"
+	AuditActionAccountDelete    AuditAction = "account_delete"
+	AuditActionEmailVerify      AuditAction = "email_verify"
+
+	// Business actions
+	AuditActionOrderPlace      AuditAction = "order_place"
+	AuditActionOrderCancel     AuditAction = "order_cancel"
+	AuditActionPortfolioDelete AuditAction = "portfolio_delete"
+	AuditActionAlertCreate     AuditAction = "alert_create"
+	AuditActionWebhookAdd      AuditAction = "webhook_add"
+	AuditActionDataExport      AuditAction = "data_export"
+
+	// System actions
+	AuditActionBankrollReconcile AuditAction = "bankroll_reconcile"
 )
 
 // AuditLog represents an audit log entry for security events.
@@ -149,12 +169,13 @@ type Stock struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// StockPrice represents a stock price at a point in time.
+// StockPrice represents a stock price at a point in time. A stock has at
+// most one bar per timestamp.
 type StockPrice struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	StockID   uuid.UUID `json:"stock_id" gorm:"type:uuid;index"`
+	StockID   uuid.UUID `json:"stock_id" gorm:"type:uuid;uniqueIndex:idx_stock_prices_stock_timestamp"`
 	Stock     Stock     `json:"-" gorm:"foreignKey:StockID"`
-	Timestamp time.Time `json:"timestamp" gorm:"index"`
+	Timestamp time.Time `json:"timestamp" gorm:"index;uniqueIndex:idx_stock_prices_stock_timestamp"`
 	Open      float64   `json:"open"`
 	High      float64   `json:"high"`
 	Low       float64   `json:"low"`
@@ -164,14 +185,66 @@ type StockPrice struct {
 
 // Portfolio represents a paper trading portfolio.
 type Portfolio struct {
-	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;index"`
-	User        User       `json:"-" gorm:"foreignKey:UserID"`
-	Name        string     `json:"name"`
-	CashBalance float64    `json:"cash_balance" gorm:"default:100000"`
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;index"`
+	User        User      `json:"-" gorm:"foreignKey:UserID"`
+	Name        string    `json:"name"`
+	CashBalance float64   `json:"cash_balance" gorm:"default:100000"`
+	// InitialCash is the cash the portfolio was opened with; returns are
+	// measured against it.
+	InitialCash float64    `json:"initial_cash" gorm:"default:100000"`
 	Positions   []Position `json:"positions,omitempty" gorm:"foreignKey:PortfolioID"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	// Slippage applied to market order fills. SlippageBps is the cost for the
+	// fixed_bps model and the minimum cost for the volatility model, which
+	// charges SlippageVolFactor times the symbol's daily volatility.
+	SlippageModel     SlippageModel `json:"slippage_model" gorm:"type:varchar(20);default:'none'"`
+	SlippageBps       float64       `json:"slippage_bps"`
+	SlippageVolFactor float64       `json:"slippage_vol_factor"`
+	// Commission charged on every fill. FeeAmount is the charge per trade
+	// for the flat model, per share for per_share and in basis points of
+	// the fill value for bps.
+	FeeModel  FeeModel  `json:"fee_model" gorm:"type:varchar(20);default:'none'"`
+	FeeAmount float64   `json:"fee_amount"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SlippageModel selects how a market order's fill price is moved away from
+// the reference price, always against the taker.
+type SlippageModel string
+
+const (
+	SlippageModelNone       SlippageModel = "none"
+	SlippageModelFixedBps   SlippageModel = "fixed_bps"
+	SlippageModelVolatility SlippageModel = "volatility"
+)
+
+// IsValid reports whether the slippage model is one of the known models.
+func (m SlippageModel) IsValid() bool {
+	switch m {
+	case SlippageModelNone, SlippageModelFixedBps, SlippageModelVolatility:
+		return true
+	}
+	return false
+}
+
+// FeeModel selects how the commission on a paper trade is charged.
+type FeeModel string
+
+const (
+	FeeModelNone     FeeModel = "none"
+	FeeModelFlat     FeeModel = "flat"
+	FeeModelPerShare FeeModel = "per_share"
+	FeeModelBps      FeeModel = "bps"
+)
+
+// IsValid reports whether the fee model is one of the known models.
+func (m FeeModel) IsValid() bool {
+	switch m {
+	case FeeModelNone, FeeModelFlat, FeeModelPerShare, FeeModelBps:
+		return true
+	}
+	return false
 }
 
 // Position represents a stock position in a portfolio.
@@ -182,8 +255,13 @@ type Position struct {
 	Quantity     int64     `json:"quantity"`
 	AvgCost      float64   `json:"avg_cost"`
 	CurrentPrice float64   `json:"current_price"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	// DayChange and DayChangePercent are the price change since the previous
+	// close as of PriceUpdatedAt.
+	DayChange        float64    `json:"day_change"`
+	DayChangePercent float64    `json:"day_change_percent"`
+	PriceUpdatedAt   *time.Time `json:"price_updated_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 // OrderSide represents the side of an order (buy/sell).
@@ -212,6 +290,34 @@ const (
 	OrderStatusRejected  OrderStatus = "rejected"
 )
 
+// IsValid reports whether the order status is one of the known statuses.
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusFilled, OrderStatusCancelled, OrderStatusRejected:
+		return true
+	}
+	return false
+}
+
+// TimeInForce represents how long an order stays working before it is cancelled.
+type TimeInForce string
+
+const (
+	TimeInForceDay TimeInForce = "DAY" // Cancelled at market close
+	TimeInForceGTC TimeInForce = "GTC" // Good till cancelled
+	TimeInForceIOC TimeInForce = "IOC" // Immediate or cancel
+	TimeInForceFOK TimeInForce = "FOK" // Fill or kill
+)
+
+// IsValid reports whether the time in force is one of the known values.
+func (t TimeInForce) IsValid() bool {
+	switch t {
+	case TimeInForceDay, TimeInForceGTC, TimeInForceIOC, TimeInForceFOK:
+		return true
+	}
+	return false
+}
+
 // Order represents a paper trading order.
 type Order struct {
 	ID          uuid.UUID   `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
@@ -223,6 +329,7 @@ type Order struct {
 	Quantity    int64       `json:"quantity" gorm:"not null"`
 	Price       float64     `json:"price"`
 	Status      OrderStatus `json:"status" gorm:"default:'pending'"`
+	TimeInForce TimeInForce `json:"time_in_force" gorm:"type:varchar(3);default:'DAY'"`
 	FilledAt    *time.Time  `json:"filled_at,omitempty"`
 	CreatedAt   time.Time   `json:"created_at"`
 	UpdatedAt   time.Time   `json:"updated_at"`
@@ -240,22 +347,33 @@ type Trade struct {
 	Quantity    int64     `json:"quantity" gorm:"not null"`
 	Price       float64   `json:"price" gorm:"not null"`
 	Total       float64   `json:"total" gorm:"not null"`
-	ExecutedAt  time.Time `json:"executed_at"`
+	// ReferencePrice is the market price before slippage. Slippage is the
+	// fill's total cost against that price, positive when the fill was worse.
+	ReferencePrice float64 `json:"reference_price"`
+	Slippage       float64 `json:"slippage"`
+	// Fees is the commission charged on the fill under the portfolio's fee
+	// model, on top of Total.
+	Fees float64 `json:"fees"`
+	// RealizedPL is the profit or loss a sell locked in against the
+	// position's average cost, before fees. It is zero for buys.
+	RealizedPL float64   `json:"realized_pl"`
+	ExecutedAt time.Time `json:"executed_at"`
 }
 
 // AlertType represents the type of alert.
 type AlertType string
 
 const (
-	AlertTypeStockPrice   AlertType = "stock_price"
-	AlertTypeStockVolume  AlertType = "stock_volume"
-	AlertTypeOddsChange   AlertType = "odds_change"
-	AlertTypeMatchStart   AlertType = "match_start"
-	AlertTypeValueBet     AlertType = "value_bet"
-	AlertTypeTechnical    AlertType = "technical"
-	AlertTypeNews         AlertType = "news"
-	AlertTypeDividend     AlertType = "dividend"
-	AlertTypeEarnings     AlertType = "earnings"
+	AlertTypeStockPrice  AlertType = "stock_price"
+	AlertTypeStockVolume AlertType = "stock_volume"
+	AlertTypeOddsChange  AlertType = "odds_change"
+	AlertTypeMatchStart  AlertType = "match_start"
+	AlertTypeValueBet    AlertType = "value_bet"
+	AlertTypeTechnical   AlertType = "technical"
+	AlertTypeNews        AlertType = "news"
+	AlertTypeDividend    AlertType = "dividend"
+	AlertTypeEarnings    AlertType = "earnings"
+	AlertTypeFairValue   AlertType = "fair_value" // TargetValue is a margin-of-safety threshold in percent; zero follows the user's buy/sell margin
 )
 
 // AlertCondition represents the condition for triggering an alert.
@@ -272,24 +390,24 @@ const (
 
 // Alert represents a user-configured alert.
 type Alert struct {
-	ID             uuid.UUID       `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	UserID         uuid.UUID       `json:"user_id" gorm:"type:uuid;index;not null"`
-	User           User            `json:"-" gorm:"foreignKey:UserID"`
-	Type           AlertType       `json:"type" gorm:"type:varchar(50);not null"`
-	Symbol         string          `json:"symbol" gorm:"index"` // Stock symbol or match identifier
-	Condition      AlertCondition  `json:"condition" gorm:"type:varchar(50);not null"`
-	TargetValue    float64         `json:"target_value"`
-	CurrentValue   float64         `json:"current_value"`
-	Message        string          `json:"message"`
-	Active         bool            `json:"active" gorm:"default:true"`
-	LastTriggered  *time.Time      `json:"last_triggered,omitempty"`
-	TriggerCount   int             `json:"trigger_count" gorm:"default:0"`
-	NotifyEmail    bool            `json:"notify_email" gorm:"default:false"`
-	NotifyTelegram bool            `json:"notify_telegram" gorm:"default:false"`
-	NotifyLINE     bool            `json:"notify_line" gorm:"default:false"`
-	NotifyDiscord  bool            `json:"notify_discord" gorm:"default:false"`
-	CreatedAt      time.Time       `json:"created_at"`
-	UpdatedAt      time.Time       `json:"updated_at"`
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID         uuid.UUID      `json:"user_id" gorm:"type:uuid;index;not null"`
+	User           User           `json:"-" gorm:"foreignKey:UserID"`
+	Type           AlertType      `json:"type" gorm:"type:varchar(50);not null"`
+	Symbol         string         `json:"symbol" gorm:"index"` // Stock symbol or match identifier
+	Condition      AlertCondition `json:"condition" gorm:"type:varchar(50);not null"`
+	TargetValue    float64        `json:"target_value"`
+	CurrentValue   float64        `json:"current_value"`
+	Message        string         `json:"message"`
+	Active         bool           `json:"active" gorm:"default:true"`
+	LastTriggered  *time.Time     `json:"last_triggered,omitempty"`
+	TriggerCount   int            `json:"trigger_count" gorm:"default:0"`
+	NotifyEmail    bool           `json:"notify_email" gorm:"default:false"`
+	NotifyTelegram bool           `json:"notify_telegram" gorm:"default:false"`
+	NotifyLINE     bool           `json:"notify_line" gorm:"default:false"`
+	NotifyDiscord  bool           `json:"notify_discord" gorm:"default:false"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
 }
 
 // NotificationType represents the type of notification.
@@ -325,6 +443,41 @@ type Notification struct {
 	Status    NotificationStatus `json:"status" gorm:"type:varchar(20);default:'unread'"`
 	ReadAt    *time.Time         `json:"read_at,omitempty"`
 	CreatedAt time.Time          `json:"created_at" gorm:"index"`
+	// Deliveries records the outcome of each channel the notification was sent through.
+	Deliveries []NotificationDelivery `json:"deliveries,omitempty" gorm:"foreignKey:NotificationID;constraint:OnDelete:CASCADE"`
+}
+
+// NotificationChannel represents a channel a notification is delivered through.
+type NotificationChannel string
+
+const (
+	NotificationChannelInApp    NotificationChannel = "in_app"
+	NotificationChannelEmail    NotificationChannel = "email"
+	NotificationChannelTelegram NotificationChannel = "telegram"
+	NotificationChannelLINE     NotificationChannel = "line"
+	NotificationChannelDiscord  NotificationChannel = "discord"
+)
+
+// DeliveryStatus represents the outcome of delivering a notification on one channel.
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// NotificationDelivery records a notification's delivery on one channel.
+type NotificationDelivery struct {
+	ID             uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	NotificationID uuid.UUID           `json:"notification_id" gorm:"type:uuid;index;not null"`
+	Channel        NotificationChannel `json:"channel" gorm:"type:varchar(20);not null"`
+	Status         DeliveryStatus      `json:"status" gorm:"type:varchar(20);default:'pending'"`
+	Error          string              `json:"error,omitempty"`
+	Attempts       int                 `json:"attempts" gorm:"default:0"`
+	DeliveredAt    *time.Time          `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
 }
 
 // Watchlist represents a user's stock watchlist.
@@ -384,23 +537,28 @@ type BankrollHistory struct {
 	CreatedAt time.Time `json:"created_at" gorm:"index"`
 }
 
+// TableName returns the table name for the BankrollHistory model.
+func (BankrollHistory) TableName() string {
+	return "bankroll_history"
+}
+
 // ValueBet represents a detected value betting opportunity.
 type ValueBet struct {
-	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	MatchID           uuid.UUID  `json:"match_id" gorm:"type:uuid;index;not null"`
-	Match             Match      `json:"match" gorm:"foreignKey:MatchID"`
-	Market            string     `json:"market" gorm:"not null"`
-	Selection         string     `json:"selection" gorm:"not null"`
-	Bookmaker         string     `json:"bookmaker" gorm:"not null"`
-	BookmakerOdds     float64    `json:"bookmaker_odds" gorm:"not null"`
-	TrueProbability   float64    `json:"true_probability" gorm:"not null"`
-	ImpliedProbability float64   `json:"implied_probability" gorm:"not null"`
-	ValuePercent      float64    `json:"value_percent" gorm:"not null"`
-	KellyStake        float64    `json:"kelly_stake"`
-	Confidence        float64    `json:"confidence"`
-	ExpiresAt         time.Time  `json:"expires_at"`
-	NotifiedUsers     []uuid.UUID `json:"-" gorm:"-"` // Runtime field
-	CreatedAt         time.Time  `json:"created_at" gorm:"index"`
+	ID                 uuid.UUID   `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	MatchID            uuid.UUID   `json:"match_id" gorm:"type:uuid;index;not null"`
+	Match              Match       `json:"match" gorm:"foreignKey:MatchID"`
+	Market             string      `json:"market" gorm:"not null"`
+	Selection          string      `json:"selection" gorm:"not null"`
+	Bookmaker          string      `json:"bookmaker" gorm:"not null"`
+	BookmakerOdds      float64     `json:"bookmaker_odds" gorm:"not null"`
+	TrueProbability    float64     `json:"true_probability" gorm:"not null"`
+	ImpliedProbability float64     `json:"implied_probability" gorm:"not null"`
+	ValuePercent       float64     `json:"value_percent" gorm:"not null"`
+	KellyStake         float64     `json:"kelly_stake"`
+	Confidence         float64     `json:"confidence"`
+	ExpiresAt          time.Time   `json:"expires_at"`
+	NotifiedUsers      []uuid.UUID `json:"-" gorm:"-"` // Runtime field
+	CreatedAt          time.Time   `json:"created_at" gorm:"index"`
 }
 
 // StockNews represents a news article about a stock.
@@ -419,20 +577,21 @@ type StockNews struct {
 
 // FairValue represents a calculated fair value for a stock.
 type FairValue struct {
-	ID               uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	StockID          uuid.UUID `json:"stock_id" gorm:"type:uuid;index;not null"`
-	Stock            Stock     `json:"stock" gorm:"foreignKey:StockID"`
-	DCFValue         float64   `json:"dcf_value"`
-	PEValue          float64   `json:"pe_value"`
-	PBVValue         float64   `json:"pbv_value"`
-	GrahamValue      float64   `json:"graham_value"`
-	BuffettValue     float64   `json:"buffett_value"`
-	WeightedAvg      float64   `json:"weighted_avg" gorm:"not null"`
-	CurrentPrice     float64   `json:"current_price" gorm:"not null"`
-	MarginOfSafety   float64   `json:"margin_of_safety"`
-	UpsidePercent    float64   `json:"upside_percent"`
-	Recommendation   string    `json:"recommendation"`
-	CalculatedAt     time.Time `json:"calculated_at" gorm:"index"`
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	StockID        uuid.UUID `json:"stock_id" gorm:"type:uuid;index;not null"`
+	Stock          Stock     `json:"stock" gorm:"foreignKey:StockID"`
+	Symbol         string    `json:"symbol" gorm:"index"`
+	DCFValue       float64   `json:"dcf_value"`
+	PEValue        float64   `json:"pe_value"`
+	PBVValue       float64   `json:"pbv_value"`
+	GrahamValue    float64   `json:"graham_value"`
+	BuffettValue   float64   `json:"buffett_value"`
+	WeightedAvg    float64   `json:"weighted_avg" gorm:"not null"`
+	CurrentPrice   float64   `json:"current_price" gorm:"not null"`
+	MarginOfSafety float64   `json:"margin_of_safety"`
+	UpsidePercent  float64   `json:"upside_percent"`
+	Recommendation string    `json:"recommendation"`
+	CalculatedAt   time.Time `json:"calculated_at" gorm:"index"`
 }
 
 // TradeJournal represents a trading journal entry.
@@ -456,44 +615,46 @@ type TradeJournal struct {
 
 // Goal represents a user's financial goal.
 type Goal struct {
-	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
-	User           User       `json:"-" gorm:"foreignKey:UserID"`
-	Title          string     `json:"title" gorm:"not null"`
-	Description    string     `json:"description"`
-	TargetAmount   float64    `json:"target_amount" gorm:"not null"`
-	CurrentAmount  float64    `json:"current_amount" gorm:"default:0"`
-	TargetDate     *time.Time `json:"target_date,omitempty"`
-	Category       string     `json:"category"` // betting, trading, portfolio
-	Status         string     `json:"status" gorm:"default:'active'"`
-	AchievedAt     *time.Time `json:"achieved_at,omitempty"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	User          User       `json:"-" gorm:"foreignKey:UserID"`
+	Title         string     `json:"title" gorm:"not null"`
+	Description   string     `json:"description"`
+	TargetAmount  float64    `json:"target_amount" gorm:"not null"`
+	CurrentAmount float64    `json:"current_amount" gorm:"default:0"`
+	TargetDate    *time.Time `json:"target_date,omitempty"`
+	Category      string     `json:"category"` // betting, trading, portfolio
+	Status        string     `json:"status" gorm:"default:'active'"`
+	AchievedAt    *time.Time `json:"achieved_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 // Settings represents user preferences and settings.
 type Settings struct {
-	ID                    uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	UserID                uuid.UUID `json:"user_id" gorm:"type:uuid;uniqueIndex;not null"`
-	User                  User      `json:"-" gorm:"foreignKey:UserID"`
-	InitialBankroll       float64   `json:"initial_bankroll" gorm:"default:1000"`
-	CurrentBankroll       float64   `json:"current_bankroll" gorm:"default:1000"`
-	KellyFactor           float64   `json:"kelly_factor" gorm:"default:0.5"`
-	RiskLevel             string    `json:"risk_level" gorm:"default:'moderate'"`
-	DefaultBookmaker      string    `json:"default_bookmaker"`
-	ValueBetThreshold     float64   `json:"value_bet_threshold" gorm:"default:5"`
-	MaxDailyBets          int       `json:"max_daily_bets" gorm:"default:10"`
-	MaxStakePerBet        float64   `json:"max_stake_per_bet"`
-	PreferredLeagues      string    `json:"preferred_leagues"` // JSON array
-	NotifyEmail           bool      `json:"notify_email" gorm:"default:true"`
-	NotifyTelegram        bool      `json:"notify_telegram" gorm:"default:false"`
-	NotifyLINE            bool      `json:"notify_line" gorm:"default:false"`
-	NotifyDiscord         bool      `json:"notify_discord" gorm:"default:false"`
-	TelegramChatID        string    `json:"telegram_chat_id"`
-	LINEToken             string    `json:"line_token"`
-	DiscordWebhook        string    `json:"discord_webhook"`
-	Theme                 string    `json:"theme" gorm:"default:'dark'"`
-	Language              string    `json:"language" gorm:"default:'en'"`
-	CreatedAt             time.Time `json:"created_at"`
-	UpdatedAt             time.Time `json:"updated_at"`
+	ID                 uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID             uuid.UUID `json:"user_id" gorm:"type:uuid;uniqueIndex;not null"`
+	User               User      `json:"-" gorm:"foreignKey:UserID"`
+	InitialBankroll    float64   `json:"initial_bankroll" gorm:"default:1000"`
+	CurrentBankroll    float64   `json:"current_bankroll" gorm:"default:1000"`
+	KellyFactor        float64   `json:"kelly_factor" gorm:"default:0.5"`
+	RiskLevel          string    `json:"risk_level" gorm:"default:'moderate'"`
+	DefaultBookmaker   string    `json:"default_bookmaker"`
+	ValueBetThreshold  float64   `json:"value_bet_threshold" gorm:"default:5"`
+	BuyMarginOfSafety  float64   `json:"buy_margin_of_safety" gorm:"default:20"`   // Percent below fair value at which a stock is a buy
+	SellMarginOfSafety float64   `json:"sell_margin_of_safety" gorm:"default:-10"` // Negative: percent above fair value at which a stock is a sell
+	MaxDailyBets       int       `json:"max_daily_bets" gorm:"default:10"`
+	MaxStakePerBet     float64   `json:"max_stake_per_bet"`
+	PreferredLeagues   string    `json:"preferred_leagues"` // JSON array
+	NotifyEmail        bool      `json:"notify_email" gorm:"default:true"`
+	NotifyTelegram     bool      `json:"notify_telegram" gorm:"default:false"`
+	NotifyLINE         bool      `json:"notify_line" gorm:"default:false"`
+	NotifyDiscord      bool      `json:"notify_discord" gorm:"default:false"`
+	TelegramChatID     string    `json:"telegram_chat_id"`
+	LINEToken          string    `json:"line_token"`
+	DiscordWebhook     string    `json:"discord_webhook"`
+	Theme              string    `json:"theme" gorm:"default:'dark'"`
+	Language           string    `json:"language" gorm:"default:'en'"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 }
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/pkg/pq"
+	"github.com/google/uuid"
+)
+
+// ScreenerPreset represents a saved stock screener filter owned by a user.
+type ScreenerPreset struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID      uuid.UUID      `json:"user_id" gorm:"type:uuid;index;not null"`
+	User        User           `json:"-" gorm:"foreignKey:UserID"`
+	Name        string         `json:"name" gorm:"not null"`
+	Filter      string         `json:"filter" gorm:"type:jsonb;not null"` // JSON-encoded screener filter
+	NotifyOnNew bool           `json:"notify_on_new" gorm:"default:true"`
+	LastResults pq.StringArray `json:"last_results" gorm:"type:text[]"` // Symbols matched by the last daily run
+	LastRunAt   *time.Time     `json:"last_run_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// TableName returns the table name for the ScreenerPreset model.
+func (ScreenerPreset) TableName() string {
+	return "screener_presets"
+}
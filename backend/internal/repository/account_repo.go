@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserDataRepository removes the records a user owns when their account is
+// deleted. Sessions, OAuth accounts and 2FA settings are handled by the auth
+// repositories.
+type UserDataRepository interface {
+	// DeleteUserData removes everything the user owns and detaches their
+	// audit logs so the user row itself can be deleted.
+	DeleteUserData(userID uuid.UUID) error
+	// DeletePersonalData removes the user's personal records (settings,
+	// notifications, alerts, watchlists, favorites, screener presets, goals
+	// and journal entries) but keeps their portfolios, bets and bankroll
+	// history for an anonymized account.
+	DeletePersonalData(userID uuid.UUID) error
+}
+
+// userDataRepository implements UserDataRepository using GORM.
+type userDataRepository struct {
+	db *gorm.DB
+}
+
+// NewUserDataRepository creates a new UserDataRepository instance.
+func NewUserDataRepository(db *gorm.DB) UserDataRepository {
+	return &userDataRepository{db: db}
+}
+
+// DeleteUserData removes all of the user's records in a single transaction.
+func (r *userDataRepository) DeleteUserData(userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := deletePersonalData(tx, userID); err != nil {
+			return err
+		}
+
+		portfolios := tx.Model(&model.Portfolio{}).Select("id").Where("user_id = ?", userID)
+		for _, owned := range []interface{}{&model.Trade{}, &model.Order{}, &model.Position{}} {
+			if err := tx.Where("portfolio_id IN (?)", portfolios).Delete(owned).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, owned := range []interface{}{&model.Portfolio{}, &model.Bet{}, &model.BankrollHistory{}} {
+			if err := tx.Where("user_id = ?", userID).Delete(owned).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&model.AuditLog{}).Where("user_id = ?", userID).Update("user_id", nil).Error
+	})
+}
+
+// DeletePersonalData removes the user's personal records in a single transaction.
+func (r *userDataRepository) DeletePersonalData(userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return deletePersonalData(tx, userID)
+	})
+}
+
+// deletePersonalData removes the records DeletePersonalData covers. Journal
+// entries go first because they reference trades and bets.
+func deletePersonalData(tx *gorm.DB, userID uuid.UUID) error {
+	watchlists := tx.Model(&model.Watchlist{}).Select("id").Where("user_id = ?", userID)
+	if err := tx.Where("watchlist_id IN (?)", watchlists).Delete(&model.WatchlistItem{}).Error; err != nil {
+		return err
+	}
+
+	owned := []interface{}{
+		&model.TradeJournal{},
+		&model.Watchlist{},
+		&model.Alert{},
+		&model.Notification{},
+		&model.Favorite{},
+		&model.ScreenerPreset{},
+		&model.Goal{},
+		&model.Settings{},
+	}
+	for _, record := range owned {
+		if err := tx.Where("user_id = ?", userID).Delete(record).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"super-dashboard/backend/internal/model"
+	"gorm.io/gorm/clause"
 )
 
 // AlertRepository handles database operations for alerts.
@@ -39,6 +39,16 @@ func (r *AlertRepository) GetActiveAlertsByUser(ctx context.Context, userID uuid
 	return alerts, err
 }
 
+// GetUserAlerts retrieves all alerts for a specific user, active or not.
+func (r *AlertRepository) GetUserAlerts(ctx context.Context, userID uuid.UUID) ([]model.Alert, error) {
+	var alerts []model.Alert
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&alerts).Error
+	return alerts, err
+}
+
 // GetAlertsBySymbol retrieves all active alerts for a specific symbol.
 func (r *AlertRepository) GetAlertsBySymbol(ctx context.Context, symbol string) ([]model.Alert, error) {
 	var alerts []model.Alert
@@ -73,6 +83,17 @@ func (r *AlertRepository) UpdateAlertTrigger(ctx context.Context, alertID uuid.U
 		}).Error
 }
 
+// UpdateAlertValue records the value an alert was last evaluated against.
+func (r *AlertRepository) UpdateAlertValue(ctx context.Context, alertID uuid.UUID, currentValue float64) error {
+	return r.db.WithContext(ctx).
+		Model(&model.Alert{}).
+		Where("id = ?", alertID).
+		Updates(map[string]interface{}{
+			"current_value": currentValue,
+			"updated_at":    time.Now(),
+		}).Error
+}
+
 // DeactivateAlert deactivates an alert.
 func (r *AlertRepository) DeactivateAlert(ctx context.Context, alertID uuid.UUID) error {
 	return r.db.WithContext(ctx).
@@ -86,9 +107,9 @@ func (r *AlertRepository) CreateAlert(ctx context.Context, alert *model.Alert) e
 	return r.db.WithContext(ctx).Create(alert).Error
 }
 
-// UpdateAlert updates an alert.
+// UpdateAlert updates an alert. The preloaded user is not saved.
 func (r *AlertRepository) UpdateAlert(ctx context.Context, alert *model.Alert) error {
-	return r.db.WithContext(ctx).Save(alert).Error
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(alert).Error
 }
 
 // DeleteAlert deletes an alert.
@@ -145,6 +166,26 @@ func (r *NotificationRepository) GetUnreadNotifications(ctx context.Context, use
 	return notifications, err
 }
 
+// CountUserNotifications counts a user's notifications.
+func (r *NotificationRepository) CountUserNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// CountUnread counts a user's unread notifications.
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("user_id = ? AND status = ?", userID, model.NotificationStatusUnread).
+		Count(&count).Error
+	return count, err
+}
+
 // MarkAsRead marks a notification as read.
 func (r *NotificationRepository) MarkAsRead(ctx context.Context, notificationID uuid.UUID) error {
 	now := time.Now()
@@ -181,3 +222,33 @@ func (r *NotificationRepository) DeleteOldNotifications(ctx context.Context, old
 		Where("created_at < ?", cutoff).
 		Delete(&model.Notification{}).Error
 }
+
+// GetNotification retrieves a notification by ID.
+func (r *NotificationRepository) GetNotification(ctx context.Context, notificationID uuid.UUID) (*model.Notification, error) {
+	var notification model.Notification
+	err := r.db.WithContext(ctx).Where("id = ?", notificationID).First(&notification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// CreateDelivery records a delivery attempt for a notification.
+func (r *NotificationRepository) CreateDelivery(ctx context.Context, delivery *model.NotificationDelivery) error {
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+
+// UpdateDelivery saves a delivery's status, error, attempts and delivery time.
+func (r *NotificationRepository) UpdateDelivery(ctx context.Context, delivery *model.NotificationDelivery) error {
+	return r.db.WithContext(ctx).Save(delivery).Error
+}
+
+// GetDeliveries retrieves a notification's deliveries in the order they were recorded.
+func (r *NotificationRepository) GetDeliveries(ctx context.Context, notificationID uuid.UUID) ([]model.NotificationDelivery, error) {
+	var deliveries []model.NotificationDelivery
+	err := r.db.WithContext(ctx).
+		Where("notification_id = ?", notificationID).
+		Order("created_at ASC").
+		Find(&deliveries).Error
+	return deliveries, err
+}
@@ -4,14 +4,15 @@ import (
 	"context"
 	"sync"
 
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
 )
 
 // ArticleRepository defines the interface for article data operations.
 type ArticleRepository interface {
 	Create(ctx context.Context, article *model.Article) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Article, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, limit, offset int) ([]model.Article, error)
 	Search(ctx context.Context, query string, limit int) ([]model.Article, error)
 	StoreEmbedding(ctx context.Context, articleID uuid.UUID, embedding []float32) error
@@ -63,6 +64,19 @@ func (r *InMemoryArticleRepository) GetByID(ctx context.Context, id uuid.UUID) (
 	return article, nil
 }
 
+// Delete removes an article and its embedding.
+func (r *InMemoryArticleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.articles[id]; !exists {
+		return ErrNotFound
+	}
+	delete(r.articles, id)
+	delete(r.embeddings, id)
+	return nil
+}
+
 // List returns a paginated list of articles.
 func (r *InMemoryArticleRepository) List(ctx context.Context, limit, offset int) ([]model.Article, error) {
 	r.mu.RLock()
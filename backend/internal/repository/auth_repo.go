@@ -41,12 +41,21 @@ type TwoFactorAuthRepository interface {
 	Delete(userID uuid.UUID) error
 }
 
+// AuditLogFilter narrows audit log queries. Nil fields match every entry.
+type AuditLogFilter struct {
+	UserID  *uuid.UUID
+	Success *bool
+}
+
 // AuditLogRepository defines the interface for audit log operations.
 type AuditLogRepository interface {
 	Create(log *model.AuditLog) error
 	GetByUserID(userID uuid.UUID, limit, offset int) ([]model.AuditLog, error)
-	GetByAction(action model.AuditAction, limit, offset int) ([]model.AuditLog, error)
-	GetRecent(limit int) ([]model.AuditLog, error)
+	GetByAction(action model.AuditAction, filter AuditLogFilter, limit, offset int) ([]model.AuditLog, error)
+	GetRecent(filter AuditLogFilter, limit, offset int) ([]model.AuditLog, error)
+	// Count returns the number of entries matching filter and, when action
+	// is not empty, action.
+	Count(action model.AuditAction, filter AuditLogFilter) (int64, error)
 	DeleteOlderThan(before time.Time) error
 }
 
@@ -219,24 +228,45 @@ func (r *auditLogRepository) GetByUserID(userID uuid.UUID, limit, offset int) ([
 	return logs, nil
 }
 
-func (r *auditLogRepository) GetByAction(action model.AuditAction, limit, offset int) ([]model.AuditLog, error) {
+func (r *auditLogRepository) GetByAction(action model.AuditAction, filter AuditLogFilter, limit, offset int) ([]model.AuditLog, error) {
 	var logs []model.AuditLog
-	err := r.db.Where("action = ?", action).Order("created_at DESC").Limit(limit).Offset(offset).Find(&logs).Error
+	err := r.filtered(action, filter).Order("created_at DESC").Limit(limit).Offset(offset).Find(&logs).Error
 	if err != nil {
 		return nil, err
 	}
 	return logs, nil
 }
 
-func (r *auditLogRepository) GetRecent(limit int) ([]model.AuditLog, error) {
+func (r *auditLogRepository) GetRecent(filter AuditLogFilter, limit, offset int) ([]model.AuditLog, error) {
 	var logs []model.AuditLog
-	err := r.db.Order("created_at DESC").Limit(limit).Find(&logs).Error
+	err := r.filtered("", filter).Order("created_at DESC").Limit(limit).Offset(offset).Find(&logs).Error
 	if err != nil {
 		return nil, err
 	}
 	return logs, nil
 }
 
+func (r *auditLogRepository) Count(action model.AuditAction, filter AuditLogFilter) (int64, error) {
+	var count int64
+	err := r.filtered(action, filter).Model(&model.AuditLog{}).Count(&count).Error
+	return count, err
+}
+
+// filtered scopes a query to the entries matching action and filter.
+func (r *auditLogRepository) filtered(action model.AuditAction, filter AuditLogFilter) *gorm.DB {
+	query := r.db
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+	return query
+}
+
 func (r *auditLogRepository) DeleteOlderThan(before time.Time) error {
 	return r.db.Delete(&model.AuditLog{}, "created_at < ?", before).Error
 }
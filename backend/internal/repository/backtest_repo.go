@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BacktestResultRepository defines the interface for stored backtest results.
+type BacktestResultRepository interface {
+	Create(result *model.BacktestResult) error
+	GetByID(id uuid.UUID) (*model.BacktestResult, error)
+	// List returns up to limit results, most recently completed first.
+	List(limit int) ([]model.BacktestResult, error)
+}
+
+// backtestResultRepository implements BacktestResultRepository using GORM.
+type backtestResultRepository struct {
+	db *gorm.DB
+}
+
+// NewBacktestResultRepository creates a new BacktestResultRepository instance.
+func NewBacktestResultRepository(db *gorm.DB) BacktestResultRepository {
+	return &backtestResultRepository{db: db}
+}
+
+// Create stores a backtest result.
+func (r *backtestResultRepository) Create(result *model.BacktestResult) error {
+	return r.db.Create(result).Error
+}
+
+// GetByID retrieves a backtest result by its ID.
+func (r *backtestResultRepository) GetByID(id uuid.UUID) (*model.BacktestResult, error) {
+	var result model.BacktestResult
+	err := r.db.Where("id = ?", id).First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// List retrieves the most recently completed backtest results.
+func (r *backtestResultRepository) List(limit int) ([]model.BacktestResult, error) {
+	var results []model.BacktestResult
+	err := r.db.Order("completed_at DESC").Limit(limit).Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// InMemoryBacktestResultRepository is an in-memory implementation of BacktestResultRepository for mock mode.
+type InMemoryBacktestResultRepository struct {
+	mu      sync.RWMutex
+	results map[uuid.UUID]*model.BacktestResult
+}
+
+// NewInMemoryBacktestResultRepository creates a new in-memory backtest result repository.
+func NewInMemoryBacktestResultRepository() BacktestResultRepository {
+	return &InMemoryBacktestResultRepository{
+		results: make(map[uuid.UUID]*model.BacktestResult),
+	}
+}
+
+func (r *InMemoryBacktestResultRepository) Create(result *model.BacktestResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if result.ID == uuid.Nil {
+		result.ID = uuid.New()
+	}
+	r.results[result.ID] = result
+	return nil
+}
+
+func (r *InMemoryBacktestResultRepository) GetByID(id uuid.UUID) (*model.BacktestResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if result, ok := r.results[id]; ok {
+		return result, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *InMemoryBacktestResultRepository) List(limit int) ([]model.BacktestResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]model.BacktestResult, 0, len(r.results))
+	for _, backtest := range r.results {
+		result = append(result, *backtest)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CompletedAt.After(result[j].CompletedAt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
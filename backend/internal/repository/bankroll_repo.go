@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BankrollRepository defines the interface for users' bankroll history.
+type BankrollRepository interface {
+	// Append adds an entry to the end of a user's bankroll history.
+	Append(entry *model.BankrollHistory) error
+	// GetByUserID returns a user's entries from from up to, but not
+	// including, to, oldest first. A zero from or to leaves that end open.
+	GetByUserID(userID uuid.UUID, from, to time.Time) ([]model.BankrollHistory, error)
+}
+
+// bankrollRepository implements BankrollRepository using GORM.
+type bankrollRepository struct {
+	db *gorm.DB
+}
+
+// NewBankrollRepository creates a new BankrollRepository instance.
+func NewBankrollRepository(db *gorm.DB) BankrollRepository {
+	return &bankrollRepository{db: db}
+}
+
+// Append stores a bankroll history entry.
+func (r *bankrollRepository) Append(entry *model.BankrollHistory) error {
+	return r.db.Omit("User").Create(entry).Error
+}
+
+// GetByUserID retrieves a user's bankroll history in a time range.
+func (r *bankrollRepository) GetByUserID(userID uuid.UUID, from, to time.Time) ([]model.BankrollHistory, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at < ?", to)
+	}
+
+	var history []model.BankrollHistory
+	if err := query.Order("created_at ASC").Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
@@ -2,14 +2,17 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"super-dashboard/backend/internal/model"
 )
 
+// ErrBetNotPending is returned when settling a bet that is no longer pending.
+var ErrBetNotPending = errors.New("bet is not pending")
+
 // BetRepository handles database operations for bets.
 type BetRepository struct {
 	db *gorm.DB
@@ -34,20 +37,20 @@ type BetFilters struct {
 
 // BetStats represents betting statistics.
 type BetStats struct {
-	TotalBets      int
-	WonBets        int
-	LostBets       int
-	PendingBets    int
-	TotalStake     float64
-	TotalProfit    float64
-	WinRate        float64
-	ROI            float64
-	AverageOdds    float64
-	AverageStake   float64
+	TotalBets         int
+	WonBets           int
+	LostBets          int
+	PendingBets       int
+	TotalStake        float64
+	TotalProfit       float64
+	WinRate           float64
+	ROI               float64
+	AverageOdds       float64
+	AverageStake      float64
 	LongestWinStreak  int
 	LongestLoseStreak int
-	CurrentStreak  int
-	StreakType     string // "win" or "lose"
+	CurrentStreak     int
+	StreakType        string // "win" or "lose"
 }
 
 // CreateBet creates a new bet.
@@ -117,19 +120,85 @@ func (r *BetRepository) UpdateBet(ctx context.Context, bet *model.Bet) error {
 	return r.db.WithContext(ctx).Save(bet).Error
 }
 
-// SettleBet settles a bet with result and profit.
-func (r *BetRepository) SettleBet(ctx context.Context, betID uuid.UUID, result string, profit float64) error {
-	now := time.Now()
-	return r.db.WithContext(ctx).
+// GetMatch retrieves a match with its teams.
+func (r *BetRepository) GetMatch(ctx context.Context, matchID uuid.UUID) (*model.Match, error) {
+	var match model.Match
+	err := r.db.WithContext(ctx).
+		Preload("HomeTeam").
+		Preload("AwayTeam").
+		First(&match, "id = ?", matchID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// CountUserBetsSince counts the bets a user has placed since a time.
+func (r *BetRepository) CountUserBetsSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
 		Model(&model.Bet{}).
-		Where("id = ?", betID).
-		Updates(map[string]interface{}{
-			"status":     "settled",
-			"result":     result,
-			"profit":     profit,
-			"settled_at": now,
-			"updated_at": now,
-		}).Error
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// PlaceBet stores a bet and debits its stake from the user's bankroll in a
+// single transaction. debit.Change is applied to Settings.CurrentBankroll and
+// debit is recorded with the resulting balance.
+func (r *BetRepository) PlaceBet(ctx context.Context, bet *model.Bet, debit *model.BankrollHistory) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User", "Match").Create(bet).Error; err != nil {
+			return err
+		}
+		return recordBankrollChange(tx, debit)
+	})
+}
+
+// SettleBet saves a pending bet's status, result and profit and, when credit
+// is not nil, credits the user's bankroll in the same transaction. It returns
+// ErrBetNotPending if the bet was settled or cancelled in the meantime.
+func (r *BetRepository) SettleBet(ctx context.Context, bet *model.Bet, credit *model.BankrollHistory) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Bet{}).
+			Where("id = ? AND status = ?", bet.ID, "pending").
+			Updates(map[string]interface{}{
+				"status":     bet.Status,
+				"result":     bet.Result,
+				"profit":     bet.Profit,
+				"settled_at": bet.SettledAt,
+				"updated_at": bet.UpdatedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrBetNotPending
+		}
+		if credit == nil {
+			return nil
+		}
+		return recordBankrollChange(tx, credit)
+	})
+}
+
+// recordBankrollChange adds entry.Change to the user's current bankroll and
+// records entry with the new balance.
+func recordBankrollChange(tx *gorm.DB, entry *model.BankrollHistory) error {
+	err := tx.Model(&model.Settings{}).
+		Where("user_id = ?", entry.UserID).
+		Update("current_bankroll", gorm.Expr("current_bankroll + ?", entry.Change)).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&model.Settings{}).
+		Where("user_id = ?", entry.UserID).
+		Select("current_bankroll").
+		Scan(&entry.Balance).Error
+	if err != nil {
+		return err
+	}
+	return tx.Omit("User").Create(entry).Error
 }
 
 // GetBetStats calculates betting statistics for a user.
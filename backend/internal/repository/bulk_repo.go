@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BulkRepository defines the interface for adding many watchlist items or
+// alerts at once.
+type BulkRepository interface {
+	GetStocksBySymbols(symbols []string) ([]model.Stock, error)
+	// GetWatchlist retrieves a watchlist with its items.
+	GetWatchlist(watchlistID uuid.UUID) (*model.Watchlist, error)
+	// AddWatchlistItems creates the items in a single transaction.
+	AddWatchlistItems(items []model.WatchlistItem) error
+	GetActiveAlerts(userID uuid.UUID) ([]model.Alert, error)
+	// CreateAlerts creates the alerts in a single transaction.
+	CreateAlerts(alerts []model.Alert) error
+}
+
+// bulkRepository implements BulkRepository using GORM.
+type bulkRepository struct {
+	db *gorm.DB
+}
+
+// NewBulkRepository creates a new BulkRepository instance.
+func NewBulkRepository(db *gorm.DB) BulkRepository {
+	return &bulkRepository{db: db}
+}
+
+// GetStocksBySymbols retrieves the stocks stored under any of the symbols.
+func (r *bulkRepository) GetStocksBySymbols(symbols []string) ([]model.Stock, error) {
+	var stocks []model.Stock
+	if len(symbols) == 0 {
+		return stocks, nil
+	}
+	err := r.db.Where("symbol IN ?", symbols).Find(&stocks).Error
+	if err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// GetWatchlist retrieves a watchlist by ID with its items.
+func (r *bulkRepository) GetWatchlist(watchlistID uuid.UUID) (*model.Watchlist, error) {
+	var watchlist model.Watchlist
+	err := r.db.Preload("Items").Where("id = ?", watchlistID).First(&watchlist).Error
+	if err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+// AddWatchlistItems creates all items or none of them.
+func (r *bulkRepository) AddWatchlistItems(items []model.WatchlistItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&items).Error
+	})
+}
+
+// GetActiveAlerts retrieves a user's active alerts.
+func (r *bulkRepository) GetActiveAlerts(userID uuid.UUID) ([]model.Alert, error) {
+	var alerts []model.Alert
+	err := r.db.Where("user_id = ? AND active = ?", userID, true).Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// CreateAlerts creates all alerts or none of them.
+func (r *bulkRepository) CreateAlerts(alerts []model.Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&alerts).Error
+	})
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OpenBetTotals summarizes a user's unsettled bets.
+type OpenBetTotals struct {
+	Count int64
+	Stake float64
+}
+
+// WatchlistStockPrices holds the two most recent price bars of a stock on a
+// user's watchlists. Previous is nil when only one bar is stored.
+type WatchlistStockPrices struct {
+	Stock    model.Stock
+	Latest   model.StockPrice
+	Previous *model.StockPrice
+}
+
+// DashboardSummaryRepository defines the interface for reading the figures
+// shown on a user's dashboard.
+type DashboardSummaryRepository interface {
+	// GetPortfolios retrieves the user's paper portfolios with their positions.
+	GetPortfolios(ctx context.Context, userID uuid.UUID) ([]model.Portfolio, error)
+	GetOpenBetTotals(ctx context.Context, userID uuid.UUID) (OpenBetTotals, error)
+	// GetSettings returns nil when the user has no settings yet.
+	GetSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error)
+	CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountActiveAlerts(ctx context.Context, userID uuid.UUID) (int64, error)
+	// GetWatchlistStockPrices retrieves each distinct stock on the user's
+	// watchlists that has price data.
+	GetWatchlistStockPrices(ctx context.Context, userID uuid.UUID) ([]WatchlistStockPrices, error)
+}
+
+// dashboardSummaryRepository implements DashboardSummaryRepository using GORM.
+type dashboardSummaryRepository struct {
+	db *gorm.DB
+}
+
+// NewDashboardSummaryRepository creates a new DashboardSummaryRepository instance.
+func NewDashboardSummaryRepository(db *gorm.DB) DashboardSummaryRepository {
+	return &dashboardSummaryRepository{db: db}
+}
+
+// GetPortfolios retrieves a user's portfolios with their positions.
+func (r *dashboardSummaryRepository) GetPortfolios(ctx context.Context, userID uuid.UUID) ([]model.Portfolio, error) {
+	var portfolios []model.Portfolio
+	err := r.db.WithContext(ctx).
+		Preload("Positions").
+		Where("user_id = ?", userID).
+		Find(&portfolios).Error
+	if err != nil {
+		return nil, err
+	}
+	return portfolios, nil
+}
+
+// GetOpenBetTotals counts a user's pending bets and sums their stakes.
+func (r *dashboardSummaryRepository) GetOpenBetTotals(ctx context.Context, userID uuid.UUID) (OpenBetTotals, error) {
+	var totals OpenBetTotals
+	err := r.db.WithContext(ctx).
+		Model(&model.Bet{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(stake), 0) AS stake").
+		Where("user_id = ? AND status = ?", userID, "pending").
+		Scan(&totals).Error
+	if err != nil {
+		return OpenBetTotals{}, err
+	}
+	return totals, nil
+}
+
+// GetSettings retrieves a user's settings, or nil when none are stored.
+func (r *dashboardSummaryRepository) GetSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error) {
+	var settings model.Settings
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Limit(1).Find(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	if settings.ID == uuid.Nil {
+		return nil, nil
+	}
+	return &settings, nil
+}
+
+// CountUnreadNotifications counts a user's unread notifications.
+func (r *dashboardSummaryRepository) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("user_id = ? AND status = ?", userID, model.NotificationStatusUnread).
+		Count(&count).Error
+	return count, err
+}
+
+// CountActiveAlerts counts a user's active alerts.
+func (r *dashboardSummaryRepository) CountActiveAlerts(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.Alert{}).
+		Where("user_id = ? AND active = ?", userID, true).
+		Count(&count).Error
+	return count, err
+}
+
+// GetWatchlistStockPrices loads the watched stocks, then the latest two bars
+// of each. Watchlists are small, so a query per stock keeps this portable.
+func (r *dashboardSummaryRepository) GetWatchlistStockPrices(ctx context.Context, userID uuid.UUID) ([]WatchlistStockPrices, error) {
+	db := r.db.WithContext(ctx)
+
+	var stocks []model.Stock
+	err := db.
+		Where("id IN (?)", db.Model(&model.WatchlistItem{}).
+			Select("watchlist_items.stock_id").
+			Joins("JOIN watchlists ON watchlists.id = watchlist_items.watchlist_id").
+			Where("watchlists.user_id = ?", userID)).
+		Order("symbol").
+		Find(&stocks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]WatchlistStockPrices, 0, len(stocks))
+	for _, stock := range stocks {
+		var bars []model.StockPrice
+		err := db.Where("stock_id = ?", stock.ID).
+			Order("timestamp DESC").
+			Limit(2).
+			Find(&bars).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(bars) == 0 {
+			continue
+		}
+
+		prices := WatchlistStockPrices{Stock: stock, Latest: bars[0]}
+		if len(bars) > 1 {
+			prices.Previous = &bars[1]
+		}
+		result = append(result, prices)
+	}
+	return result, nil
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
-	"super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/model"
 )
 
 // FairValueRepository handles database operations for fair value calculations.
@@ -38,6 +38,13 @@ func (r *FairValueRepository) GetLatestFairValue(ctx context.Context, symbol str
 	return &fv, nil
 }
 
+// GetTrackedStocks retrieves the stocks whose fair value is recalculated on a schedule.
+func (r *FairValueRepository) GetTrackedStocks(ctx context.Context) ([]model.Stock, error) {
+	var stocks []model.Stock
+	err := r.db.WithContext(ctx).Order("symbol ASC").Find(&stocks).Error
+	return stocks, err
+}
+
 // GetFairValueByMethod retrieves fair value by calculation method.
 func (r *FairValueRepository) GetFairValueByMethod(ctx context.Context, symbol, method string) (*model.FairValue, error) {
 	var fv model.FairValue
@@ -65,7 +72,7 @@ func (r *FairValueRepository) GetFairValueHistory(ctx context.Context, symbol st
 // GetUndervaluedStocks retrieves stocks trading below fair value.
 func (r *FairValueRepository) GetUndervaluedStocks(ctx context.Context, threshold float64) ([]model.FairValue, error) {
 	var fvList []model.FairValue
-	
+
 	// Get latest fair value for each stock
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT DISTINCT ON (symbol) *
@@ -80,7 +87,7 @@ func (r *FairValueRepository) GetUndervaluedStocks(ctx context.Context, threshol
 // GetOvervaluedStocks retrieves stocks trading above fair value.
 func (r *FairValueRepository) GetOvervaluedStocks(ctx context.Context, threshold float64) ([]model.FairValue, error) {
 	var fvList []model.FairValue
-	
+
 	// Get latest fair value for each stock with negative upside
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT DISTINCT ON (symbol) *
@@ -95,7 +102,7 @@ func (r *FairValueRepository) GetOvervaluedStocks(ctx context.Context, threshold
 // GetFairValuesByRating retrieves fair values by investment rating.
 func (r *FairValueRepository) GetFairValuesByRating(ctx context.Context, rating string) ([]model.FairValue, error) {
 	var fvList []model.FairValue
-	
+
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT DISTINCT ON (symbol) *
 		FROM fair_values
@@ -127,7 +134,7 @@ func (r *FairValueRepository) GetFairValueByID(ctx context.Context, id uuid.UUID
 // GetAllLatestFairValues retrieves the latest fair value for all stocks.
 func (r *FairValueRepository) GetAllLatestFairValues(ctx context.Context) ([]model.FairValue, error) {
 	var fvList []model.FairValue
-	
+
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT DISTINCT ON (symbol) *
 		FROM fair_values
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FavoriteRepository defines the interface for favorite data operations.
+type FavoriteRepository interface {
+	Create(favorite *model.Favorite) error
+	GetByUserAndEntity(userID uuid.UUID, entityType model.FavoriteEntityType, entityID string) (*model.Favorite, error)
+	// GetByUserID lists a user's favorites, optionally restricted to one entity type.
+	GetByUserID(userID uuid.UUID, entityType model.FavoriteEntityType) ([]model.Favorite, error)
+	Delete(id uuid.UUID) error
+}
+
+// favoriteRepository implements FavoriteRepository using GORM.
+type favoriteRepository struct {
+	db *gorm.DB
+}
+
+// NewFavoriteRepository creates a new FavoriteRepository instance.
+func NewFavoriteRepository(db *gorm.DB) FavoriteRepository {
+	return &favoriteRepository{db: db}
+}
+
+// Create creates a new favorite in the database.
+func (r *favoriteRepository) Create(favorite *model.Favorite) error {
+	return r.db.Create(favorite).Error
+}
+
+// GetByUserAndEntity retrieves a user's favorite for a specific entity.
+func (r *favoriteRepository) GetByUserAndEntity(userID uuid.UUID, entityType model.FavoriteEntityType, entityID string) (*model.Favorite, error) {
+	var favorite model.Favorite
+	err := r.db.Where("user_id = ? AND entity_type = ? AND entity_id = ?", userID, entityType, entityID).
+		First(&favorite).Error
+	if err != nil {
+		return nil, err
+	}
+	return &favorite, nil
+}
+
+// GetByUserID retrieves a user's favorites, newest first.
+func (r *favoriteRepository) GetByUserID(userID uuid.UUID, entityType model.FavoriteEntityType) ([]model.Favorite, error) {
+	var favorites []model.Favorite
+	query := r.db.Where("user_id = ?", userID)
+	if entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	err := query.Order("created_at DESC").Find(&favorites).Error
+	if err != nil {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+// Delete deletes a favorite by its ID.
+func (r *favoriteRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&model.Favorite{}, "id = ?", id).Error
+}
+
+// InMemoryFavoriteRepository is an in-memory implementation of FavoriteRepository for mock mode.
+type InMemoryFavoriteRepository struct {
+	mu        sync.RWMutex
+	favorites map[uuid.UUID]*model.Favorite
+}
+
+// NewInMemoryFavoriteRepository creates a new in-memory favorite repository.
+func NewInMemoryFavoriteRepository() FavoriteRepository {
+	return &InMemoryFavoriteRepository{
+		favorites: make(map[uuid.UUID]*model.Favorite),
+	}
+}
+
+func (r *InMemoryFavoriteRepository) Create(favorite *model.Favorite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, f := range r.favorites {
+		if f.UserID == favorite.UserID && f.EntityType == favorite.EntityType && f.EntityID == favorite.EntityID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	if favorite.ID == uuid.Nil {
+		favorite.ID = uuid.New()
+	}
+	r.favorites[favorite.ID] = favorite
+	return nil
+}
+
+func (r *InMemoryFavoriteRepository) GetByUserAndEntity(userID uuid.UUID, entityType model.FavoriteEntityType, entityID string) (*model.Favorite, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, f := range r.favorites {
+		if f.UserID == userID && f.EntityType == entityType && f.EntityID == entityID {
+			return f, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *InMemoryFavoriteRepository) GetByUserID(userID uuid.UUID, entityType model.FavoriteEntityType) ([]model.Favorite, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []model.Favorite
+	for _, f := range r.favorites {
+		if f.UserID == userID && (entityType == "" || f.EntityType == entityType) {
+			result = append(result, *f)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+func (r *InMemoryFavoriteRepository) Delete(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.favorites[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.favorites, id)
+	return nil
+}
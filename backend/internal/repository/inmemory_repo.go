@@ -1,11 +1,12 @@
 package repository
 
 import (
+	"sort"
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -193,6 +194,23 @@ func (r *InMemoryOrderRepository) GetByPortfolioID(portfolioID uuid.UUID) ([]mod
 	return result, nil
 }
 
+func (r *InMemoryOrderRepository) ListByPortfolio(portfolioID uuid.UUID, status model.OrderStatus, filter HistoryFilter) ([]model.Order, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var matched []model.Order
+	for _, o := range r.orders {
+		if o.PortfolioID != portfolioID || (status != "" && o.Status != status) || !filter.contains(o.CreatedAt) {
+			continue
+		}
+		matched = append(matched, *o)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+	start, end := filter.bounds(len(matched))
+	return matched[start:end], int64(len(matched)), nil
+}
+
 func (r *InMemoryOrderRepository) Update(order *model.Order) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -254,6 +272,23 @@ func (r *InMemoryTradeRepository) GetByPortfolioID(portfolioID uuid.UUID) ([]mod
 	return result, nil
 }
 
+func (r *InMemoryTradeRepository) ListByPortfolio(portfolioID uuid.UUID, filter HistoryFilter) ([]model.Trade, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var matched []model.Trade
+	for _, t := range r.trades {
+		if t.PortfolioID != portfolioID || !filter.contains(t.ExecutedAt) {
+			continue
+		}
+		matched = append(matched, *t)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].ExecutedAt.After(matched[j].ExecutedAt)
+	})
+	start, end := filter.bounds(len(matched))
+	return matched[start:end], int64(len(matched)), nil
+}
+
 func (r *InMemoryTradeRepository) GetByOrderID(orderID uuid.UUID) ([]model.Trade, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -273,17 +308,18 @@ func SeedDefaultPortfolio(
 ) (*model.Portfolio, error) {
 	// Create a default user ID
 	userID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
-	
+
 	// Create default portfolio
 	portfolio := &model.Portfolio{
 		ID:          uuid.MustParse("00000000-0000-0000-0000-000000000001"),
 		UserID:      userID,
 		Name:        "Default Paper Portfolio",
 		CashBalance: 100000,
+		InitialCash: 100000,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
-	
+
 	if err := portfolioRepo.Create(portfolio); err != nil {
 		return nil, err
 	}
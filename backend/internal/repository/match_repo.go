@@ -3,21 +3,24 @@ package repository
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
 )
 
 // ErrNotFound is returned when a requested resource is not found.
 var ErrNotFound = errors.New("resource not found")
 
 // MatchMockData represents the structure of the mock matches JSON file.
+// Records are decoded one at a time into TeamJSON, MatchJSON and OddsJSON so
+// that a malformed record can be skipped without rejecting the file.
 type MatchMockData struct {
-	Teams   []TeamJSON  `json:"teams"`
-	Matches []MatchJSON `json:"matches"`
-	Odds    []OddsJSON  `json:"odds"`
+	Teams   []json.RawMessage `json:"teams"`
+	Matches []json.RawMessage `json:"matches"`
+	Odds    []json.RawMessage `json:"odds"`
 }
 
 // TeamJSON represents a team in the mock JSON format.
@@ -64,6 +67,8 @@ type mockMatchRepository struct {
 }
 
 // NewMockMatchRepository creates a new mock match repository from a JSON file.
+// Invalid records are skipped and reported in a *MockDataError returned with
+// the repository; any other error means the file could not be loaded at all.
 func NewMockMatchRepository(filePath string) (MatchRepository, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
@@ -80,9 +85,19 @@ func NewMockMatchRepository(filePath string) (MatchRepository, error) {
 		matches: make(map[string]model.Match),
 		odds:    make(map[string][]model.Odds),
 	}
+	problems := &MockDataError{File: filePath}
 
 	// Parse teams
-	for _, t := range mockData.Teams {
+	for i, raw := range mockData.Teams {
+		var t TeamJSON
+		if field, err := decodeMockRecord(raw, &t); err != nil {
+			problems.add("teams", i, mockRecordID(raw), field, err)
+			continue
+		}
+		if t.ID == "" {
+			problems.add("teams", i, "", "id", errors.New("is required"))
+			continue
+		}
 		repo.teams[t.ID] = model.Team{
 			ID:      stringToUUID(t.ID),
 			Name:    t.Name,
@@ -92,10 +107,31 @@ func NewMockMatchRepository(filePath string) (MatchRepository, error) {
 	}
 
 	// Parse matches
-	for _, m := range mockData.Matches {
-		startTime, _ := time.Parse(time.RFC3339, m.StartTime)
-		homeTeam := repo.teams[m.HomeTeamID]
-		awayTeam := repo.teams[m.AwayTeamID]
+	for i, raw := range mockData.Matches {
+		var m MatchJSON
+		if field, err := decodeMockRecord(raw, &m); err != nil {
+			problems.add("matches", i, mockRecordID(raw), field, err)
+			continue
+		}
+		if m.ID == "" {
+			problems.add("matches", i, "", "id", errors.New("is required"))
+			continue
+		}
+		startTime, err := time.Parse(time.RFC3339, m.StartTime)
+		if err != nil {
+			problems.add("matches", i, m.ID, "start_time", errors.New("must be an RFC3339 timestamp"))
+			continue
+		}
+		homeTeam, ok := repo.teams[m.HomeTeamID]
+		if !ok {
+			problems.add("matches", i, m.ID, "home_team_id", fmt.Errorf("unknown team %q", m.HomeTeamID))
+			continue
+		}
+		awayTeam, ok := repo.teams[m.AwayTeamID]
+		if !ok {
+			problems.add("matches", i, m.ID, "away_team_id", fmt.Errorf("unknown team %q", m.AwayTeamID))
+			continue
+		}
 
 		repo.matches[m.ID] = model.Match{
 			ID:         stringToUUID(m.ID),
@@ -111,7 +147,20 @@ func NewMockMatchRepository(filePath string) (MatchRepository, error) {
 	}
 
 	// Parse odds
-	for _, o := range mockData.Odds {
+	for i, raw := range mockData.Odds {
+		var o OddsJSON
+		if field, err := decodeMockRecord(raw, &o); err != nil {
+			problems.add("odds", i, mockRecordID(raw), field, err)
+			continue
+		}
+		if _, ok := repo.matches[o.MatchID]; !ok {
+			problems.add("odds", i, o.ID, "match_id", fmt.Errorf("unknown match %q", o.MatchID))
+			continue
+		}
+		if o.Price <= 1 {
+			problems.add("odds", i, o.ID, "price", errors.New("must be greater than 1"))
+			continue
+		}
 		matchOdds := model.Odds{
 			ID:        stringToUUID(o.ID),
 			MatchID:   stringToUUID(o.MatchID),
@@ -123,7 +172,7 @@ func NewMockMatchRepository(filePath string) (MatchRepository, error) {
 		repo.odds[o.MatchID] = append(repo.odds[o.MatchID], matchOdds)
 	}
 
-	return repo, nil
+	return repo, problems.errOrNil()
 }
 
 // GetAll returns all matches.
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -199,10 +200,10 @@ func TestMockMatchRepository_GetOddsByMatchID(t *testing.T) {
 	}
 
 	tests := []struct {
-		name         string
-		matchID      string
-		expectedLen  int
-		expectOdds   bool
+		name        string
+		matchID     string
+		expectedLen int
+		expectOdds  bool
 	}{
 		{
 			name:        "match 1 with 5 odds",
@@ -311,3 +312,57 @@ func TestStringToUUID(t *testing.T) {
 		t.Errorf("Expected different UUIDs for different inputs, got same: %s", uuid1)
 	}
 }
+
+func TestNewMockMatchRepository_SkipsInvalidRecords(t *testing.T) {
+	matchesJSON := `{
+		"teams": [
+			{ "id": "team-1", "name": "Arsenal", "country": "England", "elo": 1850 },
+			{ "id": "team-2", "name": "Chelsea", "country": "England", "elo": "1790" }
+		],
+		"matches": [
+			{ "id": "match-1", "home_team_id": "team-1", "away_team_id": "team-1", "start_time": "2024-03-01T15:00:00Z" },
+			{ "id": "match-2", "home_team_id": "team-1", "away_team_id": "team-2", "start_time": "2024-03-02T15:00:00Z" },
+			{ "id": "match-3", "home_team_id": "team-1", "away_team_id": "team-1", "start_time": "Saturday" }
+		],
+		"odds": [
+			{ "id": "odds-1", "match_id": "match-1", "outcome": "home", "price": 1.85 },
+			{ "id": "odds-2", "match_id": "match-1", "outcome": "away", "price": 0 },
+			{ "id": "odds-3", "match_id": "match-2", "outcome": "home", "price": 2.10 }
+		]
+	}`
+	path := filepath.Join(t.TempDir(), "matches.json")
+	if err := os.WriteFile(path, []byte(matchesJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	repo, err := NewMockMatchRepository(path)
+	var dataErr *MockDataError
+	if !errors.As(err, &dataErr) {
+		t.Fatalf("Expected *MockDataError, got %v", err)
+	}
+
+	want := []struct{ section, id, field string }{
+		{"teams", "team-2", "elo"},
+		{"matches", "match-2", "away_team_id"},
+		{"matches", "match-3", "start_time"},
+		{"odds", "odds-2", "price"},
+		{"odds", "odds-3", "match_id"},
+	}
+	if len(dataErr.Records) != len(want) {
+		t.Fatalf("Expected %d skipped records, got %d: %v", len(want), len(dataErr.Records), dataErr.Records)
+	}
+	for i, w := range want {
+		if got := dataErr.Records[i]; got.Section != w.section || got.ID != w.id || got.Field != w.field {
+			t.Errorf("Record %d: expected %s %s field %s, got %v", i, w.section, w.id, w.field, got)
+		}
+	}
+
+	matches, _ := repo.GetAll()
+	if len(matches) != 1 {
+		t.Errorf("Expected 1 valid match, got %d", len(matches))
+	}
+	odds, _ := repo.GetOddsByMatchID("match-1")
+	if len(odds) != 1 {
+		t.Errorf("Expected 1 valid odds record, got %d", len(odds))
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MockRecordError describes a record skipped while loading mock data.
+type MockRecordError struct {
+	Section string // top-level array the record is in, e.g. "matches"
+	Index   int
+	ID      string
+	Field   string
+	Err     error
+}
+
+// Error implements the error interface.
+func (e MockRecordError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s[%d]", e.Section, e.Index)
+	if e.ID != "" {
+		fmt.Fprintf(&b, " (%s)", e.ID)
+	}
+	if e.Field != "" {
+		fmt.Fprintf(&b, " field %s", e.Field)
+	}
+	fmt.Fprintf(&b, ": %v", e.Err)
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e MockRecordError) Unwrap() error {
+	return e.Err
+}
+
+// MockDataError reports the records skipped while loading a mock data file.
+// It is returned together with a repository holding the valid records, so a
+// single malformed record does not disable the whole file.
+type MockDataError struct {
+	File    string
+	Records []MockRecordError
+}
+
+// Error implements the error interface.
+func (e *MockDataError) Error() string {
+	return fmt.Sprintf("%s: skipped %d invalid record(s), first: %v", e.File, len(e.Records), e.Records[0])
+}
+
+// add records a skipped record.
+func (e *MockDataError) add(section string, index int, id, field string, err error) {
+	e.Records = append(e.Records, MockRecordError{Section: section, Index: index, ID: id, Field: field, Err: err})
+}
+
+// errOrNil returns e when any record was skipped, and nil otherwise.
+func (e *MockDataError) errOrNil() error {
+	if len(e.Records) == 0 {
+		return nil
+	}
+	return e
+}
+
+// decodeMockRecord decodes a single record, returning the offending field
+// name when a value has the wrong JSON type.
+func decodeMockRecord(raw json.RawMessage, v interface{}) (string, error) {
+	err := json.Unmarshal(raw, v)
+	if err == nil {
+		return "", nil
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return typeErr.Field, fmt.Errorf("expected %s, got %s", typeErr.Type, typeErr.Value)
+	}
+	return "", err
+}
+
+// mockRecordID extracts the id or symbol of a raw record for error reports,
+// even when the rest of the record does not decode.
+func mockRecordID(raw json.RawMessage) string {
+	var keys struct {
+		ID     interface{} `json:"id"`
+		Symbol interface{} `json:"symbol"`
+	}
+	if json.Unmarshal(raw, &keys) != nil {
+		return ""
+	}
+	for _, v := range []interface{}{keys.ID, keys.Symbol} {
+		if v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
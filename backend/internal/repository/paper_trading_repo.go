@@ -1,11 +1,71 @@
 package repository
 
 import (
-	"github.com/google/uuid"
+	"time"
+
 	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// HistoryFilter narrows and paginates order and trade history queries.
+// From is inclusive and To is exclusive; a zero Limit returns all matching rows.
+type HistoryFilter struct {
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Offset int
+}
+
+// apply adds the date range on column and the pagination to query.
+func (f HistoryFilter) apply(query *gorm.DB, column string) *gorm.DB {
+	if f.From != nil {
+		query = query.Where(column+" >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where(column+" < ?", *f.To)
+	}
+	return query
+}
+
+// paginate adds the limit and offset to query.
+func (f HistoryFilter) paginate(query *gorm.DB) *gorm.DB {
+	if f.Limit > 0 {
+		query = query.Limit(f.Limit)
+	}
+	if f.Offset > 0 {
+		query = query.Offset(f.Offset)
+	}
+	return query
+}
+
+// contains reports whether t falls inside the filter's date range.
+func (f HistoryFilter) contains(t time.Time) bool {
+	if f.From != nil && t.Before(*f.From) {
+		return false
+	}
+	if f.To != nil && !t.Before(*f.To) {
+		return false
+	}
+	return true
+}
+
+// bounds returns the slice bounds of the requested page within total rows.
+func (f HistoryFilter) bounds(total int) (int, int) {
+	start := f.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end := total
+	if f.Limit > 0 && start+f.Limit < total {
+		end = start + f.Limit
+	}
+	return start, end
+}
+
 // PortfolioRepository defines the interface for portfolio data operations.
 type PortfolioRepository interface {
 	Create(portfolio *model.Portfolio) error
@@ -141,6 +201,9 @@ type OrderRepository interface {
 	Create(order *model.Order) error
 	GetByID(id uuid.UUID) (*model.Order, error)
 	GetByPortfolioID(portfolioID uuid.UUID) ([]model.Order, error)
+	// ListByPortfolio returns a page of a portfolio's orders, newest first, and the
+	// total number of matching orders. An empty status matches every status.
+	ListByPortfolio(portfolioID uuid.UUID, status model.OrderStatus, filter HistoryFilter) ([]model.Order, int64, error)
 	Update(order *model.Order) error
 	Delete(id uuid.UUID) error
 }
@@ -180,6 +243,27 @@ func (r *orderRepository) GetByPortfolioID(portfolioID uuid.UUID) ([]model.Order
 	return orders, nil
 }
 
+// ListByPortfolio retrieves a filtered page of orders for a portfolio.
+func (r *orderRepository) ListByPortfolio(portfolioID uuid.UUID, status model.OrderStatus, filter HistoryFilter) ([]model.Order, int64, error) {
+	query := r.db.Model(&model.Order{}).Where("portfolio_id = ?", portfolioID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query = filter.apply(query, "created_at")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []model.Order
+	err := filter.paginate(query.Order("created_at DESC")).Find(&orders).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
 // Update updates an existing order.
 func (r *orderRepository) Update(order *model.Order) error {
 	return r.db.Save(order).Error
@@ -195,6 +279,9 @@ type TradeRepository interface {
 	Create(trade *model.Trade) error
 	GetByID(id uuid.UUID) (*model.Trade, error)
 	GetByPortfolioID(portfolioID uuid.UUID) ([]model.Trade, error)
+	// ListByPortfolio returns a page of a portfolio's trades, newest first, and the
+	// total number of matching trades.
+	ListByPortfolio(portfolioID uuid.UUID, filter HistoryFilter) ([]model.Trade, int64, error)
 	GetByOrderID(orderID uuid.UUID) ([]model.Trade, error)
 }
 
@@ -233,6 +320,23 @@ func (r *tradeRepository) GetByPortfolioID(portfolioID uuid.UUID) ([]model.Trade
 	return trades, nil
 }
 
+// ListByPortfolio retrieves a filtered page of trades for a portfolio.
+func (r *tradeRepository) ListByPortfolio(portfolioID uuid.UUID, filter HistoryFilter) ([]model.Trade, int64, error) {
+	query := filter.apply(r.db.Model(&model.Trade{}).Where("portfolio_id = ?", portfolioID), "executed_at")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var trades []model.Trade
+	err := filter.paginate(query.Order("executed_at DESC")).Find(&trades).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return trades, total, nil
+}
+
 // GetByOrderID retrieves all trades for an order.
 func (r *tradeRepository) GetByOrderID(orderID uuid.UUID) ([]model.Trade, error) {
 	var trades []model.Trade
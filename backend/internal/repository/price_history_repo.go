@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PriceHistoryRepository defines the interface for bulk-loading historical
+// stock prices.
+type PriceHistoryRepository interface {
+	// EnsureStock returns the stock with the given symbol, creating it when
+	// it does not exist yet.
+	EnsureStock(symbol string) (*model.Stock, error)
+	// SavePrices stores prices for a stock in a single transaction, skipping
+	// bars whose timestamp is already stored, and returns the number inserted.
+	SavePrices(stockID uuid.UUID, prices []model.StockPrice) (int64, error)
+	// GetPriceHistory returns up to limit of a symbol's most recent bars,
+	// newest first.
+	GetPriceHistory(symbol string, limit int) ([]model.StockPrice, error)
+}
+
+// priceHistoryRepository implements PriceHistoryRepository using GORM.
+type priceHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewPriceHistoryRepository creates a new PriceHistoryRepository instance.
+func NewPriceHistoryRepository(db *gorm.DB) PriceHistoryRepository {
+	return &priceHistoryRepository{db: db}
+}
+
+// EnsureStock finds or creates the stock row for a symbol.
+func (r *priceHistoryRepository) EnsureStock(symbol string) (*model.Stock, error) {
+	symbol = NormalizeSymbol(symbol)
+	stock := model.Stock{Symbol: symbol}
+	err := r.db.Where(model.Stock{Symbol: symbol}).
+		Attrs(model.Stock{Name: symbol}).
+		FirstOrCreate(&stock).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// SavePrices inserts the prices not yet stored for the stock.
+func (r *priceHistoryRepository) SavePrices(stockID uuid.UUID, prices []model.StockPrice) (int64, error) {
+	if len(prices) == 0 {
+		return 0, nil
+	}
+
+	from, to := prices[0].Timestamp, prices[0].Timestamp
+	for _, price := range prices {
+		if price.Timestamp.Before(from) {
+			from = price.Timestamp
+		}
+		if price.Timestamp.After(to) {
+			to = price.Timestamp
+		}
+	}
+
+	var inserted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var stored []time.Time
+		if err := tx.Model(&model.StockPrice{}).
+			Where("stock_id = ? AND timestamp BETWEEN ? AND ?", stockID, from, to).
+			Pluck("timestamp", &stored).Error; err != nil {
+			return err
+		}
+
+		existing := make(map[int64]bool, len(stored))
+		for _, ts := range stored {
+			existing[ts.Unix()] = true
+		}
+
+		missing := make([]model.StockPrice, 0, len(prices))
+		for _, price := range prices {
+			if existing[price.Timestamp.Unix()] {
+				continue
+			}
+			existing[price.Timestamp.Unix()] = true
+			if price.ID == uuid.Nil {
+				price.ID = uuid.New()
+			}
+			price.StockID = stockID
+			missing = append(missing, price)
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+
+		result := tx.CreateInBatches(missing, 500)
+		if result.Error != nil {
+			return result.Error
+		}
+		inserted = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// GetPriceHistory retrieves the latest bars stored for a symbol.
+func (r *priceHistoryRepository) GetPriceHistory(symbol string, limit int) ([]model.StockPrice, error) {
+	var prices []model.StockPrice
+	err := r.db.
+		Joins("JOIN stocks ON stocks.id = stock_prices.stock_id").
+		Where("stocks.symbol = ?", NormalizeSymbol(symbol)).
+		Order("stock_prices.timestamp DESC").
+		Limit(limit).
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ScreenerPresetRepository defines the interface for screener preset data operations.
+type ScreenerPresetRepository interface {
+	Create(preset *model.ScreenerPreset) error
+	GetByID(id uuid.UUID) (*model.ScreenerPreset, error)
+	GetByUserID(userID uuid.UUID) ([]model.ScreenerPreset, error)
+	Update(preset *model.ScreenerPreset) error
+	Delete(id uuid.UUID) error
+	List() ([]model.ScreenerPreset, error)
+}
+
+// screenerPresetRepository implements ScreenerPresetRepository using GORM.
+type screenerPresetRepository struct {
+	db *gorm.DB
+}
+
+// NewScreenerPresetRepository creates a new ScreenerPresetRepository instance.
+func NewScreenerPresetRepository(db *gorm.DB) ScreenerPresetRepository {
+	return &screenerPresetRepository{db: db}
+}
+
+// Create creates a new screener preset in the database.
+func (r *screenerPresetRepository) Create(preset *model.ScreenerPreset) error {
+	return r.db.Create(preset).Error
+}
+
+// GetByID retrieves a screener preset by its ID.
+func (r *screenerPresetRepository) GetByID(id uuid.UUID) (*model.ScreenerPreset, error) {
+	var preset model.ScreenerPreset
+	err := r.db.Where("id = ?", id).First(&preset).Error
+	if err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+// GetByUserID retrieves all screener presets for a user ordered by name.
+func (r *screenerPresetRepository) GetByUserID(userID uuid.UUID) ([]model.ScreenerPreset, error) {
+	var presets []model.ScreenerPreset
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&presets).Error
+	if err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
+
+// Update updates an existing screener preset.
+func (r *screenerPresetRepository) Update(preset *model.ScreenerPreset) error {
+	return r.db.Save(preset).Error
+}
+
+// Delete deletes a screener preset by its ID.
+func (r *screenerPresetRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&model.ScreenerPreset{}, "id = ?", id).Error
+}
+
+// List retrieves all screener presets.
+func (r *screenerPresetRepository) List() ([]model.ScreenerPreset, error) {
+	var presets []model.ScreenerPreset
+	err := r.db.Find(&presets).Error
+	if err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
+
+// InMemoryScreenerPresetRepository is an in-memory implementation of ScreenerPresetRepository for mock mode.
+type InMemoryScreenerPresetRepository struct {
+	mu      sync.RWMutex
+	presets map[uuid.UUID]*model.ScreenerPreset
+}
+
+// NewInMemoryScreenerPresetRepository creates a new in-memory screener preset repository.
+func NewInMemoryScreenerPresetRepository() ScreenerPresetRepository {
+	return &InMemoryScreenerPresetRepository{
+		presets: make(map[uuid.UUID]*model.ScreenerPreset),
+	}
+}
+
+func (r *InMemoryScreenerPresetRepository) Create(preset *model.ScreenerPreset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if preset.ID == uuid.Nil {
+		preset.ID = uuid.New()
+	}
+	r.presets[preset.ID] = preset
+	return nil
+}
+
+func (r *InMemoryScreenerPresetRepository) GetByID(id uuid.UUID) (*model.ScreenerPreset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.presets[id]; ok {
+		return p, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *InMemoryScreenerPresetRepository) GetByUserID(userID uuid.UUID) ([]model.ScreenerPreset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []model.ScreenerPreset
+	for _, p := range r.presets {
+		if p.UserID == userID {
+			result = append(result, *p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func (r *InMemoryScreenerPresetRepository) Update(preset *model.ScreenerPreset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.presets[preset.ID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	r.presets[preset.ID] = preset
+	return nil
+}
+
+func (r *InMemoryScreenerPresetRepository) Delete(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.presets[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.presets, id)
+	return nil
+}
+
+func (r *InMemoryScreenerPresetRepository) List() ([]model.ScreenerPreset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []model.ScreenerPreset
+	for _, p := range r.presets {
+		result = append(result, *p)
+	}
+	return result, nil
+}
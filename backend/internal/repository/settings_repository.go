@@ -31,18 +31,20 @@ func (r *SettingsRepository) GetUserSettings(ctx context.Context, userID uuid.UU
 	if err == gorm.ErrRecordNotFound {
 		// Create default settings if not found
 		settings = model.Settings{
-			UserID:            userID,
-			Currency:          "USD",
-			Language:          "en",
-			Theme:             "dark",
-			InitialBankroll:   1000.0,
-			RiskPerTrade:      2.0,
-			MaxOpenPositions:  5,
-			NotifyEmail:       true,
-			NotifyPush:        true,
-			NotifyValueBets:   true,
-			NotifyAlerts:      true,
-			NotifyNews:        true,
+			UserID:             userID,
+			Currency:           "USD",
+			Language:           "en",
+			Theme:              "dark",
+			InitialBankroll:    1000.0,
+			RiskPerTrade:       2.0,
+			MaxOpenPositions:   5,
+			NotifyEmail:        true,
+			NotifyPush:         true,
+			NotifyValueBets:    true,
+			NotifyAlerts:       true,
+			NotifyNews:         true,
+			BuyMarginOfSafety:  20,
+			SellMarginOfSafety: -10,
 		}
 		if err := r.CreateSettings(ctx, &settings); err != nil {
 			return nil, err
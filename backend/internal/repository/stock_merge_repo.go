@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StockMergeCounts reports how many rows were repointed by a stock merge.
+type StockMergeCounts struct {
+	WatchlistItems int64 `json:"watchlist_items"`
+	Prices         int64 `json:"prices"`
+	News           int64 `json:"news"`
+	FairValues     int64 `json:"fair_values"`
+	StocksDeleted  int64 `json:"stocks_deleted"`
+}
+
+// StockMergeRepository defines the interface for merging duplicate stock rows.
+type StockMergeRepository interface {
+	ListStocks() ([]model.Stock, error)
+	// Merge repoints everything that references the duplicate stocks to the
+	// canonical stock and deletes the duplicates in a single transaction.
+	Merge(canonicalID uuid.UUID, duplicateIDs []uuid.UUID) (*StockMergeCounts, error)
+}
+
+// stockMergeRepository implements StockMergeRepository using GORM.
+type stockMergeRepository struct {
+	db *gorm.DB
+}
+
+// NewStockMergeRepository creates a new StockMergeRepository instance.
+func NewStockMergeRepository(db *gorm.DB) StockMergeRepository {
+	return &stockMergeRepository{db: db}
+}
+
+// ListStocks retrieves all stocks ordered by creation time.
+func (r *stockMergeRepository) ListStocks() ([]model.Stock, error) {
+	var stocks []model.Stock
+	err := r.db.Order("created_at ASC").Find(&stocks).Error
+	if err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// Merge folds the duplicate stocks into the canonical stock.
+func (r *stockMergeRepository) Merge(canonicalID uuid.UUID, duplicateIDs []uuid.UUID) (*StockMergeCounts, error) {
+	counts := &StockMergeCounts{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var canonical model.Stock
+		if err := tx.Where("id = ?", canonicalID).First(&canonical).Error; err != nil {
+			return err
+		}
+
+		// A watchlist that already holds the canonical stock keeps that item
+		// and drops the duplicate's.
+		if err := tx.Where("stock_id IN ? AND watchlist_id IN (?)", duplicateIDs,
+			tx.Model(&model.WatchlistItem{}).Select("watchlist_id").Where("stock_id = ?", canonicalID),
+		).Delete(&model.WatchlistItem{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.WatchlistItem{}).Where("stock_id IN ?", duplicateIDs).Update("stock_id", canonicalID)
+		if result.Error != nil {
+			return result.Error
+		}
+		counts.WatchlistItems = result.RowsAffected
+
+		// Bars are unique per stock and timestamp, so the canonical stock
+		// keeps its own bars and of the duplicates' bars for one timestamp
+		// only the first is kept.
+		if err := tx.Where("stock_id IN ? AND timestamp IN (?)", duplicateIDs,
+			tx.Model(&model.StockPrice{}).Select("timestamp").Where("stock_id = ?", canonicalID),
+		).Delete(&model.StockPrice{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("stock_id IN ? AND EXISTS (?)", duplicateIDs,
+			tx.Table("stock_prices AS kept").Select("1").
+				Where("kept.stock_id IN ? AND kept.timestamp = stock_prices.timestamp AND kept.id < stock_prices.id", duplicateIDs),
+		).Delete(&model.StockPrice{}).Error; err != nil {
+			return err
+		}
+
+		result = tx.Model(&model.StockPrice{}).Where("stock_id IN ?", duplicateIDs).Update("stock_id", canonicalID)
+		if result.Error != nil {
+			return result.Error
+		}
+		counts.Prices = result.RowsAffected
+
+		result = tx.Model(&model.StockNews{}).Where("stock_id IN ?", duplicateIDs).Update("stock_id", canonicalID)
+		if result.Error != nil {
+			return result.Error
+		}
+		counts.News = result.RowsAffected
+
+		result = tx.Model(&model.FairValue{}).Where("stock_id IN ?", duplicateIDs).
+			Updates(map[string]interface{}{"stock_id": canonicalID, "symbol": canonical.Symbol})
+		if result.Error != nil {
+			return result.Error
+		}
+		counts.FairValues = result.RowsAffected
+
+		result = tx.Where("id IN ?", duplicateIDs).Delete(&model.Stock{})
+		if result.Error != nil {
+			return result.Error
+		}
+		counts.StocksDeleted = result.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
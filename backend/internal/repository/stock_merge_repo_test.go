@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingDriver is a database/sql driver that records the statements it
+// executes. Queries return the rows in the rows field; every exec affects
+// one row.
+type recordingDriver struct {
+	mu    sync.Mutex
+	execs []string
+	rows  [][]driver.Value
+	cols  []string
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
+
+type recordingStmt struct {
+	driver *recordingDriver
+	query  string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.driver.mu.Lock()
+	defer s.driver.mu.Unlock()
+	s.driver.execs = append(s.driver.execs, s.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &recordingRows{cols: s.driver.cols, rows: s.driver.rows}, nil
+}
+
+type recordingRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string { return r.cols }
+func (r *recordingRows) Close() error      { return nil }
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var testRecordingDriver = &recordingDriver{}
+
+func init() {
+	sql.Register("repository-recording-test", testRecordingDriver)
+}
+
+func TestStockMergeRepository_MergeDropsOverlappingBars(t *testing.T) {
+	canonicalID := uuid.New()
+	testRecordingDriver.cols = []string{"id", "symbol"}
+	testRecordingDriver.rows = [][]driver.Value{{canonicalID.String(), "AAPL"}}
+
+	sqlDB, err := sql.Open("repository-recording-test", "")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer sqlDB.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open gorm: %v", err)
+	}
+
+	if _, err := NewStockMergeRepository(db).Merge(canonicalID, []uuid.UUID{uuid.New(), uuid.New()}); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	// The duplicates' bars on timestamps the canonical stock already has,
+	// and all but one of theirs per timestamp, must go before the rest are
+	// repointed, or the update breaks idx_stock_prices_stock_timestamp.
+	overlapDelete, dupDelete, update := -1, -1, -1
+	for i, stmt := range testRecordingDriver.execs {
+		switch {
+		case strings.HasPrefix(stmt, `DELETE FROM "stock_prices"`) && strings.Contains(stmt, `timestamp IN (SELECT "timestamp" FROM "stock_prices" WHERE stock_id =`):
+			overlapDelete = i
+		case strings.HasPrefix(stmt, `DELETE FROM "stock_prices"`) && strings.Contains(stmt, "kept.timestamp = stock_prices.timestamp"):
+			dupDelete = i
+		case strings.HasPrefix(stmt, `UPDATE "stock_prices" SET "stock_id"`):
+			update = i
+		}
+	}
+	if overlapDelete < 0 || dupDelete < 0 || update < 0 {
+		t.Fatalf("Expected both bar deletes and the repoint, got %q", testRecordingDriver.execs)
+	}
+	if overlapDelete > update || dupDelete > update {
+		t.Errorf("Expected overlapping bars to be deleted before the repoint, got %q", testRecordingDriver.execs)
+	}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StockPriceRepository defines the interface for stored daily price bars.
+type StockPriceRepository interface {
+	// GetStockBySymbol returns the stock with the given symbol, or
+	// ErrNotFound.
+	GetStockBySymbol(symbol string) (*model.Stock, error)
+	// EnsureStock returns the stock with the given symbol, creating it when
+	// it does not exist yet.
+	EnsureStock(symbol string) (*model.Stock, error)
+	// BulkInsert stores the prices, skipping any whose stock and timestamp
+	// are already stored, and returns the number inserted.
+	BulkInsert(prices []model.StockPrice) (int64, error)
+	// GetRange returns a stock's bars from from inclusive to to exclusive,
+	// oldest first.
+	GetRange(stockID uuid.UUID, from, to time.Time) ([]model.StockPrice, error)
+	// Latest returns a stock's most recent bar, or ErrNotFound.
+	Latest(stockID uuid.UUID) (*model.StockPrice, error)
+	// Upsert stores the price, replacing the values of a stored bar with the
+	// same stock and timestamp.
+	Upsert(price *model.StockPrice) error
+}
+
+// stockPriceRepository implements StockPriceRepository using GORM.
+type stockPriceRepository struct {
+	db *gorm.DB
+}
+
+// NewStockPriceRepository creates a new StockPriceRepository instance.
+func NewStockPriceRepository(db *gorm.DB) StockPriceRepository {
+	return &stockPriceRepository{db: db}
+}
+
+// GetStockBySymbol retrieves a stock by its normalized symbol.
+func (r *stockPriceRepository) GetStockBySymbol(symbol string) (*model.Stock, error) {
+	var stock model.Stock
+	err := r.db.Where("symbol = ?", NormalizeSymbol(symbol)).First(&stock).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// EnsureStock finds or creates the stock row for a symbol.
+func (r *stockPriceRepository) EnsureStock(symbol string) (*model.Stock, error) {
+	return (&priceHistoryRepository{db: r.db}).EnsureStock(symbol)
+}
+
+// BulkInsert inserts the prices in batches. The unique index on stock and
+// timestamp turns repeats into no-ops.
+func (r *stockPriceRepository) BulkInsert(prices []model.StockPrice) (int64, error) {
+	if len(prices) == 0 {
+		return 0, nil
+	}
+
+	rows := make([]model.StockPrice, len(prices))
+	for i, price := range prices {
+		if price.ID == uuid.Nil {
+			price.ID = uuid.New()
+		}
+		rows[i] = price
+	}
+
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "stock_id"}, {Name: "timestamp"}},
+		DoNothing: true,
+	}).CreateInBatches(rows, 500)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// GetRange retrieves a stock's bars within [from, to).
+func (r *stockPriceRepository) GetRange(stockID uuid.UUID, from, to time.Time) ([]model.StockPrice, error) {
+	var prices []model.StockPrice
+	err := r.db.Where("stock_id = ? AND timestamp >= ? AND timestamp < ?", stockID, from, to).
+		Order("timestamp ASC").
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// Latest retrieves a stock's most recent bar.
+func (r *stockPriceRepository) Latest(stockID uuid.UUID) (*model.StockPrice, error) {
+	var price model.StockPrice
+	err := r.db.Where("stock_id = ?", stockID).Order("timestamp DESC").First(&price).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// Upsert inserts the price or updates the bar stored at its timestamp.
+func (r *stockPriceRepository) Upsert(price *model.StockPrice) error {
+	if price.ID == uuid.Nil {
+		price.ID = uuid.New()
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "stock_id"}, {Name: "timestamp"}},
+		DoUpdates: clause.AssignmentColumns([]string{"open", "high", "low", "close", "volume"}),
+	}).Create(price).Error
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
@@ -9,14 +11,16 @@ import (
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
 )
 
 // StockMockData represents the structure of the mock stocks JSON file.
+// Records are decoded one at a time into StockJSON and StockPriceJSON so that
+// a malformed record can be skipped without rejecting the file.
 type StockMockData struct {
-	Stocks []StockJSON      `json:"stocks"`
-	Prices []StockPriceJSON `json:"prices"`
+	Stocks []json.RawMessage `json:"stocks"`
+	Prices []json.RawMessage `json:"prices"`
 }
 
 // StockJSON represents a stock in the mock JSON format.
@@ -40,8 +44,42 @@ type StockPriceJSON struct {
 
 // StockPriceHistoryData represents the structure of the mock price history JSON file.
 type StockPriceHistoryData struct {
-	Symbol string           `json:"symbol"`
-	Prices []StockPriceJSON `json:"prices"`
+	Symbol string            `json:"symbol"`
+	Prices []json.RawMessage `json:"prices"`
+}
+
+// exchangeCodes lists the exchange suffixes and prefixes stripped by NormalizeSymbol.
+var exchangeCodes = map[string]bool{
+	"US":     true,
+	"NYSE":   true,
+	"NYQ":    true,
+	"NASDAQ": true,
+	"NMS":    true,
+	"AMEX":   true,
+	"ARCA":   true,
+	"BATS":   true,
+	"OTC":    true,
+}
+
+// NormalizeSymbol returns the canonical form of a ticker symbol. It upper-cases
+// the symbol, strips exchange qualifiers such as "AAPL.US", "AAPL:NASDAQ" and
+// "NASDAQ:AAPL", and writes share classes with a dot ("BRK-B" and "BRK/B" become "BRK.B").
+func NormalizeSymbol(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	if prefix, rest, ok := strings.Cut(symbol, ":"); ok {
+		switch {
+		case exchangeCodes[prefix]:
+			symbol = rest
+		case exchangeCodes[rest]:
+			symbol = prefix
+		}
+	}
+	if i := strings.LastIndex(symbol, "."); i > 0 && exchangeCodes[symbol[i+1:]] {
+		symbol = symbol[:i]
+	}
+
+	return strings.NewReplacer("-", ".", "/", ".").Replace(symbol)
 }
 
 // StockRepository defines the interface for stock data operations.
@@ -60,7 +98,10 @@ type mockStockRepository struct {
 	mu           sync.RWMutex
 }
 
-// NewMockStockRepository creates a new mock stock repository from a JSON file.
+// NewMockStockRepository creates a new mock stock repository from a JSON file
+// and any prices_SYMBOL.json history files next to it. Invalid records are
+// skipped and reported in a *MockDataError returned with the repository; any
+// other error means stocks.json could not be loaded at all.
 func NewMockStockRepository(filePath string) (StockRepository, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
@@ -77,13 +118,26 @@ func NewMockStockRepository(filePath string) (StockRepository, error) {
 		prices:       make(map[string]model.StockPrice),
 		priceHistory: make(map[string][]model.StockPrice),
 	}
+	problems := &MockDataError{File: filePath}
 
 	// Parse stocks
-	for _, s := range mockData.Stocks {
-		stockID := uuid.New()
-		repo.stocks[s.Symbol] = model.Stock{
-			ID:        stockID,
-			Symbol:    s.Symbol,
+	for i, raw := range mockData.Stocks {
+		var s StockJSON
+		if field, err := decodeMockRecord(raw, &s); err != nil {
+			problems.add("stocks", i, mockRecordID(raw), field, err)
+			continue
+		}
+		symbol := NormalizeSymbol(s.Symbol)
+		if symbol == "" {
+			problems.add("stocks", i, "", "symbol", errors.New("is required"))
+			continue
+		}
+		if _, exists := repo.stocks[symbol]; exists {
+			continue
+		}
+		repo.stocks[symbol] = model.Stock{
+			ID:        uuid.New(),
+			Symbol:    symbol,
 			Name:      s.Name,
 			MarketCap: s.MarketCap,
 			Sector:    s.Sector,
@@ -91,9 +145,20 @@ func NewMockStockRepository(filePath string) (StockRepository, error) {
 	}
 
 	// Parse prices from stocks.json
-	for _, p := range mockData.Prices {
-		stock, ok := repo.stocks[p.Symbol]
+	for i, raw := range mockData.Prices {
+		var p StockPriceJSON
+		if field, err := decodeMockRecord(raw, &p); err != nil {
+			problems.add("prices", i, mockRecordID(raw), field, err)
+			continue
+		}
+		symbol := NormalizeSymbol(p.Symbol)
+		stock, ok := repo.stocks[symbol]
 		if !ok {
+			problems.add("prices", i, p.Symbol, "symbol", fmt.Errorf("unknown stock %q", p.Symbol))
+			continue
+		}
+		if p.Close <= 0 {
+			problems.add("prices", i, p.Symbol, "close", errors.New("must be positive"))
 			continue
 		}
 		price := model.StockPrice{
@@ -106,8 +171,8 @@ func NewMockStockRepository(filePath string) (StockRepository, error) {
 			Close:     p.Close,
 			Volume:    p.Volume,
 		}
-		repo.prices[p.Symbol] = price
-		repo.priceHistory[p.Symbol] = []model.StockPrice{price}
+		repo.prices[symbol] = price
+		repo.priceHistory[symbol] = []model.StockPrice{price}
 	}
 
 	// Try to load additional price history files (prices_SYMBOL.json)
@@ -115,15 +180,16 @@ func NewMockStockRepository(filePath string) (StockRepository, error) {
 	files, err := filepath.Glob(filepath.Join(dir, "prices_*.json"))
 	if err == nil {
 		for _, priceFile := range files {
-			_ = repo.loadPriceHistoryFile(priceFile)
+			_ = repo.loadPriceHistoryFile(priceFile, problems)
 		}
 	}
 
-	return repo, nil
+	return repo, problems.errOrNil()
 }
 
-// loadPriceHistoryFile loads price history from a prices_SYMBOL.json file.
-func (r *mockStockRepository) loadPriceHistoryFile(filePath string) error {
+// loadPriceHistoryFile loads price history from a prices_SYMBOL.json file,
+// recording invalid price records in problems.
+func (r *mockStockRepository) loadPriceHistoryFile(filePath string, problems *MockDataError) error {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return err
@@ -134,18 +200,28 @@ func (r *mockStockRepository) loadPriceHistoryFile(filePath string) error {
 		return err
 	}
 
-	stock, ok := r.stocks[historyData.Symbol]
+	symbol := NormalizeSymbol(historyData.Symbol)
+	stock, ok := r.stocks[symbol]
 	if !ok {
 		return ErrNotFound
 	}
 
+	section := filepath.Base(filePath) + " prices"
 	prices := make([]model.StockPrice, 0, len(historyData.Prices))
-	for _, p := range historyData.Prices {
+	for i, raw := range historyData.Prices {
+		var p StockPriceJSON
+		if field, err := decodeMockRecord(raw, &p); err != nil {
+			problems.add(section, i, "", field, err)
+			continue
+		}
 		ts := time.Now()
 		if p.Timestamp != "" {
-			if parsed, err := time.Parse(time.RFC3339, p.Timestamp); err == nil {
-				ts = parsed
+			parsed, err := time.Parse(time.RFC3339, p.Timestamp)
+			if err != nil {
+				problems.add(section, i, "", "timestamp", errors.New("must be an RFC3339 timestamp"))
+				continue
 			}
+			ts = parsed
 		}
 		prices = append(prices, model.StockPrice{
 			ID:        uuid.New(),
@@ -165,9 +241,9 @@ func (r *mockStockRepository) loadPriceHistoryFile(filePath string) error {
 	})
 
 	r.mu.Lock()
-	r.priceHistory[historyData.Symbol] = prices
+	r.priceHistory[symbol] = prices
 	if len(prices) > 0 {
-		r.prices[historyData.Symbol] = prices[0]
+		r.prices[symbol] = prices[0]
 	}
 	r.mu.Unlock()
 
@@ -191,8 +267,7 @@ func (r *mockStockRepository) GetBySymbol(symbol string) (*model.Stock, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Normalize symbol to uppercase
-	symbol = strings.ToUpper(symbol)
+	symbol = NormalizeSymbol(symbol)
 
 	stock, ok := r.stocks[symbol]
 	if !ok {
@@ -206,8 +281,7 @@ func (r *mockStockRepository) GetLatestPrice(symbol string) (*model.StockPrice,
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Normalize symbol to uppercase
-	symbol = strings.ToUpper(symbol)
+	symbol = NormalizeSymbol(symbol)
 
 	price, ok := r.prices[symbol]
 	if !ok {
@@ -221,8 +295,7 @@ func (r *mockStockRepository) GetPriceHistory(symbol string, limit int) ([]model
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Normalize symbol to uppercase
-	symbol = strings.ToUpper(symbol)
+	symbol = NormalizeSymbol(symbol)
 
 	history, ok := r.priceHistory[symbol]
 	if !ok {
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -142,3 +143,99 @@ func TestNewMockStockRepository_InvalidJSON(t *testing.T) {
 		t.Error("Expected error for invalid JSON")
 	}
 }
+
+func TestNewMockStockRepository_SkipsInvalidRecords(t *testing.T) {
+	tmpDir := t.TempDir()
+	stocksJSON := `{
+		"stocks": [
+			{ "symbol": "AAPL", "name": "Apple Inc.", "market_cap": 2950000000000 },
+			{ "symbol": "MSFT", "name": "Microsoft Corporation", "market_cap": "2.78T" },
+			{ "name": "No Symbol Inc." }
+		],
+		"prices": [
+			{ "symbol": "AAPL", "open": 188.50, "high": 190.25, "low": 187.80, "close": 189.95, "volume": 54320000 },
+			{ "symbol": "TSLA", "close": 250.10 }
+		]
+	}`
+	stocksPath := filepath.Join(tmpDir, "stocks.json")
+	if err := os.WriteFile(stocksPath, []byte(stocksJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	historyJSON := `{
+		"symbol": "AAPL",
+		"prices": [
+			{ "timestamp": "2024-01-02T16:00:00Z", "open": 187.15, "high": 188.44, "low": 183.89, "close": 185.64, "volume": 82488700 },
+			{ "timestamp": "yesterday", "close": 184.25 },
+			{ "timestamp": "2024-01-04T16:00:00Z", "close": "181.91" }
+		]
+	}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "prices_AAPL.json"), []byte(historyJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	repo, err := NewMockStockRepository(stocksPath)
+	var dataErr *MockDataError
+	if !errors.As(err, &dataErr) {
+		t.Fatalf("Expected *MockDataError, got %v", err)
+	}
+	if repo == nil {
+		t.Fatal("Expected repository with the valid records")
+	}
+
+	want := []struct{ section, field string }{
+		{"stocks", "market_cap"},
+		{"stocks", "symbol"},
+		{"prices", "symbol"},
+		{"prices_AAPL.json prices", "timestamp"},
+		{"prices_AAPL.json prices", "close"},
+	}
+	if len(dataErr.Records) != len(want) {
+		t.Fatalf("Expected %d skipped records, got %d: %v", len(want), len(dataErr.Records), dataErr.Records)
+	}
+	for i, w := range want {
+		if got := dataErr.Records[i]; got.Section != w.section || got.Field != w.field {
+			t.Errorf("Record %d: expected %s field %s, got %v", i, w.section, w.field, got)
+		}
+	}
+	if dataErr.Records[0].ID != "MSFT" {
+		t.Errorf("Expected skipped stock to be identified as MSFT, got %q", dataErr.Records[0].ID)
+	}
+
+	stocks, _ := repo.GetAll()
+	if len(stocks) != 1 {
+		t.Errorf("Expected 1 valid stock, got %d", len(stocks))
+	}
+	history, err := repo.GetPriceHistory("AAPL", 0)
+	if err != nil {
+		t.Fatalf("Failed to get price history: %v", err)
+	}
+	if len(history) != 1 || history[0].Close != 185.64 {
+		t.Errorf("Expected only the valid history bar, got %+v", history)
+	}
+}
+
+func TestNormalizeSymbol(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "aapl", want: "AAPL"},
+		{input: " msft ", want: "MSFT"},
+		{input: "AAPL.US", want: "AAPL"},
+		{input: "aapl.nasdaq", want: "AAPL"},
+		{input: "NASDAQ:AAPL", want: "AAPL"},
+		{input: "AAPL:NASDAQ", want: "AAPL"},
+		{input: "BRK-B", want: "BRK.B"},
+		{input: "brk/b", want: "BRK.B"},
+		{input: "BRK.B.US", want: "BRK.B"},
+		{input: "PTT.BK", want: "PTT.BK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeSymbol(tt.input); got != tt.want {
+				t.Errorf("NormalizeSymbol(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// TrackedSymbolRepository lists the stock symbols users follow.
+type TrackedSymbolRepository interface {
+	// TrackedSymbols returns the normalized symbols of stocks on any
+	// watchlist or with an active price or volume alert, sorted.
+	TrackedSymbols(ctx context.Context) ([]string, error)
+}
+
+// trackedSymbolRepository implements TrackedSymbolRepository using GORM.
+type trackedSymbolRepository struct {
+	db *gorm.DB
+}
+
+// NewTrackedSymbolRepository creates a new TrackedSymbolRepository instance.
+func NewTrackedSymbolRepository(db *gorm.DB) TrackedSymbolRepository {
+	return &trackedSymbolRepository{db: db}
+}
+
+// TrackedSymbols merges watchlist and alert symbols.
+func (r *trackedSymbolRepository) TrackedSymbols(ctx context.Context) ([]string, error) {
+	var watched []string
+	if err := r.db.WithContext(ctx).Model(&model.WatchlistItem{}).
+		Joins("JOIN stocks ON stocks.id = watchlist_items.stock_id").
+		Distinct().
+		Pluck("stocks.symbol", &watched).Error; err != nil {
+		return nil, err
+	}
+
+	var alerted []string
+	if err := r.db.WithContext(ctx).Model(&model.Alert{}).
+		Where("active = ? AND type IN ?", true, []model.AlertType{model.AlertTypeStockPrice, model.AlertTypeStockVolume}).
+		Distinct().
+		Pluck("symbol", &alerted).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(watched)+len(alerted))
+	symbols := make([]string, 0, len(watched)+len(alerted))
+	for _, symbol := range append(watched, alerted...) {
+		symbol = NormalizeSymbol(symbol)
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
@@ -4,10 +4,9 @@ import (
 	"context"
 	"time"
 
+	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"super-dashboard/backend/internal/model"
 )
 
 // ValueBetRepository handles database operations for value bets.
@@ -25,6 +24,42 @@ func (r *ValueBetRepository) CreateValueBet(ctx context.Context, vb *model.Value
 	return r.db.WithContext(ctx).Create(vb).Error
 }
 
+// GetMatch retrieves a match with its teams.
+func (r *ValueBetRepository) GetMatch(ctx context.Context, matchID uuid.UUID) (*model.Match, error) {
+	var match model.Match
+	err := r.db.WithContext(ctx).
+		Preload("HomeTeam").
+		Preload("AwayTeam").
+		First(&match, "id = ?", matchID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// GetMatchOdds retrieves every odds snapshot of a match, newest first.
+func (r *ValueBetRepository) GetMatchOdds(ctx context.Context, matchID uuid.UUID) ([]model.Odds, error) {
+	var odds []model.Odds
+	err := r.db.WithContext(ctx).
+		Where("match_id = ?", matchID).
+		Order("created_at DESC").
+		Find(&odds).Error
+	return odds, err
+}
+
+// ReplaceMatchValueBets deletes a match's value bets and stores bets in their place.
+func (r *ValueBetRepository) ReplaceMatchValueBets(ctx context.Context, matchID uuid.UUID, bets []model.ValueBet) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("match_id = ?", matchID).Delete(&model.ValueBet{}).Error; err != nil {
+			return err
+		}
+		if len(bets) == 0 {
+			return nil
+		}
+		return tx.Omit("Match").Create(&bets).Error
+	})
+}
+
 // GetActiveValueBets retrieves active value bets above a threshold.
 func (r *ValueBetRepository) GetActiveValueBets(ctx context.Context, threshold float64) ([]model.ValueBet, error) {
 	var valueBets []model.ValueBet
@@ -145,9 +180,9 @@ func (r *ValueBetRepository) GetValueBetStatistics(ctx context.Context, period s
 	}
 
 	var stats struct {
-		TotalValueBets   int
-		AverageValue     float64
-		MaxValue         float64
+		TotalValueBets    int
+		AverageValue      float64
+		MaxValue          float64
 		AverageConfidence float64
 	}
 
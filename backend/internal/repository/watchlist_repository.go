@@ -1,148 +1,90 @@
 package repository
 
 import (
-	"context"
-	"time"
-
+	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"super-dashboard/backend/internal/model"
 )
 
-// WatchlistRepository handles database operations for watchlists.
-type WatchlistRepository struct {
-	db *gorm.DB
+// WatchlistRepository defines the interface for watchlist data operations.
+type WatchlistRepository interface {
+	Create(watchlist *model.Watchlist) error
+	// GetByID retrieves a watchlist with its items and their stocks.
+	GetByID(id uuid.UUID) (*model.Watchlist, error)
+	// GetByUserID lists a user's watchlists with their items, newest first.
+	GetByUserID(userID uuid.UUID) ([]model.Watchlist, error)
+	// Delete deletes a watchlist and its items.
+	Delete(id uuid.UUID) error
+	GetStock(id uuid.UUID) (*model.Stock, error)
+	AddItem(item *model.WatchlistItem) error
+	RemoveItem(watchlistID, stockID uuid.UUID) error
 }
 
-// NewWatchlistRepository creates a new WatchlistRepository.
-func NewWatchlistRepository(db *gorm.DB) *WatchlistRepository {
-	return &WatchlistRepository{db: db}
+// watchlistRepository implements WatchlistRepository using GORM.
+type watchlistRepository struct {
+	db *gorm.DB
 }
 
-// CreateWatchlist creates a new watchlist.
-func (r *WatchlistRepository) CreateWatchlist(ctx context.Context, wl *model.Watchlist) error {
-	return r.db.WithContext(ctx).Create(wl).Error
+// NewWatchlistRepository creates a new WatchlistRepository instance.
+func NewWatchlistRepository(db *gorm.DB) WatchlistRepository {
+	return &watchlistRepository{db: db}
 }
 
-// GetUserWatchlists retrieves all watchlists for a user.
-func (r *WatchlistRepository) GetUserWatchlists(ctx context.Context, userID uuid.UUID) ([]model.Watchlist, error) {
-	var watchlists []model.Watchlist
-	err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
-		Preload("Items").
-		Preload("Items.Stock").
-		Order("created_at DESC").
-		Find(&watchlists).Error
-	return watchlists, err
+// Create creates a new watchlist in the database.
+func (r *watchlistRepository) Create(watchlist *model.Watchlist) error {
+	return r.db.Omit("User").Create(watchlist).Error
 }
 
-// GetWatchlistByID retrieves a watchlist by ID.
-func (r *WatchlistRepository) GetWatchlistByID(ctx context.Context, id uuid.UUID) (*model.Watchlist, error) {
+// GetByID retrieves a watchlist by its ID.
+func (r *watchlistRepository) GetByID(id uuid.UUID) (*model.Watchlist, error) {
 	var watchlist model.Watchlist
-	err := r.db.WithContext(ctx).
-		Preload("Items").
-		Preload("Items.Stock").
-		First(&watchlist, id).Error
+	err := r.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("added_at ASC")
+	}).Preload("Items.Stock").Where("id = ?", id).First(&watchlist).Error
 	if err != nil {
 		return nil, err
 	}
 	return &watchlist, nil
 }
 
-// UpdateWatchlist updates a watchlist.
-func (r *WatchlistRepository) UpdateWatchlist(ctx context.Context, wl *model.Watchlist) error {
-	return r.db.WithContext(ctx).Save(wl).Error
+// GetByUserID retrieves a user's watchlists.
+func (r *watchlistRepository) GetByUserID(userID uuid.UUID) ([]model.Watchlist, error) {
+	var watchlists []model.Watchlist
+	err := r.db.Preload("Items").Preload("Items.Stock").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&watchlists).Error
+	if err != nil {
+		return nil, err
+	}
+	return watchlists, nil
 }
 
-// DeleteWatchlist deletes a watchlist.
-func (r *WatchlistRepository) DeleteWatchlist(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Delete watchlist items first
+// Delete deletes a watchlist and its items in a single transaction.
+func (r *watchlistRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where("watchlist_id = ?", id).Delete(&model.WatchlistItem{}).Error; err != nil {
 			return err
 		}
-		// Delete watchlist
-		return tx.Delete(&model.Watchlist{}, id).Error
+		return tx.Delete(&model.Watchlist{}, "id = ?", id).Error
 	})
 }
 
-// AddStockToWatchlist adds a stock to a watchlist.
-func (r *WatchlistRepository) AddStockToWatchlist(ctx context.Context, wlID, stockID uuid.UUID) error {
-	// Check if already exists
-	var count int64
-	r.db.WithContext(ctx).
-		Model(&model.WatchlistItem{}).
-		Where("watchlist_id = ? AND stock_id = ?", wlID, stockID).
-		Count(&count)
-
-	if count > 0 {
-		return nil // Already exists
-	}
-
-	item := &model.WatchlistItem{
-		WatchlistID: wlID,
-		StockID:     stockID,
-		AddedAt:     time.Now(),
-	}
-	return r.db.WithContext(ctx).Create(item).Error
-}
-
-// RemoveStockFromWatchlist removes a stock from a watchlist.
-func (r *WatchlistRepository) RemoveStockFromWatchlist(ctx context.Context, wlID, stockID uuid.UUID) error {
-	return r.db.WithContext(ctx).
-		Where("watchlist_id = ? AND stock_id = ?", wlID, stockID).
-		Delete(&model.WatchlistItem{}).Error
-}
-
-// GetWatchlistStocks retrieves all stocks in a watchlist.
-func (r *WatchlistRepository) GetWatchlistStocks(ctx context.Context, wlID uuid.UUID) ([]model.Stock, error) {
-	var stocks []model.Stock
-	err := r.db.WithContext(ctx).
-		Joins("JOIN watchlist_items ON watchlist_items.stock_id = stocks.id").
-		Where("watchlist_items.watchlist_id = ?", wlID).
-		Order("watchlist_items.added_at DESC").
-		Find(&stocks).Error
-	return stocks, err
-}
-
-// GetWatchlistItem retrieves a specific watchlist item.
-func (r *WatchlistRepository) GetWatchlistItem(ctx context.Context, wlID, stockID uuid.UUID) (*model.WatchlistItem, error) {
-	var item model.WatchlistItem
-	err := r.db.WithContext(ctx).
-		Where("watchlist_id = ? AND stock_id = ?", wlID, stockID).
-		Preload("Stock").
-		First(&item).Error
-	if err != nil {
+// GetStock retrieves a stock by its ID.
+func (r *watchlistRepository) GetStock(id uuid.UUID) (*model.Stock, error) {
+	var stock model.Stock
+	if err := r.db.Where("id = ?", id).First(&stock).Error; err != nil {
 		return nil, err
 	}
-	return &item, nil
+	return &stock, nil
 }
 
-// UpdateWatchlistItemNotes updates notes for a watchlist item.
-func (r *WatchlistRepository) UpdateWatchlistItemNotes(ctx context.Context, wlID, stockID uuid.UUID, notes string) error {
-	return r.db.WithContext(ctx).
-		Model(&model.WatchlistItem{}).
-		Where("watchlist_id = ? AND stock_id = ?", wlID, stockID).
-		Update("notes", notes).Error
-}
-
-// GetStockWatchlists retrieves all watchlists containing a specific stock.
-func (r *WatchlistRepository) GetStockWatchlists(ctx context.Context, userID, stockID uuid.UUID) ([]model.Watchlist, error) {
-	var watchlists []model.Watchlist
-	err := r.db.WithContext(ctx).
-		Joins("JOIN watchlist_items ON watchlist_items.watchlist_id = watchlists.id").
-		Where("watchlists.user_id = ? AND watchlist_items.stock_id = ?", userID, stockID).
-		Find(&watchlists).Error
-	return watchlists, err
+// AddItem adds a stock to a watchlist.
+func (r *watchlistRepository) AddItem(item *model.WatchlistItem) error {
+	return r.db.Omit("Watchlist", "Stock").Create(item).Error
 }
 
-// CountWatchlistItems counts the number of stocks in a watchlist.
-func (r *WatchlistRepository) CountWatchlistItems(ctx context.Context, wlID uuid.UUID) (int64, error) {
-	var count int64
-	err := r.db.WithContext(ctx).
-		Model(&model.WatchlistItem{}).
-		Where("watchlist_id = ?", wlID).
-		Count(&count).Error
-	return count, err
+// RemoveItem removes a stock from a watchlist.
+func (r *watchlistRepository) RemoveItem(watchlistID, stockID uuid.UUID) error {
+	return r.db.Where("watchlist_id = ? AND stock_id = ?", watchlistID, stockID).Delete(&model.WatchlistItem{}).Error
 }
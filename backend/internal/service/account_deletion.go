@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+// AccountDeletionMode controls what happens to a user row when the account is
+// deleted.
+type AccountDeletionMode string
+
+const (
+	// AccountDeletionModeDelete removes the user and everything they own.
+	AccountDeletionModeDelete AccountDeletionMode = "delete"
+	// AccountDeletionModeAnonymize scrubs the user's personal data but keeps
+	// the user row with their portfolios, bets and bankroll history.
+	AccountDeletionModeAnonymize AccountDeletionMode = "anonymize"
+)
+
+// StepUpMaxAge is how recently a user without a password must have signed in
+// to confirm a sensitive action.
+const StepUpMaxAge = 5 * time.Minute
+
+// AccountDeletion holds the step-up credentials for deleting an account.
+type AccountDeletion struct {
+	// Password is required for accounts that have one.
+	Password string
+	// Code is a TOTP or backup code, required when 2FA is enabled.
+	Code string
+	// AuthenticatedAt is when the caller's access token was issued. Accounts
+	// without a password (OAuth-only) must have signed in within StepUpMaxAge.
+	AuthenticatedAt time.Time
+	IPAddress       string
+	UserAgent       string
+}
+
+// DeleteAccount deletes or anonymizes a user after re-verifying their
+// identity. All sessions and refresh tokens are revoked, OAuth accounts are
+// unlinked and the user's records are removed according to the configured
+// AccountDeletionMode. The audit event is written before the user is removed.
+func (s *extendedAuthService) DeleteAccount(userID uuid.UUID, req AccountDeletion) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.verifyStepUp(user, req); err != nil {
+		return err
+	}
+
+	// The user ID is repeated in the details because deleted users' audit
+	// logs are detached from the account.
+	details, err := json.Marshal(map[string]string{
+		"user_id": userID.String(),
+		"mode":    string(s.deletionMode),
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.LogAuditEvent(&userID, model.AuditActionAccountDelete, req.IPAddress, req.UserAgent, string(details), true); err != nil {
+		return fmt.Errorf("failed to record account deletion: %w", err)
+	}
+
+	if err := s.revokeUserTokens(userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	if s.oauthRepo != nil {
+		accounts, err := s.oauthRepo.GetByUserID(userID)
+		if err != nil {
+			return fmt.Errorf("failed to unlink OAuth accounts: %w", err)
+		}
+		for _, account := range accounts {
+			if err := s.oauthRepo.DeleteByUserIDAndProvider(userID, account.Provider); err != nil {
+				return fmt.Errorf("failed to unlink OAuth accounts: %w", err)
+			}
+		}
+	}
+
+	if s.twoFARepo != nil {
+		if err := s.twoFARepo.Delete(userID); err != nil {
+			return fmt.Errorf("failed to remove 2FA: %w", err)
+		}
+	}
+
+	if s.deletionMode == AccountDeletionModeAnonymize {
+		if s.userDataRepo != nil {
+			if err := s.userDataRepo.DeletePersonalData(userID); err != nil {
+				return fmt.Errorf("failed to delete personal data: %w", err)
+			}
+		}
+
+		user.Email = fmt.Sprintf("deleted-%s@deleted.invalid", user.ID)
+		user.Name = ""
+		user.PasswordHash = ""
+		user.TwoFAEnabled = false
+		return s.userRepo.Update(user)
+	}
+
+	if s.userDataRepo != nil {
+		if err := s.userDataRepo.DeleteUserData(userID); err != nil {
+			return fmt.Errorf("failed to delete user data: %w", err)
+		}
+	}
+	return s.userRepo.Delete(userID)
+}
+
+// verifyStepUp re-checks the user's credentials before a sensitive action.
+func (s *extendedAuthService) verifyStepUp(user *model.User, req AccountDeletion) error {
+	if user.PasswordHash != "" {
+		if req.Password == "" {
+			return ErrReauthRequired
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+			_ = s.LogAuditEvent(&user.ID, model.AuditActionFailedLogin, req.IPAddress, req.UserAgent, "account deletion: invalid password", false)
+			return ErrInvalidCredentials
+		}
+	} else if req.AuthenticatedAt.IsZero() || time.Since(req.AuthenticatedAt) > StepUpMaxAge {
+		return ErrReauthRequired
+	}
+
+	if !user.TwoFAEnabled {
+		return nil
+	}
+	if req.Code == "" {
+		return Err2FARequired
+	}
+	if s.twoFARepo == nil {
+		return Err2FAInvalidCode
+	}
+
+	twoFA, err := s.twoFARepo.GetByUserID(user.ID)
+	if err != nil {
+		return Err2FAInvalidCode
+	}
+	if s.validateTOTP(req.Code, twoFA.Secret) {
+		return nil
+	}
+	valid, err := s.checkBackupCode(twoFA, req.Code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		_ = s.LogAuditEvent(&user.ID, model.AuditActionFailed2FAAttempt, req.IPAddress, req.UserAgent, "account deletion attempt failed", false)
+		return Err2FAInvalidCode
+	}
+	return nil
+}
+
+// revokeUserTokens removes the user's refresh tokens from the token store and
+// deletes their sessions. Every sign-in creates a session, and refreshing
+// requires a live one, so no refresh token issued before this call works
+// after it.
+func (s *extendedAuthService) revokeUserTokens(userID uuid.UUID) error {
+	if s.sessionRepo == nil {
+		return nil
+	}
+
+	sessions, err := s.sessionRepo.GetByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		s.deleteStoredRefreshToken(session.RefreshToken)
+	}
+
+	return s.sessionRepo.DeleteByUserID(userID)
+}
+
+// deleteStoredRefreshToken removes a session's refresh token from the token
+// store so it can no longer be refreshed. Invalid tokens are ignored.
+func (s *extendedAuthService) deleteStoredRefreshToken(refreshToken string) {
+	if s.tokenStore == nil {
+		return
+	}
+
+	claims, err := s.ValidateToken(refreshToken)
+	if err != nil {
+		return
+	}
+	if jti, ok := (*claims)["jti"].(string); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = s.tokenStore.DeleteRefreshToken(ctx, jti)
+	}
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+type mockTokenStore struct {
+	tokens        map[string]string
+	verification  map[string]mockStoredToken
+	passwordReset map[string]mockStoredToken
+	failedLogins  map[string]mockFailedLogins
+	oauthStates   map[string]mockStoredToken
+}
+
+// mockStoredToken is a token value with the time it expires, so tests can
+// expire tokens by moving expiresAt into the past.
+type mockStoredToken struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// mockFailedLogins is a failed login counter with the time it expires.
+type mockFailedLogins struct {
+	count     int64
+	expiresAt time.Time
+}
+
+func newMockTokenStore() *mockTokenStore {
+	return &mockTokenStore{
+		tokens:        make(map[string]string),
+		verification:  make(map[string]mockStoredToken),
+		passwordReset: make(map[string]mockStoredToken),
+		failedLogins:  make(map[string]mockFailedLogins),
+		oauthStates:   make(map[string]mockStoredToken),
+	}
+}
+
+func (m *mockTokenStore) SetRefreshToken(ctx context.Context, userID, tokenID string, expiration time.Duration) error {
+	m.tokens[tokenID] = userID
+	return nil
+}
+
+func (m *mockTokenStore) GetRefreshToken(ctx context.Context, tokenID string) (string, error) {
+	userID, ok := m.tokens[tokenID]
+	if !ok {
+		return "", errors.New("token not found")
+	}
+	return userID, nil
+}
+
+func (m *mockTokenStore) DeleteRefreshToken(ctx context.Context, tokenID string) error {
+	delete(m.tokens, tokenID)
+	return nil
+}
+
+func (m *mockTokenStore) SetEmailVerificationToken(ctx context.Context, userID, token string, expiration time.Duration) error {
+	m.verification[token] = mockStoredToken{userID: userID, expiresAt: time.Now().Add(expiration)}
+	return nil
+}
+
+func (m *mockTokenStore) GetEmailVerificationToken(ctx context.Context, token string) (string, error) {
+	stored, ok := m.verification[token]
+	if !ok || !time.Now().Before(stored.expiresAt) {
+		return "", errors.New("token not found")
+	}
+	return stored.userID, nil
+}
+
+func (m *mockTokenStore) DeleteEmailVerificationToken(ctx context.Context, token string) error {
+	delete(m.verification, token)
+	return nil
+}
+
+func (m *mockTokenStore) SetPasswordResetToken(ctx context.Context, userID, token string, expiration time.Duration) error {
+	m.passwordReset[token] = mockStoredToken{userID: userID, expiresAt: time.Now().Add(expiration)}
+	return nil
+}
+
+func (m *mockTokenStore) ConsumePasswordResetToken(ctx context.Context, token string) (string, error) {
+	stored, ok := m.passwordReset[token]
+	delete(m.passwordReset, token)
+	if !ok || !time.Now().Before(stored.expiresAt) {
+		return "", errors.New("token not found")
+	}
+	return stored.userID, nil
+}
+
+func (m *mockTokenStore) SetOAuthState(ctx context.Context, state, provider string, expiration time.Duration) error {
+	m.oauthStates[state] = mockStoredToken{userID: provider, expiresAt: time.Now().Add(expiration)}
+	return nil
+}
+
+func (m *mockTokenStore) GetOAuthState(ctx context.Context, state string) (string, error) {
+	stored, ok := m.oauthStates[state]
+	if !ok || !time.Now().Before(stored.expiresAt) {
+		return "", errors.New("state not found")
+	}
+	return stored.userID, nil
+}
+
+func (m *mockTokenStore) DeleteOAuthState(ctx context.Context, state string) error {
+	delete(m.oauthStates, state)
+	return nil
+}
+
+func (m *mockTokenStore) IncrementFailedLogins(ctx context.Context, key string, window time.Duration) (int64, error) {
+	count, _ := m.GetFailedLogins(ctx, key)
+	m.failedLogins[key] = mockFailedLogins{count: count + 1, expiresAt: time.Now().Add(window)}
+	return count + 1, nil
+}
+
+func (m *mockTokenStore) GetFailedLogins(ctx context.Context, key string) (int64, error) {
+	stored, ok := m.failedLogins[key]
+	if !ok || !time.Now().Before(stored.expiresAt) {
+		return 0, nil
+	}
+	return stored.count, nil
+}
+
+func (m *mockTokenStore) ResetFailedLogins(ctx context.Context, key string) error {
+	delete(m.failedLogins, key)
+	return nil
+}
+
+type mockUserDataRepository struct {
+	deleted   []uuid.UUID
+	personal  []uuid.UUID
+	auditRepo *mockAuditLogRepository
+	sawAudit  bool
+}
+
+func (m *mockUserDataRepository) DeleteUserData(userID uuid.UUID) error {
+	m.sawAudit = m.hasDeletionAudit(userID)
+	m.deleted = append(m.deleted, userID)
+	return nil
+}
+
+func (m *mockUserDataRepository) DeletePersonalData(userID uuid.UUID) error {
+	m.sawAudit = m.hasDeletionAudit(userID)
+	m.personal = append(m.personal, userID)
+	return nil
+}
+
+func (m *mockUserDataRepository) hasDeletionAudit(userID uuid.UUID) bool {
+	for _, log := range m.auditRepo.logs {
+		if log.Action == model.AuditActionAccountDelete && log.UserID != nil && *log.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestExtendedAuthService_DeleteAccount(t *testing.T) {
+	userRepo := newMockUserRepository()
+	sessionRepo := newMockSessionRepository()
+	oauthRepo := newMockOAuthAccountRepository()
+	auditRepo := newMockAuditLogRepository()
+	tokenStore := newMockTokenStore()
+	dataRepo := &mockUserDataRepository{auditRepo: auditRepo}
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:     userRepo,
+		SessionRepo:  sessionRepo,
+		OAuthRepo:    oauthRepo,
+		TwoFARepo:    newMockTwoFactorAuthRepository(),
+		AuditLogRepo: auditRepo,
+		UserDataRepo: dataRepo,
+		TokenStore:   tokenStore,
+		JWTSecret:    "test-secret",
+	})
+
+	user, err := authService.Register("delete@example.com", "password123", "Delete User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	_, _, refreshToken, err := authService.CreateSession(user.ID, "Test Browser", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if err := authService.LinkOAuthAccount(user.ID, &OAuthUserInfo{
+		Provider:       model.OAuthProviderGoogle,
+		ProviderUserID: "google-delete",
+		Email:          "delete@example.com",
+	}); err != nil {
+		t.Fatalf("Failed to link OAuth account: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		req     AccountDeletion
+		wantErr error
+	}{
+		{name: "missing password", req: AccountDeletion{}, wantErr: ErrReauthRequired},
+		{name: "wrong password", req: AccountDeletion{Password: "wrongpassword"}, wantErr: ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := authService.DeleteAccount(user.ID, tt.req); err != tt.wantErr {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if err := authService.DeleteAccount(user.ID, AccountDeletion{Password: "password123"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := userRepo.GetByID(user.ID); err == nil {
+		t.Error("Expected user to be deleted")
+	}
+	if len(dataRepo.deleted) != 1 || dataRepo.deleted[0] != user.ID {
+		t.Errorf("Expected user data to be deleted, got %v", dataRepo.deleted)
+	}
+	if !dataRepo.sawAudit {
+		t.Error("Expected audit event to be logged before the user's data was removed")
+	}
+	if sessions, _ := sessionRepo.GetByUserID(user.ID); len(sessions) != 0 {
+		t.Errorf("Expected sessions to be revoked, got %d", len(sessions))
+	}
+	if len(tokenStore.tokens) != 0 {
+		t.Errorf("Expected refresh tokens to be revoked, got %d", len(tokenStore.tokens))
+	}
+	if accounts, _ := oauthRepo.GetByUserID(user.ID); len(accounts) != 0 {
+		t.Errorf("Expected OAuth accounts to be unlinked, got %d", len(accounts))
+	}
+	if _, err := authService.RefreshToken(refreshToken); err != ErrInvalidToken {
+		t.Errorf("Expected ErrInvalidToken on refresh, got %v", err)
+	}
+}
+
+func TestExtendedAuthService_DeleteAccountAnonymize(t *testing.T) {
+	userRepo := newMockUserRepository()
+	auditRepo := newMockAuditLogRepository()
+	dataRepo := &mockUserDataRepository{auditRepo: auditRepo}
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:     userRepo,
+		AuditLogRepo: auditRepo,
+		UserDataRepo: dataRepo,
+		JWTSecret:    "test-secret",
+		DeletionMode: AccountDeletionModeAnonymize,
+	})
+
+	user, err := authService.Register("anon@example.com", "password123", "Anon User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	_, refreshToken, err := authService.Login("anon@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to login: %v", err)
+	}
+
+	if err := authService.DeleteAccount(user.ID, AccountDeletion{Password: "password123"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	anonymized, err := userRepo.GetByID(user.ID)
+	if err != nil {
+		t.Fatalf("Expected anonymized user to remain, got %v", err)
+	}
+	if !strings.HasPrefix(anonymized.Email, "deleted-") || anonymized.Name != "" || anonymized.PasswordHash != "" {
+		t.Errorf("Expected personal data to be scrubbed, got %+v", anonymized)
+	}
+	if len(dataRepo.personal) != 1 || len(dataRepo.deleted) != 0 {
+		t.Errorf("Expected only personal data to be deleted, got personal=%v deleted=%v", dataRepo.personal, dataRepo.deleted)
+	}
+	if !dataRepo.sawAudit {
+		t.Error("Expected audit event to be logged before the user's data was removed")
+	}
+	if _, _, err := authService.Login("anon@example.com", "password123"); err != ErrInvalidCredentials {
+		t.Errorf("Expected ErrInvalidCredentials on login, got %v", err)
+	}
+	if _, err := authService.RefreshToken(refreshToken); err != ErrInvalidToken {
+		t.Errorf("Expected ErrInvalidToken on refresh, got %v", err)
+	}
+}
+
+func TestExtendedAuthService_DeleteAccountOAuthOnly(t *testing.T) {
+	userRepo := newMockUserRepository()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:     userRepo,
+		OAuthRepo:    newMockOAuthAccountRepository(),
+		AuditLogRepo: newMockAuditLogRepository(),
+		JWTSecret:    "test-secret",
+	})
+
+	user, _, _, err := authService.HandleOAuthLogin(&OAuthUserInfo{
+		Provider:       model.OAuthProviderGoogle,
+		ProviderUserID: "google-oauth-only",
+		Email:          "oauth-only@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Failed to login with OAuth: %v", err)
+	}
+
+	stale := AccountDeletion{AuthenticatedAt: time.Now().Add(-StepUpMaxAge - time.Minute)}
+	if err := authService.DeleteAccount(user.ID, stale); err != ErrReauthRequired {
+		t.Errorf("Expected ErrReauthRequired, got %v", err)
+	}
+
+	recent := AccountDeletion{AuthenticatedAt: time.Now()}
+	if err := authService.DeleteAccount(user.ID, recent); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := userRepo.GetByID(user.ID); err == nil {
+		t.Error("Expected user to be deleted")
+	}
+}
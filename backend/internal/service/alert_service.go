@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// ErrAlertNotFound is returned when an alert does not exist or belongs to
+// another user.
+var ErrAlertNotFound = errors.New("alert not found")
+
+// AlertStore reads and writes alerts. *repository.AlertRepository implements it.
+type AlertStore interface {
+	CreateAlert(ctx context.Context, alert *model.Alert) error
+	GetUserAlerts(ctx context.Context, userID uuid.UUID) ([]model.Alert, error)
+	GetAlertByID(ctx context.Context, alertID uuid.UUID) (*model.Alert, error)
+	UpdateAlert(ctx context.Context, alert *model.Alert) error
+	DeleteAlert(ctx context.Context, alertID uuid.UUID) error
+}
+
+// AlertUpdate holds the alert fields to change; nil fields are left as they are.
+type AlertUpdate struct {
+	Condition      *model.AlertCondition
+	TargetValue    *float64
+	Message        *string
+	Active         *bool
+	NotifyEmail    *bool
+	NotifyTelegram *bool
+	NotifyLINE     *bool
+	NotifyDiscord  *bool
+}
+
+// AlertService defines the interface for managing a user's alerts.
+type AlertService interface {
+	// CreateAlert validates and stores a new alert for the user. The alert's
+	// ID, user and trigger state are set by the service.
+	CreateAlert(ctx context.Context, userID uuid.UUID, alert *model.Alert) error
+	ListAlerts(ctx context.Context, userID uuid.UUID) ([]model.Alert, error)
+	GetAlert(ctx context.Context, userID, alertID uuid.UUID) (*model.Alert, error)
+	// UpdateAlert applies update to one of the user's alerts and validates the result.
+	UpdateAlert(ctx context.Context, userID, alertID uuid.UUID, update AlertUpdate) (*model.Alert, error)
+	DeleteAlert(ctx context.Context, userID, alertID uuid.UUID) error
+}
+
+// alertService implements AlertService.
+type alertService struct {
+	store AlertStore
+}
+
+// NewAlertService creates a new AlertService instance.
+func NewAlertService(store AlertStore) AlertService {
+	return &alertService{store: store}
+}
+
+// CreateAlert validates the alert and stores it as active.
+func (s *alertService) CreateAlert(ctx context.Context, userID uuid.UUID, alert *model.Alert) error {
+	alert.Symbol = strings.TrimSpace(alert.Symbol)
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	alert.ID = uuid.New()
+	alert.UserID = userID
+	alert.Active = true
+	alert.CurrentValue = 0
+	alert.LastTriggered = nil
+	alert.TriggerCount = 0
+	alert.CreatedAt = now
+	alert.UpdatedAt = now
+
+	return s.store.CreateAlert(ctx, alert)
+}
+
+// ListAlerts lists all of the user's alerts, oldest first.
+func (s *alertService) ListAlerts(ctx context.Context, userID uuid.UUID) ([]model.Alert, error) {
+	alerts, err := s.store.GetUserAlerts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if alerts == nil {
+		alerts = []model.Alert{}
+	}
+	return alerts, nil
+}
+
+// GetAlert returns one of the user's alerts.
+func (s *alertService) GetAlert(ctx context.Context, userID, alertID uuid.UUID) (*model.Alert, error) {
+	alert, err := s.store.GetAlertByID(ctx, alertID)
+	if err != nil || alert.UserID != userID {
+		return nil, ErrAlertNotFound
+	}
+	return alert, nil
+}
+
+// UpdateAlert changes one of the user's alerts.
+func (s *alertService) UpdateAlert(ctx context.Context, userID, alertID uuid.UUID, update AlertUpdate) (*model.Alert, error) {
+	alert, err := s.GetAlert(ctx, userID, alertID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Condition != nil {
+		alert.Condition = *update.Condition
+	}
+	if update.TargetValue != nil {
+		alert.TargetValue = *update.TargetValue
+	}
+	if update.Message != nil {
+		alert.Message = *update.Message
+	}
+	if update.Active != nil {
+		alert.Active = *update.Active
+	}
+	if update.NotifyEmail != nil {
+		alert.NotifyEmail = *update.NotifyEmail
+	}
+	if update.NotifyTelegram != nil {
+		alert.NotifyTelegram = *update.NotifyTelegram
+	}
+	if update.NotifyLINE != nil {
+		alert.NotifyLINE = *update.NotifyLINE
+	}
+	if update.NotifyDiscord != nil {
+		alert.NotifyDiscord = *update.NotifyDiscord
+	}
+
+	if err := alert.Validate(); err != nil {
+		return nil, err
+	}
+	alert.UpdatedAt = time.Now()
+
+	if err := s.store.UpdateAlert(ctx, alert); err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// DeleteAlert removes one of the user's alerts.
+func (s *alertService) DeleteAlert(ctx context.Context, userID, alertID uuid.UUID) error {
+	if _, err := s.GetAlert(ctx, userID, alertID); err != nil {
+		return err
+	}
+	return s.store.DeleteAlert(ctx, alertID)
+}
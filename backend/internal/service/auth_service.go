@@ -3,14 +3,19 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base32"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
 	"golang.org/x/crypto/bcrypt"
 
@@ -30,10 +35,65 @@ var (
 	Err2FAInvalidCode = errors.New("invalid 2FA code")
 	// ErrSessionNotFound is returned when a session is not found.
 	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionForbidden is returned when a user acts on another user's session.
+	ErrSessionForbidden = errors.New("session belongs to another user")
 	// ErrOAuthAccountNotFound is returned when an OAuth account is not found.
 	ErrOAuthAccountNotFound = errors.New("OAuth account not found")
 	// ErrOAuthAccountAlreadyLinked is returned when an OAuth account is already linked.
 	ErrOAuthAccountAlreadyLinked = errors.New("OAuth account already linked")
+	// ErrInvalidBackupCode is returned when a backup code is unknown or already used.
+	ErrInvalidBackupCode = errors.New("invalid backup code")
+	// ErrReauthRequired is returned when a sensitive action needs the user to
+	// confirm their identity again.
+	ErrReauthRequired = errors.New("re-authentication required")
+	// ErrPasswordNotSet is returned when a password operation targets an
+	// account that signs in through OAuth only.
+	ErrPasswordNotSet = errors.New("account has no password; sign in with your OAuth provider")
+	// ErrSamePassword is returned when a new password equals the current one.
+	ErrSamePassword = errors.New("new password must differ from the current password")
+	// ErrEmailNotVerified is returned on login when email verification is
+	// required and the user has not verified their email yet.
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	// ErrEmailAlreadyVerified is returned when verifying an email that is
+	// already verified.
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
+	// ErrInvalidVerificationToken is returned when an email verification
+	// token is unknown, already used or expired.
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	// ErrInvalidResetToken is returned when a password reset token is
+	// unknown, already used or expired.
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+	// ErrAccountLocked is returned on login after too many failed attempts
+	// for the email, until the lockout expires.
+	ErrAccountLocked = errors.New("account temporarily locked after too many failed login attempts")
+	// ErrOAuthProviderNotConfigured is returned when starting or completing
+	// an OAuth login with a provider that has no client credentials.
+	ErrOAuthProviderNotConfigured = errors.New("OAuth provider not configured")
+	// ErrInvalidOAuthState is returned when an OAuth callback's state is
+	// unknown, already used, expired or was issued for another provider.
+	ErrInvalidOAuthState = errors.New("invalid or expired OAuth state")
+	// ErrOAuthExchangeFailed is returned when the provider rejects the
+	// authorization code or its profile cannot be fetched.
+	ErrOAuthExchangeFailed = errors.New("OAuth code exchange failed")
+	// ErrOAuthEmailNotVerified is returned when the provider has no
+	// verified email for the user.
+	ErrOAuthEmailNotVerified = errors.New("OAuth provider returned no verified email")
+	// ErrTokenStoreUnavailable is returned when an operation needs the token
+	// store but none is configured.
+	ErrTokenStoreUnavailable = errors.New("token store unavailable")
+)
+
+const (
+	// BackupCodeCount is the number of backup codes issued at a time.
+	BackupCodeCount = 10
+	// LowBackupCodesThreshold is the remaining count at or below which users
+	// are prompted to regenerate their backup codes.
+	LowBackupCodesThreshold = 3
+	// DefaultTOTPPeriod is the default TOTP period in seconds.
+	DefaultTOTPPeriod = 30
+	// DefaultTOTPSkew is the default number of periods before and after the
+	// current one whose TOTP codes are still accepted.
+	DefaultTOTPSkew = 1
 )
 
 // OAuthUserInfo represents user info from OAuth provider.
@@ -59,6 +119,12 @@ type TwoFactorSetup struct {
 type ExtendedAuthService interface {
 	AuthService
 
+	// Variants of the AuthService operations that record the client
+	// described by the context's RequestMeta on their audit events.
+	RegisterContext(ctx context.Context, email, password, name string) (*model.User, error)
+	LoginContext(ctx context.Context, email, password string) (string, string, error)
+	RefreshTokenContext(ctx context.Context, refreshToken string) (string, error)
+
 	// User operations
 	GetUserByID(userID uuid.UUID) (*model.User, error)
 	UpdateUser(user *model.User) error
@@ -68,20 +134,39 @@ type ExtendedAuthService interface {
 	GetSession(sessionID uuid.UUID) (*model.Session, error)
 	GetUserSessions(userID uuid.UUID) ([]model.Session, error)
 	RevokeSession(sessionID uuid.UUID) error
+	RevokeUserSession(userID, sessionID uuid.UUID) error
+	RevokeOtherSessions(userID uuid.UUID, currentRefreshToken string) (int, error)
 	RevokeAllUserSessions(userID uuid.UUID) error
 	Logout(refreshToken string) error
+	LogoutContext(ctx context.Context, refreshToken string) error
 
 	// OAuth operations
 	HandleOAuthLogin(info *OAuthUserInfo) (*model.User, string, string, error)
+	OAuthLoginURL(provider model.OAuthProvider) (string, string, error)
+	CompleteOAuthLogin(ctx context.Context, provider model.OAuthProvider, state, code string) (*model.User, string, string, error)
 	LinkOAuthAccount(userID uuid.UUID, info *OAuthUserInfo) error
 	UnlinkOAuthAccount(userID uuid.UUID, provider model.OAuthProvider) error
 	GetLinkedOAuthAccounts(userID uuid.UUID) ([]model.OAuthAccount, error)
+	SetProfileSyncProvider(userID uuid.UUID, provider model.OAuthProvider) (*model.User, error)
 
 	// 2FA operations
 	Setup2FA(userID uuid.UUID) (*TwoFactorSetup, error)
 	Verify2FA(userID uuid.UUID, code string) error
 	Disable2FA(userID uuid.UUID, code string) error
 	ValidateLoginWith2FA(email, password, code string) (string, string, error)
+	ValidateLoginWith2FAContext(ctx context.Context, email, password, code string) (string, string, error)
+	LoginWithBackupCode(email, password, backupCode string) (string, string, int, error)
+	LoginWithBackupCodeContext(ctx context.Context, email, password, backupCode string) (string, string, int, error)
+	RegenerateBackupCodes(userID uuid.UUID, code string) ([]string, error)
+	RegenerateBackupCodesContext(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+
+	// Account operations
+	ChangePassword(userID uuid.UUID, oldPassword, newPassword string) error
+	RequestPasswordReset(email string) (string, error)
+	ResetPassword(token, newPassword string) error
+	SendVerificationEmail(userID uuid.UUID) (string, error)
+	VerifyEmail(token string) error
+	DeleteAccount(userID uuid.UUID, req AccountDeletion) error
 
 	// Audit logging
 	LogAuditEvent(userID *uuid.UUID, action model.AuditAction, ipAddress, userAgent, details string, success bool) error
@@ -90,26 +175,65 @@ type ExtendedAuthService interface {
 
 // extendedAuthService implements ExtendedAuthService.
 type extendedAuthService struct {
-	userRepo      repository.UserRepository
-	sessionRepo   repository.SessionRepository
-	oauthRepo     repository.OAuthAccountRepository
-	twoFARepo     repository.TwoFactorAuthRepository
-	auditLogRepo  repository.AuditLogRepository
-	tokenStore    TokenStore
-	jwtSecret     string
-	issuerName    string
+	userRepo                 repository.UserRepository
+	sessionRepo              repository.SessionRepository
+	oauthRepo                repository.OAuthAccountRepository
+	twoFARepo                repository.TwoFactorAuthRepository
+	auditLogRepo             repository.AuditLogRepository
+	userDataRepo             repository.UserDataRepository
+	tokenStore               TokenStore
+	jwtSecret                string
+	issuerName               string
+	deletionMode             AccountDeletionMode
+	bcryptCost               int
+	requireEmailVerification bool
+	failedLoginThreshold     int
+	lockoutDuration          time.Duration
+	totpPeriod               uint
+	totpSkew                 uint
+	oauthProviders           map[model.OAuthProvider]OAuthProvider
 }
 
 // AuthServiceConfig holds configuration for the auth service.
 type AuthServiceConfig struct {
-	UserRepo      repository.UserRepository
-	SessionRepo   repository.SessionRepository
-	OAuthRepo     repository.OAuthAccountRepository
-	TwoFARepo     repository.TwoFactorAuthRepository
-	AuditLogRepo  repository.AuditLogRepository
-	TokenStore    TokenStore
-	JWTSecret     string
-	IssuerName    string
+	UserRepo     repository.UserRepository
+	SessionRepo  repository.SessionRepository
+	OAuthRepo    repository.OAuthAccountRepository
+	TwoFARepo    repository.TwoFactorAuthRepository
+	AuditLogRepo repository.AuditLogRepository
+	// UserDataRepo removes a user's records when their account is deleted
+	// (optional).
+	UserDataRepo repository.UserDataRepository
+	TokenStore   TokenStore
+	JWTSecret    string
+	IssuerName   string
+	// DeletionMode controls how DeleteAccount removes users. Defaults to
+	// AccountDeletionModeDelete.
+	DeletionMode AccountDeletionMode
+	// BcryptCost is the cost factor for new password hashes. Zero and values
+	// outside bcrypt.MinCost..bcrypt.MaxCost fall back to bcrypt.DefaultCost.
+	BcryptCost int
+	// FailedLoginThreshold is the number of failed logins for an email after
+	// which logins are rejected with ErrAccountLocked. Defaults to
+	// DefaultFailedLoginThreshold. Lockout needs a TokenStore.
+	FailedLoginThreshold int
+	// LockoutDuration is both the window failed logins are counted in,
+	// measured from the latest failure, and how long the lockout lasts.
+	// Defaults to DefaultLockoutDuration.
+	LockoutDuration time.Duration
+	// TOTPPeriod is the TOTP period in seconds. Defaults to
+	// DefaultTOTPPeriod. Changing it invalidates existing authenticator
+	// setups.
+	TOTPPeriod uint
+	// TOTPSkew is how many periods of client clock drift TOTP validation
+	// tolerates in either direction. Defaults to DefaultTOTPSkew.
+	TOTPSkew uint
+	// OAuthProviders run the authorization code flow for OAuthLoginURL and
+	// CompleteOAuthLogin. Providers without an entry are not available.
+	OAuthProviders map[model.OAuthProvider]OAuthProvider
+	// RequireEmailVerification rejects password logins with
+	// ErrEmailNotVerified until the user has verified their email.
+	RequireEmailVerification bool
 }
 
 // NewExtendedAuthService creates a new ExtendedAuthService instance.
@@ -118,20 +242,59 @@ func NewExtendedAuthService(cfg AuthServiceConfig) ExtendedAuthService {
 	if issuerName == "" {
 		issuerName = "SuperDashboard"
 	}
+	deletionMode := cfg.DeletionMode
+	if deletionMode == "" {
+		deletionMode = AccountDeletionModeDelete
+	}
+	bcryptCost := cfg.BcryptCost
+	if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+		bcryptCost = bcrypt.DefaultCost
+	}
+	failedLoginThreshold := cfg.FailedLoginThreshold
+	if failedLoginThreshold <= 0 {
+		failedLoginThreshold = DefaultFailedLoginThreshold
+	}
+	lockoutDuration := cfg.LockoutDuration
+	if lockoutDuration <= 0 {
+		lockoutDuration = DefaultLockoutDuration
+	}
+	totpPeriod := cfg.TOTPPeriod
+	if totpPeriod == 0 {
+		totpPeriod = DefaultTOTPPeriod
+	}
+	totpSkew := cfg.TOTPSkew
+	if totpSkew == 0 {
+		totpSkew = DefaultTOTPSkew
+	}
 	return &extendedAuthService{
-		userRepo:     cfg.UserRepo,
-		sessionRepo:  cfg.SessionRepo,
-		oauthRepo:    cfg.OAuthRepo,
-		twoFARepo:    cfg.TwoFARepo,
-		auditLogRepo: cfg.AuditLogRepo,
-		tokenStore:   cfg.TokenStore,
-		jwtSecret:    cfg.JWTSecret,
-		issuerName:   issuerName,
+		userRepo:                 cfg.UserRepo,
+		sessionRepo:              cfg.SessionRepo,
+		oauthRepo:                cfg.OAuthRepo,
+		twoFARepo:                cfg.TwoFARepo,
+		auditLogRepo:             cfg.AuditLogRepo,
+		userDataRepo:             cfg.UserDataRepo,
+		tokenStore:               cfg.TokenStore,
+		jwtSecret:                cfg.JWTSecret,
+		issuerName:               issuerName,
+		deletionMode:             deletionMode,
+		bcryptCost:               bcryptCost,
+		requireEmailVerification: cfg.RequireEmailVerification,
+		failedLoginThreshold:     failedLoginThreshold,
+		lockoutDuration:          lockoutDuration,
+		totpPeriod:               totpPeriod,
+		totpSkew:                 totpSkew,
+		oauthProviders:           cfg.OAuthProviders,
 	}
 }
 
 // Register creates a new user account.
 func (s *extendedAuthService) Register(email, password, name string) (*model.User, error) {
+	return s.RegisterContext(context.Background(), email, password, name)
+}
+
+// RegisterContext creates a new user account, recording the client from
+// ctx on the audit event.
+func (s *extendedAuthService) RegisterContext(ctx context.Context, email, password, name string) (*model.User, error) {
 	// Check if user already exists
 	existing, _ := s.userRepo.GetByEmail(email)
 	if existing != nil {
@@ -139,7 +302,7 @@ func (s *extendedAuthService) Register(email, password, name string) (*model.Use
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := s.hashPassword(password)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +311,7 @@ func (s *extendedAuthService) Register(email, password, name string) (*model.Use
 	user := &model.User{
 		ID:           uuid.New(),
 		Email:        email,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: hashedPassword,
 		Name:         name,
 		Role:         "user",
 	}
@@ -159,7 +322,7 @@ func (s *extendedAuthService) Register(email, password, name string) (*model.Use
 
 	// Log audit event
 	if s.auditLogRepo != nil {
-		_ = s.LogAuditEvent(&user.ID, model.AuditActionRegister, "", "", "", true)
+		_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionRegister, "", true)
 	}
 
 	return user, nil
@@ -167,9 +330,21 @@ func (s *extendedAuthService) Register(email, password, name string) (*model.Use
 
 // Login authenticates a user and returns access and refresh tokens.
 func (s *extendedAuthService) Login(email, password string) (string, string, error) {
+	return s.LoginContext(context.Background(), email, password)
+}
+
+// LoginContext authenticates a user like Login, recording the client from
+// ctx on the audit events.
+func (s *extendedAuthService) LoginContext(ctx context.Context, email, password string) (string, string, error) {
+	if err := s.checkLoginLockout(email); err != nil {
+		return "", "", err
+	}
+
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(email)
 	if err != nil {
+		compareDummyPassword(password)
+		s.recordFailedLogin(email)
 		return "", "", ErrInvalidCredentials
 	}
 
@@ -177,25 +352,32 @@ func (s *extendedAuthService) Login(email, password string) (string, string, err
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
 		// Log failed login attempt
 		if s.auditLogRepo != nil {
-			_ = s.LogAuditEvent(&user.ID, model.AuditActionFailedLogin, "", "", "invalid password", false)
+			_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionFailedLogin, "invalid password", false)
 		}
+		s.recordFailedLogin(email)
 		return "", "", ErrInvalidCredentials
 	}
 
+	if err := s.checkEmailVerified(user); err != nil {
+		return "", "", err
+	}
+
 	// Check if 2FA is enabled
 	if user.TwoFAEnabled {
 		return "", "", Err2FARequired
 	}
 
 	// Generate tokens
-	accessToken, refreshToken, err := s.generateTokenPair(user)
+	accessToken, refreshToken, err := s.generateTokenPair(ctx, user)
 	if err != nil {
 		return "", "", err
 	}
 
+	s.clearFailedLogins(email)
+
 	// Log successful login
 	if s.auditLogRepo != nil {
-		_ = s.LogAuditEvent(&user.ID, model.AuditActionLogin, "", "", "", true)
+		_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionLogin, "", true)
 	}
 
 	return accessToken, refreshToken, nil
@@ -203,20 +385,37 @@ func (s *extendedAuthService) Login(email, password string) (string, string, err
 
 // ValidateLoginWith2FA validates login with 2FA code.
 func (s *extendedAuthService) ValidateLoginWith2FA(email, password, code string) (string, string, error) {
+	return s.ValidateLoginWith2FAContext(context.Background(), email, password, code)
+}
+
+// ValidateLoginWith2FAContext validates login with 2FA code like
+// ValidateLoginWith2FA, recording the client from ctx on the audit events.
+func (s *extendedAuthService) ValidateLoginWith2FAContext(ctx context.Context, email, password, code string) (string, string, error) {
+	if err := s.checkLoginLockout(email); err != nil {
+		return "", "", err
+	}
+
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(email)
 	if err != nil {
+		compareDummyPassword(password)
+		s.recordFailedLogin(email)
 		return "", "", ErrInvalidCredentials
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
 		if s.auditLogRepo != nil {
-			_ = s.LogAuditEvent(&user.ID, model.AuditActionFailedLogin, "", "", "invalid password", false)
+			_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionFailedLogin, "invalid password", false)
 		}
+		s.recordFailedLogin(email)
 		return "", "", ErrInvalidCredentials
 	}
 
+	if err := s.checkEmailVerified(user); err != nil {
+		return "", "", err
+	}
+
 	// Check if 2FA is enabled
 	if !user.TwoFAEnabled {
 		return "", "", Err2FANotEnabled
@@ -229,33 +428,164 @@ func (s *extendedAuthService) ValidateLoginWith2FA(email, password, code string)
 	}
 
 	// Verify TOTP code
-	if !totp.Validate(code, twoFA.Secret) {
+	if !s.validateTOTP(code, twoFA.Secret) {
 		// Check backup codes
-		valid := s.checkBackupCode(twoFA, code)
+		valid, err := s.checkBackupCode(twoFA, code)
+		if err != nil {
+			return "", "", err
+		}
 		if !valid {
 			if s.auditLogRepo != nil {
-				_ = s.LogAuditEvent(&user.ID, model.AuditActionFailed2FAAttempt, "", "", "", false)
+				_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionFailed2FAAttempt, "", false)
 			}
+			s.recordFailedLogin(email)
 			return "", "", Err2FAInvalidCode
 		}
 	}
 
 	// Generate tokens
-	accessToken, refreshToken, err := s.generateTokenPair(user)
+	accessToken, refreshToken, err := s.generateTokenPair(ctx, user)
 	if err != nil {
 		return "", "", err
 	}
 
+	s.clearFailedLogins(email)
+
 	// Log successful login
 	if s.auditLogRepo != nil {
-		_ = s.LogAuditEvent(&user.ID, model.AuditActionLogin, "", "", "with 2FA", true)
+		_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionLogin, "with 2FA", true)
 	}
 
 	return accessToken, refreshToken, nil
 }
 
+// LoginWithBackupCode validates login for a 2FA user who has lost their
+// authenticator, consuming one of their backup codes.
+func (s *extendedAuthService) LoginWithBackupCode(email, password, backupCode string) (string, string, int, error) {
+	return s.LoginWithBackupCodeContext(context.Background(), email, password, backupCode)
+}
+
+// LoginWithBackupCodeContext validates login with a backup code like
+// LoginWithBackupCode, recording the client from ctx on the audit events.
+func (s *extendedAuthService) LoginWithBackupCodeContext(ctx context.Context, email, password, backupCode string) (string, string, int, error) {
+	if err := s.checkLoginLockout(email); err != nil {
+		return "", "", 0, err
+	}
+
+	// Get user by email
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		compareDummyPassword(password)
+		s.recordFailedLogin(email)
+		return "", "", 0, ErrInvalidCredentials
+	}
+
+	// Verify password
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		if s.auditLogRepo != nil {
+			_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionFailedLogin, "invalid password", false)
+		}
+		s.recordFailedLogin(email)
+		return "", "", 0, ErrInvalidCredentials
+	}
+
+	if err := s.checkEmailVerified(user); err != nil {
+		return "", "", 0, err
+	}
+
+	// Check if 2FA is enabled
+	if !user.TwoFAEnabled || s.twoFARepo == nil {
+		return "", "", 0, Err2FANotEnabled
+	}
+
+	twoFA, err := s.twoFARepo.GetByUserID(user.ID)
+	if err != nil {
+		return "", "", 0, Err2FANotEnabled
+	}
+
+	remaining, ok, err := s.consumeBackupCode(twoFA, backupCode)
+	if err != nil {
+		return "", "", 0, err
+	}
+	if !ok {
+		if s.auditLogRepo != nil {
+			_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionFailed2FAAttempt, "invalid backup code", false)
+		}
+		s.recordFailedLogin(email)
+		return "", "", 0, ErrInvalidBackupCode
+	}
+
+	// Generate tokens
+	accessToken, refreshToken, err := s.generateTokenPair(ctx, user)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	s.clearFailedLogins(email)
+
+	// Log successful login
+	if s.auditLogRepo != nil {
+		_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionLogin, fmt.Sprintf("with backup code, %d remaining", remaining), true)
+	}
+
+	return accessToken, refreshToken, remaining, nil
+}
+
+// RegenerateBackupCodes replaces a user's backup codes after verifying a TOTP code.
+func (s *extendedAuthService) RegenerateBackupCodes(userID uuid.UUID, code string) ([]string, error) {
+	return s.RegenerateBackupCodesContext(context.Background(), userID, code)
+}
+
+// RegenerateBackupCodesContext replaces a user's backup codes like
+// RegenerateBackupCodes, recording the client from ctx on the audit events.
+func (s *extendedAuthService) RegenerateBackupCodesContext(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	if s.twoFARepo == nil {
+		return nil, Err2FANotEnabled
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TwoFAEnabled {
+		return nil, Err2FANotEnabled
+	}
+
+	twoFA, err := s.twoFARepo.GetByUserID(userID)
+	if err != nil {
+		return nil, Err2FANotEnabled
+	}
+
+	// Verify TOTP code
+	if !s.validateTOTP(code, twoFA.Secret) {
+		if s.auditLogRepo != nil {
+			_ = s.logAuditEventContext(ctx, &userID, model.AuditActionFailed2FAAttempt, "backup code regeneration failed", false)
+		}
+		return nil, Err2FAInvalidCode
+	}
+
+	backupCodes := s.generateBackupCodes()
+	twoFA.BackupCodes = hashBackupCodes(backupCodes)
+	if err := s.twoFARepo.Update(twoFA); err != nil {
+		return nil, err
+	}
+
+	if s.auditLogRepo != nil {
+		_ = s.logAuditEventContext(ctx, &userID, model.AuditActionSettingsChange, "backup codes regenerated", true)
+	}
+
+	return backupCodes, nil
+}
+
 // RefreshToken generates a new access token from a valid refresh token.
 func (s *extendedAuthService) RefreshToken(refreshToken string) (string, error) {
+	return s.RefreshTokenContext(context.Background(), refreshToken)
+}
+
+// RefreshTokenContext generates a new access token like RefreshToken,
+// recording the client from ctx on the audit event.
+func (s *extendedAuthService) RefreshTokenContext(ctx context.Context, refreshToken string) (string, error) {
 	claims, err := s.ValidateToken(refreshToken)
 	if err != nil {
 		return "", err
@@ -280,6 +610,19 @@ func (s *extendedAuthService) RefreshToken(refreshToken string) (string, error)
 		role = "user"
 	}
 
+	// Reject tokens for deleted or anonymized accounts
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user.Email != email {
+		return "", ErrInvalidToken
+	}
+
+	// Reject tokens whose session was revoked, e.g. by a password change
+	if s.sessionRepo != nil {
+		if _, err := s.sessionRepo.GetByRefreshToken(refreshToken); err != nil {
+			return "", ErrRefreshTokenNotFound
+		}
+	}
+
 	// Verify refresh token exists in Redis if token store is available
 	if s.tokenStore != nil {
 		jti, ok := (*claims)["jti"].(string)
@@ -287,10 +630,10 @@ func (s *extendedAuthService) RefreshToken(refreshToken string) (string, error)
 			return "", ErrInvalidToken
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		storedUserID, err := s.tokenStore.GetRefreshToken(ctx, jti)
+		storedUserID, err := s.tokenStore.GetRefreshToken(storeCtx, jti)
 		if err != nil {
 			return "", ErrRefreshTokenNotFound
 		}
@@ -302,11 +645,15 @@ func (s *extendedAuthService) RefreshToken(refreshToken string) (string, error)
 
 	// Log token refresh
 	if s.auditLogRepo != nil {
-		_ = s.LogAuditEvent(&userID, model.AuditActionTokenRefresh, "", "", "", true)
+		_ = s.logAuditEventContext(ctx, &userID, model.AuditActionTokenRefresh, "", true)
 	}
 
-	// Generate new access token
-	return s.generateToken(userID, email, role, AccessTokenDuration, "")
+	// Generate new access token, keeping the time the user signed in
+	authTime := time.Now()
+	if v, ok := (*claims)["auth_time"].(float64); ok {
+		authTime = time.Unix(int64(v), 0)
+	}
+	return s.generateTokenAuthenticatedAt(userID, email, role, AccessTokenDuration, "", authTime)
 }
 
 // ValidateToken validates a JWT token and returns its claims.
@@ -347,31 +694,8 @@ func (s *extendedAuthService) CreateSession(userID uuid.UUID, userAgent, ipAddre
 		return nil, "", "", err
 	}
 
-	// Generate tokens
-	accessToken, refreshToken, err := s.generateTokenPair(user)
-	if err != nil {
-		return nil, "", "", err
-	}
-
-	// Create session if session repo is available
-	if s.sessionRepo != nil {
-		session := &model.Session{
-			ID:           uuid.New(),
-			UserID:       userID,
-			RefreshToken: refreshToken,
-			UserAgent:    userAgent,
-			IPAddress:    ipAddress,
-			ExpiresAt:    time.Now().Add(RefreshTokenDuration),
-		}
-
-		if err := s.sessionRepo.Create(session); err != nil {
-			return nil, "", "", err
-		}
-
-		return session, accessToken, refreshToken, nil
-	}
-
-	return nil, accessToken, refreshToken, nil
+	ctx := WithRequestMeta(context.Background(), RequestMeta{IPAddress: ipAddress, UserAgent: userAgent})
+	return s.issueSession(ctx, user)
 }
 
 // GetSession retrieves a session by ID.
@@ -390,15 +714,16 @@ func (s *extendedAuthService) GetUserSessions(userID uuid.UUID) ([]model.Session
 	return s.sessionRepo.GetByUserID(userID)
 }
 
-// RevokeSession revokes a specific session.
+// RevokeSession revokes a specific session and its refresh token.
 func (s *extendedAuthService) RevokeSession(sessionID uuid.UUID) error {
 	if s.sessionRepo == nil {
 		return nil
 	}
 
-	if s.auditLogRepo != nil {
-		session, err := s.sessionRepo.GetByID(sessionID)
-		if err == nil {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err == nil {
+		s.deleteStoredRefreshToken(session.RefreshToken)
+		if s.auditLogRepo != nil {
 			_ = s.LogAuditEvent(&session.UserID, model.AuditActionSessionRevoke, "", "", "", true)
 		}
 	}
@@ -406,6 +731,63 @@ func (s *extendedAuthService) RevokeSession(sessionID uuid.UUID) error {
 	return s.sessionRepo.RevokeSession(sessionID)
 }
 
+// RevokeUserSession revokes one of the user's sessions. It returns
+// ErrSessionNotFound for unknown or already revoked sessions and
+// ErrSessionForbidden for sessions of other users.
+func (s *extendedAuthService) RevokeUserSession(userID, sessionID uuid.UUID) error {
+	if s.sessionRepo == nil {
+		return ErrSessionNotFound
+	}
+
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return ErrSessionNotFound
+	}
+	if session.UserID != userID {
+		return ErrSessionForbidden
+	}
+
+	return s.RevokeSession(sessionID)
+}
+
+// RevokeOtherSessions revokes all of the user's sessions except the one
+// holding currentRefreshToken and returns how many were revoked. It returns
+// ErrInvalidToken when the token does not belong to one of the user's
+// sessions.
+func (s *extendedAuthService) RevokeOtherSessions(userID uuid.UUID, currentRefreshToken string) (int, error) {
+	if s.sessionRepo == nil {
+		return 0, nil
+	}
+
+	current, err := s.sessionRepo.GetByRefreshToken(currentRefreshToken)
+	if err != nil || current.UserID != userID {
+		return 0, ErrInvalidToken
+	}
+
+	sessions, err := s.sessionRepo.GetByUserID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.ID == current.ID {
+			continue
+		}
+		s.deleteStoredRefreshToken(session.RefreshToken)
+		if err := s.sessionRepo.RevokeSession(session.ID); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+
+	if s.auditLogRepo != nil && revoked > 0 {
+		_ = s.LogAuditEvent(&userID, model.AuditActionSessionRevoke, "", "", fmt.Sprintf("%d other sessions", revoked), true)
+	}
+
+	return revoked, nil
+}
+
 // RevokeAllUserSessions revokes all sessions for a user.
 func (s *extendedAuthService) RevokeAllUserSessions(userID uuid.UUID) error {
 	if s.sessionRepo == nil {
@@ -421,6 +803,12 @@ func (s *extendedAuthService) RevokeAllUserSessions(userID uuid.UUID) error {
 
 // Logout invalidates a refresh token.
 func (s *extendedAuthService) Logout(refreshToken string) error {
+	return s.LogoutContext(context.Background(), refreshToken)
+}
+
+// LogoutContext invalidates a refresh token like Logout, recording the
+// client from ctx on the audit event.
+func (s *extendedAuthService) LogoutContext(ctx context.Context, refreshToken string) error {
 	claims, err := s.ValidateToken(refreshToken)
 	if err != nil {
 		return err
@@ -439,15 +827,26 @@ func (s *extendedAuthService) Logout(refreshToken string) error {
 	if s.tokenStore != nil {
 		jti, ok := (*claims)["jti"].(string)
 		if ok {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			_ = s.tokenStore.DeleteRefreshToken(ctx, jti)
+			_ = s.tokenStore.DeleteRefreshToken(storeCtx, jti)
+		}
+	}
+
+	// Revoke the token's session, which also keeps the token from
+	// refreshing without a token store
+	if s.sessionRepo != nil {
+		session, err := s.sessionRepo.GetByRefreshToken(refreshToken)
+		if err == nil && session.UserID == userID {
+			if err := s.sessionRepo.RevokeSession(session.ID); err != nil {
+				return err
+			}
 		}
 	}
 
 	// Log logout
 	if s.auditLogRepo != nil {
-		_ = s.LogAuditEvent(&userID, model.AuditActionLogout, "", "", "", true)
+		_ = s.logAuditEventContext(ctx, &userID, model.AuditActionLogout, "", true)
 	}
 
 	return nil
@@ -455,6 +854,13 @@ func (s *extendedAuthService) Logout(refreshToken string) error {
 
 // HandleOAuthLogin handles OAuth login/registration flow.
 func (s *extendedAuthService) HandleOAuthLogin(info *OAuthUserInfo) (*model.User, string, string, error) {
+	return s.handleOAuthLogin(context.Background(), info)
+}
+
+// handleOAuthLogin handles the OAuth login/registration flow like
+// HandleOAuthLogin, recording the client from ctx on the session and audit
+// events.
+func (s *extendedAuthService) handleOAuthLogin(ctx context.Context, info *OAuthUserInfo) (*model.User, string, string, error) {
 	// Check if OAuth account already exists
 	if s.oauthRepo != nil {
 		existingOAuth, err := s.oauthRepo.GetByProviderAndProviderUserID(info.Provider, info.ProviderUserID)
@@ -465,21 +871,31 @@ func (s *extendedAuthService) HandleOAuthLogin(info *OAuthUserInfo) (*model.User
 				return nil, "", "", err
 			}
 
-			// Update OAuth tokens
+			// Update OAuth tokens and the provider's profile
 			existingOAuth.AccessToken = info.AccessToken
 			existingOAuth.RefreshToken = info.RefreshToken
 			existingOAuth.ExpiresAt = info.ExpiresAt
+			if info.Name != "" {
+				existingOAuth.Name = info.Name
+			}
+			if info.AvatarURL != "" {
+				existingOAuth.AvatarURL = info.AvatarURL
+			}
 			_ = s.oauthRepo.Update(existingOAuth)
 
+			if user.ProfileSyncProvider == info.Provider && copyOAuthProfile(user, existingOAuth) {
+				_ = s.userRepo.Update(user)
+			}
+
 			// Generate tokens
-			accessToken, refreshToken, err := s.generateTokenPair(user)
+			accessToken, refreshToken, err := s.generateTokenPair(ctx, user)
 			if err != nil {
 				return nil, "", "", err
 			}
 
 			// Log OAuth login
 			if s.auditLogRepo != nil {
-				_ = s.LogAuditEvent(&user.ID, model.AuditActionLogin, "", "", fmt.Sprintf("via %s", info.Provider), true)
+				_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionLogin, fmt.Sprintf("via %s", info.Provider), true)
 			}
 
 			return user, accessToken, refreshToken, nil
@@ -506,15 +922,21 @@ func (s *extendedAuthService) HandleOAuthLogin(info *OAuthUserInfo) (*model.User
 			_ = s.oauthRepo.Create(oauthAccount)
 		}
 
+		// Fill in an avatar the user hasn't set themselves
+		if existingUser.AvatarURL == "" && info.AvatarURL != "" {
+			existingUser.AvatarURL = info.AvatarURL
+			_ = s.userRepo.Update(existingUser)
+		}
+
 		// Generate tokens
-		accessToken, refreshToken, err := s.generateTokenPair(existingUser)
+		accessToken, refreshToken, err := s.generateTokenPair(ctx, existingUser)
 		if err != nil {
 			return nil, "", "", err
 		}
 
 		// Log OAuth link
 		if s.auditLogRepo != nil {
-			_ = s.LogAuditEvent(&existingUser.ID, model.AuditActionOAuthLink, "", "", fmt.Sprintf("%s linked", info.Provider), true)
+			_ = s.logAuditEventContext(ctx, &existingUser.ID, model.AuditActionOAuthLink, fmt.Sprintf("%s linked", info.Provider), true)
 		}
 
 		return existingUser, accessToken, refreshToken, nil
@@ -526,7 +948,10 @@ func (s *extendedAuthService) HandleOAuthLogin(info *OAuthUserInfo) (*model.User
 		Email:        info.Email,
 		PasswordHash: "", // OAuth users don't have a password
 		Name:         info.Name,
+		AvatarURL:    info.AvatarURL,
 		Role:         "user",
+		// The provider has already verified the email
+		EmailVerified: true,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
@@ -551,14 +976,14 @@ func (s *extendedAuthService) HandleOAuthLogin(info *OAuthUserInfo) (*model.User
 	}
 
 	// Generate tokens
-	accessToken, refreshToken, err := s.generateTokenPair(user)
+	accessToken, refreshToken, err := s.generateTokenPair(ctx, user)
 	if err != nil {
 		return nil, "", "", err
 	}
 
 	// Log registration
 	if s.auditLogRepo != nil {
-		_ = s.LogAuditEvent(&user.ID, model.AuditActionRegister, "", "", fmt.Sprintf("via %s", info.Provider), true)
+		_ = s.logAuditEventContext(ctx, &user.ID, model.AuditActionRegister, fmt.Sprintf("via %s", info.Provider), true)
 	}
 
 	return user, accessToken, refreshToken, nil
@@ -628,6 +1053,68 @@ func (s *extendedAuthService) GetLinkedOAuthAccounts(userID uuid.UUID) ([]model.
 	return s.oauthRepo.GetByUserID(userID)
 }
 
+// SetProfileSyncProvider chooses the linked OAuth provider whose name and
+// avatar are synced to the user's profile, and syncs them immediately. An
+// empty provider turns syncing off.
+func (s *extendedAuthService) SetProfileSyncProvider(userID uuid.UUID, provider model.OAuthProvider) (*model.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if provider != "" {
+		account, err := s.linkedOAuthAccount(userID, provider)
+		if err != nil {
+			return nil, err
+		}
+		copyOAuthProfile(user, account)
+	}
+
+	user.ProfileSyncProvider = provider
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	if s.auditLogRepo != nil {
+		_ = s.LogAuditEvent(&userID, model.AuditActionSettingsChange, "", "", fmt.Sprintf("profile sync provider set to %q", provider), true)
+	}
+
+	return user, nil
+}
+
+// linkedOAuthAccount returns the user's linked account for a provider.
+func (s *extendedAuthService) linkedOAuthAccount(userID uuid.UUID, provider model.OAuthProvider) (*model.OAuthAccount, error) {
+	if s.oauthRepo == nil {
+		return nil, ErrOAuthAccountNotFound
+	}
+
+	accounts, err := s.oauthRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		if accounts[i].Provider == provider {
+			return &accounts[i], nil
+		}
+	}
+	return nil, ErrOAuthAccountNotFound
+}
+
+// copyOAuthProfile copies the non-empty name and avatar from a linked account
+// to the user. It reports whether anything changed.
+func copyOAuthProfile(user *model.User, account *model.OAuthAccount) bool {
+	changed := false
+	if account.Name != "" && account.Name != user.Name {
+		user.Name = account.Name
+		changed = true
+	}
+	if account.AvatarURL != "" && account.AvatarURL != user.AvatarURL {
+		user.AvatarURL = account.AvatarURL
+		changed = true
+	}
+	return changed
+}
+
 // Setup2FA sets up 2FA for a user.
 func (s *extendedAuthService) Setup2FA(userID uuid.UUID) (*TwoFactorSetup, error) {
 	user, err := s.userRepo.GetByID(userID)
@@ -643,25 +1130,21 @@ func (s *extendedAuthService) Setup2FA(userID uuid.UUID) (*TwoFactorSetup, error
 	key, err := totp.Generate(totp.GenerateOpts{
 		Issuer:      s.issuerName,
 		AccountName: user.Email,
+		Period:      s.totpPeriod,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	// Generate backup codes
-	backupCodes := make([]string, 10)
-	for i := range backupCodes {
-		backupCodes[i] = s.generateBackupCode()
-	}
-
-	backupCodesJSON, _ := json.Marshal(backupCodes)
+	backupCodes := s.generateBackupCodes()
 
 	// Store 2FA config (not verified yet)
 	twoFA := &model.TwoFactorAuth{
 		ID:          uuid.New(),
 		UserID:      userID,
 		Secret:      key.Secret(),
-		BackupCodes: string(backupCodesJSON),
+		BackupCodes: hashBackupCodes(backupCodes),
 		Verified:    false,
 	}
 
@@ -692,7 +1175,7 @@ func (s *extendedAuthService) Verify2FA(userID uuid.UUID, code string) error {
 	}
 
 	// Verify TOTP code
-	if !totp.Validate(code, twoFA.Secret) {
+	if !s.validateTOTP(code, twoFA.Secret) {
 		if s.auditLogRepo != nil {
 			_ = s.LogAuditEvent(&userID, model.AuditActionFailed2FAAttempt, "", "", "setup verification failed", false)
 		}
@@ -746,9 +1229,13 @@ func (s *extendedAuthService) Disable2FA(userID uuid.UUID, code string) error {
 	}
 
 	// Verify TOTP code
-	if !totp.Validate(code, twoFA.Secret) {
+	if !s.validateTOTP(code, twoFA.Secret) {
 		// Check backup codes
-		if !s.checkBackupCode(twoFA, code) {
+		valid, err := s.checkBackupCode(twoFA, code)
+		if err != nil {
+			return err
+		}
+		if !valid {
 			if s.auditLogRepo != nil {
 				_ = s.LogAuditEvent(&userID, model.AuditActionFailed2FAAttempt, "", "", "disable attempt failed", false)
 			}
@@ -804,39 +1291,81 @@ func (s *extendedAuthService) GetUserAuditLogs(userID uuid.UUID, limit, offset i
 
 // Helper methods
 
-func (s *extendedAuthService) generateTokenPair(user *model.User) (string, string, error) {
+// generateTokenPair signs the user in, returning access and refresh tokens
+// for a new session.
+func (s *extendedAuthService) generateTokenPair(ctx context.Context, user *model.User) (string, string, error) {
+	_, accessToken, refreshToken, err := s.issueSession(ctx, user)
+	return accessToken, refreshToken, err
+}
+
+// issueSession generates an access and refresh token pair and, when sessions
+// are stored, records a session for the refresh token with the client from
+// ctx. The session is nil without a session repository.
+func (s *extendedAuthService) issueSession(ctx context.Context, user *model.User) (*model.Session, string, string, error) {
 	// Generate access token
 	accessToken, err := s.generateToken(user.ID, user.Email, user.Role, AccessTokenDuration, "")
 	if err != nil {
-		return "", "", err
+		return nil, "", "", err
 	}
 
 	// Generate refresh token with JTI for Redis storage
 	jti := uuid.New().String()
 	refreshToken, err := s.generateToken(user.ID, user.Email, user.Role, RefreshTokenDuration, jti)
 	if err != nil {
-		return "", "", err
+		return nil, "", "", err
 	}
 
 	// Store refresh token in Redis if token store is available
 	if s.tokenStore != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		if err := s.tokenStore.SetRefreshToken(ctx, user.ID.String(), jti, RefreshTokenDuration); err != nil {
-			return "", "", err
+		if err := s.tokenStore.SetRefreshToken(storeCtx, user.ID.String(), jti, RefreshTokenDuration); err != nil {
+			return nil, "", "", err
 		}
 	}
 
-	return accessToken, refreshToken, nil
+	if s.sessionRepo == nil {
+		return nil, accessToken, refreshToken, nil
+	}
+
+	meta := RequestMetaFromContext(ctx)
+	session := &model.Session{
+		ID:           uuid.New(),
+		UserID:       user.ID,
+		RefreshToken: refreshToken,
+		UserAgent:    meta.UserAgent,
+		IPAddress:    meta.IPAddress,
+		ExpiresAt:    time.Now().Add(RefreshTokenDuration),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, "", "", err
+	}
+	return session, accessToken, refreshToken, nil
+}
+
+// hashPassword hashes a password with the configured bcrypt cost.
+func (s *extendedAuthService) hashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), s.bcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
 }
 
 func (s *extendedAuthService) generateToken(userID uuid.UUID, email, role string, expiry time.Duration, jti string) (string, error) {
+	return s.generateTokenAuthenticatedAt(userID, email, role, expiry, jti, time.Now())
+}
+
+// generateTokenAuthenticatedAt generates a token whose auth_time claim records
+// when the user signed in, so refreshed access tokens keep the original time.
+func (s *extendedAuthService) generateTokenAuthenticatedAt(userID uuid.UUID, email, role string, expiry time.Duration, jti string, authTime time.Time) (string, error) {
 	claims := jwt.MapClaims{
-		"user_id": userID.String(),
-		"email":   email,
-		"role":    role,
-		"exp":     time.Now().Add(expiry).Unix(),
-		"iat":     time.Now().Unix(),
+		"user_id":   userID.String(),
+		"email":     email,
+		"role":      role,
+		"exp":       time.Now().Add(expiry).Unix(),
+		"iat":       time.Now().Unix(),
+		"auth_time": authTime.Unix(),
 	}
 
 	if jti != "" {
@@ -853,24 +1382,115 @@ func (s *extendedAuthService) generateBackupCode() string {
 	return base32.StdEncoding.EncodeToString(bytes)[:8]
 }
 
-func (s *extendedAuthService) checkBackupCode(twoFA *model.TwoFactorAuth, code string) bool {
+func (s *extendedAuthService) generateBackupCodes() []string {
+	backupCodes := make([]string, BackupCodeCount)
+	for i := range backupCodes {
+		backupCodes[i] = s.generateBackupCode()
+	}
+	return backupCodes
+}
+
+// validateTOTP reports whether code is valid for secret at the current time,
+// allowing for the configured clock skew.
+func (s *extendedAuthService) validateTOTP(code, secret string) bool {
+	valid, err := totp.ValidateCustom(code, secret, time.Now().UTC(), totp.ValidateOpts{
+		Period:    s.totpPeriod,
+		Skew:      s.totpSkew,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	return err == nil && valid
+}
+
+// hashBackupCodes returns the JSON array of backup code hashes stored in
+// TwoFactorAuth.BackupCodes. The plaintext codes are only shown to the user.
+func hashBackupCodes(codes []string) string {
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = hashBackupCode(code)
+	}
+	backupCodesJSON, _ := json.Marshal(hashes)
+	return string(backupCodesJSON)
+}
+
+// hashBackupCode returns the hex SHA-256 hash of a normalized backup code.
+func hashBackupCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkBackupCode consumes code if it is one of the user's backup codes. It
+// fails if the remaining codes cannot be saved, so a used code is never
+// accepted again.
+func (s *extendedAuthService) checkBackupCode(twoFA *model.TwoFactorAuth, code string) (bool, error) {
+	_, ok, err := s.consumeBackupCode(twoFA, code)
+	return ok, err
+}
+
+// consumeBackupCode removes a matching backup code and persists the rest. It
+// returns the number of codes left and whether the code matched. Codes are
+// compared case-insensitively, ignoring dashes and spaces, against the stored
+// hashes. Plaintext codes stored before hashing was introduced are hashed
+// and saved on first read, so they are never compared in plaintext.
+func (s *extendedAuthService) consumeBackupCode(twoFA *model.TwoFactorAuth, code string) (int, bool, error) {
 	var backupCodes []string
 	if err := json.Unmarshal([]byte(twoFA.BackupCodes), &backupCodes); err != nil {
-		return false
+		return 0, false, nil
 	}
+	rehashed := hashLegacyBackupCodes(backupCodes)
 
-	for i, bc := range backupCodes {
-		if bc == code {
-			// Remove used backup code
-			backupCodes = append(backupCodes[:i], backupCodes[i+1:]...)
-			backupCodesJSON, _ := json.Marshal(backupCodes)
-			twoFA.BackupCodes = string(backupCodesJSON)
-			if s.twoFARepo != nil {
-				_ = s.twoFARepo.Update(twoFA)
+	matched := -1
+	if code = normalizeBackupCode(code); code != "" {
+		hashed := hashBackupCode(code)
+		for i, bc := range backupCodes {
+			if subtle.ConstantTimeCompare([]byte(bc), []byte(hashed)) == 1 {
+				matched = i
+				break
 			}
-			return true
 		}
 	}
+	if matched < 0 && !rehashed {
+		return len(backupCodes), false, nil
+	}
 
-	return false
+	// Remove the used backup code
+	if matched >= 0 {
+		backupCodes = append(backupCodes[:matched], backupCodes[matched+1:]...)
+	}
+	backupCodesJSON, _ := json.Marshal(backupCodes)
+	twoFA.BackupCodes = string(backupCodesJSON)
+	if s.twoFARepo != nil {
+		if err := s.twoFARepo.Update(twoFA); err != nil && matched >= 0 {
+			return len(backupCodes), true, err
+		}
+	}
+	return len(backupCodes), matched >= 0, nil
+}
+
+// normalizeBackupCode upper-cases a backup code and strips dashes and spaces.
+func normalizeBackupCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// hashLegacyBackupCodes replaces plaintext codes in backupCodes with their
+// hashes and reports whether any were replaced.
+func hashLegacyBackupCodes(backupCodes []string) bool {
+	rehashed := false
+	for i, bc := range backupCodes {
+		if !isBackupCodeHash(bc) {
+			backupCodes[i] = hashBackupCode(normalizeBackupCode(bc))
+			rehashed = true
+		}
+	}
+	return rehashed
+}
+
+// isBackupCodeHash reports whether a stored backup code is a hash from
+// hashBackupCode rather than a legacy plaintext code.
+func isBackupCodeHash(stored string) bool {
+	if len(stored) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(stored)
+	return err == nil
 }
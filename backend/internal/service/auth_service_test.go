@@ -1,10 +1,15 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
 	"github.com/awaymess/super-dashboard/backend/internal/model"
@@ -97,7 +102,8 @@ func (m *mockSessionRepository) RevokeSession(id uuid.UUID) error {
 }
 
 type mockTwoFactorAuthRepository struct {
-	twoFAs map[uuid.UUID]*model.TwoFactorAuth
+	twoFAs    map[uuid.UUID]*model.TwoFactorAuth
+	updateErr error
 }
 
 func newMockTwoFactorAuthRepository() *mockTwoFactorAuthRepository {
@@ -123,6 +129,9 @@ func (m *mockTwoFactorAuthRepository) GetByUserID(userID uuid.UUID) (*model.TwoF
 }
 
 func (m *mockTwoFactorAuthRepository) Update(twoFA *model.TwoFactorAuth) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
 	m.twoFAs[twoFA.UserID] = twoFA
 	return nil
 }
@@ -168,13 +177,8 @@ func (m *mockAuditLogRepository) GetByUserID(userID uuid.UUID, limit, offset int
 	return logs[offset:end], nil
 }
 
-func (m *mockAuditLogRepository) GetByAction(action model.AuditAction, limit, offset int) ([]model.AuditLog, error) {
-	var logs []model.AuditLog
-	for _, log := range m.logs {
-		if log.Action == action {
-			logs = append(logs, log)
-		}
-	}
+func (m *mockAuditLogRepository) GetByAction(action model.AuditAction, filter repository.AuditLogFilter, limit, offset int) ([]model.AuditLog, error) {
+	logs := m.filtered(action, filter)
 	if offset >= len(logs) {
 		return nil, nil
 	}
@@ -185,11 +189,29 @@ func (m *mockAuditLogRepository) GetByAction(action model.AuditAction, limit, of
 	return logs[offset:end], nil
 }
 
-func (m *mockAuditLogRepository) GetRecent(limit int) ([]model.AuditLog, error) {
-	if limit > len(m.logs) {
-		limit = len(m.logs)
+func (m *mockAuditLogRepository) GetRecent(filter repository.AuditLogFilter, limit, offset int) ([]model.AuditLog, error) {
+	return m.GetByAction("", filter, limit, offset)
+}
+
+func (m *mockAuditLogRepository) Count(action model.AuditAction, filter repository.AuditLogFilter) (int64, error) {
+	return int64(len(m.filtered(action, filter))), nil
+}
+
+func (m *mockAuditLogRepository) filtered(action model.AuditAction, filter repository.AuditLogFilter) []model.AuditLog {
+	var logs []model.AuditLog
+	for _, log := range m.logs {
+		if action != "" && log.Action != action {
+			continue
+		}
+		if filter.UserID != nil && (log.UserID == nil || *log.UserID != *filter.UserID) {
+			continue
+		}
+		if filter.Success != nil && log.Success != *filter.Success {
+			continue
+		}
+		logs = append(logs, log)
 	}
-	return m.logs[:limit], nil
+	return logs
 }
 
 func (m *mockAuditLogRepository) DeleteOlderThan(before time.Time) error {
@@ -300,6 +322,40 @@ func TestExtendedAuthService_Register(t *testing.T) {
 	}
 }
 
+func TestExtendedAuthService_BcryptCost(t *testing.T) {
+	tests := []struct {
+		name string
+		cost int
+		want int
+	}{
+		{name: "configured", cost: bcrypt.MinCost, want: bcrypt.MinCost},
+		{name: "zero uses default", cost: 0, want: bcrypt.DefaultCost},
+		{name: "out of range uses default", cost: bcrypt.MaxCost + 1, want: bcrypt.DefaultCost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService := NewExtendedAuthService(AuthServiceConfig{
+				UserRepo:   newMockUserRepository(),
+				JWTSecret:  "test-secret",
+				BcryptCost: tt.cost,
+			})
+
+			user, err := authService.Register("cost@example.com", "password123", "Cost User")
+			if err != nil {
+				t.Fatalf("Failed to register user: %v", err)
+			}
+			cost, err := bcrypt.Cost([]byte(user.PasswordHash))
+			if err != nil {
+				t.Fatalf("Failed to read hash cost: %v", err)
+			}
+			if cost != tt.want {
+				t.Errorf("Expected cost %d, got %d", tt.want, cost)
+			}
+		})
+	}
+}
+
 func TestExtendedAuthService_Login(t *testing.T) {
 	userRepo := newMockUserRepository()
 	authService := NewExtendedAuthService(AuthServiceConfig{
@@ -377,6 +433,202 @@ func TestExtendedAuthService_Setup2FA(t *testing.T) {
 	}
 }
 
+func TestExtendedAuthService_LoginWithBackupCode(t *testing.T) {
+	userRepo := newMockUserRepository()
+	twoFARepo := newMockTwoFactorAuthRepository()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:     userRepo,
+		TwoFARepo:    twoFARepo,
+		AuditLogRepo: newMockAuditLogRepository(),
+		JWTSecret:    "test-secret",
+		IssuerName:   "TestApp",
+	})
+
+	user, err := authService.Register("recovery@example.com", "password123", "Recovery User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	// Backup codes only work once 2FA is enabled
+	setup, err := authService.Setup2FA(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to setup 2FA: %v", err)
+	}
+	if _, _, _, err := authService.LoginWithBackupCode("recovery@example.com", "password123", setup.BackupCodes[0]); err != Err2FANotEnabled {
+		t.Errorf("Expected Err2FANotEnabled, got %v", err)
+	}
+
+	code, _ := totp.GenerateCode(setup.Secret, time.Now())
+	if err := authService.Verify2FA(user.ID, code); err != nil {
+		t.Fatalf("Failed to verify 2FA: %v", err)
+	}
+
+	// Codes are accepted case-insensitively with separators
+	typed := strings.ToLower(setup.BackupCodes[0][:4] + "-" + setup.BackupCodes[0][4:])
+	accessToken, _, remaining, err := authService.LoginWithBackupCode("recovery@example.com", "password123", typed)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if accessToken == "" {
+		t.Error("Expected access token to be set")
+	}
+	if remaining != BackupCodeCount-1 {
+		t.Errorf("Expected %d remaining backup codes, got %d", BackupCodeCount-1, remaining)
+	}
+
+	// A used code cannot be replayed
+	if _, _, _, err := authService.LoginWithBackupCode("recovery@example.com", "password123", setup.BackupCodes[0]); err != ErrInvalidBackupCode {
+		t.Errorf("Expected ErrInvalidBackupCode, got %v", err)
+	}
+
+	if _, _, _, err := authService.LoginWithBackupCode("recovery@example.com", "wrongpassword", setup.BackupCodes[1]); err != ErrInvalidCredentials {
+		t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+	}
+
+	// Regenerating invalidates the old codes
+	if _, err := authService.RegenerateBackupCodes(user.ID, "000000"); err != Err2FAInvalidCode {
+		t.Errorf("Expected Err2FAInvalidCode, got %v", err)
+	}
+	code, _ = totp.GenerateCode(setup.Secret, time.Now())
+	backupCodes, err := authService.RegenerateBackupCodes(user.ID, code)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(backupCodes) != BackupCodeCount {
+		t.Errorf("Expected %d backup codes, got %d", BackupCodeCount, len(backupCodes))
+	}
+	if _, _, _, err := authService.LoginWithBackupCode("recovery@example.com", "password123", setup.BackupCodes[1]); err != ErrInvalidBackupCode {
+		t.Errorf("Expected ErrInvalidBackupCode for an old code, got %v", err)
+	}
+	if _, _, remaining, err := authService.LoginWithBackupCode("recovery@example.com", "password123", backupCodes[0]); err != nil || remaining != BackupCodeCount-1 {
+		t.Errorf("Expected new code to work with %d remaining, got %d, %v", BackupCodeCount-1, remaining, err)
+	}
+	if _, _, _, err := authService.LoginWithBackupCode("recovery@example.com", "password123", backupCodes[0]); err != ErrInvalidBackupCode {
+		t.Errorf("Expected ErrInvalidBackupCode for a reused new code, got %v", err)
+	}
+
+	// Only hashes of the codes are stored
+	stored := twoFARepo.twoFAs[user.ID].BackupCodes
+	for _, c := range backupCodes {
+		if strings.Contains(stored, c) {
+			t.Errorf("Expected backup code %s not to be stored in plaintext", c)
+		}
+	}
+	if !strings.Contains(stored, hashBackupCode(backupCodes[1])) {
+		t.Error("Expected stored backup codes to contain the code hashes")
+	}
+}
+
+func TestExtendedAuthService_LoginWithLegacyBackupCode(t *testing.T) {
+	userRepo := newMockUserRepository()
+	twoFARepo := newMockTwoFactorAuthRepository()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:  userRepo,
+		TwoFARepo: twoFARepo,
+		JWTSecret: "test-secret",
+	})
+
+	user, err := authService.Register("legacy@example.com", "password123", "Legacy User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	setup, err := authService.Setup2FA(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to setup 2FA: %v", err)
+	}
+	code, _ := totp.GenerateCode(setup.Secret, time.Now())
+	if err := authService.Verify2FA(user.ID, code); err != nil {
+		t.Fatalf("Failed to verify 2FA: %v", err)
+	}
+
+	// Codes stored in plaintext before hashing was introduced are hashed on
+	// first read, even by a failed attempt, and keep working
+	twoFARepo.twoFAs[user.ID].BackupCodes = `["ABCD2345","EFGH6789"]`
+	if _, _, _, err := authService.LoginWithBackupCode("legacy@example.com", "password123", "WRONG000"); err == nil {
+		t.Error("Expected an unknown backup code to be rejected")
+	}
+	if stored := twoFARepo.twoFAs[user.ID].BackupCodes; strings.Contains(stored, "ABCD2345") || strings.Contains(stored, "EFGH6789") {
+		t.Errorf("Expected legacy codes to be hashed on first read, got %s", stored)
+	}
+	if _, _, remaining, err := authService.LoginWithBackupCode("legacy@example.com", "password123", "abcd-2345"); err != nil || remaining != 1 {
+		t.Errorf("Expected legacy code to work with 1 remaining, got %d, %v", remaining, err)
+	}
+	if _, _, _, err := authService.LoginWithBackupCode("legacy@example.com", "password123", "abcd-2345"); err == nil {
+		t.Error("Expected a used legacy code to be rejected")
+	}
+}
+
+func TestExtendedAuthService_BackupCodeSaveError(t *testing.T) {
+	twoFARepo := newMockTwoFactorAuthRepository()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:   newMockUserRepository(),
+		TwoFARepo:  twoFARepo,
+		JWTSecret:  "test-secret",
+		IssuerName: "TestApp",
+	})
+
+	user, err := authService.Register("save-error@example.com", "password123", "Save Error User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	setup, err := authService.Setup2FA(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to setup 2FA: %v", err)
+	}
+	code, _ := totp.GenerateCode(setup.Secret, time.Now())
+	if err := authService.Verify2FA(user.ID, code); err != nil {
+		t.Fatalf("Failed to verify 2FA: %v", err)
+	}
+
+	// A backup code whose use cannot be saved must not be accepted
+	dbErr := errors.New("db down")
+	twoFARepo.updateErr = dbErr
+	if _, _, err := authService.ValidateLoginWith2FA("save-error@example.com", "password123", setup.BackupCodes[0]); err != dbErr {
+		t.Errorf("Expected the save error from login, got %v", err)
+	}
+	if err := authService.Disable2FA(user.ID, setup.BackupCodes[1]); err != dbErr {
+		t.Errorf("Expected the save error from disabling 2FA, got %v", err)
+	}
+}
+
+func TestExtendedAuthService_TOTPSkew(t *testing.T) {
+	userRepo := newMockUserRepository()
+	twoFARepo := newMockTwoFactorAuthRepository()
+	cfg := AuthServiceConfig{
+		UserRepo:   userRepo,
+		TwoFARepo:  twoFARepo,
+		JWTSecret:  "test-secret",
+		IssuerName: "TestApp",
+	}
+	authService := NewExtendedAuthService(cfg)
+
+	user, err := authService.Register("skew@example.com", "password123", "Skew User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	setup, err := authService.Setup2FA(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to setup 2FA: %v", err)
+	}
+	code, _ := totp.GenerateCode(setup.Secret, time.Now())
+	if err := authService.Verify2FA(user.ID, code); err != nil {
+		t.Fatalf("Failed to verify 2FA: %v", err)
+	}
+
+	// A code from the previous period, as sent by a client whose clock lags
+	previous, _ := totp.GenerateCode(setup.Secret, time.Now().Add(-DefaultTOTPPeriod*time.Second))
+
+	if _, _, err := authService.ValidateLoginWith2FA("skew@example.com", "password123", previous); err != nil {
+		t.Errorf("Expected previous period code to validate with skew 1, got %v", err)
+	}
+
+	strict := NewExtendedAuthService(cfg)
+	strict.(*extendedAuthService).totpSkew = 0
+	if _, _, err := strict.ValidateLoginWith2FA("skew@example.com", "password123", previous); err != Err2FAInvalidCode {
+		t.Errorf("Expected Err2FAInvalidCode with skew 0, got %v", err)
+	}
+}
+
 func TestExtendedAuthService_SessionManagement(t *testing.T) {
 	userRepo := newMockUserRepository()
 	sessionRepo := newMockSessionRepository()
@@ -434,6 +686,108 @@ func TestExtendedAuthService_SessionManagement(t *testing.T) {
 	}
 }
 
+func TestExtendedAuthService_LoginCreatesSession(t *testing.T) {
+	userRepo := newMockUserRepository()
+	sessionRepo := newMockSessionRepository()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:    userRepo,
+		SessionRepo: sessionRepo,
+		JWTSecret:   "test-secret",
+	})
+
+	user, err := authService.Register("login-session@example.com", "password123", "Session User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	ctx := WithRequestMeta(context.Background(), RequestMeta{IPAddress: "203.0.113.7", UserAgent: "Firefox"})
+	_, refreshToken, err := authService.LoginContext(ctx, "login-session@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	sessions, err := authService.GetUserSessions(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get user sessions: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("Expected 1 session after login, got %d", len(sessions))
+	}
+	session := sessions[0]
+	if session.RefreshToken != refreshToken || session.IPAddress != "203.0.113.7" || session.UserAgent != "Firefox" {
+		t.Errorf("Expected the session to hold the login's token and client, got %+v", session)
+	}
+
+	// Revoking it from the session list signs the login out
+	if err := authService.RevokeUserSession(user.ID, session.ID); err != nil {
+		t.Fatalf("Failed to revoke session: %v", err)
+	}
+	if sessions, _ := authService.GetUserSessions(user.ID); len(sessions) != 0 {
+		t.Errorf("Expected no sessions after revoking, got %d", len(sessions))
+	}
+}
+
+func TestExtendedAuthService_RevokeUserSessions(t *testing.T) {
+	userRepo := newMockUserRepository()
+	sessionRepo := newMockSessionRepository()
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:    userRepo,
+		SessionRepo: sessionRepo,
+		TokenStore:  tokenStore,
+		JWTSecret:   "test-secret",
+	})
+
+	user, _ := authService.Register("owner@example.com", "password123", "Owner")
+	other, _ := authService.Register("other@example.com", "password123", "Other")
+
+	current, _, currentToken, _ := authService.CreateSession(user.ID, "Laptop", "127.0.0.1")
+	phone, _, phoneToken, _ := authService.CreateSession(user.ID, "Phone", "127.0.0.2")
+	tablet, _, tabletToken, _ := authService.CreateSession(user.ID, "Tablet", "127.0.0.3")
+	otherSession, _, otherToken, _ := authService.CreateSession(other.ID, "Other", "127.0.0.4")
+
+	if err := authService.RevokeUserSession(user.ID, otherSession.ID); err != ErrSessionForbidden {
+		t.Errorf("Expected ErrSessionForbidden, got %v", err)
+	}
+	if err := authService.RevokeUserSession(user.ID, uuid.New()); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	if err := authService.RevokeUserSession(user.ID, phone.ID); err != nil {
+		t.Fatalf("Failed to revoke session: %v", err)
+	}
+	if _, err := authService.RefreshToken(phoneToken); err == nil {
+		t.Error("Expected revoked session's refresh token to stop working")
+	}
+	if err := authService.RevokeUserSession(user.ID, phone.ID); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound for revoked session, got %v", err)
+	}
+
+	if _, err := authService.RevokeOtherSessions(user.ID, otherToken); err != ErrInvalidToken {
+		t.Errorf("Expected ErrInvalidToken for another user's token, got %v", err)
+	}
+
+	revoked, err := authService.RevokeOtherSessions(user.ID, currentToken)
+	if err != nil {
+		t.Fatalf("Failed to revoke other sessions: %v", err)
+	}
+	if revoked != 1 {
+		t.Errorf("Expected 1 revoked session, got %d", revoked)
+	}
+	if _, err := authService.GetSession(tablet.ID); err == nil {
+		t.Error("Expected tablet session to be revoked")
+	}
+	if _, err := authService.RefreshToken(tabletToken); err == nil {
+		t.Error("Expected tablet refresh token to stop working")
+	}
+	if _, err := authService.GetSession(current.ID); err != nil {
+		t.Errorf("Expected current session to stay active, got %v", err)
+	}
+	if _, err := authService.GetSession(otherSession.ID); err != nil {
+		t.Errorf("Expected other user's session to stay active, got %v", err)
+	}
+}
+
 func TestExtendedAuthService_OAuthLogin(t *testing.T) {
 	userRepo := newMockUserRepository()
 	oauthRepo := newMockOAuthAccountRepository()
@@ -481,6 +835,70 @@ func TestExtendedAuthService_OAuthLogin(t *testing.T) {
 	}
 }
 
+func TestExtendedAuthService_ProfileSync(t *testing.T) {
+	userRepo := newMockUserRepository()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:     userRepo,
+		OAuthRepo:    newMockOAuthAccountRepository(),
+		AuditLogRepo: newMockAuditLogRepository(),
+		JWTSecret:    "test-secret",
+	})
+
+	// A password user without an avatar gets one when a provider is linked
+	user, err := authService.Register("sync@example.com", "password123", "Local Name")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	info := &OAuthUserInfo{
+		Provider:       model.OAuthProviderGitHub,
+		ProviderUserID: "github-sync",
+		Email:          "sync@example.com",
+		Name:           "GitHub Name",
+		AvatarURL:      "https://example.com/first.png",
+	}
+	if _, _, _, err := authService.HandleOAuthLogin(info); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.AvatarURL != "https://example.com/first.png" || user.Name != "Local Name" {
+		t.Errorf("Expected only the avatar to be filled in, got name=%q avatar=%q", user.Name, user.AvatarURL)
+	}
+
+	if _, err := authService.SetProfileSyncProvider(user.ID, model.OAuthProviderGoogle); err != ErrOAuthAccountNotFound {
+		t.Errorf("Expected ErrOAuthAccountNotFound, got %v", err)
+	}
+
+	// Choosing the provider syncs its profile immediately
+	synced, err := authService.SetProfileSyncProvider(user.ID, model.OAuthProviderGitHub)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if synced.Name != "GitHub Name" || synced.ProfileSyncProvider != model.OAuthProviderGitHub {
+		t.Errorf("Expected profile to be synced from GitHub, got %+v", synced)
+	}
+
+	// Later logins keep the profile in sync
+	info.Name = "Renamed"
+	info.AvatarURL = "https://example.com/second.png"
+	if _, _, _, err := authService.HandleOAuthLogin(info); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Name != "Renamed" || user.AvatarURL != "https://example.com/second.png" {
+		t.Errorf("Expected profile to follow GitHub, got name=%q avatar=%q", user.Name, user.AvatarURL)
+	}
+
+	// Turning syncing off leaves the profile alone on login
+	if _, err := authService.SetProfileSyncProvider(user.ID, ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	info.Name = "Ignored"
+	if _, _, _, err := authService.HandleOAuthLogin(info); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Name != "Renamed" {
+		t.Errorf("Expected name to stay 'Renamed', got %q", user.Name)
+	}
+}
+
 func TestExtendedAuthService_AuditLogging(t *testing.T) {
 	userRepo := newMockUserRepository()
 	auditRepo := newMockAuditLogRepository()
@@ -511,6 +929,98 @@ func TestExtendedAuthService_AuditLogging(t *testing.T) {
 	}
 }
 
+func TestExtendedAuthService_AuditLoggingRequestMeta(t *testing.T) {
+	userRepo := newMockUserRepository()
+	auditRepo := newMockAuditLogRepository()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:     userRepo,
+		AuditLogRepo: auditRepo,
+		JWTSecret:    "test-secret",
+	})
+
+	meta := RequestMeta{IPAddress: "203.0.113.7", UserAgent: "test-agent/1.0"}
+	ctx := WithRequestMeta(context.Background(), meta)
+
+	if _, err := authService.RegisterContext(ctx, "meta@example.com", "password123", "Meta User"); err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	if _, _, err := authService.LoginContext(ctx, "meta@example.com", "wrongpassword"); err != ErrInvalidCredentials {
+		t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+	}
+	_, refreshToken, err := authService.LoginContext(ctx, "meta@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to login: %v", err)
+	}
+	if _, err := authService.RefreshTokenContext(ctx, refreshToken); err != nil {
+		t.Fatalf("Failed to refresh token: %v", err)
+	}
+	if err := authService.LogoutContext(ctx, refreshToken); err != nil {
+		t.Fatalf("Failed to logout: %v", err)
+	}
+
+	want := []model.AuditAction{
+		model.AuditActionRegister,
+		model.AuditActionFailedLogin,
+		model.AuditActionLogin,
+		model.AuditActionTokenRefresh,
+		model.AuditActionLogout,
+	}
+	if len(auditRepo.logs) != len(want) {
+		t.Fatalf("Expected %d audit logs, got %d", len(want), len(auditRepo.logs))
+	}
+	for i, log := range auditRepo.logs {
+		if log.Action != want[i] {
+			t.Errorf("Log %d: expected action %s, got %s", i, want[i], log.Action)
+		}
+		if log.IPAddress != meta.IPAddress {
+			t.Errorf("Log %d (%s): expected IP %q, got %q", i, log.Action, meta.IPAddress, log.IPAddress)
+		}
+		if log.UserAgent != meta.UserAgent {
+			t.Errorf("Log %d (%s): expected user agent %q, got %q", i, log.Action, meta.UserAgent, log.UserAgent)
+		}
+	}
+}
+
+func TestExtendedAuthService_LogoutRevokesSession(t *testing.T) {
+	sessionRepo := newMockSessionRepository()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:    newMockUserRepository(),
+		SessionRepo: sessionRepo,
+		JWTSecret:   "test-secret",
+	})
+
+	user, err := authService.Register("logout-session@example.com", "password123", "Logout User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	_, refreshToken, err := authService.Login("logout-session@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to login: %v", err)
+	}
+	_, otherRefreshToken, err := authService.Login("logout-session@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to login: %v", err)
+	}
+
+	if err := authService.Logout(refreshToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sessions, err := authService.GetUserSessions(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to list sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].RefreshToken != otherRefreshToken {
+		t.Errorf("Expected only the other login's session to be listed, got %d sessions", len(sessions))
+	}
+	if _, err := authService.RefreshToken(refreshToken); err != ErrRefreshTokenNotFound {
+		t.Errorf("Expected the logged out refresh token to be rejected, got %v", err)
+	}
+	if _, err := authService.RefreshToken(otherRefreshToken); err != nil {
+		t.Errorf("Expected the other session to still refresh, got %v", err)
+	}
+}
+
 func TestExtendedAuthService_Logout(t *testing.T) {
 	userRepo := newMockUserRepository()
 	authService := NewExtendedAuthService(AuthServiceConfig{
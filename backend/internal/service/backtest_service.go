@@ -0,0 +1,208 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/lib/calculations"
+	"github.com/google/uuid"
+)
+
+// backtestHistoryLimit bounds how many bars a backtest loads for a symbol.
+const backtestHistoryLimit = 5000
+
+// Backtest errors
+var (
+	ErrInvalidBacktestStrategy  = errors.New("strategy type must be one of: sma_crossover, rsi, macd")
+	ErrInvalidBacktestParams    = errors.New("invalid strategy params")
+	ErrInvalidBacktestRange     = errors.New("end_date must not be before start_date")
+	ErrInvalidBacktestCapital   = errors.New("initial_capital must be greater than 0")
+	ErrInsufficientBacktestData = errors.New("not enough price history in the backtest range")
+	ErrBacktestNotFound         = errors.New("backtest result not found")
+	ErrBacktestsUnavailable     = errors.New("backtests are not available")
+)
+
+// BacktestConfig describes a backtest run.
+type BacktestConfig struct {
+	Symbol         string
+	StartDate      time.Time
+	EndDate        time.Time
+	InitialCapital float64
+	StrategyName   string
+	StrategyType   model.BacktestStrategyType
+	Params         map[string]float64
+}
+
+// Validate checks the strategy is known and the range and capital are usable.
+func (c BacktestConfig) Validate() error {
+	if !c.StrategyType.IsValid() {
+		return ErrInvalidBacktestStrategy
+	}
+	if c.EndDate.Before(c.StartDate) {
+		return ErrInvalidBacktestRange
+	}
+	if c.InitialCapital <= 0 {
+		return ErrInvalidBacktestCapital
+	}
+	return nil
+}
+
+// BacktestService defines the interface for running and storing backtests.
+type BacktestService interface {
+	// Run simulates the strategy over the symbol's stored daily history and
+	// stores the result.
+	Run(config BacktestConfig) (*model.BacktestResult, error)
+	GetResult(id uuid.UUID) (*model.BacktestResult, error)
+	ListResults(limit int) ([]model.BacktestResult, error)
+}
+
+// backtestService implements BacktestService.
+type backtestService struct {
+	history PriceHistorySource
+	repo    repository.BacktestResultRepository
+}
+
+// NewBacktestService creates a new BacktestService instance.
+func NewBacktestService(history PriceHistorySource, repo repository.BacktestResultRepository) BacktestService {
+	return &backtestService{history: history, repo: repo}
+}
+
+// Run loads the symbol's history, simulates the strategy and stores the result.
+func (s *backtestService) Run(config BacktestConfig) (*model.BacktestResult, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if s.history == nil {
+		return nil, ErrInsufficientBacktestData
+	}
+
+	bars, err := s.history.GetPriceHistory(config.Symbol, backtestHistoryLimit)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInsufficientBacktestData
+		}
+		return nil, err
+	}
+
+	result, err := SimulateBacktest(config, bars)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetResult retrieves a stored backtest result.
+func (s *backtestService) GetResult(id uuid.UUID) (*model.BacktestResult, error) {
+	result, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrBacktestNotFound
+	}
+	return result, nil
+}
+
+// ListResults retrieves the most recently completed backtest results.
+func (s *backtestService) ListResults(limit int) ([]model.BacktestResult, error) {
+	return s.repo.List(limit)
+}
+
+// SimulateBacktest trades the strategy over the bars between the config's
+// start and end dates, in any order. Signals fill at the bar's close: a buy
+// spends all cash on whole shares and a sell closes the whole position. A
+// position still open after the last bar is closed at its close.
+func SimulateBacktest(config BacktestConfig, bars []model.StockPrice) (*model.BacktestResult, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	end := config.EndDate.AddDate(0, 0, 1)
+	var inRange []model.StockPrice
+	for _, bar := range sortedByTime(bars) {
+		if !bar.Timestamp.Before(config.StartDate) && bar.Timestamp.Before(end) {
+			inRange = append(inRange, bar)
+		}
+	}
+	if len(inRange) < 2 {
+		return nil, ErrInsufficientBacktestData
+	}
+
+	closes := make([]float64, len(inRange))
+	for i, bar := range inRange {
+		closes[i] = bar.Close
+	}
+	params := backtestParams(config.StrategyType, config.Params)
+	signals, err := strategySignals(config.StrategyType, params, closes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.BacktestResult{
+		ID:             uuid.New(),
+		Symbol:         config.Symbol,
+		StrategyName:   config.StrategyName,
+		StrategyType:   config.StrategyType,
+		Params:         params,
+		StartDate:      config.StartDate,
+		EndDate:        config.EndDate,
+		InitialCapital: config.InitialCapital,
+		EquityCurve:    make([]model.EquityPoint, 0, len(inRange)),
+	}
+
+	cash := config.InitialCapital
+	var shares, entryCost float64
+	closePosition := func(price float64) {
+		proceeds := shares * price
+		result.TotalTrades++
+		if proceeds > entryCost {
+			result.WinningTrades++
+		} else {
+			result.LosingTrades++
+		}
+		cash += proceeds
+		shares, entryCost = 0, 0
+	}
+
+	values := make([]float64, len(inRange))
+	for i, bar := range inRange {
+		switch signals[i] {
+		case signalBuy:
+			if shares == 0 && bar.Close > 0 {
+				if qty := math.Floor(cash / bar.Close); qty > 0 {
+					shares = qty
+					entryCost = qty * bar.Close
+					cash -= entryCost
+				}
+			}
+		case signalSell:
+			if shares > 0 {
+				closePosition(bar.Close)
+			}
+		}
+		if i == len(inRange)-1 && shares > 0 {
+			closePosition(bar.Close)
+		}
+
+		values[i] = cash + shares*bar.Close
+		result.EquityCurve = append(result.EquityCurve, model.EquityPoint{
+			Timestamp: bar.Timestamp,
+			Value:     values[i],
+		})
+	}
+
+	result.FinalValue = cash
+	result.TotalReturn = result.FinalValue - config.InitialCapital
+	result.TotalReturnPercent = result.TotalReturn / config.InitialCapital * 100
+	if result.TotalTrades > 0 {
+		result.WinRate = float64(result.WinningTrades) / float64(result.TotalTrades) * 100
+	}
+	result.SharpeRatio = calculations.SharpeRatio(periodReturns(values), 0)
+	result.MaxDrawdown = calculations.MaxDrawdown(values)
+	result.CompletedAt = time.Now()
+
+	return result, nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/lib/calculations"
+	"github.com/google/uuid"
+)
+
+var backtestStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// dailyBars returns one bar per day from backtestStart, newest first like
+// PriceHistorySource.
+func dailyBars(closes []float64) []model.StockPrice {
+	bars := make([]model.StockPrice, len(closes))
+	for i, c := range closes {
+		bars[len(closes)-1-i] = model.StockPrice{Timestamp: backtestStart.AddDate(0, 0, i), Close: c}
+	}
+	return bars
+}
+
+func smaCrossoverConfig(days int) BacktestConfig {
+	return BacktestConfig{
+		Symbol:         "AAPL",
+		StartDate:      backtestStart,
+		EndDate:        backtestStart.AddDate(0, 0, days-1),
+		InitialCapital: 1000,
+		StrategyName:   "fast cross",
+		StrategyType:   model.BacktestStrategySMACrossover,
+		Params:         map[string]float64{"fast_period": 2, "slow_period": 3},
+	}
+}
+
+func TestSimulateBacktest_SMACrossover(t *testing.T) {
+	// SMA(2) crosses above SMA(3) on days 3 and 10 and below on days 6 and 14.
+	// The first round trip buys 83 shares at 12 and sells at 11; the second
+	// buys 83 at 11 and sells at 12.
+	closes := []float64{10, 10, 10, 12, 14, 13, 11, 9, 9, 9, 11, 13, 15, 14, 12}
+	result, err := SimulateBacktest(smaCrossoverConfig(len(closes)), dailyBars(closes))
+	if err != nil {
+		t.Fatalf("SimulateBacktest() error = %v", err)
+	}
+
+	if result.TotalTrades != 2 || result.WinningTrades != 1 || result.LosingTrades != 1 {
+		t.Errorf("SimulateBacktest() trades = %d (%d won, %d lost), want 2 (1 won, 1 lost)",
+			result.TotalTrades, result.WinningTrades, result.LosingTrades)
+	}
+	if result.WinRate != 50 {
+		t.Errorf("SimulateBacktest() win rate = %v, want 50", result.WinRate)
+	}
+	if result.FinalValue != 1000 || result.TotalReturn != 0 {
+		t.Errorf("SimulateBacktest() final value = %v, return = %v, want 1000, 0", result.FinalValue, result.TotalReturn)
+	}
+
+	wantEquity := []float64{1000, 1000, 1000, 1000, 1166, 1083, 917, 917, 917, 917, 917, 1083, 1249, 1166, 1000}
+	if len(result.EquityCurve) != len(wantEquity) {
+		t.Fatalf("SimulateBacktest() equity curve has %d points, want %d", len(result.EquityCurve), len(wantEquity))
+	}
+	for i, want := range wantEquity {
+		point := result.EquityCurve[i]
+		if math.Abs(point.Value-want) > 1e-9 {
+			t.Errorf("equity[%d] = %v, want %v", i, point.Value, want)
+		}
+		if !point.Timestamp.Equal(backtestStart.AddDate(0, 0, i)) {
+			t.Errorf("equity[%d] timestamp = %v, want day %d", i, point.Timestamp, i)
+		}
+	}
+
+	if want := (1166.0 - 917) / 1166 * 100; math.Abs(result.MaxDrawdown-want) > 1e-9 {
+		t.Errorf("SimulateBacktest() max drawdown = %v, want %v", result.MaxDrawdown, want)
+	}
+	if want := calculations.SharpeRatio(periodReturns(wantEquity), 0); math.Abs(result.SharpeRatio-want) > 1e-9 {
+		t.Errorf("SimulateBacktest() sharpe = %v, want %v", result.SharpeRatio, want)
+	}
+}
+
+func TestSimulateBacktest_ClosesOpenPositionAtEnd(t *testing.T) {
+	closes := []float64{10, 10, 10, 12, 14, 16}
+	result, err := SimulateBacktest(smaCrossoverConfig(len(closes)), dailyBars(closes))
+	if err != nil {
+		t.Fatalf("SimulateBacktest() error = %v", err)
+	}
+
+	// 83 shares bought at 12 are sold at the last close of 16
+	if result.TotalTrades != 1 || result.WinningTrades != 1 {
+		t.Errorf("SimulateBacktest() trades = %d (%d won), want 1 (1 won)", result.TotalTrades, result.WinningTrades)
+	}
+	if want := 4 + 83*16.0; result.FinalValue != want {
+		t.Errorf("SimulateBacktest() final value = %v, want %v", result.FinalValue, want)
+	}
+}
+
+func TestSimulateBacktest_FiltersDateRange(t *testing.T) {
+	closes := []float64{10, 10, 10, 12, 14, 16, 20, 30}
+	config := smaCrossoverConfig(6)
+	result, err := SimulateBacktest(config, dailyBars(closes))
+	if err != nil {
+		t.Fatalf("SimulateBacktest() error = %v", err)
+	}
+	if len(result.EquityCurve) != 6 {
+		t.Errorf("SimulateBacktest() equity curve has %d points, want 6", len(result.EquityCurve))
+	}
+
+	config.StartDate = backtestStart.AddDate(0, 0, 7)
+	config.EndDate = config.StartDate
+	if _, err := SimulateBacktest(config, dailyBars(closes)); err != ErrInsufficientBacktestData {
+		t.Errorf("SimulateBacktest() with one bar error = %v, want %v", err, ErrInsufficientBacktestData)
+	}
+}
+
+func TestSimulateBacktest_InvalidConfig(t *testing.T) {
+	bars := dailyBars([]float64{10, 11, 12})
+	tests := []struct {
+		name   string
+		modify func(*BacktestConfig)
+		want   error
+	}{
+		{"unknown strategy", func(c *BacktestConfig) { c.StrategyType = "custom" }, ErrInvalidBacktestStrategy},
+		{"end before start", func(c *BacktestConfig) { c.EndDate = c.StartDate.AddDate(0, 0, -1) }, ErrInvalidBacktestRange},
+		{"no capital", func(c *BacktestConfig) { c.InitialCapital = 0 }, ErrInvalidBacktestCapital},
+		{"slow not above fast", func(c *BacktestConfig) { c.Params = map[string]float64{"fast_period": 3, "slow_period": 3} }, ErrInvalidBacktestParams},
+		{"rsi thresholds crossed", func(c *BacktestConfig) {
+			c.StrategyType = model.BacktestStrategyRSI
+			c.Params = map[string]float64{"oversold": 70, "overbought": 30}
+		}, ErrInvalidBacktestParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := smaCrossoverConfig(3)
+			tt.modify(&config)
+			if _, err := SimulateBacktest(config, bars); err != tt.want {
+				t.Errorf("SimulateBacktest() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulateBacktest_RSIAndMACD(t *testing.T) {
+	// vShape falls by 1 a day then recovers by 2 a day. RSI buys once it
+	// reads oversold during the fall and sells once the recovery makes it
+	// overbought; MACD buys when the recovery crosses it above its signal
+	// line and holds to the end.
+	vShape := func(down, up int) []float64 {
+		var closes []float64
+		for i := 0; i < down; i++ {
+			closes = append(closes, 100-float64(i))
+		}
+		for i := 1; i <= up; i++ {
+			closes = append(closes, 101-float64(down)+2*float64(i))
+		}
+		return closes
+	}
+
+	tests := []struct {
+		strategy model.BacktestStrategyType
+		closes   []float64
+		param    string
+	}{
+		{model.BacktestStrategyRSI, vShape(16, 20), "period"},
+		{model.BacktestStrategyMACD, vShape(50, 30), "signal_period"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			config := smaCrossoverConfig(len(tt.closes))
+			config.StrategyType = tt.strategy
+			config.Params = nil
+			result, err := SimulateBacktest(config, dailyBars(tt.closes))
+			if err != nil {
+				t.Fatalf("SimulateBacktest() error = %v", err)
+			}
+			if result.TotalTrades != 1 || result.WinningTrades != 1 || result.TotalReturn <= 0 {
+				t.Errorf("SimulateBacktest() trades = %d (%d won), return = %v, want one winning trade",
+					result.TotalTrades, result.WinningTrades, result.TotalReturn)
+			}
+			if _, ok := result.Params[tt.param]; !ok {
+				t.Errorf("SimulateBacktest() params = %v, want defaults filled in", result.Params)
+			}
+		})
+	}
+}
+
+func TestSimulateBacktest_EquityCurve(t *testing.T) {
+	// Every strategy gets one point per bar, in time order, starting at the
+	// initial capital even when a signal fills on the first bar.
+	closes := []float64{10, 10, 10, 12, 14, 13, 11, 9, 9, 9, 11, 13, 15, 14, 12}
+	for _, strategy := range []model.BacktestStrategyType{
+		model.BacktestStrategySMACrossover, model.BacktestStrategyRSI, model.BacktestStrategyMACD,
+	} {
+		t.Run(string(strategy), func(t *testing.T) {
+			config := smaCrossoverConfig(len(closes))
+			config.StrategyType = strategy
+			config.Params = map[string]float64{"fast_period": 2, "slow_period": 3, "signal_period": 2, "period": 2}
+			result, err := SimulateBacktest(config, dailyBars(closes))
+			if err != nil {
+				t.Fatalf("SimulateBacktest() error = %v", err)
+			}
+
+			curve := result.EquityCurve
+			if len(curve) != len(closes) {
+				t.Fatalf("SimulateBacktest() equity curve has %d points, want %d", len(curve), len(closes))
+			}
+			if curve[0].Value != config.InitialCapital {
+				t.Errorf("equity[0] = %v, want initial capital %v", curve[0].Value, config.InitialCapital)
+			}
+			for i := 1; i < len(curve); i++ {
+				if !curve[i].Timestamp.After(curve[i-1].Timestamp) {
+					t.Errorf("equity[%d] at %v is not after equity[%d] at %v", i, curve[i].Timestamp, i-1, curve[i-1].Timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestBacktestService_Run(t *testing.T) {
+	closes := []float64{10, 10, 10, 12, 14, 13, 11, 9, 9, 9, 11, 13, 15, 14, 12}
+	repo := repository.NewInMemoryBacktestResultRepository()
+	svc := NewBacktestService(&mockPriceHistory{bars: dailyBars(closes)}, repo)
+
+	result, err := svc.Run(smaCrossoverConfig(len(closes)))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	stored, err := svc.GetResult(result.ID)
+	if err != nil {
+		t.Fatalf("GetResult() error = %v", err)
+	}
+	if stored.TotalTrades != 2 || len(stored.EquityCurve) != len(closes) {
+		t.Errorf("GetResult() = %d trades, %d equity points, want 2, %d", stored.TotalTrades, len(stored.EquityCurve), len(closes))
+	}
+
+	results, err := svc.ListResults(10)
+	if err != nil || len(results) != 1 {
+		t.Errorf("ListResults() = %d results, %v, want 1", len(results), err)
+	}
+}
+
+func TestBacktestService_Run_Errors(t *testing.T) {
+	repo := repository.NewInMemoryBacktestResultRepository()
+
+	svc := NewBacktestService(&mockPriceHistory{err: repository.ErrNotFound}, repo)
+	if _, err := svc.Run(smaCrossoverConfig(3)); err != ErrInsufficientBacktestData {
+		t.Errorf("Run() with unknown symbol error = %v, want %v", err, ErrInsufficientBacktestData)
+	}
+
+	dbErr := errors.New("db down")
+	svc = NewBacktestService(&mockPriceHistory{err: dbErr}, repo)
+	if _, err := svc.Run(smaCrossoverConfig(3)); err != dbErr {
+		t.Errorf("Run() error = %v, want %v", err, dbErr)
+	}
+
+	if _, err := svc.GetResult(uuid.New()); err != ErrBacktestNotFound {
+		t.Errorf("GetResult() error = %v, want %v", err, ErrBacktestNotFound)
+	}
+}
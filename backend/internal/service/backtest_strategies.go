@@ -0,0 +1,200 @@
+package service
+
+import (
+	"math"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+// backtestSignal is a strategy's instruction at the close of one bar.
+type backtestSignal int
+
+const (
+	signalHold backtestSignal = iota
+	signalBuy
+	signalSell
+)
+
+// Default strategy parameters, used when a parameter is omitted.
+var defaultBacktestParams = map[model.BacktestStrategyType]map[string]float64{
+	model.BacktestStrategySMACrossover: {"fast_period": 10, "slow_period": 30},
+	model.BacktestStrategyRSI:          {"period": 14, "oversold": 30, "overbought": 70},
+	model.BacktestStrategyMACD:         {"fast_period": 12, "slow_period": 26, "signal_period": 9},
+}
+
+// backtestParams returns params with defaults filled in for the strategy.
+func backtestParams(strategy model.BacktestStrategyType, params map[string]float64) map[string]float64 {
+	merged := make(map[string]float64, len(defaultBacktestParams[strategy]))
+	for name, value := range defaultBacktestParams[strategy] {
+		merged[name] = value
+		if v, ok := params[name]; ok {
+			merged[name] = v
+		}
+	}
+	return merged
+}
+
+// strategySignals runs the strategy over closes, oldest first, and returns
+// one signal per bar. params must already include defaults.
+func strategySignals(strategy model.BacktestStrategyType, params map[string]float64, closes []float64) ([]backtestSignal, error) {
+	switch strategy {
+	case model.BacktestStrategySMACrossover:
+		fast, slow := int(params["fast_period"]), int(params["slow_period"])
+		if fast < 1 || slow <= fast {
+			return nil, ErrInvalidBacktestParams
+		}
+		return crossoverSignals(sma(closes, fast), sma(closes, slow)), nil
+	case model.BacktestStrategyRSI:
+		period := int(params["period"])
+		oversold, overbought := params["oversold"], params["overbought"]
+		if period < 1 || oversold <= 0 || overbought >= 100 || oversold >= overbought {
+			return nil, ErrInvalidBacktestParams
+		}
+		return thresholdSignals(rsi(closes, period), oversold, overbought), nil
+	case model.BacktestStrategyMACD:
+		fast, slow, signal := int(params["fast_period"]), int(params["slow_period"]), int(params["signal_period"])
+		if fast < 1 || slow <= fast || signal < 1 {
+			return nil, ErrInvalidBacktestParams
+		}
+		line, signalLine := macd(closes, fast, slow, signal)
+		return crossoverSignals(line, signalLine), nil
+	default:
+		return nil, ErrInvalidBacktestStrategy
+	}
+}
+
+// crossoverSignals buys when fast crosses above slow and sells when it
+// crosses below. Bars where either series is still warming up (NaN) hold.
+func crossoverSignals(fast, slow []float64) []backtestSignal {
+	signals := make([]backtestSignal, len(fast))
+	for i := 1; i < len(fast); i++ {
+		if math.IsNaN(fast[i-1]) || math.IsNaN(slow[i-1]) || math.IsNaN(fast[i]) || math.IsNaN(slow[i]) {
+			continue
+		}
+		switch {
+		case fast[i-1] <= slow[i-1] && fast[i] > slow[i]:
+			signals[i] = signalBuy
+		case fast[i-1] >= slow[i-1] && fast[i] < slow[i]:
+			signals[i] = signalSell
+		}
+	}
+	return signals
+}
+
+// thresholdSignals buys when the oscillator is below low and sells when it
+// is above high.
+func thresholdSignals(values []float64, low, high float64) []backtestSignal {
+	signals := make([]backtestSignal, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v):
+		case v < low:
+			signals[i] = signalBuy
+		case v > high:
+			signals[i] = signalSell
+		}
+	}
+	return signals
+}
+
+// sma returns the simple moving average aligned with values; the first
+// period-1 entries are NaN.
+func sma(values []float64, period int) []float64 {
+	out := nanSeries(len(values))
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// ema returns the exponential moving average aligned with values, seeded
+// with the SMA of the first period non-NaN values. Leading NaNs are skipped.
+func ema(values []float64, period int) []float64 {
+	out := nanSeries(len(values))
+	start := 0
+	for start < len(values) && math.IsNaN(values[start]) {
+		start++
+	}
+	if len(values)-start < period {
+		return out
+	}
+
+	var seed float64
+	for _, v := range values[start : start+period] {
+		seed += v
+	}
+	out[start+period-1] = seed / float64(period)
+
+	k := 2 / float64(period+1)
+	for i := start + period; i < len(values); i++ {
+		out[i] = values[i]*k + out[i-1]*(1-k)
+	}
+	return out
+}
+
+// rsi returns Wilder's relative strength index aligned with values; the
+// first period entries are NaN.
+func rsi(values []float64, period int) []float64 {
+	out := nanSeries(len(values))
+	if len(values) <= period {
+		return out
+	}
+
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		change := values[i] - values[i-1]
+		gain += math.Max(change, 0)
+		loss += math.Max(-change, 0)
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+	out[period] = rsiValue(gain, loss)
+
+	for i := period + 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		gain = (gain*float64(period-1) + math.Max(change, 0)) / float64(period)
+		loss = (loss*float64(period-1) + math.Max(-change, 0)) / float64(period)
+		out[i] = rsiValue(gain, loss)
+	}
+	return out
+}
+
+// rsiValue converts average gain and loss into an RSI between 0 and 100.
+func rsiValue(gain, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// macd returns the MACD line (fast EMA minus slow EMA) and its signal line,
+// both aligned with values.
+func macd(values []float64, fast, slow, signal int) ([]float64, []float64) {
+	fastEMA, slowEMA := ema(values, fast), ema(values, slow)
+	line := nanSeries(len(values))
+	for i := range values {
+		if !math.IsNaN(fastEMA[i]) && !math.IsNaN(slowEMA[i]) {
+			line[i] = fastEMA[i] - slowEMA[i]
+		}
+	}
+	return line, ema(line, signal)
+}
+
+// nanSeries returns n NaNs.
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
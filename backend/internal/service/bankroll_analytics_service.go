@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/bankroll"
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidBankrollPeriod is returned for a period other than the
+// BankrollPeriod values.
+var ErrInvalidBankrollPeriod = errors.New("period must be week, month, year or all")
+
+// Bankroll analytics periods. BankrollPeriodMonth is the default.
+const (
+	BankrollPeriodWeek  = "week"
+	BankrollPeriodMonth = "month"
+	BankrollPeriodYear  = "year"
+	BankrollPeriodAll   = "all"
+)
+
+// SettledBetStore provides users' bets. *repository.BetRepository implements it.
+type SettledBetStore interface {
+	GetUserBets(ctx context.Context, userID uuid.UUID, filters repository.BetFilters) ([]model.Bet, error)
+}
+
+// BankrollPoint is a user's bankroll after one change.
+type BankrollPoint struct {
+	Time    time.Time `json:"time"`
+	Balance float64   `json:"balance"`
+	Change  float64   `json:"change"`
+	Reason  string    `json:"reason"`
+}
+
+// BankrollAnalytics is a user's bankroll over a period and the performance
+// of the bets settled in it.
+type BankrollAnalytics struct {
+	Period string `json:"period"`
+	// From is the start of the period; it is omitted for all time.
+	From    *time.Time      `json:"from,omitempty"`
+	History []BankrollPoint `json:"history"`
+	// StartingBalance is the bankroll before the period's first change.
+	StartingBalance float64 `json:"starting_balance"`
+	SettledBets     int     `json:"settled_bets"`
+	// TotalStaked excludes void bets, whose stakes were returned.
+	TotalStaked float64 `json:"total_staked"`
+	Profit      float64 `json:"profit"`
+	// ROI is profit in percent of the starting balance.
+	ROI float64 `json:"roi"`
+	// Yield is profit in percent of the total staked.
+	Yield                float64 `json:"yield"`
+	LongestWinningStreak int     `json:"longest_winning_streak"`
+	LongestLosingStreak  int     `json:"longest_losing_streak"`
+	// CurrentDrawdown is how far the latest balance is below the period's
+	// peak, in percent.
+	CurrentDrawdown float64 `json:"current_drawdown"`
+}
+
+// BankrollAnalyticsService defines the interface for bankroll history and
+// betting performance.
+type BankrollAnalyticsService interface {
+	History(ctx context.Context, userID uuid.UUID, period string) (*BankrollAnalytics, error)
+}
+
+// bankrollAnalyticsService implements BankrollAnalyticsService.
+type bankrollAnalyticsService struct {
+	history repository.BankrollRepository
+	bets    SettledBetStore
+	now     func() time.Time
+}
+
+// NewBankrollAnalyticsService creates a new BankrollAnalyticsService instance.
+func NewBankrollAnalyticsService(history repository.BankrollRepository, bets SettledBetStore) BankrollAnalyticsService {
+	return &bankrollAnalyticsService{history: history, bets: bets, now: time.Now}
+}
+
+// History returns the user's bankroll over the period with statistics for
+// the bets settled in it. An empty period has zero statistics.
+func (s *bankrollAnalyticsService) History(ctx context.Context, userID uuid.UUID, period string) (*BankrollAnalytics, error) {
+	if period == "" {
+		period = BankrollPeriodMonth
+	}
+	now := s.now()
+	var from time.Time
+	switch period {
+	case BankrollPeriodWeek:
+		from = now.AddDate(0, 0, -7)
+	case BankrollPeriodMonth:
+		from = now.AddDate(0, -1, 0)
+	case BankrollPeriodYear:
+		from = now.AddDate(-1, 0, 0)
+	case BankrollPeriodAll:
+	default:
+		return nil, ErrInvalidBankrollPeriod
+	}
+
+	entries, err := s.history.GetByUserID(userID, from, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	bets, err := s.bets.GetUserBets(ctx, userID, repository.BetFilters{Status: BetStatusSettled})
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &BankrollAnalytics{Period: period, History: make([]BankrollPoint, 0, len(entries))}
+	if !from.IsZero() {
+		analytics.From = &from
+	}
+
+	balances := make([]float64, 0, len(entries))
+	for _, entry := range entries {
+		analytics.History = append(analytics.History, BankrollPoint{Time: entry.CreatedAt, Balance: entry.Balance, Change: entry.Change, Reason: entry.Reason})
+		balances = append(balances, entry.Balance)
+	}
+	if len(entries) > 0 {
+		analytics.StartingBalance = entries[0].Balance - entries[0].Change
+	}
+	analytics.CurrentDrawdown = bankroll.CurrentDrawdown(balances)
+
+	settled := make([]model.Bet, 0, len(bets))
+	for _, bet := range bets {
+		if bet.SettledAt != nil && !bet.SettledAt.Before(from) {
+			settled = append(settled, bet)
+		}
+	}
+	sort.SliceStable(settled, func(i, j int) bool {
+		return settled[i].SettledAt.Before(*settled[j].SettledAt)
+	})
+
+	profits := make([]float64, 0, len(settled))
+	for _, bet := range settled {
+		analytics.Profit += bet.Profit
+		if bet.Result != BetResultVoid {
+			analytics.TotalStaked += bet.Stake
+		}
+		profits = append(profits, bet.Profit)
+	}
+	analytics.SettledBets = len(settled)
+	analytics.LongestWinningStreak, analytics.LongestLosingStreak = bankroll.Streaks(profits)
+
+	if analytics.StartingBalance > 0 {
+		analytics.ROI = analytics.Profit / analytics.StartingBalance * 100
+	}
+	if analytics.TotalStaked > 0 {
+		analytics.Yield = analytics.Profit / analytics.TotalStaked * 100
+	}
+	return analytics, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// memoryBankrollHistory serves the bankroll history recorded by a memoryBetStore.
+type memoryBankrollHistory struct {
+	store *memoryBetStore
+}
+
+func (m memoryBankrollHistory) Append(entry *model.BankrollHistory) error {
+	m.store.history = append(m.store.history, *entry)
+	return nil
+}
+
+func (m memoryBankrollHistory) GetByUserID(userID uuid.UUID, from, to time.Time) ([]model.BankrollHistory, error) {
+	var entries []model.BankrollHistory
+	for _, entry := range m.store.history {
+		if entry.UserID == userID && !entry.CreatedAt.Before(from) && (to.IsZero() || entry.CreatedAt.Before(to)) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// seedBettingRecord places and settles a 100 stake bet at noon on each of
+// 1-7 December 2024, settling it six hours later. The results are two wins,
+// three losses, a void and a win, taking a 1000 bankroll to 1050.
+func seedBettingRecord(t *testing.T) (*memoryBetStore, uuid.UUID) {
+	t.Helper()
+	store, svc, userID := bettingFixture(model.Settings{})
+	clock := svc.(*bettingService)
+
+	record := []struct {
+		odds   float64
+		result string
+	}{
+		{2.0, "won"}, {1.5, "won"}, {2.2, "lost"}, {1.8, "lost"}, {2.5, "lost"}, {2.0, "void"}, {3.0, "won"},
+	}
+	for i, r := range record {
+		day := time.Date(2024, 12, i+1, 12, 0, 0, 0, time.UTC)
+		clock.now = func() time.Time { return day }
+		bet, err := svc.PlaceBet(context.Background(), userID, PlaceBetRequest{MatchID: store.matchID, Odds: r.odds, Stake: 100})
+		if err != nil {
+			t.Fatalf("PlaceBet() %d error = %v", i+1, err)
+		}
+		clock.now = func() time.Time { return day.Add(6 * time.Hour) }
+		if _, err := svc.SettleBet(context.Background(), userID, bet.ID, r.result); err != nil {
+			t.Fatalf("SettleBet() %d error = %v", i+1, err)
+		}
+	}
+	return store, userID
+}
+
+func analyticsAt(store *memoryBetStore, now time.Time) BankrollAnalyticsService {
+	svc := NewBankrollAnalyticsService(memoryBankrollHistory{store: store}, store)
+	svc.(*bankrollAnalyticsService).now = func() time.Time { return now }
+	return svc
+}
+
+func TestBankrollAnalyticsService_History(t *testing.T) {
+	store, userID := seedBettingRecord(t)
+	svc := analyticsAt(store, time.Date(2024, 12, 8, 12, 0, 0, 0, time.UTC))
+
+	analytics, err := svc.History(context.Background(), userID, "")
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if analytics.Period != BankrollPeriodMonth || analytics.From == nil {
+		t.Errorf("History() period = %q from %v, want the last month", analytics.Period, analytics.From)
+	}
+	// Seven debits and five credits: every bet but the three losses pays back.
+	if len(analytics.History) != 11 || analytics.History[len(analytics.History)-1].Balance != 1050 {
+		t.Fatalf("History() has %d points ending at %+v, want 11 ending at 1050", len(analytics.History), analytics.History[len(analytics.History)-1])
+	}
+
+	want := BankrollAnalytics{
+		StartingBalance:      1000,
+		SettledBets:          7,
+		TotalStaked:          600,
+		Profit:               50,
+		ROI:                  5,
+		Yield:                50.0 / 600 * 100,
+		LongestWinningStreak: 2,
+		LongestLosingStreak:  3,
+		// The peak was 1150 after the second win.
+		CurrentDrawdown: 100.0 / 1150 * 100,
+	}
+	checkBankrollAnalytics(t, analytics, want)
+}
+
+func TestBankrollAnalyticsService_History_Period(t *testing.T) {
+	store, userID := seedBettingRecord(t)
+	// The last week starts at noon on 5 December, before the fifth bet.
+	svc := analyticsAt(store, time.Date(2024, 12, 12, 12, 0, 0, 0, time.UTC))
+
+	analytics, err := svc.History(context.Background(), userID, BankrollPeriodWeek)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(analytics.History) != 5 {
+		t.Errorf("History() has %d points, want 5", len(analytics.History))
+	}
+	checkBankrollAnalytics(t, analytics, BankrollAnalytics{
+		StartingBalance:      950,
+		SettledBets:          3,
+		TotalStaked:          200,
+		Profit:               100,
+		ROI:                  100.0 / 950 * 100,
+		Yield:                50,
+		LongestWinningStreak: 1,
+		LongestLosingStreak:  1,
+		CurrentDrawdown:      0,
+	})
+
+	all, err := svc.History(context.Background(), userID, BankrollPeriodAll)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if all.From != nil || all.SettledBets != 7 {
+		t.Errorf("History(all) = %+v, want every bet and no start", all)
+	}
+}
+
+func TestBankrollAnalyticsService_History_Empty(t *testing.T) {
+	store, _, userID := bettingFixture(model.Settings{})
+	svc := analyticsAt(store, time.Date(2024, 12, 8, 12, 0, 0, 0, time.UTC))
+
+	analytics, err := svc.History(context.Background(), userID, BankrollPeriodYear)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if analytics.History == nil || len(analytics.History) != 0 {
+		t.Errorf("History() points = %v, want an empty list", analytics.History)
+	}
+	checkBankrollAnalytics(t, analytics, BankrollAnalytics{})
+
+	if _, err := svc.History(context.Background(), userID, "decade"); err != ErrInvalidBankrollPeriod {
+		t.Errorf("History() with an unknown period error = %v, want %v", err, ErrInvalidBankrollPeriod)
+	}
+}
+
+func checkBankrollAnalytics(t *testing.T, got *BankrollAnalytics, want BankrollAnalytics) {
+	t.Helper()
+	for _, f := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"starting balance", got.StartingBalance, want.StartingBalance},
+		{"total staked", got.TotalStaked, want.TotalStaked},
+		{"profit", got.Profit, want.Profit},
+		{"ROI", got.ROI, want.ROI},
+		{"yield", got.Yield, want.Yield},
+		{"current drawdown", got.CurrentDrawdown, want.CurrentDrawdown},
+	} {
+		if math.Abs(f.got-f.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", f.name, f.got, f.want)
+		}
+	}
+	if got.SettledBets != want.SettledBets || got.LongestWinningStreak != want.LongestWinningStreak || got.LongestLosingStreak != want.LongestLosingStreak {
+		t.Errorf("settled bets %d, streaks %d/%d, want %d, %d/%d", got.SettledBets, got.LongestWinningStreak, got.LongestLosingStreak, want.SettledBets, want.LongestWinningStreak, want.LongestLosingStreak)
+	}
+}
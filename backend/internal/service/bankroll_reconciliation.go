@@ -0,0 +1,108 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+// Bankroll history reasons that the reconciliation treats specially. They
+// match the reasons written by BankrollService.
+const (
+	BankrollReasonDeposit        = "Deposit"
+	BankrollReasonWithdrawal     = "Withdrawal"
+	BankrollReasonReset          = "Bankroll reset"
+	BankrollReasonReconciliation = "Reconciliation adjustment"
+)
+
+// BankrollDriftTolerance is the largest difference, in bankroll currency,
+// that is treated as rounding rather than drift.
+const BankrollDriftTolerance = 0.005
+
+// BankrollReconciliation is the result of recomputing a user's bankroll.
+type BankrollReconciliation struct {
+	// Expected is the balance implied by the last reset (or the initial
+	// bankroll), deposits, withdrawals and the user's bets since then.
+	Expected float64 `json:"expected"`
+	// Ledger is the balance of the latest bankroll history entry.
+	Ledger float64 `json:"ledger"`
+	// Recorded is Settings.CurrentBankroll.
+	Recorded float64 `json:"recorded"`
+}
+
+// LedgerDrift returns how far the history ledger is from the expected balance.
+func (r BankrollReconciliation) LedgerDrift() float64 {
+	return r.Expected - r.Ledger
+}
+
+// RecordedDrift returns how far Settings.CurrentBankroll is from the expected
+// balance.
+func (r BankrollReconciliation) RecordedDrift() float64 {
+	return r.Expected - r.Recorded
+}
+
+// NeedsAdjustment reports whether either the ledger or the recorded bankroll
+// has drifted from the expected balance.
+func (r BankrollReconciliation) NeedsAdjustment() bool {
+	return math.Abs(r.LedgerDrift()) > BankrollDriftTolerance ||
+		math.Abs(r.RecordedDrift()) > BankrollDriftTolerance
+}
+
+// ReconcileBankroll recomputes a user's bankroll from their settings, bankroll
+// history and bets. Pending and lost bets cost their stake, won bets return
+// stake times odds, and void and cancelled bets net to zero. Only bets placed
+// after the latest reset are counted.
+func ReconcileBankroll(settings model.Settings, history []model.BankrollHistory, bets []model.Bet) BankrollReconciliation {
+	result := BankrollReconciliation{
+		Expected: settings.InitialBankroll,
+		Ledger:   settings.InitialBankroll,
+		Recorded: settings.CurrentBankroll,
+	}
+
+	var since, latest time.Time
+	for _, entry := range history {
+		if latest.IsZero() || !entry.CreatedAt.Before(latest) {
+			latest = entry.CreatedAt
+			result.Ledger = entry.Balance
+		}
+		if entry.Reason == BankrollReasonReset && !entry.CreatedAt.Before(since) {
+			since = entry.CreatedAt
+			result.Expected = entry.Balance
+		}
+	}
+
+	for _, entry := range history {
+		if entry.CreatedAt.Before(since) {
+			continue
+		}
+		if entry.Reason == BankrollReasonDeposit || entry.Reason == BankrollReasonWithdrawal {
+			result.Expected += entry.Change
+		}
+	}
+
+	for _, bet := range bets {
+		if bet.CreatedAt.Before(since) {
+			continue
+		}
+		result.Expected += betBankrollChange(bet)
+	}
+
+	return result
+}
+
+// betBankrollChange returns the net effect of a bet on the bankroll.
+func betBankrollChange(bet model.Bet) float64 {
+	switch bet.Status {
+	case "cancelled":
+		return 0
+	case "settled":
+		switch bet.Result {
+		case "won":
+			return bet.Stake*bet.Odds - bet.Stake
+		case "void":
+			return 0
+		}
+	}
+	return -bet.Stake
+}
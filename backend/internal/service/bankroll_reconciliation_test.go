@@ -0,0 +1,115 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+func TestReconcileBankroll(t *testing.T) {
+	start := time.Date(2024, 12, 1, 12, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time {
+		return start.Add(time.Duration(hours) * time.Hour)
+	}
+
+	bets := []model.Bet{
+		{Stake: 100, Odds: 2.5, Status: "settled", Result: "won", CreatedAt: at(1)},
+		{Stake: 50, Odds: 1.8, Status: "settled", Result: "lost", CreatedAt: at(2)},
+		{Stake: 20, Odds: 3.0, Status: "settled", Result: "void", CreatedAt: at(3)},
+		{Stake: 30, Odds: 2.0, Status: "pending", CreatedAt: at(4)},
+		{Stake: 40, Odds: 2.0, Status: "cancelled", CreatedAt: at(5)},
+	}
+	// 1000 + 150 - 50 + 0 - 30 + 0
+	consistent := []model.BankrollHistory{
+		{Balance: 900, Change: -100, Reason: "Bet placed", CreatedAt: at(1)},
+		{Balance: 850, Change: -50, Reason: "Bet placed", CreatedAt: at(2)},
+		{Balance: 830, Change: -20, Reason: "Bet placed", CreatedAt: at(3)},
+		{Balance: 800, Change: -30, Reason: "Bet placed", CreatedAt: at(4)},
+		{Balance: 760, Change: -40, Reason: "Bet placed", CreatedAt: at(5)},
+		{Balance: 800, Change: 40, Reason: "Bet cancelled", CreatedAt: at(6)},
+		{Balance: 1050, Change: 250, Reason: "Bet won: Home", CreatedAt: at(7)},
+		{Balance: 1070, Change: 20, Reason: "Bet void: Draw", CreatedAt: at(8)},
+	}
+
+	tests := []struct {
+		name          string
+		settings      model.Settings
+		history       []model.BankrollHistory
+		bets          []model.Bet
+		wantExpected  float64
+		wantLedger    float64
+		wantAdjusting bool
+	}{
+		{
+			name:         "in sync",
+			settings:     model.Settings{InitialBankroll: 1000, CurrentBankroll: 1070},
+			history:      consistent,
+			bets:         bets,
+			wantExpected: 1070,
+			wantLedger:   1070,
+		},
+		{
+			name:          "missing settlement credit",
+			settings:      model.Settings{InitialBankroll: 1000, CurrentBankroll: 820},
+			history:       consistent[:6],
+			bets:          bets,
+			wantExpected:  1070,
+			wantLedger:    800,
+			wantAdjusting: true,
+		},
+		{
+			name:          "stale recorded bankroll",
+			settings:      model.Settings{InitialBankroll: 1000, CurrentBankroll: 1000},
+			history:       consistent,
+			bets:          bets,
+			wantExpected:  1070,
+			wantLedger:    1070,
+			wantAdjusting: true,
+		},
+		{
+			name:     "deposits and reset",
+			settings: model.Settings{InitialBankroll: 1000, CurrentBankroll: 780},
+			history: []model.BankrollHistory{
+				{Balance: 1500, Change: 500, Reason: BankrollReasonDeposit, CreatedAt: at(0)},
+				{Balance: 1000, Change: 0, Reason: BankrollReasonReset, CreatedAt: at(3)},
+				{Balance: 800, Change: -200, Reason: BankrollReasonWithdrawal, CreatedAt: at(3).Add(time.Minute)},
+				{Balance: 770, Change: -30, Reason: "Bet placed", CreatedAt: at(4)},
+			},
+			// Only the pending bet was placed after the reset
+			bets:          bets,
+			wantExpected:  770,
+			wantLedger:    770,
+			wantAdjusting: true,
+		},
+		{
+			name:         "no activity",
+			settings:     model.Settings{InitialBankroll: 1000, CurrentBankroll: 1000},
+			wantExpected: 1000,
+			wantLedger:   1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ReconcileBankroll(tt.settings, tt.history, tt.bets)
+			if math.Abs(result.Expected-tt.wantExpected) > 1e-9 {
+				t.Errorf("Expected balance %v, got %v", tt.wantExpected, result.Expected)
+			}
+			if math.Abs(result.Ledger-tt.wantLedger) > 1e-9 {
+				t.Errorf("Expected ledger %v, got %v", tt.wantLedger, result.Ledger)
+			}
+			if result.NeedsAdjustment() != tt.wantAdjusting {
+				t.Errorf("Expected NeedsAdjustment %v, got %v", tt.wantAdjusting, result.NeedsAdjustment())
+			}
+		})
+	}
+}
+
+func TestBankrollReconciliation_ToleratesRounding(t *testing.T) {
+	result := BankrollReconciliation{Expected: 100.001, Ledger: 100, Recorded: 100.004}
+	if result.NeedsAdjustment() {
+		t.Error("Expected sub-cent differences to be ignored")
+	}
+}
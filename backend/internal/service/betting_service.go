@@ -2,304 +2,226 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"strings"
 	"time"
 
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog"
+)
+
+// Betting errors
+var (
+	ErrBetNotFound       = errors.New("bet not found")
+	ErrInvalidStake      = errors.New("stake must be positive")
+	ErrInvalidBetOdds    = errors.New("odds must be greater than 1")
+	ErrStakeAboveLimit   = errors.New("stake exceeds your max stake per bet")
+	ErrDailyBetLimit     = errors.New("daily bet limit reached")
+	ErrInvalidBetResult  = errors.New("result must be won, lost or void")
+	ErrBetAlreadySettled = errors.New("bet already settled")
+)
+
+// Bet statuses and results. They match those counted by ReconcileBankroll.
+const (
+	BetStatusPending = "pending"
+	BetStatusSettled = "settled"
+
+	BetResultWon  = "won"
+	BetResultLost = "lost"
+	BetResultVoid = "void"
+)
 
-	"super-dashboard/backend/internal/model"
-	"super-dashboard/backend/internal/repository"
+// Bankroll history reasons written by BettingService.
+const (
+	BankrollReasonBetPlaced = "Bet placed"
+	BankrollReasonBetWon    = "Bet won"
+	BankrollReasonBetVoid   = "Bet void"
 )
 
-// BettingService handles betting business logic.
-type BettingService struct {
-	betRepo      *repository.BetRepository
-	bankrollRepo *repository.BankrollHistoryRepository
-	settingsRepo *repository.SettingsRepository
-	matchRepo    *repository.MatchRepository
-	logger       zerolog.Logger
+// BetStore reads and writes bets. *repository.BetRepository implements it.
+type BetStore interface {
+	GetMatch(ctx context.Context, matchID uuid.UUID) (*model.Match, error)
+	GetBetByID(ctx context.Context, id uuid.UUID) (*model.Bet, error)
+	GetUserBets(ctx context.Context, userID uuid.UUID, filters repository.BetFilters) ([]model.Bet, error)
+	CountUserBetsSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+	// PlaceBet stores bet and applies debit to the user's bankroll atomically.
+	PlaceBet(ctx context.Context, bet *model.Bet, debit *model.BankrollHistory) error
+	// SettleBet saves a pending bet's settlement and applies credit, if any,
+	// atomically. It returns repository.ErrBetNotPending if the bet is no
+	// longer pending.
+	SettleBet(ctx context.Context, bet *model.Bet, credit *model.BankrollHistory) error
 }
 
-// NewBettingService creates a new BettingService.
-func NewBettingService(
-	betRepo *repository.BetRepository,
-	bankrollRepo *repository.BankrollHistoryRepository,
-	settingsRepo *repository.SettingsRepository,
-	matchRepo *repository.MatchRepository,
-	logger zerolog.Logger,
-) *BettingService {
-	return &BettingService{
-		betRepo:      betRepo,
-		bankrollRepo: bankrollRepo,
-		settingsRepo: settingsRepo,
-		matchRepo:    matchRepo,
-		logger:       logger.With().Str("service", "betting").Logger(),
-	}
+// BettingSettingsStore provides users' bankroll and staking limits.
+type BettingSettingsStore interface {
+	GetUserSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error)
 }
 
-// PlaceBetRequest represents a bet placement request.
+// PlaceBetRequest holds the details of a bet to place.
 type PlaceBetRequest struct {
-	UserID     uuid.UUID
-	MatchID    uuid.UUID
-	Market     string
-	Selection  string
-	Odds       float64
-	Stake      float64
-	Bookmaker  string
+	MatchID   uuid.UUID
+	Market    string
+	Selection string
+	Bookmaker string
+	Odds      float64
+	Stake     float64
 }
 
-// PlaceBet places a new bet.
-func (s *BettingService) PlaceBet(ctx context.Context, req PlaceBetRequest) (*model.Bet, error) {
-	// Validate stake
-	if req.Stake <= 0 {
-		return nil, fmt.Errorf("stake must be positive")
-	}
-
-	// Get current bankroll
-	currentBalance, err := s.bankrollRepo.GetCurrentBalance(ctx, req.UserID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get bankroll: %w", err)
-	}
-
-	// Check if user has enough balance
-	if req.Stake > currentBalance {
-		return nil, fmt.Errorf("insufficient balance: have %.2f, need %.2f", currentBalance, req.Stake)
-	}
-
-	// Get match details
-	match, err := s.matchRepo.GetByID(ctx, req.MatchID)
-	if err != nil {
-		return nil, fmt.Errorf("match not found: %w", err)
-	}
-
-	// Check if match has started
-	if time.Now().After(match.MatchDate) {
-		return nil, fmt.Errorf("cannot place bet on match that has already started")
-	}
+// BettingService defines the interface for placing and settling bets.
+type BettingService interface {
+	// PlaceBet checks the stake against the user's bankroll and limits,
+	// stores the bet as pending and debits the stake.
+	PlaceBet(ctx context.Context, userID uuid.UUID, req PlaceBetRequest) (*model.Bet, error)
+	// SettleBet settles one of the user's pending bets as won, lost or void
+	// and credits any return to their bankroll.
+	SettleBet(ctx context.Context, userID, betID uuid.UUID, result string) (*model.Bet, error)
+	// ListBets lists the user's bets, newest first, optionally by status.
+	ListBets(ctx context.Context, userID uuid.UUID, status string) ([]model.Bet, error)
+}
 
-	// Create bet
-	bet := &model.Bet{
-		UserID:    req.UserID,
-		MatchID:   req.MatchID,
-		Market:    req.Market,
-		Selection: req.Selection,
-		Odds:      req.Odds,
-		Stake:     req.Stake,
-		Bookmaker: req.Bookmaker,
-		Status:    "pending",
-		PlacedAt:  time.Now(),
-	}
+// bettingService implements BettingService.
+type bettingService struct {
+	bets     BetStore
+	settings BettingSettingsStore
+	now      func() time.Time
+}
 
-	if err := s.betRepo.CreateBet(ctx, bet); err != nil {
-		return nil, fmt.Errorf("failed to create bet: %w", err)
-	}
+// NewBettingService creates a new BettingService instance.
+func NewBettingService(bets BetStore, settings BettingSettingsStore) BettingService {
+	return &bettingService{bets: bets, settings: settings, now: time.Now}
+}
 
-	// Update bankroll
-	newBalance := currentBalance - req.Stake
-	entry := &model.BankrollHistory{
-		UserID:  req.UserID,
-		Balance: newBalance,
-		Change:  -req.Stake,
-		Reason:  fmt.Sprintf("Bet placed on %s vs %s", match.HomeTeam.Name, match.AwayTeam.Name),
+// PlaceBet places a bet. The stake must be within the user's current
+// bankroll and max stake per bet, and the user must not have reached their
+// max daily bets. Limits that are not positive are not enforced.
+func (s *bettingService) PlaceBet(ctx context.Context, userID uuid.UUID, req PlaceBetRequest) (*model.Bet, error) {
+	if req.Stake <= 0 {
+		return nil, ErrInvalidStake
 	}
-
-	if err := s.bankrollRepo.CreateEntry(ctx, entry); err != nil {
-		s.logger.Error().Err(err).Msg("Failed to update bankroll history")
+	if req.Odds <= 1 {
+		return nil, ErrInvalidBetOdds
 	}
 
-	s.logger.Info().
-		Str("bet_id", bet.ID.String()).
-		Float64("stake", req.Stake).
-		Float64("odds", req.Odds).
-		Msg("Bet placed successfully")
-
-	return bet, nil
-}
-
-// SettleBet settles a bet with result.
-func (s *BettingService) SettleBet(ctx context.Context, betID uuid.UUID, result string) error {
-	bet, err := s.betRepo.GetBetByID(ctx, betID)
+	settings, err := s.settings.GetUserSettings(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("bet not found: %w", err)
+		return nil, err
 	}
-
-	if bet.Status == "settled" {
-		return fmt.Errorf("bet already settled")
-	}
-
-	// Calculate profit
-	var profit float64
-	switch result {
-	case "won":
-		profit = bet.Stake * (bet.Odds - 1)
-	case "lost":
-		profit = -bet.Stake
-	case "void":
-		profit = 0
-	default:
-		return fmt.Errorf("invalid result: %s", result)
+	if settings.MaxStakePerBet > 0 && req.Stake > settings.MaxStakePerBet {
+		return nil, ErrStakeAboveLimit
 	}
-
-	// Update bet
-	if err := s.betRepo.SettleBet(ctx, betID, result, profit); err != nil {
-		return fmt.Errorf("failed to settle bet: %w", err)
+	if req.Stake > settings.CurrentBankroll {
+		return nil, ErrInsufficientFunds
 	}
 
-	// Update bankroll if won or void
-	if result == "won" || result == "void" {
-		currentBalance, err := s.bankrollRepo.GetCurrentBalance(ctx, bet.UserID)
+	now := s.now()
+	if settings.MaxDailyBets > 0 {
+		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		placed, err := s.bets.CountUserBetsSince(ctx, userID, startOfDay)
 		if err != nil {
-			return fmt.Errorf("failed to get bankroll: %w", err)
+			return nil, err
 		}
-
-		returnAmount := bet.Stake
-		if result == "won" {
-			returnAmount = bet.Stake * bet.Odds
+		if placed >= int64(settings.MaxDailyBets) {
+			return nil, ErrDailyBetLimit
 		}
-
-		newBalance := currentBalance + returnAmount
-		entry := &model.BankrollHistory{
-			UserID:  bet.UserID,
-			Balance: newBalance,
-			Change:  returnAmount,
-			Reason:  fmt.Sprintf("Bet %s: %s", result, bet.Selection),
-		}
-
-		if err := s.bankrollRepo.CreateEntry(ctx, entry); err != nil {
-			s.logger.Error().Err(err).Msg("Failed to update bankroll history")
-		}
-	}
-
-	s.logger.Info().
-		Str("bet_id", betID.String()).
-		Str("result", result).
-		Float64("profit", profit).
-		Msg("Bet settled")
-
-	return nil
-}
-
-// CalculateRecommendedStake calculates recommended stake based on Kelly Criterion.
-func (s *BettingService) CalculateRecommendedStake(ctx context.Context, userID uuid.UUID, odds, winProbability float64) (float64, error) {
-	// Get user settings
-	settings, err := s.settingsRepo.GetUserSettings(ctx, userID)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get settings: %w", err)
 	}
 
-	// Get current bankroll
-	bankroll, err := s.bankrollRepo.GetCurrentBalance(ctx, userID)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get bankroll: %w", err)
+	if _, err := s.bets.GetMatch(ctx, req.MatchID); err != nil {
+		return nil, ErrMatchNotFound
 	}
 
-	// Kelly Criterion: f* = (bp - q) / b
-	// where b = odds - 1, p = win probability, q = 1 - p
-	b := odds - 1
-	p := winProbability
-	q := 1 - p
-
-	kellyFraction := (b*p - q) / b
-
-	// Apply fractional Kelly for safety (typically 25-50% of Kelly)
-	fractionalKelly := 0.25
-	recommendedFraction := kellyFraction * fractionalKelly
+	bet := &model.Bet{
+		ID:              uuid.New(),
+		UserID:          userID,
+		MatchID:         req.MatchID,
+		Market:          strings.TrimSpace(req.Market),
+		Selection:       strings.TrimSpace(req.Selection),
+		Bookmaker:       strings.TrimSpace(req.Bookmaker),
+		Odds:            req.Odds,
+		Stake:           req.Stake,
+		PotentialReturn: req.Stake * req.Odds,
+		Status:          BetStatusPending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	debit := &model.BankrollHistory{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Change:    -req.Stake,
+		Reason:    BankrollReasonBetPlaced,
+		CreatedAt: now,
+	}
+	if err := s.bets.PlaceBet(ctx, bet, debit); err != nil {
+		return nil, err
+	}
+	BetsPlacedTotal.Inc()
+	return bet, nil
+}
 
-	// Don't bet if Kelly is negative (no edge)
-	if recommendedFraction <= 0 {
-		return 0, nil
+// SettleBet settles a bet. A won bet returns stake times odds, a void bet
+// returns its stake and a lost bet returns nothing.
+func (s *bettingService) SettleBet(ctx context.Context, userID, betID uuid.UUID, result string) (*model.Bet, error) {
+	result = strings.ToLower(strings.TrimSpace(result))
+	if result != BetResultWon && result != BetResultLost && result != BetResultVoid {
+		return nil, ErrInvalidBetResult
 	}
 
-	// Calculate stake
-	recommendedStake := bankroll * recommendedFraction
-
-	// Apply maximum risk per trade limit
-	maxRiskAmount := bankroll * (settings.RiskPerTrade / 100.0)
-	if recommendedStake > maxRiskAmount {
-		recommendedStake = maxRiskAmount
+	bet, err := s.bets.GetBetByID(ctx, betID)
+	if err != nil || bet.UserID != userID {
+		return nil, ErrBetNotFound
 	}
-
-	// Ensure minimum stake
-	minStake := 1.0
-	if recommendedStake < minStake {
-		recommendedStake = minStake
+	if bet.Status != BetStatusPending {
+		return nil, ErrBetAlreadySettled
 	}
 
-	return recommendedStake, nil
-}
-
-// CalculateROI calculates return on investment.
-func (s *BettingService) CalculateROI(ctx context.Context, userID uuid.UUID, period string) (float64, error) {
-	stats, err := s.betRepo.GetBetStats(ctx, userID, period)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get bet stats: %w", err)
+	var profit, credit float64
+	var reason string
+	switch result {
+	case BetResultWon:
+		credit = bet.Stake * bet.Odds
+		profit = credit - bet.Stake
+		reason = BankrollReasonBetWon
+	case BetResultLost:
+		profit = -bet.Stake
+	case BetResultVoid:
+		credit = bet.Stake
+		reason = BankrollReasonBetVoid
+	}
+
+	now := s.now()
+	bet.Status = BetStatusSettled
+	bet.Result = result
+	bet.Profit = profit
+	bet.SettledAt = &now
+	bet.UpdatedAt = now
+
+	var entry *model.BankrollHistory
+	if credit > 0 {
+		entry = &model.BankrollHistory{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Change:    credit,
+			Reason:    reason,
+			CreatedAt: now,
+		}
 	}
-
-	return stats.ROI, nil
-}
-
-// GetBettingPerformance retrieves comprehensive betting performance.
-func (s *BettingService) GetBettingPerformance(ctx context.Context, userID uuid.UUID, period string) (map[string]interface{}, error) {
-	stats, err := s.betRepo.GetBetStats(ctx, userID, period)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get bet stats: %w", err)
+	if err := s.bets.SettleBet(ctx, bet, entry); err != nil {
+		if errors.Is(err, repository.ErrBetNotPending) {
+			return nil, ErrBetAlreadySettled
+		}
+		return nil, err
 	}
-
-	return map[string]interface{}{
-		"total_bets":       stats.TotalBets,
-		"won_bets":         stats.WonBets,
-		"lost_bets":        stats.LostBets,
-		"pending_bets":     stats.PendingBets,
-		"total_stake":      stats.TotalStake,
-		"total_profit":     stats.TotalProfit,
-		"win_rate":         stats.WinRate,
-		"roi":              stats.ROI,
-		"average_odds":     stats.AverageOdds,
-		"average_stake":    stats.AverageStake,
-		"longest_win_streak":  stats.LongestWinStreak,
-		"longest_lose_streak": stats.LongestLoseStreak,
-		"current_streak":   stats.CurrentStreak,
-		"streak_type":      stats.StreakType,
-	}, nil
+	return bet, nil
 }
 
-// CancelBet cancels a pending bet.
-func (s *BettingService) CancelBet(ctx context.Context, betID uuid.UUID) error {
-	bet, err := s.betRepo.GetBetByID(ctx, betID)
-	if err != nil {
-		return fmt.Errorf("bet not found: %w", err)
-	}
-
-	if bet.Status != "pending" {
-		return fmt.Errorf("can only cancel pending bets")
-	}
-
-	// Refund stake to bankroll
-	currentBalance, err := s.bankrollRepo.GetCurrentBalance(ctx, bet.UserID)
+// ListBets lists the user's bets.
+func (s *bettingService) ListBets(ctx context.Context, userID uuid.UUID, status string) ([]model.Bet, error) {
+	bets, err := s.bets.GetUserBets(ctx, userID, repository.BetFilters{Status: status})
 	if err != nil {
-		return fmt.Errorf("failed to get bankroll: %w", err)
-	}
-
-	newBalance := currentBalance + bet.Stake
-	entry := &model.BankrollHistory{
-		UserID:  bet.UserID,
-		Balance: newBalance,
-		Change:  bet.Stake,
-		Reason:  "Bet cancelled",
+		return nil, err
 	}
-
-	if err := s.bankrollRepo.CreateEntry(ctx, entry); err != nil {
-		return fmt.Errorf("failed to update bankroll: %w", err)
-	}
-
-	// Mark bet as cancelled
-	bet.Status = "cancelled"
-	if err := s.betRepo.UpdateBet(ctx, bet); err != nil {
-		return fmt.Errorf("failed to cancel bet: %w", err)
+	if bets == nil {
+		bets = []model.Bet{}
 	}
-
-	s.logger.Info().Str("bet_id", betID.String()).Msg("Bet cancelled")
-
-	return nil
+	return bets, nil
 }
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// memoryBetStore keeps bets, one match and each user's bankroll in memory.
+type memoryBetStore struct {
+	matchID  uuid.UUID
+	bets     map[uuid.UUID]*model.Bet
+	settings map[uuid.UUID]*model.Settings
+	history  []model.BankrollHistory
+}
+
+func newMemoryBetStore() *memoryBetStore {
+	return &memoryBetStore{
+		matchID:  uuid.New(),
+		bets:     make(map[uuid.UUID]*model.Bet),
+		settings: make(map[uuid.UUID]*model.Settings),
+	}
+}
+
+func (m *memoryBetStore) GetUserSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error) {
+	settings, ok := m.settings[userID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	found := *settings
+	return &found, nil
+}
+
+func (m *memoryBetStore) GetMatch(ctx context.Context, matchID uuid.UUID) (*model.Match, error) {
+	if matchID != m.matchID {
+		return nil, errors.New("record not found")
+	}
+	return &model.Match{ID: matchID}, nil
+}
+
+func (m *memoryBetStore) GetBetByID(ctx context.Context, id uuid.UUID) (*model.Bet, error) {
+	bet, ok := m.bets[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	found := *bet
+	return &found, nil
+}
+
+func (m *memoryBetStore) GetUserBets(ctx context.Context, userID uuid.UUID, filters repository.BetFilters) ([]model.Bet, error) {
+	var bets []model.Bet
+	for _, bet := range m.bets {
+		if bet.UserID == userID && (filters.Status == "" || bet.Status == filters.Status) {
+			bets = append(bets, *bet)
+		}
+	}
+	return bets, nil
+}
+
+func (m *memoryBetStore) CountUserBetsSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	for _, bet := range m.bets {
+		if bet.UserID == userID && !bet.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryBetStore) PlaceBet(ctx context.Context, bet *model.Bet, debit *model.BankrollHistory) error {
+	stored := *bet
+	m.bets[bet.ID] = &stored
+	m.record(debit)
+	return nil
+}
+
+func (m *memoryBetStore) SettleBet(ctx context.Context, bet *model.Bet, credit *model.BankrollHistory) error {
+	if m.bets[bet.ID].Status != BetStatusPending {
+		return repository.ErrBetNotPending
+	}
+	stored := *bet
+	m.bets[bet.ID] = &stored
+	if credit != nil {
+		m.record(credit)
+	}
+	return nil
+}
+
+func (m *memoryBetStore) record(entry *model.BankrollHistory) {
+	settings := m.settings[entry.UserID]
+	settings.CurrentBankroll += entry.Change
+	entry.Balance = settings.CurrentBankroll
+	m.history = append(m.history, *entry)
+}
+
+// bettingFixture returns a service for a user with a 1000 bankroll whose
+// clock reads 15:00 on 7 December 2024.
+func bettingFixture(settings model.Settings) (*memoryBetStore, BettingService, uuid.UUID) {
+	store := newMemoryBetStore()
+	userID := uuid.New()
+	settings.UserID = userID
+	settings.CurrentBankroll = 1000
+	store.settings[userID] = &settings
+
+	svc := NewBettingService(store, store)
+	svc.(*bettingService).now = func() time.Time { return time.Date(2024, 12, 7, 15, 0, 0, 0, time.UTC) }
+	return store, svc, userID
+}
+
+func TestBettingService_PlaceBet(t *testing.T) {
+	store, svc, userID := bettingFixture(model.Settings{MaxDailyBets: 10, MaxStakePerBet: 200})
+	placed := BetsPlacedTotal.Value()
+
+	bet, err := svc.PlaceBet(context.Background(), userID, PlaceBetRequest{MatchID: store.matchID, Market: "1X2", Selection: " 1 ", Odds: 2.5, Stake: 100})
+	if err != nil {
+		t.Fatalf("PlaceBet() error = %v", err)
+	}
+	if bet.Status != BetStatusPending || bet.PotentialReturn != 250 || bet.Selection != "1" || bet.UserID != userID {
+		t.Errorf("PlaceBet() = %+v, want a pending bet returning 250", bet)
+	}
+	if _, ok := store.bets[bet.ID]; !ok {
+		t.Error("PlaceBet() did not store the bet")
+	}
+	if len(store.history) != 1 || store.history[0].Change != -100 || store.history[0].Balance != 900 || store.history[0].Reason != BankrollReasonBetPlaced {
+		t.Errorf("bankroll history = %+v, want a 100 debit leaving 900", store.history)
+	}
+	if got := BetsPlacedTotal.Value(); got != placed+1 {
+		t.Errorf("BetsPlacedTotal = %d, want %d", got, placed+1)
+	}
+}
+
+func TestBettingService_PlaceBet_Rejected(t *testing.T) {
+	store, svc, userID := bettingFixture(model.Settings{MaxDailyBets: 10, MaxStakePerBet: 200})
+
+	for _, tt := range []struct {
+		name string
+		req  PlaceBetRequest
+		want error
+	}{
+		{"zero stake", PlaceBetRequest{MatchID: store.matchID, Odds: 2, Stake: 0}, ErrInvalidStake},
+		{"odds of 1", PlaceBetRequest{MatchID: store.matchID, Odds: 1, Stake: 10}, ErrInvalidBetOdds},
+		{"above max stake", PlaceBetRequest{MatchID: store.matchID, Odds: 2, Stake: 250}, ErrStakeAboveLimit},
+		{"unknown match", PlaceBetRequest{MatchID: uuid.New(), Odds: 2, Stake: 10}, ErrMatchNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.PlaceBet(context.Background(), userID, tt.req); err != tt.want {
+				t.Errorf("PlaceBet() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	store.settings[userID].CurrentBankroll = 50
+	if _, err := svc.PlaceBet(context.Background(), userID, PlaceBetRequest{MatchID: store.matchID, Odds: 2, Stake: 60}); err != ErrInsufficientFunds {
+		t.Errorf("PlaceBet() above the bankroll error = %v, want %v", err, ErrInsufficientFunds)
+	}
+	if len(store.bets) != 0 || len(store.history) != 0 {
+		t.Errorf("rejected bets stored %d bets and %d history entries, want none", len(store.bets), len(store.history))
+	}
+}
+
+func TestBettingService_PlaceBet_DailyLimit(t *testing.T) {
+	store, svc, userID := bettingFixture(model.Settings{MaxDailyBets: 2})
+	req := PlaceBetRequest{MatchID: store.matchID, Market: "1X2", Selection: "X", Odds: 3.2, Stake: 10}
+
+	// A bet placed yesterday does not count towards today's limit.
+	yesterday := &model.Bet{ID: uuid.New(), UserID: userID, Status: BetStatusPending, CreatedAt: time.Date(2024, 12, 6, 23, 59, 0, 0, time.UTC)}
+	store.bets[yesterday.ID] = yesterday
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.PlaceBet(context.Background(), userID, req); err != nil {
+			t.Fatalf("PlaceBet() %d error = %v", i+1, err)
+		}
+	}
+	if _, err := svc.PlaceBet(context.Background(), userID, req); err != ErrDailyBetLimit {
+		t.Fatalf("PlaceBet() over the daily limit error = %v, want %v", err, ErrDailyBetLimit)
+	}
+	if len(store.bets) != 3 || store.settings[userID].CurrentBankroll != 980 {
+		t.Errorf("got %d bets and a %v bankroll, want the rejected bet neither stored nor debited", len(store.bets), store.settings[userID].CurrentBankroll)
+	}
+}
+
+func TestBettingService_SettleBet(t *testing.T) {
+	for _, tt := range []struct {
+		result       string
+		profit       float64
+		wantBankroll float64
+		wantReason   string
+	}{
+		{"won", 150, 1150, BankrollReasonBetWon},
+		{"Lost", -100, 900, ""},
+		{"void", 0, 1000, BankrollReasonBetVoid},
+	} {
+		t.Run(tt.result, func(t *testing.T) {
+			store, svc, userID := bettingFixture(model.Settings{})
+			bet, err := svc.PlaceBet(context.Background(), userID, PlaceBetRequest{MatchID: store.matchID, Odds: 2.5, Stake: 100})
+			if err != nil {
+				t.Fatalf("PlaceBet() error = %v", err)
+			}
+
+			settled, err := svc.SettleBet(context.Background(), userID, bet.ID, tt.result)
+			if err != nil {
+				t.Fatalf("SettleBet() error = %v", err)
+			}
+			if settled.Status != BetStatusSettled || settled.SettledAt == nil || math.Abs(settled.Profit-tt.profit) > 1e-9 {
+				t.Errorf("SettleBet() = %+v, want settled with profit %v", settled, tt.profit)
+			}
+			if got := store.settings[userID].CurrentBankroll; math.Abs(got-tt.wantBankroll) > 1e-9 {
+				t.Errorf("bankroll = %v, want %v", got, tt.wantBankroll)
+			}
+			last := store.history[len(store.history)-1]
+			if tt.wantReason == "" && len(store.history) != 1 {
+				t.Errorf("bankroll history = %+v, want no credit", store.history)
+			}
+			if tt.wantReason != "" && (last.Reason != tt.wantReason || last.Balance != tt.wantBankroll) {
+				t.Errorf("last bankroll entry = %+v, want %q leaving %v", last, tt.wantReason, tt.wantBankroll)
+			}
+		})
+	}
+}
+
+func TestBettingService_SettleBet_Rejected(t *testing.T) {
+	store, svc, userID := bettingFixture(model.Settings{})
+	bet, err := svc.PlaceBet(context.Background(), userID, PlaceBetRequest{MatchID: store.matchID, Odds: 2, Stake: 10})
+	if err != nil {
+		t.Fatalf("PlaceBet() error = %v", err)
+	}
+
+	if _, err := svc.SettleBet(context.Background(), userID, bet.ID, "half-won"); err != ErrInvalidBetResult {
+		t.Errorf("SettleBet() with an unknown result error = %v, want %v", err, ErrInvalidBetResult)
+	}
+	if _, err := svc.SettleBet(context.Background(), uuid.New(), bet.ID, "won"); err != ErrBetNotFound {
+		t.Errorf("SettleBet() of another user's bet error = %v, want %v", err, ErrBetNotFound)
+	}
+	if _, err := svc.SettleBet(context.Background(), userID, bet.ID, "won"); err != nil {
+		t.Fatalf("SettleBet() error = %v", err)
+	}
+	if _, err := svc.SettleBet(context.Background(), userID, bet.ID, "lost"); err != ErrBetAlreadySettled {
+		t.Errorf("SettleBet() twice error = %v, want %v", err, ErrBetAlreadySettled)
+	}
+}
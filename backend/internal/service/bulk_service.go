@@ -0,0 +1,294 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// MaxBulkItems is the most items a single bulk request may contain.
+const MaxBulkItems = 100
+
+// Bulk service errors.
+var (
+	ErrEmptyBulkRequest  = errors.New("at least one item is required")
+	ErrTooManyBulkItems  = fmt.Errorf("at most %d items are allowed per request", MaxBulkItems)
+	ErrWatchlistNotFound = errors.New("watchlist not found")
+	ErrInvalidSymbol     = errors.New("symbol is required")
+	ErrDuplicateItem     = errors.New("duplicate item")
+	ErrNotStockAlert     = errors.New("alert type must be a stock alert type")
+)
+
+// BulkItemStatus is the outcome of one item in a bulk request.
+type BulkItemStatus string
+
+const (
+	BulkItemCreated BulkItemStatus = "created"
+	// BulkItemSkipped marks an item that already exists or repeats an
+	// earlier item in the same request.
+	BulkItemSkipped BulkItemStatus = "skipped"
+	BulkItemFailed  BulkItemStatus = "failed"
+)
+
+// BulkItemResult reports the outcome of one item, in request order.
+type BulkItemResult struct {
+	Index  int            `json:"index"`
+	Symbol string         `json:"symbol"`
+	Status BulkItemStatus `json:"status"`
+	ID     *uuid.UUID     `json:"id,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// BulkResult summarizes a bulk request.
+type BulkResult struct {
+	Created int              `json:"created"`
+	Skipped int              `json:"skipped"`
+	Failed  int              `json:"failed"`
+	Items   []BulkItemResult `json:"items"`
+}
+
+// stockAlertTypes are the alert types whose symbol is a stock symbol.
+var stockAlertTypes = map[model.AlertType]bool{
+	model.AlertTypeStockPrice:  true,
+	model.AlertTypeStockVolume: true,
+	model.AlertTypeTechnical:   true,
+	model.AlertTypeNews:        true,
+	model.AlertTypeDividend:    true,
+	model.AlertTypeEarnings:    true,
+	model.AlertTypeFairValue:   true,
+}
+
+// BulkService defines the interface for adding many watchlist items or alerts
+// in one request.
+type BulkService interface {
+	// AddWatchlistItems adds the symbols to one of the user's watchlists.
+	AddWatchlistItems(userID, watchlistID uuid.UUID, symbols []string) (*BulkResult, error)
+	// CreateAlerts creates stock alerts for the user. The alerts' IDs, user
+	// and active flag are set by the service.
+	CreateAlerts(userID uuid.UUID, alerts []model.Alert) (*BulkResult, error)
+}
+
+// bulkService implements BulkService.
+type bulkService struct {
+	bulkRepo repository.BulkRepository
+}
+
+// NewBulkService creates a new BulkService instance.
+func NewBulkService(bulkRepo repository.BulkRepository) BulkService {
+	return &bulkService{bulkRepo: bulkRepo}
+}
+
+// AddWatchlistItems validates every symbol against the stock table and adds
+// the valid ones in a single transaction. Symbols already in the watchlist or
+// repeated in the request are skipped.
+func (s *bulkService) AddWatchlistItems(userID, watchlistID uuid.UUID, symbols []string) (*BulkResult, error) {
+	if err := checkBulkSize(len(symbols)); err != nil {
+		return nil, err
+	}
+
+	watchlist, err := s.bulkRepo.GetWatchlist(watchlistID)
+	if err != nil || watchlist.UserID != userID {
+		return nil, ErrWatchlistNotFound
+	}
+
+	results := make([]BulkItemResult, len(symbols))
+	for i, symbol := range symbols {
+		results[i] = BulkItemResult{Index: i, Symbol: repository.NormalizeSymbol(symbol)}
+	}
+
+	stocks, err := s.lookupStocks(results)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(watchlist.Items))
+	for _, item := range watchlist.Items {
+		seen[item.StockID] = true
+	}
+
+	now := time.Now()
+	var items []model.WatchlistItem
+	var pending []int
+	for i := range results {
+		stock, ok := s.checkSymbol(&results[i], stocks)
+		if !ok {
+			continue
+		}
+		if seen[stock.ID] {
+			skipBulkItem(&results[i])
+			continue
+		}
+		seen[stock.ID] = true
+
+		id := uuid.New()
+		results[i].ID = &id
+		items = append(items, model.WatchlistItem{ID: id, WatchlistID: watchlistID, StockID: stock.ID, AddedAt: now})
+		pending = append(pending, i)
+	}
+
+	if err := s.bulkRepo.AddWatchlistItems(items); err != nil {
+		return nil, err
+	}
+	return summarizeBulk(results, pending), nil
+}
+
+// CreateAlerts validates every alert and creates the valid ones in a single
+// transaction. Alerts identical to an active alert or to an earlier alert in
+// the request are skipped.
+func (s *bulkService) CreateAlerts(userID uuid.UUID, alerts []model.Alert) (*BulkResult, error) {
+	if err := checkBulkSize(len(alerts)); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkItemResult, len(alerts))
+	for i := range alerts {
+		results[i] = BulkItemResult{Index: i, Symbol: repository.NormalizeSymbol(alerts[i].Symbol)}
+	}
+
+	stocks, err := s.lookupStocks(results)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.bulkRepo.GetActiveAlerts(userID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(existing))
+	for i := range existing {
+		seen[alertKey(&existing[i])] = true
+	}
+
+	now := time.Now()
+	var created []model.Alert
+	var pending []int
+	for i := range results {
+		alert := alerts[i]
+		if err := alert.Validate(); err != nil {
+			failBulkItem(&results[i], err)
+			continue
+		}
+		if !stockAlertTypes[alert.Type] {
+			failBulkItem(&results[i], ErrNotStockAlert)
+			continue
+		}
+		stock, ok := s.checkSymbol(&results[i], stocks)
+		if !ok {
+			continue
+		}
+
+		alert.ID = uuid.New()
+		alert.UserID = userID
+		alert.Symbol = stock.Symbol
+		alert.Active = true
+		alert.LastTriggered = nil
+		alert.TriggerCount = 0
+		alert.CreatedAt = now
+		alert.UpdatedAt = now
+
+		key := alertKey(&alert)
+		if seen[key] {
+			skipBulkItem(&results[i])
+			continue
+		}
+		seen[key] = true
+
+		results[i].ID = &alert.ID
+		created = append(created, alert)
+		pending = append(pending, i)
+	}
+
+	if err := s.bulkRepo.CreateAlerts(created); err != nil {
+		return nil, err
+	}
+	return summarizeBulk(results, pending), nil
+}
+
+// lookupStocks loads the stocks for the results' symbols, keyed by normalized symbol.
+func (s *bulkService) lookupStocks(results []BulkItemResult) (map[string]model.Stock, error) {
+	symbols := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Symbol != "" {
+			symbols = append(symbols, result.Symbol)
+		}
+	}
+
+	stocks, err := s.bulkRepo.GetStocksBySymbols(symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	bySymbol := make(map[string]model.Stock, len(stocks))
+	for _, stock := range stocks {
+		bySymbol[repository.NormalizeSymbol(stock.Symbol)] = stock
+	}
+	return bySymbol, nil
+}
+
+// checkSymbol returns the stock for the result's symbol, or marks the result
+// failed when the symbol is empty or unknown.
+func (s *bulkService) checkSymbol(result *BulkItemResult, stocks map[string]model.Stock) (model.Stock, bool) {
+	if result.Symbol == "" {
+		failBulkItem(result, ErrInvalidSymbol)
+		return model.Stock{}, false
+	}
+	stock, ok := stocks[result.Symbol]
+	if !ok {
+		failBulkItem(result, ErrStockNotFound)
+		return model.Stock{}, false
+	}
+	return stock, true
+}
+
+// checkBulkSize rejects empty and oversized bulk requests.
+func checkBulkSize(n int) error {
+	if n == 0 {
+		return ErrEmptyBulkRequest
+	}
+	if n > MaxBulkItems {
+		return ErrTooManyBulkItems
+	}
+	return nil
+}
+
+// summarizeBulk marks the pending results created and counts the outcomes.
+func summarizeBulk(results []BulkItemResult, pending []int) *BulkResult {
+	for _, i := range pending {
+		results[i].Status = BulkItemCreated
+	}
+
+	summary := &BulkResult{Items: results}
+	for _, result := range results {
+		switch result.Status {
+		case BulkItemCreated:
+			summary.Created++
+		case BulkItemSkipped:
+			summary.Skipped++
+		default:
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+// failBulkItem marks a result failed with err.
+func failBulkItem(result *BulkItemResult, err error) {
+	result.Status = BulkItemFailed
+	result.ID = nil
+	result.Error = err.Error()
+}
+
+// skipBulkItem marks a result skipped as a duplicate.
+func skipBulkItem(result *BulkItemResult) {
+	result.Status = BulkItemSkipped
+	result.Error = ErrDuplicateItem.Error()
+}
+
+// alertKey identifies alerts that would always trigger together.
+func alertKey(alert *model.Alert) string {
+	return fmt.Sprintf("%s|%s|%s|%g", alert.Type, repository.NormalizeSymbol(alert.Symbol), alert.Condition, alert.TargetValue)
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// mockBulkRepository stores watchlist items and alerts in memory.
+type mockBulkRepository struct {
+	stocks     []model.Stock
+	watchlists map[uuid.UUID]*model.Watchlist
+	alerts     []model.Alert
+	saveErr    error
+}
+
+func newMockBulkRepository(stocks ...model.Stock) *mockBulkRepository {
+	return &mockBulkRepository{stocks: stocks, watchlists: make(map[uuid.UUID]*model.Watchlist)}
+}
+
+func (m *mockBulkRepository) GetStocksBySymbols(symbols []string) ([]model.Stock, error) {
+	var stocks []model.Stock
+	for _, stock := range m.stocks {
+		for _, symbol := range symbols {
+			if stock.Symbol == symbol {
+				stocks = append(stocks, stock)
+				break
+			}
+		}
+	}
+	return stocks, nil
+}
+
+func (m *mockBulkRepository) GetWatchlist(watchlistID uuid.UUID) (*model.Watchlist, error) {
+	watchlist, ok := m.watchlists[watchlistID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return watchlist, nil
+}
+
+func (m *mockBulkRepository) AddWatchlistItems(items []model.WatchlistItem) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	for _, item := range items {
+		watchlist := m.watchlists[item.WatchlistID]
+		watchlist.Items = append(watchlist.Items, item)
+	}
+	return nil
+}
+
+func (m *mockBulkRepository) GetActiveAlerts(userID uuid.UUID) ([]model.Alert, error) {
+	var alerts []model.Alert
+	for _, alert := range m.alerts {
+		if alert.UserID == userID && alert.Active {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+func (m *mockBulkRepository) CreateAlerts(alerts []model.Alert) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.alerts = append(m.alerts, alerts...)
+	return nil
+}
+
+func TestBulkService_AddWatchlistItems(t *testing.T) {
+	aapl := model.Stock{ID: uuid.New(), Symbol: "AAPL"}
+	msft := model.Stock{ID: uuid.New(), Symbol: "MSFT"}
+	brk := model.Stock{ID: uuid.New(), Symbol: "BRK.B"}
+	repo := newMockBulkRepository(aapl, msft, brk)
+
+	userID := uuid.New()
+	watchlist := &model.Watchlist{ID: uuid.New(), UserID: userID, Items: []model.WatchlistItem{{StockID: msft.ID}}}
+	repo.watchlists[watchlist.ID] = watchlist
+	svc := NewBulkService(repo)
+
+	result, err := svc.AddWatchlistItems(userID, watchlist.ID, []string{"aapl", "MSFT", "BRK-B", "AAPL.US", "NOPE", ""})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []struct {
+		symbol string
+		status BulkItemStatus
+	}{
+		{"AAPL", BulkItemCreated},
+		{"MSFT", BulkItemSkipped},
+		{"BRK.B", BulkItemCreated},
+		{"AAPL", BulkItemSkipped},
+		{"NOPE", BulkItemFailed},
+		{"", BulkItemFailed},
+	}
+	for i, w := range want {
+		item := result.Items[i]
+		if item.Index != i || item.Symbol != w.symbol || item.Status != w.status {
+			t.Errorf("Item %d: expected %s %s, got %+v", i, w.symbol, w.status, item)
+		}
+	}
+	if result.Created != 2 || result.Skipped != 2 || result.Failed != 2 {
+		t.Errorf("Expected 2 created, 2 skipped, 2 failed, got %+v", result)
+	}
+	if len(watchlist.Items) != 3 {
+		t.Errorf("Expected 3 watchlist items, got %d", len(watchlist.Items))
+	}
+	if result.Items[0].ID == nil || *result.Items[0].ID != watchlist.Items[1].ID {
+		t.Errorf("Expected created item ID to be reported, got %v", result.Items[0].ID)
+	}
+}
+
+func TestBulkService_AddWatchlistItemsErrors(t *testing.T) {
+	repo := newMockBulkRepository(model.Stock{ID: uuid.New(), Symbol: "AAPL"})
+	userID := uuid.New()
+	watchlist := &model.Watchlist{ID: uuid.New(), UserID: userID}
+	repo.watchlists[watchlist.ID] = watchlist
+	svc := NewBulkService(repo)
+
+	tooMany := make([]string, MaxBulkItems+1)
+	tests := []struct {
+		name        string
+		userID      uuid.UUID
+		watchlistID uuid.UUID
+		symbols     []string
+		wantErr     error
+	}{
+		{name: "empty request", userID: userID, watchlistID: watchlist.ID, symbols: nil, wantErr: ErrEmptyBulkRequest},
+		{name: "too many items", userID: userID, watchlistID: watchlist.ID, symbols: tooMany, wantErr: ErrTooManyBulkItems},
+		{name: "unknown watchlist", userID: userID, watchlistID: uuid.New(), symbols: []string{"AAPL"}, wantErr: ErrWatchlistNotFound},
+		{name: "other user's watchlist", userID: uuid.New(), watchlistID: watchlist.ID, symbols: []string{"AAPL"}, wantErr: ErrWatchlistNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.AddWatchlistItems(tt.userID, tt.watchlistID, tt.symbols); err != tt.wantErr {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	repo.saveErr = errors.New("connection reset")
+	if _, err := svc.AddWatchlistItems(userID, watchlist.ID, []string{"AAPL"}); err != repo.saveErr {
+		t.Errorf("Expected transaction error, got %v", err)
+	}
+	if len(watchlist.Items) != 0 {
+		t.Errorf("Expected no items after a failed transaction, got %d", len(watchlist.Items))
+	}
+}
+
+func TestBulkService_CreateAlerts(t *testing.T) {
+	repo := newMockBulkRepository(model.Stock{ID: uuid.New(), Symbol: "AAPL"}, model.Stock{ID: uuid.New(), Symbol: "MSFT"})
+	userID := uuid.New()
+	repo.alerts = []model.Alert{{
+		ID: uuid.New(), UserID: userID, Type: model.AlertTypeStockPrice, Symbol: "MSFT",
+		Condition: model.AlertConditionAbove, TargetValue: 400, Active: true,
+	}}
+	svc := NewBulkService(repo)
+
+	result, err := svc.CreateAlerts(userID, []model.Alert{
+		{Type: model.AlertTypeStockPrice, Symbol: "aapl", Condition: model.AlertConditionAbove, TargetValue: 200},
+		{Type: model.AlertTypeStockPrice, Symbol: "AAPL", Condition: model.AlertConditionBelow, TargetValue: 150},
+		{Type: model.AlertTypeStockPrice, Symbol: "AAPL.US", Condition: model.AlertConditionAbove, TargetValue: 200},
+		{Type: model.AlertTypeStockPrice, Symbol: "MSFT", Condition: model.AlertConditionAbove, TargetValue: 400},
+		{Type: model.AlertTypeOddsChange, Symbol: "AAPL", Condition: model.AlertConditionAbove, TargetValue: 2},
+		{Type: model.AlertTypeStockPrice, Symbol: "AAPL", Condition: "sideways", TargetValue: 1},
+		{Type: model.AlertTypeStockVolume, Symbol: "AAPL", Condition: model.AlertConditionCrosses, TargetValue: 1},
+		{Type: model.AlertTypeStockPrice, Symbol: "NOPE", Condition: model.AlertConditionAbove, TargetValue: 1},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []struct {
+		status BulkItemStatus
+		err    error
+	}{
+		{BulkItemCreated, nil},
+		{BulkItemCreated, nil},
+		{BulkItemSkipped, ErrDuplicateItem},
+		{BulkItemSkipped, ErrDuplicateItem},
+		{BulkItemFailed, ErrNotStockAlert},
+		{BulkItemFailed, model.ErrInvalidAlertCondition},
+		{BulkItemFailed, model.ErrUnsupportedAlertPairing},
+		{BulkItemFailed, ErrStockNotFound},
+	}
+	for i, w := range want {
+		item := result.Items[i]
+		if item.Status != w.status {
+			t.Errorf("Item %d: expected %s, got %+v", i, w.status, item)
+		}
+		if w.err != nil && !strings.HasPrefix(item.Error, w.err.Error()) {
+			t.Errorf("Item %d: expected error %q, got %q", i, w.err, item.Error)
+		}
+	}
+	if result.Created != 2 || result.Skipped != 2 || result.Failed != 4 {
+		t.Errorf("Expected 2 created, 2 skipped, 4 failed, got %+v", result)
+	}
+
+	if len(repo.alerts) != 3 {
+		t.Fatalf("Expected 3 stored alerts, got %d", len(repo.alerts))
+	}
+	created := repo.alerts[1]
+	if created.UserID != userID || created.Symbol != "AAPL" || !created.Active {
+		t.Errorf("Expected an active AAPL alert for the user, got %+v", created)
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+// Candle resampling errors.
+var (
+	ErrInvalidCandleInterval = errors.New("invalid interval")
+	ErrInvalidCandleRange    = errors.New("invalid range")
+	ErrIntervalNotMultiple   = errors.New("interval must be a multiple of the source interval")
+)
+
+// DefaultCandleSourceInterval is assumed when there are too few bars to infer
+// the source interval from the data.
+const DefaultCandleSourceInterval = 24 * time.Hour
+
+// Candle is one OHLCV bar.
+type Candle struct {
+	Timestamp time.Time `json:"timestamp"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    int64     `json:"volume"`
+}
+
+// ParseCandleInterval parses an interval such as 5m, 1h, 4h, 1d or 1w.
+func ParseCandleInterval(value string) (time.Duration, error) {
+	n, unit, err := splitCount(value)
+	if err != nil {
+		return 0, ErrInvalidCandleInterval
+	}
+
+	switch unit {
+	case "m":
+		return time.Duration(n) * time.Minute, nil
+	case "h":
+		return time.Duration(n) * time.Hour, nil
+	case "d":
+		return time.Duration(n) * 24 * time.Hour, nil
+	case "w":
+		return time.Duration(n) * 7 * 24 * time.Hour, nil
+	default:
+		return 0, ErrInvalidCandleInterval
+	}
+}
+
+// CandleRangeStart returns the start of a range such as 5d, 1mo or 1y that
+// ends at end.
+func CandleRangeStart(value string, end time.Time) (time.Time, error) {
+	n, unit, err := splitCount(value)
+	if err != nil {
+		return time.Time{}, ErrInvalidCandleRange
+	}
+
+	switch unit {
+	case "d":
+		return end.AddDate(0, 0, -n), nil
+	case "w":
+		return end.AddDate(0, 0, -7*n), nil
+	case "mo":
+		return end.AddDate(0, -n, 0), nil
+	case "y":
+		return end.AddDate(-n, 0, 0), nil
+	default:
+		return time.Time{}, ErrInvalidCandleRange
+	}
+}
+
+// splitCount splits a value like "4h" into 4 and "h".
+func splitCount(value string) (int, string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	i := 0
+	for i < len(value) && value[i] >= '0' && value[i] <= '9' {
+		i++
+	}
+	n, err := strconv.Atoi(value[:i])
+	if err != nil || n <= 0 {
+		return 0, "", fmt.Errorf("invalid count in %q", value)
+	}
+	return n, value[i:], nil
+}
+
+// InferCandleInterval returns the smallest gap between consecutive bars,
+// which is the interval the data was fetched at. Gaps from weekends and
+// holidays are larger and do not affect the result.
+func InferCandleInterval(prices []model.StockPrice) time.Duration {
+	sorted := sortedByTime(prices)
+
+	var interval time.Duration
+	for i := 1; i < len(sorted); i++ {
+		gap := sorted[i].Timestamp.Sub(sorted[i-1].Timestamp)
+		if gap > 0 && (interval == 0 || gap < interval) {
+			interval = gap
+		}
+	}
+	if interval == 0 {
+		return DefaultCandleSourceInterval
+	}
+	return interval
+}
+
+// ResampleCandles aggregates prices into candles of the target interval:
+// open is the first open, high the max, low the min, close the last close
+// and volume the sum. Buckets are aligned to UTC multiples of the interval
+// and returned oldest first. target must be a multiple of source.
+func ResampleCandles(prices []model.StockPrice, source, target time.Duration) ([]Candle, error) {
+	if target < source || target%source != 0 {
+		return nil, ErrIntervalNotMultiple
+	}
+
+	candles := make([]Candle, 0)
+	for _, price := range sortedByTime(prices) {
+		bucket := price.Timestamp.UTC().Truncate(target)
+
+		last := len(candles) - 1
+		if last >= 0 && candles[last].Timestamp.Equal(bucket) {
+			candle := &candles[last]
+			if price.High > candle.High {
+				candle.High = price.High
+			}
+			if price.Low < candle.Low {
+				candle.Low = price.Low
+			}
+			candle.Close = price.Close
+			candle.Volume += price.Volume
+			continue
+		}
+
+		candles = append(candles, Candle{
+			Timestamp: bucket,
+			Open:      price.Open,
+			High:      price.High,
+			Low:       price.Low,
+			Close:     price.Close,
+			Volume:    price.Volume,
+		})
+	}
+
+	return candles, nil
+}
+
+// sortedByTime returns a copy of prices ordered oldest first.
+func sortedByTime(prices []model.StockPrice) []model.StockPrice {
+	sorted := make([]model.StockPrice, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+	return sorted
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+func hourlyPrices(start time.Time, n int) []model.StockPrice {
+	prices := make([]model.StockPrice, n)
+	for i := range prices {
+		base := 100 + float64(i)
+		prices[i] = model.StockPrice{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Open:      base,
+			High:      base + 2,
+			Low:       base - 1,
+			Close:     base + 1,
+			Volume:    int64(1000 * (i + 1)),
+		}
+	}
+	// Stored newest first, like the repositories return them
+	for i, j := 0, len(prices)-1; i < j; i, j = i+1, j-1 {
+		prices[i], prices[j] = prices[j], prices[i]
+	}
+	return prices
+}
+
+func TestResampleCandles(t *testing.T) {
+	start := time.Date(2024, 12, 2, 0, 0, 0, 0, time.UTC)
+	prices := hourlyPrices(start, 8)
+
+	candles, err := ResampleCandles(prices, time.Hour, 4*time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(candles) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(candles))
+	}
+
+	want := []Candle{
+		{Timestamp: start, Open: 100, High: 105, Low: 99, Close: 104, Volume: 10000},
+		{Timestamp: start.Add(4 * time.Hour), Open: 104, High: 109, Low: 103, Close: 108, Volume: 26000},
+	}
+	for i, candle := range candles {
+		if candle != want[i] {
+			t.Errorf("Candle %d: expected %+v, got %+v", i, want[i], candle)
+		}
+	}
+
+	// Same interval passes bars through, oldest first
+	candles, err = ResampleCandles(prices, time.Hour, time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(candles) != 8 || !candles[0].Timestamp.Equal(start) {
+		t.Errorf("Expected 8 hourly candles starting at %v, got %d", start, len(candles))
+	}
+
+	for _, target := range []time.Duration{30 * time.Minute, 90 * time.Minute} {
+		if _, err := ResampleCandles(prices, time.Hour, target); err != ErrIntervalNotMultiple {
+			t.Errorf("Expected ErrIntervalNotMultiple for %v, got %v", target, err)
+		}
+	}
+}
+
+func TestInferCandleInterval(t *testing.T) {
+	start := time.Date(2024, 11, 29, 16, 0, 0, 0, time.UTC)
+	daily := []model.StockPrice{
+		{Timestamp: start},
+		{Timestamp: start.AddDate(0, 0, 3)}, // over the weekend
+		{Timestamp: start.AddDate(0, 0, 4)},
+	}
+
+	tests := []struct {
+		name   string
+		prices []model.StockPrice
+		want   time.Duration
+	}{
+		{"hourly", hourlyPrices(start, 5), time.Hour},
+		{"daily with weekend gap", daily, 24 * time.Hour},
+		{"single bar", daily[:1], DefaultCandleSourceInterval},
+		{"no bars", nil, DefaultCandleSourceInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferCandleInterval(tt.prices); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseCandleIntervalAndRange(t *testing.T) {
+	intervals := map[string]time.Duration{
+		"15m": 15 * time.Minute,
+		"4h":  4 * time.Hour,
+		"1D":  24 * time.Hour,
+		"1w":  7 * 24 * time.Hour,
+	}
+	for value, want := range intervals {
+		if got, err := ParseCandleInterval(value); err != nil || got != want {
+			t.Errorf("ParseCandleInterval(%q): expected %v, got %v (%v)", value, want, got, err)
+		}
+	}
+	for _, value := range []string{"", "h", "0h", "-1h", "4x", "1mo"} {
+		if _, err := ParseCandleInterval(value); err != ErrInvalidCandleInterval {
+			t.Errorf("ParseCandleInterval(%q): expected ErrInvalidCandleInterval, got %v", value, err)
+		}
+	}
+
+	end := time.Date(2024, 12, 4, 16, 0, 0, 0, time.UTC)
+	ranges := map[string]time.Time{
+		"5d":  end.AddDate(0, 0, -5),
+		"2w":  end.AddDate(0, 0, -14),
+		"1mo": end.AddDate(0, -1, 0),
+		"1y":  end.AddDate(-1, 0, 0),
+	}
+	for value, want := range ranges {
+		if got, err := CandleRangeStart(value, end); err != nil || !got.Equal(want) {
+			t.Errorf("CandleRangeStart(%q): expected %v, got %v (%v)", value, want, got, err)
+		}
+	}
+	for _, value := range []string{"", "mo", "1h", "1x"} {
+		if _, err := CandleRangeStart(value, end); err != ErrInvalidCandleRange {
+			t.Errorf("CandleRangeStart(%q): expected ErrInvalidCandleRange, got %v", value, err)
+		}
+	}
+}
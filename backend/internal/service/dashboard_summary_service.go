@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Dashboard summary defaults
+const (
+	// DashboardSummaryCacheTTL is how long a user's summary is reused. It is
+	// short so that trades and new notifications show up on the next load.
+	DashboardSummaryCacheTTL = 15 * time.Second
+	// DashboardTopMovers is the number of watchlist movers in a summary.
+	DashboardTopMovers = 5
+)
+
+// PortfolioSummary totals a user's paper portfolios at their last marked
+// prices.
+type PortfolioSummary struct {
+	Portfolios       int     `json:"portfolios"`
+	TotalValue       float64 `json:"total_value"`
+	DayChange        float64 `json:"day_change"`
+	DayChangePercent float64 `json:"day_change_percent"`
+}
+
+// BettingSummary is a user's open betting exposure and bankroll.
+type BettingSummary struct {
+	OpenBets  int64   `json:"open_bets"`
+	OpenStake float64 `json:"open_stake"`
+	Bankroll  float64 `json:"bankroll"`
+}
+
+// WatchlistMover is a watched stock's latest close and its change from the
+// previous close.
+type WatchlistMover struct {
+	Symbol        string    `json:"symbol"`
+	Name          string    `json:"name"`
+	Price         float64   `json:"price"`
+	Change        float64   `json:"change"`
+	ChangePercent float64   `json:"change_percent"`
+	AsOf          time.Time `json:"as_of"`
+}
+
+// DashboardSummary is the set of key figures shown when the dashboard loads.
+type DashboardSummary struct {
+	Portfolio           PortfolioSummary `json:"portfolio"`
+	Betting             BettingSummary   `json:"betting"`
+	UnreadNotifications int64            `json:"unread_notifications"`
+	ActiveAlerts        int64            `json:"active_alerts"`
+	TopMovers           []WatchlistMover `json:"top_movers"`
+	GeneratedAt         time.Time        `json:"generated_at"`
+}
+
+// DashboardSummaryService defines the interface for building a user's
+// dashboard summary.
+type DashboardSummaryService interface {
+	GetSummary(ctx context.Context, userID uuid.UUID) (*DashboardSummary, error)
+}
+
+// cachedSummary is a summary and the time it was built.
+type cachedSummary struct {
+	summary *DashboardSummary
+	builtAt time.Time
+}
+
+// dashboardSummaryService implements DashboardSummaryService.
+type dashboardSummaryService struct {
+	repo repository.DashboardSummaryRepository
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedSummary
+}
+
+// NewDashboardSummaryService creates a new DashboardSummaryService that
+// reuses each user's summary for ttl. A ttl of zero disables the cache.
+func NewDashboardSummaryService(repo repository.DashboardSummaryRepository, ttl time.Duration) DashboardSummaryService {
+	return &dashboardSummaryService{
+		repo:  repo,
+		ttl:   ttl,
+		now:   time.Now,
+		cache: make(map[uuid.UUID]cachedSummary),
+	}
+}
+
+// GetSummary returns the user's cached summary, or builds a new one.
+func (s *dashboardSummaryService) GetSummary(ctx context.Context, userID uuid.UUID) (*DashboardSummary, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[userID]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.builtAt) < s.ttl {
+		return cached.summary, nil
+	}
+
+	summary, err := s.build(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	summary.GeneratedAt = now
+
+	if s.ttl > 0 {
+		s.mu.Lock()
+		// Drop expired entries so users who stop polling are not kept forever
+		for id, entry := range s.cache {
+			if now.Sub(entry.builtAt) >= s.ttl {
+				delete(s.cache, id)
+			}
+		}
+		s.cache[userID] = cachedSummary{summary: summary, builtAt: now}
+		s.mu.Unlock()
+	}
+	return summary, nil
+}
+
+// build loads every section of the summary concurrently. The first error
+// fails the whole summary.
+func (s *dashboardSummaryService) build(ctx context.Context, userID uuid.UUID) (*DashboardSummary, error) {
+	summary := &DashboardSummary{}
+
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+	)
+	run := func(load func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := load(); err != nil {
+				errMu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				errMu.Unlock()
+			}
+		}()
+	}
+
+	run(func() error {
+		portfolios, err := s.repo.GetPortfolios(ctx, userID)
+		if err != nil {
+			return err
+		}
+		summary.Portfolio = summarizePortfolios(portfolios)
+		return nil
+	})
+	run(func() error {
+		totals, err := s.repo.GetOpenBetTotals(ctx, userID)
+		if err != nil {
+			return err
+		}
+		summary.Betting.OpenBets = totals.Count
+		summary.Betting.OpenStake = totals.Stake
+		return nil
+	})
+	run(func() error {
+		settings, err := s.repo.GetSettings(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if settings != nil {
+			summary.Betting.Bankroll = settings.CurrentBankroll
+		}
+		return nil
+	})
+	run(func() (err error) {
+		summary.UnreadNotifications, err = s.repo.CountUnreadNotifications(ctx, userID)
+		return err
+	})
+	run(func() (err error) {
+		summary.ActiveAlerts, err = s.repo.CountActiveAlerts(ctx, userID)
+		return err
+	})
+	run(func() error {
+		prices, err := s.repo.GetWatchlistStockPrices(ctx, userID)
+		if err != nil {
+			return err
+		}
+		summary.TopMovers = topMovers(prices, DashboardTopMovers)
+		return nil
+	})
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return summary, nil
+}
+
+// summarizePortfolios totals cash and positions at their last marked prices.
+// The day change percent is relative to the value at the previous close.
+func summarizePortfolios(portfolios []model.Portfolio) PortfolioSummary {
+	summary := PortfolioSummary{Portfolios: len(portfolios)}
+	for _, portfolio := range portfolios {
+		summary.TotalValue += portfolio.CashBalance
+		for i := range portfolio.Positions {
+			valuation := valuePosition(&portfolio.Positions[i], false)
+			summary.TotalValue += valuation.MarketValue
+			summary.DayChange += valuation.DayChangeValue
+		}
+	}
+	if previous := summary.TotalValue - summary.DayChange; previous > 0 {
+		summary.DayChangePercent = summary.DayChange / previous * 100
+	}
+	return summary
+}
+
+// topMovers returns up to limit watched stocks with the largest absolute
+// percentage change between their last two closes. Stocks without a previous
+// close are left out.
+func topMovers(prices []repository.WatchlistStockPrices, limit int) []WatchlistMover {
+	movers := make([]WatchlistMover, 0, len(prices))
+	for _, p := range prices {
+		if p.Previous == nil || p.Previous.Close <= 0 {
+			continue
+		}
+		change := p.Latest.Close - p.Previous.Close
+		movers = append(movers, WatchlistMover{
+			Symbol:        p.Stock.Symbol,
+			Name:          p.Stock.Name,
+			Price:         p.Latest.Close,
+			Change:        change,
+			ChangePercent: change / p.Previous.Close * 100,
+			AsOf:          p.Latest.Timestamp,
+		})
+	}
+
+	sort.SliceStable(movers, func(i, j int) bool {
+		return math.Abs(movers[i].ChangePercent) > math.Abs(movers[j].ChangePercent)
+	})
+	if len(movers) > limit {
+		movers = movers[:limit]
+	}
+	return movers
+}
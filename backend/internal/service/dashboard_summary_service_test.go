@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// mockDashboardSummaryRepository returns fixed figures and counts portfolio loads.
+type mockDashboardSummaryRepository struct {
+	portfolios []model.Portfolio
+	bets       repository.OpenBetTotals
+	settings   *model.Settings
+	unread     int64
+	alerts     int64
+	prices     []repository.WatchlistStockPrices
+	alertsErr  error
+	loads      atomic.Int32
+}
+
+func (m *mockDashboardSummaryRepository) GetPortfolios(ctx context.Context, userID uuid.UUID) ([]model.Portfolio, error) {
+	m.loads.Add(1)
+	return m.portfolios, nil
+}
+
+func (m *mockDashboardSummaryRepository) GetOpenBetTotals(ctx context.Context, userID uuid.UUID) (repository.OpenBetTotals, error) {
+	return m.bets, nil
+}
+
+func (m *mockDashboardSummaryRepository) GetSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error) {
+	return m.settings, nil
+}
+
+func (m *mockDashboardSummaryRepository) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return m.unread, nil
+}
+
+func (m *mockDashboardSummaryRepository) CountActiveAlerts(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return m.alerts, m.alertsErr
+}
+
+func (m *mockDashboardSummaryRepository) GetWatchlistStockPrices(ctx context.Context, userID uuid.UUID) ([]repository.WatchlistStockPrices, error) {
+	return m.prices, nil
+}
+
+// watchedStock builds watchlist prices for a stock that moved from previous to latest.
+func watchedStock(symbol string, previous, latest float64) repository.WatchlistStockPrices {
+	return repository.WatchlistStockPrices{
+		Stock:    model.Stock{Symbol: symbol},
+		Latest:   model.StockPrice{Close: latest},
+		Previous: &model.StockPrice{Close: previous},
+	}
+}
+
+func TestDashboardSummaryService_GetSummary(t *testing.T) {
+	repo := &mockDashboardSummaryRepository{
+		portfolios: []model.Portfolio{
+			{CashBalance: 5000, Positions: []model.Position{
+				{Symbol: "AAPL", Quantity: 10, CurrentPrice: 200, DayChange: 5},
+				{Symbol: "MSFT", Quantity: 5, CurrentPrice: 400, DayChange: -2},
+			}},
+			{CashBalance: 1000},
+		},
+		bets:     repository.OpenBetTotals{Count: 3, Stake: 75},
+		settings: &model.Settings{CurrentBankroll: 1250},
+		unread:   4,
+		alerts:   2,
+		prices: []repository.WatchlistStockPrices{
+			watchedStock("AAPL", 100, 101),
+			watchedStock("TSLA", 200, 180),
+			watchedStock("NVDA", 100, 105),
+			{Stock: model.Stock{Symbol: "NEW"}, Latest: model.StockPrice{Close: 10}},
+		},
+	}
+	svc := NewDashboardSummaryService(repo, 0)
+
+	summary, err := svc.GetSummary(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+
+	// 6000 cash + 2000 AAPL + 2000 MSFT; day change 10*5 - 5*2 = 40
+	if summary.Portfolio.Portfolios != 2 || summary.Portfolio.TotalValue != 10000 || summary.Portfolio.DayChange != 40 {
+		t.Errorf("Unexpected portfolio summary %+v", summary.Portfolio)
+	}
+	if want := 40.0 / 9960 * 100; math.Abs(summary.Portfolio.DayChangePercent-want) > 1e-9 {
+		t.Errorf("Expected day change percent %f, got %f", want, summary.Portfolio.DayChangePercent)
+	}
+	if summary.Betting != (BettingSummary{OpenBets: 3, OpenStake: 75, Bankroll: 1250}) {
+		t.Errorf("Unexpected betting summary %+v", summary.Betting)
+	}
+	if summary.UnreadNotifications != 4 || summary.ActiveAlerts != 2 {
+		t.Errorf("Expected 4 unread and 2 alerts, got %d and %d", summary.UnreadNotifications, summary.ActiveAlerts)
+	}
+
+	var symbols []string
+	for _, mover := range summary.TopMovers {
+		symbols = append(symbols, mover.Symbol)
+	}
+	if len(symbols) != 3 || symbols[0] != "TSLA" || symbols[1] != "NVDA" || symbols[2] != "AAPL" {
+		t.Errorf("Expected movers [TSLA NVDA AAPL], got %v", symbols)
+	}
+	if summary.TopMovers[0].Change != -20 || summary.TopMovers[0].ChangePercent != -10 {
+		t.Errorf("Unexpected TSLA move %+v", summary.TopMovers[0])
+	}
+}
+
+func TestDashboardSummaryService_Cache(t *testing.T) {
+	repo := &mockDashboardSummaryRepository{}
+	svc := NewDashboardSummaryService(repo, time.Minute).(*dashboardSummaryService)
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	userID, otherID := uuid.New(), uuid.New()
+	svc.GetSummary(context.Background(), userID)
+	svc.GetSummary(context.Background(), userID)
+	if loads := repo.loads.Load(); loads != 1 {
+		t.Errorf("Expected the cached summary to be reused, got %d loads", loads)
+	}
+
+	svc.GetSummary(context.Background(), otherID)
+	if loads := repo.loads.Load(); loads != 2 {
+		t.Errorf("Expected summaries to be cached per user, got %d loads", loads)
+	}
+
+	now = now.Add(time.Minute)
+	summary, _ := svc.GetSummary(context.Background(), userID)
+	if loads := repo.loads.Load(); loads != 3 {
+		t.Errorf("Expected an expired summary to be rebuilt, got %d loads", loads)
+	}
+	if !summary.GeneratedAt.Equal(now) {
+		t.Errorf("Expected generated_at %v, got %v", now, summary.GeneratedAt)
+	}
+	if _, ok := svc.cache[otherID]; ok {
+		t.Error("Expected expired entries to be evicted")
+	}
+}
+
+func TestDashboardSummaryService_Error(t *testing.T) {
+	repoErr := errors.New("connection refused")
+	repo := &mockDashboardSummaryRepository{alertsErr: repoErr}
+	svc := NewDashboardSummaryService(repo, time.Minute)
+
+	if _, err := svc.GetSummary(context.Background(), uuid.New()); err != repoErr {
+		t.Errorf("Expected %v, got %v", repoErr, err)
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+import "github.com/awaymess/super-dashboard/backend/pkg/metrics"
+
+// Domain event counters since startup, exposed by the metrics endpoint.
+var (
+	// PaperOrdersTotal counts paper orders created, by side and the status
+	// they were created with: filled, pending or cancelled.
+	PaperOrdersTotal = metrics.NewCounterVec("side", "status")
+	// BetsPlacedTotal counts bets placed.
+	BetsPlacedTotal = metrics.NewCounterVec()
+	// ValueBetsDetectedTotal counts positive-value bets found by detection
+	// runs, whatever the caller's threshold.
+	ValueBetsDetectedTotal = metrics.NewCounterVec()
+	// NotificationsSentTotal counts notification deliveries by channel and
+	// outcome: delivered or failed.
+	NotificationsSentTotal = metrics.NewCounterVec("channel", "status")
+)
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+)
+
+// Match probability errors
+var (
+	ErrUnratedTeams = errors.New("both teams need an Elo rating")
+)
+
+// Elo defaults. The home side gets DefaultEloHomeAdvantage extra rating
+// points; between evenly matched sides DefaultEloDrawFactor of results are
+// draws.
+const (
+	DefaultEloHomeAdvantage = 100.0
+	DefaultEloDrawFactor    = 0.2
+	DefaultEloKFactor       = 32.0
+	// MaxEloDrawFactor keeps win probabilities from going negative.
+	MaxEloDrawFactor = 0.5
+)
+
+// EloWinProbability returns the home win, draw and away win probabilities of
+// a match with DefaultEloDrawFactor. homeAdvantage is added to the home
+// side's rating; pass 0 for a neutral venue.
+func EloWinProbability(homeElo, awayElo, homeAdvantage float64) (pHome, pDraw, pAway float64) {
+	return eloProbabilities(homeElo, awayElo, homeAdvantage, DefaultEloDrawFactor)
+}
+
+// eloProbabilities splits the home side's Elo expected score e into a
+// result. Draws are likeliest between even sides: pDraw = drawFactor*4e(1-e).
+// Half of each draw counts towards each side's expected score, so
+// pHome + pDraw/2 = e. drawFactor is clamped to [0, MaxEloDrawFactor].
+func eloProbabilities(homeElo, awayElo, homeAdvantage, drawFactor float64) (pHome, pDraw, pAway float64) {
+	drawFactor = math.Max(0, math.Min(drawFactor, MaxEloDrawFactor))
+	expectedHome := eloExpectedScore(homeElo+homeAdvantage, awayElo)
+
+	pDraw = drawFactor * 4 * expectedHome * (1 - expectedHome)
+	pHome = expectedHome - pDraw/2
+	pAway = 1 - expectedHome - pDraw/2
+	return pHome, pDraw, pAway
+}
+
+// eloExpectedScore returns the expected score of a side rated rating against
+// one rated opponent.
+func eloExpectedScore(rating, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
+
+// UpdateElo moves k times the winner's unexpected share of the result from
+// the loser's rating to the winner's. k defaults to DefaultEloKFactor when
+// it is not positive.
+func UpdateElo(winnerElo, loserElo, k float64) (newWinner, newLoser float64) {
+	if k <= 0 {
+		k = DefaultEloKFactor
+	}
+	delta := k * (1 - eloExpectedScore(winnerElo, loserElo))
+	return winnerElo + delta, loserElo - delta
+}
+
+// EloProbabilityModel estimates match result probabilities from the teams'
+// Elo ratings. It has no estimate unless both teams are rated.
+type EloProbabilityModel struct {
+	HomeAdvantage float64
+	DrawFactor    float64
+}
+
+// DefaultEloProbabilityModel uses the default home advantage and draw factor.
+var DefaultEloProbabilityModel = EloProbabilityModel{
+	HomeAdvantage: DefaultEloHomeAdvantage,
+	DrawFactor:    DefaultEloDrawFactor,
+}
+
+// Probabilities returns the home win, draw and away win probabilities.
+func (m EloProbabilityModel) Probabilities(match *model.Match) (pHome, pDraw, pAway float64, ok bool) {
+	if match.HomeTeam.Elo <= 0 || match.AwayTeam.Elo <= 0 {
+		return 0, 0, 0, false
+	}
+	pHome, pDraw, pAway = eloProbabilities(match.HomeTeam.Elo, match.AwayTeam.Elo, m.HomeAdvantage, m.DrawFactor)
+	return pHome, pDraw, pAway, true
+}
+
+// TrueProbability returns the probability of a home win, draw or away win in
+// the 1X2 market. Outcomes may be named 1, X and 2 or home, draw and away.
+func (m EloProbabilityModel) TrueProbability(match *model.Match, market, outcome string) (float64, bool) {
+	if !strings.EqualFold(market, MarketMatchResult) {
+		return 0, false
+	}
+	pHome, pDraw, pAway, ok := m.Probabilities(match)
+	if !ok {
+		return 0, false
+	}
+
+	switch matchResultOutcome(outcome) {
+	case OutcomeHome:
+		return pHome, true
+	case OutcomeDraw:
+		return pDraw, true
+	case OutcomeAway:
+		return pAway, true
+	}
+	return 0, false
+}
+
+// matchResultOutcome maps 1X2 outcome names to OutcomeHome, OutcomeDraw or
+// OutcomeAway, or returns "" for anything else.
+func matchResultOutcome(outcome string) string {
+	switch strings.ToLower(outcome) {
+	case "1", OutcomeHome:
+		return OutcomeHome
+	case "x", OutcomeDraw:
+		return OutcomeDraw
+	case "2", OutcomeAway:
+		return OutcomeAway
+	}
+	return ""
+}
+
+// MatchProbabilities are a match's result probabilities and the ratings
+// behind them.
+type MatchProbabilities struct {
+	MatchID       string  `json:"match_id"`
+	HomeTeam      string  `json:"home_team"`
+	AwayTeam      string  `json:"away_team"`
+	HomeElo       float64 `json:"home_elo"`
+	AwayElo       float64 `json:"away_elo"`
+	HomeAdvantage float64 `json:"home_advantage"`
+	DrawFactor    float64 `json:"draw_factor"`
+	HomeWin       float64 `json:"home_win"`
+	Draw          float64 `json:"draw"`
+	AwayWin       float64 `json:"away_win"`
+}
+
+// MatchProbabilityService defines the interface for match result probabilities.
+type MatchProbabilityService interface {
+	Probabilities(matchID string) (*MatchProbabilities, error)
+}
+
+// matchProbabilityService implements MatchProbabilityService.
+type matchProbabilityService struct {
+	matches repository.MatchRepository
+	model   EloProbabilityModel
+}
+
+// NewMatchProbabilityService creates a new MatchProbabilityService instance.
+func NewMatchProbabilityService(matches repository.MatchRepository, model EloProbabilityModel) MatchProbabilityService {
+	return &matchProbabilityService{matches: matches, model: model}
+}
+
+// Probabilities converts the teams' Elo ratings into 1X2 probabilities.
+func (s *matchProbabilityService) Probabilities(matchID string) (*MatchProbabilities, error) {
+	match, err := s.matches.GetByID(matchID)
+	if err != nil {
+		return nil, ErrMatchNotFound
+	}
+	pHome, pDraw, pAway, ok := s.model.Probabilities(match)
+	if !ok {
+		return nil, ErrUnratedTeams
+	}
+
+	return &MatchProbabilities{
+		MatchID:       matchID,
+		HomeTeam:      match.HomeTeam.Name,
+		AwayTeam:      match.AwayTeam.Name,
+		HomeElo:       match.HomeTeam.Elo,
+		AwayElo:       match.AwayTeam.Elo,
+		HomeAdvantage: s.model.HomeAdvantage,
+		DrawFactor:    math.Max(0, math.Min(s.model.DrawFactor, MaxEloDrawFactor)),
+		HomeWin:       pHome,
+		Draw:          pDraw,
+		AwayWin:       pAway,
+	}, nil
+}
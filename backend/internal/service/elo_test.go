@@ -0,0 +1,140 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+)
+
+func TestEloWinProbability_SymmetricTeams(t *testing.T) {
+	pHome, pDraw, pAway := EloWinProbability(1500, 1500, 0)
+	if math.Abs(pHome-pAway) > 1e-9 {
+		t.Errorf("EloWinProbability() home %v, away %v, want equal for even teams on neutral ground", pHome, pAway)
+	}
+	if math.Abs(pDraw-DefaultEloDrawFactor) > 1e-9 {
+		t.Errorf("EloWinProbability() draw = %v, want %v", pDraw, DefaultEloDrawFactor)
+	}
+	if math.Abs(pHome+pDraw+pAway-1) > 1e-9 {
+		t.Errorf("EloWinProbability() probabilities sum to %v, want 1", pHome+pDraw+pAway)
+	}
+}
+
+func TestEloWinProbability_HomeAdvantage(t *testing.T) {
+	neutralHome, neutralDraw, neutralAway := EloWinProbability(1500, 1500, 0)
+	pHome, pDraw, pAway := EloWinProbability(1500, 1500, DefaultEloHomeAdvantage)
+
+	if pHome <= neutralHome || pAway >= neutralAway {
+		t.Errorf("home advantage gave home %v, away %v; want home above %v and away below %v", pHome, pAway, neutralHome, neutralAway)
+	}
+	if pDraw >= neutralDraw {
+		t.Errorf("home advantage gave draw %v, want below the even-match %v", pDraw, neutralDraw)
+	}
+	if math.Abs(pHome+pDraw+pAway-1) > 1e-9 {
+		t.Errorf("probabilities sum to %v, want 1", pHome+pDraw+pAway)
+	}
+
+	// Home advantage is worth the same as that many extra rating points.
+	ratedHome, _, _ := EloWinProbability(1500+DefaultEloHomeAdvantage, 1500, 0)
+	if math.Abs(pHome-ratedHome) > 1e-9 {
+		t.Errorf("home advantage gave home %v, want %v", pHome, ratedHome)
+	}
+}
+
+func TestEloWinProbability_ExpectedScore(t *testing.T) {
+	// A 400-point gap makes the stronger side ten times as likely to score.
+	pHome, pDraw, _ := EloWinProbability(1900, 1500, 0)
+	if want := 10.0 / 11; math.Abs(pHome+pDraw/2-want) > 1e-9 {
+		t.Errorf("home expected score = %v, want %v", pHome+pDraw/2, want)
+	}
+}
+
+func TestUpdateElo(t *testing.T) {
+	winner, loser := UpdateElo(1500, 1500, 32)
+	if winner != 1516 || loser != 1484 {
+		t.Errorf("UpdateElo(1500, 1500, 32) = %v, %v, want 1516, 1484", winner, loser)
+	}
+
+	// An upset moves more points than an expected win.
+	favWinner, _ := UpdateElo(1700, 1500, 32)
+	upsetWinner, _ := UpdateElo(1500, 1700, 32)
+	if favWinner-1700 >= upsetWinner-1500 {
+		t.Errorf("favourite gained %v, underdog %v; want the upset to gain more", favWinner-1700, upsetWinner-1500)
+	}
+
+	defaultWinner, _ := UpdateElo(1500, 1500, 0)
+	if defaultWinner != 1500+DefaultEloKFactor/2 {
+		t.Errorf("UpdateElo() with no K = %v, want the default K factor", defaultWinner)
+	}
+}
+
+func TestEloProbabilityModel_TrueProbability(t *testing.T) {
+	m := DefaultEloProbabilityModel
+	// The home advantage makes the sides level.
+	match := &model.Match{HomeTeam: model.Team{Elo: 1600}, AwayTeam: model.Team{Elo: 1700}}
+
+	for _, c := range []struct {
+		outcome string
+		want    float64
+	}{
+		{"1", 0.4}, {"home", 0.4}, {"X", 0.2}, {"draw", 0.2}, {"2", 0.4}, {"away", 0.4},
+	} {
+		prob, ok := m.TrueProbability(match, "1x2", c.outcome)
+		if !ok || math.Abs(prob-c.want) > 1e-9 {
+			t.Errorf("TrueProbability(%q) = %v, %v, want %v", c.outcome, prob, ok, c.want)
+		}
+	}
+
+	if _, ok := m.TrueProbability(match, "O/U 2.5", "Over"); ok {
+		t.Error("TrueProbability() estimated an over/under market")
+	}
+	if _, ok := m.TrueProbability(&model.Match{HomeTeam: model.Team{Elo: 1600}}, "1X2", "1"); ok {
+		t.Error("TrueProbability() estimated a match with an unrated team")
+	}
+}
+
+// stubMatches serves a single match, "1".
+type stubMatches struct {
+	match model.Match
+}
+
+func (s *stubMatches) GetAll() ([]model.Match, error) { return []model.Match{s.match}, nil }
+
+func (s *stubMatches) GetByID(id string) (*model.Match, error) {
+	if id != "1" {
+		return nil, repository.ErrNotFound
+	}
+	return &s.match, nil
+}
+
+func (s *stubMatches) GetOddsByMatchID(matchID string) ([]model.Odds, error) { return nil, nil }
+
+func TestMatchProbabilityService_Probabilities(t *testing.T) {
+	matches := &stubMatches{match: model.Match{
+		HomeTeam: model.Team{Name: "Home", Elo: 1850},
+		AwayTeam: model.Team{Name: "Away", Elo: 1900},
+	}}
+	svc := NewMatchProbabilityService(matches, EloProbabilityModel{HomeAdvantage: 50, DrawFactor: 0.3})
+
+	probs, err := svc.Probabilities("1")
+	if err != nil {
+		t.Fatalf("Probabilities() error = %v", err)
+	}
+	// The home advantage makes the sides level.
+	if math.Abs(probs.HomeWin-0.35) > 1e-9 || math.Abs(probs.Draw-0.3) > 1e-9 || math.Abs(probs.AwayWin-0.35) > 1e-9 {
+		t.Errorf("Probabilities() = %+v, want 0.35/0.3/0.35", probs)
+	}
+	if probs.HomeTeam != "Home" || probs.HomeElo != 1850 || probs.DrawFactor != 0.3 {
+		t.Errorf("Probabilities() = %+v, want the teams and model settings", probs)
+	}
+
+	if _, err := svc.Probabilities("missing"); err != ErrMatchNotFound {
+		t.Errorf("Probabilities() of an unknown match error = %v, want %v", err, ErrMatchNotFound)
+	}
+
+	matches.match.AwayTeam.Elo = 0
+	if _, err := svc.Probabilities("1"); err != ErrUnratedTeams {
+		t.Errorf("Probabilities() with an unrated team error = %v, want %v", err, ErrUnratedTeams)
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+func TestExtendedAuthService_VerifyEmail(t *testing.T) {
+	userRepo := newMockUserRepository()
+	auditRepo := newMockAuditLogRepository()
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:     userRepo,
+		SessionRepo:  newMockSessionRepository(),
+		AuditLogRepo: auditRepo,
+		TokenStore:   tokenStore,
+		JWTSecret:    "test-secret",
+	})
+
+	user, err := authService.Register("verify@example.com", "password123", "Verify User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	if user.EmailVerified {
+		t.Fatal("Expected new user to be unverified")
+	}
+
+	if err := authService.VerifyEmail("unknown-token"); err != ErrInvalidVerificationToken {
+		t.Errorf("Expected ErrInvalidVerificationToken for unknown token, got %v", err)
+	}
+
+	token, err := authService.SendVerificationEmail(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to send verification email: %v", err)
+	}
+	if err := authService.VerifyEmail(token); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !user.EmailVerified {
+		t.Error("Expected email to be verified")
+	}
+
+	// Tokens are single use
+	if err := authService.VerifyEmail(token); err != ErrInvalidVerificationToken {
+		t.Errorf("Expected ErrInvalidVerificationToken for reused token, got %v", err)
+	}
+
+	found := false
+	for _, log := range auditRepo.logs {
+		if log.Action == model.AuditActionEmailVerify && log.Success {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected email verification to be audit logged")
+	}
+}
+
+func TestExtendedAuthService_VerifyEmail_ExpiredToken(t *testing.T) {
+	userRepo := newMockUserRepository()
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:   userRepo,
+		TokenStore: tokenStore,
+		JWTSecret:  "test-secret",
+	})
+
+	user, err := authService.Register("expired@example.com", "password123", "Expired User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	token, err := authService.SendVerificationEmail(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to send verification email: %v", err)
+	}
+
+	stored := tokenStore.verification[token]
+	if ttl := time.Until(stored.expiresAt); ttl <= EmailVerificationTokenDuration-time.Minute || ttl > EmailVerificationTokenDuration {
+		t.Errorf("Expected token to expire in %v, got %v", EmailVerificationTokenDuration, ttl)
+	}
+	stored.expiresAt = time.Now().Add(-time.Second)
+	tokenStore.verification[token] = stored
+
+	if err := authService.VerifyEmail(token); err != ErrInvalidVerificationToken {
+		t.Errorf("Expected ErrInvalidVerificationToken, got %v", err)
+	}
+	if user.EmailVerified {
+		t.Error("Expected email to stay unverified")
+	}
+}
+
+func TestExtendedAuthService_VerifyEmail_AlreadyVerified(t *testing.T) {
+	userRepo := newMockUserRepository()
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:   userRepo,
+		TokenStore: tokenStore,
+		JWTSecret:  "test-secret",
+	})
+
+	user, err := authService.Register("already@example.com", "password123", "Already User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	first, err := authService.SendVerificationEmail(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to send verification email: %v", err)
+	}
+	second, err := authService.SendVerificationEmail(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to send verification email: %v", err)
+	}
+	if err := authService.VerifyEmail(first); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := authService.VerifyEmail(second); err != ErrEmailAlreadyVerified {
+		t.Errorf("Expected ErrEmailAlreadyVerified, got %v", err)
+	}
+	if _, err := authService.SendVerificationEmail(user.ID); err != ErrEmailAlreadyVerified {
+		t.Errorf("Expected ErrEmailAlreadyVerified, got %v", err)
+	}
+}
+
+func TestExtendedAuthService_LoginRequiresEmailVerification(t *testing.T) {
+	userRepo := newMockUserRepository()
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:                 userRepo,
+		SessionRepo:              newMockSessionRepository(),
+		TokenStore:               tokenStore,
+		JWTSecret:                "test-secret",
+		RequireEmailVerification: true,
+	})
+
+	user, err := authService.Register("required@example.com", "password123", "Required User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	if _, _, err := authService.Login("required@example.com", "wrongpassword"); err != ErrInvalidCredentials {
+		t.Errorf("Expected ErrInvalidCredentials before the verification check, got %v", err)
+	}
+	if _, _, err := authService.Login("required@example.com", "password123"); err != ErrEmailNotVerified {
+		t.Errorf("Expected ErrEmailNotVerified, got %v", err)
+	}
+
+	token, err := authService.SendVerificationEmail(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to send verification email: %v", err)
+	}
+	if err := authService.VerifyEmail(token); err != nil {
+		t.Fatalf("Failed to verify email: %v", err)
+	}
+
+	if _, _, err := authService.Login("required@example.com", "password123"); err != nil {
+		t.Errorf("Expected login to succeed after verification, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// MaxDryRunWindow is the longest date range a dry run may replay.
+const MaxDryRunWindow = 92 * 24 * time.Hour
+
+// Engine dry-run errors
+var (
+	ErrRangeRequiresDryRun = errors.New("a date range can only be replayed as a dry run")
+	ErrDryRunWindowTooLong = errors.New("date range must not exceed 92 days")
+	ErrEngineUnavailable   = errors.New("engine is not available")
+)
+
+// EngineRunOptions controls a single pass of the value-bet or alert engine.
+type EngineRunOptions struct {
+	// DryRun reports what the engine would do without saving value bets,
+	// sending notifications or updating alerts.
+	DryRun bool
+	// From and To replay the engine over historical data instead of the
+	// latest data. Both must be set together and require DryRun.
+	From time.Time
+	To   time.Time
+}
+
+// HasRange reports whether the options replay a date range.
+func (o EngineRunOptions) HasRange() bool {
+	return !o.From.IsZero() || !o.To.IsZero()
+}
+
+// Validate checks the date range and that it is only used for a dry run.
+func (o EngineRunOptions) Validate() error {
+	if !o.HasRange() {
+		return nil
+	}
+	if o.From.IsZero() || o.To.IsZero() || !o.From.Before(o.To) {
+		return ErrInvalidDateRange
+	}
+	if o.To.Sub(o.From) > MaxDryRunWindow {
+		return ErrDryRunWindowTooLong
+	}
+	if !o.DryRun {
+		return ErrRangeRequiresDryRun
+	}
+	return nil
+}
+
+// AlertTrigger is an alert whose condition was met by an observed value.
+type AlertTrigger struct {
+	AlertID     uuid.UUID            `json:"alert_id"`
+	UserID      uuid.UUID            `json:"user_id"`
+	Symbol      string               `json:"symbol"`
+	Type        model.AlertType      `json:"type"`
+	Condition   model.AlertCondition `json:"condition"`
+	TargetValue float64              `json:"target_value"`
+	Value       float64              `json:"value"`
+	TriggeredAt time.Time            `json:"triggered_at"`
+}
+
+// ValueBetEngine runs one pass of the value-bet calculator.
+type ValueBetEngine interface {
+	RunOnce(ctx context.Context, opts EngineRunOptions) ([]model.ValueBet, error)
+}
+
+// AlertEngine runs one pass of the alert checker.
+type AlertEngine interface {
+	RunOnce(ctx context.Context, opts EngineRunOptions) ([]AlertTrigger, error)
+}
+
+// ValueBetDryRun is the result of replaying the value-bet engine.
+type ValueBetDryRun struct {
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Count     int              `json:"count"`
+	ValueBets []model.ValueBet `json:"value_bets"`
+}
+
+// AlertDryRun is the result of replaying the alert engine.
+type AlertDryRun struct {
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Count    int            `json:"count"`
+	Triggers []AlertTrigger `json:"triggers"`
+}
+
+// EngineDryRunService defines the interface for replaying the value-bet and
+// alert engines over a date range without side effects.
+type EngineDryRunService interface {
+	DryRunValueBets(ctx context.Context, from, to time.Time) (*ValueBetDryRun, error)
+	DryRunAlerts(ctx context.Context, from, to time.Time) (*AlertDryRun, error)
+}
+
+// engineDryRunService implements EngineDryRunService.
+type engineDryRunService struct {
+	valueBets ValueBetEngine
+	alerts    AlertEngine
+}
+
+// NewEngineDryRunService creates a new EngineDryRunService instance. Either
+// engine may be nil, in which case its dry runs return ErrEngineUnavailable.
+func NewEngineDryRunService(valueBets ValueBetEngine, alerts AlertEngine) EngineDryRunService {
+	return &engineDryRunService{valueBets: valueBets, alerts: alerts}
+}
+
+// DryRunValueBets lists the value bets the engine would find for matches
+// starting between from and to.
+func (s *engineDryRunService) DryRunValueBets(ctx context.Context, from, to time.Time) (*ValueBetDryRun, error) {
+	opts := EngineRunOptions{DryRun: true, From: from, To: to}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if s.valueBets == nil {
+		return nil, ErrEngineUnavailable
+	}
+
+	valueBets, err := s.valueBets.RunOnce(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if valueBets == nil {
+		valueBets = []model.ValueBet{}
+	}
+	return &ValueBetDryRun{From: from, To: to, Count: len(valueBets), ValueBets: valueBets}, nil
+}
+
+// DryRunAlerts lists the alerts that would have triggered on data observed
+// between from and to.
+func (s *engineDryRunService) DryRunAlerts(ctx context.Context, from, to time.Time) (*AlertDryRun, error) {
+	opts := EngineRunOptions{DryRun: true, From: from, To: to}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if s.alerts == nil {
+		return nil, ErrEngineUnavailable
+	}
+
+	triggers, err := s.alerts.RunOnce(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if triggers == nil {
+		triggers = []AlertTrigger{}
+	}
+	return &AlertDryRun{From: from, To: to, Count: len(triggers), Triggers: triggers}, nil
+}
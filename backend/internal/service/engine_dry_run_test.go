@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// mockValueBetEngine records the options it was run with.
+type mockValueBetEngine struct {
+	opts      EngineRunOptions
+	valueBets []model.ValueBet
+}
+
+func (m *mockValueBetEngine) RunOnce(ctx context.Context, opts EngineRunOptions) ([]model.ValueBet, error) {
+	m.opts = opts
+	return m.valueBets, nil
+}
+
+// mockAlertEngine records the options it was run with.
+type mockAlertEngine struct {
+	opts     EngineRunOptions
+	triggers []AlertTrigger
+}
+
+func (m *mockAlertEngine) RunOnce(ctx context.Context, opts EngineRunOptions) ([]AlertTrigger, error) {
+	m.opts = opts
+	return m.triggers, nil
+}
+
+func TestEngineRunOptions_Validate(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		opts    EngineRunOptions
+		wantErr error
+	}{
+		{"live run", EngineRunOptions{}, nil},
+		{"live dry run", EngineRunOptions{DryRun: true}, nil},
+		{"dry run over range", EngineRunOptions{DryRun: true, From: from, To: from.AddDate(0, 1, 0)}, nil},
+		{"range without dry run", EngineRunOptions{From: from, To: from.AddDate(0, 1, 0)}, ErrRangeRequiresDryRun},
+		{"missing to", EngineRunOptions{DryRun: true, From: from}, ErrInvalidDateRange},
+		{"reversed range", EngineRunOptions{DryRun: true, From: from, To: from.AddDate(0, 0, -1)}, ErrInvalidDateRange},
+		{"range too long", EngineRunOptions{DryRun: true, From: from, To: from.AddDate(0, 6, 0)}, ErrDryRunWindowTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); err != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEngineDryRunService(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	valueBets := &mockValueBetEngine{valueBets: []model.ValueBet{{MatchID: uuid.New(), ValuePercent: 8}}}
+	alerts := &mockAlertEngine{}
+	svc := NewEngineDryRunService(valueBets, alerts)
+
+	betRun, err := svc.DryRunValueBets(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("DryRunValueBets failed: %v", err)
+	}
+	if betRun.Count != 1 || len(betRun.ValueBets) != 1 {
+		t.Errorf("Expected 1 value bet, got %+v", betRun)
+	}
+	if want := (EngineRunOptions{DryRun: true, From: from, To: to}); valueBets.opts != want {
+		t.Errorf("Expected engine options %+v, got %+v", want, valueBets.opts)
+	}
+
+	alertRun, err := svc.DryRunAlerts(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("DryRunAlerts failed: %v", err)
+	}
+	if alertRun.Triggers == nil || alertRun.Count != 0 {
+		t.Errorf("Expected an empty trigger list, got %+v", alertRun)
+	}
+	if !alerts.opts.DryRun {
+		t.Error("Expected the alert engine to be run as a dry run")
+	}
+
+	if _, err := svc.DryRunAlerts(context.Background(), to, from); err != ErrInvalidDateRange {
+		t.Errorf("Expected ErrInvalidDateRange, got %v", err)
+	}
+	if _, err := NewEngineDryRunService(nil, nil).DryRunValueBets(context.Background(), from, to); err != ErrEngineUnavailable {
+		t.Errorf("Expected ErrEngineUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Data export service errors.
+var (
+	ErrExportJobNotFound = errors.New("export job not found")
+	ErrExportInProgress  = errors.New("an export is already in progress")
+)
+
+// ExportJobTTL is how long a finished export job can be downloaded.
+const ExportJobTTL = 24 * time.Hour
+
+// exportAuditPageSize is the page size used when exporting audit logs.
+const exportAuditPageSize = 500
+
+// ExportSource gathers one section of a user's data export.
+type ExportSource func(ctx context.Context, userID uuid.UUID) (interface{}, error)
+
+// ExportNotificationSink stores the notification sent when an export is ready.
+type ExportNotificationSink interface {
+	CreateNotification(ctx context.Context, notification *model.Notification) error
+}
+
+// DataExport is everything stored about a user, keyed by section name.
+type DataExport struct {
+	UserID      uuid.UUID              `json:"user_id"`
+	GeneratedAt time.Time              `json:"generated_at"`
+	Sections    map[string]interface{} `json:"sections"`
+}
+
+// ExportJobStatus represents the state of an asynchronous export.
+type ExportJobStatus string
+
+const (
+	ExportJobPending ExportJobStatus = "pending"
+	ExportJobReady   ExportJobStatus = "ready"
+	ExportJobFailed  ExportJobStatus = "failed"
+)
+
+// ExportJob tracks an export generated in the background.
+type ExportJob struct {
+	ID          uuid.UUID       `json:"id"`
+	UserID      uuid.UUID       `json:"user_id"`
+	Status      ExportJobStatus `json:"status"`
+	Error       string          `json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	Export      *DataExport     `json:"-"`
+}
+
+// DataExportService defines the interface for per-user data exports.
+type DataExportService interface {
+	// AddSource registers a named section. Sections added later with the
+	// same name replace earlier ones.
+	AddSource(name string, source ExportSource)
+
+	// Export gathers the user's data synchronously.
+	Export(ctx context.Context, userID uuid.UUID) (*DataExport, error)
+
+	// StartExport generates the export in the background and notifies the
+	// user when it is ready. Only one export per user runs at a time.
+	StartExport(userID uuid.UUID) (*ExportJob, error)
+
+	// GetJob returns one of the user's export jobs.
+	GetJob(userID, jobID uuid.UUID) (*ExportJob, error)
+}
+
+// exportSource is a registered, named ExportSource.
+type exportSource struct {
+	name  string
+	fetch ExportSource
+}
+
+// dataExportService implements DataExportService.
+type dataExportService struct {
+	sink ExportNotificationSink
+
+	mu      sync.Mutex
+	sources []exportSource
+	jobs    map[uuid.UUID]*ExportJob
+}
+
+// NewDataExportService creates a new DataExportService that always exports
+// the user's profile. sink may be nil; background exports then complete
+// without a notification.
+func NewDataExportService(userRepo repository.UserRepository, sink ExportNotificationSink) DataExportService {
+	s := &dataExportService{
+		sink: sink,
+		jobs: make(map[uuid.UUID]*ExportJob),
+	}
+	s.AddSource("profile", func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		return userRepo.GetByID(userID)
+	})
+	return s
+}
+
+// AddSource registers a named export section.
+func (s *dataExportService) AddSource(name string, source ExportSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.sources {
+		if s.sources[i].name == name {
+			s.sources[i].fetch = source
+			return
+		}
+	}
+	s.sources = append(s.sources, exportSource{name: name, fetch: source})
+}
+
+// Export gathers every registered section for the user.
+func (s *dataExportService) Export(ctx context.Context, userID uuid.UUID) (*DataExport, error) {
+	s.mu.Lock()
+	sources := make([]exportSource, len(s.sources))
+	copy(sources, s.sources)
+	s.mu.Unlock()
+
+	export := &DataExport{
+		UserID:      userID,
+		GeneratedAt: time.Now().UTC(),
+		Sections:    make(map[string]interface{}, len(sources)),
+	}
+	for _, source := range sources {
+		data, err := source.fetch(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", source.name, err)
+		}
+		export.Sections[source.name] = data
+	}
+
+	return export, nil
+}
+
+// StartExport queues a background export for the user.
+func (s *dataExportService) StartExport(userID uuid.UUID) (*ExportJob, error) {
+	s.mu.Lock()
+	s.purgeExpiredLocked()
+	for _, job := range s.jobs {
+		if job.UserID == userID && job.Status == ExportJobPending {
+			s.mu.Unlock()
+			return nil, ErrExportInProgress
+		}
+	}
+
+	job := &ExportJob{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Status:    ExportJobPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	s.jobs[job.ID] = job
+	snapshot := *job
+	s.mu.Unlock()
+
+	go s.runJob(job.ID, userID)
+
+	return &snapshot, nil
+}
+
+// GetJob returns a copy of the user's export job.
+func (s *dataExportService) GetJob(userID, jobID uuid.UUID) (*ExportJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeExpiredLocked()
+	job, ok := s.jobs[jobID]
+	if !ok || job.UserID != userID {
+		return nil, ErrExportJobNotFound
+	}
+
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// runJob generates an export and records the result on the job.
+func (s *dataExportService) runJob(jobID, userID uuid.UUID) {
+	ctx := context.Background()
+	export, err := s.Export(ctx, userID)
+
+	now := time.Now().UTC()
+	s.mu.Lock()
+	job, ok := s.jobs[jobID]
+	if ok {
+		job.CompletedAt = &now
+		if err != nil {
+			job.Status = ExportJobFailed
+			job.Error = err.Error()
+		} else {
+			job.Status = ExportJobReady
+			job.Export = export
+		}
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userID.String()).Str("job_id", jobID.String()).Msg("Data export failed")
+	}
+	if ok {
+		s.notify(ctx, userID, jobID, err)
+	}
+}
+
+// notify stores an in-app notification telling the user the export finished.
+func (s *dataExportService) notify(ctx context.Context, userID, jobID uuid.UUID, exportErr error) {
+	if s.sink == nil {
+		return
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"job_id": jobID})
+	if err != nil {
+		return
+	}
+
+	title := "Your data export is ready"
+	message := "Download it within 24 hours from your account settings."
+	if exportErr != nil {
+		title = "Your data export failed"
+		message = "Please try again later."
+	}
+
+	if err := s.sink.CreateNotification(ctx, &model.Notification{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      model.NotificationTypeSystem,
+		Title:     title,
+		Message:   message,
+		Data:      string(data),
+		Status:    model.NotificationStatusUnread,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		log.Warn().Err(err).Str("user_id", userID.String()).Msg("Failed to notify user of data export")
+	}
+}
+
+// purgeExpiredLocked drops finished jobs older than ExportJobTTL. The caller
+// must hold s.mu.
+func (s *dataExportService) purgeExpiredLocked() {
+	cutoff := time.Now().Add(-ExportJobTTL)
+	for id, job := range s.jobs {
+		if job.CompletedAt != nil && job.CompletedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// PortfolioExportSource exports the user's paper trading portfolios with their
+// positions, orders and trades.
+func PortfolioExportSource(
+	portfolioRepo repository.PortfolioRepository,
+	positionRepo repository.PositionRepository,
+	orderRepo repository.OrderRepository,
+	tradeRepo repository.TradeRepository,
+) ExportSource {
+	type portfolioExport struct {
+		model.Portfolio
+		Positions []model.Position `json:"positions"`
+		Orders    []model.Order    `json:"orders"`
+		Trades    []model.Trade    `json:"trades"`
+	}
+
+	return func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		portfolios, err := portfolioRepo.GetByUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]portfolioExport, 0, len(portfolios))
+		for _, portfolio := range portfolios {
+			entry := portfolioExport{Portfolio: portfolio}
+			if entry.Positions, err = positionRepo.GetByPortfolioID(portfolio.ID); err != nil {
+				return nil, err
+			}
+			if entry.Orders, err = orderRepo.GetByPortfolioID(portfolio.ID); err != nil {
+				return nil, err
+			}
+			if entry.Trades, err = tradeRepo.GetByPortfolioID(portfolio.ID); err != nil {
+				return nil, err
+			}
+			result = append(result, entry)
+		}
+		return result, nil
+	}
+}
+
+// AuditLogExportSource exports every audit log entry recorded for the user.
+func AuditLogExportSource(auditRepo repository.AuditLogRepository) ExportSource {
+	return func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		logs := make([]model.AuditLog, 0)
+		for offset := 0; ; offset += exportAuditPageSize {
+			page, err := auditRepo.GetByUserID(userID, exportAuditPageSize, offset)
+			if err != nil {
+				return nil, err
+			}
+			logs = append(logs, page...)
+			if len(page) < exportAuditPageSize {
+				return logs, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// chanNotificationSink delivers notifications on a channel so tests can wait
+// for background exports.
+type chanNotificationSink struct {
+	ch chan *model.Notification
+}
+
+func (s *chanNotificationSink) CreateNotification(ctx context.Context, notification *model.Notification) error {
+	s.ch <- notification
+	return nil
+}
+
+func newExportTestService(t *testing.T) (DataExportService, *model.User, *chanNotificationSink) {
+	t.Helper()
+	userRepo := newMockUserRepository()
+	user := &model.User{Email: "export@example.com", Name: "Export User", PasswordHash: "secret-hash"}
+	if err := userRepo.Create(user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	sink := &chanNotificationSink{ch: make(chan *model.Notification, 1)}
+	return NewDataExportService(userRepo, sink), user, sink
+}
+
+func TestDataExportService_Export(t *testing.T) {
+	svc, user, _ := newExportTestService(t)
+
+	portfolioRepo := newMockPortfolioRepository()
+	positionRepo := newMockPositionRepository()
+	orderRepo := newMockOrderRepository()
+	tradeRepo := newMockTradeRepository()
+	portfolio := &model.Portfolio{ID: uuid.New(), UserID: user.ID, Name: "Main", CashBalance: 5000}
+	portfolioRepo.Create(portfolio)
+	positionRepo.Create(&model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Quantity: 10})
+	svc.AddSource("portfolios", PortfolioExportSource(portfolioRepo, positionRepo, orderRepo, tradeRepo))
+
+	auditRepo := newMockAuditLogRepository()
+	for i := 0; i < exportAuditPageSize+3; i++ {
+		auditRepo.Create(&model.AuditLog{UserID: &user.ID, Action: model.AuditActionLogin})
+	}
+	svc.AddSource("audit_logs", AuditLogExportSource(auditRepo))
+
+	export, err := svc.Export(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, section := range []string{"profile", "portfolios", "audit_logs"} {
+		if _, ok := export.Sections[section]; !ok {
+			t.Errorf("Expected section %q in export", section)
+		}
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("Failed to marshal export: %v", err)
+	}
+	var decoded struct {
+		Sections struct {
+			Profile    map[string]interface{}   `json:"profile"`
+			Portfolios []map[string]interface{} `json:"portfolios"`
+			AuditLogs  []interface{}            `json:"audit_logs"`
+		} `json:"sections"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to parse export: %v", err)
+	}
+
+	if decoded.Sections.Profile["email"] != user.Email {
+		t.Errorf("Expected profile email %s, got %v", user.Email, decoded.Sections.Profile["email"])
+	}
+	if _, ok := decoded.Sections.Profile["password_hash"]; ok {
+		t.Error("Export must not include the password hash")
+	}
+	if len(decoded.Sections.Portfolios) != 1 {
+		t.Fatalf("Expected 1 portfolio, got %d", len(decoded.Sections.Portfolios))
+	}
+	if positions, _ := decoded.Sections.Portfolios[0]["positions"].([]interface{}); len(positions) != 1 {
+		t.Errorf("Expected 1 position in portfolio, got %v", decoded.Sections.Portfolios[0]["positions"])
+	}
+	if len(decoded.Sections.AuditLogs) != exportAuditPageSize+3 {
+		t.Errorf("Expected %d audit logs across pages, got %d", exportAuditPageSize+3, len(decoded.Sections.AuditLogs))
+	}
+}
+
+func TestDataExportService_SourceError(t *testing.T) {
+	svc, user, _ := newExportTestService(t)
+	svc.AddSource("bets", func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		return nil, errors.New("database unavailable")
+	})
+
+	if _, err := svc.Export(context.Background(), user.ID); err == nil {
+		t.Error("Expected an error when a section fails")
+	}
+}
+
+func TestDataExportService_StartExport(t *testing.T) {
+	svc, user, sink := newExportTestService(t)
+
+	release := make(chan struct{})
+	svc.AddSource("slow", func(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+		<-release
+		return []string{"done"}, nil
+	})
+
+	job, err := svc.StartExport(user.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if job.Status != ExportJobPending {
+		t.Errorf("Expected status %s, got %s", ExportJobPending, job.Status)
+	}
+
+	if _, err := svc.StartExport(user.ID); err != ErrExportInProgress {
+		t.Errorf("Expected ErrExportInProgress, got %v", err)
+	}
+	if _, err := svc.GetJob(uuid.New(), job.ID); err != ErrExportJobNotFound {
+		t.Errorf("Expected ErrExportJobNotFound for another user, got %v", err)
+	}
+
+	close(release)
+
+	select {
+	case notification := <-sink.ch:
+		if notification.UserID != user.ID || notification.Type != model.NotificationTypeSystem {
+			t.Errorf("Unexpected notification %+v", notification)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for export notification")
+	}
+
+	finished, err := svc.GetJob(user.ID, job.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if finished.Status != ExportJobReady || finished.Export == nil || finished.CompletedAt == nil {
+		t.Errorf("Expected a ready export, got %+v", finished)
+	}
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// Fair value service errors.
+var (
+	ErrValuationUnavailable = errors.New("valuation is not available")
+	ErrInvalidHistoryDays   = errors.New("days must be between 1 and 3650")
+	ErrInvalidThresholds    = errors.New("buy margin of safety must be greater than sell margin of safety")
+)
+
+// DefaultFairValueHistoryDays is the history window returned when none is requested.
+const DefaultFairValueHistoryDays = 365
+
+// Recommendations derived from a stock's margin of safety.
+const (
+	RecommendationBuy  = "buy"
+	RecommendationHold = "hold"
+	RecommendationSell = "sell"
+)
+
+// RecommendationThresholds are the margin-of-safety cutoffs, in percent, at
+// which a stock becomes a buy or a sell. A margin of 20 means the price is 20%
+// below fair value; a margin of -10 means it is 10% above.
+type RecommendationThresholds struct {
+	BuyMargin  float64 `json:"buy_margin_of_safety"`
+	SellMargin float64 `json:"sell_margin_of_safety"`
+}
+
+// DefaultRecommendationThresholds apply to users who have not set their own
+// and to the recommendation stored with each calculation.
+var DefaultRecommendationThresholds = RecommendationThresholds{BuyMargin: 20, SellMargin: -10}
+
+// Validate checks that the buy cutoff lies above the sell cutoff.
+func (t RecommendationThresholds) Validate() error {
+	if t.BuyMargin <= t.SellMargin {
+		return ErrInvalidThresholds
+	}
+	return nil
+}
+
+// Recommend returns buy at or above the buy cutoff, sell at or below the
+// sell cutoff and hold in between.
+func (t RecommendationThresholds) Recommend(marginOfSafety float64) string {
+	switch {
+	case marginOfSafety >= t.BuyMargin:
+		return RecommendationBuy
+	case marginOfSafety <= t.SellMargin:
+		return RecommendationSell
+	default:
+		return RecommendationHold
+	}
+}
+
+// FairValueCalculator computes a fresh fair value for a symbol.
+type FairValueCalculator interface {
+	Calculate(symbol string) (*model.FairValue, error)
+}
+
+// FairValueStore persists fair value calculations.
+type FairValueStore interface {
+	CreateFairValue(ctx context.Context, fv *model.FairValue) error
+	GetLatestFairValue(ctx context.Context, symbol string) (*model.FairValue, error)
+	GetFairValueHistory(ctx context.Context, symbol string, days int) ([]model.FairValue, error)
+	GetTrackedStocks(ctx context.Context) ([]model.Stock, error)
+}
+
+// FairValueAlertStore provides the margin-of-safety alerts users have configured.
+type FairValueAlertStore interface {
+	GetAlertsByType(ctx context.Context, alertType model.AlertType) ([]model.Alert, error)
+	UpdateAlertTrigger(ctx context.Context, alertID uuid.UUID, currentValue float64) error
+}
+
+// FairValueSettingsStore provides users' recommendation thresholds.
+type FairValueSettingsStore interface {
+	GetUserSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error)
+}
+
+// NotificationSink stores in-app notifications for users.
+type NotificationSink interface {
+	CreateNotification(ctx context.Context, notification *model.Notification) error
+}
+
+// FairValueService defines the interface for scheduled fair value recalculation.
+type FairValueService interface {
+	// RecalculateAll recomputes and stores the fair value of every tracked stock
+	// and notifies users whose margin-of-safety threshold was crossed.
+	RecalculateAll(ctx context.Context) (int, error)
+	// Calculate computes a symbol's current fair value without storing it.
+	Calculate(symbol string) (*model.FairValue, error)
+	// GetHistory returns a symbol's fair value calculations over the last days, newest first.
+	GetHistory(ctx context.Context, symbol string, days int) ([]model.FairValue, error)
+	// Thresholds returns the user's recommendation thresholds, falling back to
+	// DefaultRecommendationThresholds.
+	Thresholds(ctx context.Context, userID uuid.UUID) (RecommendationThresholds, error)
+}
+
+// fairValueService implements FairValueService.
+type fairValueService struct {
+	store      FairValueStore
+	calculator FairValueCalculator
+	alerts     FairValueAlertStore
+	sink       NotificationSink
+	settings   FairValueSettingsStore
+}
+
+// NewFairValueService creates a new FairValueService instance.
+// calculator, alerts, sink and settings may be nil; calculation then fails
+// with ErrValuationUnavailable, threshold crossings are not notified and
+// every user gets DefaultRecommendationThresholds.
+func NewFairValueService(store FairValueStore, calculator FairValueCalculator, alerts FairValueAlertStore, sink NotificationSink, settings FairValueSettingsStore) FairValueService {
+	return &fairValueService{
+		store:      store,
+		calculator: calculator,
+		alerts:     alerts,
+		sink:       sink,
+		settings:   settings,
+	}
+}
+
+// RecalculateAll recomputes the fair value of all tracked stocks.
+func (s *fairValueService) RecalculateAll(ctx context.Context) (int, error) {
+	if s.calculator == nil {
+		return 0, ErrValuationUnavailable
+	}
+
+	stocks, err := s.store.GetTrackedStocks(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	thresholds, err := s.thresholdsBySymbol(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	userThresholds := make(map[uuid.UUID]RecommendationThresholds)
+	calculated := 0
+	var errs []error
+	for _, stock := range stocks {
+		previous, err := s.store.GetLatestFairValue(ctx, stock.Symbol)
+		if err != nil {
+			previous = nil
+		}
+
+		fv, err := s.calculator.Calculate(stock.Symbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", stock.Symbol, err))
+			continue
+		}
+		fv.ID = uuid.New()
+		fv.StockID = stock.ID
+		fv.Symbol = stock.Symbol
+		fv.CalculatedAt = time.Now()
+		fv.Recommendation = DefaultRecommendationThresholds.Recommend(fv.MarginOfSafety)
+
+		if err := s.store.CreateFairValue(ctx, fv); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", stock.Symbol, err))
+			continue
+		}
+		calculated++
+
+		if previous == nil {
+			continue
+		}
+		for _, alert := range thresholds[stock.Symbol] {
+			if alert.TargetValue == 0 {
+				// The alert follows its owner's buy or sell cutoff.
+				personal, ok := userThresholds[alert.UserID]
+				if !ok {
+					personal, err = s.Thresholds(ctx, alert.UserID)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("%s: %w", stock.Symbol, err))
+						continue
+					}
+					userThresholds[alert.UserID] = personal
+				}
+				alert.TargetValue = personal.BuyMargin
+				if alert.Condition == model.AlertConditionBelow {
+					alert.TargetValue = personal.SellMargin
+				}
+			}
+			if !marginCrossed(alert, previous.MarginOfSafety, fv.MarginOfSafety) {
+				continue
+			}
+			if err := s.notify(ctx, alert, fv); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", stock.Symbol, err))
+			}
+		}
+	}
+
+	return calculated, errors.Join(errs...)
+}
+
+// Calculate computes a symbol's current fair value.
+func (s *fairValueService) Calculate(symbol string) (*model.FairValue, error) {
+	if s.calculator == nil {
+		return nil, ErrValuationUnavailable
+	}
+	return s.calculator.Calculate(strings.ToUpper(symbol))
+}
+
+// GetHistory retrieves fair value history for a symbol.
+func (s *fairValueService) GetHistory(ctx context.Context, symbol string, days int) ([]model.FairValue, error) {
+	if days == 0 {
+		days = DefaultFairValueHistoryDays
+	}
+	if days < 1 || days > 3650 {
+		return nil, ErrInvalidHistoryDays
+	}
+	return s.store.GetFairValueHistory(ctx, strings.ToUpper(symbol), days)
+}
+
+// Thresholds returns the user's recommendation thresholds.
+func (s *fairValueService) Thresholds(ctx context.Context, userID uuid.UUID) (RecommendationThresholds, error) {
+	if s.settings == nil {
+		return DefaultRecommendationThresholds, nil
+	}
+	settings, err := s.settings.GetUserSettings(ctx, userID)
+	if err != nil {
+		return RecommendationThresholds{}, err
+	}
+	thresholds := RecommendationThresholds{BuyMargin: settings.BuyMarginOfSafety, SellMargin: settings.SellMarginOfSafety}
+	if thresholds.Validate() != nil {
+		return DefaultRecommendationThresholds, nil
+	}
+	return thresholds, nil
+}
+
+// thresholdsBySymbol groups active fair value alerts by symbol.
+func (s *fairValueService) thresholdsBySymbol(ctx context.Context) (map[string][]model.Alert, error) {
+	result := make(map[string][]model.Alert)
+	if s.alerts == nil {
+		return result, nil
+	}
+
+	alerts, err := s.alerts.GetAlertsByType(ctx, model.AlertTypeFairValue)
+	if err != nil {
+		return nil, err
+	}
+	for _, alert := range alerts {
+		symbol := strings.ToUpper(alert.Symbol)
+		result[symbol] = append(result[symbol], alert)
+	}
+	return result, nil
+}
+
+// marginCrossed reports whether the margin of safety moved across the alert's
+// threshold. Below alerts fire when it falls through the threshold; all other
+// conditions fire when it rises through it.
+func marginCrossed(alert model.Alert, previous, current float64) bool {
+	if alert.Condition == model.AlertConditionBelow {
+		return previous > alert.TargetValue && current <= alert.TargetValue
+	}
+	return previous < alert.TargetValue && current >= alert.TargetValue
+}
+
+// notify records the alert trigger and stores an in-app notification.
+func (s *fairValueService) notify(ctx context.Context, alert model.Alert, fv *model.FairValue) error {
+	if err := s.alerts.UpdateAlertTrigger(ctx, alert.ID, fv.MarginOfSafety); err != nil {
+		return err
+	}
+	if s.sink == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"alert_id":         alert.ID,
+		"symbol":           fv.Symbol,
+		"fair_value":       fv.WeightedAvg,
+		"current_price":    fv.CurrentPrice,
+		"margin_of_safety": fv.MarginOfSafety,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification data: %w", err)
+	}
+
+	return s.sink.CreateNotification(ctx, &model.Notification{
+		ID:     uuid.New(),
+		UserID: alert.UserID,
+		Type:   model.NotificationTypeAlert,
+		Title:  fmt.Sprintf("%s margin of safety crossed %.1f%%", fv.Symbol, alert.TargetValue),
+		Message: fmt.Sprintf("%s now trades at %.2f against a fair value of %.2f (margin of safety %.1f%%)",
+			fv.Symbol, fv.CurrentPrice, fv.WeightedAvg, fv.MarginOfSafety),
+		Data:      string(data),
+		Status:    model.NotificationStatusUnread,
+		CreatedAt: time.Now(),
+	})
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// mockFairValueStore keeps fair value calculations in memory.
+type mockFairValueStore struct {
+	stocks []model.Stock
+	values []model.FairValue
+}
+
+func (m *mockFairValueStore) CreateFairValue(ctx context.Context, fv *model.FairValue) error {
+	m.values = append(m.values, *fv)
+	return nil
+}
+
+func (m *mockFairValueStore) GetLatestFairValue(ctx context.Context, symbol string) (*model.FairValue, error) {
+	for i := len(m.values) - 1; i >= 0; i-- {
+		if m.values[i].Symbol == symbol {
+			return &m.values[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockFairValueStore) GetFairValueHistory(ctx context.Context, symbol string, days int) ([]model.FairValue, error) {
+	var result []model.FairValue
+	for i := len(m.values) - 1; i >= 0; i-- {
+		if m.values[i].Symbol == symbol {
+			result = append(result, m.values[i])
+		}
+	}
+	return result, nil
+}
+
+func (m *mockFairValueStore) GetTrackedStocks(ctx context.Context) ([]model.Stock, error) {
+	return m.stocks, nil
+}
+
+// mockFairValueCalculator returns a fixed margin of safety per symbol.
+type mockFairValueCalculator struct {
+	margins map[string]float64
+}
+
+func (m *mockFairValueCalculator) Calculate(symbol string) (*model.FairValue, error) {
+	return &model.FairValue{WeightedAvg: 100, CurrentPrice: 100 - m.margins[symbol], MarginOfSafety: m.margins[symbol]}, nil
+}
+
+// mockFairValueAlertStore returns configured alerts and records triggers.
+type mockFairValueAlertStore struct {
+	alerts    []model.Alert
+	triggered []uuid.UUID
+}
+
+func (m *mockFairValueAlertStore) GetAlertsByType(ctx context.Context, alertType model.AlertType) ([]model.Alert, error) {
+	var result []model.Alert
+	for _, a := range m.alerts {
+		if a.Type == alertType {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockFairValueAlertStore) UpdateAlertTrigger(ctx context.Context, alertID uuid.UUID, currentValue float64) error {
+	m.triggered = append(m.triggered, alertID)
+	return nil
+}
+
+// mockFairValueSettingsStore returns per-user settings.
+type mockFairValueSettingsStore struct {
+	settings map[uuid.UUID]*model.Settings
+}
+
+func (m *mockFairValueSettingsStore) GetUserSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error) {
+	if settings, ok := m.settings[userID]; ok {
+		return settings, nil
+	}
+	return &model.Settings{UserID: userID, BuyMarginOfSafety: 20, SellMarginOfSafety: -10}, nil
+}
+
+func TestFairValueService_RecalculateAll(t *testing.T) {
+	store := &mockFairValueStore{stocks: []model.Stock{{ID: uuid.New(), Symbol: "AAPL"}, {ID: uuid.New(), Symbol: "MSFT"}}}
+	calculator := &mockFairValueCalculator{margins: map[string]float64{"AAPL": 10, "MSFT": 30}}
+	userID := uuid.New()
+	rising := model.Alert{ID: uuid.New(), UserID: userID, Type: model.AlertTypeFairValue, Symbol: "AAPL", Condition: model.AlertConditionAbove, TargetValue: 20, Active: true}
+	falling := model.Alert{ID: uuid.New(), UserID: userID, Type: model.AlertTypeFairValue, Symbol: "MSFT", Condition: model.AlertConditionBelow, TargetValue: 20, Active: true}
+	alerts := &mockFairValueAlertStore{alerts: []model.Alert{rising, falling}}
+	sink := &mockNotificationSink{}
+
+	svc := NewFairValueService(store, calculator, alerts, sink, nil)
+
+	// The first calculation has nothing to compare against.
+	n, err := svc.RecalculateAll(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("Expected 2 calculations, got %d", n)
+	}
+	if len(sink.notifications) != 0 {
+		t.Fatalf("Expected no notifications on first run, got %d", len(sink.notifications))
+	}
+
+	// AAPL rises through 20% and MSFT falls through 20%.
+	calculator.margins = map[string]float64{"AAPL": 25, "MSFT": 15}
+	if _, err := svc.RecalculateAll(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sink.notifications) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(sink.notifications))
+	}
+	if len(alerts.triggered) != 2 {
+		t.Errorf("Expected 2 alert triggers, got %d", len(alerts.triggered))
+	}
+
+	// Staying on the same side of the threshold does not notify again.
+	calculator.margins = map[string]float64{"AAPL": 26, "MSFT": 14}
+	if _, err := svc.RecalculateAll(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sink.notifications) != 2 {
+		t.Errorf("Expected no new notifications, got %d total", len(sink.notifications))
+	}
+
+	history, err := svc.GetHistory(context.Background(), "aapl", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 history entries, got %d", len(history))
+	}
+	if history[0].MarginOfSafety != 26 || history[0].StockID != store.stocks[0].ID {
+		t.Errorf("Expected newest entry first with stock ID set, got %+v", history[0])
+	}
+}
+
+func TestFairValueService_Unavailable(t *testing.T) {
+	svc := NewFairValueService(&mockFairValueStore{}, nil, nil, nil, nil)
+
+	if _, err := svc.RecalculateAll(context.Background()); err != ErrValuationUnavailable {
+		t.Errorf("Expected ErrValuationUnavailable, got %v", err)
+	}
+	if _, err := svc.Calculate("AAPL"); err != ErrValuationUnavailable {
+		t.Errorf("Expected ErrValuationUnavailable from Calculate, got %v", err)
+	}
+	if _, err := svc.GetHistory(context.Background(), "AAPL", -1); err != ErrInvalidHistoryDays {
+		t.Errorf("Expected ErrInvalidHistoryDays, got %v", err)
+	}
+}
+
+func TestRecommendationThresholds_Recommend(t *testing.T) {
+	thresholds := RecommendationThresholds{BuyMargin: 20, SellMargin: -10}
+	tests := []struct {
+		margin float64
+		want   string
+	}{
+		{35, RecommendationBuy},
+		{20, RecommendationBuy},
+		{19.9, RecommendationHold},
+		{0, RecommendationHold},
+		{-10, RecommendationSell},
+		{-25, RecommendationSell},
+	}
+	for _, tt := range tests {
+		if got := thresholds.Recommend(tt.margin); got != tt.want {
+			t.Errorf("Recommend(%v) = %q, want %q", tt.margin, got, tt.want)
+		}
+	}
+
+	if err := (RecommendationThresholds{BuyMargin: 5, SellMargin: 5}).Validate(); err != ErrInvalidThresholds {
+		t.Errorf("Expected ErrInvalidThresholds, got %v", err)
+	}
+}
+
+func TestFairValueService_PersonalThresholds(t *testing.T) {
+	store := &mockFairValueStore{stocks: []model.Stock{{ID: uuid.New(), Symbol: "AAPL"}}}
+	calculator := &mockFairValueCalculator{margins: map[string]float64{"AAPL": 10}}
+	aggressive, conservative := uuid.New(), uuid.New()
+	settings := &mockFairValueSettingsStore{settings: map[uuid.UUID]*model.Settings{
+		aggressive:   {UserID: aggressive, BuyMarginOfSafety: 5, SellMarginOfSafety: -20},
+		conservative: {UserID: conservative, BuyMarginOfSafety: 30, SellMarginOfSafety: 0},
+	}}
+	// Alerts without a target follow their owner's buy cutoff.
+	alerts := &mockFairValueAlertStore{alerts: []model.Alert{
+		{ID: uuid.New(), UserID: aggressive, Type: model.AlertTypeFairValue, Symbol: "AAPL", Condition: model.AlertConditionAbove, Active: true},
+		{ID: uuid.New(), UserID: conservative, Type: model.AlertTypeFairValue, Symbol: "AAPL", Condition: model.AlertConditionAbove, Active: true},
+	}}
+	sink := &mockNotificationSink{}
+
+	svc := NewFairValueService(store, calculator, alerts, sink, settings)
+
+	thresholds, err := svc.Thresholds(context.Background(), conservative)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if thresholds.Recommend(25) != RecommendationHold {
+		t.Errorf("Expected a 25%% margin to be a hold for the conservative user")
+	}
+
+	if _, err := svc.RecalculateAll(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if store.values[0].Recommendation != RecommendationHold {
+		t.Errorf("Expected default recommendation hold, got %q", store.values[0].Recommendation)
+	}
+
+	// Rising from 3% to 12% crosses the aggressive user's 5% buy cutoff but
+	// not the conservative user's 30%.
+	calculator.margins["AAPL"] = 3
+	svc.RecalculateAll(context.Background())
+	calculator.margins["AAPL"] = 12
+	if _, err := svc.RecalculateAll(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(alerts.triggered) != 1 || alerts.triggered[0] != alerts.alerts[0].ID {
+		t.Fatalf("Expected only the aggressive user's alert to trigger, got %v", alerts.triggered)
+	}
+}
+
+func TestFairValueService_ThresholdsDefault(t *testing.T) {
+	svc := NewFairValueService(&mockFairValueStore{}, nil, nil, nil, nil)
+
+	thresholds, err := svc.Thresholds(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if thresholds != DefaultRecommendationThresholds {
+		t.Errorf("Expected default thresholds, got %+v", thresholds)
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Favorite service errors.
+var (
+	ErrInvalidEntityType = errors.New("entity_type must be one of: stock, match, portfolio")
+	ErrInvalidEntityID   = errors.New("invalid entity id")
+	ErrFavoriteExists    = errors.New("entity is already a favorite")
+	ErrFavoriteNotFound  = errors.New("favorite not found")
+)
+
+// FavoriteService defines the interface for user favorites across entity types.
+type FavoriteService interface {
+	AddFavorite(userID uuid.UUID, entityType model.FavoriteEntityType, entityID string) (*model.Favorite, error)
+	RemoveFavorite(userID uuid.UUID, entityType model.FavoriteEntityType, entityID string) error
+	// ListFavorites lists a user's favorites; an empty entityType lists all types.
+	ListFavorites(userID uuid.UUID, entityType model.FavoriteEntityType) ([]model.Favorite, error)
+}
+
+// favoriteService implements FavoriteService.
+type favoriteService struct {
+	favoriteRepo repository.FavoriteRepository
+}
+
+// NewFavoriteService creates a new FavoriteService instance.
+func NewFavoriteService(favoriteRepo repository.FavoriteRepository) FavoriteService {
+	return &favoriteService{favoriteRepo: favoriteRepo}
+}
+
+// AddFavorite pins an entity for a user.
+func (s *favoriteService) AddFavorite(userID uuid.UUID, entityType model.FavoriteEntityType, entityID string) (*model.Favorite, error) {
+	entityID, err := normalizeFavoriteEntity(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.favoriteRepo.GetByUserAndEntity(userID, entityType, entityID); err == nil {
+		return nil, ErrFavoriteExists
+	}
+
+	favorite := &model.Favorite{
+		ID:         uuid.New(),
+		UserID:     userID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.favoriteRepo.Create(favorite); err != nil {
+		return nil, err
+	}
+
+	return favorite, nil
+}
+
+// RemoveFavorite unpins an entity for a user.
+func (s *favoriteService) RemoveFavorite(userID uuid.UUID, entityType model.FavoriteEntityType, entityID string) error {
+	entityID, err := normalizeFavoriteEntity(entityType, entityID)
+	if err != nil {
+		return err
+	}
+
+	favorite, err := s.favoriteRepo.GetByUserAndEntity(userID, entityType, entityID)
+	if err != nil {
+		return ErrFavoriteNotFound
+	}
+
+	return s.favoriteRepo.Delete(favorite.ID)
+}
+
+// ListFavorites lists a user's favorites.
+func (s *favoriteService) ListFavorites(userID uuid.UUID, entityType model.FavoriteEntityType) ([]model.Favorite, error) {
+	if entityType != "" && !entityType.IsValid() {
+		return nil, ErrInvalidEntityType
+	}
+	return s.favoriteRepo.GetByUserID(userID, entityType)
+}
+
+// normalizeFavoriteEntity validates the entity type and returns the canonical entity ID.
+// Stocks are keyed by upper-case symbol; matches and portfolios by UUID.
+func normalizeFavoriteEntity(entityType model.FavoriteEntityType, entityID string) (string, error) {
+	if !entityType.IsValid() {
+		return "", ErrInvalidEntityType
+	}
+
+	entityID = strings.TrimSpace(entityID)
+	if entityID == "" {
+		return "", ErrInvalidEntityID
+	}
+
+	if entityType == model.FavoriteEntityStock {
+		return strings.ToUpper(entityID), nil
+	}
+
+	id, err := uuid.Parse(entityID)
+	if err != nil {
+		return "", ErrInvalidEntityID
+	}
+	return id.String(), nil
+}
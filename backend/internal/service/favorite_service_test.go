@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+func TestFavoriteService_AddFavorite(t *testing.T) {
+	svc := NewFavoriteService(repository.NewInMemoryFavoriteRepository())
+	userID := uuid.New()
+	matchID := uuid.New().String()
+
+	tests := []struct {
+		name       string
+		entityType model.FavoriteEntityType
+		entityID   string
+		wantID     string
+		wantErr    error
+	}{
+		{name: "stock symbol is upper-cased", entityType: model.FavoriteEntityStock, entityID: "aapl", wantID: "AAPL"},
+		{name: "duplicate stock", entityType: model.FavoriteEntityStock, entityID: "AAPL", wantErr: ErrFavoriteExists},
+		{name: "match by uuid", entityType: model.FavoriteEntityMatch, entityID: matchID, wantID: matchID},
+		{name: "match with invalid id", entityType: model.FavoriteEntityMatch, entityID: "not-a-uuid", wantErr: ErrInvalidEntityID},
+		{name: "unknown entity type", entityType: "team", entityID: "x", wantErr: ErrInvalidEntityType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			favorite, err := svc.AddFavorite(userID, tt.entityType, tt.entityID)
+			if err != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && favorite.EntityID != tt.wantID {
+				t.Errorf("Expected entity ID %s, got %s", tt.wantID, favorite.EntityID)
+			}
+		})
+	}
+}
+
+func TestFavoriteService_ListAndRemove(t *testing.T) {
+	svc := NewFavoriteService(repository.NewInMemoryFavoriteRepository())
+	userID := uuid.New()
+
+	svc.AddFavorite(userID, model.FavoriteEntityStock, "MSFT")
+	svc.AddFavorite(userID, model.FavoriteEntityPortfolio, uuid.New().String())
+	svc.AddFavorite(uuid.New(), model.FavoriteEntityStock, "MSFT")
+
+	all, _ := svc.ListFavorites(userID, "")
+	if len(all) != 2 {
+		t.Errorf("Expected 2 favorites, got %d", len(all))
+	}
+
+	stocks, _ := svc.ListFavorites(userID, model.FavoriteEntityStock)
+	if len(stocks) != 1 {
+		t.Errorf("Expected 1 stock favorite, got %d", len(stocks))
+	}
+
+	if _, err := svc.ListFavorites(userID, "team"); err != ErrInvalidEntityType {
+		t.Errorf("Expected ErrInvalidEntityType, got %v", err)
+	}
+
+	if err := svc.RemoveFavorite(userID, model.FavoriteEntityStock, "msft"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := svc.RemoveFavorite(userID, model.FavoriteEntityStock, "MSFT"); err != ErrFavoriteNotFound {
+		t.Errorf("Expected ErrFavoriteNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/awaymess/super-dashboard/backend/internal/bankroll"
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// Kelly stake errors
+var (
+	ErrInvalidKellyOdds        = errors.New("odds must be greater than 1")
+	ErrInvalidKellyProbability = errors.New("true probability must be between 0 and 1")
+)
+
+// DefaultKellyFactor applies when a user's Kelly factor is not positive. It
+// matches the Settings column default.
+const DefaultKellyFactor = 0.5
+
+// KellySettingsStore provides users' bankroll and staking settings.
+type KellySettingsStore interface {
+	GetUserSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error)
+}
+
+// KellyRecommendation is the stake suggested for a bet.
+type KellyRecommendation struct {
+	Odds            float64 `json:"odds"`
+	TrueProbability float64 `json:"true_probability"`
+	// Edge is the expected return per unit staked, trueProb*odds - 1.
+	Edge        float64 `json:"edge"`
+	KellyFactor float64 `json:"kelly_factor"`
+	Bankroll    float64 `json:"bankroll"`
+	Stake       float64 `json:"stake"`
+	// Capped reports whether the stake was cut to the user's max stake per bet.
+	Capped bool `json:"capped"`
+}
+
+// KellyService defines the interface for sizing bets with the Kelly criterion.
+type KellyService interface {
+	Stake(ctx context.Context, userID uuid.UUID, odds, trueProb float64) (*KellyRecommendation, error)
+}
+
+// kellyService implements KellyService.
+type kellyService struct {
+	settings KellySettingsStore
+}
+
+// NewKellyService creates a new KellyService instance.
+func NewKellyService(settings KellySettingsStore) KellyService {
+	return &kellyService{settings: settings}
+}
+
+// Stake sizes a bet at decimal odds with fractional Kelly against the user's
+// current bankroll and Kelly factor, capped at their max stake per bet when
+// one is set.
+func (s *kellyService) Stake(ctx context.Context, userID uuid.UUID, odds, trueProb float64) (*KellyRecommendation, error) {
+	if odds <= 1 {
+		return nil, ErrInvalidKellyOdds
+	}
+	if trueProb <= 0 || trueProb >= 1 {
+		return nil, ErrInvalidKellyProbability
+	}
+
+	settings, err := s.settings.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	kellyFactor := settings.KellyFactor
+	if kellyFactor <= 0 {
+		kellyFactor = DefaultKellyFactor
+	}
+
+	rec := &KellyRecommendation{
+		Odds:            odds,
+		TrueProbability: trueProb,
+		Edge:            trueProb*odds - 1,
+		KellyFactor:     kellyFactor,
+		Bankroll:        settings.CurrentBankroll,
+		Stake:           bankroll.KellyStake(settings.CurrentBankroll, odds, trueProb, kellyFactor),
+	}
+	if settings.MaxStakePerBet > 0 && rec.Stake > settings.MaxStakePerBet {
+		rec.Stake = settings.MaxStakePerBet
+		rec.Capped = true
+	}
+	return rec, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// stubKellySettings returns the same settings for every user.
+type stubKellySettings struct {
+	settings model.Settings
+}
+
+func (s *stubKellySettings) GetUserSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error) {
+	settings := s.settings
+	return &settings, nil
+}
+
+func TestKellyService_Stake(t *testing.T) {
+	tests := []struct {
+		name       string
+		settings   model.Settings
+		odds       float64
+		trueProb   float64
+		wantStake  float64
+		wantCapped bool
+	}{
+		{"positive edge", model.Settings{CurrentBankroll: 1000, KellyFactor: 0.5}, 2.0, 0.6, 100, false},
+		{"zero edge", model.Settings{CurrentBankroll: 1000, KellyFactor: 0.5}, 2.0, 0.5, 0, false},
+		{"negative edge", model.Settings{CurrentBankroll: 1000, KellyFactor: 0.5}, 2.0, 0.3, 0, false},
+		{"capped", model.Settings{CurrentBankroll: 1000, KellyFactor: 1, MaxStakePerBet: 50}, 2.0, 0.6, 50, true},
+		{"below cap", model.Settings{CurrentBankroll: 1000, KellyFactor: 0.5, MaxStakePerBet: 500}, 2.0, 0.6, 100, false},
+		{"default kelly factor", model.Settings{CurrentBankroll: 1000}, 2.0, 0.6, 100, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewKellyService(&stubKellySettings{settings: tt.settings})
+			rec, err := svc.Stake(context.Background(), uuid.New(), tt.odds, tt.trueProb)
+			if err != nil {
+				t.Fatalf("Stake() error = %v", err)
+			}
+			if math.Abs(rec.Stake-tt.wantStake) > 1e-9 || rec.Capped != tt.wantCapped {
+				t.Errorf("Stake() = %v capped %v, want %v capped %v", rec.Stake, rec.Capped, tt.wantStake, tt.wantCapped)
+			}
+			if want := tt.trueProb*tt.odds - 1; math.Abs(rec.Edge-want) > 1e-9 {
+				t.Errorf("Stake() edge = %v, want %v", rec.Edge, want)
+			}
+		})
+	}
+}
+
+func TestKellyService_Stake_Invalid(t *testing.T) {
+	svc := NewKellyService(&stubKellySettings{settings: model.Settings{CurrentBankroll: 1000}})
+	tests := []struct {
+		name     string
+		odds     float64
+		trueProb float64
+		want     error
+	}{
+		{"odds of one", 1, 0.6, ErrInvalidKellyOdds},
+		{"zero probability", 2, 0, ErrInvalidKellyProbability},
+		{"certain probability", 2, 1, ErrInvalidKellyProbability},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Stake(context.Background(), uuid.New(), tt.odds, tt.trueProb); err != tt.want {
+				t.Errorf("Stake() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
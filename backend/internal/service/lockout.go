@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Login lockout defaults used when AuthServiceConfig fields are zero.
+const (
+	DefaultFailedLoginThreshold = 5
+	DefaultLockoutDuration      = 15 * time.Minute
+)
+
+// failedLoginKey returns the TokenStore key failed logins for email are
+// counted under.
+func failedLoginKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// checkLoginLockout returns ErrAccountLocked when email has reached the
+// failed login threshold. Lockout is skipped without a token store, and
+// token store errors let the login through rather than lock everyone out.
+func (s *extendedAuthService) checkLoginLockout(email string) error {
+	if s.tokenStore == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := s.tokenStore.GetFailedLogins(ctx, failedLoginKey(email))
+	if err != nil {
+		return nil
+	}
+	if count >= int64(s.failedLoginThreshold) {
+		return ErrAccountLocked
+	}
+	return nil
+}
+
+// recordFailedLogin counts a failed login for email. Unknown emails are
+// counted too, so lockout does not reveal which accounts exist.
+func (s *extendedAuthService) recordFailedLogin(email string) {
+	if s.tokenStore == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _ = s.tokenStore.IncrementFailedLogins(ctx, failedLoginKey(email), s.lockoutDuration)
+}
+
+// clearFailedLogins resets the failed login count for email after a
+// successful login.
+func (s *extendedAuthService) clearFailedLogins(email string) {
+	if s.tokenStore == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = s.tokenStore.ResetFailedLogins(ctx, failedLoginKey(email))
+}
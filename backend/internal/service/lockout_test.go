@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExtendedAuthService_LoginLockout(t *testing.T) {
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:             newMockUserRepository(),
+		TokenStore:           tokenStore,
+		JWTSecret:            "test-secret",
+		FailedLoginThreshold: 3,
+		LockoutDuration:      time.Minute,
+	})
+
+	if _, err := authService.Register("lockout@example.com", "password123", "Lockout User"); err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	// A successful login clears earlier failures
+	for i := 0; i < 2; i++ {
+		if _, _, err := authService.Login("lockout@example.com", "wrongpassword"); err != ErrInvalidCredentials {
+			t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+		}
+	}
+	if _, _, err := authService.Login("lockout@example.com", "password123"); err != nil {
+		t.Fatalf("Expected login to succeed below the threshold, got %v", err)
+	}
+	if _, ok := tokenStore.failedLogins[failedLoginKey("lockout@example.com")]; ok {
+		t.Error("Expected successful login to clear failed logins")
+	}
+
+	// Reaching the threshold locks the account, even for the right password
+	for i := 0; i < 3; i++ {
+		if _, _, err := authService.Login("Lockout@Example.com", "wrongpassword"); err != ErrInvalidCredentials {
+			t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+		}
+	}
+	if _, _, err := authService.Login("lockout@example.com", "password123"); err != ErrAccountLocked {
+		t.Errorf("Expected ErrAccountLocked, got %v", err)
+	}
+	if _, _, err := authService.ValidateLoginWith2FA("lockout@example.com", "password123", "123456"); err != ErrAccountLocked {
+		t.Errorf("Expected ErrAccountLocked for 2FA login, got %v", err)
+	}
+
+	// The lockout clears once the window has passed
+	key := failedLoginKey("lockout@example.com")
+	stored := tokenStore.failedLogins[key]
+	stored.expiresAt = time.Now().Add(-time.Second)
+	tokenStore.failedLogins[key] = stored
+
+	if _, _, err := authService.Login("lockout@example.com", "password123"); err != nil {
+		t.Errorf("Expected login to succeed after the lockout expired, got %v", err)
+	}
+}
+
+func TestExtendedAuthService_LoginLockout_UnknownEmail(t *testing.T) {
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:   newMockUserRepository(),
+		TokenStore: tokenStore,
+		JWTSecret:  "test-secret",
+	})
+
+	for i := 0; i < DefaultFailedLoginThreshold; i++ {
+		if _, _, err := authService.Login("nobody@example.com", "password123"); err != ErrInvalidCredentials {
+			t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+		}
+	}
+	if _, _, err := authService.Login("nobody@example.com", "password123"); err != ErrAccountLocked {
+		t.Errorf("Expected unknown emails to lock like existing ones, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrQuotesUnavailable is returned when no live quote source is configured.
+var ErrQuotesUnavailable = errors.New("live quotes are not available")
+
+// MarketQuote is a live price and its change since the previous close.
+type MarketQuote struct {
+	Price         float64
+	Change        float64
+	ChangePercent float64
+}
+
+// QuoteSource fetches live quotes.
+type QuoteSource interface {
+	GetQuote(ctx context.Context, symbol string) (*MarketQuote, error)
+}
+
+// mockQuoteSource serves MockPriceProvider prices as quotes without a day change.
+type mockQuoteSource struct {
+	prices MockPriceProvider
+}
+
+// NewMockQuoteSource creates a QuoteSource backed by mock prices.
+func NewMockQuoteSource(prices MockPriceProvider) QuoteSource {
+	return &mockQuoteSource{prices: prices}
+}
+
+// GetQuote returns the mock price for a symbol.
+func (s *mockQuoteSource) GetQuote(ctx context.Context, symbol string) (*MarketQuote, error) {
+	return &MarketQuote{Price: s.prices.GetPrice(symbol)}, nil
+}
+
+// PositionValuation is a position valued at its current price.
+type PositionValuation struct {
+	model.Position
+	MarketValue         float64 `json:"market_value"`
+	CostBasis           float64 `json:"cost_basis"`
+	UnrealizedPL        float64 `json:"unrealized_pl"`
+	UnrealizedPLPercent float64 `json:"unrealized_pl_percent"`
+	// DayChangeValue is the position's value change since the previous close.
+	DayChangeValue float64 `json:"day_change_value"`
+	// Stale is set when no quote could be fetched and the stored price was kept.
+	Stale bool `json:"stale"`
+}
+
+// PortfolioValuation is a portfolio marked to market.
+type PortfolioValuation struct {
+	ID                  uuid.UUID           `json:"id"`
+	UserID              uuid.UUID           `json:"user_id"`
+	Name                string              `json:"name"`
+	CashBalance         float64             `json:"cash_balance"`
+	MarketValue         float64             `json:"market_value"`
+	CostBasis           float64             `json:"cost_basis"`
+	UnrealizedPL        float64             `json:"unrealized_pl"`
+	UnrealizedPLPercent float64             `json:"unrealized_pl_percent"`
+	DayChange           float64             `json:"day_change"`
+	TotalValue          float64             `json:"total_value"`
+	Positions           []PositionValuation `json:"positions"`
+	RefreshedAt         time.Time           `json:"refreshed_at"`
+}
+
+// MarkToMarketService defines the interface for revaluing paper positions at
+// live prices.
+type MarkToMarketService interface {
+	// RefreshPortfolio updates the current price and day change of every
+	// position in the portfolio and returns the revalued portfolio.
+	RefreshPortfolio(ctx context.Context, portfolioID uuid.UUID) (*PortfolioValuation, error)
+	// RefreshAll refreshes every portfolio and returns the number of
+	// positions updated. Each symbol is quoted once per call.
+	RefreshAll(ctx context.Context) (int, error)
+}
+
+// markToMarketService implements MarkToMarketService.
+type markToMarketService struct {
+	portfolioRepo repository.PortfolioRepository
+	positionRepo  repository.PositionRepository
+	quotes        QuoteSource
+	now           func() time.Time
+}
+
+// NewMarkToMarketService creates a new MarkToMarketService instance.
+// quotes may be nil; refreshes then fail with ErrQuotesUnavailable.
+func NewMarkToMarketService(portfolioRepo repository.PortfolioRepository, positionRepo repository.PositionRepository, quotes QuoteSource) MarkToMarketService {
+	return &markToMarketService{
+		portfolioRepo: portfolioRepo,
+		positionRepo:  positionRepo,
+		quotes:        quotes,
+		now:           time.Now,
+	}
+}
+
+// RefreshPortfolio marks one portfolio to market.
+func (s *markToMarketService) RefreshPortfolio(ctx context.Context, portfolioID uuid.UUID) (*PortfolioValuation, error) {
+	if s.quotes == nil {
+		return nil, ErrQuotesUnavailable
+	}
+
+	portfolio, err := s.portfolioRepo.GetByID(portfolioID)
+	if err != nil {
+		return nil, ErrPortfolioNotFound
+	}
+
+	valuation, _, err := s.refresh(ctx, portfolio, make(map[string]*MarketQuote))
+	return valuation, err
+}
+
+// RefreshAll marks every portfolio to market.
+func (s *markToMarketService) RefreshAll(ctx context.Context) (int, error) {
+	if s.quotes == nil {
+		return 0, ErrQuotesUnavailable
+	}
+
+	portfolios, err := s.portfolioRepo.List()
+	if err != nil {
+		return 0, err
+	}
+
+	quotes := make(map[string]*MarketQuote)
+	total := 0
+	for i := range portfolios {
+		if ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+		_, updated, err := s.refresh(ctx, &portfolios[i], quotes)
+		if err != nil {
+			log.Error().Err(err).Str("portfolio_id", portfolios[i].ID.String()).Msg("Failed to mark portfolio to market")
+			continue
+		}
+		total += updated
+	}
+	return total, nil
+}
+
+// refresh updates the portfolio's positions from quotes, fetching symbols not
+// yet in the cache, and returns the valuation and number of positions updated.
+func (s *markToMarketService) refresh(ctx context.Context, portfolio *model.Portfolio, quotes map[string]*MarketQuote) (*PortfolioValuation, int, error) {
+	positions, err := s.positionRepo.GetByPortfolioID(portfolio.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := s.now()
+	valuation := &PortfolioValuation{
+		ID:          portfolio.ID,
+		UserID:      portfolio.UserID,
+		Name:        portfolio.Name,
+		CashBalance: portfolio.CashBalance,
+		Positions:   make([]PositionValuation, 0, len(positions)),
+		RefreshedAt: now,
+	}
+
+	updated := 0
+	for i := range positions {
+		position := &positions[i]
+		quote := s.quote(ctx, position.Symbol, quotes)
+		if quote != nil {
+			position.CurrentPrice = quote.Price
+			position.DayChange = quote.Change
+			position.DayChangePercent = quote.ChangePercent
+			position.PriceUpdatedAt = &now
+			position.UpdatedAt = now
+			if err := s.positionRepo.Update(position); err != nil {
+				return nil, updated, err
+			}
+			updated++
+		}
+		valuation.Positions = append(valuation.Positions, valuePosition(position, quote == nil))
+	}
+
+	for _, position := range valuation.Positions {
+		valuation.MarketValue += position.MarketValue
+		valuation.CostBasis += position.CostBasis
+		valuation.DayChange += position.DayChangeValue
+	}
+	valuation.UnrealizedPL = valuation.MarketValue - valuation.CostBasis
+	if valuation.CostBasis > 0 {
+		valuation.UnrealizedPLPercent = valuation.UnrealizedPL / valuation.CostBasis * 100
+	}
+	valuation.TotalValue = valuation.CashBalance + valuation.MarketValue
+
+	return valuation, updated, nil
+}
+
+// quote returns the cached quote for a symbol, fetching it on first use. A
+// failed fetch is cached as nil so the symbol is not retried in the same pass.
+func (s *markToMarketService) quote(ctx context.Context, symbol string, quotes map[string]*MarketQuote) *MarketQuote {
+	if quote, ok := quotes[symbol]; ok {
+		return quote
+	}
+
+	quote, err := s.quotes.GetQuote(ctx, symbol)
+	if err != nil || quote.Price <= 0 {
+		log.Warn().Err(err).Str("symbol", symbol).Msg("No live quote, keeping stored price")
+		quote = nil
+	}
+	quotes[symbol] = quote
+	return quote
+}
+
+// valuePosition values a position at its current price.
+func valuePosition(position *model.Position, stale bool) PositionValuation {
+	quantity := float64(position.Quantity)
+	valuation := PositionValuation{
+		Position:       *position,
+		MarketValue:    quantity * position.CurrentPrice,
+		CostBasis:      quantity * position.AvgCost,
+		DayChangeValue: quantity * position.DayChange,
+		Stale:          stale,
+	}
+	valuation.UnrealizedPL = valuation.MarketValue - valuation.CostBasis
+	if valuation.CostBasis > 0 {
+		valuation.UnrealizedPLPercent = valuation.UnrealizedPL / valuation.CostBasis * 100
+	}
+	return valuation
+}
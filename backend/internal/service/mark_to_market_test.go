@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// stubQuoteSource serves fixed quotes and counts fetches per symbol.
+type stubQuoteSource struct {
+	quotes  map[string]*MarketQuote
+	fetches map[string]int
+}
+
+func (s *stubQuoteSource) GetQuote(ctx context.Context, symbol string) (*MarketQuote, error) {
+	s.fetches[symbol]++
+	quote, ok := s.quotes[symbol]
+	if !ok {
+		return nil, errors.New("quote not found")
+	}
+	return quote, nil
+}
+
+func TestMarkToMarketService_RefreshPortfolio(t *testing.T) {
+	portfolioRepo := repository.NewInMemoryPortfolioRepository()
+	positionRepo := repository.NewInMemoryPositionRepository()
+	portfolio := &model.Portfolio{ID: uuid.New(), Name: "Test", CashBalance: 1000}
+	_ = portfolioRepo.Create(portfolio)
+	aapl := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Quantity: 10, AvgCost: 100, CurrentPrice: 100}
+	delisted := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "GONE", Quantity: 5, AvgCost: 20, CurrentPrice: 10}
+	_ = positionRepo.Create(aapl)
+	_ = positionRepo.Create(delisted)
+
+	quotes := &stubQuoteSource{
+		quotes:  map[string]*MarketQuote{"AAPL": {Price: 120, Change: 2, ChangePercent: 1.69}},
+		fetches: make(map[string]int),
+	}
+	svc := NewMarkToMarketService(portfolioRepo, positionRepo, quotes)
+
+	valuation, err := svc.RefreshPortfolio(context.Background(), portfolio.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if valuation.MarketValue != 1250 || valuation.CostBasis != 1100 || valuation.UnrealizedPL != 150 {
+		t.Errorf("Expected market value 1250, cost basis 1100, P&L 150, got %+v", valuation)
+	}
+	if valuation.TotalValue != 2250 || valuation.DayChange != 20 {
+		t.Errorf("Expected total value 2250 and day change 20, got %v and %v", valuation.TotalValue, valuation.DayChange)
+	}
+
+	stored, _ := positionRepo.GetByID(aapl.ID)
+	if stored.CurrentPrice != 120 || stored.DayChange != 2 || stored.DayChangePercent != 1.69 || stored.PriceUpdatedAt == nil {
+		t.Errorf("Expected AAPL position to be marked to market, got %+v", stored)
+	}
+	stale, _ := positionRepo.GetByID(delisted.ID)
+	if stale.CurrentPrice != 10 || stale.PriceUpdatedAt != nil {
+		t.Errorf("Expected position without a quote to keep its price, got %+v", stale)
+	}
+	for _, position := range valuation.Positions {
+		if position.Stale != (position.Symbol == "GONE") {
+			t.Errorf("Unexpected stale flag for %s", position.Symbol)
+		}
+	}
+
+	if _, err := svc.RefreshPortfolio(context.Background(), uuid.New()); err != ErrPortfolioNotFound {
+		t.Errorf("Expected ErrPortfolioNotFound, got %v", err)
+	}
+	if _, err := NewMarkToMarketService(portfolioRepo, positionRepo, nil).RefreshPortfolio(context.Background(), portfolio.ID); err != ErrQuotesUnavailable {
+		t.Errorf("Expected ErrQuotesUnavailable, got %v", err)
+	}
+}
+
+func TestMarkToMarketService_RefreshAll(t *testing.T) {
+	portfolioRepo := repository.NewInMemoryPortfolioRepository()
+	positionRepo := repository.NewInMemoryPositionRepository()
+	for i := 0; i < 3; i++ {
+		portfolio := &model.Portfolio{ID: uuid.New()}
+		_ = portfolioRepo.Create(portfolio)
+		_ = positionRepo.Create(&model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "MSFT", Quantity: 1})
+	}
+
+	quotes := &stubQuoteSource{
+		quotes:  map[string]*MarketQuote{"MSFT": {Price: 400}},
+		fetches: make(map[string]int),
+	}
+	updated, err := NewMarkToMarketService(portfolioRepo, positionRepo, quotes).RefreshAll(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated != 3 {
+		t.Errorf("Expected 3 positions updated, got %d", updated)
+	}
+	if quotes.fetches["MSFT"] != 1 {
+		t.Errorf("Expected MSFT to be quoted once, got %d", quotes.fetches["MSFT"])
+	}
+}
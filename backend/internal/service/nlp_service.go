@@ -2,16 +2,23 @@ package service
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/awaymess/super-dashboard/backend/internal/repository"
 	"github.com/awaymess/super-dashboard/backend/pkg/nlp"
 	"github.com/awaymess/super-dashboard/backend/pkg/pq"
+	"github.com/google/uuid"
 )
 
+// ErrArticleNotFound is returned when an ingested article does not exist.
+var ErrArticleNotFound = errors.New("article not found")
+
+// ArticleSummaryMaxLength is the maximum length of a stored article summary.
+const ArticleSummaryMaxLength = 280
+
 // NLPService defines the interface for NLP operations.
 type NLPService interface {
 	// IngestArticle ingests a new article, generates embeddings and analyzes sentiment.
@@ -19,6 +26,12 @@ type NLPService interface {
 
 	// SemanticSearch performs a semantic search for articles matching the query.
 	SemanticSearch(ctx context.Context, query string, limit int) (*SearchResponse, error)
+
+	// GetArticle retrieves an ingested article with its NLP results.
+	GetArticle(ctx context.Context, id uuid.UUID) (*model.Article, error)
+
+	// DeleteArticle removes an ingested article and its embedding.
+	DeleteArticle(ctx context.Context, id uuid.UUID) error
 }
 
 // IngestArticleRequest represents a request to ingest an article.
@@ -33,10 +46,10 @@ type IngestArticleRequest struct {
 
 // IngestArticleResponse represents the response after ingesting an article.
 type IngestArticleResponse struct {
-	ID               uuid.UUID        `json:"id"`
-	Sentiment        SentimentResult  `json:"sentiment"`
-	EventType        string           `json:"event_type"`
-	EmbeddingCreated bool             `json:"embedding_created"`
+	ID               uuid.UUID       `json:"id"`
+	Sentiment        SentimentResult `json:"sentiment"`
+	EventType        string          `json:"event_type"`
+	EmbeddingCreated bool            `json:"embedding_created"`
 }
 
 // SentimentResult contains sentiment analysis results.
@@ -47,9 +60,9 @@ type SentimentResult struct {
 
 // SearchResponse represents a semantic search response.
 type SearchResponse struct {
-	Results             []SearchResult `json:"results"`
-	QueryEmbeddingTimeMs int64         `json:"query_embedding_time_ms"`
-	SearchTimeMs        int64          `json:"search_time_ms"`
+	Results              []SearchResult `json:"results"`
+	QueryEmbeddingTimeMs int64          `json:"query_embedding_time_ms"`
+	SearchTimeMs         int64          `json:"search_time_ms"`
 }
 
 // SearchResult represents a single search result.
@@ -104,6 +117,7 @@ func (s *nlpService) IngestArticle(ctx context.Context, req IngestArticleRequest
 		SentimentScore: score,
 		SentimentLabel: label,
 		EventType:      eventType,
+		Summary:        s.summarize(ctx, req.Content),
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
@@ -192,12 +206,59 @@ func (s *nlpService) SemanticSearch(ctx context.Context, query string, limit int
 	}
 
 	return &SearchResponse{
-		Results:             searchResults,
+		Results:              searchResults,
 		QueryEmbeddingTimeMs: embedTimeMs,
-		SearchTimeMs:        searchTimeMs,
+		SearchTimeMs:         searchTimeMs,
 	}, nil
 }
 
+// GetArticle retrieves an article by ID.
+func (s *nlpService) GetArticle(ctx context.Context, id uuid.UUID) (*model.Article, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+	return article, nil
+}
+
+// DeleteArticle deletes an article by ID.
+func (s *nlpService) DeleteArticle(ctx context.Context, id uuid.UUID) error {
+	if err := s.articleRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrArticleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// summarize uses the provider's summarization when it offers one and falls
+// back to the leading text of the article.
+func (s *nlpService) summarize(ctx context.Context, content string) string {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return ""
+	}
+
+	if summarizer, ok := s.provider.(nlp.SummarizationProvider); ok {
+		if summary, err := summarizer.Summarize(ctx, content, ArticleSummaryMaxLength); err == nil && summary != "" {
+			return summary
+		}
+	}
+
+	if len(content) <= ArticleSummaryMaxLength {
+		return content
+	}
+	summary := content[:ArticleSummaryMaxLength]
+	if i := strings.LastIndex(summary, " "); i > 0 {
+		summary = summary[:i]
+	}
+	return summary + "..."
+}
+
 // classifyEventType classifies the event type based on text content.
 // Uses ordered checks to ensure more specific keywords are matched before generic ones.
 func classifyEventType(text string) string {
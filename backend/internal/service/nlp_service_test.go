@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -297,3 +298,54 @@ func TestNLPService_SemanticSearchEmptyResults(t *testing.T) {
 		t.Errorf("Expected 0 results, got %d", len(result.Results))
 	}
 }
+
+func TestNLPService_GetAndDeleteArticle(t *testing.T) {
+	svc := setupNLPService()
+	ctx := context.Background()
+
+	longContent := strings.Repeat("Apple reported record services revenue this quarter. ", 10)
+	ingested, err := svc.IngestArticle(ctx, IngestArticleRequest{
+		Title:   "Apple Earnings Report",
+		Content: longContent,
+		Symbols: []string{"AAPL"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to ingest article: %v", err)
+	}
+
+	article, err := svc.GetArticle(ctx, ingested.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if article.EventType != ingested.EventType {
+		t.Errorf("Expected event type %s, got %s", ingested.EventType, article.EventType)
+	}
+	if len(article.Symbols) != 1 || article.Symbols[0] != "AAPL" {
+		t.Errorf("Expected symbols [AAPL], got %v", article.Symbols)
+	}
+	if article.Summary == "" || len(article.Summary) > ArticleSummaryMaxLength+3 {
+		t.Errorf("Expected summary of at most %d characters, got %q", ArticleSummaryMaxLength, article.Summary)
+	}
+	if !strings.HasSuffix(article.Summary, "...") {
+		t.Errorf("Expected truncated summary to end with ..., got %q", article.Summary)
+	}
+
+	if err := svc.DeleteArticle(ctx, ingested.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := svc.GetArticle(ctx, ingested.ID); err != ErrArticleNotFound {
+		t.Errorf("Expected ErrArticleNotFound, got %v", err)
+	}
+	if err := svc.DeleteArticle(ctx, ingested.ID); err != ErrArticleNotFound {
+		t.Errorf("Expected ErrArticleNotFound, got %v", err)
+	}
+
+	// Deleted articles no longer appear in search results
+	results, err := svc.SemanticSearch(ctx, "Apple earnings", 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results.Results) != 0 {
+		t.Errorf("Expected no search results, got %d", len(results.Results))
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// ErrNotificationNotFound is returned when a notification does not exist or
+// belongs to another user.
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationDeliveryStore reads notifications and their delivery records.
+type NotificationDeliveryStore interface {
+	GetNotification(ctx context.Context, notificationID uuid.UUID) (*model.Notification, error)
+	GetDeliveries(ctx context.Context, notificationID uuid.UUID) ([]model.NotificationDelivery, error)
+}
+
+// NotificationDeliveryService defines the interface for inspecting how a
+// notification was delivered.
+type NotificationDeliveryService interface {
+	// GetDeliveries returns the per-channel deliveries of a user's notification.
+	GetDeliveries(ctx context.Context, userID, notificationID uuid.UUID) ([]model.NotificationDelivery, error)
+}
+
+// notificationDeliveryService implements NotificationDeliveryService.
+type notificationDeliveryService struct {
+	store NotificationDeliveryStore
+}
+
+// NewNotificationDeliveryService creates a new NotificationDeliveryService instance.
+func NewNotificationDeliveryService(store NotificationDeliveryStore) NotificationDeliveryService {
+	return &notificationDeliveryService{store: store}
+}
+
+// GetDeliveries checks the notification belongs to the user and lists its deliveries.
+func (s *notificationDeliveryService) GetDeliveries(ctx context.Context, userID, notificationID uuid.UUID) ([]model.NotificationDelivery, error) {
+	notification, err := s.store.GetNotification(ctx, notificationID)
+	if err != nil || notification.UserID != userID {
+		return nil, ErrNotificationNotFound
+	}
+
+	deliveries, err := s.store.GetDeliveries(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		deliveries = []model.NotificationDelivery{}
+	}
+	return deliveries, nil
+}
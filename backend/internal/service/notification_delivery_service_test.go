@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// mockNotificationDeliveryStore stores notifications and deliveries in memory.
+type mockNotificationDeliveryStore struct {
+	notifications map[uuid.UUID]*model.Notification
+	deliveries    []model.NotificationDelivery
+	err           error
+}
+
+func (m *mockNotificationDeliveryStore) GetNotification(ctx context.Context, notificationID uuid.UUID) (*model.Notification, error) {
+	notification, ok := m.notifications[notificationID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return notification, nil
+}
+
+func (m *mockNotificationDeliveryStore) GetDeliveries(ctx context.Context, notificationID uuid.UUID) ([]model.NotificationDelivery, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var deliveries []model.NotificationDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.NotificationID == notificationID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func TestNotificationDeliveryService_GetDeliveries(t *testing.T) {
+	userID := uuid.New()
+	delivered := &model.Notification{ID: uuid.New(), UserID: userID}
+	pending := &model.Notification{ID: uuid.New(), UserID: userID}
+	deliveredAt := time.Now()
+	store := &mockNotificationDeliveryStore{
+		notifications: map[uuid.UUID]*model.Notification{delivered.ID: delivered, pending.ID: pending},
+		deliveries: []model.NotificationDelivery{
+			{NotificationID: delivered.ID, Channel: model.NotificationChannelInApp, Status: model.DeliveryStatusDelivered, Attempts: 1, DeliveredAt: &deliveredAt},
+			{NotificationID: delivered.ID, Channel: model.NotificationChannelEmail, Status: model.DeliveryStatusFailed, Attempts: 3, Error: "sendgrid error: status 401"},
+		},
+	}
+	svc := NewNotificationDeliveryService(store)
+
+	tests := []struct {
+		name           string
+		userID         uuid.UUID
+		notificationID uuid.UUID
+		wantCount      int
+		wantErr        error
+	}{
+		{"own notification", userID, delivered.ID, 2, nil},
+		{"no deliveries yet", userID, pending.ID, 0, nil},
+		{"unknown notification", userID, uuid.New(), 0, ErrNotificationNotFound},
+		{"other user's notification", uuid.New(), delivered.ID, 0, ErrNotificationNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveries, err := svc.GetDeliveries(context.Background(), tt.userID, tt.notificationID)
+			if err != tt.wantErr {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (deliveries == nil || len(deliveries) != tt.wantCount) {
+				t.Errorf("Expected %d deliveries, got %v", tt.wantCount, deliveries)
+			}
+		})
+	}
+
+	store.err = errors.New("connection reset")
+	if _, err := svc.GetDeliveries(context.Background(), userID, delivered.ID); err != store.err {
+		t.Errorf("Expected store error, got %v", err)
+	}
+}
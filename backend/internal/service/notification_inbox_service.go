@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// NotificationInboxStore reads and updates the notifications stored for users.
+type NotificationInboxStore interface {
+	GetNotification(ctx context.Context, notificationID uuid.UUID) (*model.Notification, error)
+	GetUserNotifications(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]model.Notification, error)
+	CountUserNotifications(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+	MarkAsRead(ctx context.Context, notificationID uuid.UUID) error
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
+}
+
+// NotificationInboxService defines the interface for a user's in-app
+// notification inbox.
+type NotificationInboxService interface {
+	// ListNotifications returns a page of the user's notifications, newest
+	// first, and the total number of notifications the user has.
+	ListNotifications(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Notification, int64, error)
+	// MarkRead marks one of the user's notifications as read.
+	MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error
+	// MarkAllRead marks all of the user's unread notifications as read.
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+	// UnreadCount returns the number of unread notifications the user has.
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// notificationInboxService implements NotificationInboxService.
+type notificationInboxService struct {
+	store NotificationInboxStore
+}
+
+// NewNotificationInboxService creates a new NotificationInboxService instance.
+func NewNotificationInboxService(store NotificationInboxStore) NotificationInboxService {
+	return &notificationInboxService{store: store}
+}
+
+// ListNotifications lists a page of the user's notifications with the total count.
+func (s *notificationInboxService) ListNotifications(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Notification, int64, error) {
+	notifications, err := s.store.GetUserNotifications(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if notifications == nil {
+		notifications = []model.Notification{}
+	}
+
+	total, err := s.store.CountUserNotifications(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// MarkRead checks the notification belongs to the user and marks it as read.
+func (s *notificationInboxService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	notification, err := s.store.GetNotification(ctx, notificationID)
+	if err != nil || notification.UserID != userID {
+		return ErrNotificationNotFound
+	}
+	if notification.Status == model.NotificationStatusRead {
+		return nil
+	}
+	return s.store.MarkAsRead(ctx, notificationID)
+}
+
+// MarkAllRead marks all of the user's unread notifications as read.
+func (s *notificationInboxService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	return s.store.MarkAllAsRead(ctx, userID)
+}
+
+// UnreadCount counts the user's unread notifications.
+func (s *notificationInboxService) UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.store.CountUnread(ctx, userID)
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// mockNotificationInboxStore stores notifications in memory.
+type mockNotificationInboxStore struct {
+	notifications map[uuid.UUID]*model.Notification
+}
+
+func newMockNotificationInboxStore() *mockNotificationInboxStore {
+	return &mockNotificationInboxStore{notifications: make(map[uuid.UUID]*model.Notification)}
+}
+
+// add stores a notification for userID created age ago.
+func (m *mockNotificationInboxStore) add(userID uuid.UUID, age time.Duration, status model.NotificationStatus) *model.Notification {
+	notification := &model.Notification{ID: uuid.New(), UserID: userID, Status: status, CreatedAt: time.Now().Add(-age)}
+	m.notifications[notification.ID] = notification
+	return notification
+}
+
+func (m *mockNotificationInboxStore) GetNotification(ctx context.Context, notificationID uuid.UUID) (*model.Notification, error) {
+	notification, ok := m.notifications[notificationID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return notification, nil
+}
+
+func (m *mockNotificationInboxStore) GetUserNotifications(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]model.Notification, error) {
+	var notifications []model.Notification
+	for _, n := range m.notifications {
+		if n.UserID == userID {
+			notifications = append(notifications, *n)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+	if offset >= len(notifications) {
+		return nil, nil
+	}
+	notifications = notifications[offset:]
+	if limit < len(notifications) {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+func (m *mockNotificationInboxStore) CountUserNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	for _, n := range m.notifications {
+		if n.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockNotificationInboxStore) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	for _, n := range m.notifications {
+		if n.UserID == userID && n.Status == model.NotificationStatusUnread {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockNotificationInboxStore) MarkAsRead(ctx context.Context, notificationID uuid.UUID) error {
+	if n, ok := m.notifications[notificationID]; ok {
+		now := time.Now()
+		n.Status = model.NotificationStatusRead
+		n.ReadAt = &now
+	}
+	return nil
+}
+
+func (m *mockNotificationInboxStore) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
+	for _, n := range m.notifications {
+		if n.UserID == userID && n.Status == model.NotificationStatusUnread {
+			m.MarkAsRead(ctx, n.ID)
+		}
+	}
+	return nil
+}
+
+func TestNotificationInboxService_ListNotifications(t *testing.T) {
+	store := newMockNotificationInboxStore()
+	userID := uuid.New()
+	var created []*model.Notification
+	for i := 0; i < 5; i++ {
+		created = append(created, store.add(userID, time.Duration(i)*time.Minute, model.NotificationStatusUnread))
+	}
+	store.add(uuid.New(), 0, model.NotificationStatusUnread)
+	svc := NewNotificationInboxService(store)
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		wantIDs       []uuid.UUID
+	}{
+		{"first page", 2, 0, []uuid.UUID{created[0].ID, created[1].ID}},
+		{"second page", 2, 2, []uuid.UUID{created[2].ID, created[3].ID}},
+		{"last partial page", 2, 4, []uuid.UUID{created[4].ID}},
+		{"past the end", 2, 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifications, total, err := svc.ListNotifications(context.Background(), userID, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if total != 5 {
+				t.Errorf("Expected total 5, got %d", total)
+			}
+			if notifications == nil || len(notifications) != len(tt.wantIDs) {
+				t.Fatalf("Expected %d notifications, got %v", len(tt.wantIDs), notifications)
+			}
+			for i, id := range tt.wantIDs {
+				if notifications[i].ID != id {
+					t.Errorf("Expected notification %d to be %s, got %s", i, id, notifications[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestNotificationInboxService_MarkRead(t *testing.T) {
+	store := newMockNotificationInboxStore()
+	userID := uuid.New()
+	first := store.add(userID, time.Minute, model.NotificationStatusUnread)
+	store.add(userID, 0, model.NotificationStatusUnread)
+	store.add(userID, 0, model.NotificationStatusRead)
+	other := store.add(uuid.New(), 0, model.NotificationStatusUnread)
+	svc := NewNotificationInboxService(store)
+	ctx := context.Background()
+
+	if count, _ := svc.UnreadCount(ctx, userID); count != 2 {
+		t.Fatalf("Expected 2 unread, got %d", count)
+	}
+
+	if err := svc.MarkRead(ctx, userID, first.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if first.Status != model.NotificationStatusRead || first.ReadAt == nil {
+		t.Errorf("Expected notification to be read, got %+v", first)
+	}
+	if err := svc.MarkRead(ctx, userID, other.ID); err != ErrNotificationNotFound {
+		t.Errorf("Expected ErrNotificationNotFound for another user's notification, got %v", err)
+	}
+	if err := svc.MarkRead(ctx, userID, uuid.New()); err != ErrNotificationNotFound {
+		t.Errorf("Expected ErrNotificationNotFound, got %v", err)
+	}
+
+	if err := svc.MarkAllRead(ctx, userID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count, _ := svc.UnreadCount(ctx, userID); count != 0 {
+		t.Errorf("Expected 0 unread after marking all read, got %d", count)
+	}
+	if other.Status != model.NotificationStatusUnread {
+		t.Error("Expected other users' notifications to stay unread")
+	}
+}
@@ -3,19 +3,45 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
-	"super-dashboard/backend/internal/model"
-	"super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	channels "github.com/awaymess/super-dashboard/backend/pkg/api/notification"
 )
 
+// Delivery retry settings. Each retry waits deliveryRetryDelay times the
+// number of attempts so far.
+const (
+	maxDeliveryAttempts = 3
+	deliveryRetryDelay  = 2 * time.Second
+)
+
+// Value bets at or above highValueBetPercent are shown in green on Discord,
+// marginal ones in yellow.
+const (
+	highValueBetPercent = 10.0
+	discordColorGreen   = 0x2ECC71
+	discordColorYellow  = 0xF1C40F
+)
+
+// NotificationSettingsStore loads the settings holding a user's channel
+// addresses. *repository.SettingsRepository implements it.
+type NotificationSettingsStore interface {
+	GetUserSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error)
+}
+
 // NotificationService handles sending notifications through various channels.
 type NotificationService struct {
 	notifRepo *repository.NotificationRepository
+	manager   *channels.NotificationManager
+	settings  NotificationSettingsStore
 	log       zerolog.Logger
 }
 
@@ -27,6 +53,15 @@ func NewNotificationService(notifRepo *repository.NotificationRepository, log ze
 	}
 }
 
+// SetChannels enables delivery outside the app. Email, Telegram and LINE go
+// through manager; Discord goes to the user's own webhook. Telegram chat IDs
+// and Discord webhooks are read from the user's settings. Without channels,
+// deliveries on them fail as not configured.
+func (s *NotificationService) SetChannels(manager *channels.NotificationManager, settings NotificationSettingsStore) {
+	s.manager = manager
+	s.settings = settings
+}
+
 // NotificationPayload represents the data for a notification.
 type NotificationPayload struct {
 	UserID  uuid.UUID
@@ -34,12 +69,14 @@ type NotificationPayload struct {
 	Title   string
 	Message string
 	Data    map[string]interface{}
+	// DiscordEmbed, when set, is posted to Discord instead of the plain message.
+	DiscordEmbed *channels.DiscordEmbed
 }
 
 // SendAlertNotification sends a notification for a triggered alert.
 func (s *NotificationService) SendAlertNotification(ctx context.Context, alert *model.Alert, currentValue float64) error {
 	message := s.formatAlertMessage(alert, currentValue)
-	
+
 	data := map[string]interface{}{
 		"alert_id":      alert.ID,
 		"symbol":        alert.Symbol,
@@ -58,35 +95,30 @@ func (s *NotificationService) SendAlertNotification(ctx context.Context, alert *
 	}
 
 	// Create in-app notification
-	if err := s.CreateNotification(ctx, payload); err != nil {
+	notification, err := s.createNotification(ctx, payload)
+	if err != nil {
 		s.log.Error().Err(err).Msg("Failed to create in-app notification")
 	}
 
-	// Send email notification if enabled
-	if alert.NotifyEmail {
-		if err := s.sendEmailNotification(ctx, alert.User, payload); err != nil {
-			s.log.Error().Err(err).Msg("Failed to send email notification")
-		}
-	}
-
-	// Send Telegram notification if enabled
-	if alert.NotifyTelegram {
-		if err := s.sendTelegramNotification(ctx, alert.User, payload); err != nil {
-			s.log.Error().Err(err).Msg("Failed to send Telegram notification")
-		}
+	targets := []struct {
+		enabled bool
+		channel model.NotificationChannel
+		send    func(ctx context.Context, user model.User, payload NotificationPayload) error
+	}{
+		{alert.NotifyEmail, model.NotificationChannelEmail, s.sendEmailNotification},
+		{alert.NotifyTelegram, model.NotificationChannelTelegram, s.sendTelegramNotification},
+		{alert.NotifyLINE, model.NotificationChannelLINE, s.sendLINENotification},
+		{alert.NotifyDiscord, model.NotificationChannelDiscord, s.sendDiscordNotification},
 	}
-
-	// Send LINE notification if enabled
-	if alert.NotifyLINE {
-		if err := s.sendLINENotification(ctx, alert.User, payload); err != nil {
-			s.log.Error().Err(err).Msg("Failed to send LINE notification")
+	for _, ch := range targets {
+		if !ch.enabled {
+			continue
 		}
-	}
-
-	// Send Discord notification if enabled
-	if alert.NotifyDiscord {
-		if err := s.sendDiscordNotification(ctx, alert.User, payload); err != nil {
-			s.log.Error().Err(err).Msg("Failed to send Discord notification")
+		send := ch.send
+		if err := s.deliver(ctx, notification, ch.channel, func() error {
+			return send(ctx, alert.User, payload)
+		}); err != nil {
+			s.log.Error().Err(err).Str("channel", string(ch.channel)).Msg("Failed to deliver notification")
 		}
 	}
 
@@ -95,9 +127,15 @@ func (s *NotificationService) SendAlertNotification(ctx context.Context, alert *
 
 // CreateNotification creates a new in-app notification.
 func (s *NotificationService) CreateNotification(ctx context.Context, payload NotificationPayload) error {
+	_, err := s.createNotification(ctx, payload)
+	return err
+}
+
+// createNotification stores an in-app notification and records its in-app delivery.
+func (s *NotificationService) createNotification(ctx context.Context, payload NotificationPayload) (*model.Notification, error) {
 	dataJSON, err := json.Marshal(payload.Data)
 	if err != nil {
-		return fmt.Errorf("failed to marshal notification data: %w", err)
+		return nil, fmt.Errorf("failed to marshal notification data: %w", err)
 	}
 
 	notification := &model.Notification{
@@ -111,7 +149,7 @@ func (s *NotificationService) CreateNotification(ctx context.Context, payload No
 	}
 
 	if err := s.notifRepo.CreateNotification(ctx, notification); err != nil {
-		return fmt.Errorf("failed to create notification: %w", err)
+		return nil, fmt.Errorf("failed to create notification: %w", err)
 	}
 
 	s.log.Info().
@@ -119,10 +157,85 @@ func (s *NotificationService) CreateNotification(ctx context.Context, payload No
 		Str("type", string(payload.Type)).
 		Msg("Notification created")
 
+	deliveredAt := notification.CreatedAt
+	if err := s.notifRepo.CreateDelivery(ctx, &model.NotificationDelivery{
+		NotificationID: notification.ID,
+		Channel:        model.NotificationChannelInApp,
+		Status:         model.DeliveryStatusDelivered,
+		Attempts:       1,
+		DeliveredAt:    &deliveredAt,
+	}); err != nil {
+		s.log.Warn().Err(err).Str("notification_id", notification.ID.String()).Msg("Failed to record in-app delivery")
+	}
+	NotificationsSentTotal.Inc(string(model.NotificationChannelInApp), string(model.DeliveryStatusDelivered))
+
 	// TODO: Emit WebSocket event for real-time notification
 	// ws.EmitToUser(payload.UserID, "notification:new", notification)
 
-	return nil
+	return notification, nil
+}
+
+// deliver sends a notification on one channel, retrying failures, and records
+// the outcome. notification may be nil when the in-app notification could not
+// be stored; the channel is still tried but nothing is recorded.
+func (s *NotificationService) deliver(ctx context.Context, notification *model.Notification, channel model.NotificationChannel, send func() error) error {
+	var delivery *model.NotificationDelivery
+	if notification != nil {
+		delivery = &model.NotificationDelivery{
+			NotificationID: notification.ID,
+			Channel:        channel,
+			Status:         model.DeliveryStatusPending,
+		}
+		if err := s.notifRepo.CreateDelivery(ctx, delivery); err != nil {
+			s.log.Warn().Err(err).Str("channel", string(channel)).Msg("Failed to record notification delivery")
+			delivery = nil
+		}
+	}
+
+	var (
+		err      error
+		attempts int
+	)
+	for attempts < maxDeliveryAttempts {
+		if attempts > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Duration(attempts) * deliveryRetryDelay):
+			}
+		}
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			break
+		}
+		attempts++
+		if err = send(); err == nil || errors.Is(err, channels.ErrChannelNotConfigured) {
+			break
+		}
+	}
+
+	status := model.DeliveryStatusDelivered
+	if err != nil {
+		status = model.DeliveryStatusFailed
+	}
+	NotificationsSentTotal.Inc(string(channel), string(status))
+
+	if delivery == nil {
+		return err
+	}
+
+	delivery.Attempts = attempts
+	if err != nil {
+		delivery.Status = model.DeliveryStatusFailed
+		delivery.Error = err.Error()
+	} else {
+		now := time.Now()
+		delivery.Status = model.DeliveryStatusDelivered
+		delivery.DeliveredAt = &now
+	}
+	if updateErr := s.notifRepo.UpdateDelivery(ctx, delivery); updateErr != nil {
+		s.log.Warn().Err(updateErr).Str("channel", string(channel)).Msg("Failed to record notification delivery")
+	}
+	return err
 }
 
 // SendValueBetNotification sends a notification for a value bet opportunity.
@@ -149,15 +262,74 @@ func (s *NotificationService) SendValueBetNotification(ctx context.Context, user
 		"kelly_stake":         valueBet.KellyStake,
 	}
 
+	embed := BuildValueBetEmbed(*valueBet)
 	payload := NotificationPayload{
-		UserID:  userID,
-		Type:    model.NotificationTypeValueBet,
-		Title:   fmt.Sprintf("Value Bet: %.2f%% Value", valueBet.ValuePercent),
-		Message: message,
-		Data:    data,
+		UserID:       userID,
+		Type:         model.NotificationTypeValueBet,
+		Title:        fmt.Sprintf("Value Bet: %.2f%% Value", valueBet.ValuePercent),
+		Message:      message,
+		Data:         data,
+		DiscordEmbed: &embed,
 	}
 
-	return s.CreateNotification(ctx, payload)
+	notification, err := s.createNotification(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	// Discord is the only channel value bets are pushed to, and only for
+	// users who enabled it in their settings.
+	if s.settings == nil {
+		return nil
+	}
+	settings, err := s.settings.GetUserSettings(ctx, userID)
+	if err != nil {
+		s.log.Warn().Err(err).Str("user_id", userID.String()).Msg("Failed to load settings for value bet notification")
+		return nil
+	}
+	if !settings.NotifyDiscord {
+		return nil
+	}
+	if err := s.deliver(ctx, notification, model.NotificationChannelDiscord, func() error {
+		return s.sendDiscordNotification(ctx, model.User{ID: userID}, payload)
+	}); err != nil {
+		s.log.Error().Err(err).Str("channel", string(model.NotificationChannelDiscord)).Msg("Failed to deliver notification")
+	}
+	return nil
+}
+
+// BuildValueBetEmbed formats a value bet as a Discord embed: green when its
+// value reaches highValueBetPercent, yellow when it is marginal.
+func BuildValueBetEmbed(vb model.ValueBet) channels.DiscordEmbed {
+	color := discordColorYellow
+	if vb.ValuePercent >= highValueBetPercent {
+		color = discordColorGreen
+	}
+
+	match := vb.MatchID.String()
+	if vb.Match.HomeTeam.Name != "" && vb.Match.AwayTeam.Name != "" {
+		match = vb.Match.HomeTeam.Name + " vs " + vb.Match.AwayTeam.Name
+	}
+	if vb.Match.League != "" {
+		match += " (" + vb.Match.League + ")"
+	}
+
+	embed := channels.DiscordEmbed{
+		Title:       fmt.Sprintf("Value Bet: %.2f%% Value", vb.ValuePercent),
+		Description: match,
+		Color:       color,
+		Fields: []channels.DiscordEmbedField{
+			{Name: "Selection", Value: fmt.Sprintf("%s (%s)", vb.Selection, vb.Market), Inline: true},
+			{Name: "Odds", Value: fmt.Sprintf("%.2f at %s", vb.BookmakerOdds, vb.Bookmaker), Inline: true},
+			{Name: "Probability", Value: fmt.Sprintf("%.1f%% true vs %.1f%% implied", vb.TrueProbability*100, vb.ImpliedProbability*100)},
+			{Name: "Value", Value: fmt.Sprintf("%.2f%%", vb.ValuePercent), Inline: true},
+			{Name: "Kelly Stake", Value: fmt.Sprintf("%.2f%% of bankroll", vb.KellyStake), Inline: true},
+		},
+	}
+	if !vb.CreatedAt.IsZero() {
+		embed.Timestamp = vb.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	return embed
 }
 
 // formatAlertMessage formats the alert message based on condition.
@@ -183,74 +355,84 @@ func (s *NotificationService) formatAlertMessage(alert *model.Alert, currentValu
 	}
 }
 
-// sendEmailNotification sends an email notification.
-// TODO: Implement actual email sending using SMTP or service like SendGrid.
+// sendEmailNotification emails the user through the notification manager.
 func (s *NotificationService) sendEmailNotification(ctx context.Context, user model.User, payload NotificationPayload) error {
-	s.log.Debug().
-		Str("user_id", user.ID.String()).
-		Str("email", user.Email).
-		Str("title", payload.Title).
-		Msg("Would send email notification (not implemented)")
-
-	// TODO: Implement email sending
-	// Example using SMTP or SendGrid:
-	// - Format HTML email template
-	// - Include notification details
-	// - Send via SMTP client
-	
-	return nil
+	if user.Email == "" {
+		return channelNotConfigured(channels.ChannelEmail)
+	}
+	msg := channelNotification(payload)
+	msg.EmailRecipients = []string{user.Email}
+	return s.notify(ctx, channels.ChannelEmail, msg)
 }
 
-// sendTelegramNotification sends a Telegram notification.
-// TODO: Implement Telegram Bot API integration.
+// sendTelegramNotification messages the chat ID in the user's settings.
 func (s *NotificationService) sendTelegramNotification(ctx context.Context, user model.User, payload NotificationPayload) error {
-	s.log.Debug().
-		Str("user_id", user.ID.String()).
-		Str("title", payload.Title).
-		Msg("Would send Telegram notification (not implemented)")
-
-	// TODO: Implement Telegram notification
-	// Example:
-	// - Get user's Telegram chat ID from settings
-	// - Format message with Markdown
-	// - Send via Telegram Bot API
-	// URL: https://api.telegram.org/bot<token>/sendMessage
-	
-	return nil
+	settings, err := s.userSettings(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if settings.TelegramChatID == "" {
+		return channelNotConfigured(channels.ChannelTelegram)
+	}
+	msg := channelNotification(payload)
+	msg.TelegramChatID = settings.TelegramChatID
+	return s.notify(ctx, channels.ChannelTelegram, msg)
 }
 
-// sendLINENotification sends a LINE notification.
-// TODO: Implement LINE Notify API integration.
+// sendLINENotification pushes a LINE message. Settings only hold a LINE
+// Notify token, not the LINE user ID the manager pushes to, so LINE
+// deliveries fail as not configured until users can link their LINE account.
 func (s *NotificationService) sendLINENotification(ctx context.Context, user model.User, payload NotificationPayload) error {
-	s.log.Debug().
-		Str("user_id", user.ID.String()).
-		Str("title", payload.Title).
-		Msg("Would send LINE notification (not implemented)")
-
-	// TODO: Implement LINE notification
-	// Example:
-	// - Get user's LINE token from settings
-	// - Format message
-	// - Send via LINE Notify API
-	// URL: https://notify-api.line.me/api/notify
-	
-	return nil
+	return channelNotConfigured(channels.ChannelLINE)
 }
 
-// sendDiscordNotification sends a Discord notification.
-// TODO: Implement Discord Webhook integration.
+// sendDiscordNotification posts to the Discord webhook in the user's settings,
+// as an embed when the payload carries one.
 func (s *NotificationService) sendDiscordNotification(ctx context.Context, user model.User, payload NotificationPayload) error {
-	s.log.Debug().
-		Str("user_id", user.ID.String()).
-		Str("title", payload.Title).
-		Msg("Would send Discord notification (not implemented)")
-
-	// TODO: Implement Discord notification
-	// Example:
-	// - Get user's Discord webhook URL from settings
-	// - Format embed message
-	// - Send via Discord Webhook
-	// POST to webhook URL with JSON payload
-	
-	return nil
+	settings, err := s.userSettings(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if settings.DiscordWebhook == "" {
+		return channelNotConfigured(channels.ChannelDiscord)
+	}
+	client := channels.NewDiscordClient(settings.DiscordWebhook)
+	if payload.DiscordEmbed != nil {
+		return client.SendEmbed(ctx, *payload.DiscordEmbed)
+	}
+	return client.SendMessage(ctx, channelNotification(payload).Message)
+}
+
+// notify sends msg on one channel of the notification manager.
+func (s *NotificationService) notify(ctx context.Context, channel channels.Channel, msg channels.Notification) error {
+	if s.manager == nil {
+		return channelNotConfigured(channel)
+	}
+	return s.manager.Notify(ctx, channel, msg)
+}
+
+// userSettings loads the user's settings for their channel addresses.
+func (s *NotificationService) userSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error) {
+	if s.settings == nil {
+		return nil, fmt.Errorf("%w: no user settings", channels.ErrChannelNotConfigured)
+	}
+	settings, err := s.settings.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user settings: %w", err)
+	}
+	return settings, nil
+}
+
+// channelNotification formats payload for the external channels.
+func channelNotification(payload NotificationPayload) channels.Notification {
+	return channels.Notification{
+		Subject: payload.Title,
+		Message: payload.Title + "\n" + payload.Message,
+		Body:    "<h2>" + html.EscapeString(payload.Title) + "</h2><p>" + html.EscapeString(payload.Message) + "</p>",
+	}
+}
+
+// channelNotConfigured reports that the user cannot be reached on channel.
+func channelNotConfigured(channel channels.Channel) error {
+	return fmt.Errorf("%w: %s", channels.ErrChannelNotConfigured, channel)
 }
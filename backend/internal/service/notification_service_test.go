@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+func TestBuildValueBetEmbed(t *testing.T) {
+	vb := model.ValueBet{
+		Match: model.Match{
+			League:   "Premier League",
+			HomeTeam: model.Team{Name: "Arsenal"},
+			AwayTeam: model.Team{Name: "Chelsea"},
+		},
+		Market:             "1X2",
+		Selection:          "home",
+		Bookmaker:          "Pinnacle",
+		BookmakerOdds:      2.1,
+		TrueProbability:    0.55,
+		ImpliedProbability: 1 / 2.1,
+		ValuePercent:       15.5,
+		KellyStake:         4.2,
+		CreatedAt:          time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	embed := BuildValueBetEmbed(vb)
+
+	if embed.Title != "Value Bet: 15.50% Value" {
+		t.Errorf("Unexpected title %q", embed.Title)
+	}
+	if embed.Description != "Arsenal vs Chelsea (Premier League)" {
+		t.Errorf("Unexpected description %q", embed.Description)
+	}
+	if embed.Timestamp != "2026-03-01T12:00:00Z" {
+		t.Errorf("Unexpected timestamp %q", embed.Timestamp)
+	}
+
+	want := map[string]string{
+		"Selection":   "home (1X2)",
+		"Odds":        "2.10 at Pinnacle",
+		"Probability": "55.0% true vs 47.6% implied",
+		"Value":       "15.50%",
+		"Kelly Stake": "4.20% of bankroll",
+	}
+	if len(embed.Fields) != len(want) {
+		t.Fatalf("Expected %d fields, got %+v", len(want), embed.Fields)
+	}
+	for _, field := range embed.Fields {
+		if field.Value != want[field.Name] {
+			t.Errorf("Field %q = %q, want %q", field.Name, field.Value, want[field.Name])
+		}
+	}
+}
+
+func TestBuildValueBetEmbed_Color(t *testing.T) {
+	tests := []struct {
+		name         string
+		valuePercent float64
+		want         int
+	}{
+		{"marginal", 5, discordColorYellow},
+		{"just below threshold", 9.99, discordColorYellow},
+		{"at threshold", highValueBetPercent, discordColorGreen},
+		{"high", 25, discordColorGreen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildValueBetEmbed(model.ValueBet{ValuePercent: tt.valuePercent}).Color; got != tt.want {
+				t.Errorf("Expected color %#x, got %#x", tt.want, got)
+			}
+		})
+	}
+}
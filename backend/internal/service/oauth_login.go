@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/pkg/api/oauth"
+)
+
+// OAuthProvider runs the OAuth authorization code flow with one identity
+// provider. *oauth.Provider implements it.
+type OAuthProvider interface {
+	AuthCodeURL(state string) string
+	Exchange(ctx context.Context, code string) (*oauth.Token, error)
+	UserInfo(ctx context.Context, token *oauth.Token) (*oauth.UserInfo, error)
+}
+
+// OAuthLoginURL starts an OAuth login, returning the provider's consent page
+// URL and the state sent along. The state is stored for OAuthStateDuration
+// and checked by CompleteOAuthLogin, so callbacks cannot be forged. Callers
+// must also bind it to the browser that started the login, so that a
+// callback from another browser's login is rejected.
+func (s *extendedAuthService) OAuthLoginURL(provider model.OAuthProvider) (string, string, error) {
+	p, ok := s.oauthProviders[provider]
+	if !ok {
+		return "", "", ErrOAuthProviderNotConfigured
+	}
+	if s.tokenStore == nil {
+		return "", "", ErrTokenStoreUnavailable
+	}
+
+	state, err := generateSecureToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.tokenStore.SetOAuthState(ctx, state, string(provider), OAuthStateDuration); err != nil {
+		return "", "", err
+	}
+
+	return p.AuthCodeURL(state), state, nil
+}
+
+// CompleteOAuthLogin finishes an OAuth login started with OAuthLoginURL. It
+// checks and consumes state, exchanges code for tokens, fetches the user's
+// profile from the provider and signs them in like HandleOAuthLogin.
+func (s *extendedAuthService) CompleteOAuthLogin(ctx context.Context, provider model.OAuthProvider, state, code string) (*model.User, string, string, error) {
+	p, ok := s.oauthProviders[provider]
+	if !ok {
+		return nil, "", "", ErrOAuthProviderNotConfigured
+	}
+	if s.tokenStore == nil {
+		return nil, "", "", ErrTokenStoreUnavailable
+	}
+
+	storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	storedProvider, err := s.tokenStore.GetOAuthState(storeCtx, state)
+	if err != nil {
+		return nil, "", "", ErrInvalidOAuthState
+	}
+	_ = s.tokenStore.DeleteOAuthState(storeCtx, state)
+	if storedProvider != string(provider) {
+		return nil, "", "", ErrInvalidOAuthState
+	}
+
+	token, err := p.Exchange(ctx, code)
+	if err != nil {
+		return nil, "", "", ErrOAuthExchangeFailed
+	}
+	profile, err := p.UserInfo(ctx, token)
+	if err != nil {
+		if errors.Is(err, oauth.ErrEmailNotVerified) {
+			return nil, "", "", ErrOAuthEmailNotVerified
+		}
+		return nil, "", "", ErrOAuthExchangeFailed
+	}
+
+	info := &OAuthUserInfo{
+		Provider:       provider,
+		ProviderUserID: profile.ProviderUserID,
+		Email:          profile.Email,
+		Name:           profile.Name,
+		AvatarURL:      profile.AvatarURL,
+		AccessToken:    token.AccessToken,
+		RefreshToken:   token.RefreshToken,
+	}
+	if !token.Expiry.IsZero() {
+		expiresAt := token.Expiry
+		info.ExpiresAt = &expiresAt
+	}
+
+	return s.handleOAuthLogin(ctx, info)
+}
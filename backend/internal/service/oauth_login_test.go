@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/pkg/api/oauth"
+)
+
+// mockOAuthProvider accepts the code "good-code" and returns profile.
+type mockOAuthProvider struct {
+	profile    *oauth.UserInfo
+	profileErr error
+}
+
+func (m *mockOAuthProvider) AuthCodeURL(state string) string {
+	return "https://provider.example.com/auth?state=" + url.QueryEscape(state)
+}
+
+func (m *mockOAuthProvider) Exchange(ctx context.Context, code string) (*oauth.Token, error) {
+	if code != "good-code" {
+		return nil, errors.New("bad_verification_code")
+	}
+	return &oauth.Token{AccessToken: "provider-access", Expiry: time.Now().Add(time.Hour)}, nil
+}
+
+func (m *mockOAuthProvider) UserInfo(ctx context.Context, token *oauth.Token) (*oauth.UserInfo, error) {
+	if m.profileErr != nil {
+		return nil, m.profileErr
+	}
+	return m.profile, nil
+}
+
+// startOAuthLogin begins a login and returns its state, checking that it
+// is the one sent in the consent URL.
+func startOAuthLogin(t *testing.T, authService ExtendedAuthService, provider model.OAuthProvider) string {
+	t.Helper()
+	authURL, state, err := authService.OAuthLoginURL(provider)
+	if err != nil {
+		t.Fatalf("Failed to start OAuth login: %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("Invalid consent URL %q: %v", authURL, err)
+	}
+	if got := u.Query().Get("state"); got != state {
+		t.Fatalf("Expected state %q in the consent URL, got %q", state, got)
+	}
+	return state
+}
+
+func TestExtendedAuthService_CompleteOAuthLogin(t *testing.T) {
+	oauthRepo := newMockOAuthAccountRepository()
+	google := &mockOAuthProvider{profile: &oauth.UserInfo{ProviderUserID: "google-1", Email: "code@example.com", Name: "Code User"}}
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:   newMockUserRepository(),
+		OAuthRepo:  oauthRepo,
+		TokenStore: newMockTokenStore(),
+		JWTSecret:  "test-secret",
+		OAuthProviders: map[model.OAuthProvider]OAuthProvider{
+			model.OAuthProviderGoogle: google,
+			model.OAuthProviderGitHub: &mockOAuthProvider{},
+		},
+	})
+	ctx := context.Background()
+
+	state := startOAuthLogin(t, authService, model.OAuthProviderGoogle)
+	if state == "" {
+		t.Fatal("Expected a state in the consent URL")
+	}
+
+	user, accessToken, _, err := authService.CompleteOAuthLogin(ctx, model.OAuthProviderGoogle, state, "good-code")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Email != "code@example.com" || accessToken == "" {
+		t.Errorf("Expected code@example.com to be signed in, got %s", user.Email)
+	}
+	account, err := oauthRepo.GetByProviderAndProviderUserID(model.OAuthProviderGoogle, "google-1")
+	if err != nil || account.AccessToken != "provider-access" || account.ExpiresAt == nil {
+		t.Errorf("Expected provider tokens to be stored, got %+v, %v", account, err)
+	}
+
+	// States are single use
+	if _, _, _, err := authService.CompleteOAuthLogin(ctx, model.OAuthProviderGoogle, state, "good-code"); err != ErrInvalidOAuthState {
+		t.Errorf("Expected ErrInvalidOAuthState for a reused state, got %v", err)
+	}
+	if _, _, _, err := authService.CompleteOAuthLogin(ctx, model.OAuthProviderGoogle, "forged", "good-code"); err != ErrInvalidOAuthState {
+		t.Errorf("Expected ErrInvalidOAuthState for an unknown state, got %v", err)
+	}
+
+	// A state only works with the provider it was issued for
+	state = startOAuthLogin(t, authService, model.OAuthProviderGoogle)
+	if _, _, _, err := authService.CompleteOAuthLogin(ctx, model.OAuthProviderGitHub, state, "good-code"); err != ErrInvalidOAuthState {
+		t.Errorf("Expected ErrInvalidOAuthState for another provider, got %v", err)
+	}
+
+	state = startOAuthLogin(t, authService, model.OAuthProviderGoogle)
+	if _, _, _, err := authService.CompleteOAuthLogin(ctx, model.OAuthProviderGoogle, state, "bad-code"); err != ErrOAuthExchangeFailed {
+		t.Errorf("Expected ErrOAuthExchangeFailed, got %v", err)
+	}
+
+	google.profileErr = oauth.ErrEmailNotVerified
+	state = startOAuthLogin(t, authService, model.OAuthProviderGoogle)
+	if _, _, _, err := authService.CompleteOAuthLogin(ctx, model.OAuthProviderGoogle, state, "good-code"); err != ErrOAuthEmailNotVerified {
+		t.Errorf("Expected ErrOAuthEmailNotVerified, got %v", err)
+	}
+}
+
+func TestExtendedAuthService_OAuthLoginURL_NotConfigured(t *testing.T) {
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:   newMockUserRepository(),
+		TokenStore: newMockTokenStore(),
+		JWTSecret:  "test-secret",
+	})
+
+	if _, _, err := authService.OAuthLoginURL(model.OAuthProviderGitHub); err != ErrOAuthProviderNotConfigured {
+		t.Errorf("Expected ErrOAuthProviderNotConfigured, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// calendarDateLayout formats trading days in calendar analytics.
+const calendarDateLayout = "2006-01-02"
+
+// DailyPnL is the realized P&L of one trading day.
+type DailyPnL struct {
+	Date       string  `json:"date"`
+	RealizedPL float64 `json:"realized_pl"`
+	TradeCount int     `json:"trade_count"`
+}
+
+// CalendarPnL is a portfolio's realized P&L by trading day for a calendar
+// year, suitable for rendering a heatmap.
+type CalendarPnL struct {
+	PortfolioID     uuid.UUID  `json:"portfolio_id"`
+	Year            int        `json:"year"`
+	Days            []DailyPnL `json:"days"`
+	TotalRealizedPL float64    `json:"total_realized_pl"`
+	BestDay         *DailyPnL  `json:"best_day,omitempty"`
+	WorstDay        *DailyPnL  `json:"worst_day,omitempty"`
+	WinningDays     int        `json:"winning_days"`
+	LosingDays      int        `json:"losing_days"`
+	// CurrentStreak counts consecutive winning (positive) or losing (negative)
+	// days up to the last day with realized P&L. Days that only opened or
+	// added to positions do not break a streak.
+	CurrentStreak int `json:"current_streak"`
+}
+
+// GetCalendarPnL buckets a portfolio's realized P&L by exchange trading day.
+// The whole trade history is replayed so that sells early in the year are
+// measured against cost basis built up in earlier years.
+func (s *paperTradingService) GetCalendarPnL(portfolioID uuid.UUID, year int) (*CalendarPnL, error) {
+	if year == 0 {
+		year = time.Now().In(marketLocation).Year()
+	}
+	if year < 1970 || year > 9999 {
+		return nil, ErrInvalidYear
+	}
+
+	if _, err := s.portfolioRepo.GetByID(portfolioID); err != nil {
+		return nil, ErrPortfolioNotFound
+	}
+
+	trades, err := s.tradeRepo.GetByPortfolioID(portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].ExecutedAt.Before(trades[j].ExecutedAt)
+	})
+
+	type holding struct {
+		quantity int64
+		avgCost  float64
+	}
+	holdings := make(map[string]*holding)
+	days := make(map[string]*DailyPnL)
+
+	for _, trade := range trades {
+		h, ok := holdings[trade.Symbol]
+		if !ok {
+			h = &holding{}
+			holdings[trade.Symbol] = h
+		}
+
+		var realized float64
+		if trade.Side == model.OrderSideBuy {
+			totalCost := float64(h.quantity)*h.avgCost + trade.Total
+			h.quantity += trade.Quantity
+			h.avgCost = totalCost / float64(h.quantity)
+		} else {
+			sold := trade.Quantity
+			if sold > h.quantity {
+				sold = h.quantity
+			}
+			realized = float64(sold) * (trade.Price - h.avgCost)
+			h.quantity -= sold
+			if h.quantity == 0 {
+				h.avgCost = 0
+			}
+		}
+
+		local := trade.ExecutedAt.In(marketLocation)
+		if local.Year() != year {
+			continue
+		}
+		date := local.Format(calendarDateLayout)
+		day, ok := days[date]
+		if !ok {
+			day = &DailyPnL{Date: date}
+			days[date] = day
+		}
+		day.RealizedPL += realized
+		day.TradeCount++
+	}
+
+	calendar := &CalendarPnL{
+		PortfolioID: portfolioID,
+		Year:        year,
+		Days:        make([]DailyPnL, 0, len(days)),
+	}
+	for _, day := range days {
+		calendar.Days = append(calendar.Days, *day)
+	}
+	sort.Slice(calendar.Days, func(i, j int) bool {
+		return calendar.Days[i].Date < calendar.Days[j].Date
+	})
+
+	for i := range calendar.Days {
+		day := &calendar.Days[i]
+		calendar.TotalRealizedPL += day.RealizedPL
+		switch {
+		case day.RealizedPL > 0:
+			calendar.WinningDays++
+		case day.RealizedPL < 0:
+			calendar.LosingDays++
+		default:
+			continue
+		}
+		if calendar.BestDay == nil || day.RealizedPL > calendar.BestDay.RealizedPL {
+			calendar.BestDay = day
+		}
+		if calendar.WorstDay == nil || day.RealizedPL < calendar.WorstDay.RealizedPL {
+			calendar.WorstDay = day
+		}
+	}
+	calendar.CurrentStreak = currentStreak(calendar.Days)
+
+	return calendar, nil
+}
+
+// currentStreak counts the run of same-signed P&L days ending at the last
+// day with realized P&L, negative for a losing run.
+func currentStreak(days []DailyPnL) int {
+	streak := 0
+	for i := len(days) - 1; i >= 0; i-- {
+		pl := days[i].RealizedPL
+		switch {
+		case pl == 0:
+			continue
+		case pl > 0 && streak >= 0:
+			streak++
+		case pl < 0 && streak <= 0:
+			streak--
+		default:
+			return streak
+		}
+	}
+	return streak
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// MaxFeeBps caps the basis-points fee model at 10% of the fill value.
+const MaxFeeBps = 1000
+
+// Fee errors
+var (
+	ErrInvalidFeeModel = errors.New("fee_model must be one of: none, flat, per_share, bps")
+	ErrInvalidFee      = errors.New("fee_amount must not be negative, and at most 1000 for bps")
+)
+
+// FeeSettings configures the commission charged on a portfolio's fills.
+type FeeSettings struct {
+	Model  model.FeeModel `json:"fee_model"`
+	Amount float64        `json:"fee_amount"`
+}
+
+// Validate checks the model is known and the amount is in range.
+func (s FeeSettings) Validate() error {
+	if !s.Model.IsValid() {
+		return ErrInvalidFeeModel
+	}
+	if s.Amount < 0 || (s.Model == model.FeeModelBps && s.Amount > MaxFeeBps) {
+		return ErrInvalidFee
+	}
+	return nil
+}
+
+// UpdateFees changes the commission charged on a portfolio's future fills.
+func (s *paperTradingService) UpdateFees(id uuid.UUID, settings FeeSettings) (*model.Portfolio, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	portfolio, err := s.portfolioRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrPortfolioNotFound
+	}
+
+	portfolio.FeeModel = settings.Model
+	portfolio.FeeAmount = settings.Amount
+
+	if err := s.portfolioRepo.Update(portfolio); err != nil {
+		return nil, err
+	}
+	return portfolio, nil
+}
+
+// tradeFee returns the commission on filling quantity shares at price under
+// the portfolio's fee model.
+func tradeFee(portfolio *model.Portfolio, quantity int64, price float64) float64 {
+	switch portfolio.FeeModel {
+	case model.FeeModelFlat:
+		return portfolio.FeeAmount
+	case model.FeeModelPerShare:
+		return portfolio.FeeAmount * float64(quantity)
+	case model.FeeModelBps:
+		return float64(quantity) * price * portfolio.FeeAmount / 10000
+	default:
+		return 0
+	}
+}
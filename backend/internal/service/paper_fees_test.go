@@ -0,0 +1,142 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestPaperTradingService_CreateOrder_Fees(t *testing.T) {
+	tests := []struct {
+		name      string
+		feeModel  model.FeeModel
+		amount    float64
+		side      model.OrderSide
+		wantPrice float64
+		wantFee   float64
+	}{
+		{"none", model.FeeModelNone, 5, model.OrderSideBuy, 150.15, 0},
+		{"flat buy", model.FeeModelFlat, 1, model.OrderSideBuy, 150.15, 1},
+		{"per share buy", model.FeeModelPerShare, 0.01, model.OrderSideBuy, 150.15, 0.1},
+		{"bps buy", model.FeeModelBps, 5, model.OrderSideBuy, 150.15, 0.75075},
+		{"flat sell", model.FeeModelFlat, 1, model.OrderSideSell, 149.85, 1},
+		{"bps sell", model.FeeModelBps, 5, model.OrderSideSell, 149.85, 0.74925},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, portfolioRepo, positionRepo, _, _ := createTestService()
+			portfolio := &model.Portfolio{
+				ID:            uuid.New(),
+				CashBalance:   100000,
+				SlippageModel: model.SlippageModelFixedBps,
+				SlippageBps:   10,
+				FeeModel:      tt.feeModel,
+				FeeAmount:     tt.amount,
+			}
+			portfolioRepo.portfolios[portfolio.ID] = portfolio
+			position := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Quantity: 100, AvgCost: 100, CreatedAt: time.Now()}
+			positionRepo.positions[position.ID] = position
+
+			_, trade, err := svc.CreateOrder(portfolio.ID, "AAPL", tt.side, model.OrderTypeMarket, 10, 0, model.TimeInForceDay)
+			if err != nil {
+				t.Fatalf("CreateOrder() error = %v", err)
+			}
+
+			if math.Abs(trade.Price-tt.wantPrice) > 1e-9 {
+				t.Errorf("CreateOrder() price = %v, want %v", trade.Price, tt.wantPrice)
+			}
+			if math.Abs(trade.Fees-tt.wantFee) > 1e-9 {
+				t.Errorf("CreateOrder() fees = %v, want %v", trade.Fees, tt.wantFee)
+			}
+
+			// Buys pay quantity*fillPrice + fee; sells receive quantity*fillPrice - fee.
+			wantCash := 100000 - (10*tt.wantPrice + tt.wantFee)
+			if tt.side == model.OrderSideSell {
+				wantCash = 100000 + 10*tt.wantPrice - tt.wantFee
+			}
+			if math.Abs(portfolio.CashBalance-wantCash) > 1e-9 {
+				t.Errorf("Cash balance = %v, want %v", portfolio.CashBalance, wantCash)
+			}
+		})
+	}
+}
+
+func TestPaperTradingService_CreateOrder_FeeMustBeAffordable(t *testing.T) {
+	svc, portfolioRepo, _, _, _ := createTestService()
+	portfolio := &model.Portfolio{ID: uuid.New(), CashBalance: 1500.5, FeeModel: model.FeeModelFlat, FeeAmount: 1}
+	portfolioRepo.portfolios[portfolio.ID] = portfolio
+
+	if _, _, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeMarket, 10, 0, model.TimeInForceDay); err != ErrInsufficientFunds {
+		t.Errorf("CreateOrder() error = %v, want %v", err, ErrInsufficientFunds)
+	}
+	if _, _, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeLimit, 10, 149.99, model.TimeInForceGTC); err != ErrInsufficientFunds {
+		t.Errorf("CreateOrder() resting limit error = %v, want %v", err, ErrInsufficientFunds)
+	}
+	if portfolio.CashBalance != 1500.5 {
+		t.Errorf("Cash balance = %v, want unchanged 1500.5", portfolio.CashBalance)
+	}
+}
+
+func TestPaperTradingService_ProcessPendingOrders_ChargesFee(t *testing.T) {
+	svc, portfolioRepo, _, _, _ := createTestService()
+	prices := svc.(*paperTradingService).priceProvider.(*mockPriceProvider)
+	portfolio := &model.Portfolio{ID: uuid.New(), CashBalance: 10000, FeeModel: model.FeeModelFlat, FeeAmount: 2}
+	portfolioRepo.portfolios[portfolio.ID] = portfolio
+
+	order, _, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeLimit, 10, 140, model.TimeInForceGTC)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	prices.prices["AAPL"] = 135
+
+	if filled, err := svc.ProcessPendingOrders(portfolio.ID); err != nil || filled != 1 {
+		t.Fatalf("ProcessPendingOrders() = %d, %v, want 1, nil", filled, err)
+	}
+	if stored, _ := svc.GetOrder(order.ID); stored.Status != model.OrderStatusFilled {
+		t.Errorf("Order status = %s, want filled", stored.Status)
+	}
+	if want := 10000 - (10*135 + 2.0); portfolio.CashBalance != want {
+		t.Errorf("Cash balance = %v, want %v", portfolio.CashBalance, want)
+	}
+}
+
+func TestPaperTradingService_UpdateFees(t *testing.T) {
+	svc, portfolioRepo, _, _, _ := createTestService()
+	portfolio := &model.Portfolio{ID: uuid.New(), CashBalance: 100000}
+	portfolioRepo.portfolios[portfolio.ID] = portfolio
+
+	tests := []struct {
+		name     string
+		id       uuid.UUID
+		settings FeeSettings
+		wantErr  error
+	}{
+		{"flat", portfolio.ID, FeeSettings{Model: model.FeeModelFlat, Amount: 1}, nil},
+		{"per share", portfolio.ID, FeeSettings{Model: model.FeeModelPerShare, Amount: 0.005}, nil},
+		{"bps", portfolio.ID, FeeSettings{Model: model.FeeModelBps, Amount: 10}, nil},
+		{"unknown model", portfolio.ID, FeeSettings{Model: "random"}, ErrInvalidFeeModel},
+		{"empty model", portfolio.ID, FeeSettings{}, ErrInvalidFeeModel},
+		{"negative amount", portfolio.ID, FeeSettings{Model: model.FeeModelFlat, Amount: -1}, ErrInvalidFee},
+		{"bps too large", portfolio.ID, FeeSettings{Model: model.FeeModelBps, Amount: 1001}, ErrInvalidFee},
+		{"unknown portfolio", uuid.New(), FeeSettings{Model: model.FeeModelNone}, ErrPortfolioNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := svc.UpdateFees(tt.id, tt.settings)
+			if err != tt.wantErr {
+				t.Fatalf("UpdateFees() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if updated.FeeModel != tt.settings.Model || updated.FeeAmount != tt.settings.Amount {
+				t.Errorf("UpdateFees() = %s/%v, want %s/%v", updated.FeeModel, updated.FeeAmount, tt.settings.Model, tt.settings.Amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/lib/calculations"
+	"github.com/google/uuid"
+)
+
+// PerformanceMetrics summarizes how a paper portfolio has performed.
+type PerformanceMetrics struct {
+	RealizedPL   float64 `json:"realized_pl"`
+	UnrealizedPL float64 `json:"unrealized_pl"`
+	Fees         float64 `json:"fees"`
+	// TotalReturn is realized plus unrealized P&L less fees.
+	// TotalReturnPercent is the same as a percentage of the portfolio's
+	// initial cash.
+	TotalReturn        float64 `json:"total_return"`
+	TotalReturnPercent float64 `json:"total_return_percent"`
+	// WinRate is the percentage of closed trades (sells) that realized a profit.
+	WinRate      float64 `json:"win_rate"`
+	ClosedTrades int     `json:"closed_trades"`
+	// SharpeRatio is annualized from the equity curve's daily returns with a
+	// zero risk-free rate. MaxDrawdown is the curve's largest peak-to-trough
+	// fall in percent.
+	SharpeRatio float64 `json:"sharpe_ratio"`
+	MaxDrawdown float64 `json:"max_drawdown"`
+}
+
+// ComputePerformance derives the metrics from the portfolio's equity curve.
+func (s *paperTradingService) ComputePerformance(portfolioID uuid.UUID) (PerformanceMetrics, error) {
+	metrics, equity, err := s.replayEquity(portfolioID)
+	if err != nil {
+		return metrics, err
+	}
+	metrics.SharpeRatio = calculations.SharpeRatio(periodReturns(equity), 0)
+	metrics.MaxDrawdown = calculations.MaxDrawdown(equity)
+	return metrics, nil
+}
+
+// replayEquity replays the portfolio's trades into a daily mark-to-market
+// equity curve: the initial cash, then the cash plus holdings at every
+// trading day's close since the first trade, then today's value at the
+// positions' stored prices. The returned metrics have everything but the
+// curve's ratios filled in.
+func (s *paperTradingService) replayEquity(portfolioID uuid.UUID) (PerformanceMetrics, []float64, error) {
+	var metrics PerformanceMetrics
+
+	portfolio, err := s.portfolioRepo.GetByID(portfolioID)
+	if err != nil {
+		return metrics, nil, ErrPortfolioNotFound
+	}
+	trades, err := s.tradeRepo.GetByPortfolioID(portfolioID)
+	if err != nil {
+		return metrics, nil, err
+	}
+	positions, err := s.positionRepo.GetByPortfolioID(portfolioID)
+	if err != nil {
+		return metrics, nil, err
+	}
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].ExecutedAt.Before(trades[j].ExecutedAt)
+	})
+
+	closes, err := s.dailyEquity(portfolio.InitialCash, trades)
+	if err != nil {
+		return metrics, nil, err
+	}
+	equity := append([]float64{portfolio.InitialCash}, closes...)
+
+	wins := 0
+	for _, trade := range trades {
+		metrics.Fees += trade.Fees
+		if trade.Side != model.OrderSideSell {
+			continue
+		}
+		metrics.ClosedTrades++
+		metrics.RealizedPL += trade.RealizedPL
+		if trade.RealizedPL > 0 {
+			wins++
+		}
+	}
+
+	for _, position := range positions {
+		metrics.UnrealizedPL += (position.CurrentPrice - position.AvgCost) * float64(position.Quantity)
+	}
+	metrics.TotalReturn = metrics.RealizedPL + metrics.UnrealizedPL - metrics.Fees
+	equity = append(equity, portfolio.InitialCash+metrics.TotalReturn)
+
+	if portfolio.InitialCash > 0 {
+		metrics.TotalReturnPercent = metrics.TotalReturn / portfolio.InitialCash * 100
+	}
+	if metrics.ClosedTrades > 0 {
+		metrics.WinRate = float64(wins) / float64(metrics.ClosedTrades) * 100
+	}
+
+	return metrics, equity, nil
+}
+
+// dailyEquity values the portfolio at the close of every trading day from
+// the first of trades, sorted by execution time, up to yesterday. Trading
+// days are the days with trades or with a stored daily bar for a traded
+// symbol. Holdings are valued at that day's close, or at the last close or
+// fill price before it when the symbol has no bar that day.
+func (s *paperTradingService) dailyEquity(cash float64, trades []model.Trade) ([]float64, error) {
+	if len(trades) == 0 {
+		return nil, nil
+	}
+
+	firstDate := tradeDate(trades[0])
+	days := make(map[string]bool)
+	closes := make(map[string]map[string]float64)
+	for _, trade := range trades {
+		days[tradeDate(trade)] = true
+		if _, ok := closes[trade.Symbol]; ok || s.priceHistory == nil {
+			continue
+		}
+
+		// There are fewer trading days than calendar days since the first trade.
+		limit := int(time.Since(trades[0].ExecutedAt).Hours()/24) + 2
+		bars, err := s.priceHistory.GetPriceHistory(trade.Symbol, limit)
+		if err != nil {
+			return nil, err
+		}
+		byDate := make(map[string]float64, len(bars))
+		for _, bar := range bars {
+			// Daily bars are stamped with their session's date at midnight UTC.
+			date := bar.Timestamp.UTC().Format(calendarDateLayout)
+			if bar.Close <= 0 || date < firstDate {
+				continue
+			}
+			byDate[date] = bar.Close
+			days[date] = true
+		}
+		closes[trade.Symbol] = byDate
+	}
+
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	// Today's close is the current value, which the caller appends.
+	today := time.Now().In(marketLocation).Format(calendarDateLayout)
+	holdings := make(map[string]int64)
+	lastPrice := make(map[string]float64)
+	equity := make([]float64, 0, len(dates))
+	next := 0
+	for _, date := range dates {
+		if date >= today {
+			break
+		}
+		for ; next < len(trades) && tradeDate(trades[next]) <= date; next++ {
+			trade := trades[next]
+			cash -= trade.Fees
+			if trade.Side == model.OrderSideSell {
+				cash += trade.Total
+				holdings[trade.Symbol] -= trade.Quantity
+			} else {
+				cash -= trade.Total
+				holdings[trade.Symbol] += trade.Quantity
+			}
+			lastPrice[trade.Symbol] = trade.Price
+		}
+
+		value := cash
+		for symbol, quantity := range holdings {
+			if price, ok := closes[symbol][date]; ok {
+				lastPrice[symbol] = price
+			}
+			value += float64(quantity) * lastPrice[symbol]
+		}
+		equity = append(equity, value)
+	}
+	return equity, nil
+}
+
+// tradeDate is the market date a trade executed on.
+func tradeDate(trade model.Trade) string {
+	return trade.ExecutedAt.In(marketLocation).Format(calendarDateLayout)
+}
+
+// SetPriceHistory sets the daily bars the equity curve is marked to. Without
+// it, holdings are valued at their last fill price until today.
+func (s *paperTradingService) SetPriceHistory(history PriceHistorySource) {
+	s.priceHistory = history
+}
+
+// periodReturns converts a value series into simple returns, skipping
+// periods that start from a non-positive value.
+func periodReturns(values []float64) []float64 {
+	returns := make([]float64, 0, len(values))
+	for i := 1; i < len(values); i++ {
+		if values[i-1] <= 0 {
+			continue
+		}
+		returns = append(returns, values[i]/values[i-1]-1)
+	}
+	return returns
+}
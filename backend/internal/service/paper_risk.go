@@ -0,0 +1,64 @@
+package service
+
+import (
+	"github.com/awaymess/super-dashboard/backend/lib/calculations"
+	"github.com/google/uuid"
+)
+
+// DefaultRiskConfidence is the VaR confidence level, in percent, used when
+// none is given.
+const DefaultRiskConfidence = 95
+
+// RiskMetrics are a paper portfolio's historical downside risk, measured on
+// the daily returns of its equity curve.
+type RiskMetrics struct {
+	// Confidence is the VaR confidence level in percent, e.g. 95.
+	Confidence float64 `json:"confidence"`
+	// Observations is the number of daily returns measured. With fewer than
+	// two, every risk figure is zero.
+	Observations int `json:"observations"`
+	// ValueAtRiskPercent is the daily loss, in percent of equity, exceeded
+	// on only (100 - Confidence)% of days. ConditionalVaRPercent is the
+	// average loss on those days. Both are positive for losses.
+	ValueAtRiskPercent    float64 `json:"value_at_risk_percent"`
+	ConditionalVaRPercent float64 `json:"conditional_var_percent"`
+	// Equity is the portfolio's current value; ValueAtRisk and
+	// ConditionalVaR are the percentages applied to it.
+	Equity         float64 `json:"equity"`
+	ValueAtRisk    float64 `json:"value_at_risk"`
+	ConditionalVaR float64 `json:"conditional_var"`
+}
+
+// ComputeRisk measures historical VaR and CVaR on the daily returns of the
+// portfolio's equity curve. A zero confidence means DefaultRiskConfidence.
+func (s *paperTradingService) ComputeRisk(portfolioID uuid.UUID, confidence float64) (RiskMetrics, error) {
+	if confidence == 0 {
+		confidence = DefaultRiskConfidence
+	}
+	if confidence < 50 || confidence >= 100 {
+		return RiskMetrics{}, ErrInvalidConfidence
+	}
+
+	_, equity, err := s.replayEquity(portfolioID)
+	if err != nil {
+		return RiskMetrics{}, err
+	}
+	returns := periodReturns(equity)
+
+	risk := RiskMetrics{
+		Confidence:            confidence,
+		Observations:          len(returns),
+		ValueAtRiskPercent:    lossPercent(calculations.ValueAtRisk(returns, confidence)),
+		ConditionalVaRPercent: lossPercent(calculations.ConditionalVaR(returns, confidence)),
+		Equity:                equity[len(equity)-1],
+	}
+	risk.ValueAtRisk = risk.Equity * risk.ValueAtRiskPercent / 100
+	risk.ConditionalVaR = risk.Equity * risk.ConditionalVaRPercent / 100
+	return risk, nil
+}
+
+// lossPercent turns a return into a loss in percent. Subtracting from zero
+// keeps a zero return from encoding as -0.
+func lossPercent(r float64) float64 {
+	return 0 - r*100
+}
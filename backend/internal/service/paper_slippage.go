@@ -0,0 +1,168 @@
+package service
+
+import (
+	"errors"
+	"math"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// Slippage limits and defaults
+const (
+	// MaxSlippageBps caps the fixed or minimum slippage at 10%.
+	MaxSlippageBps = 1000
+	// MaxSlippageVolFactor caps the volatility model at five daily standard
+	// deviations.
+	MaxSlippageVolFactor = 5
+	// DefaultVolatilityLookback is the number of daily returns used to
+	// estimate volatility.
+	DefaultVolatilityLookback = 20
+)
+
+// Slippage errors
+var (
+	ErrInvalidSlippageModel = errors.New("slippage_model must be one of: none, fixed_bps, volatility")
+	ErrInvalidSlippage      = errors.New("slippage_bps must be between 0 and 1000 and slippage_vol_factor between 0 and 5")
+	ErrNotEnoughHistory     = errors.New("not enough price history to estimate volatility")
+)
+
+// SlippageSettings configures the slippage applied to a portfolio's market
+// orders.
+type SlippageSettings struct {
+	Model     model.SlippageModel `json:"slippage_model"`
+	Bps       float64             `json:"slippage_bps"`
+	VolFactor float64             `json:"slippage_vol_factor"`
+}
+
+// Validate checks the model is known and the parameters are in range.
+func (s SlippageSettings) Validate() error {
+	if !s.Model.IsValid() {
+		return ErrInvalidSlippageModel
+	}
+	if s.Bps < 0 || s.Bps > MaxSlippageBps || s.VolFactor < 0 || s.VolFactor > MaxSlippageVolFactor {
+		return ErrInvalidSlippage
+	}
+	return nil
+}
+
+// VolatilitySource estimates a symbol's daily volatility as the standard
+// deviation of its daily returns.
+type VolatilitySource interface {
+	DailyVolatility(symbol string) (float64, error)
+}
+
+// PriceHistorySource provides a symbol's most recent price bars, newest first.
+type PriceHistorySource interface {
+	GetPriceHistory(symbol string, limit int) ([]model.StockPrice, error)
+}
+
+// priceHistoryVolatility implements VolatilitySource from stored closes.
+type priceHistoryVolatility struct {
+	history  PriceHistorySource
+	lookback int
+}
+
+// NewPriceHistoryVolatility creates a VolatilitySource that uses the last
+// lookback daily returns of history. A lookback below 2 uses
+// DefaultVolatilityLookback.
+func NewPriceHistoryVolatility(history PriceHistorySource, lookback int) VolatilitySource {
+	if lookback < 2 {
+		lookback = DefaultVolatilityLookback
+	}
+	return &priceHistoryVolatility{history: history, lookback: lookback}
+}
+
+// DailyVolatility returns the sample standard deviation of daily log returns.
+func (v *priceHistoryVolatility) DailyVolatility(symbol string) (float64, error) {
+	bars, err := v.history.GetPriceHistory(symbol, v.lookback+1)
+	if err != nil {
+		return 0, err
+	}
+
+	returns := make([]float64, 0, len(bars))
+	for i := 0; i+1 < len(bars); i++ {
+		if bars[i].Close <= 0 || bars[i+1].Close <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(bars[i].Close/bars[i+1].Close))
+	}
+	if len(returns) < 2 {
+		return 0, ErrNotEnoughHistory
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+
+	return math.Sqrt(variance), nil
+}
+
+// SetVolatilitySource enables the volatility slippage model. Without a
+// source, or when a symbol's volatility cannot be estimated, the model
+// charges only its minimum slippage_bps.
+func (s *paperTradingService) SetVolatilitySource(source VolatilitySource) {
+	s.volatility = source
+}
+
+// UpdateSlippage changes the slippage applied to a portfolio's future market
+// orders.
+func (s *paperTradingService) UpdateSlippage(id uuid.UUID, settings SlippageSettings) (*model.Portfolio, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	portfolio, err := s.portfolioRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrPortfolioNotFound
+	}
+
+	portfolio.SlippageModel = settings.Model
+	portfolio.SlippageBps = settings.Bps
+	portfolio.SlippageVolFactor = settings.VolFactor
+
+	if err := s.portfolioRepo.Update(portfolio); err != nil {
+		return nil, err
+	}
+	return portfolio, nil
+}
+
+// slippageFraction returns the fraction of the reference price that a market
+// fill gives up under the portfolio's slippage model.
+func (s *paperTradingService) slippageFraction(portfolio *model.Portfolio, symbol string) float64 {
+	fraction := portfolio.SlippageBps / 10000
+
+	switch portfolio.SlippageModel {
+	case model.SlippageModelFixedBps:
+		return fraction
+	case model.SlippageModelVolatility:
+		if s.volatility == nil {
+			return fraction
+		}
+		vol, err := s.volatility.DailyVolatility(symbol)
+		if err != nil {
+			return fraction
+		}
+		return math.Max(fraction, portfolio.SlippageVolFactor*vol)
+	default:
+		return 0
+	}
+}
+
+// marketFillPrice applies slippage to a market order's reference price:
+// buys fill higher and sells fill lower.
+func (s *paperTradingService) marketFillPrice(portfolio *model.Portfolio, symbol string, side model.OrderSide, referencePrice float64) float64 {
+	fraction := s.slippageFraction(portfolio, symbol)
+	if side == model.OrderSideBuy {
+		return referencePrice * (1 + fraction)
+	}
+	return referencePrice * (1 - fraction)
+}
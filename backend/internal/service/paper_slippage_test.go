@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// mockVolatilitySource returns a fixed volatility per symbol.
+type mockVolatilitySource struct {
+	vols map[string]float64
+}
+
+func (m *mockVolatilitySource) DailyVolatility(symbol string) (float64, error) {
+	if vol, ok := m.vols[symbol]; ok {
+		return vol, nil
+	}
+	return 0, ErrNotEnoughHistory
+}
+
+// mockPriceHistory returns stored bars newest first.
+type mockPriceHistory struct {
+	bars []model.StockPrice
+	err  error
+}
+
+func (m *mockPriceHistory) GetPriceHistory(symbol string, limit int) ([]model.StockPrice, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if len(m.bars) > limit {
+		return m.bars[:limit], nil
+	}
+	return m.bars, nil
+}
+
+func TestPaperTradingService_CreateOrder_Slippage(t *testing.T) {
+	tests := []struct {
+		name          string
+		slippageModel model.SlippageModel
+		bps           float64
+		volFactor     float64
+		symbol        string
+		side          model.OrderSide
+		wantPrice     float64
+		wantSlippage  float64
+	}{
+		{"none", model.SlippageModelNone, 50, 0, "AAPL", model.OrderSideBuy, 150, 0},
+		{"fixed bps buy", model.SlippageModelFixedBps, 10, 0, "AAPL", model.OrderSideBuy, 150.15, 1.5},
+		{"fixed bps sell", model.SlippageModelFixedBps, 10, 0, "AAPL", model.OrderSideSell, 149.85, 1.5},
+		{"volatility above minimum", model.SlippageModelVolatility, 1, 0.1, "AAPL", model.OrderSideBuy, 150.3, 3},
+		{"volatility below minimum", model.SlippageModelVolatility, 50, 0.1, "AAPL", model.OrderSideBuy, 150.75, 7.5},
+		{"volatility unavailable", model.SlippageModelVolatility, 10, 0.1, "MSFT", model.OrderSideSell, 299.7, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, portfolioRepo, positionRepo, _, _ := createTestService()
+			svc.SetVolatilitySource(&mockVolatilitySource{vols: map[string]float64{"AAPL": 0.02}})
+
+			portfolio := &model.Portfolio{
+				ID:                uuid.New(),
+				CashBalance:       100000,
+				SlippageModel:     tt.slippageModel,
+				SlippageBps:       tt.bps,
+				SlippageVolFactor: tt.volFactor,
+			}
+			portfolioRepo.portfolios[portfolio.ID] = portfolio
+			position := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: tt.symbol, Quantity: 100, AvgCost: 100, CreatedAt: time.Now()}
+			positionRepo.positions[position.ID] = position
+
+			_, trade, err := svc.CreateOrder(portfolio.ID, tt.symbol, tt.side, model.OrderTypeMarket, 10, 0, model.TimeInForceDay)
+			if err != nil {
+				t.Fatalf("CreateOrder() error = %v", err)
+			}
+
+			if math.Abs(trade.Price-tt.wantPrice) > 1e-9 {
+				t.Errorf("CreateOrder() price = %v, want %v", trade.Price, tt.wantPrice)
+			}
+			if math.Abs(trade.Slippage-tt.wantSlippage) > 1e-9 {
+				t.Errorf("CreateOrder() slippage = %v, want %v", trade.Slippage, tt.wantSlippage)
+			}
+			if want := newMockPriceProvider().GetPrice(tt.symbol); trade.ReferencePrice != want {
+				t.Errorf("CreateOrder() reference price = %v, want %v", trade.ReferencePrice, want)
+			}
+		})
+	}
+}
+
+func TestPaperTradingService_CreateOrder_LimitOrderHasNoSlippage(t *testing.T) {
+	svc, portfolioRepo, _, _, _ := createTestService()
+	portfolio := &model.Portfolio{ID: uuid.New(), CashBalance: 100000, SlippageModel: model.SlippageModelFixedBps, SlippageBps: 100}
+	portfolioRepo.portfolios[portfolio.ID] = portfolio
+
+	_, trade, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeLimit, 10, 155, model.TimeInForceGTC)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	if trade.Price != 150 || trade.ReferencePrice != 150 || trade.Slippage != 0 {
+		t.Errorf("CreateOrder() price = %v, reference = %v, slippage = %v, want 150, 150, 0", trade.Price, trade.ReferencePrice, trade.Slippage)
+	}
+}
+
+func TestPaperTradingService_UpdateSlippage(t *testing.T) {
+	svc, portfolioRepo, _, _, _ := createTestService()
+	portfolio := &model.Portfolio{ID: uuid.New(), CashBalance: 100000}
+	portfolioRepo.portfolios[portfolio.ID] = portfolio
+
+	tests := []struct {
+		name     string
+		id       uuid.UUID
+		settings SlippageSettings
+		wantErr  error
+	}{
+		{"fixed bps", portfolio.ID, SlippageSettings{Model: model.SlippageModelFixedBps, Bps: 5}, nil},
+		{"volatility", portfolio.ID, SlippageSettings{Model: model.SlippageModelVolatility, Bps: 2, VolFactor: 0.25}, nil},
+		{"unknown model", portfolio.ID, SlippageSettings{Model: "random"}, ErrInvalidSlippageModel},
+		{"empty model", portfolio.ID, SlippageSettings{}, ErrInvalidSlippageModel},
+		{"negative bps", portfolio.ID, SlippageSettings{Model: model.SlippageModelFixedBps, Bps: -1}, ErrInvalidSlippage},
+		{"bps too large", portfolio.ID, SlippageSettings{Model: model.SlippageModelFixedBps, Bps: 1001}, ErrInvalidSlippage},
+		{"vol factor too large", portfolio.ID, SlippageSettings{Model: model.SlippageModelVolatility, VolFactor: 6}, ErrInvalidSlippage},
+		{"unknown portfolio", uuid.New(), SlippageSettings{Model: model.SlippageModelNone}, ErrPortfolioNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := svc.UpdateSlippage(tt.id, tt.settings)
+			if err != tt.wantErr {
+				t.Fatalf("UpdateSlippage() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if updated.SlippageModel != tt.settings.Model || updated.SlippageBps != tt.settings.Bps || updated.SlippageVolFactor != tt.settings.VolFactor {
+				t.Errorf("UpdateSlippage() = %s/%v/%v, want %s/%v/%v", updated.SlippageModel, updated.SlippageBps, updated.SlippageVolFactor,
+					tt.settings.Model, tt.settings.Bps, tt.settings.VolFactor)
+			}
+		})
+	}
+}
+
+func TestPriceHistoryVolatility_DailyVolatility(t *testing.T) {
+	// Closes alternate up and down by the same log return
+	up := math.Exp(0.01)
+	closes := []float64{100 * up, 100, 100 * up, 100, 100 * up}
+	bars := make([]model.StockPrice, len(closes))
+	for i, c := range closes {
+		bars[i] = model.StockPrice{Close: c}
+	}
+
+	vol, err := NewPriceHistoryVolatility(&mockPriceHistory{bars: bars}, 20).DailyVolatility("AAPL")
+	if err != nil {
+		t.Fatalf("DailyVolatility() error = %v", err)
+	}
+	// Returns are +-0.01 with mean 0; sample variance is 4*0.0001/3
+	if want := math.Sqrt(0.0004 / 3); math.Abs(vol-want) > 1e-12 {
+		t.Errorf("DailyVolatility() = %v, want %v", vol, want)
+	}
+
+	if _, err := NewPriceHistoryVolatility(&mockPriceHistory{bars: bars[:2]}, 20).DailyVolatility("AAPL"); err != ErrNotEnoughHistory {
+		t.Errorf("DailyVolatility() with one return error = %v, want %v", err, ErrNotEnoughHistory)
+	}
+
+	dbErr := errors.New("db down")
+	if _, err := NewPriceHistoryVolatility(&mockPriceHistory{err: dbErr}, 20).DailyVolatility("AAPL"); err != dbErr {
+		t.Errorf("DailyVolatility() error = %v, want %v", err, dbErr)
+	}
+}
@@ -1,12 +1,17 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"math"
+	"sort"
 	"time"
+	_ "time/tzdata" // marketLocation must load without system zoneinfo
 
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 )
 
 // Paper trading service errors.
@@ -18,6 +23,22 @@ var (
 	ErrInsufficientPosition = errors.New("insufficient position quantity")
 	ErrInvalidQuantity      = errors.New("quantity must be greater than 0")
 	ErrInvalidPrice         = errors.New("price must be greater than 0")
+	ErrInvalidOrderStatus   = errors.New("invalid order status")
+	ErrOrderNotPending      = errors.New("only pending orders can be cancelled")
+	ErrInvalidTimeInForce   = errors.New("time_in_force must be one of: DAY, GTC, IOC, FOK")
+	ErrInvalidDateRange     = errors.New("from must be before to")
+	ErrInvalidPagination    = errors.New("limit and offset must not be negative")
+	ErrInvalidYear          = errors.New("year must be between 1970 and 9999")
+	ErrInvalidConfidence    = errors.New("confidence must be at least 50 and below 100")
+)
+
+// MarketCloseHour is the hour (US/Eastern) at which DAY orders expire.
+const MarketCloseHour = 16
+
+// Order and trade history page sizes.
+const (
+	DefaultHistoryLimit = 50
+	MaxHistoryLimit     = 500
 )
 
 // MockPriceProvider provides mock prices for symbols in mock mode.
@@ -57,6 +78,63 @@ func (p *defaultMockPriceProvider) GetPrice(symbol string) float64 {
 	return 100.00
 }
 
+// quotePriceProvider serves live quote prices to the paper trading service.
+type quotePriceProvider struct {
+	quotes QuoteSource
+}
+
+// NewQuotePriceProvider creates a price provider backed by live quotes, so
+// paper orders fill at real prices. A symbol without a quote has price 0,
+// which the paper trading service treats as no price.
+func NewQuotePriceProvider(quotes QuoteSource) MockPriceProvider {
+	return &quotePriceProvider{quotes: quotes}
+}
+
+// GetPrice returns the live price for a symbol, or 0 if it cannot be quoted.
+func (p *quotePriceProvider) GetPrice(symbol string) float64 {
+	quote, err := p.quotes.GetQuote(context.Background(), symbol)
+	if err != nil {
+		log.Warn().Err(err).Str("symbol", symbol).Msg("No live price for paper trading")
+		return 0
+	}
+	return quote.Price
+}
+
+// OpenOrder is a working order annotated with how far the market is from its trigger price.
+type OpenOrder struct {
+	Order        model.Order `json:"order"`
+	CurrentPrice float64     `json:"current_price"`
+	// Distance is the price move needed to trigger the order (trigger - current).
+	// Negative means the price must fall, positive means it must rise. Both
+	// distances are nil when the symbol cannot be quoted.
+	Distance        *float64 `json:"distance,omitempty"`
+	DistancePercent *float64 `json:"distance_percent,omitempty"`
+}
+
+// PositionPnL breaks down a position's profit and loss over its current holding period.
+// A holding period starts when the position is opened from flat; trades from earlier
+// periods that were fully closed are not attributed to it.
+type PositionPnL struct {
+	PositionID   uuid.UUID `json:"position_id"`
+	Symbol       string    `json:"symbol"`
+	Quantity     int64     `json:"quantity"`
+	AvgCost      float64   `json:"avg_cost"`
+	CurrentPrice float64   `json:"current_price"`
+	OpenedAt     time.Time `json:"opened_at"`
+	HoldingDays  int       `json:"holding_days"`
+	TradeCount   int       `json:"trade_count"`
+	// RealizedPL is the price-change P&L locked in by partial sells during the period.
+	RealizedPL float64 `json:"realized_pl"`
+	// UnrealizedPL is the price-change P&L on the remaining quantity.
+	UnrealizedPL  float64 `json:"unrealized_pl"`
+	PriceChangePL float64 `json:"price_change_pl"`
+	Dividends     float64 `json:"dividends"`
+	Fees          float64 `json:"fees"`
+	TotalPL       float64 `json:"total_pl"`
+	CostBasis     float64 `json:"cost_basis"`
+	ReturnPercent float64 `json:"return_percent"`
+}
+
 // PaperTradingService defines the interface for paper trading operations.
 type PaperTradingService interface {
 	// Portfolio operations
@@ -70,14 +148,57 @@ type PaperTradingService interface {
 	// Position operations
 	GetPositions(portfolioID uuid.UUID) ([]model.Position, error)
 	GetPosition(id uuid.UUID) (*model.Position, error)
+	GetPositionPnL(id uuid.UUID) (*PositionPnL, error)
+	// RefreshPrices sets each position's current price from the live quotes
+	// when a mark-to-market service is set, else from the price provider.
+	RefreshPrices(portfolioID uuid.UUID) error
+	// GetPortfolioValue returns the portfolio's cash balance and the market
+	// value of its positions at their current prices.
+	GetPortfolioValue(portfolioID uuid.UUID) (cash float64, marketValue float64, err error)
 
 	// Order operations
-	CreateOrder(portfolioID uuid.UUID, symbol string, side model.OrderSide, orderType model.OrderType, quantity int64, price float64) (*model.Order, *model.Trade, error)
+	CreateOrder(portfolioID uuid.UUID, symbol string, side model.OrderSide, orderType model.OrderType, quantity int64, price float64, timeInForce model.TimeInForce) (*model.Order, *model.Trade, error)
 	GetOrder(id uuid.UUID) (*model.Order, error)
 	GetOrders(portfolioID uuid.UUID) ([]model.Order, error)
+	GetOrdersByStatus(portfolioID uuid.UUID, status model.OrderStatus) ([]model.Order, error)
+	// ListOrders returns a page of order history and the total number of matching orders.
+	// An empty status matches every status.
+	ListOrders(portfolioID uuid.UUID, status model.OrderStatus, filter repository.HistoryFilter) ([]model.Order, int64, error)
+	GetOpenOrders(portfolioID uuid.UUID) ([]OpenOrder, error)
+	// ExpireOrders cancels pending DAY orders whose trading session closed before now.
+	ExpireOrders(now time.Time) (int, error)
+	// ProcessPendingOrders fills the portfolio's resting limit orders that
+	// have become marketable and returns how many filled.
+	ProcessPendingOrders(portfolioID uuid.UUID) (int, error)
+	// CancelOrder cancels a pending order, refunding any cash reserved for it.
+	CancelOrder(id uuid.UUID) (*model.Order, error)
 
 	// Trade operations
 	GetTrades(portfolioID uuid.UUID) ([]model.Trade, error)
+	// ListTrades returns a page of trade history and the total number of matching trades.
+	ListTrades(portfolioID uuid.UUID, filter repository.HistoryFilter) ([]model.Trade, int64, error)
+	// GetRealizedPL returns the P&L realized by all of the portfolio's sells.
+	GetRealizedPL(portfolioID uuid.UUID) (float64, error)
+
+	// Analytics
+	// GetCalendarPnL returns realized P&L per trading day for a calendar year.
+	// A zero year means the current year.
+	GetCalendarPnL(portfolioID uuid.UUID, year int) (*CalendarPnL, error)
+	// ComputePerformance derives return, win rate, Sharpe ratio and max
+	// drawdown from the portfolio's trades and open positions.
+	ComputePerformance(portfolioID uuid.UUID) (PerformanceMetrics, error)
+	// ComputeRisk measures historical VaR and CVaR at a confidence level in
+	// percent from the same equity curve. A zero confidence means 95.
+	ComputeRisk(portfolioID uuid.UUID, confidence float64) (RiskMetrics, error)
+
+	// Configuration
+	// UpdateSlippage sets the slippage model applied to the portfolio's market orders.
+	UpdateSlippage(id uuid.UUID, settings SlippageSettings) (*model.Portfolio, error)
+	// UpdateFees sets the commission charged on the portfolio's fills.
+	UpdateFees(id uuid.UUID, settings FeeSettings) (*model.Portfolio, error)
+	SetVolatilitySource(source VolatilitySource)
+	SetPriceHistory(history PriceHistorySource)
+	SetMarkToMarket(markToMarket MarkToMarketService)
 }
 
 // paperTradingService implements PaperTradingService.
@@ -87,6 +208,9 @@ type paperTradingService struct {
 	orderRepo     repository.OrderRepository
 	tradeRepo     repository.TradeRepository
 	priceProvider MockPriceProvider
+	volatility    VolatilitySource
+	priceHistory  PriceHistorySource
+	markToMarket  MarkToMarketService
 }
 
 // NewPaperTradingService creates a new PaperTradingService instance.
@@ -120,6 +244,7 @@ func (s *paperTradingService) CreatePortfolio(userID uuid.UUID, name string, ini
 		UserID:      userID,
 		Name:        name,
 		CashBalance: initialBalance,
+		InitialCash: initialBalance,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -190,8 +315,174 @@ func (s *paperTradingService) GetPosition(id uuid.UUID) (*model.Position, error)
 	return position, nil
 }
 
-// CreateOrder creates a new order and executes it immediately in mock mode.
-// This implements the simulated fill logic for paper trading.
+// GetPositionPnL attributes a position's P&L over its current holding period
+// by replaying the portfolio's trade history for the symbol.
+func (s *paperTradingService) GetPositionPnL(id uuid.UUID) (*PositionPnL, error) {
+	position, err := s.positionRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrPositionNotFound
+	}
+
+	trades, err := s.tradeRepo.GetByPortfolioID(position.PortfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	symbolTrades := make([]model.Trade, 0, len(trades))
+	for _, trade := range trades {
+		if trade.Symbol == position.Symbol {
+			symbolTrades = append(symbolTrades, trade)
+		}
+	}
+	sort.SliceStable(symbolTrades, func(i, j int) bool {
+		return symbolTrades[i].ExecutedAt.Before(symbolTrades[j].ExecutedAt)
+	})
+
+	var (
+		quantity   int64
+		avgCost    float64
+		realized   float64
+		fees       float64
+		costBasis  float64
+		tradeCount int
+		openedAt   = position.CreatedAt
+	)
+	for _, trade := range symbolTrades {
+		if quantity == 0 {
+			// Flat: a buy opens a new holding period.
+			realized, fees, costBasis, tradeCount = 0, 0, 0, 0
+			openedAt = trade.ExecutedAt
+		}
+		tradeCount++
+		fees += trade.Fees
+
+		if trade.Side == model.OrderSideBuy {
+			totalCost := float64(quantity)*avgCost + trade.Total
+			quantity += trade.Quantity
+			avgCost = totalCost / float64(quantity)
+			costBasis += trade.Total
+			continue
+		}
+
+		sold := trade.Quantity
+		if sold > quantity {
+			sold = quantity
+		}
+		realized += float64(sold) * (trade.Price - avgCost)
+		quantity -= sold
+		if quantity == 0 {
+			avgCost = 0
+		}
+	}
+
+	// Fall back to the stored position when no trade history is available (e.g. seeded data).
+	if tradeCount == 0 || quantity != position.Quantity {
+		quantity = position.Quantity
+		avgCost = position.AvgCost
+		if costBasis == 0 {
+			costBasis = float64(quantity) * avgCost
+		}
+	}
+
+	// Without a quote, value the position at its last stored price.
+	currentPrice := s.priceProvider.GetPrice(position.Symbol)
+	if currentPrice <= 0 {
+		currentPrice = position.CurrentPrice
+	}
+	unrealized := float64(quantity) * (currentPrice - avgCost)
+
+	pnl := &PositionPnL{
+		PositionID:    position.ID,
+		Symbol:        position.Symbol,
+		Quantity:      quantity,
+		AvgCost:       avgCost,
+		CurrentPrice:  currentPrice,
+		OpenedAt:      openedAt,
+		HoldingDays:   int(time.Since(openedAt).Hours() / 24),
+		TradeCount:    tradeCount,
+		RealizedPL:    realized,
+		UnrealizedPL:  unrealized,
+		PriceChangePL: realized + unrealized,
+		Fees:          fees,
+		CostBasis:     costBasis,
+	}
+	// Dividends are not yet recorded on paper trades.
+	pnl.TotalPL = pnl.PriceChangePL + pnl.Dividends - pnl.Fees
+	if costBasis > 0 {
+		pnl.ReturnPercent = pnl.TotalPL / costBasis * 100
+	}
+
+	return pnl, nil
+}
+
+// SetMarkToMarket makes RefreshPrices mark positions to live quotes, which
+// also records each position's day change.
+func (s *paperTradingService) SetMarkToMarket(markToMarket MarkToMarketService) {
+	s.markToMarket = markToMarket
+}
+
+// RefreshPrices updates every position in the portfolio to the latest price:
+// through the mark-to-market service when one is set, else from the price
+// provider. It does nothing without either.
+func (s *paperTradingService) RefreshPrices(portfolioID uuid.UUID) error {
+	if _, err := s.portfolioRepo.GetByID(portfolioID); err != nil {
+		return ErrPortfolioNotFound
+	}
+	if s.markToMarket != nil {
+		_, err := s.markToMarket.RefreshPortfolio(context.Background(), portfolioID)
+		return err
+	}
+	if s.priceProvider == nil {
+		return nil
+	}
+
+	positions, err := s.positionRepo.GetByPortfolioID(portfolioID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range positions {
+		position := &positions[i]
+		price := s.priceProvider.GetPrice(position.Symbol)
+		if price <= 0 {
+			continue
+		}
+		position.CurrentPrice = price
+		position.PriceUpdatedAt = &now
+		position.UpdatedAt = now
+		if err := s.positionRepo.Update(position); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPortfolioValue values the portfolio's positions at their stored current
+// prices; call RefreshPrices first to value them at the latest prices.
+func (s *paperTradingService) GetPortfolioValue(portfolioID uuid.UUID) (float64, float64, error) {
+	portfolio, err := s.portfolioRepo.GetByID(portfolioID)
+	if err != nil {
+		return 0, 0, ErrPortfolioNotFound
+	}
+
+	positions, err := s.positionRepo.GetByPortfolioID(portfolioID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var marketValue float64
+	for _, position := range positions {
+		marketValue += float64(position.Quantity) * position.CurrentPrice
+	}
+	return portfolio.CashBalance, marketValue, nil
+}
+
+// CreateOrder creates a new order and simulates its fill against the mock
+// price. Market orders and marketable limit orders fill immediately; an
+// empty timeInForce defaults to DAY. Limit orders that are not marketable
+// are cancelled if IOC or FOK and otherwise rest as pending orders, with a
+// buy's cost at the limit price reserved from the cash balance.
 func (s *paperTradingService) CreateOrder(
 	portfolioID uuid.UUID,
 	symbol string,
@@ -199,30 +490,97 @@ func (s *paperTradingService) CreateOrder(
 	orderType model.OrderType,
 	quantity int64,
 	price float64,
+	timeInForce model.TimeInForce,
+) (*model.Order, *model.Trade, error) {
+	order, trade, err := s.createOrder(portfolioID, symbol, side, orderType, quantity, price, timeInForce)
+	if order != nil {
+		PaperOrdersTotal.Inc(string(order.Side), string(order.Status))
+	}
+	return order, trade, err
+}
+
+// createOrder implements CreateOrder.
+func (s *paperTradingService) createOrder(
+	portfolioID uuid.UUID,
+	symbol string,
+	side model.OrderSide,
+	orderType model.OrderType,
+	quantity int64,
+	price float64,
+	timeInForce model.TimeInForce,
 ) (*model.Order, *model.Trade, error) {
 	if quantity <= 0 {
 		return nil, nil, ErrInvalidQuantity
 	}
 
+	if timeInForce == "" {
+		timeInForce = model.TimeInForceDay
+	}
+	if !timeInForce.IsValid() {
+		return nil, nil, ErrInvalidTimeInForce
+	}
+
 	// Get portfolio
 	portfolio, err := s.portfolioRepo.GetByID(portfolioID)
 	if err != nil {
 		return nil, nil, ErrPortfolioNotFound
 	}
 
-	// Get execution price (mock mode uses provider price for market orders)
-	executionPrice := price
+	// Get execution price (mock mode uses provider price for market orders,
+	// worsened by the portfolio's slippage model). Marketable limit orders
+	// fill at the current price, which is at least as good as the limit.
+	var referencePrice, executionPrice float64
+	orderPrice := price
 	if orderType == model.OrderTypeMarket {
-		executionPrice = s.priceProvider.GetPrice(symbol)
-	} else if price <= 0 {
-		return nil, nil, ErrInvalidPrice
+		referencePrice = s.priceProvider.GetPrice(symbol)
+		if referencePrice <= 0 {
+			return nil, nil, ErrQuotesUnavailable
+		}
+		executionPrice = s.marketFillPrice(portfolio, symbol, side, referencePrice)
+		orderPrice = executionPrice
+	} else {
+		if price <= 0 {
+			return nil, nil, ErrInvalidPrice
+		}
+		referencePrice = s.priceProvider.GetPrice(symbol)
+		if referencePrice <= 0 {
+			return nil, nil, ErrQuotesUnavailable
+		}
+		executionPrice = referencePrice
+	}
+
+	if orderType == model.OrderTypeLimit && !isMarketable(side, price, referencePrice) {
+		// Immediate-or-cancel style orders never rest.
+		if timeInForce == model.TimeInForceIOC || timeInForce == model.TimeInForceFOK {
+			now := time.Now()
+			order := &model.Order{
+				ID:          uuid.New(),
+				PortfolioID: portfolioID,
+				Symbol:      symbol,
+				Side:        side,
+				OrderType:   orderType,
+				Quantity:    quantity,
+				Price:       price,
+				Status:      model.OrderStatusCancelled,
+				TimeInForce: timeInForce,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+			if err := s.orderRepo.Create(order); err != nil {
+				return nil, nil, err
+			}
+			return order, nil, nil
+		}
+
+		order, err := s.restOrder(portfolio, symbol, side, quantity, price, timeInForce)
+		return order, nil, err
 	}
 
 	total := float64(quantity) * executionPrice
 
 	// Validate order
 	if side == model.OrderSideBuy {
-		if portfolio.CashBalance < total {
+		if portfolio.CashBalance < total+tradeFee(portfolio, quantity, executionPrice) {
 			return nil, nil, ErrInsufficientFunds
 		}
 	} else {
@@ -242,8 +600,9 @@ func (s *paperTradingService) CreateOrder(
 		Side:        side,
 		OrderType:   orderType,
 		Quantity:    quantity,
-		Price:       executionPrice,
+		Price:       orderPrice,
 		Status:      model.OrderStatusFilled, // Immediate fill in mock mode
+		TimeInForce: timeInForce,
 		FilledAt:    &now,
 		CreatedAt:   now,
 		UpdatedAt:   now,
@@ -253,35 +612,110 @@ func (s *paperTradingService) CreateOrder(
 		return nil, nil, err
 	}
 
-	// Create trade
-	trade := &model.Trade{
+	trade, err := s.execute(portfolio, order, referencePrice, executionPrice, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, trade, nil
+}
+
+// restOrder stores a limit order as pending. A buy's cost at the limit price
+// is reserved from the cash balance until it fills or is cancelled.
+func (s *paperTradingService) restOrder(portfolio *model.Portfolio, symbol string, side model.OrderSide, quantity int64, price float64, timeInForce model.TimeInForce) (*model.Order, error) {
+	reserved := float64(quantity) * price
+	if side == model.OrderSideBuy {
+		// The fee is charged at fill time but must be affordable now.
+		if portfolio.CashBalance < reserved+tradeFee(portfolio, quantity, price) {
+			return nil, ErrInsufficientFunds
+		}
+	} else {
+		position, err := s.positionRepo.GetByPortfolioAndSymbol(portfolio.ID, symbol)
+		if err != nil || position.Quantity < quantity {
+			return nil, ErrInsufficientPosition
+		}
+	}
+
+	now := time.Now()
+	order := &model.Order{
 		ID:          uuid.New(),
-		PortfolioID: portfolioID,
-		OrderID:     order.ID,
+		PortfolioID: portfolio.ID,
 		Symbol:      symbol,
 		Side:        side,
+		OrderType:   model.OrderTypeLimit,
 		Quantity:    quantity,
-		Price:       executionPrice,
-		Total:       total,
-		ExecutedAt:  now,
+		Price:       price,
+		Status:      model.OrderStatusPending,
+		TimeInForce: timeInForce,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.orderRepo.Create(order); err != nil {
+		return nil, err
+	}
+
+	if side == model.OrderSideBuy {
+		portfolio.CashBalance -= reserved
+		portfolio.UpdatedAt = now
+		if err := s.portfolioRepo.Update(portfolio); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// execute records the trade for a filled order and applies it to the
+// portfolio's cash and position.
+func (s *paperTradingService) execute(portfolio *model.Portfolio, order *model.Order, referencePrice, executionPrice float64, now time.Time) (*model.Trade, error) {
+	symbol, side, quantity := order.Symbol, order.Side, order.Quantity
+	total := float64(quantity) * executionPrice
+	fee := tradeFee(portfolio, quantity, executionPrice)
+
+	// Sells realize P&L against the position's average cost
+	position, err := s.positionRepo.GetByPortfolioAndSymbol(portfolio.ID, symbol)
+	var realizedPL float64
+	if side == model.OrderSideSell {
+		if err != nil {
+			return nil, ErrPositionNotFound
+		}
+		realizedPL = (executionPrice - position.AvgCost) * float64(quantity)
+	}
+
+	// Create trade
+	slippage := (executionPrice - referencePrice) * float64(quantity)
+	if side == model.OrderSideSell {
+		slippage = -slippage
+	}
+	trade := &model.Trade{
+		ID:             uuid.New(),
+		PortfolioID:    portfolio.ID,
+		OrderID:        order.ID,
+		Symbol:         symbol,
+		Side:           side,
+		Quantity:       quantity,
+		Price:          executionPrice,
+		Total:          total,
+		ReferencePrice: referencePrice,
+		Slippage:       slippage,
+		Fees:           fee,
+		RealizedPL:     realizedPL,
+		ExecutedAt:     now,
 	}
 
 	if err := s.tradeRepo.Create(trade); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Update portfolio and position
 	if side == model.OrderSideBuy {
 		// Deduct cash
-		portfolio.CashBalance -= total
+		portfolio.CashBalance -= total + fee
 
 		// Update or create position
-		position, err := s.positionRepo.GetByPortfolioAndSymbol(portfolioID, symbol)
 		if err != nil {
 			// Create new position
 			position = &model.Position{
 				ID:           uuid.New(),
-				PortfolioID:  portfolioID,
+				PortfolioID:  portfolio.ID,
 				Symbol:       symbol,
 				Quantity:     quantity,
 				AvgCost:      executionPrice,
@@ -290,7 +724,7 @@ func (s *paperTradingService) CreateOrder(
 				UpdatedAt:    now,
 			}
 			if err := s.positionRepo.Create(position); err != nil {
-				return nil, nil, err
+				return nil, err
 			}
 		} else {
 			// Update existing position with weighted average cost
@@ -301,19 +735,14 @@ func (s *paperTradingService) CreateOrder(
 			position.CurrentPrice = executionPrice
 			position.UpdatedAt = now
 			if err := s.positionRepo.Update(position); err != nil {
-				return nil, nil, err
+				return nil, err
 			}
 		}
 	} else {
 		// Add cash from sale
-		portfolio.CashBalance += total
+		portfolio.CashBalance += total - fee
 
 		// Update position
-		position, err := s.positionRepo.GetByPortfolioAndSymbol(portfolioID, symbol)
-		if err != nil {
-			return nil, nil, ErrPositionNotFound
-		}
-
 		position.Quantity -= quantity
 		position.CurrentPrice = executionPrice
 		position.UpdatedAt = now
@@ -321,11 +750,11 @@ func (s *paperTradingService) CreateOrder(
 		if position.Quantity == 0 {
 			// Delete position if quantity is 0
 			if err := s.positionRepo.Delete(position.ID); err != nil {
-				return nil, nil, err
+				return nil, err
 			}
 		} else {
 			if err := s.positionRepo.Update(position); err != nil {
-				return nil, nil, err
+				return nil, err
 			}
 		}
 	}
@@ -333,10 +762,125 @@ func (s *paperTradingService) CreateOrder(
 	// Update portfolio
 	portfolio.UpdatedAt = now
 	if err := s.portfolioRepo.Update(portfolio); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return order, trade, nil
+	return trade, nil
+}
+
+// ProcessPendingOrders fills the portfolio's resting limit orders whose limit
+// the current price has reached: buys once the price is at or below the
+// limit, sells once it is at or above. Fills are at the current price. A
+// sell whose position has shrunk below its quantity is rejected.
+func (s *paperTradingService) ProcessPendingOrders(portfolioID uuid.UUID) (int, error) {
+	portfolio, err := s.portfolioRepo.GetByID(portfolioID)
+	if err != nil {
+		return 0, ErrPortfolioNotFound
+	}
+
+	orders, err := s.GetOrdersByStatus(portfolioID, model.OrderStatusPending)
+	if err != nil {
+		return 0, err
+	}
+
+	filled := 0
+	for i := range orders {
+		order := &orders[i]
+		if order.OrderType != model.OrderTypeLimit {
+			continue
+		}
+		current := s.priceProvider.GetPrice(order.Symbol)
+		if current <= 0 || !isMarketable(order.Side, order.Price, current) {
+			continue
+		}
+
+		now := time.Now()
+		order.UpdatedAt = now
+		if order.Side == model.OrderSideSell {
+			position, err := s.positionRepo.GetByPortfolioAndSymbol(portfolioID, order.Symbol)
+			if err != nil || position.Quantity < order.Quantity {
+				order.Status = model.OrderStatusRejected
+				if err := s.orderRepo.Update(order); err != nil {
+					return filled, err
+				}
+				continue
+			}
+		} else {
+			// Release the reservation; execute charges the actual cost.
+			portfolio.CashBalance += reservedCash(order)
+			cost := float64(order.Quantity)*current + tradeFee(portfolio, order.Quantity, current)
+			if portfolio.CashBalance < cost {
+				// Only a fee raised since the order rested can get here.
+				order.Status = model.OrderStatusRejected
+				if err := s.orderRepo.Update(order); err != nil {
+					return filled, err
+				}
+				if err := s.portfolioRepo.Update(portfolio); err != nil {
+					return filled, err
+				}
+				continue
+			}
+		}
+
+		order.Status = model.OrderStatusFilled
+		order.FilledAt = &now
+		if err := s.orderRepo.Update(order); err != nil {
+			return filled, err
+		}
+		if _, err := s.execute(portfolio, order, current, current, now); err != nil {
+			return filled, err
+		}
+		filled++
+	}
+
+	return filled, nil
+}
+
+// CancelOrder cancels a pending order and returns the cash reserved for it.
+func (s *paperTradingService) CancelOrder(id uuid.UUID) (*model.Order, error) {
+	order, err := s.orderRepo.GetByID(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status != model.OrderStatusPending {
+		return nil, ErrOrderNotPending
+	}
+
+	portfolio, err := s.portfolioRepo.GetByID(order.PortfolioID)
+	if err != nil {
+		return nil, ErrPortfolioNotFound
+	}
+
+	if err := s.cancelPending(portfolio, order, time.Now()); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// cancelPending marks a pending order cancelled and refunds its reservation.
+func (s *paperTradingService) cancelPending(portfolio *model.Portfolio, order *model.Order, now time.Time) error {
+	order.Status = model.OrderStatusCancelled
+	order.UpdatedAt = now
+	if err := s.orderRepo.Update(order); err != nil {
+		return err
+	}
+
+	if refund := reservedCash(order); refund > 0 {
+		portfolio.CashBalance += refund
+		portfolio.UpdatedAt = now
+		if err := s.portfolioRepo.Update(portfolio); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reservedCash is the cash held back for a resting buy limit order.
+func reservedCash(order *model.Order) float64 {
+	if order.Side != model.OrderSideBuy || order.OrderType != model.OrderTypeLimit {
+		return 0
+	}
+	return float64(order.Quantity) * order.Price
 }
 
 // GetOrder retrieves an order by ID.
@@ -353,7 +897,176 @@ func (s *paperTradingService) GetOrders(portfolioID uuid.UUID) ([]model.Order, e
 	return s.orderRepo.GetByPortfolioID(portfolioID)
 }
 
+// GetOrdersByStatus retrieves orders for a portfolio with the given status.
+func (s *paperTradingService) GetOrdersByStatus(portfolioID uuid.UUID, status model.OrderStatus) ([]model.Order, error) {
+	if !status.IsValid() {
+		return nil, ErrInvalidOrderStatus
+	}
+
+	orders, _, err := s.orderRepo.ListByPortfolio(portfolioID, status, repository.HistoryFilter{})
+	return orders, err
+}
+
+// ListOrders retrieves a filtered page of orders for a portfolio.
+func (s *paperTradingService) ListOrders(portfolioID uuid.UUID, status model.OrderStatus, filter repository.HistoryFilter) ([]model.Order, int64, error) {
+	if status != "" && !status.IsValid() {
+		return nil, 0, ErrInvalidOrderStatus
+	}
+
+	filter, err := normalizeHistoryFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.orderRepo.ListByPortfolio(portfolioID, status, filter)
+}
+
+// GetOpenOrders retrieves all pending orders for a portfolio, closest to triggering first.
+func (s *paperTradingService) GetOpenOrders(portfolioID uuid.UUID) ([]OpenOrder, error) {
+	if _, err := s.portfolioRepo.GetByID(portfolioID); err != nil {
+		return nil, ErrPortfolioNotFound
+	}
+
+	orders, err := s.GetOrdersByStatus(portfolioID, model.OrderStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]OpenOrder, 0, len(orders))
+	for _, order := range orders {
+		open := OpenOrder{Order: order}
+		if current := s.priceProvider.GetPrice(order.Symbol); current > 0 {
+			distance := order.Price - current
+			distancePercent := distance / current * 100
+			open.CurrentPrice = current
+			open.Distance = &distance
+			open.DistancePercent = &distancePercent
+		}
+		result = append(result, open)
+	}
+
+	// Orders that cannot be quoted sort last.
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].DistancePercent == nil || result[j].DistancePercent == nil {
+			return result[j].DistancePercent == nil && result[i].DistancePercent != nil
+		}
+		return math.Abs(*result[i].DistancePercent) < math.Abs(*result[j].DistancePercent)
+	})
+
+	return result, nil
+}
+
+// ExpireOrders cancels pending DAY orders whose trading session has closed.
+func (s *paperTradingService) ExpireOrders(now time.Time) (int, error) {
+	portfolios, err := s.portfolioRepo.List()
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, portfolio := range portfolios {
+		orders, err := s.GetOrdersByStatus(portfolio.ID, model.OrderStatusPending)
+		if err != nil {
+			return expired, err
+		}
+		for i := range orders {
+			order := &orders[i]
+			if order.TimeInForce != model.TimeInForceDay && order.TimeInForce != "" {
+				continue
+			}
+			if now.Before(sessionClose(order.CreatedAt)) {
+				continue
+			}
+			if err := s.cancelPending(&portfolio, order, now); err != nil {
+				return expired, err
+			}
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
 // GetTrades retrieves all trades for a portfolio.
 func (s *paperTradingService) GetTrades(portfolioID uuid.UUID) ([]model.Trade, error) {
 	return s.tradeRepo.GetByPortfolioID(portfolioID)
 }
+
+// ListTrades retrieves a filtered page of trades for a portfolio.
+func (s *paperTradingService) ListTrades(portfolioID uuid.UUID, filter repository.HistoryFilter) ([]model.Trade, int64, error) {
+	filter, err := normalizeHistoryFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.tradeRepo.ListByPortfolio(portfolioID, filter)
+}
+
+// GetRealizedPL sums the realized P&L recorded on the portfolio's trades.
+func (s *paperTradingService) GetRealizedPL(portfolioID uuid.UUID) (float64, error) {
+	if _, err := s.portfolioRepo.GetByID(portfolioID); err != nil {
+		return 0, ErrPortfolioNotFound
+	}
+
+	trades, err := s.tradeRepo.GetByPortfolioID(portfolioID)
+	if err != nil {
+		return 0, err
+	}
+
+	var realized float64
+	for _, trade := range trades {
+		realized += trade.RealizedPL
+	}
+	return realized, nil
+}
+
+// normalizeHistoryFilter validates a history filter and clamps its page size.
+func normalizeHistoryFilter(filter repository.HistoryFilter) (repository.HistoryFilter, error) {
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return filter, ErrInvalidPagination
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, ErrInvalidDateRange
+	}
+
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = DefaultHistoryLimit
+	case filter.Limit > MaxHistoryLimit:
+		filter.Limit = MaxHistoryLimit
+	}
+	return filter, nil
+}
+
+// isMarketable reports whether a limit order would execute at the current price.
+func isMarketable(side model.OrderSide, limit, current float64) bool {
+	if side == model.OrderSideBuy {
+		return limit >= current
+	}
+	return limit <= current
+}
+
+// marketLocation is the exchange time zone used for session boundaries. The
+// embedded tzdata keeps daylight saving time correct on hosts without
+// zoneinfo, so a load failure is a build problem.
+var marketLocation = func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}()
+
+// sessionClose returns the market close that ends the session an order was placed in.
+// Orders placed after the close or on a weekend belong to the next weekday's session.
+func sessionClose(placed time.Time) time.Time {
+	local := placed.In(marketLocation)
+	closeAt := time.Date(local.Year(), local.Month(), local.Day(), MarketCloseHour, 0, 0, 0, marketLocation)
+	if !local.Before(closeAt) {
+		closeAt = closeAt.AddDate(0, 0, 1)
+	}
+	for closeAt.Weekday() == time.Saturday || closeAt.Weekday() == time.Sunday {
+		closeAt = closeAt.AddDate(0, 0, 1)
+	}
+	return closeAt
+}
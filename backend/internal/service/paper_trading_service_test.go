@@ -1,11 +1,14 @@
 package service
 
 import (
+	"math"
+	"sort"
 	"testing"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
 )
 
 // mockPortfolioRepository is a mock implementation of PortfolioRepository.
@@ -150,6 +153,31 @@ func (m *mockOrderRepository) GetByPortfolioID(portfolioID uuid.UUID) ([]model.O
 	return result, nil
 }
 
+func (m *mockOrderRepository) ListByPortfolio(portfolioID uuid.UUID, status model.OrderStatus, filter repository.HistoryFilter) ([]model.Order, int64, error) {
+	var result []model.Order
+	for _, o := range m.orders {
+		if o.PortfolioID != portfolioID || (status != "" && o.Status != status) {
+			continue
+		}
+		if (filter.From != nil && o.CreatedAt.Before(*filter.From)) || (filter.To != nil && !o.CreatedAt.Before(*filter.To)) {
+			continue
+		}
+		result = append(result, *o)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	total := int64(len(result))
+	if filter.Offset >= len(result) {
+		return nil, total, nil
+	}
+	result = result[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(result) {
+		result = result[:filter.Limit]
+	}
+	return result, total, nil
+}
+
 func (m *mockOrderRepository) Update(order *model.Order) error {
 	if _, ok := m.orders[order.ID]; !ok {
 		return ErrOrderNotFound
@@ -196,6 +224,31 @@ func (m *mockTradeRepository) GetByPortfolioID(portfolioID uuid.UUID) ([]model.T
 	return result, nil
 }
 
+func (m *mockTradeRepository) ListByPortfolio(portfolioID uuid.UUID, filter repository.HistoryFilter) ([]model.Trade, int64, error) {
+	var result []model.Trade
+	for _, t := range m.trades {
+		if t.PortfolioID != portfolioID {
+			continue
+		}
+		if (filter.From != nil && t.ExecutedAt.Before(*filter.From)) || (filter.To != nil && !t.ExecutedAt.Before(*filter.To)) {
+			continue
+		}
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ExecutedAt.After(result[j].ExecutedAt)
+	})
+	total := int64(len(result))
+	if filter.Offset >= len(result) {
+		return nil, total, nil
+	}
+	result = result[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(result) {
+		result = result[:filter.Limit]
+	}
+	return result, total, nil
+}
+
 func (m *mockTradeRepository) GetByOrderID(orderID uuid.UUID) ([]model.Trade, error) {
 	var result []model.Trade
 	for _, t := range m.trades {
@@ -214,8 +267,8 @@ type mockPriceProvider struct {
 func newMockPriceProvider() *mockPriceProvider {
 	return &mockPriceProvider{
 		prices: map[string]float64{
-			"AAPL": 150.00,
-			"MSFT": 300.00,
+			"AAPL":  150.00,
+			"MSFT":  300.00,
 			"GOOGL": 100.00,
 		},
 	}
@@ -340,6 +393,7 @@ func TestPaperTradingService_CreateOrder_Buy(t *testing.T) {
 			model.OrderTypeMarket,
 			10,
 			0,
+			model.TimeInForceDay,
 		)
 		if err != nil {
 			t.Fatalf("CreateOrder() error = %v", err)
@@ -376,6 +430,7 @@ func TestPaperTradingService_CreateOrder_Buy(t *testing.T) {
 			model.OrderTypeMarket,
 			1000000, // Way too many shares
 			0,
+			model.TimeInForceDay,
 		)
 		if err != ErrInsufficientFunds {
 			t.Errorf("CreateOrder() error = %v, want %v", err, ErrInsufficientFunds)
@@ -390,6 +445,7 @@ func TestPaperTradingService_CreateOrder_Buy(t *testing.T) {
 			model.OrderTypeMarket,
 			0,
 			0,
+			model.TimeInForceDay,
 		)
 		if err != ErrInvalidQuantity {
 			t.Errorf("CreateOrder() error = %v, want %v", err, ErrInvalidQuantity)
@@ -431,6 +487,7 @@ func TestPaperTradingService_CreateOrder_Sell(t *testing.T) {
 			model.OrderTypeMarket,
 			50,
 			0,
+			model.TimeInForceDay,
 		)
 		if err != nil {
 			t.Fatalf("CreateOrder() error = %v", err)
@@ -464,6 +521,7 @@ func TestPaperTradingService_CreateOrder_Sell(t *testing.T) {
 			model.OrderTypeMarket,
 			1000, // More than we have
 			0,
+			model.TimeInForceDay,
 		)
 		if err != ErrInsufficientPosition {
 			t.Errorf("CreateOrder() error = %v, want %v", err, ErrInsufficientPosition)
@@ -485,25 +543,45 @@ func TestPaperTradingService_CreateOrder_LimitOrder(t *testing.T) {
 	}
 	portfolioRepo.portfolios[testPortfolio.ID] = testPortfolio
 
-	t.Run("limit order with valid price", func(t *testing.T) {
+	t.Run("marketable limit order fills at the current price", func(t *testing.T) {
 		order, trade, err := svc.CreateOrder(
 			testPortfolio.ID,
 			"AAPL",
 			model.OrderSideBuy,
 			model.OrderTypeLimit,
 			10,
-			145.00, // Limit price
+			155.00, // Limit price above the current 150
+			model.TimeInForceDay,
 		)
 		if err != nil {
 			t.Fatalf("CreateOrder() error = %v", err)
 		}
 
-		if order.Price != 145.00 {
-			t.Errorf("CreateOrder() price = %v, want 145.00", order.Price)
+		if order.Price != 155.00 || order.Status != model.OrderStatusFilled {
+			t.Errorf("CreateOrder() = %v at %v, want filled at limit 155.00", order.Status, order.Price)
 		}
 
-		if trade.Total != 1450.00 {
-			t.Errorf("Trade total = %v, want 1450.00", trade.Total)
+		if trade.Total != 1500.00 {
+			t.Errorf("Trade total = %v, want 1500.00", trade.Total)
+		}
+	})
+
+	t.Run("non-marketable limit order rests", func(t *testing.T) {
+		order, trade, err := svc.CreateOrder(
+			testPortfolio.ID,
+			"AAPL",
+			model.OrderSideBuy,
+			model.OrderTypeLimit,
+			10,
+			145.00, // Limit price below the current 150
+			model.TimeInForceDay,
+		)
+		if err != nil {
+			t.Fatalf("CreateOrder() error = %v", err)
+		}
+
+		if order.Status != model.OrderStatusPending || trade != nil {
+			t.Errorf("CreateOrder() = %v with trade %v, want pending without trade", order.Status, trade)
 		}
 	})
 
@@ -515,6 +593,7 @@ func TestPaperTradingService_CreateOrder_LimitOrder(t *testing.T) {
 			model.OrderTypeLimit,
 			10,
 			0, // Invalid price for limit order
+			model.TimeInForceDay,
 		)
 		if err != ErrInvalidPrice {
 			t.Errorf("CreateOrder() error = %v, want %v", err, ErrInvalidPrice)
@@ -589,3 +668,898 @@ func TestPaperTradingService_DeletePortfolio(t *testing.T) {
 		}
 	})
 }
+
+func TestPaperTradingService_GetOpenOrders(t *testing.T) {
+	svc, _, _, orderRepo, _ := createTestService()
+
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 100000)
+
+	// AAPL is priced at 150 by the mock provider.
+	far := &model.Order{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Side: model.OrderSideBuy, OrderType: model.OrderTypeLimit, Quantity: 1, Price: 120, Status: model.OrderStatusPending}
+	near := &model.Order{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Side: model.OrderSideSell, OrderType: model.OrderTypeLimit, Quantity: 1, Price: 153, Status: model.OrderStatusPending}
+	filled := &model.Order{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Side: model.OrderSideBuy, OrderType: model.OrderTypeMarket, Quantity: 1, Price: 150, Status: model.OrderStatusFilled}
+	orderRepo.Create(far)
+	orderRepo.Create(near)
+	orderRepo.Create(filled)
+
+	open, err := svc.GetOpenOrders(portfolio.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(open) != 2 {
+		t.Fatalf("Expected 2 open orders, got %d", len(open))
+	}
+	if open[0].Order.ID != near.ID {
+		t.Errorf("Expected closest order first")
+	}
+	if open[0].DistancePercent == nil || math.Abs(*open[0].DistancePercent-2) > 1e-9 {
+		t.Errorf("Expected distance 2%%, got %v", open[0].DistancePercent)
+	}
+	if open[1].DistancePercent == nil || math.Abs(*open[1].DistancePercent+20) > 1e-9 {
+		t.Errorf("Expected distance -20%%, got %v", open[1].DistancePercent)
+	}
+
+	if _, err := svc.GetOpenOrders(uuid.New()); err != ErrPortfolioNotFound {
+		t.Errorf("Expected ErrPortfolioNotFound, got %v", err)
+	}
+	if _, err := svc.GetOrdersByStatus(portfolio.ID, "open"); err != ErrInvalidOrderStatus {
+		t.Errorf("Expected ErrInvalidOrderStatus, got %v", err)
+	}
+}
+
+func TestPaperTradingService_CreateOrder_TimeInForce(t *testing.T) {
+	svc, _, _, _, _ := createTestService()
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 100000)
+
+	tests := []struct {
+		name        string
+		price       float64
+		timeInForce model.TimeInForce
+		wantStatus  model.OrderStatus
+		wantTrade   bool
+		wantErr     error
+	}{
+		{name: "defaults to DAY", price: 145, timeInForce: "", wantStatus: model.OrderStatusPending},
+		{name: "marketable IOC fills", price: 155, timeInForce: model.TimeInForceIOC, wantStatus: model.OrderStatusFilled, wantTrade: true},
+		{name: "non-marketable IOC cancels", price: 145, timeInForce: model.TimeInForceIOC, wantStatus: model.OrderStatusCancelled},
+		{name: "non-marketable FOK cancels", price: 145, timeInForce: model.TimeInForceFOK, wantStatus: model.OrderStatusCancelled},
+		{name: "unknown time in force", price: 145, timeInForce: "WEEK", wantErr: ErrInvalidTimeInForce},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, trade, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeLimit, 1, tt.price, tt.timeInForce)
+			if err != tt.wantErr {
+				t.Fatalf("CreateOrder() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if order.Status != tt.wantStatus {
+				t.Errorf("CreateOrder() status = %v, want %v", order.Status, tt.wantStatus)
+			}
+			if (trade != nil) != tt.wantTrade {
+				t.Errorf("CreateOrder() trade = %v, want trade %v", trade, tt.wantTrade)
+			}
+			if tt.timeInForce == "" && order.TimeInForce != model.TimeInForceDay {
+				t.Errorf("CreateOrder() time in force = %v, want DAY", order.TimeInForce)
+			}
+		})
+	}
+}
+
+func TestPaperTradingService_ExpireOrders(t *testing.T) {
+	svc, _, _, orderRepo, _ := createTestService()
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 100000)
+
+	// Wednesday 10:00 New York time.
+	placed := time.Date(2024, 3, 13, 10, 0, 0, 0, marketLocation)
+	day := &model.Order{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", OrderType: model.OrderTypeLimit, Quantity: 1, Price: 100, Status: model.OrderStatusPending, TimeInForce: model.TimeInForceDay, CreatedAt: placed}
+	gtc := &model.Order{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", OrderType: model.OrderTypeLimit, Quantity: 1, Price: 100, Status: model.OrderStatusPending, TimeInForce: model.TimeInForceGTC, CreatedAt: placed}
+	orderRepo.Create(day)
+	orderRepo.Create(gtc)
+
+	expired, err := svc.ExpireOrders(placed.Add(5 * time.Hour))
+	if err != nil || expired != 0 {
+		t.Fatalf("ExpireOrders() before close = %d, %v; want 0, nil", expired, err)
+	}
+
+	expired, err = svc.ExpireOrders(placed.Add(6 * time.Hour))
+	if err != nil || expired != 1 {
+		t.Fatalf("ExpireOrders() after close = %d, %v; want 1, nil", expired, err)
+	}
+	if status := orderRepo.orders[day.ID].Status; status != model.OrderStatusCancelled {
+		t.Errorf("DAY order status = %v, want cancelled", status)
+	}
+	if status := orderRepo.orders[gtc.ID].Status; status != model.OrderStatusPending {
+		t.Errorf("GTC order status = %v, want pending", status)
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	tests := []struct {
+		name   string
+		placed time.Time
+		want   time.Time
+	}{
+		{name: "before close", placed: time.Date(2024, 3, 13, 9, 30, 0, 0, marketLocation), want: time.Date(2024, 3, 13, 16, 0, 0, 0, marketLocation)},
+		{name: "after close rolls to next day", placed: time.Date(2024, 3, 13, 17, 0, 0, 0, marketLocation), want: time.Date(2024, 3, 14, 16, 0, 0, 0, marketLocation)},
+		{name: "friday after close rolls to monday", placed: time.Date(2024, 3, 15, 18, 0, 0, 0, marketLocation), want: time.Date(2024, 3, 18, 16, 0, 0, 0, marketLocation)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionClose(tt.placed); !got.Equal(tt.want) {
+				t.Errorf("sessionClose() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaperTradingService_GetPositionPnL(t *testing.T) {
+	svc, _, positionRepo, _, tradeRepo := createTestService()
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 100000)
+
+	start := time.Now().Add(-10 * 24 * time.Hour)
+	trades := []struct {
+		side     model.OrderSide
+		quantity int64
+		price    float64
+	}{
+		// First holding period, fully closed at a profit.
+		{model.OrderSideBuy, 10, 100},
+		{model.OrderSideSell, 10, 120},
+		// Reopened: average cost 150, then partially closed at 155.
+		{model.OrderSideBuy, 5, 140},
+		{model.OrderSideBuy, 5, 160},
+		{model.OrderSideSell, 4, 155},
+	}
+	for i, tr := range trades {
+		tradeRepo.Create(&model.Trade{
+			ID:          uuid.New(),
+			PortfolioID: portfolio.ID,
+			Symbol:      "AAPL",
+			Side:        tr.side,
+			Quantity:    tr.quantity,
+			Price:       tr.price,
+			Total:       float64(tr.quantity) * tr.price,
+			ExecutedAt:  start.Add(time.Duration(i) * 24 * time.Hour),
+		})
+	}
+
+	position := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Quantity: 6, AvgCost: 150}
+	positionRepo.Create(position)
+
+	pnl, err := svc.GetPositionPnL(position.ID)
+	if err != nil {
+		t.Fatalf("GetPositionPnL() error = %v", err)
+	}
+
+	if pnl.TradeCount != 3 {
+		t.Errorf("TradeCount = %d, want 3", pnl.TradeCount)
+	}
+	if !pnl.OpenedAt.Equal(start.Add(2 * 24 * time.Hour)) {
+		t.Errorf("OpenedAt = %v, want start of second holding period", pnl.OpenedAt)
+	}
+	if pnl.RealizedPL != 20 {
+		t.Errorf("RealizedPL = %v, want 20", pnl.RealizedPL)
+	}
+	// AAPL is priced at 150 by the mock provider, equal to the average cost.
+	if pnl.UnrealizedPL != 0 {
+		t.Errorf("UnrealizedPL = %v, want 0", pnl.UnrealizedPL)
+	}
+	if pnl.CostBasis != 1500 {
+		t.Errorf("CostBasis = %v, want 1500", pnl.CostBasis)
+	}
+	if pnl.TotalPL != 20 {
+		t.Errorf("TotalPL = %v, want 20", pnl.TotalPL)
+	}
+
+	if _, err := svc.GetPositionPnL(uuid.New()); err != ErrPositionNotFound {
+		t.Errorf("GetPositionPnL() error = %v, want %v", err, ErrPositionNotFound)
+	}
+}
+
+func TestPaperTradingService_UnquotedSymbol(t *testing.T) {
+	portfolioRepo := newMockPortfolioRepository()
+	positionRepo := newMockPositionRepository()
+	orderRepo := newMockOrderRepository()
+	priceProvider := newMockPriceProvider()
+	priceProvider.prices["HALT"] = 0
+	svc := NewPaperTradingService(portfolioRepo, positionRepo, orderRepo, newMockTradeRepository(), priceProvider)
+
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 100000)
+
+	position := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "HALT", Quantity: 10, AvgCost: 50, CurrentPrice: 55}
+	positionRepo.Create(position)
+	pnl, err := svc.GetPositionPnL(position.ID)
+	if err != nil {
+		t.Fatalf("GetPositionPnL() error = %v", err)
+	}
+	if pnl.CurrentPrice != 55 || pnl.UnrealizedPL != 50 {
+		t.Errorf("Expected the stored price 55 and unrealized P&L 50, got %v and %v", pnl.CurrentPrice, pnl.UnrealizedPL)
+	}
+
+	unquoted := &model.Order{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "HALT", Side: model.OrderSideBuy, OrderType: model.OrderTypeLimit, Quantity: 1, Price: 50, Status: model.OrderStatusPending}
+	quoted := &model.Order{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Side: model.OrderSideBuy, OrderType: model.OrderTypeLimit, Quantity: 1, Price: 100, Status: model.OrderStatusPending}
+	orderRepo.Create(unquoted)
+	orderRepo.Create(quoted)
+
+	open, err := svc.GetOpenOrders(portfolio.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(open) != 2 {
+		t.Fatalf("Expected 2 open orders, got %d", len(open))
+	}
+	if open[0].Order.ID != quoted.ID {
+		t.Errorf("Expected the quoted order first")
+	}
+	if open[1].Distance != nil || open[1].DistancePercent != nil {
+		t.Errorf("Expected no distance for an unquoted order, got %v and %v", open[1].Distance, open[1].DistancePercent)
+	}
+}
+
+func TestPaperTradingService_ListTrades(t *testing.T) {
+	svc, _, _, _, tradeRepo := createTestService()
+
+	portfolioID := uuid.New()
+	base := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		tradeRepo.Create(&model.Trade{ID: uuid.New(), PortfolioID: portfolioID, Symbol: "AAPL", ExecutedAt: base.AddDate(0, 0, i)})
+	}
+
+	from := base.AddDate(0, 0, 1)
+	to := base.AddDate(0, 0, 4)
+	later := base.AddDate(0, 0, 10)
+
+	tests := []struct {
+		name      string
+		filter    repository.HistoryFilter
+		wantLen   int
+		wantTotal int64
+		wantFirst time.Time
+		wantErr   error
+	}{
+		{name: "defaults", filter: repository.HistoryFilter{}, wantLen: 5, wantTotal: 5, wantFirst: base.AddDate(0, 0, 4)},
+		{name: "date range", filter: repository.HistoryFilter{From: &from, To: &to}, wantLen: 3, wantTotal: 3, wantFirst: base.AddDate(0, 0, 3)},
+		{name: "second page", filter: repository.HistoryFilter{Limit: 2, Offset: 2}, wantLen: 2, wantTotal: 5, wantFirst: base.AddDate(0, 0, 2)},
+		{name: "offset past end", filter: repository.HistoryFilter{Offset: 10}, wantLen: 0, wantTotal: 5},
+		{name: "negative limit", filter: repository.HistoryFilter{Limit: -1}, wantErr: ErrInvalidPagination},
+		{name: "inverted range", filter: repository.HistoryFilter{From: &later, To: &from}, wantErr: ErrInvalidDateRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trades, total, err := svc.ListTrades(portfolioID, tt.filter)
+			if err != tt.wantErr {
+				t.Fatalf("ListTrades() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(trades) != tt.wantLen {
+				t.Errorf("ListTrades() returned %d trades, want %d", len(trades), tt.wantLen)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("ListTrades() total = %d, want %d", total, tt.wantTotal)
+			}
+			if tt.wantLen > 0 && !trades[0].ExecutedAt.Equal(tt.wantFirst) {
+				t.Errorf("ListTrades() first trade at %v, want %v", trades[0].ExecutedAt, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestPaperTradingService_ListOrders(t *testing.T) {
+	svc, _, _, orderRepo, _ := createTestService()
+
+	portfolioID := uuid.New()
+	orderRepo.Create(&model.Order{ID: uuid.New(), PortfolioID: portfolioID, Status: model.OrderStatusFilled, CreatedAt: time.Now()})
+	orderRepo.Create(&model.Order{ID: uuid.New(), PortfolioID: portfolioID, Status: model.OrderStatusPending, CreatedAt: time.Now()})
+
+	orders, total, err := svc.ListOrders(portfolioID, model.OrderStatusPending, repository.HistoryFilter{})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+	if len(orders) != 1 || total != 1 {
+		t.Errorf("ListOrders() returned %d orders (total %d), want 1", len(orders), total)
+	}
+
+	if _, _, err := svc.ListOrders(portfolioID, "open", repository.HistoryFilter{}); err != ErrInvalidOrderStatus {
+		t.Errorf("ListOrders() error = %v, want %v", err, ErrInvalidOrderStatus)
+	}
+}
+
+func TestPaperTradingService_GetCalendarPnL(t *testing.T) {
+	svc, _, _, _, tradeRepo := createTestService()
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 100000)
+
+	// Mid-session Eastern times, expressed in UTC.
+	at := func(date string) time.Time {
+		day, _ := time.Parse("2006-01-02", date)
+		return day.Add(15 * time.Hour)
+	}
+	trades := []struct {
+		date     string
+		symbol   string
+		side     model.OrderSide
+		quantity int64
+		price    float64
+	}{
+		// Cost basis carried over from the previous year.
+		{"2025-12-30", "AAPL", model.OrderSideBuy, 10, 100},
+		{"2026-01-05", "AAPL", model.OrderSideSell, 2, 110},
+		{"2026-01-05", "MSFT", model.OrderSideBuy, 5, 300},
+		{"2026-01-06", "MSFT", model.OrderSideSell, 5, 280},
+		{"2026-01-07", "AAPL", model.OrderSideBuy, 2, 90},
+		{"2026-01-08", "AAPL", model.OrderSideSell, 4, 95},
+		{"2026-01-09", "AAPL", model.OrderSideSell, 2, 95},
+	}
+	for _, tr := range trades {
+		tradeRepo.Create(&model.Trade{
+			ID:          uuid.New(),
+			PortfolioID: portfolio.ID,
+			Symbol:      tr.symbol,
+			Side:        tr.side,
+			Quantity:    tr.quantity,
+			Price:       tr.price,
+			Total:       float64(tr.quantity) * tr.price,
+			ExecutedAt:  at(tr.date),
+		})
+	}
+
+	calendar, err := svc.GetCalendarPnL(portfolio.ID, 2026)
+	if err != nil {
+		t.Fatalf("GetCalendarPnL() error = %v", err)
+	}
+
+	// AAPL average cost is 100 until the buy at 90 lowers it to 98.
+	want := []DailyPnL{
+		{Date: "2026-01-05", RealizedPL: 20, TradeCount: 2},
+		{Date: "2026-01-06", RealizedPL: -100, TradeCount: 1},
+		{Date: "2026-01-07", RealizedPL: 0, TradeCount: 1},
+		{Date: "2026-01-08", RealizedPL: -12, TradeCount: 1},
+		{Date: "2026-01-09", RealizedPL: -6, TradeCount: 1},
+	}
+	if len(calendar.Days) != len(want) {
+		t.Fatalf("Days = %+v, want %d days", calendar.Days, len(want))
+	}
+	for i, w := range want {
+		got := calendar.Days[i]
+		if got.Date != w.Date || math.Abs(got.RealizedPL-w.RealizedPL) > 1e-9 || got.TradeCount != w.TradeCount {
+			t.Errorf("Days[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+	if calendar.WinningDays != 1 || calendar.LosingDays != 3 {
+		t.Errorf("WinningDays, LosingDays = %d, %d, want 1, 3", calendar.WinningDays, calendar.LosingDays)
+	}
+	if calendar.BestDay == nil || calendar.BestDay.Date != "2026-01-05" {
+		t.Errorf("BestDay = %+v, want 2026-01-05", calendar.BestDay)
+	}
+	if calendar.WorstDay == nil || calendar.WorstDay.Date != "2026-01-06" {
+		t.Errorf("WorstDay = %+v, want 2026-01-06", calendar.WorstDay)
+	}
+	if calendar.CurrentStreak != -3 {
+		t.Errorf("CurrentStreak = %d, want -3", calendar.CurrentStreak)
+	}
+	if math.Abs(calendar.TotalRealizedPL-(-98)) > 1e-9 {
+		t.Errorf("TotalRealizedPL = %v, want -98", calendar.TotalRealizedPL)
+	}
+
+	empty, err := svc.GetCalendarPnL(portfolio.ID, 2024)
+	if err != nil {
+		t.Fatalf("GetCalendarPnL() error = %v", err)
+	}
+	if len(empty.Days) != 0 || empty.BestDay != nil || empty.CurrentStreak != 0 {
+		t.Errorf("Expected an empty calendar for 2024, got %+v", empty)
+	}
+
+	if _, err := svc.GetCalendarPnL(portfolio.ID, 12026); err != ErrInvalidYear {
+		t.Errorf("GetCalendarPnL() error = %v, want %v", err, ErrInvalidYear)
+	}
+	if _, err := svc.GetCalendarPnL(uuid.New(), 2026); err != ErrPortfolioNotFound {
+		t.Errorf("GetCalendarPnL() error = %v, want %v", err, ErrPortfolioNotFound)
+	}
+}
+
+func TestPaperTradingService_RefreshPrices(t *testing.T) {
+	svc, portfolioRepo, positionRepo, _, _ := createTestService()
+
+	portfolio := &model.Portfolio{ID: uuid.New(), UserID: uuid.New(), CashBalance: 10000}
+	portfolioRepo.portfolios[portfolio.ID] = portfolio
+	aapl := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Quantity: 10, AvgCost: 140, CurrentPrice: 140}
+	msft := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "MSFT", Quantity: 5, AvgCost: 280, CurrentPrice: 280}
+	positionRepo.positions[aapl.ID] = aapl
+	positionRepo.positions[msft.ID] = msft
+
+	cash, marketValue, err := svc.GetPortfolioValue(portfolio.ID)
+	if err != nil {
+		t.Fatalf("GetPortfolioValue() error = %v", err)
+	}
+	if cash != 10000 || marketValue != 2800 {
+		t.Errorf("GetPortfolioValue() before refresh = %v, %v, want 10000, 2800", cash, marketValue)
+	}
+
+	if err := svc.RefreshPrices(portfolio.ID); err != nil {
+		t.Fatalf("RefreshPrices() error = %v", err)
+	}
+	if got := positionRepo.positions[aapl.ID]; got.CurrentPrice != 150 || got.PriceUpdatedAt == nil {
+		t.Errorf("AAPL current price = %v, want 150 with an update time", got.CurrentPrice)
+	}
+	if got := positionRepo.positions[msft.ID]; got.CurrentPrice != 300 {
+		t.Errorf("MSFT current price = %v, want 300", got.CurrentPrice)
+	}
+
+	cash, marketValue, err = svc.GetPortfolioValue(portfolio.ID)
+	if err != nil {
+		t.Fatalf("GetPortfolioValue() error = %v", err)
+	}
+	if cash != 10000 || marketValue != 3000 {
+		t.Errorf("GetPortfolioValue() after refresh = %v, %v, want 10000, 3000", cash, marketValue)
+	}
+
+	if err := svc.RefreshPrices(uuid.New()); err != ErrPortfolioNotFound {
+		t.Errorf("RefreshPrices() error = %v, want %v", err, ErrPortfolioNotFound)
+	}
+	if _, _, err := svc.GetPortfolioValue(uuid.New()); err != ErrPortfolioNotFound {
+		t.Errorf("GetPortfolioValue() error = %v, want %v", err, ErrPortfolioNotFound)
+	}
+}
+
+func TestPaperTradingService_RefreshPricesWithoutProvider(t *testing.T) {
+	portfolioRepo := newMockPortfolioRepository()
+	positionRepo := newMockPositionRepository()
+	svc := &paperTradingService{portfolioRepo: portfolioRepo, positionRepo: positionRepo}
+
+	portfolio := &model.Portfolio{ID: uuid.New(), CashBalance: 10000}
+	portfolioRepo.portfolios[portfolio.ID] = portfolio
+	position := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Quantity: 10, CurrentPrice: 140}
+	positionRepo.positions[position.ID] = position
+
+	if err := svc.RefreshPrices(portfolio.ID); err != nil {
+		t.Fatalf("RefreshPrices() error = %v", err)
+	}
+	if position.CurrentPrice != 140 || position.PriceUpdatedAt != nil {
+		t.Errorf("Expected the position to be left as it was, got %+v", position)
+	}
+}
+
+func TestPaperTradingService_RefreshPricesMarksToMarket(t *testing.T) {
+	svc, portfolioRepo, positionRepo, _, _ := createTestService()
+	quotes := &stubQuoteSource{
+		quotes:  map[string]*MarketQuote{"AAPL": {Price: 155, Change: 5, ChangePercent: 3.33}},
+		fetches: map[string]int{},
+	}
+	svc.SetMarkToMarket(NewMarkToMarketService(portfolioRepo, positionRepo, quotes))
+
+	portfolio := &model.Portfolio{ID: uuid.New(), CashBalance: 10000}
+	portfolioRepo.portfolios[portfolio.ID] = portfolio
+	position := &model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Quantity: 10, AvgCost: 140, CurrentPrice: 140}
+	positionRepo.positions[position.ID] = position
+
+	if err := svc.RefreshPrices(portfolio.ID); err != nil {
+		t.Fatalf("RefreshPrices() error = %v", err)
+	}
+	// The live quote wins over the price provider's 150, with its day change.
+	if got := positionRepo.positions[position.ID]; got.CurrentPrice != 155 || got.DayChange != 5 {
+		t.Errorf("AAPL price = %v, day change = %v; want 155, 5", got.CurrentPrice, got.DayChange)
+	}
+	if _, marketValue, err := svc.GetPortfolioValue(portfolio.ID); err != nil || marketValue != 1550 {
+		t.Errorf("GetPortfolioValue() market value = %v, %v; want 1550", marketValue, err)
+	}
+}
+
+func TestPaperTradingService_PendingLimitOrders(t *testing.T) {
+	svc, _, positionRepo, orderRepo, tradeRepo := createTestService()
+	prices := svc.(*paperTradingService).priceProvider.(*mockPriceProvider)
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 10000)
+
+	// AAPL trades at 150: a buy limit at 140 and a sell limit at 160 both rest.
+	buy, _, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeLimit, 10, 140, model.TimeInForceGTC)
+	if err != nil || buy.Status != model.OrderStatusPending {
+		t.Fatalf("CreateOrder() buy = %v, %v; want pending", buy, err)
+	}
+	if portfolio.CashBalance != 8600 {
+		t.Errorf("Cash after reserving = %v, want 8600", portfolio.CashBalance)
+	}
+	if _, _, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeLimit, 100, 140, model.TimeInForceGTC); err != ErrInsufficientFunds {
+		t.Errorf("CreateOrder() over reserved cash error = %v, want %v", err, ErrInsufficientFunds)
+	}
+
+	// Not marketable yet.
+	filled, err := svc.ProcessPendingOrders(portfolio.ID)
+	if err != nil || filled != 0 {
+		t.Fatalf("ProcessPendingOrders() at 150 = %d, %v; want 0, nil", filled, err)
+	}
+
+	// The price drops through the limit: the buy fills at the current price
+	// and the unused reservation is returned.
+	prices.prices["AAPL"] = 138
+	filled, err = svc.ProcessPendingOrders(portfolio.ID)
+	if err != nil || filled != 1 {
+		t.Fatalf("ProcessPendingOrders() at 138 = %d, %v; want 1, nil", filled, err)
+	}
+	if order := orderRepo.orders[buy.ID]; order.Status != model.OrderStatusFilled || order.FilledAt == nil {
+		t.Errorf("Buy order = %v, want filled", order.Status)
+	}
+	if portfolio.CashBalance != 8620 {
+		t.Errorf("Cash after fill = %v, want 8620", portfolio.CashBalance)
+	}
+	position, err := positionRepo.GetByPortfolioAndSymbol(portfolio.ID, "AAPL")
+	if err != nil || position.Quantity != 10 || position.AvgCost != 138 {
+		t.Fatalf("Position after fill = %+v, %v; want 10 at 138", position, err)
+	}
+	if len(tradeRepo.trades) != 1 {
+		t.Errorf("Expected 1 trade, got %d", len(tradeRepo.trades))
+	}
+
+	// A resting sell fills once the price rises to its limit.
+	sell, _, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideSell, model.OrderTypeLimit, 10, 160, model.TimeInForceGTC)
+	if err != nil || sell.Status != model.OrderStatusPending {
+		t.Fatalf("CreateOrder() sell = %v, %v; want pending", sell, err)
+	}
+	prices.prices["AAPL"] = 160
+	filled, err = svc.ProcessPendingOrders(portfolio.ID)
+	if err != nil || filled != 1 {
+		t.Fatalf("ProcessPendingOrders() at 160 = %d, %v; want 1, nil", filled, err)
+	}
+	if portfolio.CashBalance != 10220 {
+		t.Errorf("Cash after sell = %v, want 10220", portfolio.CashBalance)
+	}
+	if _, err := positionRepo.GetByPortfolioAndSymbol(portfolio.ID, "AAPL"); err == nil {
+		t.Error("Expected the position to be closed")
+	}
+}
+
+func TestPaperTradingService_CancelOrder(t *testing.T) {
+	svc, _, _, orderRepo, _ := createTestService()
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 10000)
+
+	pending, _, _ := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeLimit, 10, 140, model.TimeInForceGTC)
+	filled, _, _ := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeMarket, 1, 0, model.TimeInForceDay)
+	if portfolio.CashBalance != 8450 {
+		t.Fatalf("Cash before cancel = %v, want 8450", portfolio.CashBalance)
+	}
+
+	order, err := svc.CancelOrder(pending.ID)
+	if err != nil {
+		t.Fatalf("CancelOrder() error = %v", err)
+	}
+	if order.Status != model.OrderStatusCancelled || orderRepo.orders[pending.ID].Status != model.OrderStatusCancelled {
+		t.Errorf("CancelOrder() status = %v, want cancelled", order.Status)
+	}
+	if portfolio.CashBalance != 9850 {
+		t.Errorf("Cash after cancel = %v, want the 1400 reservation refunded to 9850", portfolio.CashBalance)
+	}
+
+	if _, err := svc.CancelOrder(pending.ID); err != ErrOrderNotPending {
+		t.Errorf("CancelOrder() twice error = %v, want %v", err, ErrOrderNotPending)
+	}
+	if _, err := svc.CancelOrder(filled.ID); err != ErrOrderNotPending {
+		t.Errorf("CancelOrder() filled error = %v, want %v", err, ErrOrderNotPending)
+	}
+	if _, err := svc.CancelOrder(uuid.New()); err != ErrOrderNotFound {
+		t.Errorf("CancelOrder() unknown error = %v, want %v", err, ErrOrderNotFound)
+	}
+}
+
+func TestPaperTradingService_CreateOrder_AverageCost(t *testing.T) {
+	svc, _, positionRepo, _, _ := createTestService()
+	prices := svc.(*paperTradingService).priceProvider.(*mockPriceProvider)
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 100000)
+
+	buy := func(quantity int64, price float64) {
+		t.Helper()
+		prices.prices["AAPL"] = price
+		if _, _, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeMarket, quantity, 0, model.TimeInForceDay); err != nil {
+			t.Fatalf("CreateOrder() buy error = %v", err)
+		}
+	}
+	sell := func(quantity int64, price float64) {
+		t.Helper()
+		prices.prices["AAPL"] = price
+		if _, _, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideSell, model.OrderTypeMarket, quantity, 0, model.TimeInForceDay); err != nil {
+			t.Fatalf("CreateOrder() sell error = %v", err)
+		}
+	}
+
+	buy(10, 150)
+	buy(10, 170)
+	position, err := positionRepo.GetByPortfolioAndSymbol(portfolio.ID, "AAPL")
+	if err != nil {
+		t.Fatalf("GetByPortfolioAndSymbol() error = %v", err)
+	}
+	if position.Quantity != 20 || position.AvgCost != 160 {
+		t.Errorf("Position after two buys = %d at %v, want 20 at 160", position.Quantity, position.AvgCost)
+	}
+	if len(positionRepo.positions) != 1 {
+		t.Errorf("Expected one position, got %d", len(positionRepo.positions))
+	}
+
+	// A partial sell keeps the average cost.
+	sell(5, 180)
+	position, _ = positionRepo.GetByPortfolioAndSymbol(portfolio.ID, "AAPL")
+	if position.Quantity != 15 || position.AvgCost != 160 {
+		t.Errorf("Position after partial sell = %d at %v, want 15 at 160", position.Quantity, position.AvgCost)
+	}
+
+	// Selling the rest closes the position.
+	sell(15, 180)
+	if _, err := positionRepo.GetByPortfolioAndSymbol(portfolio.ID, "AAPL"); err == nil {
+		t.Error("Expected the position to be deleted after a full sell")
+	}
+}
+
+func TestPaperTradingService_RealizedPL(t *testing.T) {
+	svc, _, _, _, _ := createTestService()
+	prices := svc.(*paperTradingService).priceProvider.(*mockPriceProvider)
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 100000)
+
+	trade := func(side model.OrderSide, quantity int64, price float64) *model.Trade {
+		t.Helper()
+		prices.prices["AAPL"] = price
+		_, trade, err := svc.CreateOrder(portfolio.ID, "AAPL", side, model.OrderTypeMarket, quantity, 0, model.TimeInForceDay)
+		if err != nil {
+			t.Fatalf("CreateOrder() error = %v", err)
+		}
+		return trade
+	}
+
+	if buy := trade(model.OrderSideBuy, 20, 150); buy.RealizedPL != 0 {
+		t.Errorf("Buy realized P&L = %v, want 0", buy.RealizedPL)
+	}
+	if profit := trade(model.OrderSideSell, 10, 170); profit.RealizedPL != 200 {
+		t.Errorf("Profitable sell realized P&L = %v, want 200", profit.RealizedPL)
+	}
+	if loss := trade(model.OrderSideSell, 5, 140); loss.RealizedPL != -50 {
+		t.Errorf("Losing sell realized P&L = %v, want -50", loss.RealizedPL)
+	}
+
+	realized, err := svc.GetRealizedPL(portfolio.ID)
+	if err != nil {
+		t.Fatalf("GetRealizedPL() error = %v", err)
+	}
+	if realized != 150 {
+		t.Errorf("GetRealizedPL() = %v, want 150", realized)
+	}
+	if _, err := svc.GetRealizedPL(uuid.New()); err != ErrPortfolioNotFound {
+		t.Errorf("GetRealizedPL() error = %v, want %v", err, ErrPortfolioNotFound)
+	}
+}
+
+func TestPaperTradingService_ComputePerformance(t *testing.T) {
+	svc, _, positionRepo, _, tradeRepo := createTestService()
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 10000)
+
+	// Mid-session Eastern times, expressed in UTC.
+	at := func(date string) time.Time {
+		day, _ := time.Parse("2006-01-02", date)
+		return day.Add(15 * time.Hour)
+	}
+	trades := []struct {
+		date       string
+		side       model.OrderSide
+		quantity   int64
+		price      float64
+		realizedPL float64
+	}{
+		{"2025-03-03", model.OrderSideBuy, 20, 100, 0},
+		{"2025-03-04", model.OrderSideSell, 10, 110, 100},
+		{"2025-03-05", model.OrderSideSell, 5, 90, -50},
+	}
+	for _, tr := range trades {
+		tradeRepo.Create(&model.Trade{
+			ID:          uuid.New(),
+			PortfolioID: portfolio.ID,
+			Symbol:      "AAPL",
+			Side:        tr.side,
+			Quantity:    tr.quantity,
+			Price:       tr.price,
+			Total:       float64(tr.quantity) * tr.price,
+			RealizedPL:  tr.realizedPL,
+			ExecutedAt:  at(tr.date),
+		})
+	}
+	positionRepo.Create(&model.Position{
+		ID:           uuid.New(),
+		PortfolioID:  portfolio.ID,
+		Symbol:       "AAPL",
+		Quantity:     5,
+		AvgCost:      100,
+		CurrentPrice: 120,
+	})
+
+	metrics, err := svc.ComputePerformance(portfolio.ID)
+	if err != nil {
+		t.Fatalf("ComputePerformance() error = %v", err)
+	}
+
+	// Without stored bars the holdings are marked at their fill prices, so
+	// the equity curve is 10000, 10000, 10200, 10000 at each close, then
+	// 10150 now.
+	want := PerformanceMetrics{
+		RealizedPL:         50,
+		UnrealizedPL:       100,
+		TotalReturn:        150,
+		TotalReturnPercent: 1.5,
+		WinRate:            50,
+		ClosedTrades:       2,
+		SharpeRatio:        3.4322919002302212,
+		MaxDrawdown:        200.0 / 10200 * 100,
+	}
+	for _, check := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"RealizedPL", metrics.RealizedPL, want.RealizedPL},
+		{"UnrealizedPL", metrics.UnrealizedPL, want.UnrealizedPL},
+		{"TotalReturn", metrics.TotalReturn, want.TotalReturn},
+		{"TotalReturnPercent", metrics.TotalReturnPercent, want.TotalReturnPercent},
+		{"WinRate", metrics.WinRate, want.WinRate},
+		{"SharpeRatio", metrics.SharpeRatio, want.SharpeRatio},
+		{"MaxDrawdown", metrics.MaxDrawdown, want.MaxDrawdown},
+	} {
+		if math.Abs(check.got-check.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", check.name, check.got, check.want)
+		}
+	}
+	if metrics.ClosedTrades != want.ClosedTrades {
+		t.Errorf("ClosedTrades = %d, want %d", metrics.ClosedTrades, want.ClosedTrades)
+	}
+
+	if _, err := svc.ComputePerformance(uuid.New()); err != ErrPortfolioNotFound {
+		t.Errorf("ComputePerformance() error = %v, want %v", err, ErrPortfolioNotFound)
+	}
+}
+
+func TestPaperTradingService_ReplayEquityMarksToMarket(t *testing.T) {
+	svc, _, positionRepo, _, tradeRepo := createTestService()
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 10000)
+
+	day := func(date string) time.Time {
+		t, _ := time.Parse("2006-01-02", date)
+		return t
+	}
+	// Bought 10 at 100 mid-session on the 3rd and held through the closes
+	// of the 3rd to the 5th. The bar before the purchase is not on the curve.
+	tradeRepo.Create(&model.Trade{
+		ID:          uuid.New(),
+		PortfolioID: portfolio.ID,
+		Symbol:      "AAPL",
+		Side:        model.OrderSideBuy,
+		Quantity:    10,
+		Price:       100,
+		Total:       1000,
+		Fees:        5,
+		ExecutedAt:  day("2025-03-03").Add(15 * time.Hour),
+	})
+	positionRepo.Create(&model.Position{ID: uuid.New(), PortfolioID: portfolio.ID, Symbol: "AAPL", Quantity: 10, AvgCost: 100, CurrentPrice: 108})
+	svc.SetPriceHistory(&mockPriceHistory{bars: []model.StockPrice{
+		{Timestamp: day("2025-03-05"), Close: 110},
+		{Timestamp: day("2025-03-04"), Close: 95},
+		{Timestamp: day("2025-03-03"), Close: 102},
+		{Timestamp: day("2025-02-28"), Close: 99},
+	}})
+
+	_, equity, err := svc.(*paperTradingService).replayEquity(portfolio.ID)
+	if err != nil {
+		t.Fatalf("replayEquity() error = %v", err)
+	}
+	want := []float64{10000, 10015, 9945, 10095, 10075}
+	if len(equity) != len(want) {
+		t.Fatalf("replayEquity() = %v, want %v", equity, want)
+	}
+	for i := range want {
+		if math.Abs(equity[i]-want[i]) > 1e-9 {
+			t.Errorf("replayEquity() = %v, want %v", equity, want)
+			break
+		}
+	}
+}
+
+func TestPaperTradingService_ComputeRisk(t *testing.T) {
+	svc, _, _, _, tradeRepo := createTestService()
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 10000)
+
+	// A buy, then a sell at a 200 loss the next day.
+	day := time.Date(2025, 3, 3, 15, 0, 0, 0, time.UTC)
+	for _, trade := range []model.Trade{
+		{Side: model.OrderSideBuy, Quantity: 10, Price: 100, ExecutedAt: day},
+		{Side: model.OrderSideSell, Quantity: 10, Price: 80, RealizedPL: -200, ExecutedAt: day.AddDate(0, 0, 1)},
+	} {
+		trade.ID, trade.PortfolioID, trade.Symbol = uuid.New(), portfolio.ID, "AAPL"
+		trade.Total = float64(trade.Quantity) * trade.Price
+		tradeRepo.Create(&trade)
+	}
+
+	// Equity curve: 10000, 10000, 9800, then 9800 now, so returns of 0, -2%
+	// and 0. The 5th percentile interpolates 10% of the way from -2% to 0.
+	risk, err := svc.ComputeRisk(portfolio.ID, 0)
+	if err != nil {
+		t.Fatalf("ComputeRisk() error = %v", err)
+	}
+	if risk.Confidence != DefaultRiskConfidence || risk.Observations != 3 || risk.Equity != 9800 {
+		t.Errorf("ComputeRisk() = %+v, want confidence 95 over 3 returns of 9800", risk)
+	}
+	for _, check := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"ValueAtRiskPercent", risk.ValueAtRiskPercent, 1.8},
+		{"ConditionalVaRPercent", risk.ConditionalVaRPercent, 2},
+		{"ValueAtRisk", risk.ValueAtRisk, 176.4},
+		{"ConditionalVaR", risk.ConditionalVaR, 196},
+	} {
+		if math.Abs(check.got-check.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", check.name, check.got, check.want)
+		}
+	}
+
+	for _, confidence := range []float64{-5, 49, 100} {
+		if _, err := svc.ComputeRisk(portfolio.ID, confidence); err != ErrInvalidConfidence {
+			t.Errorf("ComputeRisk(%v) error = %v, want %v", confidence, err, ErrInvalidConfidence)
+		}
+	}
+	if _, err := svc.ComputeRisk(uuid.New(), 0); err != ErrPortfolioNotFound {
+		t.Errorf("ComputeRisk() error = %v, want %v", err, ErrPortfolioNotFound)
+	}
+}
+
+func TestPaperTradingService_ComputeRisk_NoTrades(t *testing.T) {
+	svc, _, _, _, _ := createTestService()
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 10000)
+
+	// A single return is too few to measure, so every figure is zero.
+	risk, err := svc.ComputeRisk(portfolio.ID, 99)
+	if err != nil {
+		t.Fatalf("ComputeRisk() error = %v", err)
+	}
+	want := RiskMetrics{Confidence: 99, Observations: 1, Equity: 10000}
+	if risk != want {
+		t.Errorf("ComputeRisk() = %+v, want %+v", risk, want)
+	}
+	if math.Signbit(risk.ValueAtRiskPercent) || math.Signbit(risk.ConditionalVaRPercent) {
+		t.Errorf("ComputeRisk() = %+v, want positive zeros", risk)
+	}
+}
+
+func TestPaperTradingService_ComputePerformance_NoTrades(t *testing.T) {
+	svc, _, _, _, _ := createTestService()
+	portfolio, _ := svc.CreatePortfolio(uuid.New(), "Test", 10000)
+
+	metrics, err := svc.ComputePerformance(portfolio.ID)
+	if err != nil {
+		t.Fatalf("ComputePerformance() error = %v", err)
+	}
+	if metrics != (PerformanceMetrics{}) {
+		t.Errorf("ComputePerformance() = %+v, want zero metrics", metrics)
+	}
+}
+
+func TestPaperTradingService_CreateOrder_LiveQuotes(t *testing.T) {
+	quotes := &stubQuoteSource{
+		quotes:  map[string]*MarketQuote{"AAPL": {Price: 187.5}},
+		fetches: make(map[string]int),
+	}
+	portfolioRepo := newMockPortfolioRepository()
+	svc := NewPaperTradingService(portfolioRepo, newMockPositionRepository(), newMockOrderRepository(), newMockTradeRepository(), NewQuotePriceProvider(quotes))
+	portfolio := &model.Portfolio{ID: uuid.New(), UserID: uuid.New(), Name: "Live", CashBalance: 10000}
+	portfolioRepo.portfolios[portfolio.ID] = portfolio
+
+	_, trade, err := svc.CreateOrder(portfolio.ID, "AAPL", model.OrderSideBuy, model.OrderTypeMarket, 10, 0, model.TimeInForceDay)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	if trade == nil || trade.Price != 187.5 {
+		t.Errorf("CreateOrder() filled at %+v, want the live price 187.5", trade)
+	}
+
+	// A symbol without a quote is rejected rather than filled at a zero price.
+	if _, _, err := svc.CreateOrder(portfolio.ID, "GONE", model.OrderSideBuy, model.OrderTypeMarket, 1, 0, model.TimeInForceDay); err != ErrQuotesUnavailable {
+		t.Errorf("CreateOrder() without a quote error = %v, want %v", err, ErrQuotesUnavailable)
+	}
+	if _, _, err := svc.CreateOrder(portfolio.ID, "GONE", model.OrderSideBuy, model.OrderTypeLimit, 1, 10, model.TimeInForceDay); err != ErrQuotesUnavailable {
+		t.Errorf("CreateOrder() limit without a quote error = %v, want %v", err, ErrQuotesUnavailable)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+// ChangePassword replaces a user's password after verifying the current one.
+// All sessions and refresh tokens are revoked so other devices must sign in
+// again with the new password.
+func (s *extendedAuthService) ChangePassword(userID uuid.UUID, oldPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.PasswordHash == "" {
+		return ErrPasswordNotSet
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(oldPassword)); err != nil {
+		_ = s.LogAuditEvent(&userID, model.AuditActionPasswordChange, "", "", "invalid current password", false)
+		return ErrInvalidCredentials
+	}
+	if oldPassword == newPassword {
+		return ErrSamePassword
+	}
+
+	hashedPassword, err := s.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	if err := s.revokeUserTokens(userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	_ = s.LogAuditEvent(&userID, model.AuditActionPasswordChange, "", "", "", true)
+	return nil
+}
+
+// RequestPasswordReset issues a password reset token for the account using
+// email and returns it for delivery. It returns an empty token and no error
+// when no account uses email, so callers cannot tell whether it exists.
+func (s *extendedAuthService) RequestPasswordReset(email string) (string, error) {
+	if s.tokenStore == nil {
+		return "", ErrTokenStoreUnavailable
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		return "", nil
+	}
+
+	token, err := generateSecureToken()
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.tokenStore.SetPasswordResetToken(ctx, user.ID.String(), token, PasswordResetTokenDuration); err != nil {
+		return "", fmt.Errorf("failed to store password reset token: %w", err)
+	}
+
+	return token, nil
+}
+
+// ResetPassword sets a new password for the user a reset token was issued
+// to. The token is consumed before anything else, so it is single use even
+// when the reset fails, and all sessions and refresh tokens are revoked as
+// with ChangePassword.
+func (s *extendedAuthService) ResetPassword(token, newPassword string) error {
+	if s.tokenStore == nil {
+		return ErrTokenStoreUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	storedUserID, err := s.tokenStore.ConsumePasswordResetToken(ctx, token)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	userID, err := uuid.Parse(storedUserID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	hashedPassword, err := s.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	if err := s.revokeUserTokens(userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	_ = s.LogAuditEvent(&userID, model.AuditActionPasswordChange, "", "", "password reset", true)
+	return nil
+}
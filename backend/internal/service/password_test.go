@@ -0,0 +1,238 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+func TestExtendedAuthService_ChangePassword(t *testing.T) {
+	userRepo := newMockUserRepository()
+	sessionRepo := newMockSessionRepository()
+	auditRepo := newMockAuditLogRepository()
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:     userRepo,
+		SessionRepo:  sessionRepo,
+		AuditLogRepo: auditRepo,
+		TokenStore:   tokenStore,
+		JWTSecret:    "test-secret",
+	})
+
+	user, err := authService.Register("change@example.com", "password123", "Change User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	_, _, refreshToken, err := authService.CreateSession(user.ID, "Other Device", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		oldPassword string
+		newPassword string
+		wantErr     error
+	}{
+		{name: "wrong current password", oldPassword: "wrongpassword", newPassword: "newpassword", wantErr: ErrInvalidCredentials},
+		{name: "same password", oldPassword: "password123", newPassword: "password123", wantErr: ErrSamePassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := authService.ChangePassword(user.ID, tt.oldPassword, tt.newPassword); err != tt.wantErr {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if err := authService.ChangePassword(user.ID, "password123", "newpassword"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Other devices are signed out
+	if sessions, _ := sessionRepo.GetByUserID(user.ID); len(sessions) != 0 {
+		t.Errorf("Expected sessions to be revoked, got %d", len(sessions))
+	}
+	if _, err := authService.RefreshToken(refreshToken); err == nil {
+		t.Error("Expected refresh token to be revoked")
+	}
+
+	if _, _, err := authService.Login("change@example.com", "password123"); err != ErrInvalidCredentials {
+		t.Errorf("Expected old password to be rejected, got %v", err)
+	}
+	if _, _, err := authService.Login("change@example.com", "newpassword"); err != nil {
+		t.Errorf("Expected new password to work, got %v", err)
+	}
+
+	var succeeded, failed int
+	for _, log := range auditRepo.logs {
+		if log.Action != model.AuditActionPasswordChange {
+			continue
+		}
+		if log.Success {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+	if succeeded != 1 || failed != 1 {
+		t.Errorf("Expected 1 successful and 1 failed password change audit, got %d and %d", succeeded, failed)
+	}
+}
+
+func TestExtendedAuthService_ChangePasswordRevokesLoginTokens(t *testing.T) {
+	tests := []struct {
+		name       string
+		tokenStore TokenStore
+	}{
+		{"with token store", newMockTokenStore()},
+		{"sessions only", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService := NewExtendedAuthService(AuthServiceConfig{
+				UserRepo:    newMockUserRepository(),
+				SessionRepo: newMockSessionRepository(),
+				TokenStore:  tt.tokenStore,
+				JWTSecret:   "test-secret",
+			})
+			user, err := authService.Register("login-tokens@example.com", "password123", "Login User")
+			if err != nil {
+				t.Fatalf("Failed to register user: %v", err)
+			}
+
+			_, refreshToken, err := authService.Login("login-tokens@example.com", "password123")
+			if err != nil {
+				t.Fatalf("Login failed: %v", err)
+			}
+			if _, err := authService.RefreshToken(refreshToken); err != nil {
+				t.Fatalf("Expected the login's refresh token to work, got %v", err)
+			}
+
+			if err := authService.ChangePassword(user.ID, "password123", "newpassword"); err != nil {
+				t.Fatalf("ChangePassword failed: %v", err)
+			}
+			if _, err := authService.RefreshToken(refreshToken); err == nil {
+				t.Error("Expected the refresh token issued before the change to be rejected")
+			}
+		})
+	}
+}
+
+func TestExtendedAuthService_ChangePasswordOAuthOnly(t *testing.T) {
+	userRepo := newMockUserRepository()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:  userRepo,
+		JWTSecret: "test-secret",
+	})
+
+	user := &model.User{ID: uuid.New(), Email: "oauth@example.com", Role: "user"}
+	_ = userRepo.Create(user)
+
+	if err := authService.ChangePassword(user.ID, "", "newpassword"); err != ErrPasswordNotSet {
+		t.Errorf("Expected ErrPasswordNotSet, got %v", err)
+	}
+}
+
+func TestExtendedAuthService_ResetPassword(t *testing.T) {
+	userRepo := newMockUserRepository()
+	sessionRepo := newMockSessionRepository()
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:    userRepo,
+		SessionRepo: sessionRepo,
+		TokenStore:  tokenStore,
+		JWTSecret:   "test-secret",
+	})
+
+	user, err := authService.Register("reset@example.com", "password123", "Reset User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	_, _, refreshToken, err := authService.CreateSession(user.ID, "Other Device", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	token, err := authService.RequestPasswordReset("reset@example.com")
+	if err != nil {
+		t.Fatalf("Failed to request password reset: %v", err)
+	}
+	if token == "" {
+		t.Fatal("Expected a reset token")
+	}
+	if ttl := time.Until(tokenStore.passwordReset[token].expiresAt); ttl <= PasswordResetTokenDuration-time.Minute || ttl > PasswordResetTokenDuration {
+		t.Errorf("Expected token to expire in %v, got %v", PasswordResetTokenDuration, ttl)
+	}
+
+	if err := authService.ResetPassword("unknown-token", "newpassword"); err != ErrInvalidResetToken {
+		t.Errorf("Expected ErrInvalidResetToken for unknown token, got %v", err)
+	}
+
+	if err := authService.ResetPassword(token, "newpassword"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, _, err := authService.Login("reset@example.com", "newpassword"); err != nil {
+		t.Errorf("Expected new password to work, got %v", err)
+	}
+	if _, err := authService.RefreshToken(refreshToken); err == nil {
+		t.Error("Expected refresh token to be revoked")
+	}
+
+	// Tokens are single use
+	if err := authService.ResetPassword(token, "otherpassword"); err != ErrInvalidResetToken {
+		t.Errorf("Expected ErrInvalidResetToken for reused token, got %v", err)
+	}
+	if _, _, err := authService.Login("reset@example.com", "newpassword"); err != nil {
+		t.Errorf("Expected reused token not to change the password, got %v", err)
+	}
+}
+
+func TestExtendedAuthService_ResetPasswordConsumesTokenFirst(t *testing.T) {
+	userRepo := newMockUserRepository()
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:   userRepo,
+		TokenStore: tokenStore,
+		JWTSecret:  "test-secret",
+	})
+
+	user, err := authService.Register("consume@example.com", "password123", "Consume User")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	token, err := authService.RequestPasswordReset("consume@example.com")
+	if err != nil {
+		t.Fatalf("Failed to request password reset: %v", err)
+	}
+	if err := userRepo.Delete(user.ID); err != nil {
+		t.Fatalf("Failed to delete user: %v", err)
+	}
+
+	if err := authService.ResetPassword(token, "newpassword"); err != ErrInvalidResetToken {
+		t.Errorf("Expected ErrInvalidResetToken, got %v", err)
+	}
+	if _, ok := tokenStore.passwordReset[token]; ok {
+		t.Error("Expected the token to be consumed by the failed reset")
+	}
+}
+
+func TestExtendedAuthService_RequestPasswordResetUnknownEmail(t *testing.T) {
+	tokenStore := newMockTokenStore()
+	authService := NewExtendedAuthService(AuthServiceConfig{
+		UserRepo:   newMockUserRepository(),
+		TokenStore: tokenStore,
+		JWTSecret:  "test-secret",
+	})
+
+	token, err := authService.RequestPasswordReset("nobody@example.com")
+	if err != nil || token != "" {
+		t.Errorf("Expected no token and no error, got %q and %v", token, err)
+	}
+	if len(tokenStore.passwordReset) != 0 {
+		t.Errorf("Expected no stored reset tokens, got %d", len(tokenStore.passwordReset))
+	}
+}
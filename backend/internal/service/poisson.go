@@ -0,0 +1,204 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+)
+
+// Poisson model errors
+var (
+	ErrInvalidLambda   = errors.New("home_lambda and away_lambda must be positive")
+	ErrInvalidMaxGoals = errors.New("max_goals must be between 1 and 10")
+)
+
+// MaxPoissonGoals bounds the goals per side in a score matrix. Scores beyond
+// it are left out, so a matrix sums to slightly less than 1.
+const MaxPoissonGoals = 10
+
+// TopScorelines is how many of the likeliest scorelines a prediction lists.
+const TopScorelines = 5
+
+// OverUnderLines are the total goals lines a prediction prices.
+var OverUnderLines = []float64{0.5, 1.5, 2.5, 3.5, 4.5}
+
+// ScoreMatrix holds the probability of each final score, indexed by home
+// goals then away goals.
+type ScoreMatrix [][]float64
+
+// PoissonMatchModel returns the score matrix of a match whose home and away
+// goals are independent Poisson variables with the given means, up to
+// maxGoals per side.
+func PoissonMatchModel(homeLambda, awayLambda float64, maxGoals int) ScoreMatrix {
+	home := poissonDistribution(homeLambda, maxGoals)
+	away := poissonDistribution(awayLambda, maxGoals)
+
+	matrix := make(ScoreMatrix, maxGoals+1)
+	for h := range matrix {
+		matrix[h] = make([]float64, maxGoals+1)
+		for a := range matrix[h] {
+			matrix[h][a] = home[h] * away[a]
+		}
+	}
+	return matrix
+}
+
+// poissonDistribution returns P(X = k) for k up to maxGoals.
+func poissonDistribution(lambda float64, maxGoals int) []float64 {
+	probs := make([]float64, maxGoals+1)
+	probs[0] = math.Exp(-lambda)
+	for k := 1; k <= maxGoals; k++ {
+		probs[k] = probs[k-1] * lambda / float64(k)
+	}
+	return probs
+}
+
+// OverUnderProbability returns the probabilities of more and fewer total
+// goals than line. On a whole-number line the push is in neither.
+func OverUnderProbability(matrix ScoreMatrix, line float64) (over, under float64) {
+	for h, row := range matrix {
+		for a, p := range row {
+			switch total := float64(h + a); {
+			case total > line:
+				over += p
+			case total < line:
+				under += p
+			}
+		}
+	}
+	return over, under
+}
+
+// BTTSProbability returns the probability that both teams score.
+func BTTSProbability(matrix ScoreMatrix) float64 {
+	var p float64
+	for h := 1; h < len(matrix); h++ {
+		for a := 1; a < len(matrix[h]); a++ {
+			p += matrix[h][a]
+		}
+	}
+	return p
+}
+
+// resultProbabilities returns the probabilities of a home win, draw and away win.
+func resultProbabilities(matrix ScoreMatrix) (home, draw, away float64) {
+	for h, row := range matrix {
+		for a, p := range row {
+			switch {
+			case h > a:
+				home += p
+			case h == a:
+				draw += p
+			default:
+				away += p
+			}
+		}
+	}
+	return home, draw, away
+}
+
+// Scoreline is a final score and its probability.
+type Scoreline struct {
+	HomeGoals   int     `json:"home_goals"`
+	AwayGoals   int     `json:"away_goals"`
+	Probability float64 `json:"probability"`
+}
+
+// likeliestScorelines returns the n most probable scores, likeliest first.
+// Equally likely scores are ordered by home then away goals.
+func likeliestScorelines(matrix ScoreMatrix, n int) []Scoreline {
+	var scores []Scoreline
+	for h, row := range matrix {
+		for a, p := range row {
+			scores = append(scores, Scoreline{HomeGoals: h, AwayGoals: a, Probability: p})
+		}
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Probability > scores[j].Probability
+	})
+	if len(scores) > n {
+		scores = scores[:n]
+	}
+	return scores
+}
+
+// OverUnderMarket prices a total goals line.
+type OverUnderMarket struct {
+	Line  float64 `json:"line"`
+	Over  float64 `json:"over"`
+	Under float64 `json:"under"`
+}
+
+// PoissonMarkets are market probabilities derived from a score matrix.
+type PoissonMarkets struct {
+	HomeWin   float64           `json:"home_win"`
+	Draw      float64           `json:"draw"`
+	AwayWin   float64           `json:"away_win"`
+	BTTSYes   float64           `json:"btts_yes"`
+	BTTSNo    float64           `json:"btts_no"`
+	OverUnder []OverUnderMarket `json:"over_under"`
+}
+
+// PoissonPrediction is a match's likeliest scorelines and market
+// probabilities under the Poisson model.
+type PoissonPrediction struct {
+	MatchID    string         `json:"match_id"`
+	HomeLambda float64        `json:"home_lambda"`
+	AwayLambda float64        `json:"away_lambda"`
+	MaxGoals   int            `json:"max_goals"`
+	Scorelines []Scoreline    `json:"scorelines"`
+	Markets    PoissonMarkets `json:"markets"`
+}
+
+// MatchPoissonService defines the interface for Poisson match predictions.
+type MatchPoissonService interface {
+	Predict(matchID string, homeLambda, awayLambda float64, maxGoals int) (*PoissonPrediction, error)
+}
+
+// matchPoissonService implements MatchPoissonService.
+type matchPoissonService struct {
+	matches repository.MatchRepository
+}
+
+// NewMatchPoissonService creates a new MatchPoissonService instance.
+func NewMatchPoissonService(matches repository.MatchRepository) MatchPoissonService {
+	return &matchPoissonService{matches: matches}
+}
+
+// Predict prices a match from the expected goals of each side. maxGoals
+// defaults to MaxPoissonGoals when 0.
+func (s *matchPoissonService) Predict(matchID string, homeLambda, awayLambda float64, maxGoals int) (*PoissonPrediction, error) {
+	if homeLambda <= 0 || awayLambda <= 0 {
+		return nil, ErrInvalidLambda
+	}
+	if maxGoals == 0 {
+		maxGoals = MaxPoissonGoals
+	}
+	if maxGoals < 1 || maxGoals > MaxPoissonGoals {
+		return nil, ErrInvalidMaxGoals
+	}
+	if _, err := s.matches.GetByID(matchID); err != nil {
+		return nil, ErrMatchNotFound
+	}
+
+	matrix := PoissonMatchModel(homeLambda, awayLambda, maxGoals)
+	home, draw, away := resultProbabilities(matrix)
+	btts := BTTSProbability(matrix)
+
+	markets := PoissonMarkets{HomeWin: home, Draw: draw, AwayWin: away, BTTSYes: btts, BTTSNo: home + draw + away - btts}
+	for _, line := range OverUnderLines {
+		over, under := OverUnderProbability(matrix, line)
+		markets.OverUnder = append(markets.OverUnder, OverUnderMarket{Line: line, Over: over, Under: under})
+	}
+
+	return &PoissonPrediction{
+		MatchID:    matchID,
+		HomeLambda: homeLambda,
+		AwayLambda: awayLambda,
+		MaxGoals:   maxGoals,
+		Scorelines: likeliestScorelines(matrix, TopScorelines),
+		Markets:    markets,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+func TestPoissonMatchModel_SumsToOne(t *testing.T) {
+	matrix := PoissonMatchModel(1.6, 1.1, MaxPoissonGoals)
+	if len(matrix) != MaxPoissonGoals+1 || len(matrix[0]) != MaxPoissonGoals+1 {
+		t.Fatalf("PoissonMatchModel() is %dx%d, want %dx%d", len(matrix), len(matrix[0]), MaxPoissonGoals+1, MaxPoissonGoals+1)
+	}
+
+	var total float64
+	for _, row := range matrix {
+		for _, p := range row {
+			total += p
+		}
+	}
+	// Scores above MaxPoissonGoals a side are cut off.
+	if math.Abs(total-1) > 1e-4 {
+		t.Errorf("PoissonMatchModel() sums to %v, want ~1", total)
+	}
+
+	// 0-0 needs neither side to score.
+	if want := math.Exp(-1.6) * math.Exp(-1.1); math.Abs(matrix[0][0]-want) > 1e-12 {
+		t.Errorf("P(0-0) = %v, want %v", matrix[0][0], want)
+	}
+}
+
+func TestPoissonMatchModel_Symmetric(t *testing.T) {
+	matrix := PoissonMatchModel(1.4, 1.4, 6)
+	for h := range matrix {
+		for a := range matrix[h] {
+			if math.Abs(matrix[h][a]-matrix[a][h]) > 1e-12 {
+				t.Errorf("P(%d-%d) = %v, P(%d-%d) = %v, want equal", h, a, matrix[h][a], a, h, matrix[a][h])
+			}
+		}
+	}
+
+	home, _, away := resultProbabilities(matrix)
+	if math.Abs(home-away) > 1e-12 {
+		t.Errorf("home win %v, away win %v, want equal", home, away)
+	}
+}
+
+func TestOverUnderProbability(t *testing.T) {
+	matrix := PoissonMatchModel(1.5, 1.0, MaxPoissonGoals)
+
+	// Total goals are Poisson with mean 2.5.
+	var under float64
+	for _, p := range poissonDistribution(2.5, 2) {
+		under += p
+	}
+	over, gotUnder := OverUnderProbability(matrix, 2.5)
+	if math.Abs(gotUnder-under) > 1e-9 {
+		t.Errorf("under 2.5 = %v, want %v", gotUnder, under)
+	}
+	if math.Abs(over+gotUnder-1) > 1e-4 {
+		t.Errorf("over + under 2.5 = %v, want ~1", over+gotUnder)
+	}
+
+	// On a whole line exactly two goals is a push.
+	over2, under2 := OverUnderProbability(matrix, 2)
+	exactlyTwo := poissonDistribution(2.5, 2)[2]
+	if math.Abs(over2+under2+exactlyTwo-1) > 1e-4 {
+		t.Errorf("over 2 %v + under 2 %v + push %v, want ~1", over2, under2, exactlyTwo)
+	}
+}
+
+func TestBTTSProbability(t *testing.T) {
+	matrix := PoissonMatchModel(1.5, 1.0, MaxPoissonGoals)
+	want := (1 - math.Exp(-1.5)) * (1 - math.Exp(-1.0))
+	if got := BTTSProbability(matrix); math.Abs(got-want) > 1e-4 {
+		t.Errorf("BTTSProbability() = %v, want %v", got, want)
+	}
+}
+
+func TestMatchPoissonService_Predict(t *testing.T) {
+	svc := NewMatchPoissonService(&stubMatches{match: model.Match{}})
+
+	prediction, err := svc.Predict("1", 1.2, 1.2, 0)
+	if err != nil {
+		t.Fatalf("Predict() error = %v", err)
+	}
+	if prediction.MaxGoals != MaxPoissonGoals || len(prediction.Scorelines) != TopScorelines {
+		t.Errorf("Predict() = %+v, want %d goals and %d scorelines", prediction, MaxPoissonGoals, TopScorelines)
+	}
+	// With 1.2 expected goals a side, 1-1 is the likeliest score, then the
+	// two 1-0 results in home-first order.
+	want := []Scoreline{{HomeGoals: 1, AwayGoals: 1}, {HomeGoals: 0, AwayGoals: 1}, {HomeGoals: 1, AwayGoals: 0}}
+	for i, w := range want {
+		got := prediction.Scorelines[i]
+		if got.HomeGoals != w.HomeGoals || got.AwayGoals != w.AwayGoals {
+			t.Errorf("scoreline %d = %d-%d, want %d-%d", i, got.HomeGoals, got.AwayGoals, w.HomeGoals, w.AwayGoals)
+		}
+	}
+	if math.Abs(prediction.Scorelines[1].Probability-prediction.Scorelines[2].Probability) > 1e-12 {
+		t.Errorf("0-1 and 1-0 have probabilities %v and %v, want equal", prediction.Scorelines[1].Probability, prediction.Scorelines[2].Probability)
+	}
+	m := prediction.Markets
+	if math.Abs(m.HomeWin-m.AwayWin) > 1e-12 || len(m.OverUnder) != len(OverUnderLines) {
+		t.Errorf("Predict() markets = %+v, want symmetric results and every line", m)
+	}
+
+	for _, tt := range []struct {
+		name       string
+		matchID    string
+		home, away float64
+		maxGoals   int
+		want       error
+	}{
+		{"zero home lambda", "1", 0, 1, 0, ErrInvalidLambda},
+		{"negative away lambda", "1", 1, -1, 0, ErrInvalidLambda},
+		{"too many goals", "1", 1, 1, MaxPoissonGoals + 1, ErrInvalidMaxGoals},
+		{"negative goals", "1", 1, 1, -1, ErrInvalidMaxGoals},
+		{"unknown match", "missing", 1, 1, 0, ErrMatchNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Predict(tt.matchID, tt.home, tt.away, tt.maxGoals); err != tt.want {
+				t.Errorf("Predict() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
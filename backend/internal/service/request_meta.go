@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+// RequestMeta describes the client a call is made on behalf of. It is
+// recorded on the audit events the call writes.
+type RequestMeta struct {
+	IPAddress string
+	UserAgent string
+}
+
+type requestMetaKey struct{}
+
+// WithRequestMeta returns a copy of ctx carrying meta.
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFromContext returns the RequestMeta stored in ctx, or the zero
+// value when there is none.
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
+
+// logAuditEventContext logs an audit event with the client details carried
+// by ctx.
+func (s *extendedAuthService) logAuditEventContext(ctx context.Context, userID *uuid.UUID, action model.AuditAction, details string, success bool) error {
+	meta := RequestMetaFromContext(ctx)
+	return s.LogAuditEvent(userID, action, meta.IPAddress, meta.UserAgent, details, success)
+}
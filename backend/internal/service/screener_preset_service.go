@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/pkg/pq"
+	"github.com/google/uuid"
+)
+
+// Screener preset service errors.
+var (
+	ErrPresetNotFound      = errors.New("screener preset not found")
+	ErrInvalidPresetName   = errors.New("preset name is required")
+	ErrInvalidPresetFilter = errors.New("preset filter must be a JSON object")
+	ErrScreenerUnavailable = errors.New("screener is not available")
+)
+
+// ScreenRunner executes a screener filter and returns the symbols that match it.
+type ScreenRunner interface {
+	RunScreen(ctx context.Context, filter json.RawMessage) ([]string, error)
+}
+
+// PresetNotificationSink stores notifications raised when new symbols enter a preset.
+type PresetNotificationSink interface {
+	CreateNotification(ctx context.Context, notification *model.Notification) error
+}
+
+// PresetRunResult is the outcome of running a saved screener preset.
+type PresetRunResult struct {
+	PresetID uuid.UUID `json:"preset_id"`
+	Symbols  []string  `json:"symbols"`
+	Added    []string  `json:"added"`   // Symbols not present in the last daily snapshot
+	Removed  []string  `json:"removed"` // Symbols that dropped out since the last daily snapshot
+	RanAt    time.Time `json:"ran_at"`
+}
+
+// ScreenerPresetService defines the interface for saved screener filters.
+type ScreenerPresetService interface {
+	CreatePreset(userID uuid.UUID, name string, filter json.RawMessage, notifyOnNew bool) (*model.ScreenerPreset, error)
+	GetPreset(userID, id uuid.UUID) (*model.ScreenerPreset, error)
+	ListPresets(userID uuid.UUID) ([]model.ScreenerPreset, error)
+	DeletePreset(userID, id uuid.UUID) error
+
+	// RunPreset runs a preset on demand. It reports changes against the last
+	// daily snapshot but does not advance it.
+	RunPreset(ctx context.Context, userID, id uuid.UUID) (*PresetRunResult, error)
+
+	// DiffAll runs every preset, stores the new snapshot and notifies owners
+	// of symbols that entered the result set since the previous run.
+	DiffAll(ctx context.Context) ([]PresetRunResult, error)
+}
+
+// screenerPresetService implements ScreenerPresetService.
+type screenerPresetService struct {
+	presetRepo repository.ScreenerPresetRepository
+	runner     ScreenRunner
+	sink       PresetNotificationSink
+}
+
+// NewScreenerPresetService creates a new ScreenerPresetService instance.
+// runner and sink may be nil; running presets then fails with ErrScreenerUnavailable
+// and daily diffs are recorded without notifications.
+func NewScreenerPresetService(presetRepo repository.ScreenerPresetRepository, runner ScreenRunner, sink PresetNotificationSink) ScreenerPresetService {
+	return &screenerPresetService{
+		presetRepo: presetRepo,
+		runner:     runner,
+		sink:       sink,
+	}
+}
+
+// CreatePreset saves a new screener preset for a user.
+func (s *screenerPresetService) CreatePreset(userID uuid.UUID, name string, filter json.RawMessage, notifyOnNew bool) (*model.ScreenerPreset, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidPresetName
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(filter, &obj); err != nil || obj == nil {
+		return nil, ErrInvalidPresetFilter
+	}
+
+	preset := &model.ScreenerPreset{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        name,
+		Filter:      string(filter),
+		NotifyOnNew: notifyOnNew,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.presetRepo.Create(preset); err != nil {
+		return nil, err
+	}
+
+	return preset, nil
+}
+
+// GetPreset retrieves a preset owned by the given user.
+func (s *screenerPresetService) GetPreset(userID, id uuid.UUID) (*model.ScreenerPreset, error) {
+	preset, err := s.presetRepo.GetByID(id)
+	if err != nil || preset.UserID != userID {
+		return nil, ErrPresetNotFound
+	}
+	return preset, nil
+}
+
+// ListPresets lists all presets owned by a user.
+func (s *screenerPresetService) ListPresets(userID uuid.UUID) ([]model.ScreenerPreset, error) {
+	return s.presetRepo.GetByUserID(userID)
+}
+
+// DeletePreset deletes a preset owned by the given user.
+func (s *screenerPresetService) DeletePreset(userID, id uuid.UUID) error {
+	if _, err := s.GetPreset(userID, id); err != nil {
+		return err
+	}
+	return s.presetRepo.Delete(id)
+}
+
+// RunPreset runs a preset on demand.
+func (s *screenerPresetService) RunPreset(ctx context.Context, userID, id uuid.UUID) (*PresetRunResult, error) {
+	preset, err := s.GetPreset(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, preset)
+}
+
+// DiffAll runs all presets and records their results.
+func (s *screenerPresetService) DiffAll(ctx context.Context) ([]PresetRunResult, error) {
+	if s.runner == nil {
+		return nil, ErrScreenerUnavailable
+	}
+
+	presets, err := s.presetRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []PresetRunResult
+	var errs []error
+	for i := range presets {
+		preset := &presets[i]
+
+		result, err := s.run(ctx, preset)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("preset %s: %w", preset.ID, err))
+			continue
+		}
+
+		// The first run only establishes a baseline.
+		firstRun := preset.LastRunAt == nil
+
+		preset.LastResults = pq.StringArray(result.Symbols)
+		preset.LastRunAt = &result.RanAt
+		preset.UpdatedAt = time.Now()
+		if err := s.presetRepo.Update(preset); err != nil {
+			errs = append(errs, fmt.Errorf("preset %s: %w", preset.ID, err))
+			continue
+		}
+
+		if !firstRun && preset.NotifyOnNew && len(result.Added) > 0 && s.sink != nil {
+			if err := s.notify(ctx, preset, result.Added); err != nil {
+				errs = append(errs, fmt.Errorf("preset %s: %w", preset.ID, err))
+			}
+		}
+
+		results = append(results, *result)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// run executes the preset filter and diffs it against the last snapshot.
+func (s *screenerPresetService) run(ctx context.Context, preset *model.ScreenerPreset) (*PresetRunResult, error) {
+	if s.runner == nil {
+		return nil, ErrScreenerUnavailable
+	}
+
+	symbols, err := s.runner.RunScreen(ctx, json.RawMessage(preset.Filter))
+	if err != nil {
+		return nil, err
+	}
+	symbols = normalizeSymbolSet(symbols)
+
+	added, removed := diffSymbolSets(preset.LastResults, symbols)
+
+	return &PresetRunResult{
+		PresetID: preset.ID,
+		Symbols:  symbols,
+		Added:    added,
+		Removed:  removed,
+		RanAt:    time.Now(),
+	}, nil
+}
+
+// notify stores an in-app notification listing the newly matched symbols.
+func (s *screenerPresetService) notify(ctx context.Context, preset *model.ScreenerPreset, added []string) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"preset_id": preset.ID,
+		"symbols":   added,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification data: %w", err)
+	}
+
+	return s.sink.CreateNotification(ctx, &model.Notification{
+		ID:        uuid.New(),
+		UserID:    preset.UserID,
+		Type:      model.NotificationTypeAlert,
+		Title:     fmt.Sprintf("New matches for screen %q", preset.Name),
+		Message:   fmt.Sprintf("%s entered the results of %q", strings.Join(added, ", "), preset.Name),
+		Data:      string(data),
+		Status:    model.NotificationStatusUnread,
+		CreatedAt: time.Now(),
+	})
+}
+
+// normalizeSymbolSet upper-cases, de-duplicates and sorts symbols.
+func normalizeSymbolSet(symbols []string) []string {
+	seen := make(map[string]bool, len(symbols))
+	result := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		sym = strings.ToUpper(strings.TrimSpace(sym))
+		if sym == "" || seen[sym] {
+			continue
+		}
+		seen[sym] = true
+		result = append(result, sym)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// diffSymbolSets returns the symbols added to and removed from previous.
+func diffSymbolSets(previous, current []string) (added, removed []string) {
+	prev := make(map[string]bool, len(previous))
+	for _, sym := range previous {
+		prev[sym] = true
+	}
+	curr := make(map[string]bool, len(current))
+	for _, sym := range current {
+		curr[sym] = true
+		if !prev[sym] {
+			added = append(added, sym)
+		}
+	}
+	for _, sym := range previous {
+		if !curr[sym] {
+			removed = append(removed, sym)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// mockScreenRunner returns a fixed symbol list for every filter.
+type mockScreenRunner struct {
+	symbols []string
+}
+
+func (m *mockScreenRunner) RunScreen(ctx context.Context, filter json.RawMessage) ([]string, error) {
+	return m.symbols, nil
+}
+
+// mockNotificationSink records created notifications.
+type mockNotificationSink struct {
+	notifications []*model.Notification
+}
+
+func (m *mockNotificationSink) CreateNotification(ctx context.Context, notification *model.Notification) error {
+	m.notifications = append(m.notifications, notification)
+	return nil
+}
+
+func TestScreenerPresetService_CreatePreset(t *testing.T) {
+	svc := NewScreenerPresetService(repository.NewInMemoryScreenerPresetRepository(), nil, nil)
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		preset  string
+		filter  string
+		wantErr error
+	}{
+		{name: "valid preset", preset: "Cheap tech", filter: `{"sector":"Technology","max_pe":15}`},
+		{name: "empty name", preset: "  ", filter: `{}`, wantErr: ErrInvalidPresetName},
+		{name: "filter not an object", preset: "Bad", filter: `[1,2]`, wantErr: ErrInvalidPresetFilter},
+		{name: "filter not JSON", preset: "Bad", filter: `nope`, wantErr: ErrInvalidPresetFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preset, err := svc.CreatePreset(userID, tt.preset, json.RawMessage(tt.filter), true)
+			if err != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && preset.UserID != userID {
+				t.Errorf("Expected user ID %s, got %s", userID, preset.UserID)
+			}
+		})
+	}
+}
+
+func TestScreenerPresetService_OwnerScoping(t *testing.T) {
+	svc := NewScreenerPresetService(repository.NewInMemoryScreenerPresetRepository(), nil, nil)
+	owner := uuid.New()
+
+	preset, err := svc.CreatePreset(owner, "Mine", json.RawMessage(`{}`), true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := svc.DeletePreset(uuid.New(), preset.ID); err != ErrPresetNotFound {
+		t.Errorf("Expected ErrPresetNotFound for another user, got %v", err)
+	}
+	if err := svc.DeletePreset(owner, preset.ID); err != nil {
+		t.Errorf("Expected owner delete to succeed, got %v", err)
+	}
+}
+
+func TestScreenerPresetService_RunPreset_NoScreener(t *testing.T) {
+	svc := NewScreenerPresetService(repository.NewInMemoryScreenerPresetRepository(), nil, nil)
+	userID := uuid.New()
+
+	preset, _ := svc.CreatePreset(userID, "Any", json.RawMessage(`{}`), true)
+	if _, err := svc.RunPreset(context.Background(), userID, preset.ID); err != ErrScreenerUnavailable {
+		t.Errorf("Expected ErrScreenerUnavailable, got %v", err)
+	}
+}
+
+func TestScreenerPresetService_DiffAll(t *testing.T) {
+	runner := &mockScreenRunner{symbols: []string{"aapl", "MSFT"}}
+	sink := &mockNotificationSink{}
+	svc := NewScreenerPresetService(repository.NewInMemoryScreenerPresetRepository(), runner, sink)
+	userID := uuid.New()
+
+	preset, _ := svc.CreatePreset(userID, "Growth", json.RawMessage(`{"min_growth":10}`), true)
+
+	// First run establishes the baseline without notifying.
+	if _, err := svc.DiffAll(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sink.notifications) != 0 {
+		t.Fatalf("Expected no notifications on baseline run, got %d", len(sink.notifications))
+	}
+
+	// NVDA enters and MSFT drops out.
+	runner.symbols = []string{"AAPL", "NVDA"}
+	results, err := svc.DiffAll(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if len(results[0].Added) != 1 || results[0].Added[0] != "NVDA" {
+		t.Errorf("Expected added [NVDA], got %v", results[0].Added)
+	}
+	if len(results[0].Removed) != 1 || results[0].Removed[0] != "MSFT" {
+		t.Errorf("Expected removed [MSFT], got %v", results[0].Removed)
+	}
+	if len(sink.notifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(sink.notifications))
+	}
+	if sink.notifications[0].UserID != userID {
+		t.Errorf("Expected notification for %s, got %s", userID, sink.notifications[0].UserID)
+	}
+
+	// An on-demand run reports against the stored snapshot.
+	run, err := svc.RunPreset(context.Background(), userID, preset.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(run.Added) != 0 || len(run.Removed) != 0 {
+		t.Errorf("Expected no changes against snapshot, got added=%v removed=%v", run.Added, run.Removed)
+	}
+}
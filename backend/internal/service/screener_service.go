@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// Screener errors
+var (
+	ErrInvalidScreenerRange = errors.New("min_pe must not be greater than max_pe")
+	ErrInvalidScreenerSort  = errors.New("sort must be one of: pe, market_cap, dividend_yield")
+)
+
+// Screener sort orders. P/E sorts cheapest first; the others largest first.
+const (
+	ScreenerSortPE            = "pe"
+	ScreenerSortMarketCap     = "market_cap"
+	ScreenerSortDividendYield = "dividend_yield"
+)
+
+// OverviewCacheTTL is how long a company overview is used before it is fetched again.
+const OverviewCacheTTL = 24 * time.Hour
+
+// MaxOverviewFetchesPerScreen bounds how many stale overviews one screen
+// refreshes, since overview providers are heavily rate limited. The rest are
+// screened from the cache and refreshed by later screens.
+const MaxOverviewFetchesPerScreen = 25
+
+// StockOverview is the part of a company overview the screener filters on.
+// DividendYield and ReturnOnEquity are fractions; a zero PERatio means the
+// company has no earnings.
+type StockOverview struct {
+	Symbol         string  `json:"symbol"`
+	Name           string  `json:"name"`
+	Sector         string  `json:"sector"`
+	MarketCap      float64 `json:"market_cap"`
+	PERatio        float64 `json:"pe_ratio"`
+	DividendYield  float64 `json:"dividend_yield"`
+	ReturnOnEquity float64 `json:"return_on_equity"`
+}
+
+// ScreenerResult is a stock that matched a screen.
+type ScreenerResult = StockOverview
+
+// ScreenerCriteria filters stocks by fundamentals. Nil bounds and an empty
+// sector are not applied; a P/E bound excludes stocks without earnings.
+type ScreenerCriteria struct {
+	MinPE            *float64 `json:"min_pe,omitempty"`
+	MaxPE            *float64 `json:"max_pe,omitempty"`
+	MinDividendYield *float64 `json:"min_dividend_yield,omitempty"`
+	MinMarketCap     *float64 `json:"min_market_cap,omitempty"`
+	Sector           string   `json:"sector,omitempty"`
+	MinROE           *float64 `json:"min_roe,omitempty"`
+	// Sort orders the results; results are sorted by symbol when it is empty.
+	Sort string `json:"sort,omitempty"`
+}
+
+// Validate checks the P/E range and sort order.
+func (c ScreenerCriteria) Validate() error {
+	if c.MinPE != nil && c.MaxPE != nil && *c.MinPE > *c.MaxPE {
+		return ErrInvalidScreenerRange
+	}
+	switch c.Sort {
+	case "", ScreenerSortPE, ScreenerSortMarketCap, ScreenerSortDividendYield:
+		return nil
+	}
+	return ErrInvalidScreenerSort
+}
+
+// Matches reports whether the overview passes every filter.
+func (c ScreenerCriteria) Matches(o StockOverview) bool {
+	if (c.MinPE != nil || c.MaxPE != nil) && o.PERatio <= 0 {
+		return false
+	}
+	if c.MinPE != nil && o.PERatio < *c.MinPE {
+		return false
+	}
+	if c.MaxPE != nil && o.PERatio > *c.MaxPE {
+		return false
+	}
+	if c.MinDividendYield != nil && o.DividendYield < *c.MinDividendYield {
+		return false
+	}
+	if c.MinMarketCap != nil && o.MarketCap < *c.MinMarketCap {
+		return false
+	}
+	if c.Sector != "" && !strings.EqualFold(c.Sector, o.Sector) {
+		return false
+	}
+	if c.MinROE != nil && o.ReturnOnEquity < *c.MinROE {
+		return false
+	}
+	return true
+}
+
+// OverviewSource fetches a company overview.
+type OverviewSource interface {
+	GetOverview(ctx context.Context, symbol string) (*StockOverview, error)
+}
+
+// StockUniverse lists the stocks the screener covers.
+type StockUniverse interface {
+	GetTrackedStocks(ctx context.Context) ([]model.Stock, error)
+}
+
+// ScreenerService defines the interface for screening stocks by fundamentals.
+type ScreenerService interface {
+	Screen(criteria ScreenerCriteria) ([]ScreenerResult, error)
+}
+
+// cachedOverview is an overview and when it was fetched.
+type cachedOverview struct {
+	overview  StockOverview
+	fetchedAt time.Time
+}
+
+// screenerService implements ScreenerService over cached overviews.
+type screenerService struct {
+	universe StockUniverse
+	source   OverviewSource
+
+	mu    sync.Mutex
+	cache map[string]cachedOverview
+	now   func() time.Time
+}
+
+// NewScreenerService creates a new ScreenerService instance.
+// source may be nil; screening then fails with ErrScreenerUnavailable.
+func NewScreenerService(universe StockUniverse, source OverviewSource) ScreenerService {
+	return &screenerService{
+		universe: universe,
+		source:   source,
+		cache:    make(map[string]cachedOverview),
+		now:      time.Now,
+	}
+}
+
+// Screen refreshes stale overviews and returns the cached stocks matching
+// the criteria.
+func (s *screenerService) Screen(criteria ScreenerCriteria) ([]ScreenerResult, error) {
+	if err := criteria.Validate(); err != nil {
+		return nil, err
+	}
+	if s.source == nil {
+		return nil, ErrScreenerUnavailable
+	}
+
+	overviews, err := s.overviews(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ScreenerResult, 0, len(overviews))
+	for _, overview := range overviews {
+		if criteria.Matches(overview) {
+			results = append(results, overview)
+		}
+	}
+	sortScreenerResults(results, criteria.Sort)
+	return results, nil
+}
+
+// overviews returns the cached overview of every stock in the universe,
+// first fetching up to MaxOverviewFetchesPerScreen missing or expired ones,
+// oldest first. A stock whose overview cannot be fetched keeps its expired
+// overview, or is left out if it has none.
+func (s *screenerService) overviews(ctx context.Context) ([]StockOverview, error) {
+	stocks, err := s.universe.GetTrackedStocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var stale []string
+	for _, stock := range stocks {
+		cached, ok := s.cache[stock.Symbol]
+		if !ok || now.Sub(cached.fetchedAt) >= OverviewCacheTTL {
+			stale = append(stale, stock.Symbol)
+		}
+	}
+	sort.SliceStable(stale, func(i, j int) bool {
+		return s.cache[stale[i]].fetchedAt.Before(s.cache[stale[j]].fetchedAt)
+	})
+	if len(stale) > MaxOverviewFetchesPerScreen {
+		stale = stale[:MaxOverviewFetchesPerScreen]
+	}
+
+	for _, symbol := range stale {
+		overview, err := s.source.GetOverview(ctx, symbol)
+		if err != nil {
+			log.Warn().Err(err).Str("symbol", symbol).Msg("Failed to fetch company overview for screener")
+			continue
+		}
+		overview.Symbol = symbol
+		s.cache[symbol] = cachedOverview{overview: *overview, fetchedAt: now}
+	}
+
+	overviews := make([]StockOverview, 0, len(stocks))
+	for _, stock := range stocks {
+		if cached, ok := s.cache[stock.Symbol]; ok {
+			overviews = append(overviews, cached.overview)
+		}
+	}
+	return overviews, nil
+}
+
+// screenRunner runs saved preset filters through a ScreenerService.
+type screenRunner struct {
+	screener ScreenerService
+}
+
+// NewScreenRunner creates a ScreenRunner that reads preset filters as
+// ScreenerCriteria and screens them with screener.
+func NewScreenRunner(screener ScreenerService) ScreenRunner {
+	return &screenRunner{screener: screener}
+}
+
+// RunScreen screens stocks with the filter's criteria and returns the
+// matching symbols in the screener's order.
+func (r *screenRunner) RunScreen(ctx context.Context, filter json.RawMessage) ([]string, error) {
+	var criteria ScreenerCriteria
+	if err := json.Unmarshal(filter, &criteria); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPresetFilter, err)
+	}
+	results, err := r.screener.Screen(criteria)
+	if err != nil {
+		return nil, err
+	}
+	symbols := make([]string, len(results))
+	for i, result := range results {
+		symbols[i] = result.Symbol
+	}
+	return symbols, nil
+}
+
+// sortScreenerResults orders results by the sort key, then by symbol.
+func sortScreenerResults(results []ScreenerResult, key string) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		switch key {
+		case ScreenerSortPE:
+			if a.PERatio != b.PERatio {
+				return a.PERatio < b.PERatio
+			}
+		case ScreenerSortMarketCap:
+			if a.MarketCap != b.MarketCap {
+				return a.MarketCap > b.MarketCap
+			}
+		case ScreenerSortDividendYield:
+			if a.DividendYield != b.DividendYield {
+				return a.DividendYield > b.DividendYield
+			}
+		}
+		return a.Symbol < b.Symbol
+	})
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+// stubUniverse tracks a fixed list of symbols.
+type stubUniverse []string
+
+func (u stubUniverse) GetTrackedStocks(ctx context.Context) ([]model.Stock, error) {
+	stocks := make([]model.Stock, len(u))
+	for i, symbol := range u {
+		stocks[i] = model.Stock{Symbol: symbol}
+	}
+	return stocks, nil
+}
+
+// stubOverviews serves overviews from memory and counts fetches per symbol.
+type stubOverviews struct {
+	overviews map[string]StockOverview
+	fetches   map[string]int
+}
+
+func (s *stubOverviews) GetOverview(ctx context.Context, symbol string) (*StockOverview, error) {
+	if s.fetches == nil {
+		s.fetches = make(map[string]int)
+	}
+	s.fetches[symbol]++
+	overview, ok := s.overviews[symbol]
+	if !ok {
+		return nil, errors.New("no overview")
+	}
+	return &overview, nil
+}
+
+func float(v float64) *float64 { return &v }
+
+func screenerOverviews() *stubOverviews {
+	return &stubOverviews{overviews: map[string]StockOverview{
+		"AAPL": {Name: "Apple", Sector: "TECHNOLOGY", MarketCap: 3e12, PERatio: 30, DividendYield: 0.005, ReturnOnEquity: 1.5},
+		"JNJ":  {Name: "Johnson & Johnson", Sector: "HEALTHCARE", MarketCap: 4e11, PERatio: 15, DividendYield: 0.03, ReturnOnEquity: 0.2},
+		"KO":   {Name: "Coca-Cola", Sector: "CONSUMER DEFENSIVE", MarketCap: 2.6e11, PERatio: 24, DividendYield: 0.031, ReturnOnEquity: 0.4},
+		"MSFT": {Name: "Microsoft", Sector: "TECHNOLOGY", MarketCap: 3.1e12, PERatio: 35, DividendYield: 0.007, ReturnOnEquity: 0.35},
+		"RIVN": {Name: "Rivian", Sector: "CONSUMER CYCLICAL", MarketCap: 1.2e10, PERatio: 0, ReturnOnEquity: -0.5},
+	}}
+}
+
+func screenedSymbols(results []ScreenerResult) []string {
+	symbols := make([]string, len(results))
+	for i, r := range results {
+		symbols[i] = r.Symbol
+	}
+	return symbols
+}
+
+func TestScreenerService_Screen(t *testing.T) {
+	universe := stubUniverse{"AAPL", "JNJ", "KO", "MSFT", "RIVN"}
+
+	tests := []struct {
+		name     string
+		criteria ScreenerCriteria
+		want     []string
+	}{
+		{"no filters", ScreenerCriteria{}, []string{"AAPL", "JNJ", "KO", "MSFT", "RIVN"}},
+		{"pe range excludes no earnings", ScreenerCriteria{MinPE: float(10), MaxPE: float(25)}, []string{"JNJ", "KO"}},
+		{"max pe", ScreenerCriteria{MaxPE: float(30)}, []string{"AAPL", "JNJ", "KO"}},
+		{"dividend yield", ScreenerCriteria{MinDividendYield: float(0.03)}, []string{"JNJ", "KO"}},
+		{"market cap", ScreenerCriteria{MinMarketCap: float(1e12)}, []string{"AAPL", "MSFT"}},
+		{"sector ignores case", ScreenerCriteria{Sector: "Technology"}, []string{"AAPL", "MSFT"}},
+		{"roe", ScreenerCriteria{MinROE: float(0.3)}, []string{"AAPL", "KO", "MSFT"}},
+		{"combined", ScreenerCriteria{Sector: "technology", MinROE: float(0.5)}, []string{"AAPL"}},
+		{"sort by pe", ScreenerCriteria{MinPE: float(1), Sort: ScreenerSortPE}, []string{"JNJ", "KO", "AAPL", "MSFT"}},
+		{"sort by market cap", ScreenerCriteria{Sort: ScreenerSortMarketCap}, []string{"MSFT", "AAPL", "JNJ", "KO", "RIVN"}},
+		{"sort by dividend yield", ScreenerCriteria{Sort: ScreenerSortDividendYield}, []string{"KO", "JNJ", "MSFT", "AAPL", "RIVN"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewScreenerService(universe, screenerOverviews())
+			results, err := svc.Screen(tt.criteria)
+			if err != nil {
+				t.Fatalf("Screen() error = %v", err)
+			}
+			if got := screenedSymbols(results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Screen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreenerService_Screen_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria ScreenerCriteria
+		want     error
+	}{
+		{"min pe above max pe", ScreenerCriteria{MinPE: float(20), MaxPE: float(10)}, ErrInvalidScreenerRange},
+		{"unknown sort", ScreenerCriteria{Sort: "price"}, ErrInvalidScreenerSort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewScreenerService(stubUniverse{"AAPL"}, screenerOverviews())
+			if _, err := svc.Screen(tt.criteria); err != tt.want {
+				t.Errorf("Screen() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	svc := NewScreenerService(stubUniverse{"AAPL"}, nil)
+	if _, err := svc.Screen(ScreenerCriteria{}); err != ErrScreenerUnavailable {
+		t.Errorf("Screen() without a source error = %v, want %v", err, ErrScreenerUnavailable)
+	}
+}
+
+func TestScreenerService_Screen_Cache(t *testing.T) {
+	source := screenerOverviews()
+	svc := NewScreenerService(stubUniverse{"AAPL", "UNKNOWN"}, source).(*screenerService)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		results, err := svc.Screen(ScreenerCriteria{})
+		if err != nil {
+			t.Fatalf("Screen() error = %v", err)
+		}
+		if got := screenedSymbols(results); !reflect.DeepEqual(got, []string{"AAPL"}) {
+			t.Errorf("Screen() = %v, want [AAPL]", got)
+		}
+	}
+	if source.fetches["AAPL"] != 1 {
+		t.Errorf("AAPL fetched %d times within the TTL, want 1", source.fetches["AAPL"])
+	}
+	if source.fetches["UNKNOWN"] != 2 {
+		t.Errorf("UNKNOWN fetched %d times, want a retry on every screen", source.fetches["UNKNOWN"])
+	}
+
+	now = now.Add(OverviewCacheTTL)
+	delete(source.overviews, "AAPL")
+	results, err := svc.Screen(ScreenerCriteria{})
+	if err != nil {
+		t.Fatalf("Screen() error = %v", err)
+	}
+	if source.fetches["AAPL"] != 2 {
+		t.Errorf("AAPL fetched %d times after the TTL, want 2", source.fetches["AAPL"])
+	}
+	if got := screenedSymbols(results); !reflect.DeepEqual(got, []string{"AAPL"}) {
+		t.Errorf("Screen() after a failed refresh = %v, want the expired AAPL overview", got)
+	}
+}
+
+func TestScreenerService_Screen_FetchLimit(t *testing.T) {
+	universe := make(stubUniverse, MaxOverviewFetchesPerScreen+5)
+	source := &stubOverviews{overviews: make(map[string]StockOverview)}
+	for i := range universe {
+		universe[i] = string(rune('A'+i/26)) + string(rune('A'+i%26))
+		source.overviews[universe[i]] = StockOverview{}
+	}
+	svc := NewScreenerService(universe, source)
+
+	results, err := svc.Screen(ScreenerCriteria{})
+	if err != nil {
+		t.Fatalf("Screen() error = %v", err)
+	}
+	if len(results) != MaxOverviewFetchesPerScreen {
+		t.Errorf("first Screen() returned %d stocks, want %d", len(results), MaxOverviewFetchesPerScreen)
+	}
+
+	results, err = svc.Screen(ScreenerCriteria{})
+	if err != nil {
+		t.Fatalf("Screen() error = %v", err)
+	}
+	if len(results) != len(universe) {
+		t.Errorf("second Screen() returned %d stocks, want %d", len(results), len(universe))
+	}
+}
+
+func TestScreenRunner_RunScreen(t *testing.T) {
+	runner := NewScreenRunner(NewScreenerService(stubUniverse{"AAPL", "JNJ", "KO", "MSFT", "RIVN"}, screenerOverviews()))
+
+	symbols, err := runner.RunScreen(context.Background(), json.RawMessage(`{"sector":"technology","sort":"market_cap"}`))
+	if err != nil {
+		t.Fatalf("RunScreen() error = %v", err)
+	}
+	if want := []string{"MSFT", "AAPL"}; !reflect.DeepEqual(symbols, want) {
+		t.Errorf("RunScreen() = %v, want %v", symbols, want)
+	}
+
+	if _, err := runner.RunScreen(context.Background(), json.RawMessage(`{"min_pe":"cheap"}`)); !errors.Is(err, ErrInvalidPresetFilter) {
+		t.Errorf("RunScreen() with a malformed filter error = %v, want ErrInvalidPresetFilter", err)
+	}
+	if _, err := runner.RunScreen(context.Background(), json.RawMessage(`{"sort":"name"}`)); !errors.Is(err, ErrInvalidScreenerSort) {
+		t.Errorf("RunScreen() with an invalid sort error = %v, want ErrInvalidScreenerSort", err)
+	}
+}
@@ -17,6 +17,15 @@ import (
 const (
 	AccessTokenDuration  = 15 * time.Minute
 	RefreshTokenDuration = 7 * 24 * time.Hour
+	// EmailVerificationTokenDuration is how long an email verification
+	// token can be confirmed.
+	EmailVerificationTokenDuration = 24 * time.Hour
+	// PasswordResetTokenDuration is how long a password reset token can be
+	// used.
+	PasswordResetTokenDuration = time.Hour
+	// OAuthStateDuration is how long a user has to complete an OAuth login
+	// at the provider.
+	OAuthStateDuration = 10 * time.Minute
 )
 
 var (
@@ -30,11 +39,32 @@ var (
 	ErrRefreshTokenNotFound = errors.New("refresh token not found")
 )
 
+// dummyPasswordHash is compared against when a login names an unknown user so
+// the response takes as long as a real password check. It is a DefaultCost
+// hash of a random string that no account uses.
+var dummyPasswordHash = []byte("$2a$10$j8Ra90bGR1xbJ2r/SP4BlezLUZcPa0l65eNR14kgupIa17wvE/lt2")
+
 // TokenStore defines the interface for token storage operations.
 type TokenStore interface {
 	SetRefreshToken(ctx context.Context, userID, tokenID string, expiration time.Duration) error
 	GetRefreshToken(ctx context.Context, tokenID string) (string, error)
 	DeleteRefreshToken(ctx context.Context, tokenID string) error
+	SetEmailVerificationToken(ctx context.Context, userID, token string, expiration time.Duration) error
+	GetEmailVerificationToken(ctx context.Context, token string) (string, error)
+	DeleteEmailVerificationToken(ctx context.Context, token string) error
+	SetPasswordResetToken(ctx context.Context, userID, token string, expiration time.Duration) error
+	// ConsumePasswordResetToken returns the user ID a password reset token
+	// was issued to and deletes the token in the same step, so that a token
+	// can be redeemed only once.
+	ConsumePasswordResetToken(ctx context.Context, token string) (string, error)
+	SetOAuthState(ctx context.Context, state, provider string, expiration time.Duration) error
+	GetOAuthState(ctx context.Context, state string) (string, error)
+	DeleteOAuthState(ctx context.Context, state string) error
+	// IncrementFailedLogins counts a failed login for key and returns the
+	// new count. The count expires window after the latest failure.
+	IncrementFailedLogins(ctx context.Context, key string, window time.Duration) (int64, error)
+	GetFailedLogins(ctx context.Context, key string) (int64, error)
+	ResetFailedLogins(ctx context.Context, key string) error
 }
 
 // AuthService defines the interface for authentication operations.
@@ -97,6 +127,7 @@ func (s *authService) Login(email, password string) (string, string, error) {
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(email)
 	if err != nil {
+		compareDummyPassword(password)
 		return "", "", ErrInvalidCredentials
 	}
 
@@ -219,3 +250,9 @@ func (s *authService) generateToken(userID uuid.UUID, email, role string, expiry
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(s.jwtSecret))
 }
+
+// compareDummyPassword burns the time of a bcrypt comparison so that unknown
+// emails cannot be told apart from wrong passwords by response time.
+func compareDummyPassword(password string) {
+	_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"testing"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -129,6 +131,62 @@ func TestAuthService_Login(t *testing.T) {
 	}
 }
 
+func TestAuthService_LoginTimingUniform(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping timing test in short mode")
+	}
+
+	if cost, err := bcrypt.Cost(dummyPasswordHash); err != nil || cost != bcrypt.DefaultCost {
+		t.Fatalf("Expected dummy hash with cost %d, got %d (%v)", bcrypt.DefaultCost, cost, err)
+	}
+
+	mockRepo := newMockUserRepository()
+	authService := NewAuthService(mockRepo, "test-secret", nil)
+	if _, err := authService.Register("test@example.com", "password123", "Test User"); err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	// The fastest of a few attempts filters out scheduler noise.
+	fastest := func(email string) time.Duration {
+		var best time.Duration
+		for i := 0; i < 5; i++ {
+			start := time.Now()
+			_, _, _ = authService.Login(email, "wrongpassword")
+			if elapsed := time.Since(start); i == 0 || elapsed < best {
+				best = elapsed
+			}
+		}
+		return best
+	}
+
+	existing := fastest("test@example.com")
+	unknown := fastest("nonexistent@example.com")
+
+	if unknown < existing/2 || unknown > existing*2 {
+		t.Errorf("Expected comparable login times, got %v for an existing user and %v for an unknown user", existing, unknown)
+	}
+}
+
+func BenchmarkAuthService_Login(b *testing.B) {
+	mockRepo := newMockUserRepository()
+	authService := NewAuthService(mockRepo, "test-secret", nil)
+	if _, err := authService.Register("test@example.com", "password123", "Test User"); err != nil {
+		b.Fatalf("Failed to register user: %v", err)
+	}
+
+	b.Run("existing user", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = authService.Login("test@example.com", "wrongpassword")
+		}
+	})
+
+	b.Run("unknown user", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = authService.Login("nonexistent@example.com", "wrongpassword")
+		}
+	})
+}
+
 func TestAuthService_ValidateToken(t *testing.T) {
 	mockRepo := newMockUserRepository()
 	authService := NewAuthService(mockRepo, "test-secret", nil)
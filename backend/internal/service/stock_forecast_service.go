@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/lib/calculations"
+)
+
+// Price forecast limits and defaults.
+const (
+	// DefaultForecastHorizonDays and MaxForecastHorizonDays bound how many
+	// trading days ahead a forecast simulates.
+	DefaultForecastHorizonDays = 30
+	MaxForecastHorizonDays     = 252
+	// DefaultForecastSimulations and MaxForecastSimulations bound the number
+	// of simulated paths, which is what a forecast's cost grows with.
+	DefaultForecastSimulations = 10000
+	MaxForecastSimulations     = 100000
+	// minForecastReturns is the fewest daily returns drift and volatility
+	// are estimated from.
+	minForecastReturns = 20
+)
+
+// Price forecast errors.
+var (
+	ErrInvalidForecastHorizon     = fmt.Errorf("horizon_days must be between 1 and %d", MaxForecastHorizonDays)
+	ErrInvalidForecastSimulations = fmt.Errorf("num_simulations must be between 1 and %d", MaxForecastSimulations)
+)
+
+// ForecastRequest configures a price forecast. Zero values mean the
+// defaults; a zero seed draws a random one.
+type ForecastRequest struct {
+	HorizonDays    int   `json:"horizon_days"`
+	NumSimulations int   `json:"num_simulations"`
+	Seed           int64 `json:"seed"`
+}
+
+// StockForecast is the simulated distribution of a stock's price at the
+// end of the horizon.
+type StockForecast struct {
+	Symbol         string    `json:"symbol"`
+	AsOf           time.Time `json:"as_of"`
+	CurrentPrice   float64   `json:"current_price"`
+	HorizonDays    int       `json:"horizon_days"`
+	NumSimulations int       `json:"num_simulations"`
+	// Observations is the number of daily returns DailyDrift and
+	// DailyVolatility were estimated from.
+	Observations    int     `json:"observations"`
+	DailyDrift      float64 `json:"daily_drift"`
+	DailyVolatility float64 `json:"daily_volatility"`
+	// Terminal price statistics across the simulated paths.
+	Mean         float64 `json:"mean"`
+	Median       float64 `json:"median"`
+	StdDev       float64 `json:"std_dev"`
+	Percentile5  float64 `json:"percentile_5"`
+	Percentile95 float64 `json:"percentile_95"`
+	// ProbabilityAbove is the share of paths, from 0 to 1, ending above
+	// CurrentPrice.
+	ProbabilityAbove float64 `json:"probability_above"`
+}
+
+// StockForecastService defines the interface for Monte Carlo price forecasts.
+type StockForecastService interface {
+	// Forecast simulates the symbol's price over the requested horizon with
+	// drift and volatility estimated from its recent daily closes.
+	Forecast(ctx context.Context, symbol string, req ForecastRequest) (*StockForecast, error)
+}
+
+// stockForecastService implements StockForecastService.
+type stockForecastService struct {
+	source DailyBarSource
+}
+
+// NewStockForecastService creates a new StockForecastService instance.
+func NewStockForecastService(source DailyBarSource) StockForecastService {
+	return &stockForecastService{source: source}
+}
+
+// Forecast validates the request, estimates the daily drift and volatility
+// of the recent closes and runs a geometric Brownian motion simulation from
+// the latest close.
+func (s *stockForecastService) Forecast(ctx context.Context, symbol string, req ForecastRequest) (*StockForecast, error) {
+	if req.HorizonDays == 0 {
+		req.HorizonDays = DefaultForecastHorizonDays
+	}
+	if req.HorizonDays < 1 || req.HorizonDays > MaxForecastHorizonDays {
+		return nil, ErrInvalidForecastHorizon
+	}
+	if req.NumSimulations == 0 {
+		req.NumSimulations = DefaultForecastSimulations
+	}
+	if req.NumSimulations < 1 || req.NumSimulations > MaxForecastSimulations {
+		return nil, ErrInvalidForecastSimulations
+	}
+	if req.Seed == 0 {
+		req.Seed = rand.Int63()
+	}
+
+	symbol = repository.NormalizeSymbol(symbol)
+	if symbol == "" {
+		return nil, ErrStockNotFound
+	}
+	bars, err := s.source.GetDailyBars(ctx, symbol, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) == 0 {
+		return nil, ErrStockNotFound
+	}
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Timestamp.Before(bars[j].Timestamp) })
+
+	logReturns := make([]float64, 0, len(bars))
+	for i := 1; i < len(bars); i++ {
+		if bars[i-1].Close <= 0 || bars[i].Close <= 0 {
+			continue
+		}
+		logReturns = append(logReturns, math.Log(bars[i].Close/bars[i-1].Close))
+	}
+	if len(logReturns) < minForecastReturns {
+		return nil, ErrInsufficientPriceHistory
+	}
+
+	// The simulation takes the drift of simple returns, which exceeds the
+	// mean log return by half the variance.
+	var mean, variance float64
+	for _, r := range logReturns {
+		mean += r
+	}
+	mean /= float64(len(logReturns))
+	for _, r := range logReturns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(logReturns) - 1)
+
+	latest := bars[len(bars)-1]
+	forecast := &StockForecast{
+		Symbol:          symbol,
+		AsOf:            latest.Timestamp,
+		CurrentPrice:    latest.Close,
+		HorizonDays:     req.HorizonDays,
+		NumSimulations:  req.NumSimulations,
+		Observations:    len(logReturns),
+		DailyDrift:      mean + variance/2,
+		DailyVolatility: math.Sqrt(variance),
+	}
+	result := calculations.MonteCarloSimulation(calculations.MonteCarloParams{
+		InitialPrice:   forecast.CurrentPrice,
+		DriftRate:      forecast.DailyDrift,
+		Volatility:     forecast.DailyVolatility,
+		DaysToSimulate: req.HorizonDays,
+		NumSimulations: req.NumSimulations,
+		Rand:           rand.New(rand.NewSource(req.Seed)),
+	})
+	forecast.Mean = result.Mean
+	forecast.Median = result.Median
+	forecast.StdDev = result.StdDev
+	forecast.Percentile5 = result.Percentile5
+	forecast.Percentile95 = result.Percentile95
+	forecast.ProbabilityAbove = result.ProbAbove
+	return forecast, nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+// zigzagBars returns n daily bars, newest first, whose close alternately
+// rises and falls by a log return of ln(1.01) from 100.
+func zigzagBars(n int) []model.StockPrice {
+	bars := make([]model.StockPrice, n)
+	for i := range bars {
+		day := n - 1 - i
+		closePrice := 100.0
+		if day%2 == 1 {
+			closePrice = 101
+		}
+		bars[i] = model.StockPrice{Timestamp: historyStart.AddDate(0, 0, day), Close: closePrice}
+	}
+	return bars
+}
+
+func TestStockForecastService_Forecast(t *testing.T) {
+	source := &mockDailyBarSource{bars: zigzagBars(41)}
+	svc := NewStockForecastService(source)
+
+	forecast, err := svc.Forecast(context.Background(), "aapl", ForecastRequest{HorizonDays: 10, NumSimulations: 2000, Seed: 7})
+	if err != nil {
+		t.Fatalf("Forecast() error = %v", err)
+	}
+	if forecast.Symbol != "AAPL" || forecast.CurrentPrice != 100 || !forecast.AsOf.Equal(historyStart.AddDate(0, 0, 40)) {
+		t.Errorf("Forecast() = %s at %v as of %v, want AAPL at 100 as of the newest bar", forecast.Symbol, forecast.CurrentPrice, forecast.AsOf)
+	}
+	if forecast.HorizonDays != 10 || forecast.NumSimulations != 2000 || forecast.Observations != 40 {
+		t.Errorf("Forecast() = %d days, %d simulations, %d returns, want 10, 2000 and 40", forecast.HorizonDays, forecast.NumSimulations, forecast.Observations)
+	}
+	if len(source.fulls) != 1 || source.fulls[0] {
+		t.Errorf("full history requests = %v, want one compact request", source.fulls)
+	}
+
+	// 20 log returns of +ln(1.01) and 20 of -ln(1.01): a zero mean, a sample
+	// variance of 40/39·ln(1.01)², and a simple-return drift of half that.
+	variance := 40.0 / 39 * math.Log(1.01) * math.Log(1.01)
+	if math.Abs(forecast.DailyVolatility-math.Sqrt(variance)) > 1e-12 || math.Abs(forecast.DailyDrift-variance/2) > 1e-12 {
+		t.Errorf("drift %v and volatility %v, want %v and %v", forecast.DailyDrift, forecast.DailyVolatility, variance/2, math.Sqrt(variance))
+	}
+
+	if forecast.ProbabilityAbove < 0 || forecast.ProbabilityAbove > 1 {
+		t.Errorf("ProbabilityAbove = %v, want a probability", forecast.ProbabilityAbove)
+	}
+	if !(forecast.Percentile5 < forecast.Median && forecast.Median < forecast.Percentile95) {
+		t.Errorf("percentiles out of order: 5th %v, median %v, 95th %v", forecast.Percentile5, forecast.Median, forecast.Percentile95)
+	}
+	// With no log drift the mean stays near the current price.
+	if math.Abs(forecast.Mean-100) > 1 {
+		t.Errorf("Mean = %v, want about 100", forecast.Mean)
+	}
+
+	again, err := svc.Forecast(context.Background(), "AAPL", ForecastRequest{HorizonDays: 10, NumSimulations: 2000, Seed: 7})
+	if err != nil {
+		t.Fatalf("Forecast() error = %v", err)
+	}
+	if !reflect.DeepEqual(forecast, again) {
+		t.Errorf("same seed gave %+v and %+v", forecast, again)
+	}
+}
+
+func TestStockForecastService_ForecastDefaults(t *testing.T) {
+	svc := NewStockForecastService(&mockDailyBarSource{bars: zigzagBars(41)})
+
+	forecast, err := svc.Forecast(context.Background(), "AAPL", ForecastRequest{})
+	if err != nil {
+		t.Fatalf("Forecast() error = %v", err)
+	}
+	if forecast.HorizonDays != DefaultForecastHorizonDays || forecast.NumSimulations != DefaultForecastSimulations {
+		t.Errorf("Forecast() = %d days and %d simulations, want the defaults", forecast.HorizonDays, forecast.NumSimulations)
+	}
+}
+
+func TestStockForecastService_ForecastErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		bars []model.StockPrice
+		req  ForecastRequest
+		want error
+	}{
+		{"negative horizon", zigzagBars(41), ForecastRequest{HorizonDays: -1}, ErrInvalidForecastHorizon},
+		{"horizon too long", zigzagBars(41), ForecastRequest{HorizonDays: MaxForecastHorizonDays + 1}, ErrInvalidForecastHorizon},
+		{"negative simulations", zigzagBars(41), ForecastRequest{NumSimulations: -1}, ErrInvalidForecastSimulations},
+		{"too many simulations", zigzagBars(41), ForecastRequest{NumSimulations: MaxForecastSimulations + 1}, ErrInvalidForecastSimulations},
+		{"no bars", nil, ForecastRequest{}, ErrStockNotFound},
+		{"too few bars", zigzagBars(minForecastReturns), ForecastRequest{}, ErrInsufficientPriceHistory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewStockForecastService(&mockDailyBarSource{bars: tt.bars})
+			if _, err := svc.Forecast(context.Background(), "AAPL", tt.req); err != tt.want {
+				t.Errorf("Forecast() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
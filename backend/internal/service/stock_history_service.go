@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+)
+
+// Stock history errors.
+var (
+	ErrHistorySyncUnavailable = errors.New("price history sync is not available")
+	ErrInvalidHistoryRange    = errors.New("from must be before to")
+)
+
+// DailyBarSource fetches a symbol's daily price bars from a market data
+// provider. Without full it returns only the most recent bars.
+type DailyBarSource interface {
+	GetDailyBars(ctx context.Context, symbol string, full bool) ([]model.StockPrice, error)
+}
+
+// HistorySyncResult reports a history sync.
+type HistorySyncResult struct {
+	Symbol   string `json:"symbol"`
+	Fetched  int    `json:"fetched"`
+	Inserted int64  `json:"inserted"`
+}
+
+// StockHistoryService defines the interface for stored daily price history.
+type StockHistoryService interface {
+	// GetHistory returns a symbol's stored bars from from inclusive to to
+	// exclusive, oldest first.
+	GetHistory(ctx context.Context, symbol string, from, to time.Time) ([]model.StockPrice, error)
+	// SyncDailyHistory stores the symbol's daily bars that are not stored yet.
+	SyncDailyHistory(ctx context.Context, symbol string) (*HistorySyncResult, error)
+}
+
+// stockHistoryService implements StockHistoryService.
+type stockHistoryService struct {
+	repo   repository.StockPriceRepository
+	source DailyBarSource
+}
+
+// NewStockHistoryService creates a new StockHistoryService instance. Without
+// a source, history can be read but not synced.
+func NewStockHistoryService(repo repository.StockPriceRepository, source DailyBarSource) StockHistoryService {
+	return &stockHistoryService{repo: repo, source: source}
+}
+
+// GetHistory looks up the stock and returns its bars in range.
+func (s *stockHistoryService) GetHistory(ctx context.Context, symbol string, from, to time.Time) ([]model.StockPrice, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidHistoryRange
+	}
+
+	stock, err := s.repo.GetStockBySymbol(symbol)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrStockNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := s.repo.GetRange(stock.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if prices == nil {
+		prices = []model.StockPrice{}
+	}
+	return prices, nil
+}
+
+// SyncDailyHistory fetches the full history for a stock with no stored bars
+// and only the recent bars otherwise. Bars already stored are skipped.
+func (s *stockHistoryService) SyncDailyHistory(ctx context.Context, symbol string) (*HistorySyncResult, error) {
+	if s.source == nil {
+		return nil, ErrHistorySyncUnavailable
+	}
+
+	symbol = repository.NormalizeSymbol(symbol)
+	if symbol == "" {
+		return nil, ErrStockNotFound
+	}
+	stock, err := s.repo.EnsureStock(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.repo.Latest(stock.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	bars, err := s.source.GetDailyBars(ctx, symbol, latest == nil)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make([]model.StockPrice, 0, len(bars))
+	for _, bar := range bars {
+		if latest != nil && !bar.Timestamp.After(latest.Timestamp) {
+			continue
+		}
+		bar.StockID = stock.ID
+		missing = append(missing, bar)
+	}
+
+	inserted, err := s.repo.BulkInsert(missing)
+	if err != nil {
+		return nil, err
+	}
+	return &HistorySyncResult{Symbol: symbol, Fetched: len(bars), Inserted: inserted}, nil
+}
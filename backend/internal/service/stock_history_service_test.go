@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// mockStockPriceRepository stores bars in memory, unique per stock and
+// timestamp like the database index.
+type mockStockPriceRepository struct {
+	stocks map[string]model.Stock
+	prices map[uuid.UUID]map[int64]model.StockPrice
+}
+
+func newMockStockPriceRepository() *mockStockPriceRepository {
+	return &mockStockPriceRepository{stocks: make(map[string]model.Stock), prices: make(map[uuid.UUID]map[int64]model.StockPrice)}
+}
+
+func (m *mockStockPriceRepository) GetStockBySymbol(symbol string) (*model.Stock, error) {
+	stock, ok := m.stocks[repository.NormalizeSymbol(symbol)]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &stock, nil
+}
+
+func (m *mockStockPriceRepository) EnsureStock(symbol string) (*model.Stock, error) {
+	if stock, err := m.GetStockBySymbol(symbol); err == nil {
+		return stock, nil
+	}
+	stock := model.Stock{ID: uuid.New(), Symbol: symbol}
+	m.stocks[symbol] = stock
+	return &stock, nil
+}
+
+func (m *mockStockPriceRepository) BulkInsert(prices []model.StockPrice) (int64, error) {
+	var inserted int64
+	for _, price := range prices {
+		bars := m.prices[price.StockID]
+		if bars == nil {
+			bars = make(map[int64]model.StockPrice)
+			m.prices[price.StockID] = bars
+		}
+		if _, ok := bars[price.Timestamp.Unix()]; ok {
+			continue
+		}
+		bars[price.Timestamp.Unix()] = price
+		inserted++
+	}
+	return inserted, nil
+}
+
+func (m *mockStockPriceRepository) GetRange(stockID uuid.UUID, from, to time.Time) ([]model.StockPrice, error) {
+	var prices []model.StockPrice
+	for _, price := range m.prices[stockID] {
+		if !price.Timestamp.Before(from) && price.Timestamp.Before(to) {
+			prices = append(prices, price)
+		}
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Timestamp.Before(prices[j].Timestamp) })
+	return prices, nil
+}
+
+func (m *mockStockPriceRepository) Latest(stockID uuid.UUID) (*model.StockPrice, error) {
+	var latest *model.StockPrice
+	for _, price := range m.prices[stockID] {
+		if latest == nil || price.Timestamp.After(latest.Timestamp) {
+			price := price
+			latest = &price
+		}
+	}
+	if latest == nil {
+		return nil, repository.ErrNotFound
+	}
+	return latest, nil
+}
+
+func (m *mockStockPriceRepository) Upsert(price *model.StockPrice) error {
+	if m.prices[price.StockID] == nil {
+		m.prices[price.StockID] = make(map[int64]model.StockPrice)
+	}
+	m.prices[price.StockID][price.Timestamp.Unix()] = *price
+	return nil
+}
+
+// mockDailyBarSource returns the same bars on every call and records whether
+// each call asked for the full history.
+type mockDailyBarSource struct {
+	bars  []model.StockPrice
+	fulls []bool
+}
+
+func (m *mockDailyBarSource) GetDailyBars(ctx context.Context, symbol string, full bool) ([]model.StockPrice, error) {
+	m.fulls = append(m.fulls, full)
+	return m.bars, nil
+}
+
+var historyStart = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+func historyBars(days int) []model.StockPrice {
+	bars := make([]model.StockPrice, days)
+	for i := range bars {
+		bars[i] = model.StockPrice{Timestamp: historyStart.AddDate(0, 0, i), Close: 100 + float64(i)}
+	}
+	return bars
+}
+
+func TestStockHistoryService_SyncDailyHistory_Idempotent(t *testing.T) {
+	repo := newMockStockPriceRepository()
+	source := &mockDailyBarSource{bars: historyBars(5)}
+	svc := NewStockHistoryService(repo, source)
+
+	first, err := svc.SyncDailyHistory(context.Background(), "aapl")
+	if err != nil {
+		t.Fatalf("SyncDailyHistory() error = %v", err)
+	}
+	if first.Symbol != "AAPL" || first.Fetched != 5 || first.Inserted != 5 {
+		t.Errorf("first sync = %+v, want AAPL with 5 fetched and 5 inserted", first)
+	}
+
+	second, err := svc.SyncDailyHistory(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatalf("SyncDailyHistory() error = %v", err)
+	}
+	if second.Inserted != 0 {
+		t.Errorf("second sync inserted %d bars, want 0", second.Inserted)
+	}
+	if len(source.fulls) != 2 || !source.fulls[0] || source.fulls[1] {
+		t.Errorf("full history requests = %v, want [true false]", source.fulls)
+	}
+
+	source.bars = historyBars(6)
+	third, err := svc.SyncDailyHistory(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatalf("SyncDailyHistory() error = %v", err)
+	}
+	if third.Inserted != 1 {
+		t.Errorf("sync with one new bar inserted %d, want 1", third.Inserted)
+	}
+
+	prices, err := svc.GetHistory(context.Background(), "AAPL", historyStart, historyStart.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(prices) != 6 {
+		t.Fatalf("GetHistory() returned %d bars, want 6", len(prices))
+	}
+	for i, price := range prices {
+		if !price.Timestamp.Equal(historyStart.AddDate(0, 0, i)) {
+			t.Errorf("bar %d at %v, want day %d oldest first", i, price.Timestamp, i)
+		}
+	}
+}
+
+func TestStockHistoryService_GetHistory(t *testing.T) {
+	repo := newMockStockPriceRepository()
+	svc := NewStockHistoryService(repo, nil)
+
+	if _, err := svc.GetHistory(context.Background(), "AAPL", historyStart, historyStart.AddDate(0, 0, 1)); err != ErrStockNotFound {
+		t.Errorf("GetHistory() unknown symbol error = %v, want %v", err, ErrStockNotFound)
+	}
+	if _, err := svc.GetHistory(context.Background(), "AAPL", historyStart, historyStart); err != ErrInvalidHistoryRange {
+		t.Errorf("GetHistory() empty range error = %v, want %v", err, ErrInvalidHistoryRange)
+	}
+	if _, err := svc.SyncDailyHistory(context.Background(), "AAPL"); err != ErrHistorySyncUnavailable {
+		t.Errorf("SyncDailyHistory() without a source error = %v, want %v", err, ErrHistorySyncUnavailable)
+	}
+
+	stock, _ := repo.EnsureStock("AAPL")
+	repo.BulkInsert([]model.StockPrice{{StockID: stock.ID, Timestamp: historyStart}, {StockID: stock.ID, Timestamp: historyStart.AddDate(0, 0, 1)}})
+	prices, err := svc.GetHistory(context.Background(), "AAPL", historyStart, historyStart.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(prices) != 1 || !prices[0].Timestamp.Equal(historyStart) {
+		t.Errorf("GetHistory() = %+v, want only the bar at from", prices)
+	}
+}
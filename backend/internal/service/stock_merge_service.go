@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Stock merge service errors.
+var (
+	ErrStockNotFound      = errors.New("stock not found")
+	ErrNoDuplicates       = errors.New("at least one duplicate stock is required")
+	ErrCanonicalDuplicate = errors.New("canonical stock cannot also be a duplicate")
+	ErrSymbolMismatch     = errors.New("duplicate stocks must share the canonical stock's normalized symbol")
+)
+
+// DuplicateStockGroup is a set of stock rows that normalize to the same symbol.
+type DuplicateStockGroup struct {
+	Symbol     string        `json:"symbol"`
+	Canonical  model.Stock   `json:"canonical"`
+	Duplicates []model.Stock `json:"duplicates"`
+}
+
+// StockMergeService defines the interface for detecting and merging duplicate stocks.
+type StockMergeService interface {
+	FindDuplicates() ([]DuplicateStockGroup, error)
+	MergeStocks(canonicalID uuid.UUID, duplicateIDs []uuid.UUID) (*repository.StockMergeCounts, error)
+}
+
+// stockMergeService implements StockMergeService.
+type stockMergeService struct {
+	mergeRepo repository.StockMergeRepository
+}
+
+// NewStockMergeService creates a new StockMergeService instance.
+func NewStockMergeService(mergeRepo repository.StockMergeRepository) StockMergeService {
+	return &stockMergeService{mergeRepo: mergeRepo}
+}
+
+// FindDuplicates groups stocks by normalized symbol. The suggested canonical
+// row is the one already stored under the normalized symbol, else the oldest.
+func (s *stockMergeService) FindDuplicates() ([]DuplicateStockGroup, error) {
+	stocks, err := s.mergeRepo.ListStocks()
+	if err != nil {
+		return nil, err
+	}
+
+	bySymbol := make(map[string][]model.Stock)
+	for _, stock := range stocks {
+		symbol := repository.NormalizeSymbol(stock.Symbol)
+		bySymbol[symbol] = append(bySymbol[symbol], stock)
+	}
+
+	groups := make([]DuplicateStockGroup, 0)
+	for symbol, members := range bySymbol {
+		if len(members) < 2 {
+			continue
+		}
+
+		canonical := 0
+		for i, stock := range members {
+			if stock.Symbol == symbol {
+				canonical = i
+				break
+			}
+		}
+
+		group := DuplicateStockGroup{Symbol: symbol, Canonical: members[canonical]}
+		for i, stock := range members {
+			if i != canonical {
+				group.Duplicates = append(group.Duplicates, stock)
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Symbol < groups[j].Symbol
+	})
+
+	return groups, nil
+}
+
+// MergeStocks folds duplicate stocks into the canonical stock.
+func (s *stockMergeService) MergeStocks(canonicalID uuid.UUID, duplicateIDs []uuid.UUID) (*repository.StockMergeCounts, error) {
+	if len(duplicateIDs) == 0 {
+		return nil, ErrNoDuplicates
+	}
+
+	stocks, err := s.mergeRepo.ListStocks()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]model.Stock, len(stocks))
+	for _, stock := range stocks {
+		byID[stock.ID] = stock
+	}
+
+	canonical, ok := byID[canonicalID]
+	if !ok {
+		return nil, ErrStockNotFound
+	}
+	symbol := repository.NormalizeSymbol(canonical.Symbol)
+
+	for _, id := range duplicateIDs {
+		if id == canonicalID {
+			return nil, ErrCanonicalDuplicate
+		}
+		duplicate, ok := byID[id]
+		if !ok {
+			return nil, ErrStockNotFound
+		}
+		if repository.NormalizeSymbol(duplicate.Symbol) != symbol {
+			return nil, ErrSymbolMismatch
+		}
+	}
+
+	return s.mergeRepo.Merge(canonicalID, duplicateIDs)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// mockStockMergeRepository records merges against a fixed stock list.
+type mockStockMergeRepository struct {
+	stocks []model.Stock
+	merged []uuid.UUID
+}
+
+func (m *mockStockMergeRepository) ListStocks() ([]model.Stock, error) {
+	return m.stocks, nil
+}
+
+func (m *mockStockMergeRepository) Merge(canonicalID uuid.UUID, duplicateIDs []uuid.UUID) (*repository.StockMergeCounts, error) {
+	m.merged = append(m.merged, duplicateIDs...)
+	return &repository.StockMergeCounts{StocksDeleted: int64(len(duplicateIDs))}, nil
+}
+
+func TestStockMergeService_FindDuplicates(t *testing.T) {
+	now := time.Now()
+	canonical := model.Stock{ID: uuid.New(), Symbol: "AAPL", CreatedAt: now}
+	suffixed := model.Stock{ID: uuid.New(), Symbol: "AAPL.US", CreatedAt: now.Add(-time.Hour)}
+	prefixed := model.Stock{ID: uuid.New(), Symbol: "NASDAQ:AAPL", CreatedAt: now.Add(-2 * time.Hour)}
+	unique := model.Stock{ID: uuid.New(), Symbol: "MSFT", CreatedAt: now}
+
+	svc := NewStockMergeService(&mockStockMergeRepository{stocks: []model.Stock{prefixed, suffixed, canonical, unique}})
+
+	groups, err := svc.FindDuplicates()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 duplicate group, got %d", len(groups))
+	}
+	if groups[0].Canonical.ID != canonical.ID {
+		t.Errorf("Expected the row stored as AAPL to be canonical, got %s", groups[0].Canonical.Symbol)
+	}
+	if len(groups[0].Duplicates) != 2 {
+		t.Errorf("Expected 2 duplicates, got %d", len(groups[0].Duplicates))
+	}
+}
+
+func TestStockMergeService_MergeStocks(t *testing.T) {
+	canonical := model.Stock{ID: uuid.New(), Symbol: "AAPL"}
+	duplicate := model.Stock{ID: uuid.New(), Symbol: "AAPL.US"}
+	other := model.Stock{ID: uuid.New(), Symbol: "MSFT"}
+	repo := &mockStockMergeRepository{stocks: []model.Stock{canonical, duplicate, other}}
+	svc := NewStockMergeService(repo)
+
+	tests := []struct {
+		name       string
+		canonical  uuid.UUID
+		duplicates []uuid.UUID
+		wantErr    error
+	}{
+		{name: "no duplicates", canonical: canonical.ID, wantErr: ErrNoDuplicates},
+		{name: "unknown canonical", canonical: uuid.New(), duplicates: []uuid.UUID{duplicate.ID}, wantErr: ErrStockNotFound},
+		{name: "canonical listed as duplicate", canonical: canonical.ID, duplicates: []uuid.UUID{canonical.ID}, wantErr: ErrCanonicalDuplicate},
+		{name: "different symbol", canonical: canonical.ID, duplicates: []uuid.UUID{other.ID}, wantErr: ErrSymbolMismatch},
+		{name: "valid merge", canonical: canonical.ID, duplicates: []uuid.UUID{duplicate.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, err := svc.MergeStocks(tt.canonical, tt.duplicates)
+			if err != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && counts.StocksDeleted != 1 {
+				t.Errorf("Expected 1 stock deleted, got %d", counts.StocksDeleted)
+			}
+		})
+	}
+
+	if len(repo.merged) != 1 || repo.merged[0] != duplicate.ID {
+		t.Errorf("Expected only the valid merge to reach the repository, got %v", repo.merged)
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// MaxQuoteSymbols is the most symbols a single batch quote request may name.
+const MaxQuoteSymbols = 50
+
+// Batch quote errors.
+var (
+	ErrNoQuoteSymbols      = errors.New("at least one symbol is required")
+	ErrTooManyQuoteSymbols = fmt.Errorf("at most %d symbols are allowed per request", MaxQuoteSymbols)
+)
+
+// SymbolQuote is a live quote for one symbol.
+type SymbolQuote struct {
+	Symbol        string    `json:"symbol"`
+	Price         float64   `json:"price"`
+	Change        float64   `json:"change"`
+	ChangePercent float64   `json:"change_percent"`
+	Open          float64   `json:"open"`
+	High          float64   `json:"high"`
+	Low           float64   `json:"low"`
+	PreviousClose float64   `json:"previous_close"`
+	Volume        int64     `json:"volume"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// BatchQuoteSource fetches live quotes for many symbols. It returns the
+// quotes it found and the error for each symbol it could not quote.
+type BatchQuoteSource interface {
+	GetQuotes(ctx context.Context, symbols []string) ([]SymbolQuote, map[string]error)
+}
+
+// QuoteWarning names a symbol that could not be quoted. Provider errors are
+// logged rather than returned, since they may carry upstream request details.
+type QuoteWarning struct {
+	Symbol  string `json:"symbol"`
+	Message string `json:"message"`
+}
+
+// BatchQuotes are the quotes for a batch request. Warnings list the
+// symbols without a quote, in request order, so one failing symbol does not
+// fail the request.
+type BatchQuotes struct {
+	Quotes   []SymbolQuote  `json:"quotes"`
+	Warnings []QuoteWarning `json:"warnings,omitempty"`
+}
+
+// StockQuotesService defines the interface for batch live quotes.
+type StockQuotesService interface {
+	GetQuotes(ctx context.Context, symbols []string) (*BatchQuotes, error)
+}
+
+// stockQuotesService implements StockQuotesService.
+type stockQuotesService struct {
+	source BatchQuoteSource
+}
+
+// NewStockQuotesService creates a new StockQuotesService instance.
+func NewStockQuotesService(source BatchQuoteSource) StockQuotesService {
+	return &stockQuotesService{source: source}
+}
+
+// NormalizeQuoteSymbols normalizes the symbols and drops blanks and
+// repeats, keeping their order. It fails unless between one and
+// MaxQuoteSymbols remain.
+func NormalizeQuoteSymbols(symbols []string) ([]string, error) {
+	seen := make(map[string]bool, len(symbols))
+	var normalized []string
+	for _, symbol := range symbols {
+		symbol = repository.NormalizeSymbol(symbol)
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		normalized = append(normalized, symbol)
+	}
+	if len(normalized) == 0 {
+		return nil, ErrNoQuoteSymbols
+	}
+	if len(normalized) > MaxQuoteSymbols {
+		return nil, ErrTooManyQuoteSymbols
+	}
+	return normalized, nil
+}
+
+// GetQuotes normalizes the symbols, drops blanks and repeats, and quotes the
+// rest. Quotes are in request order.
+func (s *stockQuotesService) GetQuotes(ctx context.Context, symbols []string) (*BatchQuotes, error) {
+	normalized, err := NormalizeQuoteSymbols(symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, failed := s.source.GetQuotes(ctx, normalized)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &BatchQuotes{Quotes: quotes}
+	if result.Quotes == nil {
+		result.Quotes = []SymbolQuote{}
+	}
+	for _, symbol := range normalized {
+		if err, ok := failed[symbol]; ok {
+			log.Warn().Err(err).Str("symbol", symbol).Msg("No live quote for symbol")
+			result.Warnings = append(result.Warnings, QuoteWarning{Symbol: symbol, Message: "no quote available"})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
+	"github.com/awaymess/super-dashboard/backend/lib/calculations"
+)
+
+// Technical analysis limits and defaults.
+const (
+	// DefaultIndicatorPeriod is the lookback used when none is given.
+	DefaultIndicatorPeriod = 14
+	// MinIndicatorPeriod and MaxIndicatorPeriod bound the lookback. The
+	// analysis reads about 100 daily bars, so longer periods leave too few
+	// values to be useful.
+	MinIndicatorPeriod = 2
+	MaxIndicatorPeriod = 50
+	// IndicatorSeriesLength is how many recent values each series returns.
+	IndicatorSeriesLength = 30
+)
+
+// DefaultIndicators are analyzed when a request names none.
+var DefaultIndicators = []string{"rsi", "macd", "bbands"}
+
+// Technical analysis errors.
+var (
+	ErrUnknownIndicator         = errors.New("unknown indicator: use sma, ema, rsi, macd, bbands, atr, stoch, willr, cci or obv")
+	ErrInvalidIndicatorPeriod   = fmt.Errorf("period must be between %d and %d", MinIndicatorPeriod, MaxIndicatorPeriod)
+	ErrInsufficientPriceHistory = errors.New("not enough price history for the requested indicators")
+)
+
+// IndicatorPoint is an indicator's values for one daily bar. Single-line
+// indicators use the key "value"; the others name their lines, e.g. macd,
+// signal and histogram.
+type IndicatorPoint struct {
+	Date   time.Time          `json:"date"`
+	Values map[string]float64 `json:"values"`
+}
+
+// IndicatorSeries is an indicator's latest point and its recent points,
+// oldest first.
+type IndicatorSeries struct {
+	Latest IndicatorPoint   `json:"latest"`
+	Series []IndicatorPoint `json:"series"`
+}
+
+// TechnicalAnalysis is the result of analyzing a symbol's daily bars.
+type TechnicalAnalysis struct {
+	Symbol     string                     `json:"symbol"`
+	Period     int                        `json:"period"`
+	AsOf       time.Time                  `json:"as_of"`
+	Indicators map[string]IndicatorSeries `json:"indicators"`
+}
+
+// indicatorFunc computes an indicator over bars sorted oldest first. Each
+// returned line is aligned to the end of bars.
+type indicatorFunc func(closes []float64, data []calculations.PriceData, period int) map[string][]float64
+
+// indicators maps indicator names to their calculations. MACD uses the
+// standard 12/26/9 periods, Bollinger Bands two standard deviations and
+// the stochastic a 3-bar %D, whatever the requested period.
+var indicators = map[string]indicatorFunc{
+	"sma": func(closes []float64, _ []calculations.PriceData, period int) map[string][]float64 {
+		return map[string][]float64{"value": calculations.SMA(closes, period)}
+	},
+	"ema": func(closes []float64, _ []calculations.PriceData, period int) map[string][]float64 {
+		return map[string][]float64{"value": calculations.EMA(closes, period)}
+	},
+	"rsi": func(closes []float64, _ []calculations.PriceData, period int) map[string][]float64 {
+		return map[string][]float64{"value": calculations.RSI(closes, period)}
+	},
+	"macd": func(closes []float64, _ []calculations.PriceData, _ int) map[string][]float64 {
+		results := calculations.MACD(closes, 12, 26, 9)
+		lines := map[string][]float64{
+			"macd":      make([]float64, len(results)),
+			"signal":    make([]float64, len(results)),
+			"histogram": make([]float64, len(results)),
+		}
+		for i, r := range results {
+			lines["macd"][i], lines["signal"][i], lines["histogram"][i] = r.MACD, r.Signal, r.Histogram
+		}
+		return lines
+	},
+	"bbands": func(closes []float64, _ []calculations.PriceData, period int) map[string][]float64 {
+		results := calculations.BollingerBands(closes, period, 2)
+		lines := map[string][]float64{
+			"upper":  make([]float64, len(results)),
+			"middle": make([]float64, len(results)),
+			"lower":  make([]float64, len(results)),
+		}
+		for i, r := range results {
+			lines["upper"][i], lines["middle"][i], lines["lower"][i] = r.Upper, r.Middle, r.Lower
+		}
+		return lines
+	},
+	"atr": func(_ []float64, data []calculations.PriceData, period int) map[string][]float64 {
+		return map[string][]float64{"value": calculations.ATR(data, period)}
+	},
+	"stoch": func(_ []float64, data []calculations.PriceData, period int) map[string][]float64 {
+		results := calculations.Stochastic(data, period, 3)
+		lines := map[string][]float64{
+			"k": make([]float64, len(results)),
+			"d": make([]float64, len(results)),
+		}
+		for i, r := range results {
+			lines["k"][i], lines["d"][i] = r.K, r.D
+		}
+		return lines
+	},
+	"willr": func(_ []float64, data []calculations.PriceData, period int) map[string][]float64 {
+		return map[string][]float64{"value": calculations.WilliamsR(data, period)}
+	},
+	"cci": func(_ []float64, data []calculations.PriceData, period int) map[string][]float64 {
+		return map[string][]float64{"value": calculations.CCI(data, period)}
+	},
+	"obv": func(_ []float64, data []calculations.PriceData, _ int) map[string][]float64 {
+		return map[string][]float64{"value": calculations.OBV(data)}
+	},
+}
+
+// TechnicalAnalysisService defines the interface for locally computed
+// technical indicators.
+type TechnicalAnalysisService interface {
+	// Analyze computes the named indicators over the symbol's recent daily
+	// bars. Without names it computes DefaultIndicators, and a period of 0
+	// means DefaultIndicatorPeriod.
+	Analyze(ctx context.Context, symbol string, names []string, period int) (*TechnicalAnalysis, error)
+}
+
+// technicalAnalysisService implements TechnicalAnalysisService.
+type technicalAnalysisService struct {
+	source DailyBarSource
+}
+
+// NewTechnicalAnalysisService creates a new TechnicalAnalysisService instance.
+// One history request serves every indicator, unlike the provider's
+// per-indicator endpoints.
+func NewTechnicalAnalysisService(source DailyBarSource) TechnicalAnalysisService {
+	return &technicalAnalysisService{source: source}
+}
+
+// Analyze validates the request, fetches the recent daily bars and computes
+// each indicator over them.
+func (s *technicalAnalysisService) Analyze(ctx context.Context, symbol string, names []string, period int) (*TechnicalAnalysis, error) {
+	if period == 0 {
+		period = DefaultIndicatorPeriod
+	}
+	if period < MinIndicatorPeriod || period > MaxIndicatorPeriod {
+		return nil, ErrInvalidIndicatorPeriod
+	}
+
+	requested, err := normalizeIndicators(names)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol = repository.NormalizeSymbol(symbol)
+	if symbol == "" {
+		return nil, ErrStockNotFound
+	}
+	bars, err := s.source.GetDailyBars(ctx, symbol, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) == 0 {
+		return nil, ErrStockNotFound
+	}
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Timestamp.Before(bars[j].Timestamp) })
+
+	closes := make([]float64, len(bars))
+	data := make([]calculations.PriceData, len(bars))
+	for i, bar := range bars {
+		closes[i] = bar.Close
+		data[i] = calculations.PriceData{Open: bar.Open, High: bar.High, Low: bar.Low, Close: bar.Close, Volume: float64(bar.Volume)}
+	}
+
+	analysis := &TechnicalAnalysis{
+		Symbol:     symbol,
+		Period:     period,
+		AsOf:       bars[len(bars)-1].Timestamp,
+		Indicators: make(map[string]IndicatorSeries, len(requested)),
+	}
+	for _, name := range requested {
+		series := indicatorSeries(bars, indicators[name](closes, data, period))
+		if len(series) == 0 {
+			return nil, ErrInsufficientPriceHistory
+		}
+		analysis.Indicators[name] = IndicatorSeries{Latest: series[len(series)-1], Series: series}
+	}
+	return analysis, nil
+}
+
+// normalizeIndicators lower-cases the names, drops blanks and repeats, and
+// rejects unknown names.
+func normalizeIndicators(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	var normalized []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := indicators[name]; !ok {
+			return nil, ErrUnknownIndicator
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	if len(normalized) == 0 {
+		return DefaultIndicators, nil
+	}
+	return normalized, nil
+}
+
+// indicatorSeries dates the last IndicatorSeriesLength values of the lines.
+// Values that are not finite, such as CCI over flat prices, are left out.
+func indicatorSeries(bars []model.StockPrice, lines map[string][]float64) []IndicatorPoint {
+	n := -1
+	for _, line := range lines {
+		if n < 0 || len(line) < n {
+			n = len(line)
+		}
+	}
+	if n <= 0 {
+		return nil
+	}
+	start := 0
+	if n > IndicatorSeriesLength {
+		start = n - IndicatorSeriesLength
+	}
+
+	points := make([]IndicatorPoint, 0, n-start)
+	for i := start; i < n; i++ {
+		point := IndicatorPoint{Date: bars[len(bars)-n+i].Timestamp, Values: make(map[string]float64, len(lines))}
+		for key, line := range lines {
+			value := line[len(line)-n+i]
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			point.Values[key] = value
+		}
+		points = append(points, point)
+	}
+	return points
+}
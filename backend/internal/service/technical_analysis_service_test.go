@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+// risingBars returns n daily bars whose close rises by 1 a day, newest
+// first like the provider's time series.
+func risingBars(n int) []model.StockPrice {
+	bars := make([]model.StockPrice, n)
+	for i := range bars {
+		day := n - 1 - i
+		closePrice := 100 + float64(day)
+		bars[i] = model.StockPrice{
+			Timestamp: historyStart.AddDate(0, 0, day),
+			Open:      closePrice - 0.5,
+			High:      closePrice + 1,
+			Low:       closePrice - 1,
+			Close:     closePrice,
+			Volume:    1000,
+		}
+	}
+	return bars
+}
+
+func TestTechnicalAnalysisService_Analyze(t *testing.T) {
+	source := &mockDailyBarSource{bars: risingBars(60)}
+	svc := NewTechnicalAnalysisService(source)
+
+	analysis, err := svc.Analyze(context.Background(), "aapl", []string{"RSI", " macd", "rsi"}, 0)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if analysis.Symbol != "AAPL" || analysis.Period != DefaultIndicatorPeriod {
+		t.Errorf("Analyze() = %s period %d, want AAPL period %d", analysis.Symbol, analysis.Period, DefaultIndicatorPeriod)
+	}
+	lastDay := historyStart.AddDate(0, 0, 59)
+	if !analysis.AsOf.Equal(lastDay) {
+		t.Errorf("AsOf = %v, want the newest bar %v", analysis.AsOf, lastDay)
+	}
+	if len(analysis.Indicators) != 2 {
+		t.Fatalf("Analyze() returned %d indicators, want rsi and macd", len(analysis.Indicators))
+	}
+	if len(source.fulls) != 1 || source.fulls[0] {
+		t.Errorf("full history requests = %v, want one compact request", source.fulls)
+	}
+
+	rsi := analysis.Indicators["rsi"]
+	if len(rsi.Series) != IndicatorSeriesLength {
+		t.Fatalf("rsi series has %d points, want %d", len(rsi.Series), IndicatorSeriesLength)
+	}
+	for i, point := range rsi.Series {
+		if !point.Date.Equal(lastDay.AddDate(0, 0, i-IndicatorSeriesLength+1)) {
+			t.Errorf("rsi point %d dated %v, want consecutive days ending %v", i, point.Date, lastDay)
+		}
+		if len(point.Values) != 1 || point.Values["value"] != 100 {
+			t.Errorf("rsi point %d = %v, want value 100 for rising prices", i, point.Values)
+		}
+	}
+	if !rsi.Latest.Date.Equal(lastDay) {
+		t.Errorf("rsi latest dated %v, want %v", rsi.Latest.Date, lastDay)
+	}
+
+	// 60 bars leave 35 MACD values and 27 signal values.
+	macd := analysis.Indicators["macd"]
+	if len(macd.Series) != 27 {
+		t.Fatalf("macd series has %d points, want 27", len(macd.Series))
+	}
+	latest := macd.Latest.Values
+	if len(latest) != 3 || math.Abs(latest["macd"]-7) > 1e-9 || math.Abs(latest["signal"]-7) > 1e-9 || math.Abs(latest["histogram"]) > 1e-9 {
+		t.Errorf("macd latest = %v, want macd 7, signal 7, histogram 0", latest)
+	}
+}
+
+func TestTechnicalAnalysisService_AnalyzeDefaultsAndLines(t *testing.T) {
+	svc := NewTechnicalAnalysisService(&mockDailyBarSource{bars: risingBars(60)})
+
+	analysis, err := svc.Analyze(context.Background(), "AAPL", nil, 20)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	for _, name := range DefaultIndicators {
+		if _, ok := analysis.Indicators[name]; !ok {
+			t.Errorf("default analysis is missing %s", name)
+		}
+	}
+
+	bands := analysis.Indicators["bbands"].Latest.Values
+	if bands["middle"] != 149.5 || bands["upper"] <= bands["middle"] || bands["lower"] >= bands["middle"] {
+		t.Errorf("bbands latest = %v, want a 20-day middle of 149.5 between the bands", bands)
+	}
+}
+
+func TestTechnicalAnalysisService_AnalyzeErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		bars       []model.StockPrice
+		indicators []string
+		period     int
+		want       error
+	}{
+		{"unknown indicator", risingBars(60), []string{"rsi", "vwap"}, 14, ErrUnknownIndicator},
+		{"period too short", risingBars(60), []string{"rsi"}, 1, ErrInvalidIndicatorPeriod},
+		{"period too long", risingBars(60), []string{"rsi"}, MaxIndicatorPeriod + 1, ErrInvalidIndicatorPeriod},
+		{"negative period", risingBars(60), []string{"rsi"}, -5, ErrInvalidIndicatorPeriod},
+		{"no bars", nil, []string{"rsi"}, 14, ErrStockNotFound},
+		{"too few bars", risingBars(20), []string{"macd"}, 14, ErrInsufficientPriceHistory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewTechnicalAnalysisService(&mockDailyBarSource{bars: tt.bars})
+			if _, err := svc.Analyze(context.Background(), "AAPL", tt.indicators, tt.period); err != tt.want {
+				t.Errorf("Analyze() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTechnicalAnalysisService_AnalyzeDropsNonFiniteValues(t *testing.T) {
+	bars := risingBars(30)
+	for i := range bars {
+		bars[i].High, bars[i].Low, bars[i].Close = 100, 100, 100
+	}
+	svc := NewTechnicalAnalysisService(&mockDailyBarSource{bars: bars})
+
+	// CCI divides by the mean deviation, which is zero when prices are flat.
+	analysis, err := svc.Analyze(context.Background(), "AAPL", []string{"cci", "willr"}, 14)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if _, ok := analysis.Indicators["cci"].Latest.Values["value"]; ok {
+		t.Errorf("cci over flat prices = %v, want no value", analysis.Indicators["cci"].Latest.Values)
+	}
+	if v, ok := analysis.Indicators["willr"].Latest.Values["value"]; !ok || v != -50 {
+		t.Errorf("willr over a flat range = %v, want the neutral -50", analysis.Indicators["willr"].Latest.Values)
+	}
+}
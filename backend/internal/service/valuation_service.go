@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+)
+
+// Valuation errors
+var (
+	ErrInsufficientFundamentals = errors.New("not enough fundamentals to value the stock")
+	ErrNoCurrentPrice           = errors.New("no current price for the stock")
+)
+
+// DCF assumptions. The first stage grows earnings at the company's growth
+// rate, capped to MaxDCFGrowthRate either way, for DCFHighGrowthYears; the
+// second is a perpetuity growing at DCFTerminalGrowthRate.
+const (
+	DCFDiscountRate       = 0.10
+	DCFTerminalGrowthRate = 0.03
+	DCFHighGrowthYears    = 5
+	MaxDCFGrowthRate      = 0.25
+)
+
+// DefaultSectorPE is the P/E applied to sectors missing from SectorMedianPE.
+const DefaultSectorPE = 15.0
+
+// SectorMedianPE holds typical P/E ratios by Alpha Vantage sector name.
+var SectorMedianPE = map[string]float64{
+	"TECHNOLOGY":                 25,
+	"COMMUNICATION SERVICES":     18,
+	"HEALTHCARE":                 22,
+	"LIFE SCIENCES":              22,
+	"FINANCIAL SERVICES":         13,
+	"FINANCE":                    13,
+	"ENERGY":                     11,
+	"ENERGY & TRANSPORTATION":    11,
+	"CONSUMER CYCLICAL":          18,
+	"CONSUMER DEFENSIVE":         20,
+	"TRADE & SERVICES":           18,
+	"INDUSTRIALS":                20,
+	"MANUFACTURING":              18,
+	"BASIC MATERIALS":            15,
+	"REAL ESTATE":                30,
+	"REAL ESTATE & CONSTRUCTION": 30,
+	"UTILITIES":                  17,
+}
+
+// Weights of each model in the fair value. Models without a value are left
+// out and the remaining weights rescaled.
+const (
+	dcfWeight    = 0.4
+	peWeight     = 0.3
+	grahamWeight = 0.3
+)
+
+// CompanyFundamentals are the per-share figures the valuation models use.
+// Zero means the figure is unknown.
+type CompanyFundamentals struct {
+	Sector    string
+	EPS       float64
+	BookValue float64
+	// GrowthRate is the expected annual earnings growth as a fraction.
+	GrowthRate float64
+}
+
+// FundamentalsSource fetches a company's fundamentals.
+type FundamentalsSource interface {
+	GetFundamentals(ctx context.Context, symbol string) (*CompanyFundamentals, error)
+}
+
+// ValuationService computes fair values from fundamentals and live prices.
+// It is the FairValueCalculator used for scheduled recalculation.
+type ValuationService interface {
+	Calculate(symbol string) (*model.FairValue, error)
+}
+
+// valuationService implements ValuationService.
+type valuationService struct {
+	fundamentals FundamentalsSource
+	quotes       QuoteSource
+}
+
+// NewValuationService creates a new ValuationService instance.
+func NewValuationService(fundamentals FundamentalsSource, quotes QuoteSource) ValuationService {
+	return &valuationService{fundamentals: fundamentals, quotes: quotes}
+}
+
+// Calculate values the stock with a DCF, a sector P/E and the Graham number,
+// averages them by weight and compares the result with the current price.
+// Models whose inputs are missing or not positive are skipped.
+func (s *valuationService) Calculate(symbol string) (*model.FairValue, error) {
+	ctx := context.Background()
+	symbol = strings.ToUpper(symbol)
+
+	fundamentals, err := s.fundamentals.GetFundamentals(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := s.quotes.GetQuote(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Price <= 0 {
+		return nil, ErrNoCurrentPrice
+	}
+
+	fv := &model.FairValue{
+		Symbol:       symbol,
+		DCFValue:     dcfValue(fundamentals.EPS, fundamentals.GrowthRate),
+		PEValue:      peValue(fundamentals.EPS, fundamentals.Sector),
+		GrahamValue:  grahamNumber(fundamentals.EPS, fundamentals.BookValue),
+		CurrentPrice: quote.Price,
+		CalculatedAt: time.Now(),
+	}
+
+	var total, weights float64
+	for _, m := range []struct{ value, weight float64 }{
+		{fv.DCFValue, dcfWeight},
+		{fv.PEValue, peWeight},
+		{fv.GrahamValue, grahamWeight},
+	} {
+		if m.value > 0 {
+			total += m.value * m.weight
+			weights += m.weight
+		}
+	}
+	if weights == 0 {
+		return nil, ErrInsufficientFundamentals
+	}
+
+	fv.WeightedAvg = total / weights
+	fv.MarginOfSafety = (fv.WeightedAvg - fv.CurrentPrice) / fv.WeightedAvg * 100
+	fv.UpsidePercent = (fv.WeightedAvg - fv.CurrentPrice) / fv.CurrentPrice * 100
+	fv.Recommendation = DefaultRecommendationThresholds.Recommend(fv.MarginOfSafety)
+	return fv, nil
+}
+
+// grahamNumber returns sqrt(22.5 * EPS * book value), or 0 unless both are positive.
+func grahamNumber(eps, bookValue float64) float64 {
+	if eps <= 0 || bookValue <= 0 {
+		return 0
+	}
+	return math.Sqrt(22.5 * eps * bookValue)
+}
+
+// peValue prices earnings at the sector's median P/E, or returns 0 without
+// positive earnings.
+func peValue(eps float64, sector string) float64 {
+	if eps <= 0 {
+		return 0
+	}
+	pe, ok := SectorMedianPE[strings.ToUpper(sector)]
+	if !ok {
+		pe = DefaultSectorPE
+	}
+	return eps * pe
+}
+
+// dcfValue discounts DCFHighGrowthYears of earnings growing at growth plus a
+// terminal value, or returns 0 without positive earnings.
+func dcfValue(eps, growth float64) float64 {
+	if eps <= 0 {
+		return 0
+	}
+	growth = math.Max(-MaxDCFGrowthRate, math.Min(growth, MaxDCFGrowthRate))
+
+	var value float64
+	earnings := eps
+	discount := 1.0
+	for year := 1; year <= DCFHighGrowthYears; year++ {
+		earnings *= 1 + growth
+		discount *= 1 + DCFDiscountRate
+		value += earnings / discount
+	}
+	terminal := earnings * (1 + DCFTerminalGrowthRate) / (DCFDiscountRate - DCFTerminalGrowthRate)
+	return value + terminal/discount
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// stubFundamentals serves fixed fundamentals, as an Alpha Vantage overview would.
+type stubFundamentals struct {
+	fundamentals CompanyFundamentals
+	err          error
+}
+
+func (s *stubFundamentals) GetFundamentals(ctx context.Context, symbol string) (*CompanyFundamentals, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	f := s.fundamentals
+	return &f, nil
+}
+
+// stubQuotes serves a fixed price.
+type stubQuotes struct {
+	price float64
+}
+
+func (s *stubQuotes) GetQuote(ctx context.Context, symbol string) (*MarketQuote, error) {
+	return &MarketQuote{Price: s.price}, nil
+}
+
+func TestValuationService_Calculate(t *testing.T) {
+	// With growth equal to the discount rate every discounted year of the
+	// first stage is worth the EPS, so the DCF is 5*6 + 6*1.03/0.07.
+	dcf := 30 + 6*1.03/0.07
+	graham := math.Sqrt(22.5 * 6 * 20)
+
+	tests := []struct {
+		name         string
+		fundamentals CompanyFundamentals
+		wantDCF      float64
+		wantPE       float64
+		wantGraham   float64
+		wantFair     float64
+	}{
+		{
+			name:         "all models",
+			fundamentals: CompanyFundamentals{Sector: "Technology", EPS: 6, BookValue: 20, GrowthRate: 0.10},
+			wantDCF:      dcf,
+			wantPE:       150,
+			wantGraham:   graham,
+			wantFair:     dcf*0.4 + 150*0.3 + graham*0.3,
+		},
+		{
+			name:         "negative book value skips graham",
+			fundamentals: CompanyFundamentals{Sector: "TECHNOLOGY", EPS: 6, BookValue: -5, GrowthRate: 0.10},
+			wantDCF:      dcf,
+			wantPE:       150,
+			wantFair:     (dcf*0.4 + 150*0.3) / 0.7,
+		},
+		{
+			name:         "unknown sector uses default P/E",
+			fundamentals: CompanyFundamentals{Sector: "", EPS: 6, BookValue: 20, GrowthRate: 0.10},
+			wantDCF:      dcf,
+			wantPE:       90,
+			wantGraham:   graham,
+			wantFair:     dcf*0.4 + 90*0.3 + graham*0.3,
+		},
+		{
+			name:         "growth is capped",
+			fundamentals: CompanyFundamentals{Sector: "Technology", EPS: 6, BookValue: 20, GrowthRate: 3},
+			wantDCF:      dcfValue(6, MaxDCFGrowthRate),
+			wantPE:       150,
+			wantGraham:   graham,
+			wantFair:     dcfValue(6, MaxDCFGrowthRate)*0.4 + 150*0.3 + graham*0.3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewValuationService(&stubFundamentals{fundamentals: tt.fundamentals}, &stubQuotes{price: 100})
+			fv, err := svc.Calculate("aapl")
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+
+			for _, c := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"dcf", fv.DCFValue, tt.wantDCF},
+				{"pe", fv.PEValue, tt.wantPE},
+				{"graham", fv.GrahamValue, tt.wantGraham},
+				{"fair value", fv.WeightedAvg, tt.wantFair},
+				{"margin of safety", fv.MarginOfSafety, (tt.wantFair - 100) / tt.wantFair * 100},
+				{"upside", fv.UpsidePercent, tt.wantFair - 100},
+			} {
+				if math.Abs(c.got-c.want) > 1e-9 {
+					t.Errorf("Calculate() %s = %v, want %v", c.name, c.got, c.want)
+				}
+			}
+			if fv.Symbol != "AAPL" || fv.CurrentPrice != 100 {
+				t.Errorf("Calculate() symbol = %q, price = %v, want AAPL, 100", fv.Symbol, fv.CurrentPrice)
+			}
+			if want := DefaultRecommendationThresholds.Recommend(fv.MarginOfSafety); fv.Recommendation != want {
+				t.Errorf("Calculate() recommendation = %q, want %q", fv.Recommendation, want)
+			}
+		})
+	}
+}
+
+func TestValuationService_Calculate_Errors(t *testing.T) {
+	overviewErr := errors.New("rate limited")
+	tests := []struct {
+		name         string
+		fundamentals *stubFundamentals
+		price        float64
+		want         error
+	}{
+		{"no earnings", &stubFundamentals{fundamentals: CompanyFundamentals{BookValue: 20}}, 100, ErrInsufficientFundamentals},
+		{"no price", &stubFundamentals{fundamentals: CompanyFundamentals{EPS: 6}}, 0, ErrNoCurrentPrice},
+		{"overview error", &stubFundamentals{err: overviewErr}, 100, overviewErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewValuationService(tt.fundamentals, &stubQuotes{price: tt.price})
+			if _, err := svc.Calculate("AAPL"); err != tt.want {
+				t.Errorf("Calculate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
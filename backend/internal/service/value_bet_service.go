@@ -2,206 +2,215 @@ package service
 
 import (
 	"context"
-	"fmt"
-	"math"
+	"errors"
+	"sort"
+	"time"
 
+	"github.com/awaymess/super-dashboard/backend/internal/model"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog"
+)
 
-	"super-dashboard/backend/internal/model"
-	"super-dashboard/backend/internal/repository"
+// Value bet errors
+var (
+	ErrMatchNotFound = errors.New("match not found")
 )
 
-// ValueBetService handles value bet calculations and detection.
-type ValueBetService struct {
-	valueBetRepo *repository.ValueBetRepository
-	matchRepo    *repository.MatchRepository
-	logger       zerolog.Logger
-}
+// DefaultValueBetThreshold is the minimum value, in percent, of a reported
+// value bet for users who have not set their own. It matches the Settings
+// column default.
+const DefaultValueBetThreshold = 5.0
 
-// NewValueBetService creates a new ValueBetService.
-func NewValueBetService(
-	valueBetRepo *repository.ValueBetRepository,
-	matchRepo *repository.MatchRepository,
-	logger zerolog.Logger,
-) *ValueBetService {
-	return &ValueBetService{
-		valueBetRepo: valueBetRepo,
-		matchRepo:    matchRepo,
-		logger:       logger.With().Str("service", "value_bet").Logger(),
-	}
-}
+// MarketMatchResult is the market of bets on the full-time result.
+const MarketMatchResult = "1X2"
 
-// CalculateTrueProbability calculates true probability using multiple models.
-func (s *ValueBetService) CalculateTrueProbability(ctx context.Context, matchID uuid.UUID, market string) (float64, error) {
-	match, err := s.matchRepo.GetByID(ctx, matchID)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get match: %w", err)
-	}
-
-	// Use ELO ratings to calculate probability
-	homeELO := match.HomeTeam.ELORating
-	awayELO := match.AwayTeam.ELORating
-
-	var probability float64
-
-	switch market {
-	case "1X2_home":
-		probability = s.eloWinProbability(homeELO, awayELO)
-	case "1X2_away":
-		probability = s.eloWinProbability(awayELO, homeELO)
-	case "1X2_draw":
-		probability = s.eloDrawProbability(homeELO, awayELO)
-	case "over_2.5":
-		// Simplified model based on average goals
-		avgGoals := (match.HomeTeam.AvgGoalsScored + match.AwayTeam.AvgGoalsScored) / 2
-		probability = s.poissonProbabilityOver(avgGoals, 2.5)
-	case "under_2.5":
-		avgGoals := (match.HomeTeam.AvgGoalsScored + match.AwayTeam.AvgGoalsScored) / 2
-		probability = 1 - s.poissonProbabilityOver(avgGoals, 2.5)
-	case "btts_yes":
-		// Both teams to score
-		homeScoreProb := 1 - s.poissonProbability(match.AwayTeam.AvgGoalsConceded, 0)
-		awayScoreProb := 1 - s.poissonProbability(match.HomeTeam.AvgGoalsConceded, 0)
-		probability = homeScoreProb * awayScoreProb
-	case "btts_no":
-		homeScoreProb := 1 - s.poissonProbability(match.AwayTeam.AvgGoalsConceded, 0)
-		awayScoreProb := 1 - s.poissonProbability(match.HomeTeam.AvgGoalsConceded, 0)
-		probability = 1 - (homeScoreProb * awayScoreProb)
-	default:
-		return 0, fmt.Errorf("unsupported market: %s", market)
-	}
+// Match result outcomes.
+const (
+	OutcomeHome = "home"
+	OutcomeDraw = "draw"
+	OutcomeAway = "away"
+)
 
-	return probability, nil
+// ProbabilityModel estimates the true probability of an outcome. ok is false
+// when the model has no estimate for the market or outcome.
+type ProbabilityModel interface {
+	TrueProbability(match *model.Match, market, outcome string) (prob float64, ok bool)
 }
 
-// eloWinProbability calculates win probability based on ELO ratings.
-func (s *ValueBetService) eloWinProbability(rating1, rating2 float64) float64 {
-	return 1 / (1 + math.Pow(10, (rating2-rating1)/400))
+// ValueBetStore provides matches and their odds, and stores detected value bets.
+type ValueBetStore interface {
+	GetMatch(ctx context.Context, matchID uuid.UUID) (*model.Match, error)
+	GetMatchOdds(ctx context.Context, matchID uuid.UUID) ([]model.Odds, error)
+	// ReplaceMatchValueBets replaces the stored value bets of a match.
+	ReplaceMatchValueBets(ctx context.Context, matchID uuid.UUID, bets []model.ValueBet) error
 }
 
-// eloDrawProbability estimates draw probability.
-func (s *ValueBetService) eloDrawProbability(rating1, rating2 float64) float64 {
-	diff := math.Abs(rating1 - rating2)
-	// Empirical formula: closer ratings = higher draw probability
-	baseDraw := 0.25
-	adjustment := (200 - diff) / 1000
-	if adjustment < 0 {
-		adjustment = 0
-	}
-	return baseDraw + adjustment
+// ValueBetSettingsStore provides users' value bet thresholds.
+type ValueBetSettingsStore interface {
+	GetUserSettings(ctx context.Context, userID uuid.UUID) (*model.Settings, error)
 }
 
-// poissonProbability calculates Poisson probability.
-func (s *ValueBetService) poissonProbability(lambda float64, k int) float64 {
-	if lambda <= 0 {
-		return 0
-	}
-	return (math.Pow(lambda, float64(k)) * math.Exp(-lambda)) / s.factorial(k)
+// ValueBetService defines the interface for detecting value bets.
+type ValueBetService interface {
+	// Detect returns the match's value bets worth at least DefaultValueBetThreshold.
+	Detect(matchID uuid.UUID) ([]model.ValueBet, error)
+	// DetectAbove returns the match's value bets worth at least threshold percent.
+	DetectAbove(matchID uuid.UUID, threshold float64) ([]model.ValueBet, error)
+	// Threshold returns the user's value bet threshold, falling back to
+	// DefaultValueBetThreshold.
+	Threshold(ctx context.Context, userID uuid.UUID) (float64, error)
 }
 
-// poissonProbabilityOver calculates probability of over X goals.
-func (s *ValueBetService) poissonProbabilityOver(lambda, threshold float64) float64 {
-	sum := 0.0
-	for k := 0; k <= int(threshold); k++ {
-		sum += s.poissonProbability(lambda, k)
-	}
-	return 1 - sum
+// valueBetService implements ValueBetService.
+type valueBetService struct {
+	store         ValueBetStore
+	probabilities ProbabilityModel
+	settings      ValueBetSettingsStore
 }
 
-// factorial calculates factorial.
-func (s *ValueBetService) factorial(n int) float64 {
-	if n <= 1 {
-		return 1
-	}
-	result := 1.0
-	for i := 2; i <= n; i++ {
-		result *= float64(i)
-	}
-	return result
+// NewValueBetService creates a new ValueBetService instance.
+// settings may be nil; every user then gets DefaultValueBetThreshold.
+func NewValueBetService(store ValueBetStore, probabilities ProbabilityModel, settings ValueBetSettingsStore) ValueBetService {
+	return &valueBetService{store: store, probabilities: probabilities, settings: settings}
 }
 
-// CalculateValue calculates value percentage.
-func (s *ValueBetService) CalculateValue(trueProbability, odds float64) float64 {
-	impliedProbability := 1 / odds
-	value := ((trueProbability * odds) - 1) * 100
-	
-	// Only positive value bets
-	if value < 0 {
-		return 0
-	}
-	
-	return value
+// Detect finds value bets above the default threshold.
+func (s *valueBetService) Detect(matchID uuid.UUID) ([]model.ValueBet, error) {
+	return s.DetectAbove(matchID, DefaultValueBetThreshold)
 }
 
-// CalculateKellyStake calculates optimal stake using Kelly Criterion.
-func (s *ValueBetService) CalculateKellyStake(bankroll, odds, winProbability float64) float64 {
-	b := odds - 1
-	p := winProbability
-	q := 1 - p
-
-	kellyFraction := (b*p - q) / b
-
-	// Fractional Kelly (25% for safety)
-	fractionalKelly := 0.25
-	stake := bankroll * kellyFraction * fractionalKelly
+// DetectAbove compares the latest price of each bookmaker's outcomes with the
+// model's true probability. Value is trueProb*odds - 1, in percent. Every
+// positive-value bet replaces the match's stored value bets; those worth at
+// least threshold are returned, best first.
+func (s *valueBetService) DetectAbove(matchID uuid.UUID, threshold float64) ([]model.ValueBet, error) {
+	ctx := context.Background()
 
-	// Don't bet if Kelly is negative
-	if stake < 0 {
-		return 0
+	match, err := s.store.GetMatch(ctx, matchID)
+	if err != nil {
+		return nil, ErrMatchNotFound
 	}
-
-	return stake
-}
-
-// DetectValueBets detects value betting opportunities.
-func (s *ValueBetService) DetectValueBets(ctx context.Context, minValue float64) ([]model.ValueBet, error) {
-	// This would integrate with odds API to get current odds
-	// For now, return existing value bets above threshold
-	valueBets, err := s.valueBetRepo.GetActiveValueBets(ctx, minValue)
+	odds, err := s.store.GetMatchOdds(ctx, matchID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get value bets: %w", err)
+		return nil, err
 	}
 
-	return valueBets, nil
-}
+	now := time.Now()
+	var detected []model.ValueBet
+	for _, book := range latestBookOdds(odds) {
+		implied := marginFreeProbabilities(book)
+		for i, odd := range book {
+			trueProb, ok := s.probabilities.TrueProbability(match, odd.Market, odd.Outcome)
+			if !ok {
+				continue
+			}
+			value := (trueProb*odd.Price - 1) * 100
+			if value <= 0 {
+				continue
+			}
+			detected = append(detected, model.ValueBet{
+				ID:                 uuid.New(),
+				MatchID:            match.ID,
+				Market:             odd.Market,
+				Selection:          odd.Outcome,
+				Bookmaker:          odd.Bookmaker,
+				BookmakerOdds:      odd.Price,
+				TrueProbability:    trueProb,
+				ImpliedProbability: implied[i],
+				ValuePercent:       value,
+				ExpiresAt:          match.StartTime,
+				CreatedAt:          now,
+			})
+		}
+	}
+	sort.SliceStable(detected, func(i, j int) bool {
+		return detected[i].ValuePercent > detected[j].ValuePercent
+	})
 
-// GetTopValueBets retrieves the best value betting opportunities.
-func (s *ValueBetService) GetTopValueBets(ctx context.Context, limit int) ([]model.ValueBet, error) {
-	valueBets, err := s.valueBetRepo.GetTopValueBets(ctx, limit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get top value bets: %w", err)
+	if err := s.store.ReplaceMatchValueBets(ctx, matchID, detected); err != nil {
+		return nil, err
 	}
+	ValueBetsDetectedTotal.Add(uint64(len(detected)))
 
-	return valueBets, nil
+	bets := make([]model.ValueBet, 0, len(detected))
+	for _, bet := range detected {
+		if bet.ValuePercent >= threshold {
+			bets = append(bets, bet)
+		}
+	}
+	return bets, nil
 }
 
-// GetValueBetsByLeague retrieves value bets for a specific league.
-func (s *ValueBetService) GetValueBetsByLeague(ctx context.Context, league string) ([]model.ValueBet, error) {
-	valueBets, err := s.valueBetRepo.GetValueBetsByLeague(ctx, league)
+// Threshold returns the user's value bet threshold.
+func (s *valueBetService) Threshold(ctx context.Context, userID uuid.UUID) (float64, error) {
+	if s.settings == nil {
+		return DefaultValueBetThreshold, nil
+	}
+	settings, err := s.settings.GetUserSettings(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get value bets by league: %w", err)
+		return 0, err
 	}
-
-	return valueBets, nil
+	if settings.ValueBetThreshold <= 0 {
+		return DefaultValueBetThreshold, nil
+	}
+	return settings.ValueBetThreshold, nil
 }
 
-// ExpireOldValueBets cleans up expired value bets.
-func (s *ValueBetService) ExpireOldValueBets(ctx context.Context) error {
-	if err := s.valueBetRepo.ExpireOldValueBets(ctx); err != nil {
-		return fmt.Errorf("failed to expire old value bets: %w", err)
+// latestBookOdds groups odds by bookmaker and market, keeping the most recent
+// price of each outcome. Groups are ordered by bookmaker and market, and
+// outcomes by name.
+func latestBookOdds(odds []model.Odds) [][]model.Odds {
+	type bookKey struct{ bookmaker, market string }
+	latest := make(map[bookKey]map[string]model.Odds)
+	for _, odd := range odds {
+		if odd.Price <= 1 {
+			continue
+		}
+		key := bookKey{odd.Bookmaker, odd.Market}
+		if latest[key] == nil {
+			latest[key] = make(map[string]model.Odds)
+		}
+		if prev, ok := latest[key][odd.Outcome]; !ok || odd.CreatedAt.After(prev.CreatedAt) {
+			latest[key][odd.Outcome] = odd
+		}
 	}
 
-	s.logger.Info().Msg("Expired old value bets")
-	return nil
+	keys := make([]bookKey, 0, len(latest))
+	for key := range latest {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].bookmaker != keys[j].bookmaker {
+			return keys[i].bookmaker < keys[j].bookmaker
+		}
+		return keys[i].market < keys[j].market
+	})
+
+	books := make([][]model.Odds, len(keys))
+	for i, key := range keys {
+		for _, odd := range latest[key] {
+			books[i] = append(books[i], odd)
+		}
+		sort.Slice(books[i], func(a, b int) bool { return books[i][a].Outcome < books[i][b].Outcome })
+	}
+	return books
 }
 
-// GetValueBetStatistics retrieves value bet statistics.
-func (s *ValueBetService) GetValueBetStatistics(ctx context.Context, period string) (map[string]interface{}, error) {
-	stats, err := s.valueBetRepo.GetValueBetStatistics(ctx, period)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get statistics: %w", err)
+// marginFreeProbabilities returns the implied probability 1/price of each
+// outcome in a bookmaker's market, divided by their sum to strip the
+// bookmaker's margin. A market with a single quoted outcome cannot be
+// normalized and keeps 1/price.
+func marginFreeProbabilities(book []model.Odds) []float64 {
+	implied := make([]float64, len(book))
+	var total float64
+	for i, odd := range book {
+		implied[i] = 1 / odd.Price
+		total += implied[i]
 	}
-
-	return stats, nil
+	if len(book) < 2 {
+		return implied
+	}
+	for i := range implied {
+		implied[i] /= total
+	}
+	return implied
 }
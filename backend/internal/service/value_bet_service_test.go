@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// memoryValueBetStore holds one match, its odds and the stored value bets.
+type memoryValueBetStore struct {
+	match  model.Match
+	odds   []model.Odds
+	stored []model.ValueBet
+}
+
+func (m *memoryValueBetStore) GetMatch(ctx context.Context, matchID uuid.UUID) (*model.Match, error) {
+	if matchID != m.match.ID {
+		return nil, errors.New("record not found")
+	}
+	match := m.match
+	return &match, nil
+}
+
+func (m *memoryValueBetStore) GetMatchOdds(ctx context.Context, matchID uuid.UUID) ([]model.Odds, error) {
+	return m.odds, nil
+}
+
+func (m *memoryValueBetStore) ReplaceMatchValueBets(ctx context.Context, matchID uuid.UUID, bets []model.ValueBet) error {
+	m.stored = bets
+	return nil
+}
+
+// fixedProbabilities gives 1X2 outcomes fixed true probabilities.
+type fixedProbabilities map[string]float64
+
+func (f fixedProbabilities) TrueProbability(match *model.Match, market, outcome string) (float64, bool) {
+	if market != MarketMatchResult {
+		return 0, false
+	}
+	prob, ok := f[outcome]
+	return prob, ok
+}
+
+// valueBetFixture is a match priced by two bookmakers. Against the fixed
+// probabilities Bet365's home price is worth 12.5%, Pinnacle's draw 10% and
+// its away price 8%; every other price has no value.
+func valueBetFixture() (*memoryValueBetStore, fixedProbabilities) {
+	matchID := uuid.New()
+	now := time.Date(2024, 12, 7, 12, 0, 0, 0, time.UTC)
+	odd := func(bookmaker, market, outcome string, price float64, age time.Duration) model.Odds {
+		return model.Odds{ID: uuid.New(), MatchID: matchID, Bookmaker: bookmaker, Market: market, Outcome: outcome, Price: price, CreatedAt: now.Add(-age)}
+	}
+
+	store := &memoryValueBetStore{
+		match: model.Match{ID: matchID, StartTime: now.Add(6 * time.Hour)},
+		odds: []model.Odds{
+			odd("Bet365", "1X2", "1", 2.5, 0),
+			odd("Bet365", "1X2", "1", 3.0, time.Hour), // superseded
+			odd("Bet365", "1X2", "X", 3.4, 0),
+			odd("Bet365", "1X2", "2", 3.0, 0),
+			odd("Pinnacle", "1X2", "1", 2.2, 0),
+			odd("Pinnacle", "1X2", "X", 4.4, 0),
+			odd("Pinnacle", "1X2", "2", 3.6, 0),
+			odd("Pinnacle", "O/U 2.5", "Over", 3.0, 0), // no model estimate
+		},
+	}
+	return store, fixedProbabilities{"1": 0.45, "X": 0.25, "2": 0.30}
+}
+
+func TestValueBetService_DetectAbove(t *testing.T) {
+	store, probabilities := valueBetFixture()
+	svc := NewValueBetService(store, probabilities, nil)
+
+	bets, err := svc.DetectAbove(store.match.ID, 9)
+	if err != nil {
+		t.Fatalf("DetectAbove() error = %v", err)
+	}
+
+	want := []struct {
+		bookmaker, selection string
+		odds, value, implied float64
+	}{
+		{"Bet365", "1", 2.5, 12.5, (1 / 2.5) / (1/2.5 + 1/3.4 + 1/3.0)},
+		{"Pinnacle", "X", 4.4, 10, (1 / 4.4) / (1/2.2 + 1/4.4 + 1/3.6)},
+	}
+	if len(bets) != len(want) {
+		t.Fatalf("DetectAbove() returned %d bets, want %d: %+v", len(bets), len(want), bets)
+	}
+	for i, w := range want {
+		bet := bets[i]
+		if bet.Bookmaker != w.bookmaker || bet.Selection != w.selection || bet.BookmakerOdds != w.odds {
+			t.Errorf("bet %d = %s %s @ %v, want %s %s @ %v", i, bet.Bookmaker, bet.Selection, bet.BookmakerOdds, w.bookmaker, w.selection, w.odds)
+		}
+		if math.Abs(bet.ValuePercent-w.value) > 1e-9 {
+			t.Errorf("bet %d value = %v, want %v", i, bet.ValuePercent, w.value)
+		}
+		if math.Abs(bet.ImpliedProbability-w.implied) > 1e-9 {
+			t.Errorf("bet %d implied probability = %v, want %v", i, bet.ImpliedProbability, w.implied)
+		}
+		if bet.MatchID != store.match.ID || bet.Market != "1X2" || !bet.ExpiresAt.Equal(store.match.StartTime) {
+			t.Errorf("bet %d = %+v, want the match's 1X2 market expiring at kick-off", i, bet)
+		}
+	}
+
+	if len(store.stored) != 3 || store.stored[2].Selection != "2" || math.Abs(store.stored[2].ValuePercent-8) > 1e-9 {
+		t.Errorf("stored %+v, want every positive-value bet", store.stored)
+	}
+}
+
+func TestValueBetService_Detect(t *testing.T) {
+	store, probabilities := valueBetFixture()
+	svc := NewValueBetService(store, probabilities, nil)
+
+	bets, err := svc.Detect(store.match.ID)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if len(bets) != 3 {
+		t.Errorf("Detect() returned %d bets, want 3 above the default threshold", len(bets))
+	}
+
+	if _, err := svc.Detect(uuid.New()); err != ErrMatchNotFound {
+		t.Errorf("Detect() of an unknown match error = %v, want %v", err, ErrMatchNotFound)
+	}
+}
+
+func TestMarginFreeProbabilities(t *testing.T) {
+	book := []model.Odds{{Price: 1.9}, {Price: 1.9}}
+	implied := marginFreeProbabilities(book)
+	if math.Abs(implied[0]-0.5) > 1e-9 || math.Abs(implied[1]-0.5) > 1e-9 {
+		t.Errorf("marginFreeProbabilities() = %v, want [0.5 0.5]", implied)
+	}
+
+	single := marginFreeProbabilities([]model.Odds{{Price: 4}})
+	if single[0] != 0.25 {
+		t.Errorf("marginFreeProbabilities() of one outcome = %v, want [0.25]", single)
+	}
+}
@@ -2,254 +2,189 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"time"
 
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/repository"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
 
-	"super-dashboard/backend/internal/model"
-	"super-dashboard/backend/internal/repository"
+// Watchlist service errors. ErrWatchlistNotFound is shared with the bulk service.
+var (
+	// ErrWatchlistForbidden is returned when a user acts on another user's watchlist.
+	ErrWatchlistForbidden    = errors.New("watchlist belongs to another user")
+	ErrWatchlistItemExists   = errors.New("stock is already in the watchlist")
+	ErrWatchlistItemNotFound = errors.New("stock is not in the watchlist")
+	ErrWatchlistNameRequired = errors.New("watchlist name is required")
 )
 
-// WatchlistService handles watchlist operations.
-type WatchlistService struct {
-	watchlistRepo *repository.WatchlistRepository
-	stockRepo     *repository.StockRepository
-	logger        zerolog.Logger
+// WatchlistQuote is the live quote for a watchlist item's stock.
+type WatchlistQuote struct {
+	Price         float64 `json:"price"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
 }
 
-// NewWatchlistService creates a new WatchlistService.
-func NewWatchlistService(
-	watchlistRepo *repository.WatchlistRepository,
-	stockRepo *repository.StockRepository,
-	logger zerolog.Logger,
-) *WatchlistService {
-	return &WatchlistService{
-		watchlistRepo: watchlistRepo,
-		stockRepo:     stockRepo,
-		logger:        logger.With().Str("service", "watchlist").Logger(),
-	}
+// WatchlistItemQuote is a watchlist item with its stock's current quote.
+type WatchlistItemQuote struct {
+	model.WatchlistItem
+	// Quote is omitted when no live quote is available for the stock.
+	Quote *WatchlistQuote `json:"quote,omitempty"`
+}
+
+// WatchlistDetail is a watchlist whose items carry current quotes.
+type WatchlistDetail struct {
+	model.Watchlist
+	Items []WatchlistItemQuote `json:"items"`
+}
+
+// WatchlistService defines the interface for managing a user's watchlists.
+type WatchlistService interface {
+	Create(ctx context.Context, userID uuid.UUID, name, description string) (*model.Watchlist, error)
+	List(ctx context.Context, userID uuid.UUID) ([]model.Watchlist, error)
+	// Get returns one of the user's watchlists with current quotes.
+	Get(ctx context.Context, userID, watchlistID uuid.UUID) (*WatchlistDetail, error)
+	Delete(ctx context.Context, userID, watchlistID uuid.UUID) error
+	AddItem(ctx context.Context, userID, watchlistID, stockID uuid.UUID, notes string) (*model.WatchlistItem, error)
+	RemoveItem(ctx context.Context, userID, watchlistID, stockID uuid.UUID) error
+}
+
+// watchlistService implements WatchlistService.
+type watchlistService struct {
+	repo   repository.WatchlistRepository
+	quotes QuoteSource
 }
 
-// CreateWatchlist creates a new watchlist.
-func (s *WatchlistService) CreateWatchlist(ctx context.Context, userID uuid.UUID, name, description string) (*model.Watchlist, error) {
+// NewWatchlistService creates a new WatchlistService instance. Without a
+// quote source, watchlists are returned without quotes.
+func NewWatchlistService(repo repository.WatchlistRepository, quotes QuoteSource) WatchlistService {
+	return &watchlistService{repo: repo, quotes: quotes}
+}
+
+// Create creates a watchlist for the user.
+func (s *watchlistService) Create(ctx context.Context, userID uuid.UUID, name, description string) (*model.Watchlist, error) {
 	if name == "" {
-		return nil, fmt.Errorf("watchlist name is required")
+		return nil, ErrWatchlistNameRequired
 	}
 
 	watchlist := &model.Watchlist{
+		ID:          uuid.New(),
 		UserID:      userID,
 		Name:        name,
 		Description: description,
 	}
-
-	if err := s.watchlistRepo.CreateWatchlist(ctx, watchlist); err != nil {
-		return nil, fmt.Errorf("failed to create watchlist: %w", err)
+	if err := s.repo.Create(watchlist); err != nil {
+		return nil, err
 	}
-
-	s.logger.Info().
-		Str("watchlist_id", watchlist.ID.String()).
-		Str("name", name).
-		Msg("Watchlist created")
-
 	return watchlist, nil
 }
 
-// GetUserWatchlists retrieves all watchlists for a user.
-func (s *WatchlistService) GetUserWatchlists(ctx context.Context, userID uuid.UUID) ([]model.Watchlist, error) {
-	watchlists, err := s.watchlistRepo.GetUserWatchlists(ctx, userID)
+// List returns the user's watchlists, newest first.
+func (s *watchlistService) List(ctx context.Context, userID uuid.UUID) ([]model.Watchlist, error) {
+	watchlists, err := s.repo.GetByUserID(userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get watchlists: %w", err)
+		return nil, err
 	}
-
-	return watchlists, nil
-}
-
-// GetWatchlist retrieves a watchlist by ID.
-func (s *WatchlistService) GetWatchlist(ctx context.Context, id uuid.UUID) (*model.Watchlist, error) {
-	watchlist, err := s.watchlistRepo.GetWatchlistByID(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	if watchlists == nil {
+		watchlists = []model.Watchlist{}
 	}
-
-	return watchlist, nil
+	return watchlists, nil
 }
 
-// UpdateWatchlist updates watchlist details.
-func (s *WatchlistService) UpdateWatchlist(ctx context.Context, id uuid.UUID, name, description string) error {
-	watchlist, err := s.watchlistRepo.GetWatchlistByID(ctx, id)
+// Get fetches a quote for each item's stock. Items whose quote cannot be
+// fetched are returned without one.
+func (s *watchlistService) Get(ctx context.Context, userID, watchlistID uuid.UUID) (*WatchlistDetail, error) {
+	watchlist, err := s.ownedWatchlist(userID, watchlistID)
 	if err != nil {
-		return fmt.Errorf("watchlist not found: %w", err)
-	}
-
-	if name != "" {
-		watchlist.Name = name
+		return nil, err
 	}
-	watchlist.Description = description
 
-	if err := s.watchlistRepo.UpdateWatchlist(ctx, watchlist); err != nil {
-		return fmt.Errorf("failed to update watchlist: %w", err)
+	detail := &WatchlistDetail{Watchlist: *watchlist, Items: make([]WatchlistItemQuote, 0, len(watchlist.Items))}
+	detail.Watchlist.Items = nil
+	for _, item := range watchlist.Items {
+		detail.Items = append(detail.Items, WatchlistItemQuote{WatchlistItem: item, Quote: s.quote(ctx, item.Stock.Symbol)})
 	}
-
-	s.logger.Info().Str("watchlist_id", id.String()).Msg("Watchlist updated")
-
-	return nil
+	return detail, nil
 }
 
-// DeleteWatchlist deletes a watchlist.
-func (s *WatchlistService) DeleteWatchlist(ctx context.Context, id uuid.UUID) error {
-	if err := s.watchlistRepo.DeleteWatchlist(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete watchlist: %w", err)
+// Delete deletes one of the user's watchlists with its items.
+func (s *watchlistService) Delete(ctx context.Context, userID, watchlistID uuid.UUID) error {
+	if _, err := s.ownedWatchlist(userID, watchlistID); err != nil {
+		return err
 	}
-
-	s.logger.Info().Str("watchlist_id", id.String()).Msg("Watchlist deleted")
-
-	return nil
+	return s.repo.Delete(watchlistID)
 }
 
-// AddStock adds a stock to a watchlist.
-func (s *WatchlistService) AddStock(ctx context.Context, watchlistID uuid.UUID, symbol string) error {
-	// Get stock by symbol
-	stock, err := s.stockRepo.GetBySymbol(ctx, symbol)
+// AddItem adds a stock to one of the user's watchlists. A stock can be in a
+// watchlist only once.
+func (s *watchlistService) AddItem(ctx context.Context, userID, watchlistID, stockID uuid.UUID, notes string) (*model.WatchlistItem, error) {
+	watchlist, err := s.ownedWatchlist(userID, watchlistID)
 	if err != nil {
-		return fmt.Errorf("stock not found: %w", err)
+		return nil, err
 	}
-
-	if err := s.watchlistRepo.AddStockToWatchlist(ctx, watchlistID, stock.ID); err != nil {
-		return fmt.Errorf("failed to add stock: %w", err)
+	for _, item := range watchlist.Items {
+		if item.StockID == stockID {
+			return nil, ErrWatchlistItemExists
+		}
 	}
 
-	s.logger.Info().
-		Str("watchlist_id", watchlistID.String()).
-		Str("symbol", symbol).
-		Msg("Stock added to watchlist")
-
-	return nil
-}
-
-// RemoveStock removes a stock from a watchlist.
-func (s *WatchlistService) RemoveStock(ctx context.Context, watchlistID uuid.UUID, symbol string) error {
-	// Get stock by symbol
-	stock, err := s.stockRepo.GetBySymbol(ctx, symbol)
+	stock, err := s.repo.GetStock(stockID)
 	if err != nil {
-		return fmt.Errorf("stock not found: %w", err)
+		return nil, ErrStockNotFound
 	}
 
-	if err := s.watchlistRepo.RemoveStockFromWatchlist(ctx, watchlistID, stock.ID); err != nil {
-		return fmt.Errorf("failed to remove stock: %w", err)
+	item := &model.WatchlistItem{
+		ID:          uuid.New(),
+		WatchlistID: watchlistID,
+		StockID:     stockID,
+		Notes:       notes,
+		AddedAt:     time.Now(),
 	}
-
-	s.logger.Info().
-		Str("watchlist_id", watchlistID.String()).
-		Str("symbol", symbol).
-		Msg("Stock removed from watchlist")
-
-	return nil
-}
-
-// GetWatchlistStocks retrieves all stocks in a watchlist with current prices.
-func (s *WatchlistService) GetWatchlistStocks(ctx context.Context, watchlistID uuid.UUID) ([]map[string]interface{}, error) {
-	stocks, err := s.watchlistRepo.GetWatchlistStocks(ctx, watchlistID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get stocks: %w", err)
+	if err := s.repo.AddItem(item); err != nil {
+		return nil, err
 	}
-
-	result := make([]map[string]interface{}, 0, len(stocks))
-	for _, stock := range stocks {
-		result = append(result, map[string]interface{}{
-			"id":             stock.ID,
-			"symbol":         stock.Symbol,
-			"name":           stock.Name,
-			"current_price":  stock.CurrentPrice,
-			"change_percent": stock.ChangePercent,
-			"volume":         stock.Volume,
-			"market_cap":     stock.MarketCap,
-		})
-	}
-
-	return result, nil
+	item.Stock = *stock
+	return item, nil
 }
 
-// UpdateStockNotes updates notes for a stock in a watchlist.
-func (s *WatchlistService) UpdateStockNotes(ctx context.Context, watchlistID uuid.UUID, symbol, notes string) error {
-	stock, err := s.stockRepo.GetBySymbol(ctx, symbol)
+// RemoveItem removes a stock from one of the user's watchlists.
+func (s *watchlistService) RemoveItem(ctx context.Context, userID, watchlistID, stockID uuid.UUID) error {
+	watchlist, err := s.ownedWatchlist(userID, watchlistID)
 	if err != nil {
-		return fmt.Errorf("stock not found: %w", err)
+		return err
 	}
-
-	if err := s.watchlistRepo.UpdateWatchlistItemNotes(ctx, watchlistID, stock.ID, notes); err != nil {
-		return fmt.Errorf("failed to update notes: %w", err)
+	for _, item := range watchlist.Items {
+		if item.StockID == stockID {
+			return s.repo.RemoveItem(watchlistID, stockID)
+		}
 	}
-
-	s.logger.Info().
-		Str("watchlist_id", watchlistID.String()).
-		Str("symbol", symbol).
-		Msg("Stock notes updated")
-
-	return nil
+	return ErrWatchlistItemNotFound
 }
 
-// IsStockInWatchlist checks if a stock is in any of user's watchlists.
-func (s *WatchlistService) IsStockInWatchlist(ctx context.Context, userID uuid.UUID, symbol string) (bool, error) {
-	stock, err := s.stockRepo.GetBySymbol(ctx, symbol)
+// ownedWatchlist returns the watchlist, ErrWatchlistNotFound if it does not
+// exist or ErrWatchlistForbidden if it belongs to another user.
+func (s *watchlistService) ownedWatchlist(userID, watchlistID uuid.UUID) (*model.Watchlist, error) {
+	watchlist, err := s.repo.GetByID(watchlistID)
 	if err != nil {
-		return false, fmt.Errorf("stock not found: %w", err)
+		return nil, ErrWatchlistNotFound
 	}
-
-	watchlists, err := s.watchlistRepo.GetStockWatchlists(ctx, userID, stock.ID)
-	if err != nil {
-		return false, fmt.Errorf("failed to check watchlists: %w", err)
+	if watchlist.UserID != userID {
+		return nil, ErrWatchlistForbidden
 	}
-
-	return len(watchlists) > 0, nil
+	return watchlist, nil
 }
 
-// GetWatchlistSummary retrieves a summary of a watchlist.
-func (s *WatchlistService) GetWatchlistSummary(ctx context.Context, watchlistID uuid.UUID) (map[string]interface{}, error) {
-	watchlist, err := s.watchlistRepo.GetWatchlistByID(ctx, watchlistID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get watchlist: %w", err)
-	}
-
-	count, err := s.watchlistRepo.CountWatchlistItems(ctx, watchlistID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count items: %w", err)
+// quote fetches a live quote for the symbol, or nil if none is available.
+func (s *watchlistService) quote(ctx context.Context, symbol string) *WatchlistQuote {
+	if s.quotes == nil || symbol == "" {
+		return nil
 	}
-
-	stocks, err := s.watchlistRepo.GetWatchlistStocks(ctx, watchlistID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get stocks: %w", err)
-	}
-
-	// Calculate total value and performance
-	totalValue := 0.0
-	totalChange := 0.0
-	gainers := 0
-	losers := 0
-
-	for _, stock := range stocks {
-		totalValue += stock.CurrentPrice
-		totalChange += stock.ChangePercent
-		if stock.ChangePercent > 0 {
-			gainers++
-		} else if stock.ChangePercent < 0 {
-			losers++
-		}
+	quote, err := s.quotes.GetQuote(ctx, symbol)
+	if err != nil || quote.Price <= 0 {
+		log.Warn().Err(err).Str("symbol", symbol).Msg("No live quote for watchlist item")
+		return nil
 	}
-
-	avgChange := 0.0
-	if len(stocks) > 0 {
-		avgChange = totalChange / float64(len(stocks))
-	}
-
-	return map[string]interface{}{
-		"watchlist_id":   watchlist.ID,
-		"name":           watchlist.Name,
-		"description":    watchlist.Description,
-		"total_stocks":   count,
-		"total_value":    totalValue,
-		"avg_change":     avgChange,
-		"gainers":        gainers,
-		"losers":         losers,
-	}, nil
+	return &WatchlistQuote{Price: quote.Price, Change: quote.Change, ChangePercent: quote.ChangePercent}
 }
@@ -54,8 +54,6 @@ func ImpliedProbability(odds float64) float64 {
 // TrueProbabilityFromOdds removes bookmaker margin to get true probability.
 func TrueProbabilityFromOdds(homeOdds, drawOdds, awayOdds float64) (float64, float64, float64) {
 	totalImplied := ImpliedProbability(homeOdds) + ImpliedProbability(drawOdds) + ImpliedProbability(awayOdds)
-	margin := totalImplied - 1
-
 	// Remove margin proportionally
 	homeProb := ImpliedProbability(homeOdds) / totalImplied
 	drawProb := ImpliedProbability(drawOdds) / totalImplied
@@ -71,15 +69,6 @@ func ValueBetPercentage(trueProbability, odds float64) float64 {
 	return value * 100
 }
 
-// PoissonProbability calculates Poisson probability for exact k events.
-// P(k) = (λ^k × e^(-λ)) / k!
-func PoissonProbability(lambda float64, k int) float64 {
-	if lambda <= 0 {
-		return 0
-	}
-	return (math.Pow(lambda, float64(k)) * math.Exp(-lambda)) / factorial(float64(k))
-}
-
 // PoissonUnderGoals calculates probability of under X goals.
 func PoissonUnderGoals(expectedGoals float64, threshold int) float64 {
 	probability := 0.0
@@ -104,7 +93,7 @@ func PoissonExactScore(homeExpected, awayExpected float64, homeGoals, awayGoals
 // PoissonCorrectScore calculates probabilities for common scores.
 func PoissonCorrectScore(homeExpected, awayExpected float64) map[string]float64 {
 	scores := make(map[string]float64)
-	
+
 	// Calculate probabilities for scores 0-0 to 5-5
 	for h := 0; h <= 5; h++ {
 		for a := 0; a <= 5; a++ {
@@ -112,7 +101,7 @@ func PoissonCorrectScore(homeExpected, awayExpected float64) map[string]float64
 			scores[scoreKey] = PoissonExactScore(homeExpected, awayExpected, h, a)
 		}
 	}
-	
+
 	return scores
 }
 
@@ -144,11 +133,11 @@ func ELO1X2Probabilities(homeRating, awayRating float64) (float64, float64, floa
 	homeWin := ELOWinProbability(homeRating, awayRating)
 	drawProb := ELODrawProbability(homeRating, awayRating)
 	awayWin := 1 - homeWin - drawProb
-	
+
 	if awayWin < 0 {
 		awayWin = 0
 	}
-	
+
 	// Normalize to sum to 1
 	total := homeWin + drawProb + awayWin
 	return homeWin / total, drawProb / total, awayWin / total
@@ -216,30 +205,30 @@ func ArbitrageProfit(stake float64, odds []float64) float64 {
 	for _, o := range odds {
 		totalImplied += ImpliedProbability(o)
 	}
-	
+
 	// No arbitrage if total implied >= 1
 	if totalImplied >= 1 {
 		return 0
 	}
-	
+
 	// Calculate profit
 	return (1/totalImplied - 1) * stake
 }
 
-// ArbitrageStakes calculates individual stakes for arbitrage.
-func ArbitrageStakes(totalStake float64, odds []float64) []float64 {
+// ArbitrageStakeSplit calculates individual stakes for arbitrage.
+func ArbitrageStakeSplit(totalStake float64, odds []float64) []float64 {
 	stakes := make([]float64, len(odds))
 	totalImplied := 0.0
-	
+
 	for _, o := range odds {
 		totalImplied += ImpliedProbability(o)
 	}
-	
+
 	// Calculate stakes proportionally
 	for i, o := range odds {
 		stakes[i] = (totalStake * ImpliedProbability(o)) / totalImplied
 	}
-	
+
 	return stakes
 }
 
@@ -254,19 +243,19 @@ func VarianceCalculation(results []float64) float64 {
 	if len(results) == 0 {
 		return 0
 	}
-	
+
 	mean := 0.0
 	for _, r := range results {
 		mean += r
 	}
 	mean /= float64(len(results))
-	
+
 	variance := 0.0
 	for _, r := range results {
 		variance += math.Pow(r-mean, 2)
 	}
 	variance /= float64(len(results))
-	
+
 	return variance
 }
 
@@ -297,10 +286,10 @@ func factorial(n float64) float64 {
 // BettingBankrollGrowth models bankroll growth with Kelly betting.
 func BettingBankrollGrowth(initialBankroll, winRate, avgOdds float64, numberOfBets int, fraction float64) float64 {
 	bankroll := initialBankroll
-	
+
 	for i := 0; i < numberOfBets; i++ {
 		stake := FractionalKelly(bankroll, avgOdds, winRate, fraction)
-		
+
 		// Simulate win/loss (simplified)
 		if winRate >= 0.5 { // Win
 			bankroll += stake * (avgOdds - 1)
@@ -308,7 +297,7 @@ func BettingBankrollGrowth(initialBankroll, winRate, avgOdds float64, numberOfBe
 			bankroll -= stake
 		}
 	}
-	
+
 	return bankroll
 }
 
@@ -317,7 +306,7 @@ func OptimalKellyFraction(bankroll, winRate, avgOdds float64, numberOfTrials int
 	fractions := []float64{0.1, 0.25, 0.5, 0.75, 1.0}
 	bestFraction := 0.25
 	bestResult := 0.0
-	
+
 	for _, fraction := range fractions {
 		avgResult := 0.0
 		for i := 0; i < numberOfTrials; i++ {
@@ -325,12 +314,12 @@ func OptimalKellyFraction(bankroll, winRate, avgOdds float64, numberOfTrials int
 			avgResult += result
 		}
 		avgResult /= float64(numberOfTrials)
-		
+
 		if avgResult > bestResult {
 			bestResult = avgResult
 			bestFraction = fraction
 		}
 	}
-	
+
 	return bestFraction
 }
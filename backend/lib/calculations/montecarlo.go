@@ -8,19 +8,19 @@ import (
 
 // MonteCarloResult contains Monte Carlo simulation results.
 type MonteCarloResult struct {
-	Mean               float64
-	Median             float64
-	StandardDeviation  float64
-	Percentile5        float64
-	Percentile25       float64
-	Percentile75       float64
-	Percentile95       float64
-	MinValue           float64
-	MaxValue           float64
-	ProbabilityOfLoss  float64
-	ProbabilityOfGain  float64
-	Simulations        int
-	Distribution       []float64 // Optional: sample of simulated values
+	Mean              float64
+	Median            float64
+	StandardDeviation float64
+	Percentile5       float64
+	Percentile25      float64
+	Percentile75      float64
+	Percentile95      float64
+	MinValue          float64
+	MaxValue          float64
+	ProbabilityOfLoss float64
+	ProbabilityOfGain float64
+	Simulations       int
+	Distribution      []float64 // Optional: sample of simulated values
 }
 
 // MonteCarloConfig contains configuration for Monte Carlo simulation.
@@ -54,8 +54,8 @@ func RunMonteCarloSimulation(config MonteCarloConfig) MonteCarloResult {
 	}
 
 	results := make([]float64, config.Simulations)
-	mu := config.ExpectedReturn / 100    // Convert to decimal
-	sigma := config.Volatility / 100     // Convert to decimal
+	mu := config.ExpectedReturn / 100 // Convert to decimal
+	sigma := config.Volatility / 100  // Convert to decimal
 	dt := config.TimeHorizonYears
 
 	lossCount := 0
@@ -77,40 +77,40 @@ func RunMonteCarloSimulation(config MonteCarloConfig) MonteCarloResult {
 	sort.Float64s(results)
 
 	return MonteCarloResult{
-		Mean:               calculateMean(results),
-		Median:             percentile(results, 50),
-		StandardDeviation:  calculateStdDev(results),
-		Percentile5:        percentile(results, 5),
-		Percentile25:       percentile(results, 25),
-		Percentile75:       percentile(results, 75),
-		Percentile95:       percentile(results, 95),
-		MinValue:           results[0],
-		MaxValue:           results[len(results)-1],
-		ProbabilityOfLoss:  float64(lossCount) / float64(config.Simulations) * 100,
-		ProbabilityOfGain:  float64(config.Simulations-lossCount) / float64(config.Simulations) * 100,
-		Simulations:        config.Simulations,
-		Distribution:       sampleDistribution(results, 100),
+		Mean:              calculateMean(results),
+		Median:            percentile(results, 50),
+		StandardDeviation: calculateStdDev(results),
+		Percentile5:       percentile(results, 5),
+		Percentile25:      percentile(results, 25),
+		Percentile75:      percentile(results, 75),
+		Percentile95:      percentile(results, 95),
+		MinValue:          results[0],
+		MaxValue:          results[len(results)-1],
+		ProbabilityOfLoss: float64(lossCount) / float64(config.Simulations) * 100,
+		ProbabilityOfGain: float64(config.Simulations-lossCount) / float64(config.Simulations) * 100,
+		Simulations:       config.Simulations,
+		Distribution:      sampleDistribution(results, 100),
 	}
 }
 
 // BettingMonteCarloConfig contains config for betting simulation.
 type BettingMonteCarloConfig struct {
-	Simulations    int
+	Simulations     int
 	InitialBankroll float64
-	NumBets        int
-	WinProbability float64  // As percentage (0-100)
-	AverageOdds    float64  // Decimal odds
-	StakePercent   float64  // Percentage of bankroll per bet
-	Seed           int64
+	NumBets         int
+	WinProbability  float64 // As percentage (0-100)
+	AverageOdds     float64 // Decimal odds
+	StakePercent    float64 // Percentage of bankroll per bet
+	Seed            int64
 }
 
 // BettingMonteCarloResult contains betting simulation results.
 type BettingMonteCarloResult struct {
 	MonteCarloResult
-	AvgFinalBankroll   float64
-	AvgMaxDrawdown     float64
-	RuinProbability    float64 // Probability of going bust
-	DoubleProbability  float64 // Probability of doubling bankroll
+	AvgFinalBankroll  float64
+	AvgMaxDrawdown    float64
+	RuinProbability   float64 // Probability of going bust
+	DoubleProbability float64 // Probability of doubling bankroll
 }
 
 // RunBettingMonteCarlo simulates betting strategies.
@@ -187,19 +187,19 @@ func RunBettingMonteCarlo(config BettingMonteCarloConfig) BettingMonteCarloResul
 
 	return BettingMonteCarloResult{
 		MonteCarloResult: MonteCarloResult{
-			Mean:               calculateMean(results),
-			Median:             percentile(results, 50),
-			StandardDeviation:  calculateStdDev(results),
-			Percentile5:        percentile(results, 5),
-			Percentile25:       percentile(results, 25),
-			Percentile75:       percentile(results, 75),
-			Percentile95:       percentile(results, 95),
-			MinValue:           results[0],
-			MaxValue:           results[len(results)-1],
-			ProbabilityOfLoss:  float64(countBelowThreshold(results, config.InitialBankroll)) / float64(config.Simulations) * 100,
-			ProbabilityOfGain:  float64(countAboveThreshold(results, config.InitialBankroll)) / float64(config.Simulations) * 100,
-			Simulations:        config.Simulations,
-			Distribution:       sampleDistribution(results, 100),
+			Mean:              calculateMean(results),
+			Median:            percentile(results, 50),
+			StandardDeviation: calculateStdDev(results),
+			Percentile5:       percentile(results, 5),
+			Percentile25:      percentile(results, 25),
+			Percentile75:      percentile(results, 75),
+			Percentile95:      percentile(results, 95),
+			MinValue:          results[0],
+			MaxValue:          results[len(results)-1],
+			ProbabilityOfLoss: float64(countBelowThreshold(results, config.InitialBankroll)) / float64(config.Simulations) * 100,
+			ProbabilityOfGain: float64(countAboveThreshold(results, config.InitialBankroll)) / float64(config.Simulations) * 100,
+			Simulations:       config.Simulations,
+			Distribution:      sampleDistribution(results, 100),
 		},
 		AvgFinalBankroll:  calculateMean(results),
 		AvgMaxDrawdown:    calculateMean(drawdowns),
@@ -208,35 +208,48 @@ func RunBettingMonteCarlo(config BettingMonteCarloConfig) BettingMonteCarloResul
 	}
 }
 
-// SharpeRatio calculates the Sharpe ratio.
-// returns: (mean - riskFreeRate) / stdDev
+// TradingDaysPerYear annualizes daily returns.
+const TradingDaysPerYear = 252
+
+// SharpeRatio calculates the annualized Sharpe ratio of daily returns.
+// returns: simple daily returns as fractions, e.g. 0.01 for 1%
+// riskFreeRate: annual risk-free rate as a fraction, e.g. 0.02 for 2%
+// The mean daily excess return over the sample standard deviation is scaled
+// by the square root of TradingDaysPerYear. It is zero with fewer than two
+// returns or no volatility.
 func SharpeRatio(returns []float64, riskFreeRate float64) float64 {
-	if len(returns) == 0 {
+	if len(returns) < 2 {
 		return 0
 	}
-	mean := calculateMean(returns)
 	stdDev := calculateStdDev(returns)
 	if stdDev == 0 {
 		return 0
 	}
-	return (mean - riskFreeRate) / stdDev
+	excess := calculateMean(returns) - riskFreeRate/TradingDaysPerYear
+	return excess / stdDev * math.Sqrt(TradingDaysPerYear)
 }
 
-// MaxDrawdown calculates the maximum drawdown from a series of values.
-func MaxDrawdown(values []float64) float64 {
-	if len(values) < 2 {
+// MaxDrawdown calculates the largest fall of an equity curve from a running
+// peak, in percent of that peak.
+// equity: account values in time order, e.g. one per daily close
+// Falls from a non-positive peak have no meaningful percentage and are
+// skipped, so a rising curve or one with fewer than two values gives zero.
+func MaxDrawdown(equity []float64) float64 {
+	if len(equity) < 2 {
 		return 0
 	}
 
-	maxSoFar := values[0]
+	peak := equity[0]
 	maxDrawdown := 0.0
 
-	for _, v := range values {
-		if v > maxSoFar {
-			maxSoFar = v
+	for _, v := range equity {
+		if v > peak {
+			peak = v
+		}
+		if peak <= 0 {
+			continue
 		}
-		dd := (maxSoFar - v) / maxSoFar * 100
-		if dd > maxDrawdown {
+		if dd := (peak - v) / peak * 100; dd > maxDrawdown {
 			maxDrawdown = dd
 		}
 	}
@@ -244,10 +257,13 @@ func MaxDrawdown(values []float64) float64 {
 	return maxDrawdown
 }
 
-// ValueAtRisk calculates VaR at a given confidence level.
+// ValueAtRisk calculates historical VaR at a given confidence level: the
+// return at the (100 - confidence)th percentile, negative for a loss.
 // confidence: e.g., 95 for 95% VaR
+// A single return says nothing about its distribution, so fewer than two
+// returns give 0.
 func ValueAtRisk(returns []float64, confidence float64) float64 {
-	if len(returns) == 0 {
+	if len(returns) < 2 {
 		return 0
 	}
 	sorted := make([]float64, len(returns))
@@ -256,6 +272,27 @@ func ValueAtRisk(returns []float64, confidence float64) float64 {
 	return percentile(sorted, 100-confidence)
 }
 
+// ConditionalVaR calculates historical CVaR (Expected Shortfall): the mean
+// of the returns at or below ValueAtRisk at the same confidence.
+// confidence: e.g., 95 for 95% CVaR
+func ConditionalVaR(returns []float64, confidence float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+	threshold := ValueAtRisk(returns, confidence)
+
+	sum := 0.0
+	count := 0
+	for _, r := range returns {
+		if r <= threshold {
+			sum += r
+			count++
+		}
+	}
+	// The threshold is at least the smallest return, so count > 0
+	return sum / float64(count)
+}
+
 // Helper functions
 
 func calculateMean(values []float64) float64 {
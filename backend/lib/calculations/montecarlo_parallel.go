@@ -0,0 +1,56 @@
+package calculations
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// ParallelSimulationThreshold is the simulation count from which
+// MonteCarloSimulation spreads the paths across CPUs.
+const ParallelSimulationThreshold = 100000
+
+// simulationChunkSize is how many paths share one RNG source in
+// ParallelMonteCarloSimulation. Fixed chunks make seeded results the same
+// whatever the number of CPUs.
+const simulationChunkSize = 10000
+
+// ParallelMonteCarloSimulation runs the same simulation as
+// MonteCarloSimulation on runtime.NumCPU() goroutines. The paths are split
+// into chunks, each with its own source seeded in order from params.Rand
+// (or math/rand's shared source when nil), so a seeded run is reproducible
+// but draws different paths than the single-threaded version.
+func ParallelMonteCarloSimulation(params MonteCarloParams) PriceSimulationResult {
+	finalPrices := make([]float64, params.NumSimulations)
+
+	seeds := make([]int64, (len(finalPrices)+simulationChunkSize-1)/simulationChunkSize)
+	for i := range seeds {
+		if params.Rand != nil {
+			seeds[i] = params.Rand.Int63()
+		} else {
+			seeds[i] = rand.Int63()
+		}
+	}
+
+	chunks := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.NumCPU(), len(seeds)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				start := chunk * simulationChunkSize
+				end := min(start+simulationChunkSize, len(finalPrices))
+				rng := rand.New(rand.NewSource(seeds[chunk]))
+				simulatePrices(params, rng.NormFloat64, finalPrices[start:end])
+			}
+		}()
+	}
+	for chunk := range seeds {
+		chunks <- chunk
+	}
+	close(chunks)
+	wg.Wait()
+
+	return summarizePrices(params.InitialPrice, finalPrices)
+}
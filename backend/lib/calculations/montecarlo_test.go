@@ -83,21 +83,24 @@ func TestRunBettingMonteCarlo(t *testing.T) {
 }
 
 func TestSharpeRatio(t *testing.T) {
-	// Returns: 10%, 15%, 5%, 20%, 8% with 2% risk-free rate
-	returns := []float64{10, 15, 5, 20, 8}
-	riskFreeRate := 2.0
+	// Daily returns of 1%, -1%, 2% and 0%: mean 0.5%, sample variance
+	// 0.0005 / 3, so the annualized ratio is 0.005 * sqrt(6000 * 252).
+	returns := []float64{0.01, -0.01, 0.02, 0}
 
-	sharpe := SharpeRatio(returns, riskFreeRate)
+	if sharpe, want := SharpeRatio(returns, 0), math.Sqrt(37.8); math.Abs(sharpe-want) > 1e-9 {
+		t.Errorf("SharpeRatio() = %v, expected %v", sharpe, want)
+	}
 
-	// Mean = 11.6, StdDev ≈ 5.68
-	// Sharpe = (11.6 - 2) / 5.68 ≈ 1.69
-	if sharpe < 1 || sharpe > 3 {
-		t.Errorf("Sharpe ratio %v seems unreasonable", sharpe)
+	// A 2.52% annual risk-free rate is 0.01% a day.
+	if sharpe, want := SharpeRatio(returns, 0.0252), 0.0049*math.Sqrt(6000*252); math.Abs(sharpe-want) > 1e-9 {
+		t.Errorf("SharpeRatio() with a risk-free rate = %v, expected %v", sharpe, want)
 	}
 
-	// Empty returns
-	if SharpeRatio([]float64{}, 2) != 0 {
-		t.Error("Sharpe should be 0 for empty returns")
+	// Too few returns or no volatility
+	for _, r := range [][]float64{{}, {0.01}, {0.01, 0.01, 0.01}} {
+		if sharpe := SharpeRatio(r, 0); sharpe != 0 {
+			t.Errorf("SharpeRatio(%v) = %v, expected 0", r, sharpe)
+		}
 	}
 }
 
@@ -124,6 +127,12 @@ func TestMaxDrawdown(t *testing.T) {
 	if MaxDrawdown([]float64{100}) != 0 {
 		t.Error("Max drawdown should be 0 for single value")
 	}
+
+	// Falls from a zero peak are skipped rather than dividing by zero
+	dd = MaxDrawdown([]float64{0, -10, 50, 40})
+	if math.Abs(dd-20) > 1e-9 {
+		t.Errorf("Max drawdown from a zero start = %v, expected 20", dd)
+	}
 }
 
 func TestValueAtRisk(t *testing.T) {
@@ -142,6 +151,54 @@ func TestValueAtRisk(t *testing.T) {
 	if ValueAtRisk([]float64{}, 95) != 0 {
 		t.Error("VaR should be 0 for empty returns")
 	}
+	if ValueAtRisk([]float64{-5}, 95) != 0 {
+		t.Error("VaR should be 0 for a single return")
+	}
+}
+
+func TestValueAtRisk_KnownLosses(t *testing.T) {
+	// Daily returns of -50% to +50% in 1% steps: the 5th percentile of the
+	// 101 sorted returns falls exactly on -45%, and the six returns at or
+	// below it average -47.5%.
+	returns := make([]float64, 0, 101)
+	for r := 50; r >= -50; r-- {
+		returns = append(returns, float64(r))
+	}
+
+	if got := ValueAtRisk(returns, 95); got != -45 {
+		t.Errorf("ValueAtRisk(95) = %v, expected -45", got)
+	}
+	if got := ConditionalVaR(returns, 95); got != -47.5 {
+		t.Errorf("ConditionalVaR(95) = %v, expected -47.5", got)
+	}
+	if got := ValueAtRisk(returns, 99); got != -49 {
+		t.Errorf("ValueAtRisk(99) = %v, expected -49", got)
+	}
+	if got := ConditionalVaR(returns, 99); got != -49.5 {
+		t.Errorf("ConditionalVaR(99) = %v, expected -49.5", got)
+	}
+}
+
+func TestConditionalVaR(t *testing.T) {
+	// One crash among small moves: VaR interpolates towards it, and CVaR is
+	// the crash alone.
+	returns := []float64{1, -1, 2, -2, 0.5, -30, 1.5, -0.5, 0, 1}
+	if got := ConditionalVaR(returns, 95); got != -30 {
+		t.Errorf("ConditionalVaR() = %v, expected -30", got)
+	}
+	if cvar, v := ConditionalVaR(returns, 90), ValueAtRisk(returns, 90); cvar > v {
+		t.Errorf("ConditionalVaR() = %v, expected at or below VaR %v", cvar, v)
+	}
+	if got := ConditionalValueAtRisk(returns, 0.95); got != -30 {
+		t.Errorf("ConditionalValueAtRisk() = %v, expected the same -30 from a 0-1 confidence", got)
+	}
+
+	if ConditionalVaR([]float64{}, 95) != 0 {
+		t.Error("CVaR should be 0 for empty returns")
+	}
+	if ConditionalVaR([]float64{-5}, 95) != 0 {
+		t.Error("CVaR should be 0 for a single return")
+	}
 }
 
 func TestCalculateMean(t *testing.T) {
@@ -2,7 +2,6 @@ package calculations
 
 import (
 	"math"
-	"sort"
 )
 
 // PortfolioReturn calculates total return of a portfolio.
@@ -13,34 +12,6 @@ func PortfolioReturn(initialValue, currentValue float64) float64 {
 	return ((currentValue - initialValue) / initialValue) * 100
 }
 
-// SharpeRatio calculates Sharpe Ratio.
-// Sharpe = (Portfolio Return - Risk-Free Rate) / Standard Deviation
-func SharpeRatio(returns []float64, riskFreeRate float64) float64 {
-	if len(returns) == 0 {
-		return 0
-	}
-
-	avgReturn := 0.0
-	for _, r := range returns {
-		avgReturn += r
-	}
-	avgReturn /= float64(len(returns))
-
-	// Calculate standard deviation
-	variance := 0.0
-	for _, r := range returns {
-		variance += math.Pow(r-avgReturn, 2)
-	}
-	variance /= float64(len(returns))
-	stdDev := math.Sqrt(variance)
-
-	if stdDev == 0 {
-		return 0
-	}
-
-	return (avgReturn - riskFreeRate) / stdDev
-}
-
 // SortinoRatio calculates Sortino Ratio (only considers downside deviation).
 func SortinoRatio(returns []float64, targetReturn float64) float64 {
 	if len(returns) == 0 {
@@ -77,29 +48,6 @@ func SortinoRatio(returns []float64, targetReturn float64) float64 {
 	return (avgReturn - targetReturn) / downsideDeviation
 }
 
-// MaxDrawdown calculates maximum drawdown percentage.
-func MaxDrawdown(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-
-	maxDrawdown := 0.0
-	peak := values[0]
-
-	for _, value := range values {
-		if value > peak {
-			peak = value
-		}
-
-		drawdown := ((peak - value) / peak) * 100
-		if drawdown > maxDrawdown {
-			maxDrawdown = drawdown
-		}
-	}
-
-	return maxDrawdown
-}
-
 // CalmarRatio calculates Calmar Ratio.
 // Calmar = Annual Return / Maximum Drawdown
 func CalmarRatio(annualReturn, maxDrawdown float64) float64 {
@@ -109,44 +57,12 @@ func CalmarRatio(annualReturn, maxDrawdown float64) float64 {
 	return annualReturn / maxDrawdown
 }
 
-// ValueAtRisk calculates Value at Risk at given confidence level.
-// VaR = percentile of returns distribution
-func ValueAtRisk(returns []float64, confidenceLevel float64) float64 {
-	if len(returns) == 0 {
-		return 0
-	}
-
-	sorted := make([]float64, len(returns))
-	copy(sorted, returns)
-	sort.Float64s(sorted)
-
-	index := int(float64(len(sorted)) * (1 - confidenceLevel))
-	if index >= len(sorted) {
-		index = len(sorted) - 1
-	}
-
-	return sorted[index]
-}
-
 // ConditionalValueAtRisk calculates CVaR (Expected Shortfall).
-// CVaR = average of returns below VaR threshold
+// confidenceLevel: e.g., 0.95 for 95% CVaR
+//
+// Deprecated: Use ConditionalVaR, which takes the confidence in percent.
 func ConditionalValueAtRisk(returns []float64, confidenceLevel float64) float64 {
-	var := ValueAtRisk(returns, confidenceLevel)
-
-	sum := 0.0
-	count := 0
-	for _, r := range returns {
-		if r <= var {
-			sum += r
-			count++
-		}
-	}
-
-	if count == 0 {
-		return 0
-	}
-
-	return sum / float64(count)
+	return ConditionalVaR(returns, confidenceLevel*100)
 }
 
 // Beta calculates portfolio beta (systematic risk).
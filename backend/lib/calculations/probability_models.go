@@ -3,6 +3,7 @@ package calculations
 import (
 	"math"
 	"math/rand"
+	"sort"
 )
 
 // NormalDistribution calculates PDF of normal distribution at x.
@@ -45,15 +46,6 @@ func ConfidenceIntervalNormal(mean, stdDev, confidenceLevel float64, sampleSize
 	return mean - marginOfError, mean + marginOfError
 }
 
-// BayesianUpdate updates probability using Bayes' theorem.
-// P(A|B) = P(B|A) * P(A) / P(B)
-func BayesianUpdate(priorProbability, likelihood, evidence float64) float64 {
-	if evidence == 0 {
-		return 0
-	}
-	return (likelihood * priorProbability) / evidence
-}
-
 // BayesianEvidence calculates total probability P(B).
 // P(B) = P(B|A) * P(A) + P(B|not A) * P(not A)
 func BayesianEvidence(likelihoodA, priorA, likelihoodNotA float64) float64 {
@@ -185,71 +177,97 @@ func ChiSquareDistribution(x float64, k int) float64 {
 
 // MonteCarloSimulation runs Monte Carlo simulation for price prediction.
 type MonteCarloParams struct {
-	InitialPrice  float64
-	DriftRate     float64 // Expected return
-	Volatility    float64 // Standard deviation
+	InitialPrice   float64
+	DriftRate      float64 // Expected return
+	Volatility     float64 // Standard deviation
 	DaysToSimulate int
 	NumSimulations int
+	// Rand draws the random shocks. Seed it for reproducible results; nil
+	// uses math/rand's shared source. A *rand.Rand is not safe for
+	// concurrent use, so give each simulation its own.
+	Rand *rand.Rand
+}
+
+// PriceSimulationResult contains the final price distribution of a MonteCarloSimulation.
+type PriceSimulationResult struct {
+	Mean         float64
+	Median       float64
+	StdDev       float64
+	Percentile5  float64
+	Percentile95 float64
+	ProbAbove    float64 // Probability of price being above initial
+	AllPrices    []float64
 }
 
-type MonteCarloResult struct {
-	Mean          float64
-	Median        float64
-	StdDev        float64
-	Percentile5   float64
-	Percentile95  float64
-	ProbAbove     float64 // Probability of price being above initial
-	AllPrices     []float64
+// Simulations from ParallelSimulationThreshold up are run by
+// ParallelMonteCarloSimulation.
+func MonteCarloSimulation(params MonteCarloParams) PriceSimulationResult {
+	if params.NumSimulations >= ParallelSimulationThreshold {
+		return ParallelMonteCarloSimulation(params)
+	}
+	return serialMonteCarloSimulation(params)
 }
 
-func MonteCarloSimulation(params MonteCarloParams) MonteCarloResult {
+// serialMonteCarloSimulation simulates every path on the calling goroutine.
+func serialMonteCarloSimulation(params MonteCarloParams) PriceSimulationResult {
+	normFloat64 := rand.NormFloat64
+	if params.Rand != nil {
+		normFloat64 = params.Rand.NormFloat64
+	}
 	finalPrices := make([]float64, params.NumSimulations)
+	simulatePrices(params, normFloat64, finalPrices)
+	return summarizePrices(params.InitialPrice, finalPrices)
+}
 
-	for i := 0; i < params.NumSimulations; i++ {
-		price := params.InitialPrice
-		dt := 1.0 // Daily time step
+// simulatePrices fills finalPrices with the terminal prices of independent
+// paths, drawing shocks from normFloat64.
+func simulatePrices(params MonteCarloParams, normFloat64 func() float64, finalPrices []float64) {
+	dt := 1.0 // Daily time step
+	// Geometric Brownian Motion
+	drift := (params.DriftRate - 0.5*params.Volatility*params.Volatility) * dt
+	scale := params.Volatility * math.Sqrt(dt)
 
+	for i := range finalPrices {
+		price := params.InitialPrice
 		for day := 0; day < params.DaysToSimulate; day++ {
-			// Generate random normal variable
-			z := rand.NormFloat64()
-
-			// Geometric Brownian Motion
-			drift := (params.DriftRate - 0.5*params.Volatility*params.Volatility) * dt
-			diffusion := params.Volatility * math.Sqrt(dt) * z
-
-			price *= math.Exp(drift + diffusion)
+			price *= math.Exp(drift + scale*normFloat64())
 		}
-
 		finalPrices[i] = price
 	}
+}
 
-	// Calculate statistics
-	result := MonteCarloResult{AllPrices: finalPrices}
+// summarizePrices calculates the statistics of simulated terminal prices.
+func summarizePrices(initialPrice float64, finalPrices []float64) PriceSimulationResult {
+	result := PriceSimulationResult{AllPrices: finalPrices}
 
 	sum := 0.0
 	aboveCount := 0
 
 	for _, price := range finalPrices {
 		sum += price
-		if price > params.InitialPrice {
+		if price > initialPrice {
 			aboveCount++
 		}
 	}
 
-	result.Mean = sum / float64(params.NumSimulations)
-	result.ProbAbove = float64(aboveCount) / float64(params.NumSimulations)
+	result.Mean = sum / float64(len(finalPrices))
+	result.ProbAbove = float64(aboveCount) / float64(len(finalPrices))
 
 	// Calculate standard deviation
 	variance := 0.0
 	for _, price := range finalPrices {
 		variance += math.Pow(price-result.Mean, 2)
 	}
-	result.StdDev = math.Sqrt(variance / float64(params.NumSimulations))
+	result.StdDev = math.Sqrt(variance / float64(len(finalPrices)))
 
-	// For percentiles, we'd need to sort (simplified here)
-	result.Percentile5 = result.Mean - 1.645*result.StdDev
-	result.Percentile95 = result.Mean + 1.645*result.StdDev
-	result.Median = result.Mean // Approximation
+	// Terminal prices are lognormal, so read the percentiles from the
+	// sorted prices rather than assuming a normal distribution
+	sorted := make([]float64, len(finalPrices))
+	copy(sorted, finalPrices)
+	sort.Float64s(sorted)
+	result.Percentile5 = percentile(sorted, 5)
+	result.Percentile95 = percentile(sorted, 95)
+	result.Median = percentile(sorted, 50)
 
 	return result
 }
@@ -325,8 +343,8 @@ func LinearRegression(x, y []float64) RegressionResult {
 
 // TTest performs one-sample t-test.
 type TTestResult struct {
-	TStatistic float64
-	PValue     float64 // Approximate
+	TStatistic  float64
+	PValue      float64 // Approximate
 	Significant bool
 }
 
@@ -418,11 +436,18 @@ func KernelDensityEstimation(data []float64, point, bandwidth float64) float64 {
 	return sum / (n * bandwidth)
 }
 
-// BootstrapConfidenceInterval calculates CI using bootstrap method.
-func BootstrapConfidenceInterval(data []float64, numBootstrap int, confidenceLevel float64) (float64, float64) {
+// BootstrapConfidenceInterval calculates a percentile bootstrap CI for the
+// mean. confidenceLevel: e.g., 0.95 for the 2.5th to 97.5th percentiles of
+// the resampled means. rng draws the resamples; pass a seeded one for
+// reproducible intervals, or nil to use math/rand's shared source.
+func BootstrapConfidenceInterval(data []float64, numBootstrap int, confidenceLevel float64, rng *rand.Rand) (float64, float64) {
 	if len(data) == 0 {
 		return 0, 0
 	}
+	intn := rand.Intn
+	if rng != nil {
+		intn = rng.Intn
+	}
 
 	means := make([]float64, numBootstrap)
 
@@ -430,7 +455,7 @@ func BootstrapConfidenceInterval(data []float64, numBootstrap int, confidenceLev
 		// Resample with replacement
 		sample := make([]float64, len(data))
 		for j := range sample {
-			sample[j] = data[rand.Intn(len(data))]
+			sample[j] = data[intn(len(data))]
 		}
 
 		// Calculate mean of bootstrap sample
@@ -441,27 +466,9 @@ func BootstrapConfidenceInterval(data []float64, numBootstrap int, confidenceLev
 		means[i] = sum / float64(len(sample))
 	}
 
-	// Sort means (simplified - in production use proper sorting)
-	// Return approximate percentiles
-	alpha := 1 - confidenceLevel
-	lowerPercentile := alpha / 2
-	upperPercentile := 1 - (alpha / 2)
-
-	// Simplified calculation
-	sum := 0.0
-	for _, mean := range means {
-		sum += mean
-	}
-	avgMean := sum / float64(numBootstrap)
-
-	// Use standard deviation for interval
-	variance := 0.0
-	for _, mean := range means {
-		variance += math.Pow(mean-avgMean, 2)
-	}
-	stdDev := math.Sqrt(variance / float64(numBootstrap))
-
-	return avgMean - 1.96*stdDev, avgMean + 1.96*stdDev
+	sort.Float64s(means)
+	tail := (1 - confidenceLevel) / 2 * 100
+	return percentile(means, tail), percentile(means, 100-tail)
 }
 
 // ProbabilityOfProfit calculates probability of profit for a trade.
@@ -0,0 +1,171 @@
+package calculations
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMonteCarloSimulation_EmpiricalPercentiles(t *testing.T) {
+	// 100 days at 20% daily volatility gives lognormal terminal prices with a
+	// log standard deviation of 2: median 100·e^-2 ≈ 13.5, 95th percentile
+	// ≈ 13.5·e^3.29 ≈ 363, but a mean near 100 with a standard deviation
+	// in the hundreds.
+	result := MonteCarloSimulation(MonteCarloParams{
+		InitialPrice:   100,
+		DriftRate:      0,
+		Volatility:     0.2,
+		DaysToSimulate: 100,
+		NumSimulations: 5000,
+		Rand:           rand.New(rand.NewSource(1)),
+	})
+
+	if len(result.AllPrices) != 5000 {
+		t.Fatalf("AllPrices has %d prices, expected 5000", len(result.AllPrices))
+	}
+	normal95 := result.Mean + 1.645*result.StdDev
+	if result.Percentile95*1.25 > normal95 {
+		t.Errorf("Percentile95 = %v, expected well below the normal approximation %v", result.Percentile95, normal95)
+	}
+	if result.Percentile95 < 250 || result.Percentile95 > 500 {
+		t.Errorf("Percentile95 = %v, expected about 363", result.Percentile95)
+	}
+	if result.Percentile5 <= 0 {
+		t.Errorf("Percentile5 = %v, expected a positive price", result.Percentile5)
+	}
+	if result.Median < 10 || result.Median > 17 {
+		t.Errorf("Median = %v, expected about 13.5", result.Median)
+	}
+	if result.Median >= result.Mean {
+		t.Errorf("Median = %v, expected below the mean %v for right-skewed prices", result.Median, result.Mean)
+	}
+	if !(result.Percentile5 < result.Median && result.Median < result.Percentile95) {
+		t.Errorf("Percentiles out of order: 5th %v, median %v, 95th %v", result.Percentile5, result.Median, result.Percentile95)
+	}
+}
+
+func TestMonteCarloSimulation_Seeded(t *testing.T) {
+	run := func(seed int64) PriceSimulationResult {
+		return MonteCarloSimulation(MonteCarloParams{
+			InitialPrice:   100,
+			DriftRate:      0.001,
+			Volatility:     0.02,
+			DaysToSimulate: 30,
+			NumSimulations: 200,
+			Rand:           rand.New(rand.NewSource(seed)),
+		})
+	}
+
+	first, second := run(42), run(42)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave different results: mean %v and %v", first.Mean, second.Mean)
+	}
+	if other := run(43); reflect.DeepEqual(first.AllPrices, other.AllPrices) {
+		t.Error("different seeds gave identical prices")
+	}
+}
+
+func TestBootstrapConfidenceInterval(t *testing.T) {
+	// One outlier in ten: a resample's mean is 100 times the number of times
+	// it drew the outlier, so every mean is a multiple of 100. About 35% of
+	// resamples miss it entirely and under 2.5% draw it four times or more.
+	data := []float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 1000}
+	lower, upper := BootstrapConfidenceInterval(data, 10000, 0.95, rand.New(rand.NewSource(1)))
+
+	if lower != 0 {
+		t.Errorf("lower = %v, expected 0", lower)
+	}
+	if upper != 300 {
+		t.Errorf("upper = %v, expected 300", upper)
+	}
+
+	// The normal approximation puts the lower bound below any possible mean.
+	stdErr := 1000 * math.Sqrt(0.1*0.9/10)
+	if normalLower := 100 - 1.96*stdErr; normalLower >= 0 {
+		t.Fatalf("normal lower bound = %v, expected it to be negative", normalLower)
+	}
+
+	lower, upper = BootstrapConfidenceInterval([]float64{5, 5, 5}, 100, 0.95, nil)
+	if lower != 5 || upper != 5 {
+		t.Errorf("interval of constant data = [%v, %v], expected [5, 5]", lower, upper)
+	}
+}
+
+func TestBootstrapConfidenceInterval_Seeded(t *testing.T) {
+	data := []float64{3, 7, 1, 9, 4, 6, 2, 8}
+
+	lower1, upper1 := BootstrapConfidenceInterval(data, 500, 0.9, rand.New(rand.NewSource(7)))
+	lower2, upper2 := BootstrapConfidenceInterval(data, 500, 0.9, rand.New(rand.NewSource(7)))
+	if lower1 != lower2 || upper1 != upper2 {
+		t.Errorf("same seed gave [%v, %v] and [%v, %v]", lower1, upper1, lower2, upper2)
+	}
+	if !(lower1 < 5 && 5 < upper1) {
+		t.Errorf("interval [%v, %v] does not contain the mean 5", lower1, upper1)
+	}
+}
+
+// largeSimulation is big enough for MonteCarloSimulation to run in parallel.
+// 20 days at 2% daily volatility and 0.05% drift give terminal prices with
+// a mean of 100·e^0.01 ≈ 101.0 and a standard deviation of about 9.1.
+func largeSimulation(seed int64) MonteCarloParams {
+	return MonteCarloParams{
+		InitialPrice:   100,
+		DriftRate:      0.0005,
+		Volatility:     0.02,
+		DaysToSimulate: 20,
+		NumSimulations: ParallelSimulationThreshold * 2,
+		Rand:           rand.New(rand.NewSource(seed)),
+	}
+}
+
+func TestParallelMonteCarloSimulation_MatchesSerial(t *testing.T) {
+	parallel := MonteCarloSimulation(largeSimulation(1))
+	serial := serialMonteCarloSimulation(largeSimulation(1))
+
+	if len(parallel.AllPrices) != len(serial.AllPrices) {
+		t.Fatalf("parallel simulated %d prices, expected %d", len(parallel.AllPrices), len(serial.AllPrices))
+	}
+	for i, price := range parallel.AllPrices {
+		if price <= 0 {
+			t.Fatalf("AllPrices[%d] = %v, expected every path to be simulated", i, price)
+		}
+	}
+	// The standard error of each mean is about 9.1/√200000 ≈ 0.02.
+	if math.Abs(parallel.Mean-serial.Mean) > 0.15 || math.Abs(parallel.Mean-100*math.Exp(0.01)) > 0.15 {
+		t.Errorf("parallel mean = %v, serial mean = %v, expected both about 101.0", parallel.Mean, serial.Mean)
+	}
+	if math.Abs(parallel.StdDev/serial.StdDev-1) > 0.02 {
+		t.Errorf("parallel stddev = %v, serial stddev = %v, expected them within 2%%", parallel.StdDev, serial.StdDev)
+	}
+	if math.Abs(parallel.ProbAbove-serial.ProbAbove) > 0.01 {
+		t.Errorf("parallel ProbAbove = %v, serial ProbAbove = %v", parallel.ProbAbove, serial.ProbAbove)
+	}
+}
+
+func TestParallelMonteCarloSimulation_Seeded(t *testing.T) {
+	// Fewer paths than one chunk still run, on a single worker.
+	small := largeSimulation(3)
+	small.NumSimulations = 10
+	if result := ParallelMonteCarloSimulation(small); len(result.AllPrices) != 10 || result.Mean <= 0 {
+		t.Errorf("ParallelMonteCarloSimulation() of 10 paths = %d prices with mean %v", len(result.AllPrices), result.Mean)
+	}
+
+	first := ParallelMonteCarloSimulation(largeSimulation(42))
+	second := ParallelMonteCarloSimulation(largeSimulation(42))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave different results: mean %v and %v", first.Mean, second.Mean)
+	}
+}
+
+func BenchmarkMonteCarloSimulation_Serial(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		serialMonteCarloSimulation(largeSimulation(int64(i)))
+	}
+}
+
+func BenchmarkMonteCarloSimulation_Parallel(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ParallelMonteCarloSimulation(largeSimulation(int64(i)))
+	}
+}
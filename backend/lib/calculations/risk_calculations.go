@@ -6,10 +6,10 @@ import (
 
 // PositionSize calculates position size based on risk parameters.
 type PositionSizeParams struct {
-	AccountSize      float64
-	RiskPercentage   float64 // Risk per trade as percentage (e.g., 2.0 for 2%)
-	EntryPrice       float64
-	StopLossPrice    float64
+	AccountSize    float64
+	RiskPercentage float64 // Risk per trade as percentage (e.g., 2.0 for 2%)
+	EntryPrice     float64
+	StopLossPrice  float64
 }
 
 func PositionSize(params PositionSizeParams) float64 {
@@ -200,7 +200,7 @@ func MarginRequirement(positionValue, marginPercent float64) float64 {
 }
 
 // LiquidationPrice calculates liquidation price for leveraged position.
-func LiquidationPrice(entryPrice, leverage, isLong bool) float64 {
+func LiquidationPrice(entryPrice, leverage float64, isLong bool) float64 {
 	liquidationPercent := 1 / leverage
 
 	if isLong {
@@ -363,9 +363,9 @@ func MonteCarloRisk(trades []float64, simulations int) map[string]float64 {
 	}
 
 	return map[string]float64{
-		"mean":          mean,
-		"worst_case":    worst,
-		"best_case":     best,
-		"risk_range":    best - worst,
+		"mean":       mean,
+		"worst_case": worst,
+		"best_case":  best,
+		"risk_range": best - worst,
 	}
 }
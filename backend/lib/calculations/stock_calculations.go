@@ -58,11 +58,6 @@ func GrahamIntrinsicValue(eps, growthRate, bondYield float64) float64 {
 	return eps * (8.5 + 2*growthRate*100) * 4.4 / (bondYield * 100)
 }
 
-// PEValuation calculates fair value using P/E ratio.
-func PEValuation(eps, targetPE float64) float64 {
-	return eps * targetPE
-}
-
 // PEGRatio calculates Price/Earnings to Growth ratio.
 // PEG = (Price / EPS) / Growth Rate
 func PEGRatio(price, eps, growthRate float64) float64 {
@@ -32,12 +32,13 @@ func SMA(prices []float64, period int) []float64 {
 }
 
 // EMA calculates Exponential Moving Average.
+// Like SMA, the first value is for the period ending at prices[period-1].
 func EMA(prices []float64, period int) []float64 {
 	if len(prices) < period {
 		return []float64{}
 	}
 
-	result := make([]float64, len(prices))
+	result := make([]float64, len(prices)-period+1)
 	multiplier := 2.0 / float64(period+1)
 
 	// Start with SMA for first value
@@ -45,17 +46,18 @@ func EMA(prices []float64, period int) []float64 {
 	for i := 0; i < period; i++ {
 		sum += prices[i]
 	}
-	result[period-1] = sum / float64(period)
+	result[0] = sum / float64(period)
 
 	// Calculate EMA for remaining values
-	for i := period; i < len(prices); i++ {
-		result[i] = (prices[i]-result[i-1])*multiplier + result[i-1]
+	for i := 1; i < len(result); i++ {
+		result[i] = (prices[i+period-1]-result[i-1])*multiplier + result[i-1]
 	}
 
 	return result
 }
 
-// RSI calculates Relative Strength Index.
+// RSI calculates Relative Strength Index using Wilder's smoothing.
+// The first value is for the period ending at prices[period].
 func RSI(prices []float64, period int) []float64 {
 	if len(prices) < period+1 {
 		return []float64{}
@@ -88,20 +90,32 @@ func RSI(prices []float64, period int) []float64 {
 	avgGain /= float64(period)
 	avgLoss /= float64(period)
 
+	result[0] = relativeStrengthIndex(avgGain, avgLoss)
+
 	// Calculate RSI for each point
 	for i := period; i < len(changes); i++ {
 		avgGain = ((avgGain * float64(period-1)) + gains[i]) / float64(period)
 		avgLoss = ((avgLoss * float64(period-1)) + losses[i]) / float64(period)
 
-		rs := avgGain / avgLoss
-		rsi := 100 - (100 / (1 + rs))
-
-		result[i-period] = rsi
+		result[i-period+1] = relativeStrengthIndex(avgGain, avgLoss)
 	}
 
 	return result
 }
 
+// relativeStrengthIndex converts average gain and loss to RSI. A period
+// without losses is 100 and one without any change is neutral.
+func relativeStrengthIndex(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	rs := avgGain / avgLoss
+	return 100 - (100 / (1 + rs))
+}
+
 // MACD calculates Moving Average Convergence Divergence.
 type MACDResult struct {
 	MACD      float64
@@ -213,6 +227,9 @@ type StochasticResult struct {
 	D float64 // Slow line (SMA of K)
 }
 
+// Stochastic calculates %K, where the close sits in the period's range from
+// 0 at the low to 100 at the high, and %D, its dPeriod SMA. A period with no
+// range is neutral (50).
 func Stochastic(data []PriceData, kPeriod, dPeriod int) []StochasticResult {
 	if len(data) < kPeriod {
 		return []StochasticResult{}
@@ -235,6 +252,10 @@ func Stochastic(data []PriceData, kPeriod, dPeriod int) []StochasticResult {
 		}
 
 		currentClose := data[i+kPeriod-1].Close
+		if high == low {
+			kValues[i] = 50
+			continue
+		}
 		kValues[i] = ((currentClose - low) / (high - low)) * 100
 	}
 
@@ -350,7 +371,8 @@ func OBV(data []PriceData) []float64 {
 	return result
 }
 
-// WilliamsR calculates Williams %R.
+// WilliamsR calculates Williams %R, from -100 at the period low to 0 at the
+// period high. A period with no range is neutral (-50).
 func WilliamsR(data []PriceData, period int) []float64 {
 	if len(data) < period {
 		return []float64{}
@@ -372,6 +394,10 @@ func WilliamsR(data []PriceData, period int) []float64 {
 		}
 
 		currentClose := data[i+period-1].Close
+		if high == low {
+			result[i] = -50
+			continue
+		}
 		result[i] = ((high - currentClose) / (high - low)) * -100
 	}
 
@@ -0,0 +1,135 @@
+package calculations
+
+import (
+	"math"
+	"testing"
+)
+
+// linearPrices returns n prices rising by 1 from 100.
+func linearPrices(n int) []float64 {
+	prices := make([]float64, n)
+	for i := range prices {
+		prices[i] = 100 + float64(i)
+	}
+	return prices
+}
+
+func TestEMA(t *testing.T) {
+	result := EMA([]float64{1, 2, 3, 4, 5}, 3)
+	want := []float64{2, 3, 4}
+
+	if len(result) != len(want) {
+		t.Fatalf("EMA() returned %d values, expected %d", len(result), len(want))
+	}
+	for i := range want {
+		if math.Abs(result[i]-want[i]) > 1e-9 {
+			t.Errorf("EMA()[%d] = %v, expected %v", i, result[i], want[i])
+		}
+	}
+}
+
+func TestRSI(t *testing.T) {
+	// Changes +1 -1 +1 -1: the first RSI averages one gain and one loss,
+	// then Wilder's smoothing weights each new change by 1/period.
+	result := RSI([]float64{1, 2, 1, 2, 1}, 2)
+	want := []float64{50, 75, 37.5}
+
+	if len(result) != len(want) {
+		t.Fatalf("RSI() returned %d values, expected %d", len(result), len(want))
+	}
+	for i := range want {
+		if math.Abs(result[i]-want[i]) > 1e-9 {
+			t.Errorf("RSI()[%d] = %v, expected %v", i, result[i], want[i])
+		}
+	}
+
+	rising := RSI(linearPrices(20), 14)
+	if len(rising) != 6 {
+		t.Fatalf("RSI() of 20 prices returned %d values, expected 6", len(rising))
+	}
+	for i, v := range rising {
+		if v != 100 {
+			t.Errorf("RSI()[%d] of rising prices = %v, expected 100", i, v)
+		}
+	}
+
+	flat := RSI([]float64{5, 5, 5, 5}, 2)
+	if flat[len(flat)-1] != 50 {
+		t.Errorf("RSI() of flat prices = %v, expected 50", flat[len(flat)-1])
+	}
+}
+
+func TestMACD(t *testing.T) {
+	// An EMA of prices rising by 1 lags them by (period-1)/2, so MACD(12, 26)
+	// is a constant 12.5 - 5.5 = 7.
+	result := MACD(linearPrices(60), 12, 26, 9)
+
+	// 35 MACD values from the 26th price, then 27 signal values from the 9th.
+	if len(result) != 27 {
+		t.Fatalf("MACD() returned %d values, expected 27", len(result))
+	}
+	for i, r := range result {
+		if math.Abs(r.MACD-7) > 1e-9 || math.Abs(r.Signal-7) > 1e-9 || math.Abs(r.Histogram) > 1e-9 {
+			t.Errorf("MACD()[%d] = %+v, expected MACD 7, signal 7, histogram 0", i, r)
+		}
+	}
+
+	if short := MACD(linearPrices(25), 12, 26, 9); len(short) != 0 {
+		t.Errorf("MACD() of 25 prices returned %d values, expected none", len(short))
+	}
+}
+
+// flatPriceData returns n periods that all open, close and trade at price.
+func flatPriceData(n int, price float64) []PriceData {
+	data := make([]PriceData, n)
+	for i := range data {
+		data[i] = PriceData{Open: price, High: price, Low: price, Close: price, Volume: 1000}
+	}
+	return data
+}
+
+func TestStochastic_FlatRange(t *testing.T) {
+	result := Stochastic(flatPriceData(20, 100), 14, 3)
+
+	if len(result) != 5 {
+		t.Fatalf("Stochastic() returned %d values, expected 5", len(result))
+	}
+	for i, r := range result {
+		if math.IsNaN(r.K) || math.IsNaN(r.D) || math.IsInf(r.K, 0) || math.IsInf(r.D, 0) {
+			t.Fatalf("Stochastic()[%d] = %+v, expected finite values", i, r)
+		}
+		if r.K != 50 || r.D != 50 {
+			t.Errorf("Stochastic()[%d] = %+v, expected neutral 50", i, r)
+		}
+	}
+}
+
+func TestWilliamsR_FlatRange(t *testing.T) {
+	result := WilliamsR(flatPriceData(20, 100), 14)
+
+	if len(result) != 7 {
+		t.Fatalf("WilliamsR() returned %d values, expected 7", len(result))
+	}
+	for i, v := range result {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Fatalf("WilliamsR()[%d] = %v, expected a finite value", i, v)
+		}
+		if v != -50 {
+			t.Errorf("WilliamsR()[%d] = %v, expected neutral -50", i, v)
+		}
+	}
+}
+
+func TestWilliamsR_Range(t *testing.T) {
+	data := flatPriceData(3, 100)
+	data[0].High, data[1].Low = 110, 90
+
+	// Closing at 100 in a 90-110 range is halfway, like a flat period.
+	if result := WilliamsR(data, 3); len(result) != 1 || result[0] != -50 {
+		t.Errorf("WilliamsR() = %v, expected [-50]", result)
+	}
+	data[2].Close = 110
+	if result := WilliamsR(data, 3); result[0] != 0 {
+		t.Errorf("WilliamsR() closing at the high = %v, expected 0", result[0])
+	}
+}
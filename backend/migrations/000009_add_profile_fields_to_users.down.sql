@@ -0,0 +1,3 @@
+-- Remove avatar and OAuth profile sync columns from users table
+ALTER TABLE users DROP COLUMN IF EXISTS profile_sync_provider;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_url;
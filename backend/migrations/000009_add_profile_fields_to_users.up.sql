@@ -0,0 +1,3 @@
+-- Add avatar and OAuth profile sync columns to users table
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS profile_sync_provider VARCHAR(20);
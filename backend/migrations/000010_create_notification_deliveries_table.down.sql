@@ -0,0 +1,2 @@
+-- Drop notification_deliveries table
+DROP TABLE IF EXISTS notification_deliveries;
@@ -0,0 +1,3 @@
+-- Remove personal fair value recommendation thresholds from settings table
+ALTER TABLE settings DROP COLUMN IF EXISTS sell_margin_of_safety;
+ALTER TABLE settings DROP COLUMN IF EXISTS buy_margin_of_safety;
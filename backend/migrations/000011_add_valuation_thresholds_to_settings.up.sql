@@ -0,0 +1,3 @@
+-- Add personal fair value recommendation thresholds to settings table
+ALTER TABLE settings ADD COLUMN IF NOT EXISTS buy_margin_of_safety DECIMAL(6, 2) DEFAULT 20;
+ALTER TABLE settings ADD COLUMN IF NOT EXISTS sell_margin_of_safety DECIMAL(6, 2) DEFAULT -10;
@@ -0,0 +1,6 @@
+-- Add email_verified column to users table
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN DEFAULT FALSE;
+
+-- Users created before email verification existed count as verified, so
+-- that REQUIRE_EMAIL_VERIFICATION does not lock them out
+UPDATE users SET email_verified = TRUE;
@@ -0,0 +1,2 @@
+-- Remove realized_pl column from trades table
+ALTER TABLE trades DROP COLUMN IF EXISTS realized_pl;
@@ -0,0 +1,2 @@
+-- Add realized_pl column to trades table
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS realized_pl DECIMAL(20, 4) NOT NULL DEFAULT 0;
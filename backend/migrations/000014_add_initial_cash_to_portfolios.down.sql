@@ -0,0 +1,2 @@
+-- Remove initial_cash column from portfolios table
+ALTER TABLE portfolios DROP COLUMN IF EXISTS initial_cash;
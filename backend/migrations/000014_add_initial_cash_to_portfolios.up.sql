@@ -0,0 +1,11 @@
+-- Add initial_cash column to portfolios table
+ALTER TABLE portfolios ADD COLUMN IF NOT EXISTS initial_cash DECIMAL(20, 4) NOT NULL DEFAULT 100000;
+
+-- Rebuild the opening cash of existing portfolios: current cash, plus what
+-- buys spent and resting buy limits reserve, minus what sells returned
+UPDATE portfolios p SET initial_cash = p.cash_balance
+    + COALESCE((SELECT SUM(CASE WHEN t.side = 'buy' THEN t.total ELSE -t.total END)
+                FROM trades t WHERE t.portfolio_id = p.id), 0)
+    + COALESCE((SELECT SUM(o.quantity * o.price)
+                FROM orders o
+                WHERE o.portfolio_id = p.id AND o.side = 'buy' AND o.order_type = 'limit' AND o.status = 'pending'), 0);
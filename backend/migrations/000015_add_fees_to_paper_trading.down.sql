@@ -0,0 +1,6 @@
+-- Remove fees column from trades table
+ALTER TABLE trades DROP COLUMN IF EXISTS fees;
+
+-- Remove fee model columns from portfolios table
+ALTER TABLE portfolios DROP COLUMN IF EXISTS fee_amount;
+ALTER TABLE portfolios DROP COLUMN IF EXISTS fee_model;
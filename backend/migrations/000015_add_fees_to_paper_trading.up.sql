@@ -0,0 +1,6 @@
+-- Add fee model columns to portfolios table
+ALTER TABLE portfolios ADD COLUMN IF NOT EXISTS fee_model VARCHAR(20) NOT NULL DEFAULT 'none';
+ALTER TABLE portfolios ADD COLUMN IF NOT EXISTS fee_amount DECIMAL(20, 4) NOT NULL DEFAULT 0;
+
+-- Add fees column to trades table
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS fees DECIMAL(20, 4) NOT NULL DEFAULT 0;
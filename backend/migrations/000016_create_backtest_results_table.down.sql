@@ -0,0 +1,2 @@
+-- Drop backtest_results table
+DROP TABLE IF EXISTS backtest_results;
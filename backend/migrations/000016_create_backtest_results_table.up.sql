@@ -0,0 +1,27 @@
+-- Create backtest_results table
+CREATE TABLE IF NOT EXISTS backtest_results (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    symbol VARCHAR(20) NOT NULL,
+    strategy_name VARCHAR(255),
+    strategy_type VARCHAR(20) NOT NULL,
+    params JSONB,
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    initial_capital DECIMAL(20, 4) NOT NULL,
+    final_value DECIMAL(20, 4) NOT NULL,
+    total_return DECIMAL(20, 4) NOT NULL DEFAULT 0,
+    total_return_percent DECIMAL(20, 4) NOT NULL DEFAULT 0,
+    sharpe_ratio DECIMAL(20, 4) NOT NULL DEFAULT 0,
+    max_drawdown DECIMAL(20, 4) NOT NULL DEFAULT 0,
+    win_rate DECIMAL(20, 4) NOT NULL DEFAULT 0,
+    total_trades INTEGER NOT NULL DEFAULT 0,
+    winning_trades INTEGER NOT NULL DEFAULT 0,
+    losing_trades INTEGER NOT NULL DEFAULT 0,
+    equity_curve JSONB,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for common queries
+CREATE INDEX IF NOT EXISTS idx_backtest_results_symbol ON backtest_results(symbol);
+CREATE INDEX IF NOT EXISTS idx_backtest_results_completed_at ON backtest_results(completed_at);
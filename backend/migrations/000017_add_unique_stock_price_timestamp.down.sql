@@ -0,0 +1,2 @@
+-- Drop the stock price uniqueness constraint
+DROP INDEX IF EXISTS idx_stock_prices_stock_timestamp;
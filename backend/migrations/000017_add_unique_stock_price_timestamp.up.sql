@@ -0,0 +1,8 @@
+-- Keep one bar per stock and timestamp before enforcing uniqueness
+DELETE FROM stock_prices a
+USING stock_prices b
+WHERE a.stock_id = b.stock_id
+  AND a.timestamp = b.timestamp
+  AND a.id > b.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_prices_stock_timestamp ON stock_prices(stock_id, timestamp);
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults used when BreakerConfig fields are zero.
+const (
+	DefaultBreakerFailureThreshold = 5
+	DefaultBreakerCooldown         = 30 * time.Second
+)
+
+// ErrProviderUnavailable is returned without contacting the provider while
+// its circuit breaker is open.
+var ErrProviderUnavailable = errors.New("provider unavailable: circuit open")
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+// Circuit breaker states. Requests flow while closed, are rejected while
+// open, and a single probe request is let through while half-open.
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+// String returns the state name.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerConfig controls when a provider's circuit breaker trips. After
+// FailureThreshold consecutive failed requests the breaker opens and
+// requests fail fast with ErrProviderUnavailable until Cooldown has passed.
+type BreakerConfig struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+	Disabled         bool
+}
+
+// CircuitBreaker tracks consecutive failures for one provider.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a closed circuit breaker.
+func NewCircuitBreaker(config BreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultBreakerFailureThreshold
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = DefaultBreakerCooldown
+	}
+	return &CircuitBreaker{
+		threshold: config.FailureThreshold,
+		cooldown:  config.Cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a request may be sent. Once the cooldown has passed
+// an open breaker turns half-open and admits one probe; further requests are
+// rejected until that probe is recorded.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.state = BreakerHalfOpen
+		b.probing = false
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success records a successful request and closes the breaker.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure records a failed request. A failed probe reopens the breaker
+// immediately; otherwise it opens once the threshold is reached.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
+
+// abort releases a half-open probe whose outcome is unknown, such as when
+// the caller's context was cancelled, so that another probe may be sent.
+func (b *CircuitBreaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State returns the current state, reporting an open breaker whose cooldown
+// has passed as half-open.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// breakers holds one circuit breaker per base URL, shared by every Client
+// talking to that provider.
+var breakers = struct {
+	sync.Mutex
+	byURL map[string]*CircuitBreaker
+}{byURL: make(map[string]*CircuitBreaker)}
+
+// breakerFor returns the breaker for baseURL, creating it from config on
+// first use. Later configs for the same base URL are ignored.
+func breakerFor(baseURL string, config BreakerConfig) *CircuitBreaker {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	if b, ok := breakers.byURL[baseURL]; ok {
+		return b
+	}
+	b := NewCircuitBreaker(config)
+	breakers.byURL[baseURL] = b
+	return b
+}
+
+// BreakerStatus is a snapshot of one provider's circuit breaker.
+type BreakerStatus struct {
+	BaseURL string
+	State   BreakerState
+}
+
+// BreakerStates returns the state of every provider's circuit breaker,
+// ordered by base URL.
+func BreakerStates() []BreakerStatus {
+	breakers.Lock()
+	statuses := make([]BreakerStatus, 0, len(breakers.byURL))
+	for url, b := range breakers.byURL {
+		statuses = append(statuses, BreakerStatus{BaseURL: url, State: b.State()})
+	}
+	breakers.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].BaseURL < statuses[j].BaseURL })
+	return statuses
+}
@@ -1,14 +1,25 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"strconv"
 	"time"
 )
 
+// Retry defaults used when RetryConfig fields are zero.
+const (
+	DefaultRetryAttempts  = 3
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	DefaultRetryMaxDelay  = 10 * time.Second
+)
+
 // Client is a base HTTP client for API requests.
 type Client struct {
 	httpClient *http.Client
@@ -16,15 +27,30 @@ type Client struct {
 	apiKey     string
 	headers    map[string]string
 	rateLimit  *RateLimiter
+	retry      RetryConfig
+	breaker    *CircuitBreaker
 }
 
 // ClientConfig holds configuration for API client.
 type ClientConfig struct {
-	BaseURL        string
-	APIKey         string
-	Timeout        time.Duration
-	RateLimitRPS   int // Requests per second
-	CustomHeaders  map[string]string
+	BaseURL       string
+	APIKey        string
+	Timeout       time.Duration
+	RateLimitRPS  int // Requests per second
+	CustomHeaders map[string]string
+	CookieJar     http.CookieJar // Optional; stores and sends cookies across requests
+	Retry         RetryConfig
+	Breaker       BreakerConfig
+}
+
+// RetryConfig controls how GET requests are retried after network errors,
+// 429 and 5xx responses. Delays grow exponentially from BaseDelay with
+// jitter, and a Retry-After header overrides the computed delay. No delay
+// exceeds MaxDelay.
+type RetryConfig struct {
+	MaxAttempts int // Total attempts including the first; 1 disables retries
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
 }
 
 // NewClient creates a new API client.
@@ -36,10 +62,26 @@ func NewClient(config ClientConfig) *Client {
 	client := &Client{
 		httpClient: &http.Client{
 			Timeout: config.Timeout,
+			Jar:     config.CookieJar,
 		},
 		baseURL: config.BaseURL,
 		apiKey:  config.APIKey,
 		headers: config.CustomHeaders,
+		retry:   config.Retry,
+	}
+
+	if client.retry.MaxAttempts <= 0 {
+		client.retry.MaxAttempts = DefaultRetryAttempts
+	}
+	if client.retry.BaseDelay <= 0 {
+		client.retry.BaseDelay = DefaultRetryBaseDelay
+	}
+	if client.retry.MaxDelay <= 0 {
+		client.retry.MaxDelay = DefaultRetryMaxDelay
+	}
+
+	if !config.Breaker.Disabled {
+		client.breaker = breakerFor(config.BaseURL, config.Breaker)
 	}
 
 	if config.RateLimitRPS > 0 {
@@ -49,25 +91,57 @@ func NewClient(config ClientConfig) *Client {
 	return client
 }
 
-// Get performs a GET request.
+// Get performs a GET request, retrying transient failures.
 func (c *Client) Get(ctx context.Context, endpoint string, params map[string]string) (*http.Response, error) {
-	return c.request(ctx, http.MethodGet, endpoint, params, nil)
+	return c.request(ctx, http.MethodGet, endpoint, params, nil, c.retry.MaxAttempts)
 }
 
-// Post performs a POST request.
+// Post performs a POST request. POSTs are not retried since they may not be
+// idempotent.
 func (c *Client) Post(ctx context.Context, endpoint string, body interface{}) (*http.Response, error) {
-	return c.request(ctx, http.MethodPost, endpoint, nil, body)
+	return c.request(ctx, http.MethodPost, endpoint, nil, body, 1)
 }
 
-// request performs an HTTP request with rate limiting.
-func (c *Client) request(ctx context.Context, method, endpoint string, params map[string]string, body interface{}) (*http.Response, error) {
-	// Apply rate limiting
-	if c.rateLimit != nil {
-		if err := c.rateLimit.Wait(ctx); err != nil {
-			return nil, fmt.Errorf("rate limit wait: %w", err)
-		}
+// StatusError is returned for responses with a 4xx or 5xx status code.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	retryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+}
+
+// request performs an HTTP request through the provider's circuit breaker.
+// It fails fast with ErrProviderUnavailable while the breaker is open.
+func (c *Client) request(ctx context.Context, method, endpoint string, params map[string]string, body interface{}, maxAttempts int) (*http.Response, error) {
+	if c.breaker == nil {
+		return c.attempt(ctx, method, endpoint, params, body, maxAttempts)
+	}
+	if !c.breaker.Allow() {
+		return nil, fmt.Errorf("%s: %w", c.baseURL, ErrProviderUnavailable)
+	}
+
+	resp, err := c.attempt(ctx, method, endpoint, params, body, maxAttempts)
+	switch {
+	case err == nil:
+		c.breaker.Success()
+	case ctx.Err() != nil:
+		c.breaker.abort()
+	case retryable(ctx, err):
+		c.breaker.Failure()
+	default:
+		// The provider answered, if only to reject the request.
+		c.breaker.Success()
 	}
+	return resp, err
+}
 
+// attempt performs an HTTP request with rate limiting, making up to
+// maxAttempts attempts.
+func (c *Client) attempt(ctx context.Context, method, endpoint string, params map[string]string, body interface{}, maxAttempts int) (*http.Response, error) {
 	// Build URL
 	url := c.baseURL + endpoint
 	if len(params) > 0 {
@@ -82,14 +156,53 @@ func (c *Client) request(ctx context.Context, method, endpoint string, params ma
 		}
 	}
 
-	// Create request
-	var reqBody io.Reader
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("marshal body: %w", err)
 		}
-		reqBody = io.NopCloser(io.Reader(jsonData))
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.do(ctx, method, url, jsonData)
+		if err == nil || attempt >= maxAttempts || !retryable(ctx, err) {
+			return resp, err
+		}
+
+		delay := c.backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.retryAfter > 0 {
+			delay = statusErr.retryAfter
+		}
+		if delay > c.retry.MaxDelay {
+			delay = c.retry.MaxDelay
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w (after %d attempts: %v)", ctx.Err(), attempt, err)
+		case <-timer.C:
+		}
+	}
+}
+
+// do performs a single attempt of a request.
+func (c *Client) do(ctx context.Context, method, url string, jsonData []byte) (*http.Response, error) {
+	// Apply rate limiting
+	if c.rateLimit != nil {
+		if err := c.rateLimit.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limit wait: %w", err)
+		}
+	}
+
+	// Create request
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
@@ -100,7 +213,7 @@ func (c *Client) request(ctx context.Context, method, endpoint string, params ma
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	
+
 	if c.apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
@@ -119,27 +232,82 @@ func (c *Client) request(ctx context.Context, method, endpoint string, params ma
 	if resp.StatusCode >= 400 {
 		defer resp.Body.Close()
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(bodyBytes),
+			retryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
 	return resp, nil
 }
 
+// retryable reports whether a failed attempt may succeed if repeated:
+// network errors, 429 and 5xx responses. Nothing is retried once ctx is done.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	return true
+}
+
+// backoff returns the delay before the retry following attempt.
+func (c *Client) backoff(attempt int) time.Duration {
+	return c.retry.Backoff(attempt)
+}
+
+// Backoff returns the delay before the retry following attempt: BaseDelay
+// doubled per attempt, capped at MaxDelay, with jitter over its upper half so
+// clients do not retry in lockstep.
+func (r RetryConfig) Backoff(attempt int) time.Duration {
+	delay := r.BaseDelay
+	for i := 1; i < attempt && delay < r.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// ParseRetryAfter reads a Retry-After header given either in seconds or as
+// an HTTP date. It returns zero when the header is absent or invalid.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
 // DecodeResponse decodes JSON response into target.
 func DecodeResponse(resp *http.Response, target interface{}) error {
 	defer resp.Body.Close()
-	
+
 	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
 		return fmt.Errorf("decode response: %w", err)
 	}
-	
+
 	return nil
 }
 
 // RateLimiter implements token bucket rate limiting.
 type RateLimiter struct {
-	ticker   *time.Ticker
-	tokens   chan struct{}
+	ticker    *time.Ticker
+	tokens    chan struct{}
 	maxTokens int
 }
 
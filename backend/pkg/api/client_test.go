@@ -0,0 +1,268 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyServer fails the first failures requests with status, then
+// answers {"ok":true}. It returns the server and its request counter.
+func newFlakyServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			w.Write([]byte("unavailable"))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func fastRetry(attempts int) RetryConfig {
+	return RetryConfig{MaxAttempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func TestClient_GetRetriesTransientFailures(t *testing.T) {
+	server, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable, "")
+	client := NewClient(ClientConfig{BaseURL: server.URL, Retry: fastRetry(3)})
+
+	resp, err := client.Get(context.Background(), "/quote", nil)
+	if err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != `{"ok":true}` {
+		t.Errorf("Unexpected body %q", body)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestClient_GetGivesUpAfterMaxAttempts(t *testing.T) {
+	server, calls := newFlakyServer(t, 5, http.StatusBadGateway, "")
+	client := NewClient(ClientConfig{BaseURL: server.URL, Retry: fastRetry(2)})
+
+	_, err := client.Get(context.Background(), "/quote", nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected a 502 StatusError, got %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestClient_GetDoesNotRetryClientErrors(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusNotFound, "")
+	client := NewClient(ClientConfig{BaseURL: server.URL, Retry: fastRetry(3)})
+
+	if _, err := client.Get(context.Background(), "/quote", nil); err == nil {
+		t.Fatal("Expected an error for a 404 response")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestClient_PostIsNotRetried(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, "")
+	client := NewClient(ClientConfig{BaseURL: server.URL, Retry: fastRetry(3)})
+
+	if _, err := client.Post(context.Background(), "/orders", map[string]string{"side": "buy"}); err == nil {
+		t.Fatal("Expected an error for a 503 response")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestClient_GetHonorsRetryAfter(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, "1")
+	client := NewClient(ClientConfig{
+		BaseURL: server.URL,
+		Retry:   RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second},
+	})
+
+	start := time.Now()
+	if _, err := client.Get(context.Background(), "/quote", nil); err != nil {
+		t.Fatalf("Expected success after retry, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected to wait the 1s Retry-After, waited %v", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestClient_GetStopsWhenContextCancelled(t *testing.T) {
+	server, calls := newFlakyServer(t, 5, http.StatusServiceUnavailable, "")
+	client := NewClient(ClientConfig{
+		BaseURL: server.URL,
+		Retry:   RetryConfig{MaxAttempts: 5, BaseDelay: time.Minute, MaxDelay: time.Minute},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Get(ctx, "/quote", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the backoff wait to end with the context, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"empty", "", 0},
+		{"seconds", "3", 3 * time.Second},
+		{"negative", "-1", 0},
+		{"http date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"garbage", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("ParseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_BackoffIsCapped(t *testing.T) {
+	client := NewClient(ClientConfig{Retry: RetryConfig{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}})
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := client.backoff(attempt)
+		if delay > time.Second {
+			t.Errorf("backoff(%d) = %v, want at most 1s", attempt, delay)
+		}
+	}
+	if delay := client.backoff(1); delay < 50*time.Millisecond || delay > 100*time.Millisecond {
+		t.Errorf("backoff(1) = %v, want between 50ms and 100ms", delay)
+	}
+}
+
+func TestClient_BreakerOpensAfterThreshold(t *testing.T) {
+	server, calls := newFlakyServer(t, 100, http.StatusServiceUnavailable, "")
+	client := NewClient(ClientConfig{
+		BaseURL: server.URL,
+		Retry:   fastRetry(1),
+		Breaker: BreakerConfig{FailureThreshold: 2, Cooldown: time.Hour},
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(context.Background(), "/quote", nil); errors.Is(err, ErrProviderUnavailable) {
+			t.Fatalf("Request %d: breaker opened too early", i+1)
+		}
+	}
+
+	_, err := client.Get(context.Background(), "/quote", nil)
+	if !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("Expected ErrProviderUnavailable, got %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("Expected the open breaker to skip the provider, got %d calls", got)
+	}
+
+	found := false
+	for _, status := range BreakerStates() {
+		if status.BaseURL == server.URL {
+			found = true
+			if status.State != BreakerOpen {
+				t.Errorf("Expected open state, got %s", status.State)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected a breaker for %s", server.URL)
+	}
+}
+
+func TestClient_BreakerIgnoresClientErrors(t *testing.T) {
+	server, calls := newFlakyServer(t, 100, http.StatusNotFound, "")
+	client := NewClient(ClientConfig{
+		BaseURL: server.URL,
+		Retry:   fastRetry(1),
+		Breaker: BreakerConfig{FailureThreshold: 1, Cooldown: time.Hour},
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Get(context.Background(), "/quote", nil); errors.Is(err, ErrProviderUnavailable) {
+			t.Fatalf("Request %d: breaker opened on a 404", i+1)
+		}
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("Expected 3 calls, got %d", got)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	now := time.Now()
+	breaker := NewCircuitBreaker(BreakerConfig{FailureThreshold: 1, Cooldown: time.Minute})
+	breaker.now = func() time.Time { return now }
+
+	breaker.Failure()
+	if breaker.Allow() {
+		t.Fatal("Expected open breaker to reject requests")
+	}
+
+	now = now.Add(time.Minute)
+	if got := breaker.State(); got != BreakerHalfOpen {
+		t.Fatalf("Expected half_open after cooldown, got %s", got)
+	}
+	if !breaker.Allow() {
+		t.Fatal("Expected half-open breaker to admit a probe")
+	}
+	if breaker.Allow() {
+		t.Fatal("Expected only one probe while half-open")
+	}
+
+	// A failed probe reopens the breaker for another cooldown
+	breaker.Failure()
+	if breaker.Allow() {
+		t.Fatal("Expected breaker to reopen after a failed probe")
+	}
+
+	now = now.Add(time.Minute)
+	if !breaker.Allow() {
+		t.Fatal("Expected a second probe after cooldown")
+	}
+	breaker.Success()
+	if got := breaker.State(); got != BreakerClosed {
+		t.Errorf("Expected closed after a successful probe, got %s", got)
+	}
+	if !breaker.Allow() || !breaker.Allow() {
+		t.Error("Expected closed breaker to admit requests")
+	}
+}
@@ -1,12 +1,14 @@
 package notification
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/awaymess/super-dashboard/backend/pkg/api"
 )
 
 // EmailProvider defines email sending interface.
@@ -14,12 +16,21 @@ type EmailProvider interface {
 	SendEmail(ctx context.Context, to []string, subject, body string) error
 }
 
+// Provider API base URLs.
+const (
+	sendGridBaseURL = "https://api.sendgrid.com"
+	telegramBaseURL = "https://api.telegram.org"
+	lineBaseURL     = "https://api.line.me"
+)
+
 // SendGridClient implements SendGrid email provider.
 type SendGridClient struct {
 	apiKey     string
 	fromEmail  string
 	fromName   string
+	baseURL    string
 	httpClient *http.Client
+	retry      api.RetryConfig
 }
 
 // NewSendGridClient creates a new SendGrid client.
@@ -28,12 +39,19 @@ func NewSendGridClient(apiKey, fromEmail, fromName string) *SendGridClient {
 		apiKey:    apiKey,
 		fromEmail: fromEmail,
 		fromName:  fromName,
+		baseURL:   sendGridBaseURL,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retry: retryConfig(api.RetryConfig{}),
 	}
 }
 
+// SetRetry changes how failed sends are retried. Zero fields use the defaults.
+func (c *SendGridClient) SetRetry(config api.RetryConfig) {
+	c.retry = retryConfig(config)
+}
+
 // SendEmail sends an email via SendGrid.
 func (c *SendGridClient) SendEmail(ctx context.Context, to []string, subject, body string) error {
 	payload := map[string]interface{}{
@@ -66,133 +84,95 @@ func (c *SendGridClient) SendEmail(ctx context.Context, to []string, subject, bo
 		return fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.sendgrid.com/v3/mail/send", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("sendgrid error: status %d", resp.StatusCode)
-	}
-
-	return nil
+	headers := map[string]string{"Authorization": "Bearer " + c.apiKey}
+	return postJSON(ctx, c.httpClient, c.retry, "sendgrid", c.baseURL+"/v3/mail/send", headers, jsonData)
 }
 
 // TelegramClient implements Telegram Bot API.
 type TelegramClient struct {
 	botToken   string
+	baseURL    string
 	httpClient *http.Client
+	retry      api.RetryConfig
 }
 
 // NewTelegramClient creates a new Telegram client.
 func NewTelegramClient(botToken string) *TelegramClient {
 	return &TelegramClient{
 		botToken: botToken,
+		baseURL:  telegramBaseURL,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retry: retryConfig(api.RetryConfig{}),
 	}
 }
 
+// SetRetry changes how failed sends are retried. Zero fields use the defaults.
+func (c *TelegramClient) SetRetry(config api.RetryConfig) {
+	c.retry = retryConfig(config)
+}
+
 // SendMessage sends a message to a Telegram chat.
 func (c *TelegramClient) SendMessage(ctx context.Context, chatID string, message string) error {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", c.botToken)
-
 	payload := map[string]interface{}{
 		"chat_id":    chatID,
 		"text":       message,
 		"parse_mode": "HTML",
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshal payload: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("telegram error: status %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.post(ctx, "sendMessage", payload)
 }
 
 // SendPhoto sends a photo to a Telegram chat.
 func (c *TelegramClient) SendPhoto(ctx context.Context, chatID, photoURL, caption string) error {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendPhoto", c.botToken)
-
 	payload := map[string]interface{}{
 		"chat_id": chatID,
 		"photo":   photoURL,
 		"caption": caption,
 	}
 
+	return c.post(ctx, "sendPhoto", payload)
+}
+
+// post calls a Bot API method with payload.
+func (c *TelegramClient) post(ctx context.Context, method string, payload map[string]interface{}) error {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("telegram error: status %d", resp.StatusCode)
-	}
-
-	return nil
+	url := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.botToken, method)
+	return postJSON(ctx, c.httpClient, c.retry, "telegram", url, nil, jsonData)
 }
 
 // LINEClient implements LINE Messaging API.
 type LINEClient struct {
 	channelToken string
+	baseURL      string
 	httpClient   *http.Client
+	retry        api.RetryConfig
 }
 
 // NewLINEClient creates a new LINE Messaging client.
 func NewLINEClient(channelToken string) *LINEClient {
 	return &LINEClient{
 		channelToken: channelToken,
+		baseURL:      lineBaseURL,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retry: retryConfig(api.RetryConfig{}),
 	}
 }
 
+// SetRetry changes how failed sends are retried. Zero fields use the defaults.
+func (c *LINEClient) SetRetry(config api.RetryConfig) {
+	c.retry = retryConfig(config)
+}
+
 // PushMessage sends a push message to a LINE user.
 func (c *LINEClient) PushMessage(ctx context.Context, userID string, message string) error {
-	url := "https://api.line.me/v2/bot/message/push"
-
 	payload := map[string]interface{}{
 		"to": userID,
 		"messages": []map[string]string{
@@ -203,36 +183,11 @@ func (c *LINEClient) PushMessage(ctx context.Context, userID string, message str
 		},
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshal payload: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.channelToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("LINE error: status %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.push(ctx, payload)
 }
 
 // PushFlexMessage sends a Flex Message (rich message) to LINE.
 func (c *LINEClient) PushFlexMessage(ctx context.Context, userID string, altText string, flexContent map[string]interface{}) error {
-	url := "https://api.line.me/v2/bot/message/push"
-
 	payload := map[string]interface{}{
 		"to": userID,
 		"messages": []map[string]interface{}{
@@ -244,36 +199,25 @@ func (c *LINEClient) PushFlexMessage(ctx context.Context, userID string, altText
 		},
 	}
 
+	return c.push(ctx, payload)
+}
+
+// push sends a push message payload.
+func (c *LINEClient) push(ctx context.Context, payload map[string]interface{}) error {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.channelToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("LINE error: status %d", resp.StatusCode)
-	}
-
-	return nil
+	headers := map[string]string{"Authorization": "Bearer " + c.channelToken}
+	return postJSON(ctx, c.httpClient, c.retry, "LINE", c.baseURL+"/v2/bot/message/push", headers, jsonData)
 }
 
 // DiscordClient implements Discord Webhook.
 type DiscordClient struct {
 	webhookURL string
 	httpClient *http.Client
+	retry      api.RetryConfig
 }
 
 // NewDiscordClient creates a new Discord webhook client.
@@ -283,38 +227,22 @@ func NewDiscordClient(webhookURL string) *DiscordClient {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retry: retryConfig(api.RetryConfig{}),
 	}
 }
 
+// SetRetry changes how failed sends are retried. Zero fields use the defaults.
+func (c *DiscordClient) SetRetry(config api.RetryConfig) {
+	c.retry = retryConfig(config)
+}
+
 // SendMessage sends a message to Discord channel.
 func (c *DiscordClient) SendMessage(ctx context.Context, content string) error {
 	payload := map[string]interface{}{
 		"content": content,
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshal payload: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.webhookURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("discord error: status %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.post(ctx, payload)
 }
 
 // SendEmbed sends a rich embed message to Discord.
@@ -323,29 +251,17 @@ func (c *DiscordClient) SendEmbed(ctx context.Context, embed DiscordEmbed) error
 		"embeds": []DiscordEmbed{embed},
 	}
 
+	return c.post(ctx, payload)
+}
+
+// post sends payload to the webhook.
+func (c *DiscordClient) post(ctx context.Context, payload map[string]interface{}) error {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.webhookURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("discord error: status %d", resp.StatusCode)
-	}
-
-	return nil
+	return postJSON(ctx, c.httpClient, c.retry, "discord", c.webhookURL, nil, jsonData)
 }
 
 // DiscordEmbed represents a Discord embed message.
@@ -379,6 +295,21 @@ type DiscordEmbedFooter struct {
 	IconURL string `json:"icon_url,omitempty"`
 }
 
+// Channel identifies one notification channel of a NotificationManager.
+type Channel string
+
+// Notification channels.
+const (
+	ChannelEmail    Channel = "email"
+	ChannelTelegram Channel = "telegram"
+	ChannelLINE     Channel = "line"
+	ChannelDiscord  Channel = "discord"
+)
+
+// ErrChannelNotConfigured is returned by Notify when the channel has no
+// client or the notification has no recipient on it.
+var ErrChannelNotConfigured = errors.New("notification channel not configured")
+
 // NotificationManager manages all notification channels.
 type NotificationManager struct {
 	email    EmailProvider
@@ -397,47 +328,67 @@ func NewNotificationManager(email EmailProvider, telegram *TelegramClient, line
 	}
 }
 
-// NotifyAll sends notification to all enabled channels.
-func (m *NotificationManager) NotifyAll(ctx context.Context, notification Notification) error {
-	var lastErr error
+// channels lists the channels in the order NotifyAll tries them.
+var channels = []Channel{ChannelEmail, ChannelTelegram, ChannelLINE, ChannelDiscord}
 
-	// Email
-	if m.email != nil && len(notification.EmailRecipients) > 0 {
-		if err := m.email.SendEmail(ctx, notification.EmailRecipients, notification.Subject, notification.Body); err != nil {
-			lastErr = fmt.Errorf("email notification failed: %w", err)
-		}
-	}
+// NotifyResult reports the outcome of NotifyAll per channel. Channels that
+// are not configured or have no recipient appear in neither field.
+type NotifyResult struct {
+	Delivered []Channel
+	Failed    map[Channel]error
+}
 
-	// Telegram
-	if m.telegram != nil && notification.TelegramChatID != "" {
-		if err := m.telegram.SendMessage(ctx, notification.TelegramChatID, notification.Message); err != nil {
-			lastErr = fmt.Errorf("telegram notification failed: %w", err)
+// NotifyAll sends notification to all enabled channels. A failure on one
+// channel does not stop the others; the returned error joins every
+// channel's failure.
+func (m *NotificationManager) NotifyAll(ctx context.Context, notification Notification) (NotifyResult, error) {
+	result := NotifyResult{Failed: make(map[Channel]error)}
+	var errs []error
+
+	for _, channel := range channels {
+		err := m.Notify(ctx, channel, notification)
+		switch {
+		case err == nil:
+			result.Delivered = append(result.Delivered, channel)
+		case errors.Is(err, ErrChannelNotConfigured):
+		default:
+			result.Failed[channel] = err
+			errs = append(errs, fmt.Errorf("%s notification failed: %w", channel, err))
 		}
 	}
 
-	// LINE
-	if m.line != nil && notification.LINEUserID != "" {
-		if err := m.line.PushMessage(ctx, notification.LINEUserID, notification.Message); err != nil {
-			lastErr = fmt.Errorf("LINE notification failed: %w", err)
-		}
-	}
+	return result, errors.Join(errs...)
+}
 
-	// Discord
-	if m.discord != nil {
-		if err := m.discord.SendMessage(ctx, notification.Message); err != nil {
-			lastErr = fmt.Errorf("discord notification failed: %w", err)
+// Notify sends notification on a single channel.
+func (m *NotificationManager) Notify(ctx context.Context, channel Channel, notification Notification) error {
+	switch channel {
+	case ChannelEmail:
+		if m.email != nil && len(notification.EmailRecipients) > 0 {
+			return m.email.SendEmail(ctx, notification.EmailRecipients, notification.Subject, notification.Body)
+		}
+	case ChannelTelegram:
+		if m.telegram != nil && notification.TelegramChatID != "" {
+			return m.telegram.SendMessage(ctx, notification.TelegramChatID, notification.Message)
+		}
+	case ChannelLINE:
+		if m.line != nil && notification.LINEUserID != "" {
+			return m.line.PushMessage(ctx, notification.LINEUserID, notification.Message)
+		}
+	case ChannelDiscord:
+		if m.discord != nil {
+			return m.discord.SendMessage(ctx, notification.Message)
 		}
 	}
-
-	return lastErr
+	return fmt.Errorf("%w: %s", ErrChannelNotConfigured, channel)
 }
 
 // Notification represents a multi-channel notification.
 type Notification struct {
-	Subject          string   `json:"subject"`
-	Message          string   `json:"message"`
-	Body             string   `json:"body"` // HTML body for email
-	EmailRecipients  []string `json:"emailRecipients,omitempty"`
-	TelegramChatID   string   `json:"telegramChatId,omitempty"`
-	LINEUserID       string   `json:"lineUserId,omitempty"`
+	Subject         string   `json:"subject"`
+	Message         string   `json:"message"`
+	Body            string   `json:"body"` // HTML body for email
+	EmailRecipients []string `json:"emailRecipients,omitempty"`
+	TelegramChatID  string   `json:"telegramChatId,omitempty"`
+	LINEUserID      string   `json:"lineUserId,omitempty"`
 }
@@ -0,0 +1,230 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/pkg/api"
+)
+
+// newFlakyServer fails the first failures requests with status, then
+// answers 200. It returns the server and its request counter.
+func newFlakyServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func fastRetry(attempts int) api.RetryConfig {
+	return api.RetryConfig{MaxAttempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func TestClients_RetryTransientFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		send   func(url string) error
+	}{
+		{"sendgrid", http.StatusServiceUnavailable, func(url string) error {
+			c := NewSendGridClient("key", "from@example.com", "Dashboard")
+			c.baseURL = url
+			c.SetRetry(fastRetry(3))
+			return c.SendEmail(context.Background(), []string{"to@example.com"}, "Subject", "<p>Body</p>")
+		}},
+		{"telegram", http.StatusTooManyRequests, func(url string) error {
+			c := NewTelegramClient("token")
+			c.baseURL = url
+			c.SetRetry(fastRetry(3))
+			return c.SendMessage(context.Background(), "chat", "hello")
+		}},
+		{"line", http.StatusBadGateway, func(url string) error {
+			c := NewLINEClient("token")
+			c.baseURL = url
+			c.SetRetry(fastRetry(3))
+			return c.PushMessage(context.Background(), "user", "hello")
+		}},
+		{"discord", http.StatusInternalServerError, func(url string) error {
+			c := NewDiscordClient(url)
+			c.SetRetry(fastRetry(3))
+			return c.SendMessage(context.Background(), "hello")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newFlakyServer(t, 2, tt.status, "")
+
+			if err := tt.send(server.URL); err != nil {
+				t.Fatalf("Expected success after retries, got %v", err)
+			}
+			if got := atomic.LoadInt32(calls); got != 3 {
+				t.Errorf("Expected 3 attempts, got %d", got)
+			}
+		})
+	}
+}
+
+func TestClients_GiveUpAfterMaxAttempts(t *testing.T) {
+	server, calls := newFlakyServer(t, 5, http.StatusServiceUnavailable, "")
+	client := NewDiscordClient(server.URL)
+	client.SetRetry(fastRetry(2))
+
+	err := client.SendMessage(context.Background(), "hello")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a 503 StatusError, got %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestClients_DoNotRetryClientErrors(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusBadRequest, "")
+	client := NewTelegramClient("token")
+	client.baseURL = server.URL
+	client.SetRetry(fastRetry(3))
+
+	if err := client.SendMessage(context.Background(), "chat", "hello"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestClients_DoNotRetryAfterRequestWritten(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		// Drop the connection after reading the request, as if the
+		// provider accepted it and the response was lost.
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := NewDiscordClient(server.URL)
+	client.SetRetry(fastRetry(3))
+
+	if err := client.SendMessage(context.Background(), "hello"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestClients_RetryUnsentRequests(t *testing.T) {
+	var dials int32
+	client := NewDiscordClient("http://discord.invalid/webhook")
+	client.httpClient = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			return nil, errors.New("connection refused")
+		},
+	}}
+	client.SetRetry(fastRetry(3))
+
+	if err := client.SendMessage(context.Background(), "hello"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if got := atomic.LoadInt32(&dials); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestClients_HonorRetryAfter(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, "1")
+	client := NewLINEClient("token")
+	client.baseURL = server.URL
+	client.SetRetry(api.RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second})
+
+	start := time.Now()
+	if err := client.PushMessage(context.Background(), "user", "hello"); err != nil {
+		t.Fatalf("Expected success after retry, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected to wait for Retry-After, waited %v", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestClients_StopWhenContextCancelled(t *testing.T) {
+	server, calls := newFlakyServer(t, 5, http.StatusServiceUnavailable, "")
+	client := NewDiscordClient(server.URL)
+	client.SetRetry(api.RetryConfig{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := client.SendMessage(ctx, "hello")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 attempt before cancellation, got %d", got)
+	}
+}
+
+func TestNotificationManager_NotifyAllJoinsErrors(t *testing.T) {
+	failing, _ := newFlakyServer(t, 100, http.StatusBadRequest, "")
+	working, workingCalls := newFlakyServer(t, 0, 0, "")
+
+	email := NewSendGridClient("key", "from@example.com", "Dashboard")
+	email.baseURL = failing.URL
+	telegram := NewTelegramClient("token")
+	telegram.baseURL = failing.URL
+	line := NewLINEClient("token")
+	line.baseURL = working.URL
+	manager := NewNotificationManager(email, telegram, line, nil)
+
+	result, err := manager.NotifyAll(context.Background(), Notification{
+		Message:         "hello",
+		EmailRecipients: []string{"to@example.com"},
+		TelegramChatID:  "chat",
+		LINEUserID:      "user",
+	})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	for _, want := range []string{"email notification failed", "telegram notification failed"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got %v", want, err)
+		}
+	}
+
+	if len(result.Delivered) != 1 || result.Delivered[0] != ChannelLINE {
+		t.Errorf("Expected only LINE to be delivered, got %v", result.Delivered)
+	}
+	if len(result.Failed) != 2 || result.Failed[ChannelEmail] == nil || result.Failed[ChannelTelegram] == nil {
+		t.Errorf("Expected email and telegram to fail, got %v", result.Failed)
+	}
+	if _, ok := result.Failed[ChannelDiscord]; ok {
+		t.Error("Expected the unconfigured discord channel not to be reported")
+	}
+	if got := atomic.LoadInt32(workingCalls); got != 1 {
+		t.Errorf("Expected LINE to be sent despite other failures, got %d calls", got)
+	}
+}
@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/pkg/api"
+)
+
+// StatusError is returned when a notification provider answers with a 4xx or
+// 5xx status code.
+type StatusError struct {
+	Service    string
+	StatusCode int
+	retryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s error: status %d", e.Service, e.StatusCode)
+}
+
+// unsentError is a transport error from before the request was fully
+// written, so the provider cannot have acted on it.
+type unsentError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (e *unsentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the transport error.
+func (e *unsentError) Unwrap() error {
+	return e.err
+}
+
+// retryConfig fills zero RetryConfig fields with the api package defaults.
+func retryConfig(config api.RetryConfig) api.RetryConfig {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = api.DefaultRetryAttempts
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = api.DefaultRetryBaseDelay
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = api.DefaultRetryMaxDelay
+	}
+	return config
+}
+
+// postJSON posts jsonData to url, retrying 429 and 5xx responses and
+// network errors from before the request was written with exponential
+// backoff. Other network errors are not retried: the provider may already
+// have delivered the notification. A Retry-After header overrides the
+// computed delay. It stops waiting as soon as ctx is done.
+func postJSON(ctx context.Context, httpClient *http.Client, retry api.RetryConfig, service, url string, headers map[string]string, jsonData []byte) error {
+	for attempt := 1; ; attempt++ {
+		err := postOnce(ctx, httpClient, service, url, headers, jsonData)
+		if err == nil || attempt >= retry.MaxAttempts || !retryable(ctx, err) {
+			return err
+		}
+
+		delay := retry.Backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.retryAfter > 0 {
+			delay = statusErr.retryAfter
+		}
+		if delay > retry.MaxDelay {
+			delay = retry.MaxDelay
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (after %d attempts: %v)", ctx.Err(), attempt, err)
+		case <-timer.C:
+		}
+	}
+}
+
+// postOnce performs a single POST attempt.
+func postOnce(ctx context.Context, httpClient *http.Client, service, url string, headers map[string]string, jsonData []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	var written atomic.Bool
+	req = req.WithContext(httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			written.Store(info.Err == nil)
+		},
+	}))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("send request: %w", err)
+		if !written.Load() {
+			return &unsentError{err: err}
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 400 {
+		return &StatusError{
+			Service:    service,
+			StatusCode: resp.StatusCode,
+			retryAfter: api.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	return nil
+}
+
+// retryable reports whether a failed attempt may succeed if repeated and is
+// safe to repeat: 429 and 5xx responses, and network errors from before the
+// request was written. Nothing is retried once ctx is done.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	var unsentErr *unsentError
+	return errors.As(err, &unsentErr)
+}
@@ -0,0 +1,196 @@
+// Package oauth runs the OAuth 2.0 authorization code flow, through
+// golang.org/x/oauth2, for the identity providers users can sign in with.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+)
+
+// ErrEmailNotVerified is returned when the provider has no verified email
+// address for the user.
+var ErrEmailNotVerified = errors.New("provider returned no verified email")
+
+// Token is the result of an authorization code exchange. Its Expiry is zero
+// when the provider's tokens do not expire.
+type Token = oauth2.Token
+
+// UserInfo is the profile of the signed-in user at the provider.
+type UserInfo struct {
+	ProviderUserID string
+	Email          string
+	Name           string
+	AvatarURL      string
+}
+
+// Provider runs the authorization code flow against one identity provider.
+type Provider struct {
+	config     oauth2.Config
+	httpClient *http.Client
+	userInfo   func(ctx context.Context, p *Provider, token *Token) (*UserInfo, error)
+}
+
+const (
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+	githubAPIURL      = "https://api.github.com"
+)
+
+// NewGoogleProvider creates a provider for signing in with Google.
+func NewGoogleProvider(clientID, clientSecret, redirectURL string) *Provider {
+	return &Provider{
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{"openid", "email", "profile"},
+			Endpoint:     endpoints.Google,
+		},
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		userInfo:   googleUserInfo(googleUserInfoURL),
+	}
+}
+
+// NewGitHubProvider creates a provider for signing in with GitHub.
+func NewGitHubProvider(clientID, clientSecret, redirectURL string) *Provider {
+	endpoint := endpoints.GitHub
+	endpoint.AuthStyle = oauth2.AuthStyleInParams
+	return &Provider{
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{"read:user", "user:email"},
+			Endpoint:     endpoint,
+		},
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		userInfo:   githubUserInfo(githubAPIURL),
+	}
+}
+
+// AuthCodeURL returns the provider's consent page URL. The provider sends
+// state back to the redirect URL unchanged.
+func (p *Provider) AuthCodeURL(state string) string {
+	return p.config.AuthCodeURL(state)
+}
+
+// Exchange trades an authorization code for tokens.
+func (p *Provider) Exchange(ctx context.Context, code string) (*Token, error) {
+	token, err := p.config.Exchange(p.clientContext(ctx), code)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange: %w", err)
+	}
+	return token, nil
+}
+
+// UserInfo fetches the signed-in user's profile. It returns
+// ErrEmailNotVerified when the provider has no verified email for the user.
+func (p *Provider) UserInfo(ctx context.Context, token *Token) (*UserInfo, error) {
+	return p.userInfo(ctx, p, token)
+}
+
+// clientContext makes oauth2 send its requests through the provider's
+// HTTP client.
+func (p *Provider) clientContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, p.httpClient)
+}
+
+// get fetches endpoint with the access token and decodes the JSON response.
+func (p *Provider) get(ctx context.Context, endpoint string, token *Token, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.config.Client(p.clientContext(ctx), token).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, v)
+}
+
+// googleUserInfo reads the OpenID Connect userinfo endpoint.
+func googleUserInfo(userInfoURL string) func(context.Context, *Provider, *Token) (*UserInfo, error) {
+	return func(ctx context.Context, p *Provider, token *Token) (*UserInfo, error) {
+		var body struct {
+			Sub           string `json:"sub"`
+			Email         string `json:"email"`
+			EmailVerified bool   `json:"email_verified"`
+			Name          string `json:"name"`
+			Picture       string `json:"picture"`
+		}
+		if err := p.get(ctx, userInfoURL, token, &body); err != nil {
+			return nil, fmt.Errorf("userinfo: %w", err)
+		}
+		if body.Email == "" || !body.EmailVerified {
+			return nil, ErrEmailNotVerified
+		}
+		return &UserInfo{
+			ProviderUserID: body.Sub,
+			Email:          body.Email,
+			Name:           body.Name,
+			AvatarURL:      body.Picture,
+		}, nil
+	}
+}
+
+// githubUserInfo reads the user and their primary verified email from the
+// GitHub API. The profile email is not used as it may be unverified.
+func githubUserInfo(apiURL string) func(context.Context, *Provider, *Token) (*UserInfo, error) {
+	return func(ctx context.Context, p *Provider, token *Token) (*UserInfo, error) {
+		var user struct {
+			ID        int64  `json:"id"`
+			Login     string `json:"login"`
+			Name      string `json:"name"`
+			AvatarURL string `json:"avatar_url"`
+		}
+		if err := p.get(ctx, apiURL+"/user", token, &user); err != nil {
+			return nil, fmt.Errorf("user: %w", err)
+		}
+
+		var emails []struct {
+			Email    string `json:"email"`
+			Primary  bool   `json:"primary"`
+			Verified bool   `json:"verified"`
+		}
+		if err := p.get(ctx, apiURL+"/user/emails", token, &emails); err != nil {
+			return nil, fmt.Errorf("user emails: %w", err)
+		}
+
+		info := &UserInfo{
+			ProviderUserID: fmt.Sprintf("%d", user.ID),
+			Name:           user.Name,
+			AvatarURL:      user.AvatarURL,
+		}
+		if info.Name == "" {
+			info.Name = user.Login
+		}
+		for _, e := range emails {
+			if e.Primary && e.Verified {
+				info.Email = e.Email
+			}
+		}
+		if info.Email == "" {
+			return nil, ErrEmailNotVerified
+		}
+		return info, nil
+	}
+}
@@ -0,0 +1,118 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// newGitHubServer serves the GitHub token, user and email endpoints. The
+// token endpoint only accepts the code "good-code".
+func newGitHubServer(t *testing.T, emails string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "good-code" || r.PostForm.Get("client_secret") != "secret" {
+			w.Write([]byte(`{"error":"bad_verification_code","error_description":"The code passed is incorrect or expired."}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"gh-token","token_type":"bearer","scope":"read:user,user:email"}`))
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":42,"login":"octocat","name":"","avatar_url":"https://example.com/a.png"}`))
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(emails))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestGitHubProvider(server *httptest.Server) *Provider {
+	p := NewGitHubProvider("client", "secret", "http://localhost/callback")
+	p.config.Endpoint.AuthURL = server.URL + "/login/oauth/authorize"
+	p.config.Endpoint.TokenURL = server.URL + "/login/oauth/access_token"
+	p.userInfo = githubUserInfo(server.URL)
+	return p
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	p := NewGoogleProvider("client", "secret", "http://localhost/callback")
+
+	u, err := url.Parse(p.AuthCodeURL("state-123"))
+	if err != nil {
+		t.Fatalf("Invalid URL: %v", err)
+	}
+	q := u.Query()
+	if q.Get("state") != "state-123" || q.Get("client_id") != "client" || q.Get("redirect_uri") != "http://localhost/callback" {
+		t.Errorf("Unexpected query %v", q)
+	}
+	if q.Get("response_type") != "code" || q.Get("scope") != "openid email profile" {
+		t.Errorf("Unexpected query %v", q)
+	}
+}
+
+func TestProvider_GitHubExchangeAndUserInfo(t *testing.T) {
+	server := newGitHubServer(t, `[{"email":"old@example.com","primary":false,"verified":true},{"email":"octo@example.com","primary":true,"verified":true}]`)
+	p := newTestGitHubProvider(server)
+
+	token, err := p.Exchange(context.Background(), "good-code")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if token.AccessToken != "gh-token" || !token.Expiry.IsZero() {
+		t.Errorf("Unexpected token %+v", token)
+	}
+
+	info, err := p.UserInfo(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := UserInfo{ProviderUserID: "42", Email: "octo@example.com", Name: "octocat", AvatarURL: "https://example.com/a.png"}
+	if *info != want {
+		t.Errorf("Expected %+v, got %+v", want, *info)
+	}
+}
+
+func TestProvider_ExchangeRejectsBadCode(t *testing.T) {
+	server := newGitHubServer(t, `[]`)
+	p := newTestGitHubProvider(server)
+
+	if _, err := p.Exchange(context.Background(), "bad-code"); err == nil {
+		t.Error("Expected an error for a rejected code")
+	}
+}
+
+func TestProvider_GitHubRequiresVerifiedEmail(t *testing.T) {
+	server := newGitHubServer(t, `[{"email":"octo@example.com","primary":true,"verified":false}]`)
+	p := newTestGitHubProvider(server)
+
+	_, err := p.UserInfo(context.Background(), &Token{AccessToken: "gh-token"})
+	if !errors.Is(err, ErrEmailNotVerified) {
+		t.Errorf("Expected ErrEmailNotVerified, got %v", err)
+	}
+}
+
+func TestProvider_GoogleRequiresVerifiedEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sub":"1","email":"user@example.com","email_verified":false}`))
+	}))
+	defer server.Close()
+
+	p := NewGoogleProvider("client", "secret", "http://localhost/callback")
+	p.userInfo = googleUserInfo(server.URL)
+
+	_, err := p.UserInfo(context.Background(), &Token{AccessToken: "token"})
+	if !errors.Is(err, ErrEmailNotVerified) {
+		t.Errorf("Expected ErrEmailNotVerified, got %v", err)
+	}
+}
@@ -5,13 +5,13 @@ import (
 	"fmt"
 	"time"
 
-	"super-dashboard/backend/pkg/api"
+	"github.com/awaymess/super-dashboard/backend/pkg/api"
 )
 
 // BetfairClient implements Betfair Exchange API client.
 type BetfairClient struct {
-	client    *api.Client
-	appKey    string
+	client       *api.Client
+	appKey       string
 	sessionToken string
 }
 
@@ -22,7 +22,7 @@ func NewBetfairClient(appKey, sessionToken string) *BetfairClient {
 		Timeout:      30 * time.Second,
 		RateLimitRPS: 5, // Betfair allows 5 requests/second
 		CustomHeaders: map[string]string{
-			"X-Application": appKey,
+			"X-Application":    appKey,
 			"X-Authentication": sessionToken,
 		},
 	}
@@ -57,39 +57,39 @@ type Event struct {
 
 // MarketCatalogue represents available betting markets.
 type MarketCatalogue struct {
-	MarketID     string       `json:"marketId"`
-	MarketName   string       `json:"marketName"`
-	MarketType   string       `json:"marketType"`
-	Competition  Competition  `json:"competition"`
-	Event        Event        `json:"event"`
-	Runners      []Runner     `json:"runners"`
-	TotalMatched float64      `json:"totalMatched"`
+	MarketID     string      `json:"marketId"`
+	MarketName   string      `json:"marketName"`
+	MarketType   string      `json:"marketType"`
+	Competition  Competition `json:"competition"`
+	Event        Event       `json:"event"`
+	Runners      []Runner    `json:"runners"`
+	TotalMatched float64     `json:"totalMatched"`
 }
 
 // Runner represents a selection in a market.
 type Runner struct {
-	SelectionID int64  `json:"selectionId"`
-	RunnerName  string `json:"runnerName"`
-	Handicap    float64 `json:"handicap,omitempty"`
-	SortPriority int    `json:"sortPriority"`
+	SelectionID  int64   `json:"selectionId"`
+	RunnerName   string  `json:"runnerName"`
+	Handicap     float64 `json:"handicap,omitempty"`
+	SortPriority int     `json:"sortPriority"`
 }
 
 // MarketBook represents current odds and liquidity.
 type MarketBook struct {
-	MarketID    string         `json:"marketId"`
-	IsMarketDataDelayed bool   `json:"isMarketDataDelayed"`
-	Status      string         `json:"status"`
-	Runners     []RunnerBook   `json:"runners"`
-	TotalMatched float64       `json:"totalMatched"`
+	MarketID            string       `json:"marketId"`
+	IsMarketDataDelayed bool         `json:"isMarketDataDelayed"`
+	Status              string       `json:"status"`
+	Runners             []RunnerBook `json:"runners"`
+	TotalMatched        float64      `json:"totalMatched"`
 }
 
 // RunnerBook represents current odds for a runner.
 type RunnerBook struct {
-	SelectionID   int64           `json:"selectionId"`
-	Status        string          `json:"status"`
-	LastPriceTraded float64       `json:"lastPriceTraded,omitempty"`
-	TotalMatched  float64         `json:"totalMatched"`
-	ExchangePrices ExchangePrices `json:"ex"`
+	SelectionID     int64          `json:"selectionId"`
+	Status          string         `json:"status"`
+	LastPriceTraded float64        `json:"lastPriceTraded,omitempty"`
+	TotalMatched    float64        `json:"totalMatched"`
+	ExchangePrices  ExchangePrices `json:"ex"`
 }
 
 // ExchangePrices represents available odds.
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"time"
 
-	"super-dashboard/backend/pkg/api"
+	"github.com/awaymess/super-dashboard/backend/pkg/api"
 )
 
 // PinnacleClient implements Pinnacle API client.
@@ -16,10 +16,10 @@ type PinnacleClient struct {
 // NewPinnacleClient creates a new Pinnacle API client.
 func NewPinnacleClient(apiKey string) *PinnacleClient {
 	config := api.ClientConfig{
-		BaseURL:       "https://api.pinnacle.com/v1",
-		APIKey:        apiKey,
-		Timeout:       30 * time.Second,
-		RateLimitRPS:  10, // Pinnacle allows 10 requests/second
+		BaseURL:      "https://api.pinnacle.com/v1",
+		APIKey:       apiKey,
+		Timeout:      30 * time.Second,
+		RateLimitRPS: 10, // Pinnacle allows 10 requests/second
 		CustomHeaders: map[string]string{
 			"X-API-Key": apiKey,
 		},
@@ -45,24 +45,24 @@ type League struct {
 
 // Match represents a match/event.
 type Match struct {
-	ID          int64     `json:"id"`
-	SportID     int       `json:"sportId"`
-	LeagueID    int       `json:"leagueId"`
-	HomeTeam    string    `json:"home"`
-	AwayTeam    string    `json:"away"`
-	StartTime   time.Time `json:"starts"`
-	Live        bool      `json:"live"`
-	HomeScore   *int      `json:"homeScore,omitempty"`
-	AwayScore   *int      `json:"awayScore,omitempty"`
+	ID        int64     `json:"id"`
+	SportID   int       `json:"sportId"`
+	LeagueID  int       `json:"leagueId"`
+	HomeTeam  string    `json:"home"`
+	AwayTeam  string    `json:"away"`
+	StartTime time.Time `json:"starts"`
+	Live      bool      `json:"live"`
+	HomeScore *int      `json:"homeScore,omitempty"`
+	AwayScore *int      `json:"awayScore,omitempty"`
 }
 
 // Odds represents betting odds.
 type Odds struct {
-	MatchID     int64              `json:"matchId"`
-	UpdatedAt   time.Time          `json:"updatedAt"`
-	Moneyline   *MoneylineOdds     `json:"moneyline,omitempty"`
-	Spread      *SpreadOdds        `json:"spread,omitempty"`
-	Total       *TotalOdds         `json:"total,omitempty"`
+	MatchID   int64          `json:"matchId"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	Moneyline *MoneylineOdds `json:"moneyline,omitempty"`
+	Spread    *SpreadOdds    `json:"spread,omitempty"`
+	Total     *TotalOdds     `json:"total,omitempty"`
 }
 
 // MoneylineOdds represents 1X2 or moneyline odds.
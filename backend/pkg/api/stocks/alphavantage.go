@@ -2,17 +2,39 @@ package stocks
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/rs/zerolog/log"
+
 	"super-dashboard/backend/pkg/api"
 )
 
-// AlphaVantageClient implements Alpha Vantage API client.
+// ErrRateLimited is returned when Alpha Vantage throttles a request. It
+// answers with HTTP 200 and a "Note" or "Information" message instead of data.
+var ErrRateLimited = errors.New("alpha vantage rate limit reached")
+
+// ErrNoQuoteData is returned when Alpha Vantage answers a quote request with an
+// empty "Global Quote", as it does for unknown symbols.
+var ErrNoQuoteData = errors.New("alpha vantage returned no quote data")
+
+// AlphaVantageClient implements Alpha Vantage API client. Quotes and company
+// overviews are cached, since the free tier allows only 5 calls a minute.
 type AlphaVantageClient struct {
-	client *api.Client
-	apiKey string
+	client      *api.Client
+	apiKey      string
+	cache       Cache
+	quoteTTL    time.Duration
+	overviewTTL time.Duration
 }
 
 // NewAlphaVantageClient creates a new Alpha Vantage API client.
@@ -25,82 +47,255 @@ func NewAlphaVantageClient(apiKey string) *AlphaVantageClient {
 	}
 
 	return &AlphaVantageClient{
-		client: api.NewClient(config),
-		apiKey: apiKey,
+		client:      api.NewClient(config),
+		apiKey:      apiKey,
+		cache:       NewMemoryCache(),
+		quoteTTL:    DefaultQuoteCacheTTL,
+		overviewTTL: DefaultOverviewCacheTTL,
+	}
+}
+
+// SetCache replaces the client's in-memory response cache, e.g. with a
+// Redis-backed one shared between instances. A nil cache disables caching.
+func (c *AlphaVantageClient) SetCache(cache Cache) {
+	c.cache = cache
+}
+
+// SetCacheTTLs sets how long quotes and company overviews are cached. A
+// non-positive TTL disables caching for that response.
+func (c *AlphaVantageClient) SetCacheTTLs(quote, overview time.Duration) {
+	c.quoteTTL = quote
+	c.overviewTTL = overview
+}
+
+// alphaVantageCacheKey identifies a request by its function, symbol and
+// remaining parameters. The API key is left out.
+func alphaVantageCacheKey(params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "function" && name != "symbol" && name != "apikey" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	key := "alphavantage:" + params["function"] + ":" + params["symbol"]
+	for _, name := range names {
+		key += ":" + name + "=" + params[name]
+	}
+	return key
+}
+
+// fromCache decodes the cached response to the request into dest, reporting
+// whether there was one.
+func (c *AlphaVantageClient) fromCache(ctx context.Context, params map[string]string, ttl time.Duration, dest interface{}) bool {
+	if c.cache == nil || ttl <= 0 {
+		return false
+	}
+	if err := c.cache.GetJSON(ctx, alphaVantageCacheKey(params), dest); err != nil {
+		alphaVantageCacheTotals.misses.Add(1)
+		return false
+	}
+	alphaVantageCacheTotals.hits.Add(1)
+	return true
+}
+
+// storeInCache caches the response to the request. Failures are only logged.
+func (c *AlphaVantageClient) storeInCache(ctx context.Context, params map[string]string, ttl time.Duration, value interface{}) {
+	if c.cache == nil || ttl <= 0 {
+		return
+	}
+	if err := c.cache.SetJSON(ctx, alphaVantageCacheKey(params), value, ttl); err != nil {
+		log.Warn().Err(err).Str("function", params["function"]).Str("symbol", params["symbol"]).
+			Msg("Failed to cache Alpha Vantage response")
 	}
 }
 
 // Quote represents a stock quote.
 type Quote struct {
-	Symbol           string    `json:"symbol"`
-	Price            float64   `json:"price"`
-	Change           float64   `json:"change"`
-	ChangePercent    float64   `json:"changePercent"`
-	Volume           int64     `json:"volume"`
-	Open             float64   `json:"open"`
-	High             float64   `json:"high"`
-	Low              float64   `json:"low"`
-	PreviousClose    float64   `json:"previousClose"`
-	Timestamp        time.Time `json:"timestamp"`
+	Symbol        string    `json:"symbol"`
+	Price         float64   `json:"price"`
+	Change        float64   `json:"change"`
+	ChangePercent float64   `json:"changePercent"`
+	Volume        int64     `json:"volume"`
+	Open          float64   `json:"open"`
+	High          float64   `json:"high"`
+	Low           float64   `json:"low"`
+	PreviousClose float64   `json:"previousClose"`
+	Timestamp     time.Time `json:"timestamp"`
 }
 
 // TimeSeriesDaily represents daily price data.
 type TimeSeriesDaily struct {
-	Symbol     string          `json:"symbol"`
-	Interval   string          `json:"interval"`
-	TimeSeries []PricePoint    `json:"timeSeries"`
+	Symbol        string       `json:"symbol"`
+	Interval      string       `json:"interval"`
+	TimeSeries    []PricePoint `json:"timeSeries"`
 	LastRefreshed time.Time    `json:"lastRefreshed"`
 }
 
 // PricePoint represents a single price data point.
 type PricePoint struct {
-	Date      time.Time `json:"date"`
-	Open      float64   `json:"open"`
-	High      float64   `json:"high"`
-	Low       float64   `json:"low"`
-	Close     float64   `json:"close"`
-	Volume    int64     `json:"volume"`
+	Date   time.Time `json:"date"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume int64     `json:"volume"`
 }
 
 // CompanyOverview represents fundamental data.
 type CompanyOverview struct {
-	Symbol                string  `json:"Symbol"`
-	Name                  string  `json:"Name"`
-	Description           string  `json:"Description"`
-	Exchange              string  `json:"Exchange"`
-	Currency              string  `json:"Currency"`
-	Country               string  `json:"Country"`
-	Sector                string  `json:"Sector"`
-	Industry              string  `json:"Industry"`
-	MarketCapitalization  float64 `json:"MarketCapitalization,string"`
-	PERatio               float64 `json:"PERatio,string"`
-	PEGRatio              float64 `json:"PEGRatio,string"`
-	BookValue             float64 `json:"BookValue,string"`
-	DividendPerShare      float64 `json:"DividendPerShare,string"`
-	DividendYield         float64 `json:"DividendYield,string"`
-	EPS                   float64 `json:"EPS,string"`
-	RevenuePerShareTTM    float64 `json:"RevenuePerShareTTM,string"`
-	ProfitMargin          float64 `json:"ProfitMargin,string"`
-	OperatingMarginTTM    float64 `json:"OperatingMarginTTM,string"`
-	ReturnOnAssetsTTM     float64 `json:"ReturnOnAssetsTTM,string"`
-	ReturnOnEquityTTM     float64 `json:"ReturnOnEquityTTM,string"`
-	RevenueTTM            float64 `json:"RevenueTTM,string"`
-	GrossProfitTTM        float64 `json:"GrossProfitTTM,string"`
-	DilutedEPSTTM         float64 `json:"DilutedEPSTTM,string"`
+	Symbol                     string  `json:"Symbol"`
+	Name                       string  `json:"Name"`
+	Description                string  `json:"Description"`
+	Exchange                   string  `json:"Exchange"`
+	Currency                   string  `json:"Currency"`
+	Country                    string  `json:"Country"`
+	Sector                     string  `json:"Sector"`
+	Industry                   string  `json:"Industry"`
+	MarketCapitalization       float64 `json:"MarketCapitalization,string"`
+	PERatio                    float64 `json:"PERatio,string"`
+	PEGRatio                   float64 `json:"PEGRatio,string"`
+	BookValue                  float64 `json:"BookValue,string"`
+	DividendPerShare           float64 `json:"DividendPerShare,string"`
+	DividendYield              float64 `json:"DividendYield,string"`
+	EPS                        float64 `json:"EPS,string"`
+	RevenuePerShareTTM         float64 `json:"RevenuePerShareTTM,string"`
+	ProfitMargin               float64 `json:"ProfitMargin,string"`
+	OperatingMarginTTM         float64 `json:"OperatingMarginTTM,string"`
+	ReturnOnAssetsTTM          float64 `json:"ReturnOnAssetsTTM,string"`
+	ReturnOnEquityTTM          float64 `json:"ReturnOnEquityTTM,string"`
+	RevenueTTM                 float64 `json:"RevenueTTM,string"`
+	GrossProfitTTM             float64 `json:"GrossProfitTTM,string"`
+	DilutedEPSTTM              float64 `json:"DilutedEPSTTM,string"`
 	QuarterlyEarningsGrowthYOY float64 `json:"QuarterlyEarningsGrowthYOY,string"`
 	QuarterlyRevenueGrowthYOY  float64 `json:"QuarterlyRevenueGrowthYOY,string"`
-	AnalystTargetPrice    float64 `json:"AnalystTargetPrice,string"`
-	TrailingPE            float64 `json:"TrailingPE,string"`
-	ForwardPE             float64 `json:"ForwardPE,string"`
-	PriceToSalesRatioTTM  float64 `json:"PriceToSalesRatioTTM,string"`
-	PriceToBookRatio      float64 `json:"PriceToBookRatio,string"`
-	EVToRevenue           float64 `json:"EVToRevenue,string"`
-	EVToEBITDA            float64 `json:"EVToEBITDA,string"`
-	Beta                  float64 `json:"Beta,string"`
-	High52Week            float64 `json:"52WeekHigh,string"`
-	Low52Week             float64 `json:"52WeekLow,string"`
-	MovingAverage50Day    float64 `json:"50DayMovingAverage,string"`
-	MovingAverage200Day   float64 `json:"200DayMovingAverage,string"`
+	AnalystTargetPrice         float64 `json:"AnalystTargetPrice,string"`
+	TrailingPE                 float64 `json:"TrailingPE,string"`
+	ForwardPE                  float64 `json:"ForwardPE,string"`
+	PriceToSalesRatioTTM       float64 `json:"PriceToSalesRatioTTM,string"`
+	PriceToBookRatio           float64 `json:"PriceToBookRatio,string"`
+	EVToRevenue                float64 `json:"EVToRevenue,string"`
+	EVToEBITDA                 float64 `json:"EVToEBITDA,string"`
+	Beta                       float64 `json:"Beta,string"`
+	High52Week                 float64 `json:"52WeekHigh,string"`
+	Low52Week                  float64 `json:"52WeekLow,string"`
+	MovingAverage50Day         float64 `json:"50DayMovingAverage,string"`
+	MovingAverage200Day        float64 `json:"200DayMovingAverage,string"`
+
+	// SkippedFields lists numeric fields that Alpha Vantage returned without a
+	// usable value ("None", "", "-" or similar). They are left as zero.
+	SkippedFields []string `json:"-"`
+}
+
+// overviewNumericFields holds the JSON names of CompanyOverview's numeric fields.
+var overviewNumericFields = numericJSONFields(reflect.TypeOf(CompanyOverview{}))
+
+// numericJSONFields returns the JSON names of t's float64 fields.
+func numericJSONFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Float64 {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// UnmarshalJSON decodes a company overview. Alpha Vantage returns "None", ""
+// or "-" for numeric fields it has no data for, which the ",string" tags
+// reject; those fields are decoded as zero and listed in SkippedFields
+// instead of failing the whole overview.
+func (o *CompanyOverview) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var skipped []string
+	for _, field := range overviewNumericFields {
+		value, ok := raw[field]
+		if !ok {
+			continue
+		}
+
+		number, ok := parseNumericField(value)
+		if !ok {
+			delete(raw, field)
+			skipped = append(skipped, field)
+			continue
+		}
+		raw[field] = json.RawMessage(strconv.Quote(number))
+	}
+
+	cleaned, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	// The alias type drops this method so decoding does not recurse.
+	type companyOverview CompanyOverview
+	var overview companyOverview
+	if err := json.Unmarshal(cleaned, &overview); err != nil {
+		return err
+	}
+
+	*o = CompanyOverview(overview)
+	o.SkippedFields = skipped
+	return nil
+}
+
+// parseNumericField returns a numeric field's value as a number string,
+// accepting both quoted and bare numbers.
+func parseNumericField(value json.RawMessage) (string, bool) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		var f float64
+		if err := json.Unmarshal(value, &f); err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64), true
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", false
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64), true
+}
+
+// decodeAlphaVantage decodes an Alpha Vantage response into target, returning
+// ErrRateLimited for throttle messages that would otherwise decode as empty data.
+func decodeAlphaVantage(resp *http.Response, target interface{}) error {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	var message struct {
+		Note        string `json:"Note"`
+		Information string `json:"Information"`
+	}
+	if err := json.Unmarshal(body, &message); err == nil {
+		if message.Note != "" {
+			return fmt.Errorf("%w: %s", ErrRateLimited, message.Note)
+		}
+		if message.Information != "" {
+			return fmt.Errorf("%w: %s", ErrRateLimited, message.Information)
+		}
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
 }
 
 // GetQuote retrieves real-time quote for a symbol.
@@ -111,6 +306,11 @@ func (c *AlphaVantageClient) GetQuote(ctx context.Context, symbol string) (*Quot
 		"apikey":   c.apiKey,
 	}
 
+	var cached Quote
+	if c.fromCache(ctx, params, c.quoteTTL, &cached) {
+		return &cached, nil
+	}
+
 	resp, err := c.client.Get(ctx, "", params)
 	if err != nil {
 		return nil, fmt.Errorf("get quote: %w", err)
@@ -131,11 +331,14 @@ func (c *AlphaVantageClient) GetQuote(ctx context.Context, symbol string) (*Quot
 		} `json:"Global Quote"`
 	}
 
-	if err := api.DecodeResponse(resp, &result); err != nil {
+	if err := decodeAlphaVantage(resp, &result); err != nil {
 		return nil, err
 	}
 
 	gq := result.GlobalQuote
+	if gq.Symbol == "" || gq.Price == "" {
+		return nil, fmt.Errorf("%w for %s", ErrNoQuoteData, symbol)
+	}
 	quote := &Quote{
 		Symbol: gq.Symbol,
 	}
@@ -148,7 +351,7 @@ func (c *AlphaVantageClient) GetQuote(ctx context.Context, symbol string) (*Quot
 	quote.High, _ = strconv.ParseFloat(gq.High, 64)
 	quote.Low, _ = strconv.ParseFloat(gq.Low, 64)
 	quote.PreviousClose, _ = strconv.ParseFloat(gq.PreviousClose, 64)
-	
+
 	// Parse change percent (remove %)
 	changePercentStr := gq.ChangePercent
 	if len(changePercentStr) > 0 && changePercentStr[len(changePercentStr)-1] == '%' {
@@ -159,6 +362,7 @@ func (c *AlphaVantageClient) GetQuote(ctx context.Context, symbol string) (*Quot
 	// Parse date
 	quote.Timestamp, _ = time.Parse("2006-01-02", gq.LatestTradingDay)
 
+	c.storeInCache(ctx, params, c.quoteTTL, quote)
 	return quote, nil
 }
 
@@ -195,13 +399,13 @@ func (c *AlphaVantageClient) GetDailyTimeSeries(ctx context.Context, symbol stri
 		} `json:"Time Series (Daily)"`
 	}
 
-	if err := api.DecodeResponse(resp, &result); err != nil {
+	if err := decodeAlphaVantage(resp, &result); err != nil {
 		return nil, err
 	}
 
 	ts := &TimeSeriesDaily{
-		Symbol:   result.MetaData.Symbol,
-		Interval: "daily",
+		Symbol:     result.MetaData.Symbol,
+		Interval:   "daily",
 		TimeSeries: make([]PricePoint, 0, len(result.TimeSeriesDaily)),
 	}
 
@@ -210,17 +414,17 @@ func (c *AlphaVantageClient) GetDailyTimeSeries(ctx context.Context, symbol stri
 	// Convert map to slice
 	for dateStr, data := range result.TimeSeriesDaily {
 		date, _ := time.Parse("2006-01-02", dateStr)
-		
+
 		point := PricePoint{
 			Date: date,
 		}
-		
+
 		point.Open, _ = strconv.ParseFloat(data.Open, 64)
 		point.High, _ = strconv.ParseFloat(data.High, 64)
 		point.Low, _ = strconv.ParseFloat(data.Low, 64)
 		point.Close, _ = strconv.ParseFloat(data.Close, 64)
 		point.Volume, _ = strconv.ParseInt(data.Volume, 10, 64)
-		
+
 		ts.TimeSeries = append(ts.TimeSeries, point)
 	}
 
@@ -235,25 +439,38 @@ func (c *AlphaVantageClient) GetCompanyOverview(ctx context.Context, symbol stri
 		"apikey":   c.apiKey,
 	}
 
+	var cached CompanyOverview
+	if c.fromCache(ctx, params, c.overviewTTL, &cached) {
+		return &cached, nil
+	}
+
 	resp, err := c.client.Get(ctx, "", params)
 	if err != nil {
 		return nil, fmt.Errorf("get company overview: %w", err)
 	}
 
 	var overview CompanyOverview
-	if err := api.DecodeResponse(resp, &overview); err != nil {
+	if err := decodeAlphaVantage(resp, &overview); err != nil {
 		return nil, err
 	}
 
+	if len(overview.SkippedFields) > 0 {
+		log.Warn().
+			Str("symbol", symbol).
+			Strs("fields", overview.SkippedFields).
+			Msg("Alpha Vantage overview has fields without values, using zero")
+	}
+
+	c.storeInCache(ctx, params, c.overviewTTL, &overview)
 	return &overview, nil
 }
 
 // TechnicalIndicator represents technical indicator data.
 type TechnicalIndicator struct {
-	Symbol    string                 `json:"symbol"`
-	Indicator string                 `json:"indicator"`
-	Interval  string                 `json:"interval"`
-	Data      []IndicatorDataPoint   `json:"data"`
+	Symbol    string               `json:"symbol"`
+	Indicator string               `json:"indicator"`
+	Interval  string               `json:"interval"`
+	Data      []IndicatorDataPoint `json:"data"`
 }
 
 // IndicatorDataPoint represents a single indicator data point.
@@ -287,7 +504,7 @@ func (c *AlphaVantageClient) GetSMA(ctx context.Context, symbol string, interval
 		} `json:"Technical Analysis: SMA"`
 	}
 
-	if err := api.DecodeResponse(resp, &result); err != nil {
+	if err := decodeAlphaVantage(resp, &result); err != nil {
 		return nil, err
 	}
 
@@ -301,7 +518,7 @@ func (c *AlphaVantageClient) GetSMA(ctx context.Context, symbol string, interval
 	for dateStr, data := range result.TechnicalAnalysis {
 		date, _ := time.Parse("2006-01-02", dateStr)
 		value, _ := strconv.ParseFloat(data.SMA, 64)
-		
+
 		indicator.Data = append(indicator.Data, IndicatorDataPoint{
 			Date:  date,
 			Value: value,
@@ -336,7 +553,7 @@ func (c *AlphaVantageClient) GetRSI(ctx context.Context, symbol string, interval
 		} `json:"Technical Analysis: RSI"`
 	}
 
-	if err := api.DecodeResponse(resp, &result); err != nil {
+	if err := decodeAlphaVantage(resp, &result); err != nil {
 		return nil, err
 	}
 
@@ -350,7 +567,7 @@ func (c *AlphaVantageClient) GetRSI(ctx context.Context, symbol string, interval
 	for dateStr, data := range result.TechnicalAnalysis {
 		date, _ := time.Parse("2006-01-02", dateStr)
 		value, _ := strconv.ParseFloat(data.RSI, 64)
-		
+
 		indicator.Data = append(indicator.Data, IndicatorDataPoint{
 			Date:  date,
 			Value: value,
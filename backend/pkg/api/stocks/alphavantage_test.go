@@ -0,0 +1,149 @@
+package stocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"super-dashboard/backend/pkg/api"
+)
+
+func TestCompanyOverview_UnmarshalJSON(t *testing.T) {
+	data := []byte(`{
+		"Symbol": "ACME",
+		"Name": "Acme Corp",
+		"Description": "None",
+		"MarketCapitalization": "1500000000",
+		"PERatio": "None",
+		"PEGRatio": "",
+		"DividendYield": "-",
+		"EPS": 3.25,
+		"Beta": "n/a",
+		"52WeekHigh": " 120.5 "
+	}`)
+
+	var overview CompanyOverview
+	if err := json.Unmarshal(data, &overview); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if overview.Symbol != "ACME" || overview.Description != "None" {
+		t.Errorf("Expected string fields to be kept, got %q and %q", overview.Symbol, overview.Description)
+	}
+	if overview.MarketCapitalization != 1500000000 {
+		t.Errorf("Expected market cap 1500000000, got %v", overview.MarketCapitalization)
+	}
+	if overview.EPS != 3.25 {
+		t.Errorf("Expected EPS 3.25, got %v", overview.EPS)
+	}
+	if overview.High52Week != 120.5 {
+		t.Errorf("Expected 52 week high 120.5, got %v", overview.High52Week)
+	}
+	if overview.PERatio != 0 || overview.PEGRatio != 0 || overview.DividendYield != 0 || overview.Beta != 0 {
+		t.Error("Expected fields without values to be zero")
+	}
+
+	want := []string{"PERatio", "PEGRatio", "DividendYield", "Beta"}
+	if !reflect.DeepEqual(overview.SkippedFields, want) {
+		t.Errorf("Expected skipped fields %v, got %v", want, overview.SkippedFields)
+	}
+}
+
+func TestCompanyOverview_UnmarshalJSONInvalid(t *testing.T) {
+	var overview CompanyOverview
+	if err := json.Unmarshal([]byte(`["not", "an", "object"]`), &overview); err == nil {
+		t.Error("Expected an error for a non-object response")
+	}
+}
+
+func newTestAlphaVantageClient(t *testing.T, body string) *AlphaVantageClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return &AlphaVantageClient{
+		client: api.NewClient(api.ClientConfig{BaseURL: server.URL}),
+		apiKey: "test",
+	}
+}
+
+func TestAlphaVantageClient_RateLimited(t *testing.T) {
+	bodies := map[string]string{
+		"note":        `{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute and 500 calls per day."}`,
+		"information": `{"Information": "Thank you for using Alpha Vantage! You have reached the daily rate limit."}`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			client := newTestAlphaVantageClient(t, body)
+
+			if _, err := client.GetQuote(context.Background(), "AAPL"); !errors.Is(err, ErrRateLimited) {
+				t.Errorf("GetQuote: expected ErrRateLimited, got %v", err)
+			}
+			if _, err := client.GetCompanyOverview(context.Background(), "AAPL"); !errors.Is(err, ErrRateLimited) {
+				t.Errorf("GetCompanyOverview: expected ErrRateLimited, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAlphaVantageClient_GetQuote(t *testing.T) {
+	client := newTestAlphaVantageClient(t, `{"Global Quote": {"01. symbol": "AAPL", "05. price": "189.50", "10. change percent": "1.25%"}}`)
+
+	quote, err := client.GetQuote(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if quote.Symbol != "AAPL" || quote.Price != 189.5 || quote.ChangePercent != 1.25 {
+		t.Errorf("Unexpected quote %+v", quote)
+	}
+}
+
+func TestAlphaVantageClient_GetQuoteEmpty(t *testing.T) {
+	client := newTestAlphaVantageClient(t, `{"Global Quote": {}}`)
+
+	quote, err := client.GetQuote(context.Background(), "NOPE")
+	if !errors.Is(err, ErrNoQuoteData) {
+		t.Errorf("Expected ErrNoQuoteData, got %v", err)
+	}
+	if quote != nil {
+		t.Errorf("Expected no quote, got %+v", quote)
+	}
+}
+
+func TestAlphaVantageClient_GetDailyHistory(t *testing.T) {
+	client := newTestAlphaVantageClient(t, `{
+		"Meta Data": {"2. Symbol": "AAPL", "3. Last Refreshed": "2024-01-05"},
+		"Time Series (Daily)": {
+			"2024-01-05": {"1. open": "181.99", "2. high": "182.76", "3. low": "180.17", "4. close": "181.18", "5. volume": "62303300"},
+			"2024-01-02": {"1. open": "187.15", "2. high": "188.44", "3. low": "183.89", "4. close": "185.64", "5. volume": "82488700"},
+			"2024-01-04": {"1. open": "182.15", "2. high": "183.09", "3. low": "180.88", "4. close": "181.91", "5. volume": "71983600"},
+			"2023-12-29": {"1. open": "193.90", "2. high": "194.40", "3. low": "191.73", "4. close": "192.53", "5. volume": "42628800"}
+		}
+	}`)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 4, 23, 0, 0, 0, time.UTC)
+	points, err := client.GetDailyHistory(context.Background(), "AAPL", from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(points) != 2 {
+		t.Fatalf("Expected 2 points in range, got %d", len(points))
+	}
+	if points[0].Date.Format("2006-01-02") != "2024-01-02" || points[1].Date.Format("2006-01-02") != "2024-01-04" {
+		t.Errorf("Expected points oldest first, got %v and %v", points[0].Date, points[1].Date)
+	}
+	if points[0].Close != 185.64 || points[0].Volume != 82488700 {
+		t.Errorf("Unexpected point %+v", points[0])
+	}
+}
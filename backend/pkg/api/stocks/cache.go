@@ -0,0 +1,95 @@
+package stocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default Alpha Vantage response cache lifetimes. Quotes go stale quickly;
+// company overviews change at most once a day.
+const (
+	DefaultQuoteCacheTTL    = time.Minute
+	DefaultOverviewCacheTTL = 24 * time.Hour
+)
+
+// ErrCacheMiss is returned by MemoryCache for a key that is missing or expired.
+var ErrCacheMiss = errors.New("cache miss")
+
+// Cache stores API responses as JSON. Any GetJSON error is treated as a
+// miss. MemoryCache implements it, and so does the Redis client in
+// pkg/redis.
+type Cache interface {
+	GetJSON(ctx context.Context, key string, dest interface{}) error
+	SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+// memoryCacheEntry is a cached JSON value and when it expires.
+type memoryCacheEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// MemoryCache is an in-process Cache. Expired entries are dropped when read.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	now     func() time.Time
+}
+
+// NewMemoryCache creates an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryCacheEntry), now: time.Now}
+}
+
+// GetJSON decodes the value stored under key into dest.
+func (c *MemoryCache) GetJSON(ctx context.Context, key string, dest interface{}) error {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return ErrCacheMiss
+	}
+	return json.Unmarshal(entry.data, dest)
+}
+
+// SetJSON stores value under key until expiration has passed.
+func (c *MemoryCache) SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = memoryCacheEntry{data: data, expiresAt: c.now().Add(expiration)}
+	return nil
+}
+
+// AlphaVantageCacheStats counts Alpha Vantage response cache lookups.
+type AlphaVantageCacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// alphaVantageCacheTotals accumulates AlphaVantageCacheStats across all clients.
+var alphaVantageCacheTotals struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// AlphaVantageCacheMetrics returns the Alpha Vantage cache totals since startup.
+func AlphaVantageCacheMetrics() AlphaVantageCacheStats {
+	return AlphaVantageCacheStats{
+		Hits:   alphaVantageCacheTotals.hits.Load(),
+		Misses: alphaVantageCacheTotals.misses.Load(),
+	}
+}
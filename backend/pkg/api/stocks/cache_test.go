@@ -0,0 +1,112 @@
+package stocks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"super-dashboard/backend/pkg/api"
+)
+
+// newCachingAlphaVantageClient returns a client with a fresh MemoryCache and
+// the number of requests its test server has received.
+func newCachingAlphaVantageClient(t *testing.T, body string) (*AlphaVantageClient, *MemoryCache, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	cache := NewMemoryCache()
+	return &AlphaVantageClient{
+		client:      api.NewClient(api.ClientConfig{BaseURL: server.URL}),
+		apiKey:      "test",
+		cache:       cache,
+		quoteTTL:    DefaultQuoteCacheTTL,
+		overviewTTL: DefaultOverviewCacheTTL,
+	}, cache, &requests
+}
+
+func TestAlphaVantageClient_GetQuoteCached(t *testing.T) {
+	client, cache, requests := newCachingAlphaVantageClient(t, `{"Global Quote": {"01. symbol": "AAPL", "05. price": "189.50"}}`)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	before := AlphaVantageCacheMetrics()
+
+	for i := 0; i < 2; i++ {
+		quote, err := client.GetQuote(context.Background(), "AAPL")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if quote.Price != 189.5 {
+			t.Errorf("Expected price 189.5, got %v", quote.Price)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected 1 request within the TTL, got %d", got)
+	}
+
+	after := AlphaVantageCacheMetrics()
+	if after.Hits-before.Hits != 1 || after.Misses-before.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %+v then %+v", before, after)
+	}
+
+	now = now.Add(DefaultQuoteCacheTTL)
+	if _, err := client.GetQuote(context.Background(), "AAPL"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected the quote to be fetched again after the TTL, got %d requests", got)
+	}
+}
+
+func TestAlphaVantageClient_GetCompanyOverviewCached(t *testing.T) {
+	client, _, requests := newCachingAlphaVantageClient(t, `{"Symbol": "AAPL", "Name": "Apple Inc", "MarketCapitalization": "3000000000000", "PERatio": "None"}`)
+
+	for i := 0; i < 2; i++ {
+		overview, err := client.GetCompanyOverview(context.Background(), "AAPL")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if overview.Name != "Apple Inc" || overview.MarketCapitalization != 3000000000000 {
+			t.Errorf("Unexpected overview %+v", overview)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected 1 request within the TTL, got %d", got)
+	}
+
+	if _, err := client.GetCompanyOverview(context.Background(), "MSFT"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected another symbol to be fetched, got %d requests", got)
+	}
+}
+
+func TestAlphaVantageClient_CacheDisabled(t *testing.T) {
+	client, _, requests := newCachingAlphaVantageClient(t, `{"Global Quote": {"01. symbol": "AAPL", "05. price": "189.50"}}`)
+	client.SetCache(nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetQuote(context.Background(), "AAPL"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected every call to reach the server without a cache, got %d requests", got)
+	}
+}
+
+func TestAlphaVantageCacheKey(t *testing.T) {
+	key := alphaVantageCacheKey(map[string]string{"function": "TIME_SERIES_DAILY", "symbol": "AAPL", "outputsize": "full", "apikey": "secret"})
+	if key != "alphavantage:TIME_SERIES_DAILY:AAPL:outputsize=full" {
+		t.Errorf("Unexpected cache key %q", key)
+	}
+}
@@ -0,0 +1,75 @@
+package stocks
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// HistoryProvider fetches daily price history from a single data source.
+type HistoryProvider interface {
+	Name() string
+	// GetDailyHistory returns daily bars between from and to inclusive,
+	// oldest first.
+	GetDailyHistory(ctx context.Context, symbol string, from, to time.Time) ([]PricePoint, error)
+}
+
+// GetDailyHistory retrieves the full daily time series and keeps the bars
+// between from and to. Alpha Vantage has no date filter, so a single full
+// request covers any range.
+func (c *AlphaVantageClient) GetDailyHistory(ctx context.Context, symbol string, from, to time.Time) ([]PricePoint, error) {
+	ts, err := c.GetDailyTimeSeries(ctx, symbol, true)
+	if err != nil {
+		return nil, err
+	}
+	return filterPricePoints(ts.TimeSeries, from, to), nil
+}
+
+// YahooHistoryProvider adapts YahooFinanceClient to HistoryProvider.
+type YahooHistoryProvider struct {
+	client *YahooFinanceClient
+}
+
+// NewYahooHistoryProvider creates a HistoryProvider backed by Yahoo Finance.
+func NewYahooHistoryProvider(client *YahooFinanceClient) *YahooHistoryProvider {
+	return &YahooHistoryProvider{client: client}
+}
+
+// Name returns the provider name.
+func (p *YahooHistoryProvider) Name() string {
+	return "yahoo"
+}
+
+// GetDailyHistory retrieves daily bars from Yahoo Finance.
+func (p *YahooHistoryProvider) GetDailyHistory(ctx context.Context, symbol string, from, to time.Time) ([]PricePoint, error) {
+	// period2 is exclusive, so ask for the day after to.
+	points, err := p.client.GetHistoricalCSV(ctx, symbol, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	return filterPricePoints(points, from, to), nil
+}
+
+// filterPricePoints returns the points dated between from and to inclusive,
+// sorted oldest first. Points without a parseable date are dropped.
+func filterPricePoints(points []PricePoint, from, to time.Time) []PricePoint {
+	from = truncateDay(from)
+	to = truncateDay(to)
+
+	filtered := make([]PricePoint, 0, len(points))
+	for _, point := range points {
+		if point.Date.IsZero() || point.Date.Before(from) || point.Date.After(to) {
+			continue
+		}
+		filtered = append(filtered, point)
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].Date.Before(filtered[j].Date)
+	})
+	return filtered
+}
+
+// truncateDay returns midnight UTC of t's calendar day.
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,237 @@
+package stocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/awaymess/super-dashboard/backend/pkg/api"
+)
+
+// DefaultRateLimitCooldown is how long a rate-limited provider is skipped
+// before it is tried again.
+const DefaultRateLimitCooldown = time.Minute
+
+// ErrNoQuoteProviders is returned when a QuoteService has no providers to ask.
+var ErrNoQuoteProviders = errors.New("no quote providers available")
+
+// QuoteProvider fetches a real-time quote from a single data source.
+// AlphaVantageClient implements it directly and YahooQuoteProvider adapts
+// YahooFinanceClient, whose GetQuote returns Yahoo's richer quote.
+type QuoteProvider interface {
+	Name() string
+	GetQuote(ctx context.Context, symbol string) (*Quote, error)
+}
+
+// QuoteService fetches quotes from an ordered list of providers, failing over
+// to the next provider when one errors. A provider that returns
+// ErrRateLimited is skipped until its cooldown has passed; one whose circuit
+// breaker is open fails immediately with api.ErrProviderUnavailable.
+type QuoteService struct {
+	providers []QuoteProvider
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu           sync.Mutex
+	limitedUntil map[string]time.Time
+}
+
+// NewQuoteService creates a QuoteService that tries providers in order.
+func NewQuoteService(providers ...QuoteProvider) *QuoteService {
+	return &QuoteService{
+		providers:    providers,
+		cooldown:     DefaultRateLimitCooldown,
+		now:          time.Now,
+		limitedUntil: make(map[string]time.Time),
+	}
+}
+
+// NewDefaultQuoteService creates a QuoteService that asks Yahoo Finance
+// first and fails over to Alpha Vantage when a client is given.
+func NewDefaultQuoteService(alphaVantage *AlphaVantageClient) *QuoteService {
+	providers := []QuoteProvider{NewYahooQuoteProvider(NewYahooFinanceClient())}
+	if alphaVantage != nil {
+		providers = append(providers, alphaVantage)
+	}
+	return NewQuoteService(providers...)
+}
+
+// GetQuote returns the quote from the first provider that succeeds.
+func (s *QuoteService) GetQuote(ctx context.Context, symbol string) (*Quote, error) {
+	var errs []error
+	for _, provider := range s.providers {
+		name := provider.Name()
+		if s.isLimited(name) {
+			errs = append(errs, fmt.Errorf("%s: %w", name, ErrRateLimited))
+			continue
+		}
+
+		quote, err := provider.GetQuote(ctx, symbol)
+		if err == nil {
+			return quote, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if errors.Is(err, ErrRateLimited) {
+			s.markLimited(name)
+			log.Warn().Str("provider", name).Str("symbol", symbol).Dur("cooldown", s.cooldown).
+				Msg("Quote provider rate limited, failing over")
+		} else if errors.Is(err, api.ErrProviderUnavailable) {
+			log.Debug().Str("provider", name).Str("symbol", symbol).
+				Msg("Quote provider circuit open, failing over")
+		} else {
+			log.Warn().Err(err).Str("provider", name).Str("symbol", symbol).
+				Msg("Quote provider failed, failing over")
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+
+	if len(errs) == 0 {
+		return nil, ErrNoQuoteProviders
+	}
+	return nil, fmt.Errorf("get quote %s: %w", symbol, errors.Join(errs...))
+}
+
+// isLimited reports whether the named provider is still cooling down.
+func (s *QuoteService) isLimited(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.limitedUntil[name]
+	if !ok {
+		return false
+	}
+	if s.now().After(until) {
+		delete(s.limitedUntil, name)
+		return false
+	}
+	return true
+}
+
+// markLimited starts the named provider's cooldown.
+func (s *QuoteService) markLimited(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limitedUntil[name] = s.now().Add(s.cooldown)
+}
+
+// Name returns the provider name.
+func (c *AlphaVantageClient) Name() string {
+	return "alphavantage"
+}
+
+// YahooQuoteProvider adapts YahooFinanceClient to QuoteProvider.
+type YahooQuoteProvider struct {
+	client *YahooFinanceClient
+}
+
+// NewYahooQuoteProvider creates a QuoteProvider backed by Yahoo Finance.
+func NewYahooQuoteProvider(client *YahooFinanceClient) *YahooQuoteProvider {
+	return &YahooQuoteProvider{client: client}
+}
+
+// Name returns the provider name.
+func (p *YahooQuoteProvider) Name() string {
+	return "yahoo"
+}
+
+// GetQuote retrieves a quote from Yahoo Finance.
+func (p *YahooQuoteProvider) GetQuote(ctx context.Context, symbol string) (*Quote, error) {
+	yq, err := p.client.GetQuote(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return QuoteFromYahoo(yq), nil
+}
+
+// QuoteFromYahoo converts a Yahoo Finance quote to a Quote.
+func QuoteFromYahoo(yq *YahooQuote) *Quote {
+	return &Quote{
+		Symbol:        yq.Symbol,
+		Price:         yq.RegularMarketPrice,
+		Change:        yq.RegularMarketChange,
+		ChangePercent: yq.RegularMarketChangePercent,
+		Volume:        yq.RegularMarketVolume,
+		Open:          yq.RegularMarketOpen,
+		High:          yq.RegularMarketDayHigh,
+		Low:           yq.RegularMarketDayLow,
+		PreviousClose: yq.RegularMarketPreviousClose,
+		Timestamp:     yq.Timestamp,
+	}
+}
+
+// BatchQuoteClient fetches quotes for many symbols in one request.
+// YahooFinanceClient implements it.
+type BatchQuoteClient interface {
+	GetMultipleQuotes(ctx context.Context, symbols []string) ([]YahooQuote, error)
+}
+
+// BatchQuoteService fetches quotes for many symbols with one batch request,
+// then asks a fallback provider, one symbol at a time, for the symbols the
+// batch did not return.
+type BatchQuoteService struct {
+	batch    BatchQuoteClient
+	fallback QuoteProvider
+}
+
+// NewBatchQuoteService creates a BatchQuoteService. The fallback may be nil.
+func NewBatchQuoteService(batch BatchQuoteClient, fallback QuoteProvider) *BatchQuoteService {
+	return &BatchQuoteService{batch: batch, fallback: fallback}
+}
+
+// NewDefaultBatchQuoteService creates a BatchQuoteService that batches
+// requests to Yahoo Finance and falls back to Alpha Vantage when a client
+// is given.
+func NewDefaultBatchQuoteService(alphaVantage *AlphaVantageClient) *BatchQuoteService {
+	var fallback QuoteProvider
+	if alphaVantage != nil {
+		fallback = alphaVantage
+	}
+	return NewBatchQuoteService(NewYahooFinanceClient(), fallback)
+}
+
+// GetQuotes returns the quotes found, in request order, and the error for
+// each symbol that could not be quoted.
+func (s *BatchQuoteService) GetQuotes(ctx context.Context, symbols []string) ([]Quote, map[string]error) {
+	found := make(map[string]*Quote, len(symbols))
+	var batchErr error
+	yahooQuotes, err := s.batch.GetMultipleQuotes(ctx, symbols)
+	if err != nil {
+		batchErr = err
+		log.Warn().Err(err).Int("symbols", len(symbols)).Msg("Batch quote request failed, falling back")
+	}
+	for i := range yahooQuotes {
+		found[strings.ToUpper(yahooQuotes[i].Symbol)] = QuoteFromYahoo(&yahooQuotes[i])
+	}
+
+	quotes := make([]Quote, 0, len(symbols))
+	failed := make(map[string]error)
+	for _, symbol := range symbols {
+		if quote, ok := found[strings.ToUpper(symbol)]; ok {
+			quotes = append(quotes, *quote)
+			continue
+		}
+
+		err := batchErr
+		if err == nil {
+			err = fmt.Errorf("no quote data for symbol: %s", symbol)
+		}
+		if s.fallback != nil && ctx.Err() == nil {
+			quote, fallbackErr := s.fallback.GetQuote(ctx, symbol)
+			if fallbackErr == nil {
+				quotes = append(quotes, *quote)
+				continue
+			}
+			err = fmt.Errorf("%s: %w", s.fallback.Name(), fallbackErr)
+		}
+		failed[symbol] = err
+	}
+	return quotes, failed
+}
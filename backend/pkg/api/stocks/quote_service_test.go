@@ -0,0 +1,160 @@
+package stocks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeQuoteProvider is a QuoteProvider that returns a fixed result.
+type fakeQuoteProvider struct {
+	name  string
+	price float64
+	err   error
+	calls int
+}
+
+func (p *fakeQuoteProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeQuoteProvider) GetQuote(ctx context.Context, symbol string) (*Quote, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Quote{Symbol: symbol, Price: p.price}, nil
+}
+
+func TestQuoteService_FailsOverWhenRateLimited(t *testing.T) {
+	primary := &fakeQuoteProvider{name: "primary", err: ErrRateLimited}
+	secondary := &fakeQuoteProvider{name: "secondary", price: 101}
+
+	now := time.Date(2024, 12, 2, 15, 0, 0, 0, time.UTC)
+	service := NewQuoteService(primary, secondary)
+	service.now = func() time.Time { return now }
+
+	quote, err := service.GetQuote(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if quote.Price != 101 {
+		t.Errorf("Expected price from secondary provider, got %v", quote.Price)
+	}
+
+	// The rate-limited provider is skipped during its cooldown
+	service.GetQuote(context.Background(), "AAPL")
+	if primary.calls != 1 {
+		t.Errorf("Expected primary to be called once during cooldown, got %d", primary.calls)
+	}
+
+	now = now.Add(DefaultRateLimitCooldown + time.Second)
+	service.GetQuote(context.Background(), "AAPL")
+	if primary.calls != 2 {
+		t.Errorf("Expected primary to be retried after cooldown, got %d calls", primary.calls)
+	}
+}
+
+func TestQuoteService_FailsOverOnError(t *testing.T) {
+	primary := &fakeQuoteProvider{name: "primary", err: errors.New("connection refused")}
+	secondary := &fakeQuoteProvider{name: "secondary", price: 55}
+	service := NewQuoteService(primary, secondary)
+
+	quote, err := service.GetQuote(context.Background(), "MSFT")
+	if err != nil || quote.Price != 55 {
+		t.Fatalf("Expected quote from secondary provider, got %v, %v", quote, err)
+	}
+
+	// Plain errors do not start a cooldown
+	service.GetQuote(context.Background(), "MSFT")
+	if primary.calls != 2 {
+		t.Errorf("Expected primary to be tried again, got %d calls", primary.calls)
+	}
+}
+
+func TestQuoteService_AllProvidersFail(t *testing.T) {
+	service := NewQuoteService(
+		&fakeQuoteProvider{name: "a", err: ErrRateLimited},
+		&fakeQuoteProvider{name: "b", err: errors.New("timeout")},
+	)
+
+	_, err := service.GetQuote(context.Background(), "AAPL")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected error to wrap ErrRateLimited, got %v", err)
+	}
+
+	if _, err := NewQuoteService().GetQuote(context.Background(), "AAPL"); err != ErrNoQuoteProviders {
+		t.Errorf("Expected ErrNoQuoteProviders, got %v", err)
+	}
+}
+
+// fakeBatchClient returns quotes for the symbols it has prices for.
+type fakeBatchClient struct {
+	prices  map[string]float64
+	err     error
+	symbols []string
+}
+
+func (c *fakeBatchClient) GetMultipleQuotes(ctx context.Context, symbols []string) ([]YahooQuote, error) {
+	c.symbols = symbols
+	if c.err != nil {
+		return nil, c.err
+	}
+	var quotes []YahooQuote
+	for _, symbol := range symbols {
+		if price, ok := c.prices[symbol]; ok {
+			quotes = append(quotes, YahooQuote{Symbol: symbol, RegularMarketPrice: price})
+		}
+	}
+	return quotes, nil
+}
+
+func TestBatchQuoteService_PartialFailure(t *testing.T) {
+	batch := &fakeBatchClient{prices: map[string]float64{"AAPL": 190, "NVDA": 120}}
+	fallback := &fakeQuoteProvider{name: "alphavantage", err: errors.New("invalid symbol")}
+	service := NewBatchQuoteService(batch, fallback)
+
+	quotes, failed := service.GetQuotes(context.Background(), []string{"AAPL", "XXXX", "NVDA"})
+	if len(batch.symbols) != 3 {
+		t.Errorf("Expected one batch request for 3 symbols, got %v", batch.symbols)
+	}
+	if len(quotes) != 2 || quotes[0].Symbol != "AAPL" || quotes[1].Symbol != "NVDA" || quotes[0].Price != 190 {
+		t.Errorf("Expected AAPL and NVDA quotes in request order, got %+v", quotes)
+	}
+	if fallback.calls != 1 {
+		t.Errorf("Expected the fallback to be asked only for XXXX, got %d calls", fallback.calls)
+	}
+	if len(failed) != 1 || failed["XXXX"] == nil {
+		t.Errorf("Expected only XXXX to fail, got %v", failed)
+	}
+}
+
+func TestBatchQuoteService_FallsBackWhenBatchFails(t *testing.T) {
+	batch := &fakeBatchClient{err: errors.New("connection refused")}
+	fallback := &fakeQuoteProvider{name: "alphavantage", price: 42}
+	service := NewBatchQuoteService(batch, fallback)
+
+	quotes, failed := service.GetQuotes(context.Background(), []string{"AAPL", "MSFT"})
+	if len(quotes) != 2 || len(failed) != 0 || fallback.calls != 2 {
+		t.Fatalf("Expected both quotes from the fallback, got %+v, %v after %d calls", quotes, failed, fallback.calls)
+	}
+
+	service = NewBatchQuoteService(batch, nil)
+	quotes, failed = service.GetQuotes(context.Background(), []string{"AAPL"})
+	if len(quotes) != 0 || !errors.Is(failed["AAPL"], batch.err) {
+		t.Errorf("Expected the batch error without a fallback, got %+v, %v", quotes, failed)
+	}
+}
+
+func TestClientsImplementQuoteProvider(t *testing.T) {
+	providers := []QuoteProvider{
+		NewYahooQuoteProvider(NewYahooFinanceClient()),
+		NewAlphaVantageClient("demo"),
+	}
+	for i, want := range []string{"yahoo", "alphavantage"} {
+		if got := providers[i].Name(); got != want {
+			t.Errorf("provider %d Name() = %q, want %q", i, got, want)
+		}
+	}
+}
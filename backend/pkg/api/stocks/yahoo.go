@@ -3,52 +3,149 @@ package stocks
 import (
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"super-dashboard/backend/pkg/api"
 )
 
-// YahooFinanceClient implements Yahoo Finance API client.
+// YahooFinanceClient implements Yahoo Finance API client. The quote and
+// download endpoints require a session cookie and a matching crumb, which
+// the client fetches on first use and keeps until Yahoo rejects them.
 type YahooFinanceClient struct {
-	client *api.Client
+	client       *api.Client
+	cookieClient *http.Client
+	cookieURL    string
+
+	mu    sync.Mutex
+	crumb string
 }
 
 // NewYahooFinanceClient creates a new Yahoo Finance API client.
 func NewYahooFinanceClient() *YahooFinanceClient {
+	return newYahooFinanceClient("https://query1.finance.yahoo.com", "https://fc.yahoo.com")
+}
+
+// newYahooFinanceClient creates a Yahoo Finance client for baseURL that
+// gets its session cookie from cookieURL.
+func newYahooFinanceClient(baseURL, cookieURL string) *YahooFinanceClient {
+	jar, _ := cookiejar.New(nil)
 	config := api.ClientConfig{
-		BaseURL:      "https://query1.finance.yahoo.com",
+		BaseURL:      baseURL,
 		Timeout:      30 * time.Second,
 		RateLimitRPS: 10, // Conservative rate limit
+		CookieJar:    jar,
 	}
 
 	return &YahooFinanceClient{
-		client: api.NewClient(config),
+		client:       api.NewClient(config),
+		cookieClient: &http.Client{Timeout: config.Timeout, Jar: jar},
+		cookieURL:    cookieURL,
+	}
+}
+
+// fetchCrumb returns the crumb for the client's session, first getting a
+// session cookie and a crumb for it if the client has none.
+func (c *YahooFinanceClient) fetchCrumb(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.crumb != "" {
+		return c.crumb, nil
+	}
+
+	// The cookie page answers with an error status but still sets the cookie.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cookieURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("create cookie request: %w", err)
+	}
+	cookieResp, err := c.cookieClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("get cookie: %w", err)
+	}
+	cookieResp.Body.Close()
+
+	resp, err := c.client.Get(ctx, "/v1/test/getcrumb", nil)
+	if err != nil {
+		return "", fmt.Errorf("get crumb: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read crumb: %w", err)
+	}
+	crumb := strings.TrimSpace(string(body))
+	if crumb == "" {
+		return "", errors.New("get crumb: empty response")
+	}
+
+	c.crumb = crumb
+	return crumb, nil
+}
+
+// resetCrumb drops a crumb Yahoo rejected so the next request fetches a new one.
+func (c *YahooFinanceClient) resetCrumb(rejected string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.crumb == rejected {
+		c.crumb = ""
+	}
+}
+
+// getWithCrumb performs a GET request with the session crumb, refreshing the
+// crumb once if Yahoo rejects it with 401.
+func (c *YahooFinanceClient) getWithCrumb(ctx context.Context, endpoint string, params map[string]string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		crumb, err := c.fetchCrumb(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		withCrumb := make(map[string]string, len(params)+1)
+		for key, value := range params {
+			withCrumb[key] = value
+		}
+		// Parameters are sent as given, and crumbs may contain reserved characters.
+		withCrumb["crumb"] = url.QueryEscape(crumb)
+
+		resp, err := c.client.Get(ctx, endpoint, withCrumb)
+		var statusErr *api.StatusError
+		if attempt == 1 && errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized {
+			c.resetCrumb(crumb)
+			continue
+		}
+		return resp, err
 	}
 }
 
 // YahooQuote represents Yahoo Finance quote.
 type YahooQuote struct {
-	Symbol                 string    `json:"symbol"`
-	RegularMarketPrice     float64   `json:"regularMarketPrice"`
-	RegularMarketChange    float64   `json:"regularMarketChange"`
-	RegularMarketChangePercent float64 `json:"regularMarketChangePercent"`
-	RegularMarketVolume    int64     `json:"regularMarketVolume"`
-	RegularMarketOpen      float64   `json:"regularMarketOpen"`
-	RegularMarketDayHigh   float64   `json:"regularMarketDayHigh"`
-	RegularMarketDayLow    float64   `json:"regularMarketDayLow"`
-	RegularMarketPreviousClose float64 `json:"regularMarketPreviousClose"`
-	MarketCap              float64   `json:"marketCap"`
-	FiftyTwoWeekLow        float64   `json:"fiftyTwoWeekLow"`
-	FiftyTwoWeekHigh       float64   `json:"fiftyTwoWeekHigh"`
-	TrailingPE             float64   `json:"trailingPE"`
-	ForwardPE              float64   `json:"forwardPE"`
-	DividendYield          float64   `json:"dividendYield"`
-	EPS                    float64   `json:"epsTrailingTwelveMonths"`
-	Timestamp              time.Time `json:"timestamp"`
+	Symbol                     string    `json:"symbol"`
+	RegularMarketPrice         float64   `json:"regularMarketPrice"`
+	RegularMarketChange        float64   `json:"regularMarketChange"`
+	RegularMarketChangePercent float64   `json:"regularMarketChangePercent"`
+	RegularMarketVolume        int64     `json:"regularMarketVolume"`
+	RegularMarketOpen          float64   `json:"regularMarketOpen"`
+	RegularMarketDayHigh       float64   `json:"regularMarketDayHigh"`
+	RegularMarketDayLow        float64   `json:"regularMarketDayLow"`
+	RegularMarketPreviousClose float64   `json:"regularMarketPreviousClose"`
+	MarketCap                  float64   `json:"marketCap"`
+	FiftyTwoWeekLow            float64   `json:"fiftyTwoWeekLow"`
+	FiftyTwoWeekHigh           float64   `json:"fiftyTwoWeekHigh"`
+	TrailingPE                 float64   `json:"trailingPE"`
+	ForwardPE                  float64   `json:"forwardPE"`
+	DividendYield              float64   `json:"dividendYield"`
+	EPS                        float64   `json:"epsTrailingTwelveMonths"`
+	Timestamp                  time.Time `json:"timestamp"`
 }
 
 // YahooChart represents historical chart data.
@@ -73,7 +170,7 @@ func (c *YahooFinanceClient) GetQuote(ctx context.Context, symbol string) (*Yaho
 		"symbols": symbol,
 	}
 
-	resp, err := c.client.Get(ctx, "/v7/finance/quote", params)
+	resp, err := c.getWithCrumb(ctx, "/v7/finance/quote", params)
 	if err != nil {
 		return nil, fmt.Errorf("get quote: %w", err)
 	}
@@ -81,23 +178,23 @@ func (c *YahooFinanceClient) GetQuote(ctx context.Context, symbol string) (*Yaho
 	var result struct {
 		QuoteResponse struct {
 			Result []struct {
-				Symbol                      string  `json:"symbol"`
-				RegularMarketPrice          float64 `json:"regularMarketPrice"`
-				RegularMarketChange         float64 `json:"regularMarketChange"`
-				RegularMarketChangePercent  float64 `json:"regularMarketChangePercent"`
-				RegularMarketVolume         int64   `json:"regularMarketVolume"`
-				RegularMarketOpen           float64 `json:"regularMarketOpen"`
-				RegularMarketDayHigh        float64 `json:"regularMarketDayHigh"`
-				RegularMarketDayLow         float64 `json:"regularMarketDayLow"`
-				RegularMarketPreviousClose  float64 `json:"regularMarketPreviousClose"`
-				MarketCap                   float64 `json:"marketCap"`
-				FiftyTwoWeekLow             float64 `json:"fiftyTwoWeekLow"`
-				FiftyTwoWeekHigh            float64 `json:"fiftyTwoWeekHigh"`
-				TrailingPE                  float64 `json:"trailingPE"`
-				ForwardPE                   float64 `json:"forwardPE"`
-				DividendYield               float64 `json:"dividendYield"`
-				EpsTrailingTwelveMonths     float64 `json:"epsTrailingTwelveMonths"`
-				RegularMarketTime           int64   `json:"regularMarketTime"`
+				Symbol                     string  `json:"symbol"`
+				RegularMarketPrice         float64 `json:"regularMarketPrice"`
+				RegularMarketChange        float64 `json:"regularMarketChange"`
+				RegularMarketChangePercent float64 `json:"regularMarketChangePercent"`
+				RegularMarketVolume        int64   `json:"regularMarketVolume"`
+				RegularMarketOpen          float64 `json:"regularMarketOpen"`
+				RegularMarketDayHigh       float64 `json:"regularMarketDayHigh"`
+				RegularMarketDayLow        float64 `json:"regularMarketDayLow"`
+				RegularMarketPreviousClose float64 `json:"regularMarketPreviousClose"`
+				MarketCap                  float64 `json:"marketCap"`
+				FiftyTwoWeekLow            float64 `json:"fiftyTwoWeekLow"`
+				FiftyTwoWeekHigh           float64 `json:"fiftyTwoWeekHigh"`
+				TrailingPE                 float64 `json:"trailingPE"`
+				ForwardPE                  float64 `json:"forwardPE"`
+				DividendYield              float64 `json:"dividendYield"`
+				EpsTrailingTwelveMonths    float64 `json:"epsTrailingTwelveMonths"`
+				RegularMarketTime          int64   `json:"regularMarketTime"`
 			} `json:"result"`
 		} `json:"quoteResponse"`
 	}
@@ -138,7 +235,7 @@ func (c *YahooFinanceClient) GetQuote(ctx context.Context, symbol string) (*Yaho
 func (c *YahooFinanceClient) GetChart(ctx context.Context, symbol string, interval string, rangeStr string) (*YahooChart, error) {
 	// interval: 1m, 5m, 15m, 1h, 1d, 1wk, 1mo
 	// range: 1d, 5d, 1mo, 3mo, 6mo, 1y, 2y, 5y, 10y, ytd, max
-	
+
 	params := map[string]string{
 		"interval": interval,
 		"range":    rangeStr,
@@ -212,14 +309,14 @@ func (c *YahooFinanceClient) GetChart(ctx context.Context, symbol string, interv
 // GetHistoricalCSV retrieves historical data in CSV format (alternative method).
 func (c *YahooFinanceClient) GetHistoricalCSV(ctx context.Context, symbol string, startDate, endDate time.Time) ([]PricePoint, error) {
 	params := map[string]string{
-		"period1": fmt.Sprintf("%d", startDate.Unix()),
-		"period2": fmt.Sprintf("%d", endDate.Unix()),
+		"period1":  fmt.Sprintf("%d", startDate.Unix()),
+		"period2":  fmt.Sprintf("%d", endDate.Unix()),
 		"interval": "1d",
-		"events": "history",
+		"events":   "history",
 	}
 
 	endpoint := fmt.Sprintf("/v7/finance/download/%s", symbol)
-	resp, err := c.client.Get(ctx, endpoint, params)
+	resp, err := c.getWithCrumb(ctx, endpoint, params)
 	if err != nil {
 		return nil, fmt.Errorf("get historical CSV: %w", err)
 	}
@@ -227,7 +324,7 @@ func (c *YahooFinanceClient) GetHistoricalCSV(ctx context.Context, symbol string
 
 	// Parse CSV
 	reader := csv.NewReader(resp.Body)
-	
+
 	// Skip header
 	if _, err := reader.Read(); err != nil {
 		return nil, fmt.Errorf("read CSV header: %w", err)
@@ -244,7 +341,8 @@ func (c *YahooFinanceClient) GetHistoricalCSV(ctx context.Context, symbol string
 			return nil, fmt.Errorf("read CSV row: %w", err)
 		}
 
-		if len(record) < 6 {
+		// Date, Open, High, Low, Close, Adj Close, Volume
+		if len(record) < 7 {
 			continue
 		}
 
@@ -299,7 +397,7 @@ func (c *YahooFinanceClient) SearchSymbol(ctx context.Context, query string) ([]
 		if name == "" {
 			name = q.ShortName
 		}
-		
+
 		results[i] = SearchResult{
 			Symbol:   q.Symbol,
 			Name:     name,
@@ -322,12 +420,12 @@ type SearchResult struct {
 // GetMultipleQuotes retrieves quotes for multiple symbols.
 func (c *YahooFinanceClient) GetMultipleQuotes(ctx context.Context, symbols []string) ([]YahooQuote, error) {
 	symbolsStr := strings.Join(symbols, ",")
-	
+
 	params := map[string]string{
 		"symbols": symbolsStr,
 	}
 
-	resp, err := c.client.Get(ctx, "/v7/finance/quote", params)
+	resp, err := c.getWithCrumb(ctx, "/v7/finance/quote", params)
 	if err != nil {
 		return nil, fmt.Errorf("get multiple quotes: %w", err)
 	}
@@ -335,17 +433,17 @@ func (c *YahooFinanceClient) GetMultipleQuotes(ctx context.Context, symbols []st
 	var result struct {
 		QuoteResponse struct {
 			Result []struct {
-				Symbol                      string  `json:"symbol"`
-				RegularMarketPrice          float64 `json:"regularMarketPrice"`
-				RegularMarketChange         float64 `json:"regularMarketChange"`
-				RegularMarketChangePercent  float64 `json:"regularMarketChangePercent"`
-				RegularMarketVolume         int64   `json:"regularMarketVolume"`
-				RegularMarketOpen           float64 `json:"regularMarketOpen"`
-				RegularMarketDayHigh        float64 `json:"regularMarketDayHigh"`
-				RegularMarketDayLow         float64 `json:"regularMarketDayLow"`
-				RegularMarketPreviousClose  float64 `json:"regularMarketPreviousClose"`
-				MarketCap                   float64 `json:"marketCap"`
-				RegularMarketTime           int64   `json:"regularMarketTime"`
+				Symbol                     string  `json:"symbol"`
+				RegularMarketPrice         float64 `json:"regularMarketPrice"`
+				RegularMarketChange        float64 `json:"regularMarketChange"`
+				RegularMarketChangePercent float64 `json:"regularMarketChangePercent"`
+				RegularMarketVolume        int64   `json:"regularMarketVolume"`
+				RegularMarketOpen          float64 `json:"regularMarketOpen"`
+				RegularMarketDayHigh       float64 `json:"regularMarketDayHigh"`
+				RegularMarketDayLow        float64 `json:"regularMarketDayLow"`
+				RegularMarketPreviousClose float64 `json:"regularMarketPreviousClose"`
+				MarketCap                  float64 `json:"marketCap"`
+				RegularMarketTime          int64   `json:"regularMarketTime"`
 			} `json:"result"`
 		} `json:"quoteResponse"`
 	}
@@ -0,0 +1,84 @@
+package stocks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// yahooTestServer serves a session cookie, crumbs numbered from 1 and quotes
+// that require the current crumb and the cookie.
+type yahooTestServer struct {
+	*httptest.Server
+	crumbs        atomic.Int32
+	quoteRequests atomic.Int32
+	rejectFirst   bool
+}
+
+func newYahooTestServer(t *testing.T, rejectFirst bool) *yahooTestServer {
+	t.Helper()
+	s := &yahooTestServer{rejectFirst: rejectFirst}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cookie":
+			http.SetCookie(w, &http.Cookie{Name: "A3", Value: "session", Path: "/"})
+			w.WriteHeader(http.StatusNotFound)
+		case "/v1/test/getcrumb":
+			if _, err := r.Cookie("A3"); err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			n := s.crumbs.Add(1)
+			fmt.Fprintf(w, "crumb/%d", n)
+		case "/v7/finance/quote":
+			n := s.quoteRequests.Add(1)
+			want := fmt.Sprintf("crumb/%d", s.crumbs.Load())
+			if _, err := r.Cookie("A3"); err != nil || r.URL.Query().Get("crumb") != want || (s.rejectFirst && n == 1) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"quoteResponse": {"result": [{"symbol": "AAPL", "regularMarketPrice": 189.5}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestYahooFinanceClient_GetQuoteSendsCrumb(t *testing.T) {
+	server := newYahooTestServer(t, false)
+	client := newYahooFinanceClient(server.URL, server.URL+"/cookie")
+
+	for i := 0; i < 2; i++ {
+		quote, err := client.GetQuote(context.Background(), "AAPL")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if quote.RegularMarketPrice != 189.5 {
+			t.Errorf("Expected price 189.5, got %v", quote.RegularMarketPrice)
+		}
+	}
+	if got := server.crumbs.Load(); got != 1 {
+		t.Errorf("Expected the crumb to be fetched once, got %d", got)
+	}
+}
+
+func TestYahooFinanceClient_RefreshesCrumbOn401(t *testing.T) {
+	server := newYahooTestServer(t, true)
+	client := newYahooFinanceClient(server.URL, server.URL+"/cookie")
+
+	if _, err := client.GetQuote(context.Background(), "AAPL"); err != nil {
+		t.Fatalf("Expected the retry with a new crumb to succeed, got %v", err)
+	}
+	if got := server.crumbs.Load(); got != 2 {
+		t.Errorf("Expected a second crumb after the 401, got %d crumbs", got)
+	}
+	if got := server.quoteRequests.Load(); got != 2 {
+		t.Errorf("Expected 2 quote requests, got %d", got)
+	}
+}
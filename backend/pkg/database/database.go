@@ -1,8 +1,8 @@
 package database
 
 import (
-	"github.com/rs/zerolog/log"
 	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/rs/zerolog/log"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -25,6 +25,10 @@ func Connect(databaseURL string) (*gorm.DB, error) {
 func AutoMigrate(db *gorm.DB) error {
 	log.Info().Msg("Running database migrations...")
 
+	// Users created before email verification existed count as verified,
+	// so that REQUIRE_EMAIL_VERIFICATION does not lock them out
+	addsEmailVerified := !db.Migrator().HasColumn(&model.User{}, "EmailVerified")
+
 	err := db.AutoMigrate(
 		// Auth & Users
 		&model.User{},
@@ -36,19 +40,41 @@ func AutoMigrate(db *gorm.DB) error {
 		&model.Team{},
 		&model.Match{},
 		&model.Odds{},
+		&model.ValueBet{},
+		&model.Bet{},
+		&model.BankrollHistory{},
 		// Stocks
 		&model.Stock{},
 		&model.StockPrice{},
+		&model.FairValue{},
+		&model.StockNews{},
+		&model.Watchlist{},
+		&model.WatchlistItem{},
 		// Paper Trading
 		&model.Portfolio{},
 		&model.Position{},
 		&model.Order{},
 		&model.Trade{},
+		&model.BacktestResult{},
+		// Screener
+		&model.ScreenerPreset{},
+		// Favorites
+		&model.Favorite{},
+		// Alerts & Notifications
+		&model.Alert{},
+		&model.Notification{},
+		&model.NotificationDelivery{},
 	)
 	if err != nil {
 		return err
 	}
 
+	if addsEmailVerified {
+		if err := db.Model(&model.User{}).Where("1 = 1").Update("email_verified", true).Error; err != nil {
+			return err
+		}
+	}
+
 	log.Info().Msg("Database migrations completed")
 	return nil
 }
@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// FairValueRecalcJob creates a daily job that recomputes the fair value of
+// tracked stocks and notifies users whose margin-of-safety threshold was crossed.
+func FairValueRecalcJob(fairValueService service.FairValueService) *Job {
+	return &Job{
+		Name:     "FairValueRecalc",
+		CronExpr: "0 0 7 * * *", // Every day at 7:00 AM
+		Handler: func(ctx context.Context) error {
+			calculated, err := fairValueService.RecalculateAll(ctx)
+			log.Info().Int("calculated", calculated).Msg("FairValueRecalc: Fair values refreshed")
+			return err
+		},
+	}
+}
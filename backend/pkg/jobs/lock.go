@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// JobLockTTL is how long a job tick's lock is held. Locks are not released
+// after the run, so a replica whose clock lags cannot take the same tick
+// once the first replica finishes.
+const JobLockTTL = time.Minute
+
+// Locker grants locks shared by every scheduler replica, so that only one
+// replica runs each tick of a job.
+type Locker interface {
+	// TryLock takes the lock on key for ttl and reports whether it was
+	// free.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// NoopLocker grants every lock. It is the default, for a single worker
+// instance.
+type NoopLocker struct{}
+
+// TryLock always takes the lock.
+func (NoopLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return true, nil
+}
+
+// RedisLocker takes locks with SET NX PX, so replicas sharing a Redis
+// server share the locks.
+type RedisLocker struct {
+	rdb redis.Cmdable
+}
+
+// NewRedisLocker creates a Locker backed by rdb.
+func NewRedisLocker(rdb redis.Cmdable) *RedisLocker {
+	return &RedisLocker{rdb: rdb}
+}
+
+// TryLock sets key if it does not exist, expiring it after ttl.
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return l.rdb.SetNX(ctx, key, "locked", ttl).Result()
+}
+
+// TryLockInterval takes the lock for name's run in the interval-long window
+// containing now. It is for workers that tick on their own timers rather
+// than through a Scheduler: replicas whose timers are not aligned still
+// share one run per window.
+func TryLockInterval(ctx context.Context, locker Locker, name string, interval time.Duration, now time.Time) (bool, error) {
+	return locker.TryLock(ctx, jobLockKey(name, now.Truncate(interval)), interval)
+}
+
+// jobLockKey names the lock for one tick of a job.
+func jobLockKey(name string, tick time.Time) string {
+	return "job_lock:" + name + ":" + tick.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,140 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockLocker is an in-memory Locker shared by several schedulers.
+type mockLocker struct {
+	mu    sync.Mutex
+	held  map[string]bool
+	err   error
+	tries int
+}
+
+func (m *mockLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tries++
+	if m.err != nil {
+		return false, m.err
+	}
+	if m.held[key] {
+		return false, nil
+	}
+	m.held[key] = true
+	return true, nil
+}
+
+func TestScheduler_LockerRunsEachTickOnce(t *testing.T) {
+	locker := &mockLocker{held: make(map[string]bool)}
+	var calls int32
+	newReplica := func() (*Scheduler, *Job) {
+		scheduler := NewScheduler()
+		scheduler.SetLocker(locker)
+		job := &Job{
+			Name:     "OddsSync",
+			CronExpr: "0 */30 * * * *",
+			Handler: func(ctx context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			},
+		}
+		if err := scheduler.AddJob(job); err != nil {
+			t.Fatalf("AddJob failed: %v", err)
+		}
+		return scheduler, job
+	}
+	first, firstJob := newReplica()
+	second, secondJob := newReplica()
+
+	tick := time.Date(2026, 1, 2, 3, 30, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); first.runTick(firstJob, tick) }()
+	go func() { defer wg.Done(); second.runTick(secondJob, tick) }()
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("Expected one replica to run the tick, got %d runs", n)
+	}
+	runs := len(first.JobStatuses()[0].Runs) + len(second.JobStatuses()[0].Runs)
+	if runs != 1 {
+		t.Errorf("Expected one recorded run across replicas, got %d", runs)
+	}
+	if first.JobStatuses()[0].Running || second.JobStatuses()[0].Running {
+		t.Error("Expected neither replica to be left running")
+	}
+
+	// The next tick is a new lock
+	second.runTick(secondJob, tick.Add(30*time.Minute))
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("Expected the next tick to run, got %d runs", n)
+	}
+}
+
+func TestScheduler_LockerErrorSkipsRun(t *testing.T) {
+	scheduler := NewScheduler()
+	locker := &mockLocker{err: errors.New("connection refused")}
+	scheduler.SetLocker(locker)
+
+	var calls int32
+	job := &Job{
+		Name:     "OddsSync",
+		CronExpr: "0 */30 * * * *",
+		Handler: func(ctx context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		},
+	}
+	if err := scheduler.AddJob(job); err != nil {
+		t.Fatalf("AddJob failed: %v", err)
+	}
+
+	scheduler.createJobWrapper(job)()
+	if atomic.LoadInt32(&calls) != 0 || locker.tries != 1 {
+		t.Fatalf("Expected the run skipped after one lock attempt, got %d runs and %d attempts", calls, locker.tries)
+	}
+	if scheduler.JobStatuses()[0].Running {
+		t.Error("Expected the job not left running")
+	}
+
+	// Manual runs take no lock
+	if err := scheduler.RunJobNow("OddsSync"); err != nil {
+		t.Fatalf("RunJobNow failed: %v", err)
+	}
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline) && atomic.LoadInt32(&calls) == 0; {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Error("Expected the manual run to ignore the locker")
+	}
+}
+
+func TestTryLockInterval(t *testing.T) {
+	locker := &mockLocker{held: make(map[string]bool)}
+	start := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tries := []struct {
+		at   time.Time
+		want bool
+	}{
+		{start.Add(10 * time.Second), true},
+		{start.Add(50 * time.Second), false}, // another replica in the same window
+		{start.Add(70 * time.Second), true},
+	}
+	for _, try := range tries {
+		locked, err := TryLockInterval(context.Background(), locker, "stock_sync", time.Minute, try.at)
+		if err != nil {
+			t.Fatalf("TryLockInterval() error = %v", err)
+		}
+		if locked != try.want {
+			t.Errorf("TryLockInterval() at %v = %v, want %v", try.at, locked, try.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// OrderExpiryJob creates a job that cancels paper trading DAY orders once their session closes.
+func OrderExpiryJob(paperService service.PaperTradingService) *Job {
+	return &Job{
+		Name:     "OrderExpiry",
+		CronExpr: "0 */5 * * * *", // Every 5 minutes
+		Handler: func(ctx context.Context) error {
+			expired, err := paperService.ExpireOrders(time.Now())
+			if expired > 0 {
+				log.Info().Int("expired", expired).Msg("OrderExpiry: Cancelled expired DAY orders")
+			}
+			return err
+		},
+	}
+}
+
+// PendingOrderFillJob creates a job that fills resting paper trading limit
+// orders once the market reaches their limit.
+func PendingOrderFillJob(paperService service.PaperTradingService) *Job {
+	return &Job{
+		Name:     "PendingOrderFill",
+		CronExpr: "0 * * * * *", // Every minute
+		Handler: func(ctx context.Context) error {
+			portfolios, err := paperService.ListPortfolios()
+			if err != nil {
+				return err
+			}
+
+			filled := 0
+			for _, portfolio := range portfolios {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				n, err := paperService.ProcessPendingOrders(portfolio.ID)
+				filled += n
+				if err != nil {
+					log.Error().Err(err).Str("portfolio_id", portfolio.ID.String()).Msg("PendingOrderFill: Failed to process pending orders")
+				}
+			}
+			if filled > 0 {
+				log.Info().Int("filled", filled).Msg("PendingOrderFill: Filled pending limit orders")
+			}
+			return nil
+		},
+	}
+}
@@ -3,6 +3,7 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -10,19 +11,65 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// JobRunHistory is how many recent runs each job keeps.
+const JobRunHistory = 20
+
+// Manual run errors.
+var (
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobAlreadyRunning = errors.New("job is already running")
+)
+
 // Job represents a scheduled job with cron expression support.
 type Job struct {
 	Name     string
 	CronExpr string // Cron expression (e.g., "*/30 * * * *" for every 30 minutes)
 	Handler  func(ctx context.Context) error
 	running  bool
+	paused   bool
+	entryID  cron.EntryID
+	runs     []JobRun // newest last, at most JobRunHistory
 	mu       sync.Mutex
 }
 
+// JobRun is one execution of a job.
+type JobRun struct {
+	StartedAt  time.Time `json:"started_at"`
+	DurationMS float64   `json:"duration_ms"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// JobStatus is a job's schedule and its recent runs.
+type JobStatus struct {
+	Name     string `json:"name"`
+	CronExpr string `json:"cron_expr"`
+	Running  bool   `json:"running"`
+	// Paused jobs skip their scheduled runs until resumed.
+	Paused bool `json:"paused"`
+	// NextRun is when the job fires next; nil until the scheduler starts.
+	NextRun *time.Time `json:"next_run,omitempty"`
+	// LastRun is the most recent completed run, nil if the job has not run.
+	LastRun *JobRun `json:"last_run,omitempty"`
+	// Runs holds up to JobRunHistory completed runs, newest first.
+	Runs []JobRun `json:"runs"`
+}
+
+// recordRun adds a completed run, dropping the oldest beyond JobRunHistory.
+func (j *Job) recordRun(run JobRun) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.runs = append(j.runs, run)
+	if len(j.runs) > JobRunHistory {
+		j.runs = append(j.runs[:0], j.runs[len(j.runs)-JobRunHistory:]...)
+	}
+}
+
 // Scheduler manages background jobs using robfig/cron.
 type Scheduler struct {
 	cron    *cron.Cron
 	jobs    []*Job
+	locker  Locker
 	ctx     context.Context
 	cancel  context.CancelFunc
 	running bool
@@ -35,11 +82,20 @@ func NewScheduler() *Scheduler {
 	return &Scheduler{
 		cron:   cron.New(cron.WithSeconds()),
 		jobs:   make([]*Job, 0),
+		locker: NoopLocker{},
 		ctx:    ctx,
 		cancel: cancel,
 	}
 }
 
+// SetLocker sets the lock each scheduled run must take, so that only one of
+// several replicas runs each tick of a job. Manual runs take no lock.
+func (s *Scheduler) SetLocker(locker Locker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locker = locker
+}
+
 // AddJob adds a job to the scheduler with a cron expression.
 func (s *Scheduler) AddJob(job *Job) error {
 	s.mu.Lock()
@@ -48,48 +104,166 @@ func (s *Scheduler) AddJob(job *Job) error {
 	// Create a wrapper function that handles context and concurrency
 	wrappedHandler := s.createJobWrapper(job)
 
-	_, err := s.cron.AddFunc(job.CronExpr, wrappedHandler)
+	entryID, err := s.cron.AddFunc(job.CronExpr, wrappedHandler)
 	if err != nil {
 		return err
 	}
 
+	job.entryID = entryID
 	s.jobs = append(s.jobs, job)
 	return nil
 }
 
 // createJobWrapper creates a wrapper function for the job that handles
-// context cancellation and prevents concurrent execution.
+// context cancellation, prevents concurrent execution and records each run.
+// Runs skipped because the job is paused, still running or locked by
+// another replica are not recorded.
 func (s *Scheduler) createJobWrapper(job *Job) func() {
 	return func() {
-		job.mu.Lock()
-		if job.running {
-			job.mu.Unlock()
-			log.Warn().Str("job", job.Name).Msg("Job already running, skipping")
-			return
-		}
-		job.running = true
-		job.mu.Unlock()
+		// Cron fires on whole seconds, so rounding names the same tick on
+		// every replica despite small clock and timer differences.
+		s.runTick(job, time.Now().Round(time.Second))
+	}
+}
 
-		defer func() {
-			job.mu.Lock()
-			job.running = false
-			job.mu.Unlock()
-		}()
-
-		// Check if scheduler context is cancelled
-		select {
-		case <-s.ctx.Done():
-			return
-		default:
-		}
+// runTick runs the job for the scheduled tick unless it is paused, already
+// running here or the tick's lock is held elsewhere. The job is skipped if
+// the lock cannot be checked, since running it might duplicate its work.
+func (s *Scheduler) runTick(job *Job, tick time.Time) {
+	if job.isPaused() {
+		log.Debug().Str("job", job.Name).Msg("Job paused, skipping")
+		return
+	}
+	if !job.tryStart() {
+		log.Warn().Str("job", job.Name).Msg("Job already running, skipping")
+		return
+	}
 
-		start := time.Now()
-		if err := job.Handler(s.ctx); err != nil {
-			log.Error().Err(err).Str("job", job.Name).Msg("Job failed")
+	s.mu.Lock()
+	locker := s.locker
+	s.mu.Unlock()
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	locked, err := locker.TryLock(ctx, jobLockKey(job.Name, tick), JobLockTTL)
+	cancel()
+	if err != nil || !locked {
+		job.finish()
+		if err != nil {
+			log.Error().Err(err).Str("job", job.Name).Msg("Failed to take job lock, skipping")
 		} else {
-			log.Debug().Str("job", job.Name).Dur("duration", time.Since(start)).Msg("Job completed")
+			log.Debug().Str("job", job.Name).Msg("Job tick locked by another instance, skipping")
 		}
+		return
 	}
+	s.runJob(job)
+}
+
+// isPaused reports whether the job's scheduled runs are paused.
+func (j *Job) isPaused() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.paused
+}
+
+// tryStart marks the job running, reporting false if it already was.
+func (j *Job) tryStart() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.running {
+		return false
+	}
+	j.running = true
+	return true
+}
+
+// finish marks the job idle again.
+func (j *Job) finish() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.running = false
+}
+
+// runJob runs a job claimed with tryStart, records the run and marks the
+// job idle again.
+func (s *Scheduler) runJob(job *Job) {
+	defer job.finish()
+
+	// Check if scheduler context is cancelled
+	select {
+	case <-s.ctx.Done():
+		return
+	default:
+	}
+
+	start := time.Now()
+	err := job.Handler(s.ctx)
+	duration := time.Since(start)
+	run := JobRun{
+		StartedAt:  start,
+		DurationMS: float64(duration.Microseconds()) / 1000,
+		Success:    err == nil,
+	}
+	if err != nil {
+		run.Error = err.Error()
+		log.Error().Err(err).Str("job", job.Name).Msg("Job failed")
+	} else {
+		log.Debug().Str("job", job.Name).Dur("duration", duration).Msg("Job completed")
+	}
+	job.recordRun(run)
+}
+
+// RunJobNow starts the named job in a goroutine without changing its cron
+// schedule. It returns ErrJobNotFound for an unknown name and
+// ErrJobAlreadyRunning while a scheduled or manual run is in progress.
+func (s *Scheduler) RunJobNow(name string) error {
+	job := s.findJob(name)
+	if job == nil {
+		return ErrJobNotFound
+	}
+	if !job.tryStart() {
+		return ErrJobAlreadyRunning
+	}
+
+	log.Info().Str("job", name).Msg("Running job on demand")
+	go s.runJob(job)
+	return nil
+}
+
+// PauseJob stops the named job's scheduled runs until ResumeJob. A run in
+// progress finishes, and the job can still be run with RunJobNow.
+func (s *Scheduler) PauseJob(name string) error {
+	return s.setPaused(name, true)
+}
+
+// ResumeJob restarts the named job's scheduled runs from its next cron time.
+func (s *Scheduler) ResumeJob(name string) error {
+	return s.setPaused(name, false)
+}
+
+// setPaused sets the named job's paused flag, returning ErrJobNotFound for
+// an unknown name.
+func (s *Scheduler) setPaused(name string, paused bool) error {
+	job := s.findJob(name)
+	if job == nil {
+		return ErrJobNotFound
+	}
+
+	job.mu.Lock()
+	job.paused = paused
+	job.mu.Unlock()
+	log.Info().Str("job", name).Bool("paused", paused).Msg("Job pause state changed")
+	return nil
+}
+
+// findJob returns the registered job with the name, or nil.
+func (s *Scheduler) findJob(name string) *Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return job
+		}
+	}
+	return nil
 }
 
 // Start starts the scheduler.
@@ -146,6 +320,35 @@ func (s *Scheduler) GetJobs() []*Job {
 	return jobs
 }
 
+// JobStatuses returns the schedule and recent runs of every registered job,
+// in registration order.
+func (s *Scheduler) JobStatuses() []JobStatus {
+	jobs := s.GetJobs()
+	statuses := make([]JobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		status := JobStatus{Name: job.Name, CronExpr: job.CronExpr}
+		if next := s.cron.Entry(job.entryID).Next; !next.IsZero() {
+			status.NextRun = &next
+		}
+
+		job.mu.Lock()
+		status.Running = job.running
+		status.Paused = job.paused
+		status.Runs = make([]JobRun, len(job.runs))
+		for i, run := range job.runs {
+			status.Runs[len(job.runs)-1-i] = run
+		}
+		job.mu.Unlock()
+
+		if len(status.Runs) > 0 {
+			last := status.Runs[0]
+			status.LastRun = &last
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
 // CreateDefaultJobs creates the default set of background jobs.
 // These are stubs that log their execution - implement actual logic as needed.
 // Uses standard cron expressions with seconds field: sec min hour day month weekday
@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -321,3 +322,268 @@ func TestJob_Subscribe(t *testing.T) {
 		t.Errorf("Expected %d jobs, got %d", expectedCount, scheduler.JobCount())
 	}
 }
+
+func TestScheduler_JobStatuses(t *testing.T) {
+	scheduler := NewScheduler()
+
+	ran := make(chan struct{}, 1)
+	job := &Job{
+		Name:     "StatusTestJob",
+		CronExpr: "* * * * * *", // Every second
+		Handler: func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			select {
+			case ran <- struct{}{}:
+			default:
+			}
+			return nil
+		},
+	}
+	if err := scheduler.AddJob(job); err != nil {
+		t.Fatalf("AddJob failed: %v", err)
+	}
+
+	statuses := scheduler.JobStatuses()
+	if len(statuses) != 1 || statuses[0].Name != "StatusTestJob" || statuses[0].CronExpr != "* * * * * *" {
+		t.Fatalf("Expected the registered job, got %+v", statuses)
+	}
+	if statuses[0].NextRun != nil || statuses[0].LastRun != nil || len(statuses[0].Runs) != 0 {
+		t.Errorf("Expected no schedule or runs before start, got %+v", statuses[0])
+	}
+
+	before := time.Now()
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	select {
+	case <-ran:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected the job to run")
+	}
+	// The run is recorded once the handler returns
+	var status JobStatus
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if status = scheduler.JobStatuses()[0]; status.LastRun != nil {
+			break
+		}
+	}
+
+	last := status.LastRun
+	if last == nil {
+		t.Fatal("Expected a recorded run")
+	}
+	if !last.Success || last.Error != "" {
+		t.Errorf("Expected a successful run, got %+v", last)
+	}
+	if last.StartedAt.Before(before) || last.DurationMS < 10 {
+		t.Errorf("Expected a run started after %v lasting at least 10ms, got %+v", before, last)
+	}
+	if len(status.Runs) == 0 || status.Runs[0] != *last {
+		t.Errorf("Expected the last run to head the history, got %+v", status.Runs)
+	}
+	if status.NextRun == nil || !status.NextRun.After(last.StartedAt) {
+		t.Errorf("Expected a next run after the last one, got %v", status.NextRun)
+	}
+}
+
+func TestScheduler_JobStatuses_FailuresAndHistory(t *testing.T) {
+	scheduler := NewScheduler()
+
+	var calls int32
+	job := &Job{
+		Name:     "FailingJob",
+		CronExpr: "0 0 0 1 1 *",
+		Handler: func(ctx context.Context) error {
+			if atomic.AddInt32(&calls, 1)%2 == 0 {
+				return errors.New("upstream unavailable")
+			}
+			return nil
+		},
+	}
+	if err := scheduler.AddJob(job); err != nil {
+		t.Fatalf("AddJob failed: %v", err)
+	}
+
+	run := scheduler.createJobWrapper(job)
+	for i := 0; i < JobRunHistory+5; i++ {
+		run()
+	}
+
+	status := scheduler.JobStatuses()[0]
+	if len(status.Runs) != JobRunHistory {
+		t.Fatalf("Expected the history bounded to %d runs, got %d", JobRunHistory, len(status.Runs))
+	}
+	// The 25th call succeeded and the 24th failed
+	if !status.LastRun.Success {
+		t.Errorf("Expected the last run to succeed, got %+v", status.LastRun)
+	}
+	if failed := status.Runs[1]; failed.Success || failed.Error != "upstream unavailable" {
+		t.Errorf("Expected the previous run to fail, got %+v", failed)
+	}
+	for i := 1; i < len(status.Runs); i++ {
+		if status.Runs[i].StartedAt.After(status.Runs[i-1].StartedAt) {
+			t.Fatalf("Expected runs newest first, got %+v", status.Runs)
+		}
+	}
+}
+
+func TestScheduler_RunJobNow(t *testing.T) {
+	scheduler := NewScheduler()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	job := &Job{
+		Name:     "ManualJob",
+		CronExpr: "0 0 0 1 1 *", // Once a year, so only manual runs fire
+		Handler: func(ctx context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		},
+	}
+	if err := scheduler.AddJob(job); err != nil {
+		t.Fatalf("AddJob failed: %v", err)
+	}
+	scheduler.Start()
+	defer scheduler.Stop()
+	nextBefore := scheduler.JobStatuses()[0].NextRun
+
+	if err := scheduler.RunJobNow("MissingJob"); err != ErrJobNotFound {
+		t.Errorf("Expected ErrJobNotFound, got %v", err)
+	}
+
+	if err := scheduler.RunJobNow("ManualJob"); err != nil {
+		t.Fatalf("RunJobNow failed: %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the job to run")
+	}
+
+	if err := scheduler.RunJobNow("ManualJob"); err != ErrJobAlreadyRunning {
+		t.Errorf("Expected ErrJobAlreadyRunning while the job runs, got %v", err)
+	}
+	// A scheduled firing is skipped too
+	scheduler.createJobWrapper(job)()
+	close(release)
+
+	var status JobStatus
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if status = scheduler.JobStatuses()[0]; !status.Running {
+			break
+		}
+	}
+	if status.Running || len(status.Runs) != 1 || !status.Runs[0].Success {
+		t.Fatalf("Expected one successful manual run, got %+v", status)
+	}
+	if status.NextRun == nil || nextBefore == nil || !status.NextRun.Equal(*nextBefore) {
+		t.Errorf("Expected the schedule unchanged at %v, got %v", nextBefore, status.NextRun)
+	}
+
+	if err := scheduler.RunJobNow("ManualJob"); err != nil {
+		t.Errorf("Expected the job to run again once idle, got %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the job to run again")
+	}
+}
+
+func TestScheduler_PauseResumeJob(t *testing.T) {
+	scheduler := NewScheduler()
+
+	var calls int32
+	job := &Job{
+		Name:     "PausableJob",
+		CronExpr: "* * * * * *", // Every second
+		Handler: func(ctx context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		},
+	}
+	if err := scheduler.AddJob(job); err != nil {
+		t.Fatalf("AddJob failed: %v", err)
+	}
+
+	if err := scheduler.PauseJob("MissingJob"); err != ErrJobNotFound {
+		t.Errorf("Expected ErrJobNotFound pausing an unknown job, got %v", err)
+	}
+	if err := scheduler.ResumeJob("MissingJob"); err != ErrJobNotFound {
+		t.Errorf("Expected ErrJobNotFound resuming an unknown job, got %v", err)
+	}
+
+	if err := scheduler.PauseJob("PausableJob"); err != nil {
+		t.Fatalf("PauseJob failed: %v", err)
+	}
+	if status := scheduler.JobStatuses(); len(status) != 1 || !status[0].Paused {
+		t.Fatalf("Expected the job listed as paused, got %+v", status)
+	}
+
+	scheduler.Start()
+	defer scheduler.Stop()
+	time.Sleep(1500 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Fatalf("Expected no runs while paused, got %d", n)
+	}
+
+	if err := scheduler.ResumeJob("PausableJob"); err != nil {
+		t.Fatalf("ResumeJob failed: %v", err)
+	}
+	if scheduler.JobStatuses()[0].Paused {
+		t.Error("Expected the job no longer paused")
+	}
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline) && atomic.LoadInt32(&calls) == 0; {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if atomic.LoadInt32(&calls) == 0 {
+		t.Error("Expected the job to run after resuming")
+	}
+}
+
+func TestScheduler_PauseJobMidRun(t *testing.T) {
+	scheduler := NewScheduler()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	job := &Job{
+		Name:     "LongJob",
+		CronExpr: "0 0 0 1 1 *",
+		Handler: func(ctx context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		},
+	}
+	if err := scheduler.AddJob(job); err != nil {
+		t.Fatalf("AddJob failed: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		scheduler.createJobWrapper(job)()
+		close(done)
+	}()
+	<-started
+	if err := scheduler.PauseJob("LongJob"); err != nil {
+		t.Fatalf("PauseJob failed: %v", err)
+	}
+	close(release)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the current run to finish")
+	}
+	status := scheduler.JobStatuses()[0]
+	if !status.Paused || len(status.Runs) != 1 || !status.Runs[0].Success {
+		t.Fatalf("Expected the run recorded and the job paused, got %+v", status)
+	}
+
+	// Later scheduled firings are skipped
+	scheduler.createJobWrapper(job)()
+	if runs := len(scheduler.JobStatuses()[0].Runs); runs != 1 {
+		t.Errorf("Expected no run while paused, got %d runs", runs)
+	}
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// ScreenerPresetDiffJob creates a daily job that re-runs every saved screener
+// preset and notifies owners when new symbols enter the result set.
+func ScreenerPresetDiffJob(presetService service.ScreenerPresetService) *Job {
+	return &Job{
+		Name:     "ScreenerPresetDiff",
+		CronExpr: "0 30 6 * * *", // Every day at 6:30 AM
+		Handler: func(ctx context.Context) error {
+			results, err := presetService.DiffAll(ctx)
+
+			added := 0
+			for _, r := range results {
+				added += len(r.Added)
+			}
+			log.Info().
+				Int("presets", len(results)).
+				Int("new_symbols", added).
+				Msg("ScreenerPresetDiff: Saved screens refreshed")
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,92 @@
+// Package metrics provides counters that services increment and the metrics
+// endpoint exposes.
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CounterVec is a counter with one series per combination of label values.
+// It is safe for concurrent use. A CounterVec without labels has a single
+// series.
+type CounterVec struct {
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*Series
+}
+
+// Series is one series of a CounterVec.
+type Series struct {
+	// LabelValues holds the value of each label, in the CounterVec's order.
+	LabelValues []string
+	Value       uint64
+}
+
+// NewCounterVec creates a counter labelled with the given label names.
+func NewCounterVec(labels ...string) *CounterVec {
+	return &CounterVec{labels: labels, series: make(map[string]*Series)}
+}
+
+// Labels returns the counter's label names.
+func (v *CounterVec) Labels() []string {
+	return v.labels
+}
+
+// Inc adds one to the series with the given label values, one per label.
+// Missing values are empty and extra values are ignored.
+func (v *CounterVec) Inc(labelValues ...string) {
+	v.Add(1, labelValues...)
+}
+
+// Add adds n to the series with the given label values.
+func (v *CounterVec) Add(n uint64, labelValues ...string) {
+	values := make([]string, len(v.labels))
+	copy(values, labelValues)
+	key := strings.Join(values, "\x00")
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	s, ok := v.series[key]
+	if !ok {
+		s = &Series{LabelValues: values}
+		v.series[key] = s
+	}
+	s.Value += n
+}
+
+// Value returns the count of the series with the given label values.
+func (v *CounterVec) Value(labelValues ...string) uint64 {
+	values := make([]string, len(v.labels))
+	copy(values, labelValues)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if s, ok := v.series[strings.Join(values, "\x00")]; ok {
+		return s.Value
+	}
+	return 0
+}
+
+// Series returns a copy of every series, sorted by label values.
+func (v *CounterVec) Series() []Series {
+	v.mu.Lock()
+	series := make([]Series, 0, len(v.series))
+	for _, s := range v.series {
+		series = append(series, Series{LabelValues: s.LabelValues, Value: s.Value})
+	}
+	v.mu.Unlock()
+
+	sort.Slice(series, func(i, j int) bool {
+		a, b := series[i].LabelValues, series[j].LabelValues
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	return series
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCounterVec(t *testing.T) {
+	v := NewCounterVec("side", "status")
+	v.Inc("sell", "filled")
+	v.Inc("buy", "pending")
+	v.Add(2, "buy", "pending")
+	v.Inc("buy")
+
+	want := []Series{
+		{LabelValues: []string{"buy", ""}, Value: 1},
+		{LabelValues: []string{"buy", "pending"}, Value: 3},
+		{LabelValues: []string{"sell", "filled"}, Value: 1},
+	}
+	if got := v.Series(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Series() = %v, want %v", got, want)
+	}
+	if got := v.Value("buy", "pending"); got != 3 {
+		t.Errorf("Value(buy, pending) = %d, want 3", got)
+	}
+	if got := v.Value("sell", "cancelled"); got != 0 {
+		t.Errorf("Value(sell, cancelled) = %d, want 0", got)
+	}
+}
+
+func TestCounterVec_Unlabelled(t *testing.T) {
+	v := NewCounterVec()
+	v.Inc()
+	v.Inc("ignored")
+
+	series := v.Series()
+	if len(series) != 1 || series[0].Value != 2 || len(series[0].LabelValues) != 0 {
+		t.Errorf("Series() = %v, want a single series of 2", series)
+	}
+}
+
+func TestCounterVec_Concurrent(t *testing.T) {
+	v := NewCounterVec("channel")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				v.Inc("email")
+				_ = v.Series()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := v.Value("email"); got != 8000 {
+		t.Errorf("Value(email) = %d, want 8000", got)
+	}
+}
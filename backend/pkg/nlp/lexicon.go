@@ -0,0 +1,78 @@
+package nlp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// defaultLexiconWeight is the weight of each word in the built-in lexicon.
+const defaultLexiconWeight = 0.15
+
+// Lexicon maps sentiment words to weights. Positive weights push the score
+// towards positive, negative weights towards negative.
+type Lexicon map[string]float64
+
+// DefaultLexicon returns the built-in general-purpose lexicon.
+func DefaultLexicon() Lexicon {
+	positiveWords := []string{"good", "great", "excellent", "positive", "growth", "profit", "success", "win", "beat", "up", "rise", "gain"}
+	negativeWords := []string{"bad", "poor", "negative", "loss", "fail", "down", "fall", "decline", "drop", "miss", "cut", "layoff"}
+
+	lexicon := make(Lexicon, len(positiveWords)+len(negativeWords))
+	for _, word := range positiveWords {
+		lexicon[word] = defaultLexiconWeight
+	}
+	for _, word := range negativeWords {
+		lexicon[word] = -defaultLexiconWeight
+	}
+	return lexicon
+}
+
+// LoadLexicon reads a lexicon from a JSON file of word to weight, e.g.
+// {"beat": 0.2, "downgrade": -0.3}. Words are matched case-insensitively.
+func LoadLexicon(path string) (Lexicon, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lexicon: %w", err)
+	}
+
+	var raw map[string]float64
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse lexicon %s: %w", path, err)
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("lexicon is empty")
+	}
+
+	lexicon := make(Lexicon, len(raw))
+	for word, weight := range raw {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" {
+			return nil, errors.New("lexicon contains an empty word")
+		}
+		lexicon[word] = weight
+	}
+	return lexicon, nil
+}
+
+// lexiconEntry is a single word and its weight.
+type lexiconEntry struct {
+	word   string
+	weight float64
+}
+
+// entries returns the lexicon sorted by word so scores are summed in a
+// stable order.
+func (l Lexicon) entries() []lexiconEntry {
+	entries := make([]lexiconEntry, 0, len(l))
+	for word, weight := range l {
+		entries = append(entries, lexiconEntry{word: strings.ToLower(word), weight: weight})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].word < entries[j].word
+	})
+	return entries
+}
@@ -0,0 +1,91 @@
+package nlp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMockProvider_CustomLexicon(t *testing.T) {
+	provider := NewMockProviderWithLexicon(Lexicon{
+		"touchdown": 0.5,
+		"injury":    -0.4,
+		"Fumble":    -0.3,
+	})
+
+	tests := []struct {
+		name      string
+		text      string
+		wantScore float64
+		wantLabel string
+	}{
+		{"positive domain word", "Late touchdown seals the game", 0.5, "positive"},
+		{"negative domain word", "Star quarterback out with injury", -0.4, "negative"},
+		{"case-insensitive words", "A costly FUMBLE in the red zone", -0.3, "negative"},
+		{"mixed words", "Touchdown after an early injury", 0.1, "neutral"},
+		{"built-in words are not used", "Great profit growth", 0, "neutral"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, label, err := provider.AnalyzeSentiment(context.Background(), tt.text)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if diff := score - tt.wantScore; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected score %v, got %v", tt.wantScore, score)
+			}
+			if label != tt.wantLabel {
+				t.Errorf("Expected label %q, got %q", tt.wantLabel, label)
+			}
+		})
+	}
+}
+
+func TestMockProvider_DefaultLexicon(t *testing.T) {
+	for _, provider := range []*MockProvider{NewMockProvider(), NewMockProviderWithLexicon(nil)} {
+		_, label, _ := provider.AnalyzeSentiment(context.Background(), "Great profit growth")
+		if label != "positive" {
+			t.Errorf("Expected positive label from built-in lexicon, got %q", label)
+		}
+	}
+}
+
+func TestLoadLexicon(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write lexicon: %v", err)
+		}
+		return path
+	}
+
+	t.Run("valid lexicon", func(t *testing.T) {
+		lexicon, err := LoadLexicon(write("valid.json", `{"Upgrade": 0.3, "downgrade": -0.3}`))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if lexicon["upgrade"] != 0.3 || lexicon["downgrade"] != -0.3 {
+			t.Errorf("Unexpected lexicon %v", lexicon)
+		}
+	})
+
+	errorCases := []struct {
+		name string
+		path string
+	}{
+		{"missing file", filepath.Join(dir, "missing.json")},
+		{"invalid JSON", write("invalid.json", `{"upgrade": "high"}`)},
+		{"empty lexicon", write("empty.json", `{}`)},
+		{"empty word", write("blank.json", `{" ": 0.2}`)},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadLexicon(tt.path); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
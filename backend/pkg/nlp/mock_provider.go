@@ -15,12 +15,23 @@ const (
 // MockProvider is a mock implementation of NLPProvider for testing and development.
 type MockProvider struct {
 	dimension int
+	lexicon   []lexiconEntry
 }
 
-// NewMockProvider creates a new MockProvider.
+// NewMockProvider creates a new MockProvider using the built-in lexicon.
 func NewMockProvider() *MockProvider {
+	return NewMockProviderWithLexicon(nil)
+}
+
+// NewMockProviderWithLexicon creates a new MockProvider that scores sentiment
+// with the given lexicon. A nil or empty lexicon uses DefaultLexicon.
+func NewMockProviderWithLexicon(lexicon Lexicon) *MockProvider {
+	if len(lexicon) == 0 {
+		lexicon = DefaultLexicon()
+	}
 	return &MockProvider{
 		dimension: MockEmbeddingDimension,
+		lexicon:   lexicon.entries(),
 	}
 }
 
@@ -45,24 +56,14 @@ func (p *MockProvider) GetDimension() int {
 }
 
 // AnalyzeSentiment returns a mock sentiment analysis.
-// It uses simple keyword-based heuristics for consistent testing.
+// It sums the weights of the lexicon words found in the text.
 func (p *MockProvider) AnalyzeSentiment(ctx context.Context, text string) (float64, string, error) {
 	text = strings.ToLower(text)
 
-	positiveWords := []string{"good", "great", "excellent", "positive", "growth", "profit", "success", "win", "beat", "up", "rise", "gain"}
-	negativeWords := []string{"bad", "poor", "negative", "loss", "fail", "down", "fall", "decline", "drop", "miss", "cut", "layoff"}
-
 	var score float64
-
-	for _, word := range positiveWords {
-		if strings.Contains(text, word) {
-			score += 0.15
-		}
-	}
-
-	for _, word := range negativeWords {
-		if strings.Contains(text, word) {
-			score -= 0.15
+	for _, entry := range p.lexicon {
+		if strings.Contains(text, entry.word) {
+			score += entry.weight
 		}
 	}
 
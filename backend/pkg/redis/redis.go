@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -57,6 +58,142 @@ func (c *Client) DeleteRefreshToken(ctx context.Context, tokenID string) error {
 	return c.rdb.Del(ctx, key).Err()
 }
 
+// SetEmailVerificationToken stores an email verification token with expiration.
+func (c *Client) SetEmailVerificationToken(ctx context.Context, userID, token string, expiration time.Duration) error {
+	key := "email_verification:" + token
+	return c.rdb.Set(ctx, key, userID, expiration).Err()
+}
+
+// GetEmailVerificationToken retrieves the user ID associated with an email
+// verification token.
+func (c *Client) GetEmailVerificationToken(ctx context.Context, token string) (string, error) {
+	key := "email_verification:" + token
+	return c.rdb.Get(ctx, key).Result()
+}
+
+// DeleteEmailVerificationToken deletes an email verification token.
+func (c *Client) DeleteEmailVerificationToken(ctx context.Context, token string) error {
+	key := "email_verification:" + token
+	return c.rdb.Del(ctx, key).Err()
+}
+
+// SetPasswordResetToken stores a password reset token with expiration.
+func (c *Client) SetPasswordResetToken(ctx context.Context, userID, token string, expiration time.Duration) error {
+	key := "password_reset:" + token
+	return c.rdb.Set(ctx, key, userID, expiration).Err()
+}
+
+// ConsumePasswordResetToken retrieves the user ID associated with a
+// password reset token and deletes the token with GETDEL, so that two
+// concurrent resets cannot both redeem it.
+func (c *Client) ConsumePasswordResetToken(ctx context.Context, token string) (string, error) {
+	key := "password_reset:" + token
+	return c.rdb.GetDel(ctx, key).Result()
+}
+
+// SetOAuthState stores the provider an OAuth login state was issued for.
+func (c *Client) SetOAuthState(ctx context.Context, state, provider string, expiration time.Duration) error {
+	key := "oauth_state:" + state
+	return c.rdb.Set(ctx, key, provider, expiration).Err()
+}
+
+// GetOAuthState retrieves the provider an OAuth login state was issued for.
+func (c *Client) GetOAuthState(ctx context.Context, state string) (string, error) {
+	key := "oauth_state:" + state
+	return c.rdb.Get(ctx, key).Result()
+}
+
+// DeleteOAuthState deletes an OAuth login state.
+func (c *Client) DeleteOAuthState(ctx context.Context, state string) error {
+	key := "oauth_state:" + state
+	return c.rdb.Del(ctx, key).Err()
+}
+
+// IncrementFailedLogins increments the failed login counter for key and
+// pushes its expiry out to window from now.
+func (c *Client) IncrementFailedLogins(ctx context.Context, key string, window time.Duration) (int64, error) {
+	redisKey := "failed_logins:" + key
+	pipe := c.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, redisKey)
+	pipe.Expire(ctx, redisKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// GetFailedLogins returns the failed login counter for key, or 0 if unset.
+func (c *Client) GetFailedLogins(ctx context.Context, key string) (int64, error) {
+	count, err := c.rdb.Get(ctx, "failed_logins:"+key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+// ResetFailedLogins clears the failed login counter for key.
+func (c *Client) ResetFailedLogins(ctx context.Context, key string) error {
+	return c.rdb.Del(ctx, "failed_logins:"+key).Err()
+}
+
+// idempotencyRecord is what an Idempotency-Key maps to.
+type idempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	ResourceID  string `json:"resource_id,omitempty"`
+}
+
+// ReserveIdempotencyKey claims key for a request with requestHash, unless
+// the key is already claimed.
+func (c *Client) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, expiration time.Duration) (bool, error) {
+	data, err := json.Marshal(idempotencyRecord{RequestHash: requestHash})
+	if err != nil {
+		return false, err
+	}
+	return c.rdb.SetNX(ctx, "idempotency:"+key, data, expiration).Result()
+}
+
+// GetIdempotencyKey returns the request hash and resource ID stored under
+// key, or empty strings if the key is unused.
+func (c *Client) GetIdempotencyKey(ctx context.Context, key string) (string, string, error) {
+	var record idempotencyRecord
+	if err := c.GetJSON(ctx, "idempotency:"+key, &record); err != nil {
+		if err == redis.Nil {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+	return record.RequestHash, record.ResourceID, nil
+}
+
+// CompleteIdempotencyKey records the resource created by the request that
+// claimed key.
+func (c *Client) CompleteIdempotencyKey(ctx context.Context, key, requestHash, resourceID string, expiration time.Duration) error {
+	return c.SetJSON(ctx, "idempotency:"+key, idempotencyRecord{RequestHash: requestHash, ResourceID: resourceID}, expiration)
+}
+
+// DeleteIdempotencyKey frees key.
+func (c *Client) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	return c.rdb.Del(ctx, "idempotency:"+key).Err()
+}
+
+// GetJSON decodes the JSON value stored under key into dest.
+func (c *Client) GetJSON(ctx context.Context, key string, dest interface{}) error {
+	data, err := c.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// SetJSON stores value as JSON under key with expiration.
+func (c *Client) SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(ctx, key, data, expiration).Err()
+}
+
 // Ping checks the Redis connection.
 func (c *Client) Ping(ctx context.Context) error {
 	return c.rdb.Ping(ctx).Err()
@@ -4,6 +4,7 @@ package websocket
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -45,6 +46,14 @@ const (
 
 	// Maximum message size allowed from peer.
 	maxMessageSize = 512
+
+	// Messages queued for a client before it is dropped as too slow.
+	sendBufferSize = 256
+
+	// BearerProtocol is the subprotocol a browser offers, followed by its
+	// JWT, to authenticate without headers or query parameters, e.g.
+	// new WebSocket(url, ["bearer", token]).
+	BearerProtocol = "bearer"
 )
 
 var upgrader = websocket.Upgrader{
@@ -75,10 +84,37 @@ type Client struct {
 	mu            sync.RWMutex
 }
 
+// StockChannel is the channel a symbol's price updates are sent to.
+func StockChannel(symbol string) string {
+	return "stock:" + strings.ToUpper(symbol)
+}
+
+// message is an encoded event and the clients it is for.
+type message struct {
+	data []byte
+	// channel, when set, limits the message to clients subscribed to it.
+	channel string
+	// userID, when set, limits the message to that user's clients.
+	userID string
+}
+
+// matches reports whether client should receive the message.
+func (m message) matches(client *Client) bool {
+	if m.userID != "" && client.UserID != m.userID {
+		return false
+	}
+	if m.channel == "" {
+		return true
+	}
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+	return client.Subscriptions[m.channel]
+}
+
 // Hub maintains the set of active clients and broadcasts messages.
 type Hub struct {
 	clients    map[*Client]bool
-	broadcast  chan []byte
+	broadcast  chan message
 	register   chan *Client
 	unregister chan *Client
 	mu         sync.RWMutex
@@ -88,7 +124,7 @@ type Hub struct {
 func NewHub() *Hub {
 	return &Hub{
 		clients:    make(map[*Client]bool),
-		broadcast:  make(chan []byte, 256),
+		broadcast:  make(chan message, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 	}
@@ -102,7 +138,7 @@ func (h *Hub) Run() {
 			h.mu.Lock()
 			h.clients[client] = true
 			h.mu.Unlock()
-			log.Info().Str("client_id", client.ID).Msg("WebSocket client connected")
+			log.Info().Str("client_id", client.ID).Str("user_id", client.UserID).Msg("WebSocket client connected")
 
 		case client := <-h.unregister:
 			h.mu.Lock()
@@ -113,68 +149,51 @@ func (h *Hub) Run() {
 			h.mu.Unlock()
 			log.Info().Str("client_id", client.ID).Msg("WebSocket client disconnected")
 
-		case message := <-h.broadcast:
-			h.mu.RLock()
-			// Collect clients that need to be removed
-			var clientsToRemove []*Client
+		case msg := <-h.broadcast:
+			h.mu.Lock()
 			for client := range h.clients {
+				if !msg.matches(client) {
+					continue
+				}
 				select {
-				case client.send <- message:
+				case client.send <- msg.data:
 				default:
-					// Client buffer full, mark for removal
-					clientsToRemove = append(clientsToRemove, client)
+					// Drop a client that cannot keep up; closing send
+					// makes its writePump close the connection.
+					close(client.send)
+					delete(h.clients, client)
+					log.Warn().Str("client_id", client.ID).Msg("Dropped slow WebSocket client")
 				}
 			}
-			h.mu.RUnlock()
-
-			// Remove clients with full buffers
-			if len(clientsToRemove) > 0 {
-				h.mu.Lock()
-				for _, client := range clientsToRemove {
-					if _, ok := h.clients[client]; ok {
-						close(client.send)
-						delete(h.clients, client)
-					}
-				}
-				h.mu.Unlock()
-			}
+			h.mu.Unlock()
 		}
 	}
 }
 
 // Broadcast sends an event to all connected clients.
 func (h *Hub) Broadcast(event Event) error {
-	event.Timestamp = time.Now()
-	data, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-	h.broadcast <- data
-	return nil
+	return h.publish(message{}, event)
 }
 
 // BroadcastToChannel sends an event to clients subscribed to a specific channel.
 func (h *Hub) BroadcastToChannel(channel string, event Event) error {
+	return h.publish(message{channel: channel}, event)
+}
+
+// SendToUser sends an event to every connection of a user.
+func (h *Hub) SendToUser(userID string, event Event) error {
+	return h.publish(message{userID: userID}, event)
+}
+
+// publish timestamps and encodes event and queues it for Run to deliver.
+func (h *Hub) publish(msg message, event Event) error {
 	event.Timestamp = time.Now()
 	data, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
-
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	for client := range h.clients {
-		client.mu.RLock()
-		if client.Subscriptions[channel] {
-			select {
-			case client.send <- data:
-			default:
-				// Skip if buffer full
-			}
-		}
-		client.mu.RUnlock()
-	}
+	msg.data = data
+	h.broadcast <- msg
 	return nil
 }
 
@@ -195,6 +214,28 @@ func (h *Hub) Unregister(client *Client) {
 	h.unregister <- client
 }
 
+// Listen registers a client without a connection that receives the events
+// sent to channels, for transports other than WebSocket such as
+// Server-Sent Events. The events channel is closed when stop is called or
+// the listener falls too far behind; stop must be called either way.
+func (h *Hub) Listen(channels []string) (events <-chan []byte, stop func()) {
+	client := &Client{
+		ID:            uuid.New().String(),
+		hub:           h,
+		send:          make(chan []byte, sendBufferSize),
+		Subscriptions: make(map[string]bool, len(channels)),
+	}
+	for _, channel := range channels {
+		client.Subscriptions[channel] = true
+	}
+	h.register <- client
+
+	var once sync.Once
+	return client.send, func() {
+		once.Do(func() { h.unregister <- client })
+	}
+}
+
 // Subscribe adds a client to a channel.
 func (c *Client) Subscribe(channel string) {
 	c.mu.Lock()
@@ -306,9 +347,13 @@ func (c *Client) writePump() {
 	}
 }
 
+// Authenticator resolves a JWT to the ID of the user it was issued to.
+type Authenticator func(token string) (userID string, err error)
+
 // WebSocketHandler handles WebSocket upgrade requests using gorilla/websocket.
 type WebSocketHandler struct {
-	hub *Hub
+	hub          *Hub
+	authenticate Authenticator
 }
 
 // NewWebSocketHandler creates a new WebSocket handler.
@@ -316,9 +361,52 @@ func NewWebSocketHandler(hub *Hub) *WebSocketHandler {
 	return &WebSocketHandler{hub: hub}
 }
 
+// SetAuthenticator requires every connection to present a JWT that
+// authenticate accepts, and ties the connection to its user so SendToUser
+// reaches it. Without one, connections are anonymous.
+func (h *WebSocketHandler) SetAuthenticator(authenticate Authenticator) {
+	h.authenticate = authenticate
+}
+
+// requestToken returns the JWT a connection request presents in the token
+// query parameter, the BearerProtocol subprotocol, or the Authorization
+// header, and whether it came as a subprotocol.
+func requestToken(r *http.Request) (string, bool) {
+	if token := r.URL.Query().Get("token"); token != "" {
+		return token, false
+	}
+	if protocols := websocket.Subprotocols(r); len(protocols) == 2 && protocols[0] == BearerProtocol {
+		return protocols[1], true
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token, false
+	}
+	return "", false
+}
+
 // HandleWebSocket handles WebSocket connections.
 func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	var userID string
+	var responseHeader http.Header
+	if h.authenticate != nil {
+		token, viaProtocol := requestToken(c.Request)
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "token required", "code": "unauthorized"})
+			return
+		}
+		var err error
+		if userID, err = h.authenticate(token); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token", "code": "invalid_token"})
+			return
+		}
+		if viaProtocol {
+			// Browsers fail the handshake unless an offered subprotocol is accepted
+			responseHeader = http.Header{}
+			responseHeader.Set("Sec-WebSocket-Protocol", BearerProtocol)
+		}
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, responseHeader)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to upgrade connection to WebSocket")
 		return
@@ -333,9 +421,10 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	// Create client
 	client := &Client{
 		ID:            clientID,
+		UserID:        userID,
 		hub:           h.hub,
 		conn:          conn,
-		send:          make(chan []byte, 256),
+		send:          make(chan []byte, sendBufferSize),
 		Subscriptions: make(map[string]bool),
 	}
 
@@ -348,7 +437,7 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 }
 
 // RegisterRoutes registers WebSocket routes.
-func (h *WebSocketHandler) RegisterRoutes(r *gin.Engine) {
+func (h *WebSocketHandler) RegisterRoutes(r gin.IRouter) {
 	r.GET("/ws", h.HandleWebSocket)
 
 	// Status endpoint
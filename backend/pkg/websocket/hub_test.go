@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -429,3 +430,191 @@ func TestEventTypes(t *testing.T) {
 		}
 	}
 }
+
+func TestHub_SendToUser(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	alice1 := &Client{ID: "alice-1", UserID: "alice", hub: hub, send: make(chan []byte, 256)}
+	alice2 := &Client{ID: "alice-2", UserID: "alice", hub: hub, send: make(chan []byte, 256)}
+	bob := &Client{ID: "bob", UserID: "bob", hub: hub, send: make(chan []byte, 256)}
+	for _, client := range []*Client{alice1, alice2, bob} {
+		hub.Register(client)
+	}
+
+	if err := hub.SendToUser("alice", Event{Type: EventStockAlertTriggered}); err != nil {
+		t.Fatalf("SendToUser failed: %v", err)
+	}
+
+	// Every connection of the user receives the event
+	for _, client := range []*Client{alice1, alice2} {
+		select {
+		case data := <-client.send:
+			var received Event
+			if err := json.Unmarshal(data, &received); err != nil || received.Type != EventStockAlertTriggered {
+				t.Errorf("%s received %s, expected an alert event", client.ID, data)
+			}
+		case <-time.After(500 * time.Millisecond):
+			t.Errorf("%s should have received the event", client.ID)
+		}
+	}
+	select {
+	case <-bob.send:
+		t.Error("Another user's client should not receive the event")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestHub_DropsSlowClient(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	slow := &Client{ID: "slow", hub: hub, send: make(chan []byte, 1)}
+	fast := &Client{ID: "fast", hub: hub, send: make(chan []byte, 256)}
+	hub.Register(slow)
+	hub.Register(fast)
+
+	// The second event finds the slow client's buffer full
+	for i := 0; i < 2; i++ {
+		if err := hub.Broadcast(Event{Type: EventStockPriceUpdate, Payload: i}); err != nil {
+			t.Fatalf("Broadcast failed: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-fast.send:
+		case <-time.After(500 * time.Millisecond):
+			t.Fatal("Timeout waiting for the fast client's events")
+		}
+	}
+
+	if hub.ClientCount() != 1 {
+		t.Errorf("Expected the slow client to be dropped, got %d clients", hub.ClientCount())
+	}
+	// The first event is still queued, then send is closed
+	if _, ok := <-slow.send; !ok {
+		t.Fatal("Expected the queued event before the channel closed")
+	}
+	if _, ok := <-slow.send; ok {
+		t.Error("Expected the slow client's send channel to be closed")
+	}
+
+	// Unregistering a dropped client is harmless
+	hub.Unregister(slow)
+	if hub.ClientCount() != 1 {
+		t.Errorf("Expected 1 client, got %d", hub.ClientCount())
+	}
+}
+
+func TestStockChannel(t *testing.T) {
+	if got := StockChannel("aapl"); got != "stock:AAPL" {
+		t.Errorf("StockChannel() = %q, expected stock:AAPL", got)
+	}
+}
+
+// authenticatedServer serves a hub whose connections need the token "good",
+// issued to user-1.
+func authenticatedServer(t *testing.T) (*Hub, string) {
+	t.Helper()
+	hub := NewHub()
+	go hub.Run()
+
+	handler := NewWebSocketHandler(hub)
+	handler.SetAuthenticator(func(token string) (string, error) {
+		if token != "good" {
+			return "", errors.New("invalid token")
+		}
+		return "user-1", nil
+	})
+	router := gin.New()
+	handler.RegisterRoutes(router.Group("/api/v1"))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return hub, "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws"
+}
+
+func TestWebSocketHandler_RequiresToken(t *testing.T) {
+	_, wsURL := authenticatedServer(t)
+
+	for _, url := range []string{wsURL, wsURL + "?token=bad"} {
+		_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+		if err == nil {
+			t.Fatalf("Dial(%s) succeeded, expected the upgrade to be refused", url)
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Dial(%s) response = %v, expected status 401", url, resp)
+		}
+	}
+}
+
+func TestWebSocketHandler_AuthenticatesUser(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		query     string
+		dialer    websocket.Dialer
+		header    http.Header
+		wantProto string
+	}{
+		{"query parameter", "?token=good", websocket.Dialer{}, nil, ""},
+		{"subprotocol", "", websocket.Dialer{Subprotocols: []string{BearerProtocol, "good"}}, nil, BearerProtocol},
+		{"authorization header", "", websocket.Dialer{}, http.Header{"Authorization": {"Bearer good"}}, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			hub, wsURL := authenticatedServer(t)
+
+			conn, _, err := tt.dialer.Dial(wsURL+tt.query, tt.header)
+			if err != nil {
+				t.Fatalf("Failed to connect to WebSocket: %v", err)
+			}
+			defer conn.Close()
+			if conn.Subprotocol() != tt.wantProto {
+				t.Errorf("Negotiated subprotocol %q, expected %q", conn.Subprotocol(), tt.wantProto)
+			}
+
+			// The connection belongs to the token's user
+			time.Sleep(100 * time.Millisecond)
+			_ = hub.SendToUser("user-1", Event{Type: EventNotificationNew})
+			_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatalf("Failed to read message: %v", err)
+			}
+			var received Event
+			if err := json.Unmarshal(data, &received); err != nil || received.Type != EventNotificationNew {
+				t.Errorf("Received %s, expected a notification event", data)
+			}
+		})
+	}
+}
+
+func TestHub_Listen(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	events, stop := hub.Listen([]string{StockChannel("AAPL")})
+	if hub.ClientCount() != 1 {
+		t.Fatalf("Expected the listener to be registered, got %d clients", hub.ClientCount())
+	}
+
+	_ = hub.BroadcastToChannel(StockChannel("MSFT"), Event{Type: EventStockPriceUpdate})
+	_ = hub.BroadcastToChannel(StockChannel("AAPL"), Event{Type: EventStockPriceUpdate, Payload: "AAPL"})
+	select {
+	case data := <-events:
+		var received Event
+		if err := json.Unmarshal(data, &received); err != nil || received.Payload != "AAPL" {
+			t.Errorf("Expected only the AAPL update, got %s", data)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Timeout waiting for the AAPL update")
+	}
+
+	stop()
+	stop()
+	if _, ok := <-events; ok {
+		t.Error("Expected events to be closed after stop")
+	}
+	if hub.ClientCount() != 0 {
+		t.Errorf("Expected 0 clients after stop, got %d", hub.ClientCount())
+	}
+}
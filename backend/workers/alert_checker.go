@@ -3,53 +3,211 @@ package workers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 
-	"super-dashboard/backend/internal/model"
-	"super-dashboard/backend/internal/repository"
-	"super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/pkg/jobs"
 )
 
+// AlertCheckerStats counts the work done by the alert checker.
+type AlertCheckerStats struct {
+	Runs            uint64 `json:"runs"`
+	AlertsEvaluated uint64 `json:"alerts_evaluated"`
+	QuotesFetched   uint64 `json:"quotes_fetched"`
+	Triggered       uint64 `json:"triggered"`
+}
+
+// alertCheckerTotals accumulates AlertCheckerStats across all runs.
+var alertCheckerTotals struct {
+	runs            atomic.Uint64
+	alertsEvaluated atomic.Uint64
+	quotesFetched   atomic.Uint64
+	triggered       atomic.Uint64
+}
+
+// AlertCheckerMetrics returns the alert checker's totals since startup.
+func AlertCheckerMetrics() AlertCheckerStats {
+	return AlertCheckerStats{
+		Runs:            alertCheckerTotals.runs.Load(),
+		AlertsEvaluated: alertCheckerTotals.alertsEvaluated.Load(),
+		QuotesFetched:   alertCheckerTotals.quotesFetched.Load(),
+		Triggered:       alertCheckerTotals.triggered.Load(),
+	}
+}
+
+// quote is a symbol's market data at a point in time, shared by every alert on it.
+type quote struct {
+	price  float64
+	volume float64
+	at     time.Time
+}
+
+// observation is a value an alert is evaluated against.
+type observation struct {
+	value float64
+	at    time.Time
+}
+
+// AlertCooldown is how long a triggered alert stays quiet, so that a price
+// that stays above its target notifies once rather than on every tick.
+const AlertCooldown = time.Hour
+
+// AlertStore loads active alerts and records the values they are evaluated
+// against. *repository.AlertRepository implements it.
+type AlertStore interface {
+	GetActiveAlerts(ctx context.Context) ([]model.Alert, error)
+	// UpdateAlertTrigger records that the alert triggered at currentValue.
+	UpdateAlertTrigger(ctx context.Context, alertID uuid.UUID, currentValue float64) error
+	// UpdateAlertValue records the value the alert was last evaluated
+	// against without triggering it.
+	UpdateAlertValue(ctx context.Context, alertID uuid.UUID, currentValue float64) error
+}
+
+// AlertNotifier tells a user their alert triggered, storing an in-app
+// notification and sending it on the channels the alert enables.
+// *service.NotificationService implements it.
+type AlertNotifier interface {
+	SendAlertNotification(ctx context.Context, alert *model.Alert, currentValue float64) error
+}
+
+// AlertEventPublisher is told about each alert a live run triggers.
+// *HubPublisher implements it.
+type AlertEventPublisher interface {
+	PublishAlertTriggered(trigger service.AlertTrigger)
+}
+
+// OddsSource looks up stored betting odds.
+type OddsSource interface {
+	// LatestOddsPrice returns the newest price of an outcome in a match's
+	// market as of asOf, across bookmakers.
+	LatestOddsPrice(ctx context.Context, matchID uuid.UUID, market, outcome string, asOf time.Time) (float64, error)
+}
+
+// dbOddsSource reads odds from the odds table.
+type dbOddsSource struct {
+	db *gorm.DB
+}
+
+// LatestOddsPrice implements OddsSource. Rows updated in place count from
+// their update time.
+func (s dbOddsSource) LatestOddsPrice(ctx context.Context, matchID uuid.UUID, market, outcome string, asOf time.Time) (float64, error) {
+	var odds model.Odds
+	err := s.db.WithContext(ctx).
+		Where("match_id = ? AND market = ? AND outcome = ?", matchID, market, outcome).
+		Where("updated_at <= ?", asOf).
+		Order("updated_at DESC").
+		First(&odds).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("no %s %s odds found for match %s", market, outcome, matchID)
+		}
+		return 0, err
+	}
+	return odds.Price, nil
+}
+
 // AlertCheckerWorker checks for alert conditions and sends notifications.
 type AlertCheckerWorker struct {
-	interval      time.Duration
-	log           zerolog.Logger
-	alertRepo     *repository.AlertRepository
-	notifService  *service.NotificationService
-	db            *gorm.DB
+	interval time.Duration
+	log      zerolog.Logger
+	alerts   AlertStore
+	notifier AlertNotifier
+	events   AlertEventPublisher
+	quotes   service.QuoteSource
+	odds     OddsSource
+	locker   jobs.Locker
+	db       *gorm.DB
+	// wake asks Run for a check before the next tick.
+	wake chan struct{}
 }
 
-// NewAlertCheckerWorker creates a new AlertCheckerWorker with the specified interval.
+// NewAlertCheckerWorker creates a new AlertCheckerWorker with the specified
+// interval. notifier may be nil for a worker that only does dry runs.
 func NewAlertCheckerWorker(
 	interval time.Duration,
 	log zerolog.Logger,
-	alertRepo *repository.AlertRepository,
-	notifService *service.NotificationService,
+	alerts AlertStore,
+	notifier AlertNotifier,
 	db *gorm.DB,
 ) *AlertCheckerWorker {
-	return &AlertCheckerWorker{
-		interval:     interval,
-		log:          log.With().Str("worker", "alert_checker").Logger(),
-		alertRepo:    alertRepo,
-		notifService: notifService,
-		db:           db,
+	w := &AlertCheckerWorker{
+		interval: interval,
+		log:      log.With().Str("worker", "alert_checker").Logger(),
+		alerts:   alerts,
+		notifier: notifier,
+		db:       db,
+		wake:     make(chan struct{}, 1),
 	}
+	if db != nil {
+		w.odds = dbOddsSource{db: db}
+	}
+	return w
+}
+
+// PublishPriceUpdates makes Run check alerts now rather than at the next
+// tick, so alerts see freshly synced prices promptly. It never blocks.
+func (w *AlertCheckerWorker) PublishPriceUpdates(updates []PriceUpdate) {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// SetEventPublisher sends each alert a live run triggers to publisher, on
+// top of the stored notification.
+func (w *AlertCheckerWorker) SetEventPublisher(publisher AlertEventPublisher) {
+	w.events = publisher
+}
+
+// SetOddsSource replaces the odds table as the source of odds change alert
+// values.
+func (w *AlertCheckerWorker) SetOddsSource(odds OddsSource) {
+	w.odds = odds
+}
+
+// SetQuoteSource makes live runs price stock alerts from live quotes rather
+// than the latest stored price bar.
+func (w *AlertCheckerWorker) SetQuoteSource(quotes service.QuoteSource) {
+	w.quotes = quotes
+}
+
+// SetLocker makes each tick take locker's lock first, so that only one
+// replica checks alerts per interval. Checks woken by price updates are not
+// locked, since only the replica that synced the prices is woken.
+func (w *AlertCheckerWorker) SetLocker(locker jobs.Locker) {
+	w.locker = locker
 }
 
 // StartAlertChecker starts the alert checker worker.
-// It runs until the context is cancelled.
+// It ticks at the given interval and runs until the context is cancelled,
+// then marks wg done if wg is not nil. quotes may be nil to use stored
+// prices only.
 func StartAlertChecker(
 	ctx context.Context,
+	wg *sync.WaitGroup,
+	interval time.Duration,
 	log zerolog.Logger,
-	alertRepo *repository.AlertRepository,
-	notifService *service.NotificationService,
+	alerts AlertStore,
+	notifier AlertNotifier,
 	db *gorm.DB,
+	quotes service.QuoteSource,
 ) {
-	worker := NewAlertCheckerWorker(30*time.Second, log, alertRepo, notifService, db)
+	if wg != nil {
+		defer wg.Done()
+	}
+	worker := NewAlertCheckerWorker(interval, log, alerts, notifier, db)
+	worker.SetQuoteSource(quotes)
 	worker.Run(ctx)
 }
 
@@ -61,7 +219,7 @@ func (w *AlertCheckerWorker) Run(ctx context.Context) {
 	defer ticker.Stop()
 
 	// Run immediately on startup
-	w.check(ctx)
+	w.tick(ctx)
 
 	for {
 		select {
@@ -69,183 +227,387 @@ func (w *AlertCheckerWorker) Run(ctx context.Context) {
 			w.log.Info().Msg("Alert checker worker stopping")
 			return
 		case <-ticker.C:
+			w.tick(ctx)
+		case <-w.wake:
 			w.check(ctx)
 		}
 	}
 }
 
+// tick checks alerts if this replica holds the tick's lock.
+func (w *AlertCheckerWorker) tick(ctx context.Context) {
+	if takeTick(ctx, w.locker, "alert_checker", w.interval, w.log) {
+		w.check(ctx)
+	}
+}
+
 // check evaluates all active alerts and triggers notifications if conditions are met.
 func (w *AlertCheckerWorker) check(ctx context.Context) {
+	if _, err := w.RunOnce(ctx, service.EngineRunOptions{}); err != nil {
+		w.log.Error().Err(err).Msg("Alert check failed")
+	}
+}
+
+// RunOnce evaluates all active alerts against the latest data, notifying
+// users and recording each trigger. With opts.DryRun the triggers are only
+// returned. With a date range, price and volume alerts are replayed against
+// every bar in the range and other alerts against the data as of its end.
+func (w *AlertCheckerWorker) RunOnce(ctx context.Context, opts service.EngineRunOptions) ([]service.AlertTrigger, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	startTime := time.Now()
-	w.log.Debug().Msg("Checking alert conditions")
+	w.log.Debug().Bool("dry_run", opts.DryRun).Msg("Checking alert conditions")
 
 	// Load all active alerts
-	alerts, err := w.alertRepo.GetActiveAlerts(ctx)
+	alerts, err := w.alerts.GetActiveAlerts(ctx)
 	if err != nil {
-		w.log.Error().Err(err).Msg("Failed to load active alerts")
-		return
+		return nil, fmt.Errorf("failed to load active alerts: %w", err)
 	}
 
 	if len(alerts) == 0 {
 		w.log.Debug().Msg("No active alerts to check")
-		return
+		return nil, nil
 	}
 
 	w.log.Debug().Int("count", len(alerts)).Msg("Loaded active alerts")
 
-	// Check each alert
-	triggeredCount := 0
-	for _, alert := range alerts {
-		triggered, err := w.checkAlert(ctx, &alert)
-		if err != nil {
-			w.log.Error().
-				Err(err).
-				Str("alert_id", alert.ID.String()).
-				Str("symbol", alert.Symbol).
-				Msg("Failed to check alert")
-			continue
+	// Evaluate alerts symbol by symbol so quotes are fetched once per run
+	var stats AlertCheckerStats
+	var triggers []service.AlertTrigger
+	bySymbol := groupAlertsBySymbol(alerts)
+	symbols := make([]string, 0, len(bySymbol))
+	for symbol := range bySymbol {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		group := bySymbol[symbol]
+
+		var quotes []quote
+		if needsQuote(group) {
+			stats.QuotesFetched++
+			quotes, err = w.getQuotes(ctx, symbol, opts)
+			if err != nil {
+				w.log.Error().
+					Err(err).
+					Str("symbol", symbol).
+					Int("alerts", len(group)).
+					Msg("Failed to fetch quote")
+			}
 		}
 
-		if triggered {
-			triggeredCount++
+		for i := range group {
+			alert := &group[i]
+			if isQuoteAlert(alert) && len(quotes) == 0 {
+				continue
+			}
+
+			stats.AlertsEvaluated++
+			alertTriggers, err := w.checkAlert(ctx, alert, quotes, opts)
+			if err != nil {
+				w.log.Error().
+					Err(err).
+					Str("alert_id", alert.ID.String()).
+					Str("symbol", alert.Symbol).
+					Msg("Failed to check alert")
+				continue
+			}
+
+			stats.Triggered += uint64(len(alertTriggers))
+			triggers = append(triggers, alertTriggers...)
 		}
 	}
 
+	// Dry runs are not counted in the checker's metrics
+	if !opts.DryRun {
+		alertCheckerTotals.runs.Add(1)
+		alertCheckerTotals.alertsEvaluated.Add(stats.AlertsEvaluated)
+		alertCheckerTotals.quotesFetched.Add(stats.QuotesFetched)
+		alertCheckerTotals.triggered.Add(stats.Triggered)
+	}
+
 	duration := time.Since(startTime)
 	w.log.Info().
 		Int("total_alerts", len(alerts)).
-		Int("triggered", triggeredCount).
+		Int("symbols", len(symbols)).
+		Uint64("alerts_evaluated", stats.AlertsEvaluated).
+		Uint64("quotes_fetched", stats.QuotesFetched).
+		Uint64("triggered", stats.Triggered).
+		Bool("dry_run", opts.DryRun).
 		Dur("duration", duration).
 		Msg("Alert check completed")
+
+	return triggers, nil
+}
+
+// groupAlertsBySymbol groups alerts by their symbol.
+func groupAlertsBySymbol(alerts []model.Alert) map[string][]model.Alert {
+	bySymbol := make(map[string][]model.Alert)
+	for _, alert := range alerts {
+		bySymbol[alert.Symbol] = append(bySymbol[alert.Symbol], alert)
+	}
+	return bySymbol
+}
+
+// isQuoteAlert reports whether the alert is evaluated against the symbol's quote.
+func isQuoteAlert(alert *model.Alert) bool {
+	return alert.Type == model.AlertTypeStockPrice || alert.Type == model.AlertTypeStockVolume
+}
+
+// needsQuote reports whether any alert in the group is evaluated against a quote.
+func needsQuote(alerts []model.Alert) bool {
+	for i := range alerts {
+		if isQuoteAlert(&alerts[i]) {
+			return true
+		}
+	}
+	return false
 }
 
 // checkAlert checks a single alert and triggers it if conditions are met.
-func (w *AlertCheckerWorker) checkAlert(ctx context.Context, alert *model.Alert) (bool, error) {
-	// Get current value based on alert type
-	currentValue, err := w.getCurrentValue(ctx, alert)
+// quotes are the symbol's quotes for price and volume alerts, oldest first.
+// Each observation is measured against the previous one, as repeated live
+// runs would, and the last one is saved as the alert's current value for the
+// next run. An alert does not trigger again within AlertCooldown.
+func (w *AlertCheckerWorker) checkAlert(ctx context.Context, alert *model.Alert, quotes []quote, opts service.EngineRunOptions) ([]service.AlertTrigger, error) {
+	// Get observed values based on alert type
+	observations, err := w.observe(ctx, alert, quotes, opts)
 	if err != nil {
-		return false, fmt.Errorf("failed to get current value: %w", err)
+		return nil, fmt.Errorf("failed to get current value: %w", err)
 	}
 
-	// Evaluate alert condition
-	triggered := w.evaluateCondition(alert, currentValue)
+	var triggers []service.AlertTrigger
+	baseline := *alert
+	lastTriggered := false
+	for _, obs := range observations {
+		// Evaluate alert condition against the previous observation
+		met := w.evaluateCondition(&baseline, obs.value) && !inCooldown(&baseline, obs.at)
+		baseline.CurrentValue = obs.value
+		lastTriggered = met
+		if !met {
+			continue
+		}
+		at := obs.at
+		baseline.LastTriggered = &at
 
-	if triggered {
 		w.log.Info().
 			Str("alert_id", alert.ID.String()).
 			Str("symbol", alert.Symbol).
 			Str("type", string(alert.Type)).
 			Str("condition", string(alert.Condition)).
 			Float64("target", alert.TargetValue).
-			Float64("current", currentValue).
+			Float64("current", obs.value).
+			Bool("dry_run", opts.DryRun).
 			Msg("Alert triggered")
 
+		triggers = append(triggers, service.AlertTrigger{
+			AlertID:     alert.ID,
+			UserID:      alert.UserID,
+			Symbol:      alert.Symbol,
+			Type:        alert.Type,
+			Condition:   alert.Condition,
+			TargetValue: alert.TargetValue,
+			Value:       obs.value,
+			TriggeredAt: obs.at,
+		})
+
+		if opts.DryRun {
+			continue
+		}
+
 		// Send notification
-		if err := w.notifService.SendAlertNotification(ctx, alert, currentValue); err != nil {
-			w.log.Error().
-				Err(err).
-				Str("alert_id", alert.ID.String()).
-				Msg("Failed to send alert notification")
+		if w.notifier != nil {
+			if err := w.notifier.SendAlertNotification(ctx, alert, obs.value); err != nil {
+				w.log.Error().
+					Err(err).
+					Str("alert_id", alert.ID.String()).
+					Msg("Failed to send alert notification")
+			}
 		}
 
 		// Update alert trigger information
-		if err := w.alertRepo.UpdateAlertTrigger(ctx, alert.ID, currentValue); err != nil {
+		if err := w.alerts.UpdateAlertTrigger(ctx, alert.ID, obs.value); err != nil {
 			w.log.Error().
 				Err(err).
 				Str("alert_id", alert.ID.String()).
 				Msg("Failed to update alert trigger")
 		}
 
-		// TODO: Emit WebSocket event
-		// ws.EmitToUser(alert.UserID, "alert:triggered", alert)
+		if w.events != nil {
+			w.events.PublishAlertTriggered(triggers[len(triggers)-1])
+		}
+	}
 
-		return true, nil
+	// A trigger already saved the value it fired at
+	if !opts.DryRun && len(observations) > 0 && !lastTriggered {
+		if err := w.alerts.UpdateAlertValue(ctx, alert.ID, baseline.CurrentValue); err != nil {
+			w.log.Error().
+				Err(err).
+				Str("alert_id", alert.ID.String()).
+				Msg("Failed to update alert value")
+		}
 	}
 
-	return false, nil
+	return triggers, nil
 }
 
-// getCurrentValue retrieves the current value for the alert's symbol based on its type.
-func (w *AlertCheckerWorker) getCurrentValue(ctx context.Context, alert *model.Alert) (float64, error) {
+// inCooldown reports whether the alert triggered less than AlertCooldown
+// before at. Replays of bars older than the last trigger are not held back.
+func inCooldown(alert *model.Alert, at time.Time) bool {
+	if alert.LastTriggered == nil || at.Before(*alert.LastTriggered) {
+		return false
+	}
+	return at.Sub(*alert.LastTriggered) < AlertCooldown
+}
+
+// observe returns the values the alert is evaluated against, oldest first.
+func (w *AlertCheckerWorker) observe(ctx context.Context, alert *model.Alert, quotes []quote, opts service.EngineRunOptions) ([]observation, error) {
 	switch alert.Type {
 	case model.AlertTypeStockPrice:
-		return w.getStockPrice(ctx, alert.Symbol)
+		observations := make([]observation, len(quotes))
+		for i, q := range quotes {
+			observations[i] = observation{value: q.price, at: q.at}
+		}
+		return observations, nil
 
 	case model.AlertTypeStockVolume:
-		return w.getStockVolume(ctx, alert.Symbol)
+		observations := make([]observation, len(quotes))
+		for i, q := range quotes {
+			observations[i] = observation{value: q.volume, at: q.at}
+		}
+		return observations, nil
+	}
 
-	case model.AlertTypeOddsChange:
-		return w.getOddsValue(ctx, alert.Symbol)
+	at := time.Now()
+	if opts.HasRange() {
+		at = opts.To
+	}
+	value, err := w.getCurrentValue(ctx, alert, at)
+	if err != nil {
+		return nil, err
+	}
+	return []observation{{value: value, at: at}}, nil
+}
 
+// getCurrentValue retrieves the value of a non-quote alert as of the given time.
+func (w *AlertCheckerWorker) getCurrentValue(ctx context.Context, alert *model.Alert, asOf time.Time) (float64, error) {
+	if alert.Type == model.AlertTypeOddsChange {
+		return w.getOddsValue(ctx, alert.Symbol, asOf)
+	}
+	if w.db == nil {
+		return 0, fmt.Errorf("no data source for %s alerts", alert.Type)
+	}
+
+	switch alert.Type {
 	case model.AlertTypeTechnical:
 		// Technical alerts might have complex calculations (RSI, MACD, etc.)
 		return w.getTechnicalIndicator(ctx, alert)
 
 	case model.AlertTypeValueBet:
 		// Value bet alerts check for betting opportunities
-		return w.getValueBetMetric(ctx, alert.Symbol)
+		return w.getValueBetMetric(ctx, alert.Symbol, asOf)
 
 	default:
 		return 0, fmt.Errorf("unsupported alert type: %s", alert.Type)
 	}
 }
 
-// getStockPrice retrieves the latest stock price.
-func (w *AlertCheckerWorker) getStockPrice(ctx context.Context, symbol string) (float64, error) {
-	var stockPrice model.StockPrice
-	err := w.db.WithContext(ctx).
-		Joins("JOIN stocks ON stocks.id = stock_prices.stock_id").
-		Where("stocks.symbol = ?", symbol).
-		Order("stock_prices.timestamp DESC").
-		First(&stockPrice).Error
+// getQuotes retrieves the latest stock price and volume, or every bar in the
+// options' date range oldest first. Live runs use the quote source when set.
+func (w *AlertCheckerWorker) getQuotes(ctx context.Context, symbol string, opts service.EngineRunOptions) ([]quote, error) {
+	if w.quotes != nil && !opts.HasRange() {
+		return w.getLiveQuote(ctx, symbol)
+	}
+	if w.db == nil {
+		return nil, fmt.Errorf("no price data source for symbol %s", symbol)
+	}
 
+	query := w.db.WithContext(ctx).
+		Joins("JOIN stocks ON stocks.id = stock_prices.stock_id").
+		Where("stocks.symbol = ?", symbol)
+
+	var stockPrices []model.StockPrice
+	var err error
+	if opts.HasRange() {
+		err = query.
+			Where("stock_prices.timestamp BETWEEN ? AND ?", opts.From, opts.To).
+			Order("stock_prices.timestamp ASC").
+			Find(&stockPrices).Error
+	} else {
+		err = query.
+			Order("stock_prices.timestamp DESC").
+			Limit(1).
+			Find(&stockPrices).Error
+	}
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return 0, fmt.Errorf("no price data found for symbol %s", symbol)
-		}
-		return 0, err
+		return nil, err
+	}
+	if len(stockPrices) == 0 {
+		return nil, fmt.Errorf("no price data found for symbol %s", symbol)
 	}
 
-	return stockPrice.Close, nil
+	quotes := make([]quote, len(stockPrices))
+	for i, stockPrice := range stockPrices {
+		quotes[i] = quote{
+			price:  stockPrice.Close,
+			volume: float64(stockPrice.Volume),
+			at:     stockPrice.Timestamp,
+		}
+	}
+	return quotes, nil
 }
 
-// getStockVolume retrieves the latest stock volume.
-func (w *AlertCheckerWorker) getStockVolume(ctx context.Context, symbol string) (float64, error) {
-	var stockPrice model.StockPrice
-	err := w.db.WithContext(ctx).
-		Joins("JOIN stocks ON stocks.id = stock_prices.stock_id").
-		Where("stocks.symbol = ?", symbol).
-		Order("stock_prices.timestamp DESC").
-		First(&stockPrice).Error
-
+// getLiveQuote fetches the symbol's live price. Live quotes carry no volume,
+// so the volume comes from the latest stored bar when there is one.
+func (w *AlertCheckerWorker) getLiveQuote(ctx context.Context, symbol string) ([]quote, error) {
+	live, err := w.quotes.GetQuote(ctx, symbol)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return 0, fmt.Errorf("no volume data found for symbol %s", symbol)
-		}
-		return 0, err
+		return nil, err
 	}
 
-	return float64(stockPrice.Volume), nil
+	q := quote{price: live.Price, at: time.Now()}
+	if w.db != nil {
+		var latest model.StockPrice
+		err := w.db.WithContext(ctx).
+			Joins("JOIN stocks ON stocks.id = stock_prices.stock_id").
+			Where("stocks.symbol = ?", symbol).
+			Order("stock_prices.timestamp DESC").
+			First(&latest).Error
+		if err == nil {
+			q.volume = float64(latest.Volume)
+		}
+	}
+	return []quote{q}, nil
 }
 
-// getOddsValue retrieves the latest odds for a match/market combination.
-func (w *AlertCheckerWorker) getOddsValue(ctx context.Context, identifier string) (float64, error) {
-	// identifier format: "match_id:market:outcome"
-	// TODO: Parse identifier and fetch odds
-	var odds model.Odds
-	err := w.db.WithContext(ctx).
-		Where("match_id = ?", identifier). // Simplified - needs proper parsing
-		Order("created_at DESC").
-		First(&odds).Error
-
+// getOddsValue retrieves the latest odds as of asOf for the outcome named by
+// identifier, in the form "match_id:market:outcome".
+func (w *AlertCheckerWorker) getOddsValue(ctx context.Context, identifier string, asOf time.Time) (float64, error) {
+	if w.odds == nil {
+		return 0, fmt.Errorf("no data source for %s alerts", model.AlertTypeOddsChange)
+	}
+	matchID, market, outcome, err := parseOddsIdentifier(identifier)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return 0, fmt.Errorf("no odds found for %s", identifier)
-		}
 		return 0, err
 	}
+	return w.odds.LatestOddsPrice(ctx, matchID, market, outcome, asOf)
+}
 
-	return odds.Price, nil
+// parseOddsIdentifier splits an odds alert's "match_id:market:outcome"
+// identifier. The outcome may itself contain colons.
+func parseOddsIdentifier(identifier string) (uuid.UUID, string, string, error) {
+	parts := strings.SplitN(identifier, ":", 3)
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return uuid.Nil, "", "", fmt.Errorf("odds identifier %q is not match_id:market:outcome", identifier)
+	}
+	matchID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, "", "", fmt.Errorf("odds identifier %q has an invalid match id: %w", identifier, err)
+	}
+	return matchID, parts[1], parts[2], nil
 }
 
 // getTechnicalIndicator calculates technical indicators like RSI, MACD, etc.
@@ -259,11 +621,12 @@ func (w *AlertCheckerWorker) getTechnicalIndicator(ctx context.Context, alert *m
 	return 0, fmt.Errorf("technical indicator calculation not implemented")
 }
 
-// getValueBetMetric retrieves value betting metrics.
-func (w *AlertCheckerWorker) getValueBetMetric(ctx context.Context, identifier string) (float64, error) {
+// getValueBetMetric retrieves the latest value betting metrics as of asOf.
+func (w *AlertCheckerWorker) getValueBetMetric(ctx context.Context, identifier string, asOf time.Time) (float64, error) {
 	var valueBet model.ValueBet
 	err := w.db.WithContext(ctx).
 		Where("match_id = ?", identifier).
+		Where("created_at <= ?", asOf).
 		Order("created_at DESC").
 		First(&valueBet).Error
 
@@ -277,7 +640,9 @@ func (w *AlertCheckerWorker) getValueBetMetric(ctx context.Context, identifier s
 	return valueBet.ValuePercent, nil
 }
 
-// evaluateCondition evaluates if the alert condition is met.
+// evaluateCondition evaluates if the alert condition is met. Percent and
+// crosses conditions compare currentValue with the alert's CurrentValue, the
+// value it was last evaluated against, and are never met on the first run.
 func (w *AlertCheckerWorker) evaluateCondition(alert *model.Alert, currentValue float64) bool {
 	switch alert.Condition {
 	case model.AlertConditionAbove:
@@ -0,0 +1,293 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+)
+
+// fakeAlertStore serves alerts and records triggers and saved values,
+// updating the alerts it serves as the repository would.
+type fakeAlertStore struct {
+	alerts   []model.Alert
+	triggers map[uuid.UUID][]float64
+	values   map[uuid.UUID][]float64
+}
+
+func (s *fakeAlertStore) GetActiveAlerts(ctx context.Context) ([]model.Alert, error) {
+	return s.alerts, nil
+}
+
+func (s *fakeAlertStore) UpdateAlertTrigger(ctx context.Context, alertID uuid.UUID, currentValue float64) error {
+	s.triggers[alertID] = append(s.triggers[alertID], currentValue)
+	now := time.Now()
+	for i := range s.alerts {
+		if s.alerts[i].ID == alertID {
+			s.alerts[i].CurrentValue = currentValue
+			s.alerts[i].LastTriggered = &now
+		}
+	}
+	return nil
+}
+
+func (s *fakeAlertStore) UpdateAlertValue(ctx context.Context, alertID uuid.UUID, currentValue float64) error {
+	s.values[alertID] = append(s.values[alertID], currentValue)
+	for i := range s.alerts {
+		if s.alerts[i].ID == alertID {
+			s.alerts[i].CurrentValue = currentValue
+		}
+	}
+	return nil
+}
+
+// fakeNotifier records the alerts it was asked to send.
+type fakeNotifier struct {
+	sent []uuid.UUID
+}
+
+func (n *fakeNotifier) SendAlertNotification(ctx context.Context, alert *model.Alert, currentValue float64) error {
+	n.sent = append(n.sent, alert.ID)
+	return nil
+}
+
+// fakePriceProvider serves fixed prices.
+type fakePriceProvider struct {
+	prices map[string]float64
+}
+
+func (p *fakePriceProvider) GetQuote(ctx context.Context, symbol string) (*service.MarketQuote, error) {
+	price, ok := p.prices[symbol]
+	if !ok {
+		return nil, errors.New("unknown symbol")
+	}
+	return &service.MarketQuote{Price: price}, nil
+}
+
+// fakeOddsSource serves fixed prices keyed by "match_id:market:outcome".
+type fakeOddsSource struct {
+	prices map[string]float64
+}
+
+func (s *fakeOddsSource) LatestOddsPrice(ctx context.Context, matchID uuid.UUID, market, outcome string, asOf time.Time) (float64, error) {
+	price, ok := s.prices[matchID.String()+":"+market+":"+outcome]
+	if !ok {
+		return 0, errors.New("no odds")
+	}
+	return price, nil
+}
+
+func newTestAlertChecker(alerts []model.Alert) (*AlertCheckerWorker, *fakeAlertStore, *fakeNotifier) {
+	store := &fakeAlertStore{alerts: alerts, triggers: make(map[uuid.UUID][]float64), values: make(map[uuid.UUID][]float64)}
+	notifier := &fakeNotifier{}
+	worker := NewAlertCheckerWorker(time.Minute, zerolog.Nop(), store, notifier, nil)
+	worker.SetQuoteSource(&fakePriceProvider{prices: map[string]float64{"AAPL": 145, "MSFT": 410}})
+	return worker, store, notifier
+}
+
+func TestAlertChecker_FiresBelowAlert(t *testing.T) {
+	below := model.Alert{ID: uuid.New(), UserID: uuid.New(), Type: model.AlertTypeStockPrice, Symbol: "AAPL", Condition: model.AlertConditionBelow, TargetValue: 150, NotifyEmail: true}
+	notMet := model.Alert{ID: uuid.New(), UserID: uuid.New(), Type: model.AlertTypeStockPrice, Symbol: "MSFT", Condition: model.AlertConditionBelow, TargetValue: 400}
+	unknown := model.Alert{ID: uuid.New(), UserID: uuid.New(), Type: model.AlertTypeStockPrice, Symbol: "NOPE", Condition: model.AlertConditionBelow, TargetValue: 400}
+	worker, store, notifier := newTestAlertChecker([]model.Alert{below, notMet, unknown})
+
+	triggers, err := worker.RunOnce(context.Background(), service.EngineRunOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(triggers) != 1 || triggers[0].AlertID != below.ID || triggers[0].Value != 145 {
+		t.Fatalf("Expected only the AAPL alert to trigger at 145, got %+v", triggers)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0] != below.ID {
+		t.Errorf("Expected one notification for the AAPL alert, got %v", notifier.sent)
+	}
+	if values := store.triggers[below.ID]; len(values) != 1 || values[0] != 145 {
+		t.Errorf("Expected the trigger to be recorded at 145, got %v", values)
+	}
+	if _, ok := store.triggers[notMet.ID]; ok {
+		t.Error("Expected the MSFT alert not to be recorded")
+	}
+	if values := store.values[notMet.ID]; len(values) != 1 || values[0] != 410 {
+		t.Errorf("Expected the MSFT alert's value to be saved at 410, got %v", values)
+	}
+}
+
+func TestAlertChecker_RelativeConditions(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition model.AlertCondition
+		target    float64
+		prices    []float64
+		want      []float64
+	}{
+		{name: "percent up", condition: model.AlertConditionPercentUp, target: 5, prices: []float64{100, 104, 110}, want: []float64{110}},
+		{name: "percent down", condition: model.AlertConditionPercentDown, target: 5, prices: []float64{100, 98, 90}, want: []float64{90}},
+		{name: "crosses up", condition: model.AlertConditionCrosses, target: 150, prices: []float64{140, 149, 151}, want: []float64{151}},
+		{name: "crosses down", condition: model.AlertConditionCrosses, target: 150, prices: []float64{160, 155, 145}, want: []float64{145}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := model.Alert{ID: uuid.New(), UserID: uuid.New(), Type: model.AlertTypeStockPrice, Symbol: "AAPL", Condition: tt.condition, TargetValue: tt.target}
+			worker, store, notifier := newTestAlertChecker([]model.Alert{alert})
+			prices := &fakePriceProvider{prices: map[string]float64{}}
+			worker.SetQuoteSource(prices)
+
+			for _, price := range tt.prices {
+				prices.prices["AAPL"] = price
+				if _, err := worker.RunOnce(context.Background(), service.EngineRunOptions{}); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+			}
+
+			got := store.triggers[alert.ID]
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("Expected triggers at %v, got %v", tt.want, got)
+			}
+			if len(notifier.sent) != len(tt.want) {
+				t.Errorf("Expected %d notifications, got %d", len(tt.want), len(notifier.sent))
+			}
+			if last := tt.prices[len(tt.prices)-1]; store.alerts[0].CurrentValue != last {
+				t.Errorf("Expected the current value to be saved as %v, got %v", last, store.alerts[0].CurrentValue)
+			}
+		})
+	}
+}
+
+func TestAlertChecker_FiresOddsAlert(t *testing.T) {
+	matchID := uuid.New()
+	drift := model.Alert{ID: uuid.New(), UserID: uuid.New(), Type: model.AlertTypeOddsChange, Symbol: matchID.String() + ":1x2:home", Condition: model.AlertConditionAbove, TargetValue: 2.5}
+	otherOutcome := model.Alert{ID: uuid.New(), UserID: uuid.New(), Type: model.AlertTypeOddsChange, Symbol: matchID.String() + ":1x2:away", Condition: model.AlertConditionAbove, TargetValue: 2.5}
+	malformed := model.Alert{ID: uuid.New(), UserID: uuid.New(), Type: model.AlertTypeOddsChange, Symbol: matchID.String(), Condition: model.AlertConditionAbove, TargetValue: 2.5}
+	worker, store, notifier := newTestAlertChecker([]model.Alert{drift, otherOutcome, malformed})
+	worker.SetOddsSource(&fakeOddsSource{prices: map[string]float64{
+		matchID.String() + ":1x2:home": 2.8,
+		matchID.String() + ":1x2:away": 2.1,
+	}})
+
+	triggers, err := worker.RunOnce(context.Background(), service.EngineRunOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(triggers) != 1 || triggers[0].AlertID != drift.ID || triggers[0].Value != 2.8 {
+		t.Fatalf("Expected only the home odds alert to trigger at 2.8, got %+v", triggers)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0] != drift.ID {
+		t.Errorf("Expected one notification for the home odds alert, got %v", notifier.sent)
+	}
+	if values := store.values[otherOutcome.ID]; len(values) != 1 || values[0] != 2.1 {
+		t.Errorf("Expected the away odds to be saved at 2.1, got %v", values)
+	}
+	if _, ok := store.values[malformed.ID]; ok {
+		t.Error("Expected the malformed identifier not to be evaluated")
+	}
+}
+
+func TestParseOddsIdentifier(t *testing.T) {
+	matchID := uuid.New()
+	tests := []struct {
+		name        string
+		identifier  string
+		wantMarket  string
+		wantOutcome string
+		wantErr     bool
+	}{
+		{name: "valid", identifier: matchID.String() + ":1x2:draw", wantMarket: "1x2", wantOutcome: "draw"},
+		{name: "colon in outcome", identifier: matchID.String() + ":correct_score:2:1", wantMarket: "correct_score", wantOutcome: "2:1"},
+		{name: "match only", identifier: matchID.String(), wantErr: true},
+		{name: "empty outcome", identifier: matchID.String() + ":1x2:", wantErr: true},
+		{name: "invalid match id", identifier: "match-1:1x2:home", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMatch, market, outcome, err := parseOddsIdentifier(tt.identifier)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error for %q", tt.identifier)
+				}
+				return
+			}
+			if err != nil || gotMatch != matchID || market != tt.wantMarket || outcome != tt.wantOutcome {
+				t.Errorf("parseOddsIdentifier(%q) = %v, %q, %q, %v", tt.identifier, gotMatch, market, outcome, err)
+			}
+		})
+	}
+}
+
+func TestAlertChecker_CooldownAfterTrigger(t *testing.T) {
+	alert := model.Alert{ID: uuid.New(), UserID: uuid.New(), Type: model.AlertTypeStockPrice, Symbol: "AAPL", Condition: model.AlertConditionBelow, TargetValue: 150}
+	worker, store, notifier := newTestAlertChecker([]model.Alert{alert})
+
+	for i := 0; i < 3; i++ {
+		if _, err := worker.RunOnce(context.Background(), service.EngineRunOptions{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if len(notifier.sent) != 1 || len(store.triggers[alert.ID]) != 1 {
+		t.Fatalf("Expected one trigger within the cooldown, got %d notifications and %v", len(notifier.sent), store.triggers[alert.ID])
+	}
+
+	expired := time.Now().Add(-AlertCooldown - time.Minute)
+	store.alerts[0].LastTriggered = &expired
+	if _, err := worker.RunOnce(context.Background(), service.EngineRunOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(notifier.sent) != 2 {
+		t.Errorf("Expected the alert to trigger again after the cooldown, got %d notifications", len(notifier.sent))
+	}
+}
+
+func TestAlertChecker_DryRunDoesNotNotify(t *testing.T) {
+	alert := model.Alert{ID: uuid.New(), UserID: uuid.New(), Type: model.AlertTypeStockPrice, Symbol: "AAPL", Condition: model.AlertConditionBelow, TargetValue: 150}
+	worker, store, notifier := newTestAlertChecker([]model.Alert{alert})
+
+	triggers, err := worker.RunOnce(context.Background(), service.EngineRunOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(triggers) != 1 {
+		t.Fatalf("Expected the alert to trigger, got %+v", triggers)
+	}
+	if len(notifier.sent) != 0 || len(store.triggers) != 0 {
+		t.Errorf("Expected a dry run to neither notify nor record, got %v and %v", notifier.sent, store.triggers)
+	}
+}
+
+func TestAlertChecker_RunStopsOnCancel(t *testing.T) {
+	worker, _, _ := newTestAlertChecker(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Run to return after the context was cancelled")
+	}
+}
+
+func TestStartAlertChecker_DoneOnCancel(t *testing.T) {
+	store := &fakeAlertStore{triggers: make(map[uuid.UUID][]float64), values: make(map[uuid.UUID][]float64)}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go StartAlertChecker(ctx, &wg, time.Hour, zerolog.Nop(), store, &fakeNotifier{}, nil, &fakePriceProvider{})
+	cancel()
+
+	if !waitTimeout(&wg, time.Second) {
+		t.Fatal("StartAlertChecker() did not mark the wait group done after the context was cancelled")
+	}
+}
@@ -0,0 +1,198 @@
+// Package workers provides background worker implementations for the Super Dashboard.
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"super-dashboard/backend/internal/model"
+	"super-dashboard/backend/internal/service"
+	"super-dashboard/backend/pkg/jobs"
+)
+
+// BankrollReconciliationWorker recomputes each user's bankroll from their
+// bankroll history and bets and corrects any drift left by crashes or
+// partial writes.
+type BankrollReconciliationWorker struct {
+	interval time.Duration
+	log      zerolog.Logger
+	db       *gorm.DB
+	locker   jobs.Locker
+}
+
+// NewBankrollReconciliationWorker creates a new BankrollReconciliationWorker.
+func NewBankrollReconciliationWorker(interval time.Duration, log zerolog.Logger, db *gorm.DB) *BankrollReconciliationWorker {
+	return &BankrollReconciliationWorker{
+		interval: interval,
+		log:      log.With().Str("worker", "bankroll_reconciliation").Logger(),
+		db:       db,
+	}
+}
+
+// SetLocker makes each nightly run take locker's lock first, so that only
+// one replica reconciles bankrolls per day.
+func (w *BankrollReconciliationWorker) SetLocker(locker jobs.Locker) {
+	w.locker = locker
+}
+
+// StartBankrollReconciliation starts the bankroll reconciliation worker.
+// It runs until the context is cancelled, then marks wg done if wg is not
+// nil. locker may be nil for a single instance.
+func StartBankrollReconciliation(ctx context.Context, wg *sync.WaitGroup, log zerolog.Logger, db *gorm.DB, locker jobs.Locker) {
+	if wg != nil {
+		defer wg.Done()
+	}
+	worker := NewBankrollReconciliationWorker(24*time.Hour, log, db)
+	worker.SetLocker(locker)
+	worker.Run(ctx)
+}
+
+// Run starts the worker loop.
+func (w *BankrollReconciliationWorker) Run(ctx context.Context) {
+	w.log.Info().Dur("interval", w.interval).Msg("Starting bankroll reconciliation worker")
+
+	// Schedule to run at 02:00 daily, before data cleanup and backups
+	w.runAtScheduledTime(ctx)
+}
+
+// runAtScheduledTime runs the worker at a specific time each day.
+func (w *BankrollReconciliationWorker) runAtScheduledTime(ctx context.Context) {
+	for {
+		now := time.Now()
+		next := time.Date(now.Year(), now.Month(), now.Day(), 2, 0, 0, 0, now.Location())
+
+		// If it's past 02:00 today, schedule for tomorrow
+		if now.After(next) {
+			next = next.Add(24 * time.Hour)
+		}
+
+		duration := next.Sub(now)
+		w.log.Info().
+			Time("next_run", next).
+			Dur("wait", duration).
+			Msg("Bankroll reconciliation scheduled")
+
+		select {
+		case <-ctx.Done():
+			w.log.Info().Msg("Bankroll reconciliation worker stopping")
+			return
+		case <-time.After(duration):
+			if takeTick(ctx, w.locker, "bankroll_reconciliation", w.interval, w.log) {
+				w.reconcile(ctx)
+			}
+		}
+	}
+}
+
+// reconcile checks every user's bankroll and corrects the ones that drifted.
+func (w *BankrollReconciliationWorker) reconcile(ctx context.Context) {
+	startTime := time.Now()
+	w.log.Info().Msg("Starting bankroll reconciliation")
+
+	var settings []model.Settings
+	if err := w.db.WithContext(ctx).Find(&settings).Error; err != nil {
+		w.log.Error().Err(err).Msg("Failed to fetch user settings")
+		return
+	}
+
+	adjusted := 0
+	for _, s := range settings {
+		if ctx.Err() != nil {
+			return
+		}
+
+		changed, err := w.reconcileUser(ctx, s)
+		if err != nil {
+			w.log.Error().Err(err).Str("user_id", s.UserID.String()).Msg("Failed to reconcile bankroll")
+			continue
+		}
+		if changed {
+			adjusted++
+		}
+	}
+
+	w.log.Info().
+		Int("users", len(settings)).
+		Int("adjusted", adjusted).
+		Dur("duration", time.Since(startTime)).
+		Msg("Bankroll reconciliation completed")
+}
+
+// reconcileUser recomputes one user's bankroll and, if it drifted, appends a
+// correcting history entry, updates Settings.CurrentBankroll and records an
+// audit log entry in a single transaction.
+func (w *BankrollReconciliationWorker) reconcileUser(ctx context.Context, settings model.Settings) (bool, error) {
+	var history []model.BankrollHistory
+	if err := w.db.WithContext(ctx).
+		Where("user_id = ?", settings.UserID).
+		Order("created_at ASC").
+		Find(&history).Error; err != nil {
+		return false, err
+	}
+
+	var bets []model.Bet
+	if err := w.db.WithContext(ctx).
+		Where("user_id = ?", settings.UserID).
+		Find(&bets).Error; err != nil {
+		return false, err
+	}
+
+	result := service.ReconcileBankroll(settings, history, bets)
+	if !result.NeedsAdjustment() {
+		return false, nil
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"expected":       result.Expected,
+		"ledger":         result.Ledger,
+		"recorded":       result.Recorded,
+		"ledger_drift":   result.LedgerDrift(),
+		"recorded_drift": result.RecordedDrift(),
+	})
+
+	err := w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if math.Abs(result.LedgerDrift()) > service.BankrollDriftTolerance {
+			entry := &model.BankrollHistory{
+				UserID:  settings.UserID,
+				Balance: result.Expected,
+				Change:  result.LedgerDrift(),
+				Reason:  service.BankrollReasonReconciliation,
+			}
+			if err := tx.Create(entry).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&model.Settings{}).
+			Where("user_id = ?", settings.UserID).
+			Update("current_bankroll", result.Expected).Error; err != nil {
+			return err
+		}
+
+		userID := settings.UserID
+		return tx.Create(&model.AuditLog{
+			UserID:  &userID,
+			Action:  model.AuditActionBankrollReconcile,
+			Details: string(details),
+			Success: true,
+		}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	w.log.Warn().
+		Str("user_id", settings.UserID.String()).
+		Float64("expected", result.Expected).
+		Float64("ledger", result.Ledger).
+		Float64("recorded", result.Recorded).
+		Msg("Bankroll drift corrected")
+
+	return true, nil
+}
@@ -0,0 +1,65 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/pkg/websocket"
+)
+
+// EventHub delivers events to WebSocket clients.
+// *websocket.Hub implements it.
+type EventHub interface {
+	BroadcastToChannel(channel string, event websocket.Event) error
+	SendToUser(userID string, event websocket.Event) error
+}
+
+// StockPriceEvent is the payload of a stock:price_update event.
+type StockPriceEvent struct {
+	Symbol string    `json:"symbol"`
+	Price  float64   `json:"price"`
+	Volume int64     `json:"volume"`
+	At     time.Time `json:"at"`
+}
+
+// HubPublisher publishes worker events to WebSocket clients: each synced
+// price to the subscribers of its symbol's channel, and each triggered
+// alert to the connections of the alert's owner.
+type HubPublisher struct {
+	hub EventHub
+	log zerolog.Logger
+}
+
+// NewHubPublisher creates a new HubPublisher.
+func NewHubPublisher(hub EventHub, log zerolog.Logger) *HubPublisher {
+	return &HubPublisher{hub: hub, log: log.With().Str("component", "hub_publisher").Logger()}
+}
+
+// PublishPriceUpdates sends a stock:price_update event per update to
+// websocket.StockChannel of its symbol.
+func (p *HubPublisher) PublishPriceUpdates(updates []PriceUpdate) {
+	for _, update := range updates {
+		event := websocket.Event{
+			Type: websocket.EventStockPriceUpdate,
+			Payload: StockPriceEvent{
+				Symbol: update.Symbol,
+				Price:  update.Price,
+				Volume: update.Volume,
+				At:     update.At,
+			},
+		}
+		if err := p.hub.BroadcastToChannel(websocket.StockChannel(update.Symbol), event); err != nil {
+			p.log.Error().Err(err).Str("symbol", update.Symbol).Msg("Failed to publish price update")
+		}
+	}
+}
+
+// PublishAlertTriggered sends a stock:alert_triggered event to the alert's owner.
+func (p *HubPublisher) PublishAlertTriggered(trigger service.AlertTrigger) {
+	event := websocket.Event{Type: websocket.EventStockAlertTriggered, Payload: trigger}
+	if err := p.hub.SendToUser(trigger.UserID.String(), event); err != nil {
+		p.log.Error().Err(err).Str("alert_id", trigger.AlertID.String()).Msg("Failed to publish alert trigger")
+	}
+}
@@ -0,0 +1,84 @@
+package workers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/pkg/websocket"
+)
+
+// fakeEventHub records the events sent to channels and users.
+type fakeEventHub struct {
+	channels map[string][]websocket.Event
+	users    map[string][]websocket.Event
+}
+
+func newFakeEventHub() *fakeEventHub {
+	return &fakeEventHub{channels: make(map[string][]websocket.Event), users: make(map[string][]websocket.Event)}
+}
+
+func (h *fakeEventHub) BroadcastToChannel(channel string, event websocket.Event) error {
+	h.channels[channel] = append(h.channels[channel], event)
+	return nil
+}
+
+func (h *fakeEventHub) SendToUser(userID string, event websocket.Event) error {
+	h.users[userID] = append(h.users[userID], event)
+	return nil
+}
+
+func TestHubPublisher_PublishPriceUpdates(t *testing.T) {
+	hub := newFakeEventHub()
+	at := time.Date(2025, 3, 3, 21, 0, 0, 0, time.UTC)
+
+	// Fanned out alongside another publisher, as the server wires it
+	other := &fakePublisher{}
+	PriceUpdatePublishers{NewHubPublisher(hub, zerolog.Nop()), other}.PublishPriceUpdates([]PriceUpdate{
+		{Symbol: "AAPL", Price: 150, Volume: 1000, At: at},
+		{Symbol: "MSFT", Price: 410, Volume: 2000, At: at},
+	})
+
+	if other.batchCount() != 1 {
+		t.Errorf("Expected the other publisher to get the batch, got %d batches", other.batchCount())
+	}
+	events := hub.channels[websocket.StockChannel("AAPL")]
+	if len(events) != 1 || len(hub.channels) != 2 {
+		t.Fatalf("Expected one event per symbol channel, got %v", hub.channels)
+	}
+	want := StockPriceEvent{Symbol: "AAPL", Price: 150, Volume: 1000, At: at}
+	if events[0].Type != websocket.EventStockPriceUpdate || events[0].Payload != want {
+		t.Errorf("Expected a price update of %+v, got %+v", want, events[0])
+	}
+}
+
+func TestAlertChecker_PublishesTriggers(t *testing.T) {
+	alert := model.Alert{ID: uuid.New(), UserID: uuid.New(), Type: model.AlertTypeStockPrice, Symbol: "AAPL", Condition: model.AlertConditionBelow, TargetValue: 150}
+	hub := newFakeEventHub()
+	worker, _, _ := newTestAlertChecker([]model.Alert{alert})
+	worker.SetEventPublisher(NewHubPublisher(hub, zerolog.Nop()))
+
+	// A dry run publishes nothing
+	if _, err := worker.RunOnce(context.Background(), service.EngineRunOptions{DryRun: true}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(hub.users) != 0 {
+		t.Fatalf("Expected a dry run not to publish, got %v", hub.users)
+	}
+
+	if _, err := worker.RunOnce(context.Background(), service.EngineRunOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	events := hub.users[alert.UserID.String()]
+	if len(events) != 1 || events[0].Type != websocket.EventStockAlertTriggered {
+		t.Fatalf("Expected one alert event for the owner, got %v", hub.users)
+	}
+	if trigger, ok := events[0].Payload.(service.AlertTrigger); !ok || trigger.AlertID != alert.ID || trigger.Value != 145 {
+		t.Errorf("Expected the trigger of %s at 145, got %+v", alert.ID, events[0].Payload)
+	}
+}
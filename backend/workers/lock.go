@@ -0,0 +1,35 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/awaymess/super-dashboard/backend/pkg/jobs"
+)
+
+// workerLockTimeout bounds taking a worker's tick lock.
+const workerLockTimeout = 5 * time.Second
+
+// takeTick reports whether this replica runs the named worker's tick now,
+// by taking locker's lock for the interval-long window containing it, so
+// that replicas running the same worker share one run per window. A nil
+// locker always grants the tick. The tick is skipped if the lock cannot be
+// checked, as the job scheduler does.
+func takeTick(ctx context.Context, locker jobs.Locker, name string, interval time.Duration, log zerolog.Logger) bool {
+	if locker == nil {
+		return true
+	}
+	lockCtx, cancel := context.WithTimeout(ctx, workerLockTimeout)
+	defer cancel()
+	locked, err := jobs.TryLockInterval(lockCtx, locker, name, interval, time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to take worker lock, skipping tick")
+		return false
+	}
+	if !locked {
+		log.Debug().Msg("Worker tick locked by another instance, skipping")
+	}
+	return locked
+}
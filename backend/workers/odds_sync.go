@@ -5,67 +5,82 @@ package workers
 import (
 	"context"
 	"os"
+	"sync"
 	"time"
 
-	"super-dashboard/backend/pkg/api/odds"
-	"super-dashboard/backend/pkg/cache"
-	"super-dashboard/backend/pkg/websocket"
-
 	"github.com/rs/zerolog"
+
+	"github.com/awaymess/super-dashboard/backend/pkg/api/odds"
+	"github.com/awaymess/super-dashboard/backend/pkg/jobs"
+)
+
+// Provider sport IDs synced by the odds sync: soccer on Pinnacle, football
+// on Betfair.
+const (
+	pinnacleSoccerSportID   = 29
+	betfairFootballEventID  = "1"
+	betfairMaxMarketsPerRun = 50
 )
 
-// OddsSyncWorker synchronizes sports betting odds from external providers.
+// OddsSyncWorker polls sports betting odds from the providers configured
+// in the environment: Pinnacle with PINNACLE_API_KEY, Betfair with
+// BETFAIR_APP_KEY and BETFAIR_SESSION_TOKEN. Provider matches are not yet
+// mapped to match rows, so fetched odds are logged rather than stored.
 type OddsSyncWorker struct {
-	interval     time.Duration
-	log          zerolog.Logger
-	pinnacle     *odds.PinnacleClient
-	betfair      *odds.BetfairClient
-	cacheService *cache.CacheService
+	interval time.Duration
+	log      zerolog.Logger
+	locker   jobs.Locker
+	pinnacle *odds.PinnacleClient
+	betfair  *odds.BetfairClient
+}
+
 // NewOddsSyncWorker creates a new OddsSyncWorker with the specified interval.
-func NewOddsSyncWorker(interval time.Duration, log zerolog.Logger, cacheService *cache.CacheService, broadcaster *websocket.Broadcaster) *OddsSyncWorker {
-	// Initialize API clients
-	pinnacleKey := os.Getenv("PINNACLE_API_KEY")
-	var pinnacleClient *odds.PinnacleClient
-	if pinnacleKey != "" {
-		pinnacleClient = odds.NewPinnacleClient(pinnacleKey)
+func NewOddsSyncWorker(interval time.Duration, log zerolog.Logger) *OddsSyncWorker {
+	w := &OddsSyncWorker{
+		interval: interval,
+		log:      log.With().Str("worker", "odds_sync").Logger(),
 	}
-
-	betfairAppKey := os.Getenv("BETFAIR_APP_KEY")
-	betfairToken := os.Getenv("BETFAIR_SESSION_TOKEN")
-// StartOddsSync starts the odds synchronization worker.
-// It runs until the context is cancelled.
-func StartOddsSync(ctx context.Context, log zerolog.Logger, cacheService *cache.CacheService, broadcaster *websocket.Broadcaster) {
-	worker := NewOddsSyncWorker(5*time.Minute, log, cacheService, broadcaster)
-	worker.Run(ctx)
-}return &OddsSyncWorker{
-		interval:     interval,
-		log:          log.With().Str("worker", "odds_sync").Logger(),
-		pinnacle:     pinnacleClient,
-		betfair:      betfairClient,
-		cacheService: cacheService,
-		broadcaster:  broadcaster,
+	if key := os.Getenv("PINNACLE_API_KEY"); key != "" {
+		w.pinnacle = odds.NewPinnacleClient(key)
 	}
-}	interval: interval,
-		log:      log.With().Str("worker", "odds_sync").Logger(),
+	appKey, sessionToken := os.Getenv("BETFAIR_APP_KEY"), os.Getenv("BETFAIR_SESSION_TOKEN")
+	if appKey != "" && sessionToken != "" {
+		w.betfair = odds.NewBetfairClient(appKey, sessionToken)
 	}
+	return w
+}
+
+// SetLocker makes each tick take locker's lock first, so that only one
+// replica syncs odds per interval.
+func (w *OddsSyncWorker) SetLocker(locker jobs.Locker) {
+	w.locker = locker
 }
 
 // StartOddsSync starts the odds synchronization worker.
-// It runs until the context is cancelled.
-func StartOddsSync(ctx context.Context, log zerolog.Logger) {
-	worker := NewOddsSyncWorker(5*time.Minute, log)
+// It ticks at the given interval and runs until the context is cancelled,
+// then marks wg done if wg is not nil. locker may be nil for a single
+// instance.
+func StartOddsSync(ctx context.Context, wg *sync.WaitGroup, interval time.Duration, log zerolog.Logger, locker jobs.Locker) {
+	if wg != nil {
+		defer wg.Done()
+	}
+	worker := NewOddsSyncWorker(interval, log)
+	worker.SetLocker(locker)
 	worker.Run(ctx)
 }
 
 // Run starts the worker loop, ticking at the configured interval.
 func (w *OddsSyncWorker) Run(ctx context.Context) {
+	if w.pinnacle == nil && w.betfair == nil {
+		w.log.Warn().Msg("No odds provider configured, odds sync worker idle")
+	}
 	w.log.Info().Dur("interval", w.interval).Msg("Starting odds sync worker")
 
 	ticker := time.NewTicker(w.interval)
 	defer ticker.Stop()
 
 	// Run immediately on startup
-	w.sync(ctx)
+	w.tick(ctx)
 
 	for {
 		select {
@@ -73,144 +88,65 @@ func (w *OddsSyncWorker) Run(ctx context.Context) {
 			w.log.Info().Msg("Odds sync worker stopping")
 			return
 		case <-ticker.C:
-			w.sync(ctx)
+			w.tick(ctx)
 		}
 	}
 }
 
-// sync performs the actual odds synchronization.
-func (w *OddsSyncWorker) sync(ctx context.Context) {
-	w.log.Debug().Msg("Syncing odds from external providers")
+// tick syncs odds if this replica holds the tick's lock.
+func (w *OddsSyncWorker) tick(ctx context.Context) {
+	if takeTick(ctx, w.locker, "odds_sync", w.interval, w.log) {
+		w.sync(ctx)
+	}
+}
 
-	// Sync from Pinnacle (soccer - sportID 29)
+// sync fetches odds from each configured provider. A failing provider does
+// not stop the others.
+func (w *OddsSyncWorker) sync(ctx context.Context) {
 	if w.pinnacle != nil {
-		if err := w.syncPinnacle(ctx, 29); err != nil {
+		if err := w.syncPinnacle(ctx, pinnacleSoccerSportID); err != nil {
 			w.log.Error().Err(err).Msg("Failed to sync Pinnacle odds")
 		}
 	}
-
-	// Sync from Betfair (football - eventTypeID 1)
 	if w.betfair != nil {
-		if err := w.syncBetfair(ctx, "1"); err != nil {
+		if err := w.syncBetfair(ctx, betfairFootballEventID); err != nil {
 			w.log.Error().Err(err).Msg("Failed to sync Betfair odds")
 		}
 	}
-
-	w.log.Debug().Msg("Odds sync completed")
 }
 
-// syncPinnacle syncs odds from Pinnacle API.
+// syncPinnacle fetches the sport's odds from Pinnacle.
 func (w *OddsSyncWorker) syncPinnacle(ctx context.Context, sportID int) error {
-	// Get matches
-	matches, err := w.pinnacle.GetMatches(ctx, sportID, 0)
-	if err != nil {
-		return err
-	}
-
-	w.log.Info().Int("count", len(matches)).Msg("Fetched Pinnacle matches")
-
-	// Get odds
-	oddsData, err := w.pinnacle.GetOdds(ctx, sportID, 0, "DECIMAL")
+	prices, err := w.pinnacle.GetOdds(ctx, sportID, 0, "DECIMAL")
 	if err != nil {
 		return err
 	}
-
-	w.log.Info().Int("count", len(oddsData)).Msg("Fetched Pinnacle odds")
-
-	// Process each odds update
-	for _, odd := range oddsData {
-		// Cache odds
-		if err := w.cacheService.SetOdds(ctx, odd.MatchID, odd); err != nil {
-			w.log.Error().Err(err).Int64("matchId", odd.MatchID).Msg("Failed to cache odds")
-			continue
-		}
-
-		// Find match details
-		var homeTeam, awayTeam string
-		for _, match := range matches {
-			if match.ID == odd.MatchID {
-				homeTeam = match.HomeTeam
-				awayTeam = match.AwayTeam
-				break
-			}
-		}
-
-		// Broadcast update via WebSocket
-		update := websocket.OddsUpdate{
-			MatchID:   odd.MatchID,
-			SportID:   sportID,
-			HomeTeam:  homeTeam,
-			AwayTeam:  awayTeam,
-			UpdatedAt: time.Now().Unix(),
-		}
-
-		if odd.Moneyline != nil {
-			update.Moneyline = map[string]float64{
-				"home": odd.Moneyline.Home,
-				"draw": odd.Moneyline.Draw,
-				"away": odd.Moneyline.Away,
-			}
-		}
-
-		if err := w.broadcaster.BroadcastOddsUpdate(update); err != nil {
-			w.log.Error().Err(err).Msg("Failed to broadcast odds update")
-		}
-	}
-
+	w.log.Info().Int("sport_id", sportID).Int("count", len(prices)).Msg("Fetched Pinnacle odds")
 	return nil
 }
 
-// syncBetfair syncs odds from Betfair Exchange API.
+// syncBetfair fetches the event type's market odds from Betfair, for at
+// most betfairMaxMarketsPerRun markets to stay within the rate limit.
 func (w *OddsSyncWorker) syncBetfair(ctx context.Context, eventTypeID string) error {
-	// Get markets
 	markets, err := w.betfair.GetMarkets(ctx, eventTypeID, "")
 	if err != nil {
 		return err
 	}
-
-	w.log.Info().Int("count", len(markets)).Msg("Fetched Betfair markets")
-
 	if len(markets) == 0 {
 		return nil
 	}
+	if len(markets) > betfairMaxMarketsPerRun {
+		markets = markets[:betfairMaxMarketsPerRun]
+	}
 
-	// Get odds for first 50 markets (rate limit protection)
-	marketIDs := make([]string, 0, 50)
+	marketIDs := make([]string, len(markets))
 	for i, market := range markets {
-		if i >= 50 {
-			break
-		}
-		marketIDs = append(marketIDs, market.MarketID)
+		marketIDs[i] = market.MarketID
 	}
-
-	marketBooks, err := w.betfair.GetMarketOdds(ctx, marketIDs)
+	books, err := w.betfair.GetMarketOdds(ctx, marketIDs)
 	if err != nil {
 		return err
 	}
-
-	w.log.Info().Int("count", len(marketBooks)).Msg("Fetched Betfair odds")
-
-	// Process odds updates
-	for _, book := range marketBooks {
-		// Find market details
-		var marketName string
-		for _, market := range markets {
-			if market.MarketID == book.MarketID {
-				marketName = market.Event.Name
-				break
-			}
-		}
-
-		// Cache and broadcast
-		if w.cacheService != nil {
-			// Cache market odds
-			if err := w.cacheService.SetOdds(ctx, 0, book); err != nil {
-				w.log.Error().Err(err).Str("marketId", book.MarketID).Msg("Failed to cache Betfair odds")
-			}
-		}
-
-		w.log.Debug().Str("market", marketName).Str("marketId", book.MarketID).Msg("Synced Betfair market")
-	}
-
+	w.log.Info().Str("event_type_id", eventTypeID).Int("count", len(books)).Msg("Fetched Betfair odds")
 	return nil
 }
@@ -3,68 +3,150 @@ package workers
 
 import (
 	"context"
-	"os"
+	"sync"
 	"time"
 
-	"super-dashboard/backend/pkg/api/stocks"
-	"super-dashboard/backend/pkg/cache"
-	"super-dashboard/backend/pkg/websocket"
-
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+	"github.com/awaymess/super-dashboard/backend/pkg/jobs"
 )
 
-// StockSyncWorker synchronizes stock prices from external providers.
-type StockSyncWorker struct {
-	interval       time.Duration
-	log            zerolog.Logger
-	yahoo          *stocks.YahooFinanceClient
-	alphaVantage   *stocks.AlphaVantageClient
-	cacheService   *cache.CacheService
-	broadcaster    *websocket.Broadcaster
-	watchedSymbols []string
+// DefaultStockSyncConcurrency is how many quotes the stock sync fetches at
+// once. Providers rate limit per client, so the pool is kept small.
+const DefaultStockSyncConcurrency = 4
+
+// TrackedSymbolSource lists the symbols to sync.
+// repository.TrackedSymbolRepository implements it.
+type TrackedSymbolSource interface {
+	TrackedSymbols(ctx context.Context) ([]string, error)
 }
 
-// NewStockSyncWorker creates a new StockSyncWorker with the specified interval.
-func NewStockSyncWorker(interval time.Duration, log zerolog.Logger, cacheService *cache.CacheService, broadcaster *websocket.Broadcaster) *StockSyncWorker {
-	// Initialize API clients
-	yahooClient := stocks.NewYahooFinanceClient()
+// SymbolQuoteSource fetches a symbol's live quote with the day's range and volume.
+type SymbolQuoteSource interface {
+	GetSymbolQuote(ctx context.Context, symbol string) (*service.SymbolQuote, error)
+}
+
+// StockPriceStore stores the latest price bars.
+// repository.StockPriceRepository implements it.
+type StockPriceStore interface {
+	EnsureStock(symbol string) (*model.Stock, error)
+	Upsert(price *model.StockPrice) error
+}
+
+// PriceUpdate is a symbol's price as stored by a stock sync.
+type PriceUpdate struct {
+	Symbol string
+	Price  float64
+	Volume int64
+	At     time.Time
+}
 
-	alphaVantageKey := os.Getenv("ALPHAVANTAGE_API_KEY")
-	var alphaVantageClient *stocks.AlphaVantageClient
-	if alphaVantageKey != "" {
-		alphaVantageClient = stocks.NewAlphaVantageClient(alphaVantageKey)
+// PriceUpdatePublisher is told about the prices each stock sync stored.
+// *AlertCheckerWorker and *HubPublisher implement it.
+type PriceUpdatePublisher interface {
+	PublishPriceUpdates(updates []PriceUpdate)
+}
+
+// PriceUpdatePublishers passes each sync's prices to every publisher in turn.
+type PriceUpdatePublishers []PriceUpdatePublisher
+
+// PublishPriceUpdates implements PriceUpdatePublisher.
+func (p PriceUpdatePublishers) PublishPriceUpdates(updates []PriceUpdate) {
+	for _, publisher := range p {
+		publisher.PublishPriceUpdates(updates)
 	}
+}
 
-	// Default watched symbols (could be loaded from database)
-	watchedSymbols := []string{"AAPL", "GOOGL", "MSFT", "AMZN", "TSLA", "META", "NVDA", "AMD"}
+// StockSyncWorker synchronizes the prices of tracked stocks from external providers.
+type StockSyncWorker struct {
+	interval     time.Duration
+	log          zerolog.Logger
+	symbols      TrackedSymbolSource
+	quotes       SymbolQuoteSource
+	prices       StockPriceStore
+	concurrency  int
+	markToMarket service.MarkToMarketService
+	publisher    PriceUpdatePublisher
+	locker       jobs.Locker
 
+	// stockIDs caches the stock row of each synced symbol.
+	mu       sync.Mutex
+	stockIDs map[string]uuid.UUID
+}
+
+// NewStockSyncWorker creates a new StockSyncWorker with the specified interval.
+func NewStockSyncWorker(interval time.Duration, log zerolog.Logger, symbols TrackedSymbolSource, quotes SymbolQuoteSource, prices StockPriceStore) *StockSyncWorker {
 	return &StockSyncWorker{
-		interval:       interval,
-		log:            log.With().Str("worker", "stock_sync").Logger(),
-		yahoo:          yahooClient,
-		alphaVantage:   alphaVantageClient,
-		cacheService:   cacheService,
-		broadcaster:    broadcaster,
-		watchedSymbols: watchedSymbols,
+		interval:    interval,
+		log:         log.With().Str("worker", "stock_sync").Logger(),
+		symbols:     symbols,
+		quotes:      quotes,
+		prices:      prices,
+		concurrency: DefaultStockSyncConcurrency,
+		stockIDs:    make(map[string]uuid.UUID),
 	}
 }
 
+// SetConcurrency sets how many quotes are fetched at once. Values below 1
+// are treated as 1.
+func (w *StockSyncWorker) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	w.concurrency = n
+}
+
+// SetMarkToMarket enables revaluing paper portfolios after each sync.
+func (w *StockSyncWorker) SetMarkToMarket(markToMarket service.MarkToMarketService) {
+	w.markToMarket = markToMarket
+}
+
+// SetPublisher sends the prices stored by each sync to publisher.
+func (w *StockSyncWorker) SetPublisher(publisher PriceUpdatePublisher) {
+	w.publisher = publisher
+}
+
+// SetLocker makes each tick take locker's lock first, so that only one
+// replica syncs prices per interval.
+func (w *StockSyncWorker) SetLocker(locker jobs.Locker) {
+	w.locker = locker
+}
+
 // StartStockSync starts the stock price synchronization worker.
-// It runs until the context is cancelled.
-func StartStockSync(ctx context.Context, log zerolog.Logger, cacheService *cache.CacheService, broadcaster *websocket.Broadcaster) {
-	worker := NewStockSyncWorker(1*time.Minute, log, cacheService, broadcaster)
+// It ticks at the given interval and runs until the context is cancelled,
+// then marks wg done if wg is not nil. markToMarket and publisher may be nil.
+func StartStockSync(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	interval time.Duration,
+	log zerolog.Logger,
+	symbols TrackedSymbolSource,
+	quotes SymbolQuoteSource,
+	prices StockPriceStore,
+	markToMarket service.MarkToMarketService,
+	publisher PriceUpdatePublisher,
+) {
+	if wg != nil {
+		defer wg.Done()
+	}
+	worker := NewStockSyncWorker(interval, log, symbols, quotes, prices)
+	worker.SetMarkToMarket(markToMarket)
+	worker.SetPublisher(publisher)
 	worker.Run(ctx)
 }
 
 // Run starts the worker loop, ticking at the configured interval.
 func (w *StockSyncWorker) Run(ctx context.Context) {
-	w.log.Info().Dur("interval", w.interval).Msg("Starting stock sync worker")
+	w.log.Info().Dur("interval", w.interval).Int("concurrency", w.concurrency).Msg("Starting stock sync worker")
 
 	ticker := time.NewTicker(w.interval)
 	defer ticker.Stop()
 
 	// Run immediately on startup
-	w.sync(ctx)
+	w.tick(ctx)
 
 	for {
 		select {
@@ -72,56 +154,153 @@ func (w *StockSyncWorker) Run(ctx context.Context) {
 			w.log.Info().Msg("Stock sync worker stopping")
 			return
 		case <-ticker.C:
-			w.sync(ctx)
+			w.tick(ctx)
 		}
 	}
 }
 
-// sync performs the actual stock price synchronization.
-func (w *StockSyncWorker) sync(ctx context.Context) {
-	w.log.Debug().Int("symbols", len(w.watchedSymbols)).Msg("Syncing stock prices from external providers")
+// tick syncs prices if this replica holds the tick's lock.
+func (w *StockSyncWorker) tick(ctx context.Context) {
+	if takeTick(ctx, w.locker, "stock_sync", w.interval, w.log) {
+		w.sync(ctx)
+	}
+}
 
-	// Fetch quotes for all watched symbols using Yahoo Finance (free, no rate limit)
-	quotes, err := w.yahoo.GetMultipleQuotes(ctx, w.watchedSymbols)
+// sync stores the latest price of every tracked symbol, then revalues paper
+// portfolios and publishes the updates.
+func (w *StockSyncWorker) sync(ctx context.Context) {
+	symbols, err := w.symbols.TrackedSymbols(ctx)
 	if err != nil {
-		w.log.Error().Err(err).Msg("Failed to fetch stock quotes")
+		w.log.Error().Err(err).Msg("Failed to load tracked symbols")
+		return
+	}
+	if len(symbols) == 0 {
+		w.log.Debug().Msg("No tracked symbols to sync")
 		return
 	}
 
-	w.log.Info().Int("count", len(quotes)).Msg("Fetched stock quotes")
+	updates := w.SyncOnce(ctx, symbols)
+	if ctx.Err() != nil {
+		return
+	}
+	w.log.Info().Int("symbols", len(symbols)).Int("updated", len(updates)).Msg("Stock sync completed")
 
-	// Process each quote
-	for _, quote := range quotes {
-		// Cache quote
-		if w.cacheService != nil {
-			if err := w.cacheService.SetStockQuote(ctx, quote.Symbol, quote); err != nil {
-				w.log.Error().Err(err).Str("symbol", quote.Symbol).Msg("Failed to cache stock quote")
-			}
+	w.refreshPortfolios(ctx)
+	if w.publisher != nil && len(updates) > 0 {
+		w.publisher.PublishPriceUpdates(updates)
+	}
+}
+
+// SyncOnce fetches and stores the symbols' quotes with at most the
+// configured number of requests in flight, and returns the prices stored in
+// symbol order. It stops handing out symbols once ctx is done.
+func (w *StockSyncWorker) SyncOnce(ctx context.Context, symbols []string) []PriceUpdate {
+	results := make([]*PriceUpdate, len(symbols))
+	jobs := make(chan int)
 
-			// Publish to Redis pub/sub
-			if err := w.cacheService.PublishStockUpdate(ctx, quote.Symbol, quote); err != nil {
-				w.log.Error().Err(err).Str("symbol", quote.Symbol).Msg("Failed to publish stock update")
+	var wg sync.WaitGroup
+	for i := 0; i < w.concurrency && i < len(symbols); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results[j] = w.syncSymbol(ctx, symbols[j])
 			}
+		}()
+	}
+
+dispatch:
+	for i := range symbols {
+		if ctx.Err() != nil {
+			break
 		}
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
 
-		// Broadcast via WebSocket
-		if w.broadcaster != nil {
-			update := websocket.StockPriceUpdate{
-				Symbol:        quote.Symbol,
-				Price:         quote.RegularMarketPrice,
-				Change:        quote.RegularMarketChange,
-				ChangePercent: quote.RegularMarketChangePercent,
-				Volume:        quote.RegularMarketVolume,
-				UpdatedAt:     time.Now().Unix(),
-			}
+	updates := make([]PriceUpdate, 0, len(symbols))
+	for _, update := range results {
+		if update != nil {
+			updates = append(updates, *update)
+		}
+	}
+	return updates
+}
 
-			if err := w.broadcaster.BroadcastStockPrice(update); err != nil {
-				w.log.Error().Err(err).Str("symbol", quote.Symbol).Msg("Failed to broadcast stock update")
-			}
+// syncSymbol stores the symbol's quote as its bar for the quote's day,
+// returning nil if it could not.
+func (w *StockSyncWorker) syncSymbol(ctx context.Context, symbol string) *PriceUpdate {
+	quote, err := w.quotes.GetSymbolQuote(ctx, symbol)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.log.Warn().Err(err).Str("symbol", symbol).Msg("Failed to fetch stock quote")
 		}
+		return nil
+	}
+
+	stockID, err := w.stockID(symbol)
+	if err != nil {
+		w.log.Error().Err(err).Str("symbol", symbol).Msg("Failed to load stock")
+		return nil
+	}
+
+	at := quote.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	price := &model.StockPrice{
+		StockID:   stockID,
+		Timestamp: at.UTC().Truncate(24 * time.Hour),
+		Open:      quote.Open,
+		High:      quote.High,
+		Low:       quote.Low,
+		Close:     quote.Price,
+		Volume:    quote.Volume,
+	}
+	if err := w.prices.Upsert(price); err != nil {
+		w.log.Error().Err(err).Str("symbol", symbol).Msg("Failed to store stock price")
+		return nil
+	}
+
+	w.log.Debug().Str("symbol", symbol).Float64("price", quote.Price).Msg("Synced stock price")
+	return &PriceUpdate{Symbol: symbol, Price: quote.Price, Volume: quote.Volume, At: at}
+}
+
+// stockID returns the ID of the symbol's stock row, creating the row on first use.
+func (w *StockSyncWorker) stockID(symbol string) (uuid.UUID, error) {
+	w.mu.Lock()
+	id, ok := w.stockIDs[symbol]
+	w.mu.Unlock()
+	if ok {
+		return id, nil
+	}
 
-		w.log.Debug().Str("symbol", quote.Symbol).Float64("price", quote.RegularMarketPrice).Msg("Synced stock price")
+	stock, err := w.prices.EnsureStock(symbol)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
-	w.log.Debug().Msg("Stock sync completed")
+	w.mu.Lock()
+	w.stockIDs[symbol] = stock.ID
+	w.mu.Unlock()
+	return stock.ID, nil
+}
+
+// refreshPortfolios marks every paper portfolio to market.
+func (w *StockSyncWorker) refreshPortfolios(ctx context.Context) {
+	if w.markToMarket == nil {
+		return
+	}
+
+	updated, err := w.markToMarket.RefreshAll(ctx)
+	if err != nil {
+		w.log.Error().Err(err).Msg("Failed to mark paper portfolios to market")
+		return
+	}
+	w.log.Info().Int("positions", updated).Msg("Marked paper portfolios to market")
 }
@@ -0,0 +1,280 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/awaymess/super-dashboard/backend/internal/model"
+	"github.com/awaymess/super-dashboard/backend/internal/service"
+)
+
+// fakeTrackedSymbols serves a fixed symbol list.
+type fakeTrackedSymbols []string
+
+func (s fakeTrackedSymbols) TrackedSymbols(ctx context.Context) ([]string, error) {
+	return s, nil
+}
+
+// fakeSymbolQuotes quotes each symbol at a fixed price and records the most
+// requests it saw in flight at once.
+type fakeSymbolQuotes struct {
+	prices map[string]float64
+	delay  time.Duration
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (q *fakeSymbolQuotes) GetSymbolQuote(ctx context.Context, symbol string) (*service.SymbolQuote, error) {
+	q.mu.Lock()
+	q.inFlight++
+	if q.inFlight > q.maxInFlight {
+		q.maxInFlight = q.inFlight
+	}
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		q.inFlight--
+		q.mu.Unlock()
+	}()
+
+	time.Sleep(q.delay)
+	price, ok := q.prices[symbol]
+	if !ok {
+		return nil, errors.New("no quote")
+	}
+	return &service.SymbolQuote{Symbol: symbol, Price: price, Volume: 1000, Timestamp: time.Date(2024, 3, 4, 15, 30, 0, 0, time.UTC)}, nil
+}
+
+// fakePriceStore keeps one bar per stock and day.
+type fakePriceStore struct {
+	mu      sync.Mutex
+	stocks  map[string]uuid.UUID
+	prices  map[uuid.UUID]map[time.Time]model.StockPrice
+	upserts int
+}
+
+func newFakePriceStore() *fakePriceStore {
+	return &fakePriceStore{stocks: make(map[string]uuid.UUID), prices: make(map[uuid.UUID]map[time.Time]model.StockPrice)}
+}
+
+func (s *fakePriceStore) EnsureStock(symbol string) (*model.Stock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.stocks[symbol]
+	if !ok {
+		id = uuid.New()
+		s.stocks[symbol] = id
+	}
+	return &model.Stock{ID: id, Symbol: symbol}, nil
+}
+
+func (s *fakePriceStore) Upsert(price *model.StockPrice) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.prices[price.StockID] == nil {
+		s.prices[price.StockID] = make(map[time.Time]model.StockPrice)
+	}
+	s.prices[price.StockID][price.Timestamp] = *price
+	s.upserts++
+	return nil
+}
+
+func (s *fakePriceStore) close(symbol string) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, price := range s.prices[s.stocks[symbol]] {
+		return price.Close, true
+	}
+	return 0, false
+}
+
+func (s *fakePriceStore) upsertCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.upserts
+}
+
+// fakePublisher records each published batch.
+type fakePublisher struct {
+	mu      sync.Mutex
+	batches [][]PriceUpdate
+}
+
+func (p *fakePublisher) PublishPriceUpdates(updates []PriceUpdate) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.batches = append(p.batches, updates)
+}
+
+func (p *fakePublisher) batchCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.batches)
+}
+
+func TestStockSyncWorker_RunStoresPricesAndStops(t *testing.T) {
+	symbols := fakeTrackedSymbols{"AAPL", "MSFT", "TSLA"}
+	quotes := &fakeSymbolQuotes{prices: map[string]float64{"AAPL": 190.5, "MSFT": 410.25, "TSLA": 175}}
+	store := newFakePriceStore()
+	publisher := &fakePublisher{}
+
+	worker := NewStockSyncWorker(10*time.Millisecond, zerolog.Nop(), symbols, quotes, store)
+	worker.SetPublisher(publisher)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for store.upsertCount() < 2*len(symbols) {
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d prices stored before the deadline", store.upsertCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run() did not return after the context was cancelled")
+	}
+
+	for symbol, want := range quotes.prices {
+		got, ok := store.close(symbol)
+		if !ok {
+			t.Errorf("no price stored for %s", symbol)
+		} else if got != want {
+			t.Errorf("stored close for %s = %v, want %v", symbol, got, want)
+		}
+	}
+	if publisher.batchCount() == 0 {
+		t.Error("no price updates published")
+	}
+}
+
+// waitTimeout reports whether wg finished within d.
+func waitTimeout(wg *sync.WaitGroup, d time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func TestStartStockSync_DoneOnCancel(t *testing.T) {
+	quotes := &fakeSymbolQuotes{prices: map[string]float64{"AAPL": 190.5}, delay: 20 * time.Millisecond}
+	store := newFakePriceStore()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go StartStockSync(ctx, &wg, time.Hour, zerolog.Nop(), fakeTrackedSymbols{"AAPL"}, quotes, store, nil, nil)
+
+	// Cancel while the first sync may still be fetching
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+
+	if !waitTimeout(&wg, time.Second) {
+		t.Fatal("StartStockSync() did not mark the wait group done after the context was cancelled")
+	}
+}
+
+func TestStockSyncWorker_SyncOnce(t *testing.T) {
+	symbols := []string{"A", "B", "C", "D", "E", "F", "MISSING"}
+	quotes := &fakeSymbolQuotes{prices: map[string]float64{"A": 1, "B": 2, "C": 3, "D": 4, "E": 5, "F": 6}, delay: 10 * time.Millisecond}
+	store := newFakePriceStore()
+
+	worker := NewStockSyncWorker(time.Minute, zerolog.Nop(), fakeTrackedSymbols(symbols), quotes, store)
+	worker.SetConcurrency(2)
+
+	updates := worker.SyncOnce(context.Background(), symbols)
+	if len(updates) != 6 {
+		t.Fatalf("SyncOnce() returned %d updates, want 6", len(updates))
+	}
+	for i, update := range updates {
+		if update.Symbol != symbols[i] || update.Price != float64(i+1) {
+			t.Errorf("update %d = %+v, want %s at %d", i, update, symbols[i], i+1)
+		}
+	}
+	if quotes.maxInFlight > 2 {
+		t.Errorf("%d quotes fetched at once, want at most 2", quotes.maxInFlight)
+	}
+
+	// Repeated syncs on the same day replace the day's bar
+	worker.SyncOnce(context.Background(), symbols)
+	for _, bars := range store.prices {
+		if len(bars) != 1 {
+			t.Errorf("stock has %d bars after two syncs on one day, want 1", len(bars))
+		}
+		for at := range bars {
+			if !at.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("bar stored at %v, want the start of the quote's day", at)
+			}
+		}
+	}
+}
+
+func TestStockSyncWorker_SyncOnceCancelled(t *testing.T) {
+	quotes := &fakeSymbolQuotes{prices: map[string]float64{"A": 1}}
+	store := newFakePriceStore()
+	worker := NewStockSyncWorker(time.Minute, zerolog.Nop(), nil, quotes, store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	worker.SyncOnce(ctx, []string{"A", "A", "A", "A", "A", "A", "A", "A"})
+	if n := store.upsertCount(); n != 0 {
+		t.Errorf("%d prices stored with a cancelled context, want 0", n)
+	}
+}
+
+// fakeLocker is an in-memory jobs.Locker shared by several workers.
+type fakeLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[key] {
+		return false, nil
+	}
+	l.held[key] = true
+	return true, nil
+}
+
+func TestStockSyncWorker_LockerSyncsOncePerInterval(t *testing.T) {
+	symbols := fakeTrackedSymbols{"AAPL"}
+	quotes := &fakeSymbolQuotes{prices: map[string]float64{"AAPL": 190.5}}
+	locker := &fakeLocker{held: make(map[string]bool)}
+
+	publisher := &fakePublisher{}
+	for i := 0; i < 2; i++ {
+		worker := NewStockSyncWorker(time.Hour, zerolog.Nop(), symbols, quotes, newFakePriceStore())
+		worker.SetPublisher(publisher)
+		worker.SetLocker(locker)
+		worker.tick(context.Background())
+	}
+
+	if got := publisher.batchCount(); got != 1 {
+		t.Errorf("two replicas published %d batches in one interval, want 1", got)
+	}
+}
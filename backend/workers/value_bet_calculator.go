@@ -3,6 +3,7 @@ package workers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -68,26 +69,43 @@ func (w *ValueBetCalculatorWorker) Run(ctx context.Context) {
 
 // calculate finds value betting opportunities.
 func (w *ValueBetCalculatorWorker) calculate(ctx context.Context) {
+	if _, err := w.RunOnce(ctx, service.EngineRunOptions{}); err != nil {
+		w.log.Error().Err(err).Msg("Value bet calculation failed")
+	}
+}
+
+// RunOnce finds value bets among matches starting in the next seven days,
+// saving each one and notifying users. With opts.DryRun the value bets are
+// only returned; with a date range, matches starting in that range are
+// replayed whatever their current status.
+func (w *ValueBetCalculatorWorker) RunOnce(ctx context.Context, opts service.EngineRunOptions) ([]model.ValueBet, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	startTime := time.Now()
-	w.log.Debug().Msg("Calculating value bets")
+	w.log.Debug().Bool("dry_run", opts.DryRun).Msg("Calculating value bets")
 
-	// Get upcoming matches
-	var matches []model.Match
-	err := w.db.WithContext(ctx).
+	// Get upcoming matches, or every match in the replayed range
+	query := w.db.WithContext(ctx).
 		Preload("HomeTeam").
-		Preload("AwayTeam").
-		Where("status = ?", "scheduled").
-		Where("start_time BETWEEN ? AND ?", time.Now(), time.Now().Add(7*24*time.Hour)).
-		Find(&matches).Error
+		Preload("AwayTeam")
+	if opts.HasRange() {
+		query = query.Where("start_time BETWEEN ? AND ?", opts.From, opts.To)
+	} else {
+		query = query.
+			Where("status = ?", "scheduled").
+			Where("start_time BETWEEN ? AND ?", time.Now(), time.Now().Add(7*24*time.Hour))
+	}
 
-	if err != nil {
-		w.log.Error().Err(err).Msg("Failed to fetch upcoming matches")
-		return
+	var matches []model.Match
+	if err := query.Find(&matches).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch upcoming matches: %w", err)
 	}
 
 	w.log.Debug().Int("count", len(matches)).Msg("Found upcoming matches")
 
-	valueBetsFound := 0
+	var valueBets []model.ValueBet
 	for _, match := range matches {
 		// Get odds for this match
 		var odds []model.Odds
@@ -145,12 +163,14 @@ func (w *ValueBetCalculatorWorker) calculate(ctx context.Context) {
 				}
 
 				// Save value bet
-				if err := w.db.WithContext(ctx).Create(valueBet).Error; err != nil {
-					w.log.Error().Err(err).Msg("Failed to save value bet")
-					continue
+				if !opts.DryRun {
+					if err := w.db.WithContext(ctx).Create(valueBet).Error; err != nil {
+						w.log.Error().Err(err).Msg("Failed to save value bet")
+						continue
+					}
 				}
 
-				valueBetsFound++
+				valueBets = append(valueBets, *valueBet)
 				w.log.Info().
 					Str("match_id", match.ID.String()).
 					Str("bookmaker", odd.Bookmaker).
@@ -158,10 +178,13 @@ func (w *ValueBetCalculatorWorker) calculate(ctx context.Context) {
 					Str("selection", odd.Outcome).
 					Float64("odds", odd.Price).
 					Float64("value_percent", valuePercent).
+					Bool("dry_run", opts.DryRun).
 					Msg("Value bet found")
 
 				// Notify users who have enabled value bet alerts
-				go w.notifyUsers(ctx, valueBet)
+				if !opts.DryRun {
+					go w.notifyUsers(ctx, valueBet)
+				}
 			}
 		}
 	}
@@ -169,9 +192,12 @@ func (w *ValueBetCalculatorWorker) calculate(ctx context.Context) {
 	duration := time.Since(startTime)
 	w.log.Info().
 		Int("matches_analyzed", len(matches)).
-		Int("value_bets_found", valueBetsFound).
+		Int("value_bets_found", len(valueBets)).
+		Bool("dry_run", opts.DryRun).
 		Dur("duration", duration).
 		Msg("Value bet calculation completed")
+
+	return valueBets, nil
 }
 
 // calculateTrueProbabilities calculates true win probabilities using multiple models.